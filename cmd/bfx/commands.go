@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/rest"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/websocket"
+)
+
+// errAborted is returned if a confirmation was declined
+var errAborted = errors.New("aborted")
+
+func (c *cli) wallets(args []string) error {
+	fs := c.flags("wallets")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	wallets, err := c.rest.Wallet.Wallet()
+	if err != nil {
+		return err
+	}
+	return c.print(wallets.Snapshot)
+}
+
+func (c *cli) movements(args []string) error {
+	fs := c.flags("movements")
+	currency := fs.String("currency", "", "currency, all currencies if empty")
+	limit := fs.Int("limit", 25, "maximum number of movements")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	movements, err := c.rest.Wallet.MovementsQuery(*currency, rest.NewQuery().Limit(*limit))
+	if err != nil {
+		return err
+	}
+	return c.print(movements)
+}
+
+func (c *cli) depositAddress(args []string) error {
+	fs := c.flags("deposit-address")
+	wallet := fs.String("wallet", "exchange", "wallet to deposit into")
+	method := fs.String("method", "", "deposit method, e.g. bitcoin")
+	create := fs.Bool("new", false, "create a new address")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *method == "" {
+		return fmt.Errorf("%w: -method is required", common.ErrBadRequest)
+	}
+
+	get := c.rest.Wallet.DepositAddress
+	if *create {
+		get = c.rest.Wallet.CreateDepositAddress
+	}
+	n, err := get(*wallet, *method)
+	if err != nil {
+		return err
+	}
+	return c.print(n.NotifyInfo)
+}
+
+func (c *cli) withdraw(args []string) error {
+	fs := c.flags("withdraw")
+	wallet := fs.String("wallet", "exchange", "wallet to withdraw from")
+	method := fs.String("method", "", "withdrawal method, e.g. bitcoin")
+	amount := fs.Float64("amount", 0, "amount to withdraw")
+	address := fs.String("address", "", "destination address")
+	paymentID := fs.String("payment-id", "", "payment ID or memo, if required by the method")
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *method == "" || *address == "" || *amount <= 0 {
+		return fmt.Errorf("%w: -method, -address and a positive -amount are required", common.ErrBadRequest)
+	}
+
+	if !*yes {
+		ok, err := c.confirm(fmt.Sprintf("withdraw %v via %s from the %s wallet to %s?", *amount, *method, *wallet, *address))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errAborted
+		}
+	}
+
+	var pid *string
+	if *paymentID != "" {
+		pid = paymentID
+	}
+	n, err := c.rest.Wallet.Withdraw(*wallet, *method, *amount, *address, pid)
+	if err != nil {
+		return err
+	}
+	return c.print(n)
+}
+
+func (c *cli) ticker(args []string) error {
+	fs := c.flags("ticker")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: at least one symbol is required", common.ErrBadRequest)
+	}
+	tickers, err := c.rest.Tickers.GetMulti(fs.Args())
+	if err != nil {
+		return err
+	}
+	return c.print(tickers)
+}
+
+func (c *cli) order(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: expected order submit or order cancel", common.ErrBadRequest)
+	}
+	switch args[0] {
+	case "submit":
+		return c.orderSubmit(args[1:])
+	case "cancel":
+		return c.orderCancel(args[1:])
+	}
+	return fmt.Errorf("%w: unknown order command %q", common.ErrBadRequest, args[0])
+}
+
+func (c *cli) orderSubmit(args []string) error {
+	fs := c.flags("order submit")
+	symbol := fs.String("symbol", "", "symbol, e.g. tBTCUSD")
+	typ := fs.String("type", common.OrderTypeExchangeLimit, "order type")
+	amount := fs.Float64("amount", 0, "amount, negative to sell")
+	price := fs.Float64("price", 0, "price")
+	cid := fs.Int64("cid", 0, "client order ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *symbol == "" || *amount == 0 {
+		return fmt.Errorf("%w: -symbol and a non-zero -amount are required", common.ErrBadRequest)
+	}
+
+	o, err := c.rest.Orders.PlaceOrder(context.Background(), &order.NewRequest{
+		CID:    *cid,
+		Type:   *typ,
+		Symbol: *symbol,
+		Amount: *amount,
+		Price:  *price,
+	})
+	if err != nil {
+		return err
+	}
+	return c.print(o)
+}
+
+func (c *cli) orderCancel(args []string) error {
+	fs := c.flags("order cancel")
+	id := fs.Int64("id", 0, "order ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == 0 {
+		return fmt.Errorf("%w: -id is required", common.ErrBadRequest)
+	}
+	return c.rest.Orders.CancelOrder(context.Background(), *id)
+}
+
+// stream prints the messages of a public channel until interrupted
+func (c *cli) stream(args []string) error {
+	fs := c.flags("stream")
+	channel := fs.String("channel", websocket.ChanTrades, "channel: trades, ticker, book, candles or status")
+	symbol := fs.String("symbol", "", "symbol, e.g. tBTCUSD")
+	prec := fs.String("prec", "", "book precision, e.g. P0 or R0")
+	freq := fs.String("freq", "", "book frequency, e.g. F0")
+	length := fs.String("len", "", "number of book levels")
+	key := fs.String("key", "", "key of candles and status channels, e.g. trade:1m:tBTCUSD")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ws := websocket.New()
+	if err := ws.Connect(); err != nil {
+		return err
+	}
+	defer ws.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	_, err := ws.SubscribeFunc(ctx, &websocket.SubscriptionRequest{
+		Event:     websocket.EventSubscribe,
+		Channel:   *channel,
+		Symbol:    *symbol,
+		Precision: *prec,
+		Frequency: *freq,
+		Len:       *length,
+		Key:       *key,
+	}, func(msg interface{}) {
+		_ = c.print(struct {
+			Type string      `json:"type"`
+			Data interface{} `json:"data"`
+		}{strings.TrimPrefix(fmt.Sprintf("%T", msg), "*"), msg})
+	}, cancel)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-interrupt:
+	case <-ctx.Done():
+	}
+	return nil
+}
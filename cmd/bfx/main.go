@@ -0,0 +1,149 @@
+// Command bfx exposes common operations of the Bitfinex API on the command
+// line. It is built on the rest and websocket clients of this module and
+// doubles as an example of their use.
+//
+// Authenticated commands read the credentials from the environment:
+//
+//	export BFX_API_KEY=YOUR_API_KEY
+//	export BFX_API_SECRET=YOUR_API_SECRET
+//
+// Usage:
+//
+//	bfx [-url URL] <command> [flags]
+//
+// Commands:
+//
+//	wallets                                   list the wallets and balances
+//	movements [-currency BTC] [-limit N]      list deposits and withdrawals
+//	deposit-address -method bitcoin [-new]    show or create a deposit address
+//	withdraw -method bitcoin -amount A -address ADDR [-yes]
+//	                                          withdraw after confirmation
+//	ticker SYMBOL...                          show the tickers of the symbols
+//	order submit -symbol S -amount A [-price P] [-type T]
+//	                                          submit an order
+//	order cancel -id ID                       cancel an order
+//	stream -channel trades -symbol S          print the messages of a channel
+//
+// Results are printed as JSON.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bitfinexcom/bitfinex-api-go/v2/rest"
+)
+
+func main() {
+	c := &cli{
+		in:  bufio.NewReader(os.Stdin),
+		out: os.Stdout,
+		key: os.Getenv("BFX_API_KEY"),
+		sec: os.Getenv("BFX_API_SECRET"),
+	}
+	if err := c.run(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "bfx:", err)
+		os.Exit(1)
+	}
+}
+
+// cli holds the state shared by the commands
+type cli struct {
+	in       *bufio.Reader
+	out      io.Writer
+	key, sec string
+
+	rest *rest.Client
+}
+
+const usage = `usage: bfx [-url URL] <command> [flags]
+
+commands:
+  wallets           list the wallets and balances
+  movements         list deposits and withdrawals
+  deposit-address   show or create a deposit address
+  withdraw          withdraw funds after confirmation
+  ticker            show the tickers of symbols
+  order             submit or cancel orders
+  stream            print the messages of a public channel
+
+run "bfx <command> -h" for the flags of a command
+`
+
+func (c *cli) run(args []string) error {
+	fs := flag.NewFlagSet("bfx", flag.ContinueOnError)
+	fs.SetOutput(c.out)
+	fs.Usage = func() { fmt.Fprint(c.out, usage) }
+	base := fs.String("url", "", "base URL of the rest API")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	if *base != "" {
+		c.rest = rest.NewClientWithURL(*base)
+	} else {
+		c.rest = rest.NewClient()
+	}
+	c.rest.Credentials(c.key, c.sec)
+
+	cmd, args := fs.Arg(0), fs.Args()[1:]
+	switch cmd {
+	case "wallets":
+		return c.wallets(args)
+	case "movements":
+		return c.movements(args)
+	case "deposit-address":
+		return c.depositAddress(args)
+	case "withdraw":
+		return c.withdraw(args)
+	case "ticker":
+		return c.ticker(args)
+	case "order":
+		return c.order(args)
+	case "stream":
+		return c.stream(args)
+	}
+	fs.Usage()
+	return fmt.Errorf("unknown command %q", cmd)
+}
+
+// flags returns a flag set for a command writing its usage to the output
+func (c *cli) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("bfx "+name, flag.ContinueOnError)
+	fs.SetOutput(c.out)
+	return fs
+}
+
+// print writes v as indented JSON
+func (c *cli) print(v interface{}) error {
+	enc := json.NewEncoder(c.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// confirm asks the question and reports whether it was answered with yes
+func (c *cli) confirm(question string) (bool, error) {
+	fmt.Fprintf(c.out, "%s [y/N] ", question)
+	answer, err := c.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCLI(input string) (*cli, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &cli{
+		in:  bufio.NewReader(strings.NewReader(input)),
+		out: out,
+		key: "key",
+		sec: "secret",
+	}, out
+}
+
+func TestTicker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tickers?symbols=tBTCUSD", r.RequestURI)
+		_, err := w.Write([]byte(`[["tBTCUSD",10644,50.5,10645,46.8,-136,-0.0126,10645,9271.3,10888,10436]]`))
+		require.Nil(t, err)
+	}))
+	defer server.Close()
+
+	c, out := newTestCLI("")
+	require.Nil(t, c.run([]string{"-url", server.URL, "ticker", "tBTCUSD"}))
+
+	var tickers []map[string]interface{}
+	require.Nil(t, json.Unmarshal(out.Bytes(), &tickers))
+	require.Len(t, tickers, 1)
+	assert.Equal(t, "tBTCUSD", tickers[0]["Symbol"])
+	assert.Equal(t, float64(10645), tickers[0]["LastPrice"])
+}
+
+func TestWithdrawConfirmation(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/auth/w/withdraw", r.URL.Path)
+		_, err := w.Write([]byte(`[1568742390999,"acc_wd-req",null,null,[13080092,null,"bitcoin",null,"exchange",0.1,null,null,0.0004],null,"SUCCESS","Your withdrawal request has been successfully submitted."]`))
+		require.Nil(t, err)
+	}))
+	defer server.Close()
+
+	args := []string{"-url", server.URL, "withdraw", "-method", "bitcoin", "-amount", "0.1", "-address", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"}
+
+	c, out := newTestCLI("n\n")
+	assert.True(t, errors.Is(c.run(args), errAborted))
+	assert.Contains(t, out.String(), "withdraw 0.1 via bitcoin from the exchange wallet to 1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa?")
+	assert.Equal(t, 0, calls)
+
+	c, _ = newTestCLI("yes\n")
+	require.Nil(t, c.run(args))
+	assert.Equal(t, 1, calls)
+
+	c, _ = newTestCLI("")
+	require.Nil(t, c.run(append(args, "-yes")))
+	assert.Equal(t, 2, calls)
+}
+
+func TestOrderCommands(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		pld := map[string]interface{}{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&pld))
+
+		switch r.URL.Path {
+		case "/auth/w/order/submit":
+			assert.Equal(t, "EXCHANGE LIMIT", pld["type"])
+			assert.Equal(t, "-0.5", pld["amount"])
+			_, err := w.Write([]byte(`[1567590617442,"on-req",null,null,[[30630788061,null,1567590617439,"tBTCUSD",1567590617439,1567590617439,-0.5,-0.5,"EXCHANGE LIMIT",null,null,null,0,"ACTIVE",null,null,10000,0,0,0,null,null,null,0,null,null,null,null,"API>BFX",null,null,null]],null,"SUCCESS","Submitting 1 orders."]`))
+			require.Nil(t, err)
+		case "/auth/w/order/cancel":
+			assert.Equal(t, float64(30630788061), pld["id"])
+			_, err := w.Write([]byte(`[1567590617442,"oc-req",null,null,[30630788061,null,1567590617439,"tBTCUSD",1567590617439,1567590617439,-0.5,-0.5,"EXCHANGE LIMIT",null,null,null,0,"ACTIVE",null,null,10000,0,0,0,null,null,null,0,0,null,null,null,"API>BFX",null,null,null],null,"SUCCESS","Submitted for cancellation; waiting for confirmation (ID: 30630788061)."]`))
+			require.Nil(t, err)
+		}
+	}))
+	defer server.Close()
+
+	c, out := newTestCLI("")
+	require.Nil(t, c.run([]string{"-url", server.URL, "order", "submit", "-symbol", "tBTCUSD", "-amount", "-0.5", "-price", "10000"}))
+	var o map[string]interface{}
+	require.Nil(t, json.Unmarshal(out.Bytes(), &o))
+	assert.Equal(t, float64(30630788061), o["ID"])
+
+	c, _ = newTestCLI("")
+	require.Nil(t, c.run([]string{"-url", server.URL, "order", "cancel", "-id", "30630788061"}))
+	assert.Equal(t, []string{"/auth/w/order/submit", "/auth/w/order/cancel"}, paths)
+
+	c, _ = newTestCLI("")
+	assert.NotNil(t, c.run([]string{"-url", server.URL, "order", "replace"}))
+	assert.Len(t, paths, 2)
+}
@@ -0,0 +1,172 @@
+// Package addrvalid validates withdrawal addresses locally, so that obviously
+// malformed addresses are rejected before a withdrawal request is sent.
+package addrvalid
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// ErrInvalidAddress is returned for malformed addresses. It wraps
+// common.ErrBadRequest.
+var ErrInvalidAddress = fmt.Errorf("%w: invalid address", common.ErrBadRequest)
+
+// validators maps withdrawal methods to their address check
+var validators = map[string]func(string) error{
+	"bitcoin":   Bitcoin,
+	"ethereum":  Ethereum,
+	"tetheruse": Ethereum,
+	"liquid":    Liquid,
+	"lbt":       Liquid,
+	"tetherusl": Liquid,
+	"lnx": func(s string) error {
+		_, err := DecodeInvoice(s)
+		return err
+	},
+}
+
+// Validate checks the address for the given withdrawal method. Addresses of
+// methods without a local check are accepted.
+func Validate(method, address string) error {
+	v, ok := validators[strings.ToLower(method)]
+	if !ok {
+		return nil
+	}
+	return v(address)
+}
+
+func invalid(address string, reason interface{}) error {
+	return fmt.Errorf("%w %q: %v", ErrInvalidAddress, address, reason)
+}
+
+// Bitcoin checks a bitcoin mainnet address, either segwit (bech32/bech32m) or
+// legacy base58 P2PKH/P2SH.
+func Bitcoin(address string) error {
+	if strings.HasPrefix(strings.ToLower(address), "bc1") {
+		return segwit(address, "bc")
+	}
+	return base58Address(address, 0x00, 0x05)
+}
+
+// Liquid checks a Liquid mainnet address, either confidential (blech32 or
+// base58) or unconfidential (bech32 or base58).
+func Liquid(address string) error {
+	lower := strings.ToLower(address)
+	switch {
+	case strings.HasPrefix(lower, "lq1"):
+		return confidentialSegwit(address)
+	case strings.HasPrefix(lower, "ex1"):
+		return segwit(address, "ex")
+	}
+
+	payload, err := decodeBase58Check(address)
+	if err != nil {
+		return invalid(address, err)
+	}
+
+	switch {
+	case len(payload) == 21 && (payload[0] == 0x39 || payload[0] == 0x27):
+		return nil
+	case len(payload) == 55 && payload[0] == 0x0c && (payload[1] == 0x39 || payload[1] == 0x27):
+		return nil
+	}
+	return invalid(address, "unknown address version")
+}
+
+var ethAddress = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// Ethereum checks an ethereum address, including the EIP-55 checksum of mixed
+// case addresses.
+func Ethereum(address string) error {
+	if !ethAddress.MatchString(address) {
+		return invalid(address, "not a 20 byte hex address")
+	}
+
+	hex := address[2:]
+	if strings.ToLower(hex) == hex || strings.ToUpper(hex) == hex {
+		return nil
+	}
+
+	hash := keccak256([]byte(strings.ToLower(hex)))
+	for i, c := range hex {
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if c >= 'a' && c <= 'f' && nibble >= 8 || c >= 'A' && c <= 'F' && nibble < 8 {
+			return invalid(address, errChecksum)
+		}
+	}
+	return nil
+}
+
+func base58Address(address string, versions ...byte) error {
+	payload, err := decodeBase58Check(address)
+	if err != nil {
+		return invalid(address, err)
+	}
+	if len(payload) != 21 {
+		return invalid(address, "invalid length")
+	}
+	for _, v := range versions {
+		if payload[0] == v {
+			return nil
+		}
+	}
+	return invalid(address, "unknown address version")
+}
+
+func segwit(address, hrp string) error {
+	if len(address) > 90 {
+		return invalid(address, "too long")
+	}
+
+	got, data, c, err := decodeBech32(address)
+	if err != nil {
+		return invalid(address, err)
+	}
+	if got != hrp || len(data) < 1 {
+		return invalid(address, errEncoding)
+	}
+
+	return witnessProgram(address, data, c == bech32Const, 0)
+}
+
+func confidentialSegwit(address string) error {
+	hrp, data, c, err := decodeBlech32(address)
+	if err != nil {
+		return invalid(address, err)
+	}
+	if hrp != "lq" || len(data) < 1 {
+		return invalid(address, errEncoding)
+	}
+
+	// the witness program is prefixed with the 33 byte blinding key
+	return witnessProgram(address, data, c == blech32Const, 33)
+}
+
+// witnessProgram checks the witness version, program length and the checksum
+// variant required by the version
+func witnessProgram(address string, data []byte, legacyChecksum bool, prefix int) error {
+	version := data[0]
+	program, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return invalid(address, err)
+	}
+	n := len(program) - prefix
+
+	switch {
+	case version > 16:
+		return invalid(address, "invalid witness version")
+	case version == 0 && !legacyChecksum, version > 0 && legacyChecksum:
+		return invalid(address, "checksum variant does not match witness version")
+	case version == 0 && n != 20 && n != 32:
+		return invalid(address, "invalid witness program length")
+	case n < 2 || n > 40:
+		return invalid(address, "invalid witness program length")
+	}
+	return nil
+}
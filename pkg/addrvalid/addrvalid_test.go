@@ -0,0 +1,152 @@
+package addrvalid
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeccak256(t *testing.T) {
+	assert.Equal(t, "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470", hex.EncodeToString(keccak256(nil)))
+	assert.Equal(t, "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45", hex.EncodeToString(keccak256([]byte("abc"))))
+}
+
+func TestBitcoin(t *testing.T) {
+	valid := []string{
+		"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+		"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy",
+		"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq",
+		"BC1QAR0SRRR7XFKVY5L643LYDNW9RE59GTZZWF5MDQ",
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+	}
+	for _, a := range valid {
+		assert.Nil(t, Validate("bitcoin", a), a)
+	}
+
+	invalid := []string{
+		"",
+		"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", // checksum
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", // wrong network
+		"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdr", // checksum
+		"tb1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", // testnet
+		"bc1qAr0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", // mixed case
+	}
+	for _, a := range invalid {
+		err := Validate("BITCOIN", a)
+		assert.True(t, errors.Is(err, ErrInvalidAddress), a)
+		assert.True(t, errors.Is(err, common.ErrBadRequest), a)
+	}
+}
+
+func TestEthereum(t *testing.T) {
+	valid := []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+	}
+	for _, a := range valid {
+		assert.Nil(t, Validate("ethereum", a), a)
+	}
+
+	for _, a := range []string{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"} {
+		assert.True(t, errors.Is(Validate("tetheruse", a), ErrInvalidAddress), a)
+	}
+}
+
+// blech32Encode is used to build liquid test addresses
+func blech32Encode(hrp string, version byte, program []byte, c uint64) string {
+	groups, _ := convertBits(program, 8, 5, true)
+	data := append([]byte{version}, groups...)
+	chk := blech32Polymod(append(append(hrpExpand(hrp), data...), make([]byte, 12)...)) ^ c
+
+	out := hrp + "1"
+	for _, d := range data {
+		out += string(bech32Charset[d])
+	}
+	for i := 0; i < 12; i++ {
+		out += string(bech32Charset[(chk>>(5*(11-i)))&31])
+	}
+	return out
+}
+
+func base58CheckEncode(payload []byte) string {
+	h := sha256.Sum256(payload)
+	h = sha256.Sum256(h[:])
+	b := append(append([]byte{}, payload...), h[:4]...)
+
+	n := new(big.Int).SetBytes(b)
+	out := ""
+	mod := new(big.Int)
+	for n.Sign() > 0 {
+		n.DivMod(n, big.NewInt(58), mod)
+		out = string(base58Alphabet[mod.Int64()]) + out
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = "1" + out
+	}
+	return out
+}
+
+func TestLiquid(t *testing.T) {
+	key := append([]byte{0x02}, make([]byte, 32)...)
+	hash := make([]byte, 20)
+	for i := range hash {
+		hash[i] = byte(i)
+	}
+
+	confidential := blech32Encode("lq", 0, append(key, hash...), blech32Const)
+	assert.Nil(t, Validate("liquid", confidential))
+	assert.Nil(t, Validate("lbt", blech32Encode("lq", 1, append(key, make([]byte, 32)...), blech32mConst)))
+
+	// checksum variant must match the witness version
+	assert.True(t, errors.Is(Validate("liquid", blech32Encode("lq", 0, append(key, hash...), blech32mConst)), ErrInvalidAddress))
+	// program without blinding key
+	assert.True(t, errors.Is(Validate("liquid", blech32Encode("lq", 0, hash, blech32Const)), ErrInvalidAddress))
+	// corrupted checksum
+	assert.True(t, errors.Is(Validate("liquid", confidential[:len(confidential)-1]+"q"), ErrInvalidAddress))
+
+	legacy := base58CheckEncode(append([]byte{0x0c, 0x39}, append(key, hash...)...))
+	assert.Nil(t, Validate("tetherusl", legacy))
+	assert.Nil(t, Validate("liquid", base58CheckEncode(append([]byte{0x27}, hash...))))
+	assert.True(t, errors.Is(Validate("liquid", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"), ErrInvalidAddress))
+}
+
+func TestDecodeInvoice(t *testing.T) {
+	inv, err := DecodeInvoice("lightning:lnbc2500u1pvjluezpp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqypqdq5xysxxatsyp3k7enxv4jsxqzpuaztrnwngzn3kdzw5hydlzf03qdgm2hdq27cqv3agm2awhz5se903vruatfhq77w3ls4evs3ch9zw97j25emudupq63nyw24cg27h2rspfj9srp")
+	require.Nil(t, err)
+	assert.Equal(t, &Invoice{
+		Network:     "bc",
+		AmountMsat:  250000000,
+		Timestamp:   time.Unix(1496314658, 0),
+		Expiry:      time.Minute,
+		PaymentHash: "0001020304050607080900010203040506070809000102030405060708090102",
+		Description: "1 cup coffee",
+	}, inv)
+	assert.Equal(t, time.Unix(1496314718, 0), inv.ExpiresAt())
+
+	inv, err = DecodeInvoice("lnbc1pvjluezpp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqypqdpl2pkx2ctnv5sxxmmwwd5kgetjypeh2ursdae8g6twvus8g6rfwvs8qun0dfjkxaq8rkx3yf5tcsyz3d73gafnh3cax9rn449d9p5uxz9ezhhypd0elx87sjle52x86fux2ypatgddc6k63n7erqz25le42c4u4ecky03ylcqca784w")
+	require.Nil(t, err)
+	assert.Equal(t, int64(0), inv.AmountMsat)
+	assert.Equal(t, "Please consider supporting this project", inv.Description)
+	assert.Equal(t, time.Hour, inv.Expiry)
+
+	_, err = DecodeInvoice("bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq")
+	assert.True(t, errors.Is(err, ErrInvalidAddress))
+	assert.True(t, errors.Is(Validate("lnx", "lnbc1invalid"), ErrInvalidAddress))
+}
+
+func TestValidateUnknownMethod(t *testing.T) {
+	assert.Nil(t, Validate("monero", "anything"))
+}
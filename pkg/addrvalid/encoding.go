@@ -0,0 +1,184 @@
+package addrvalid
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+const (
+	bech32Const   = 1
+	bech32mConst  = 0x2bc830a3
+	blech32Const  = 1
+	blech32mConst = 0x455972a3350f7a1
+)
+
+var (
+	errChecksum = errors.New("invalid checksum")
+	errEncoding = errors.New("invalid encoding")
+)
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+// blech32Polymod is the checksum of the blech32 encoding used by Liquid
+// confidential addresses
+func blech32Polymod(values []byte) uint64 {
+	gen := [5]uint64{0x7d52fba40bd886, 0x5e8dbf1a03950c, 0x1c3a3c74072a18, 0x385d72fa0e5139, 0x7093e5a608865b}
+	chk := uint64(1)
+	for _, v := range values {
+		b := chk >> 55
+		chk = (chk&0x7fffffffffffff)<<5 ^ uint64(v)
+		for i := 0; i < 5; i++ {
+			if (b>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func hrpExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// splitBech32 splits a bech32 like string into its lowercased human readable
+// part and 5 bit data groups, including the checksum
+func splitBech32(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, errEncoding
+	}
+	s = strings.ToLower(s)
+
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, errEncoding
+	}
+
+	data := make([]byte, 0, len(s)-pos-1)
+	for _, c := range s[pos+1:] {
+		d := strings.IndexRune(bech32Charset, c)
+		if d < 0 {
+			return "", nil, errEncoding
+		}
+		data = append(data, byte(d))
+	}
+	return s[:pos], data, nil
+}
+
+// decodeBech32 decodes a bech32 or bech32m string, returning the checksum
+// constant it matched
+func decodeBech32(s string) (string, []byte, uint32, error) {
+	hrp, data, err := splitBech32(s)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	c := bech32Polymod(append(hrpExpand(hrp), data...))
+	if c != bech32Const && c != bech32mConst {
+		return "", nil, 0, errChecksum
+	}
+	return hrp, data[:len(data)-6], c, nil
+}
+
+// decodeBlech32 decodes a blech32 or blech32m string
+func decodeBlech32(s string) (string, []byte, uint64, error) {
+	hrp, data, err := splitBech32(s)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	if len(data) < 12 {
+		return "", nil, 0, errEncoding
+	}
+
+	c := blech32Polymod(append(hrpExpand(hrp), data...))
+	if c != blech32Const && c != blech32mConst {
+		return "", nil, 0, errChecksum
+	}
+	return hrp, data[:len(data)-12], c, nil
+}
+
+// convertBits regroups bits, e.g. from 5 bit bech32 groups to bytes
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	acc, bits := uint(0), uint(0)
+	max := uint(1)<<to - 1
+	out := make([]byte, 0, len(data)*int(from)/int(to)+1)
+	for _, v := range data {
+		if uint(v)>>from != 0 {
+			return nil, errEncoding
+		}
+		acc = acc<<from | uint(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&max))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&max))
+		}
+	} else if bits >= from || acc<<(to-bits)&max != 0 {
+		return nil, errEncoding
+	}
+	return out, nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// decodeBase58Check decodes a base58 string and verifies its double sha256
+// checksum, returning the payload including the version bytes
+func decodeBase58Check(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range s {
+		d := strings.IndexRune(base58Alphabet, c)
+		if d < 0 {
+			return nil, errEncoding
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(d)))
+	}
+
+	b := n.Bytes()
+	for i := 0; i < len(s) && s[i] == '1'; i++ {
+		b = append([]byte{0}, b...)
+	}
+	if len(b) < 5 {
+		return nil, errEncoding
+	}
+
+	payload, sum := b[:len(b)-4], b[len(b)-4:]
+	h := sha256.Sum256(payload)
+	h = sha256.Sum256(h[:])
+	for i := range sum {
+		if sum[i] != h[i] {
+			return nil, errChecksum
+		}
+	}
+	return payload, nil
+}
@@ -0,0 +1,154 @@
+package addrvalid
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Invoice holds the decoded fields of a BOLT 11 lightning invoice. The
+// signature of the invoice is not verified.
+type Invoice struct {
+	Network     string // bc, tb, bcrt or sb
+	AmountMsat  int64  // 0 if the invoice has no amount
+	Timestamp   time.Time
+	Expiry      time.Duration
+	PaymentHash string
+	Description string
+}
+
+// ExpiresAt returns the time after which the invoice can not be paid anymore
+func (i *Invoice) ExpiresAt() time.Time {
+	return i.Timestamp.Add(i.Expiry)
+}
+
+var invoiceNetworks = []string{"bcrt", "bc", "tbs", "tb", "sb"}
+
+// msat per unit of the amount multipliers
+var invoiceMultipliers = map[byte]float64{
+	'm': 1e8,
+	'u': 1e5,
+	'n': 1e2,
+	'p': 1e-1,
+}
+
+const (
+	tagPaymentHash = 1
+	tagExpiry      = 6
+	tagDescription = 13
+
+	signatureGroups = 104
+	timestampGroups = 7
+)
+
+// DecodeInvoice decodes a BOLT 11 lightning invoice, optionally prefixed
+// with "lightning:".
+func DecodeInvoice(s string) (*Invoice, error) {
+	raw := strings.TrimPrefix(strings.ToLower(s), "lightning:")
+
+	hrp, data, c, err := decodeBech32(raw)
+	if err != nil {
+		return nil, invalid(s, err)
+	}
+	if c != bech32Const || !strings.HasPrefix(hrp, "ln") {
+		return nil, invalid(s, "not a lightning invoice")
+	}
+	if len(data) < timestampGroups+signatureGroups {
+		return nil, invalid(s, "too short")
+	}
+
+	inv := &Invoice{Expiry: time.Hour}
+	if inv.Network, inv.AmountMsat, err = invoiceAmount(hrp[2:]); err != nil {
+		return nil, invalid(s, err)
+	}
+	inv.Timestamp = time.Unix(int64(groupsToInt(data[:timestampGroups])), 0)
+
+	fields := data[timestampGroups : len(data)-signatureGroups]
+	for len(fields) > 0 {
+		if len(fields) < 3 {
+			return nil, invalid(s, "truncated tagged field")
+		}
+		tag := fields[0]
+		n := int(fields[1])<<5 | int(fields[2])
+		if len(fields) < 3+n {
+			return nil, invalid(s, "truncated tagged field")
+		}
+		value := fields[3 : 3+n]
+		fields = fields[3+n:]
+
+		switch tag {
+		case tagPaymentHash:
+			if n == 52 {
+				inv.PaymentHash = hex.EncodeToString(groupsToBytes(value))
+			}
+		case tagDescription:
+			inv.Description = string(groupsToBytes(value))
+		case tagExpiry:
+			inv.Expiry = time.Duration(groupsToInt(value)) * time.Second
+		}
+	}
+
+	if inv.PaymentHash == "" {
+		return nil, invalid(s, "missing payment hash")
+	}
+	return inv, nil
+}
+
+func invoiceAmount(s string) (string, int64, error) {
+	network := ""
+	for _, n := range invoiceNetworks {
+		if strings.HasPrefix(s, n) {
+			network = n
+			break
+		}
+	}
+	if network == "" {
+		return "", 0, errEncoding
+	}
+
+	amount := s[len(network):]
+	if amount == "" {
+		return network, 0, nil
+	}
+
+	unit := 1e11 // msat per bitcoin
+	if m, ok := invoiceMultipliers[amount[len(amount)-1]]; ok {
+		unit = m
+		amount = amount[:len(amount)-1]
+	}
+
+	v, err := strconv.ParseInt(amount, 10, 64)
+	if err != nil || v <= 0 {
+		return "", 0, errEncoding
+	}
+
+	msat := float64(v) * unit
+	if msat != float64(int64(msat)) {
+		return "", 0, errEncoding
+	}
+	return network, int64(msat), nil
+}
+
+func groupsToInt(data []byte) uint64 {
+	var v uint64
+	for _, d := range data {
+		v = v<<5 | uint64(d)
+	}
+	return v
+}
+
+// groupsToBytes converts 5 bit groups to bytes, dropping the padding bits
+func groupsToBytes(data []byte) []byte {
+	out := make([]byte, 0, len(data)*5/8)
+	acc, bits := uint(0), uint(0)
+	for _, d := range data {
+		acc = acc<<5 | uint(d)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			out = append(out, byte(acc>>bits))
+		}
+	}
+	return out
+}
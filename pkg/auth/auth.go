@@ -0,0 +1,106 @@
+// Package auth provides the credentials used to authenticate rest requests and
+// websocket sessions.
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNoCredentials is returned by providers without credentials
+var ErrNoCredentials = errors.New("no credentials")
+
+// Credentials authenticate either with an API key and secret, or with an auth
+// token if Token is set
+type Credentials struct {
+	Key    string
+	Secret string
+	Token  string
+}
+
+// Valid reports whether the credentials can authenticate
+func (c Credentials) Valid() bool {
+	return c.Token != "" || (c.Key != "" && c.Secret != "")
+}
+
+// Sign returns the hex encoded HMAC-SHA384 of the message keyed with the
+// secret, as expected in signed requests
+func (c Credentials) Sign(msg string) (string, error) {
+	sig := hmac.New(sha512.New384, []byte(c.Secret))
+	if _, err := sig.Write([]byte(msg)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sig.Sum(nil)), nil
+}
+
+// CredentialsProvider returns the credentials to authenticate with. It is
+// called for every authenticated request and websocket (re-)authentication, so
+// implementations backed by a secret store should cache, see NewCachingProvider.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// ProviderFunc adapts a function to a CredentialsProvider
+type ProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials calls f
+func (f ProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// Static provides fixed credentials
+type Static Credentials
+
+// StaticCredentials returns a provider of the given key and secret
+func StaticCredentials(key, secret string) Static {
+	return Static{Key: key, Secret: secret}
+}
+
+// StaticToken returns a provider of the given auth token
+func StaticToken(token string) Static {
+	return Static{Token: token}
+}
+
+// Credentials returns the static credentials
+func (s Static) Credentials(ctx context.Context) (Credentials, error) {
+	c := Credentials(s)
+	if !c.Valid() {
+		return c, ErrNoCredentials
+	}
+	return c, nil
+}
+
+type cachingProvider struct {
+	mu       sync.Mutex
+	provider CredentialsProvider
+	ttl      time.Duration
+	cached   Credentials
+	fetched  time.Time
+}
+
+// NewCachingProvider returns a provider caching the credentials of p for ttl,
+// after which they are fetched again. Failed fetches are not cached.
+func NewCachingProvider(p CredentialsProvider, ttl time.Duration) CredentialsProvider {
+	return &cachingProvider{provider: p, ttl: ttl}
+}
+
+func (cp *cachingProvider) Credentials(ctx context.Context) (Credentials, error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if cp.cached.Valid() && time.Since(cp.fetched) < cp.ttl {
+		return cp.cached, nil
+	}
+
+	c, err := cp.provider.Credentials(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	cp.cached, cp.fetched = c, time.Now()
+	return c, nil
+}
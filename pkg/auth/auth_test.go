@@ -0,0 +1,66 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatic(t *testing.T) {
+	c, err := auth.StaticCredentials("key", "secret").Credentials(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, auth.Credentials{Key: "key", Secret: "secret"}, c)
+
+	c, err = auth.StaticToken("token").Credentials(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, "token", c.Token)
+
+	_, err = auth.StaticCredentials("key", "").Credentials(context.Background())
+	assert.True(t, errors.Is(err, auth.ErrNoCredentials))
+}
+
+func TestSign(t *testing.T) {
+	sig, err := auth.Credentials{Secret: "secret"}.Sign("/api/v2/auth/r/wallets1{}")
+	require.Nil(t, err)
+	assert.Len(t, sig, 96)
+
+	again, err := auth.Credentials{Secret: "secret"}.Sign("/api/v2/auth/r/wallets1{}")
+	require.Nil(t, err)
+	assert.Equal(t, sig, again)
+}
+
+func TestCachingProvider(t *testing.T) {
+	calls := 0
+	fail := false
+	p := auth.NewCachingProvider(auth.ProviderFunc(func(ctx context.Context) (auth.Credentials, error) {
+		calls++
+		if fail {
+			return auth.Credentials{}, errors.New("vault unavailable")
+		}
+		return auth.Credentials{Key: "key", Secret: "secret"}, nil
+	}), time.Hour)
+
+	for i := 0; i < 3; i++ {
+		c, err := p.Credentials(context.Background())
+		require.Nil(t, err)
+		assert.Equal(t, "key", c.Key)
+	}
+	assert.Equal(t, 1, calls)
+
+	expiring := auth.NewCachingProvider(auth.ProviderFunc(func(ctx context.Context) (auth.Credentials, error) {
+		calls++
+		if fail {
+			return auth.Credentials{}, errors.New("vault unavailable")
+		}
+		return auth.Credentials{Key: "key", Secret: "secret"}, nil
+	}), 0)
+	fail = true
+	_, err := expiring.Credentials(context.Background())
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,219 @@
+// Package bridge publishes the events of the websocket client to an
+// external message bus, so that a single upstream connection can feed
+// several consumers. The bus is abstracted by the Publisher interface, e.g.
+// for NATS:
+//
+//	type natsPublisher struct{ nc *nats.Conn }
+//
+//	func (p natsPublisher) Publish(ctx context.Context, subject string, data []byte) error {
+//		return p.nc.Publish(subject, data)
+//	}
+//
+// for Kafka with segmentio/kafka-go, using the subject as topic:
+//
+//	func (p kafkaPublisher) Publish(ctx context.Context, subject string, data []byte) error {
+//		return p.w.WriteMessages(ctx, kafka.Message{Topic: subject, Value: data})
+//	}
+//
+// or for Redis pub/sub with go-redis:
+//
+//	func (p redisPublisher) Publish(ctx context.Context, subject string, data []byte) error {
+//		return p.rdb.Publish(ctx, subject, data).Err()
+//	}
+//
+// Messages are encoded as JSON by default, other formats such as msgpack
+// can be used by setting Config.Encoder, e.g. to msgpack.Marshal.
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/candle"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/ticker"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecution"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecutionupdate"
+)
+
+// DefaultPrefix is the first part of the default subjects
+const DefaultPrefix = "bfx"
+
+// Kinds of published messages
+const (
+	KindTrade         = "trade"
+	KindBook          = "book"
+	KindTicker        = "ticker"
+	KindCandle        = "candle"
+	KindOrder         = "order"
+	KindFill          = "fill"
+	KindFundingBook   = "fundingbook"
+	KindFundingTicker = "fundingticker"
+)
+
+// Publisher publishes a message to a subject, e.g. a topic or channel, of a
+// message bus
+type Publisher interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
+// Encoder serializes a message
+type Encoder func(v interface{}) ([]byte, error)
+
+// Message is the envelope of a published event
+type Message struct {
+	Kind   string      `json:"kind"`
+	Event  string      `json:"event"` // snapshot, update, new or cancel
+	Symbol string      `json:"symbol"`
+	Data   interface{} `json:"data"`
+}
+
+// Config configures a Bridge
+type Config struct {
+	Publisher Publisher
+	Encoder   Encoder // defaults to json.Marshal
+	Prefix    string  // defaults to DefaultPrefix
+
+	// Subject returns the subject of a message, defaults to
+	// "<prefix>.<kind>.<symbol>", e.g. bfx.trade.tBTCUSD
+	Subject func(prefix string, msg Message) string
+
+	// Kinds restricts the published messages to the given kinds, all kinds
+	// are published if empty
+	Kinds []string
+}
+
+// Bridge converts events of the websocket client into messages and
+// publishes them. Other events are ignored.
+type Bridge struct {
+	cfg   Config
+	kinds map[string]bool
+}
+
+// New returns a bridge publishing to cfg.Publisher
+func New(cfg Config) (*Bridge, error) {
+	if cfg.Publisher == nil {
+		return nil, fmt.Errorf("%w: publisher is required", common.ErrBadRequest)
+	}
+	if cfg.Encoder == nil {
+		cfg.Encoder = json.Marshal
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultPrefix
+	}
+	if cfg.Subject == nil {
+		cfg.Subject = defaultSubject
+	}
+
+	b := &Bridge{cfg: cfg}
+	if len(cfg.Kinds) > 0 {
+		b.kinds = make(map[string]bool, len(cfg.Kinds))
+		for _, k := range cfg.Kinds {
+			b.kinds[k] = true
+		}
+	}
+	return b, nil
+}
+
+// Run publishes the events until ctx is done or events is closed, e.g.:
+//
+//	err := b.Run(ctx, client.Listen(), func(err error) { log.Print(err) })
+//
+// Failed publications are passed to onError, if set, and do not stop the
+// bridge.
+func (b *Bridge) Run(ctx context.Context, events <-chan interface{}, onError func(error)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := b.Handle(ctx, ev); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Handle publishes a single event
+func (b *Bridge) Handle(ctx context.Context, ev interface{}) error {
+	msg, ok := toMessage(ev)
+	if !ok || (b.kinds != nil && !b.kinds[msg.Kind]) {
+		return nil
+	}
+
+	data, err := b.cfg.Encoder(msg)
+	if err != nil {
+		return fmt.Errorf("encoding %s %s: %w", msg.Kind, msg.Event, err)
+	}
+	subject := b.cfg.Subject(b.cfg.Prefix, msg)
+	if err := b.cfg.Publisher.Publish(ctx, subject, data); err != nil {
+		return fmt.Errorf("publishing to %s: %w", subject, err)
+	}
+	return nil
+}
+
+func defaultSubject(prefix string, msg Message) string {
+	parts := []string{prefix, msg.Kind}
+	if msg.Symbol != "" {
+		parts = append(parts, msg.Symbol)
+	}
+	return strings.Join(parts, ".")
+}
+
+// toMessage wraps the supported events into a message
+func toMessage(ev interface{}) (Message, bool) {
+	switch e := ev.(type) {
+	case *trade.Trade:
+		return Message{KindTrade, "update", e.Pair, e}, true
+	case *trade.Snapshot:
+		return snapshot(KindTrade, e, func() string { return e.Snapshot[0].Pair }, len(e.Snapshot))
+	case *book.Book:
+		return Message{KindBook, "update", e.Symbol, e}, true
+	case *book.Snapshot:
+		return snapshot(KindBook, e, func() string { return e.Snapshot[0].Symbol }, len(e.Snapshot))
+	case *book.FundingBookUpdate:
+		return Message{KindFundingBook, "update", e.Symbol, e}, true
+	case *book.FundingSnapshot:
+		return snapshot(KindFundingBook, e, func() string { return e.Snapshot[0].Symbol }, len(e.Snapshot))
+	case *ticker.Ticker:
+		return Message{KindTicker, "update", e.Symbol, e}, true
+	case *ticker.Snapshot:
+		return snapshot(KindTicker, e, func() string { return e.Snapshot[0].Symbol }, len(e.Snapshot))
+	case *ticker.FundingTicker:
+		return Message{KindFundingTicker, "update", e.Symbol, e}, true
+	case *ticker.FundingSnapshot:
+		return snapshot(KindFundingTicker, e, func() string { return e.Snapshot[0].Symbol }, len(e.Snapshot))
+	case *candle.Candle:
+		return Message{KindCandle, "update", e.Symbol, e}, true
+	case *candle.Snapshot:
+		return snapshot(KindCandle, e, func() string { return e.Snapshot[0].Symbol }, len(e.Snapshot))
+	case *order.Snapshot:
+		return Message{KindOrder, "snapshot", "", e}, true
+	case *order.New:
+		return Message{KindOrder, "new", e.Symbol, e}, true
+	case *order.Update:
+		return Message{KindOrder, "update", e.Symbol, e}, true
+	case *order.Cancel:
+		return Message{KindOrder, "cancel", e.Symbol, e}, true
+	case *tradeexecution.TradeExecution:
+		return Message{KindFill, "new", e.Pair, e}, true
+	case *tradeexecutionupdate.TradeExecutionUpdate:
+		return Message{KindFill, "update", e.Pair, e}, true
+	}
+	return Message{}, false
+}
+
+func snapshot(kind string, data interface{}, symbol func() string, n int) (Message, bool) {
+	if n == 0 {
+		return Message{}, false
+	}
+	return Message{kind, "snapshot", symbol(), data}, true
+}
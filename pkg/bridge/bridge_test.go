@@ -0,0 +1,131 @@
+package bridge_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/bridge"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/ticker"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type published struct {
+	subject string
+	data    string
+}
+
+type mockPublisher struct {
+	mu   sync.Mutex
+	msgs []published
+	err  error
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, subject string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.msgs = append(m.msgs, published{subject, string(data)})
+	return nil
+}
+
+func TestBridge(t *testing.T) {
+	_, err := bridge.New(bridge.Config{})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	p := &mockPublisher{}
+	b, err := bridge.New(bridge.Config{Publisher: p})
+	require.Nil(t, err)
+
+	events := make(chan interface{}, 4)
+	events <- &trade.Trade{Pair: "tBTCUSD", ID: 1, MTS: 2, Amount: -0.5, Price: 100}
+	events <- &book.Snapshot{Snapshot: []*book.Book{{Symbol: "tBTCUSD", Price: 99, Amount: 1, Side: common.Bid}}}
+	events <- &order.Cancel{ID: 5, Symbol: "tETHUSD", Status: "CANCELED"}
+	events <- "ignored"
+	close(events)
+	require.Nil(t, b.Run(context.Background(), events, nil))
+
+	require.Len(t, p.msgs, 3)
+	assert.Equal(t, "bfx.trade.tBTCUSD", p.msgs[0].subject)
+	assert.Equal(t, "bfx.book.tBTCUSD", p.msgs[1].subject)
+	assert.Equal(t, "bfx.order.tETHUSD", p.msgs[2].subject)
+
+	var msg struct {
+		Kind   string
+		Event  string
+		Symbol string
+		Data   trade.Trade
+	}
+	require.Nil(t, json.Unmarshal([]byte(p.msgs[0].data), &msg))
+	assert.Equal(t, "trade", msg.Kind)
+	assert.Equal(t, "update", msg.Event)
+	assert.Equal(t, trade.Trade{Pair: "tBTCUSD", ID: 1, MTS: 2, Amount: -0.5, Price: 100}, msg.Data)
+}
+
+func TestBridgeFunding(t *testing.T) {
+	p := &mockPublisher{}
+	b, err := bridge.New(bridge.Config{Publisher: p})
+	require.Nil(t, err)
+	ctx := context.Background()
+
+	require.Nil(t, b.Handle(ctx, &book.FundingSnapshot{Snapshot: []*book.FundingBookUpdate{{Symbol: "fUSD", Rate: 0.0002, Period: 2, Count: 1, Amount: 100, Side: common.Ask}}}))
+	require.Nil(t, b.Handle(ctx, &book.FundingBookUpdate{Symbol: "fUSD", Rate: 0.0003, Period: 30, Count: 2, Amount: 500, Side: common.Ask}))
+	require.Nil(t, b.Handle(ctx, &ticker.FundingTicker{Symbol: "fUSD", Frr: 0.0002}))
+	require.Nil(t, b.Handle(ctx, &ticker.FundingSnapshot{Snapshot: []*ticker.FundingTicker{{Symbol: "fUSD", Frr: 0.0002}}}))
+	require.Nil(t, b.Handle(ctx, &book.FundingSnapshot{}))
+
+	require.Len(t, p.msgs, 4)
+	assert.Equal(t, "bfx.fundingbook.fUSD", p.msgs[0].subject)
+	assert.Equal(t, "bfx.fundingbook.fUSD", p.msgs[1].subject)
+	assert.Equal(t, "bfx.fundingticker.fUSD", p.msgs[2].subject)
+	assert.Equal(t, "bfx.fundingticker.fUSD", p.msgs[3].subject)
+
+	var msg struct {
+		Kind   string
+		Event  string
+		Symbol string
+		Data   book.FundingBookUpdate
+	}
+	require.Nil(t, json.Unmarshal([]byte(p.msgs[1].data), &msg))
+	assert.Equal(t, bridge.KindFundingBook, msg.Kind)
+	assert.Equal(t, "update", msg.Event)
+	assert.Equal(t, 0.0003, msg.Data.Rate)
+	assert.Equal(t, int64(30), msg.Data.Period)
+}
+
+func TestBridgeConfig(t *testing.T) {
+	p := &mockPublisher{}
+	b, err := bridge.New(bridge.Config{
+		Publisher: p,
+		Prefix:    "md",
+		Kinds:     []string{bridge.KindOrder},
+		Encoder:   func(v interface{}) ([]byte, error) { return []byte(v.(bridge.Message).Event), nil },
+		Subject: func(prefix string, msg bridge.Message) string {
+			return prefix + "/" + msg.Kind
+		},
+	})
+	require.Nil(t, err)
+	ctx := context.Background()
+
+	require.Nil(t, b.Handle(ctx, &trade.Trade{Pair: "tBTCUSD"}))
+	require.Nil(t, b.Handle(ctx, &order.New{ID: 1, Symbol: "tBTCUSD"}))
+	assert.Equal(t, []published{{"md/order", "new"}}, p.msgs)
+
+	p.err = errors.New("connection closed")
+	var errs []error
+	events := make(chan interface{}, 1)
+	events <- &order.Update{ID: 1}
+	close(events)
+	require.Nil(t, b.Run(ctx, events, func(err error) { errs = append(errs, err) }))
+	require.Len(t, errs, 1)
+	assert.True(t, errors.Is(errs[0], p.err))
+}
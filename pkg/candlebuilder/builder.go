@@ -0,0 +1,185 @@
+// Package candlebuilder aggregates the public trade stream into candles, e.g.
+// for resolutions or symbols without a candle subscription, or to build
+// candles from recorded trades.
+package candlebuilder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/candle"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+)
+
+// Builder builds a candle per symbol and interval from trades. Intervals are
+// aligned to multiples of the resolution since the Unix epoch. A candle is
+// closed by the first trade of a later interval, or by Flush once the clock
+// passed the end of its interval. Intervals without trades have no candle.
+// Trades have to be passed to Handle, e.g.:
+//
+//	for ev := range client.Listen() {
+//		builder.Handle(ev)
+//	}
+type Builder struct {
+	mu         sync.Mutex
+	resolution common.CandleResolution
+	length     int64 // in milliseconds
+	clock      utils.Clock
+	onClose    func(*candle.Candle)
+	current    map[string]*candle.Candle
+	closed     map[string]int64 // start of the last closed interval
+}
+
+// New returns a builder of candles of the resolution, which passes every
+// closed candle to onClose. The clock decides when candles without further
+// trades are closed, utils.SystemClock is used if it is nil.
+func New(resolution common.CandleResolution, clock utils.Clock, onClose func(*candle.Candle)) (*Builder, error) {
+	d, ok := resolution.Duration()
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported resolution %q", common.ErrBadRequest, resolution)
+	}
+	if onClose == nil {
+		return nil, fmt.Errorf("%w: onClose is required", common.ErrBadRequest)
+	}
+	if clock == nil {
+		clock = utils.SystemClock
+	}
+	return &Builder{
+		resolution: resolution,
+		length:     d.Milliseconds(),
+		clock:      clock,
+		onClose:    onClose,
+		current:    make(map[string]*candle.Candle),
+		closed:     make(map[string]int64),
+	}, nil
+}
+
+// Handle adds trade and trade snapshot events, other events are ignored.
+func (b *Builder) Handle(ev interface{}) {
+	switch e := ev.(type) {
+	case *trade.Trade:
+		b.Add(e)
+	case *trade.Snapshot:
+		// snapshots are sorted by descending time
+		trades := append([]*trade.Trade(nil), e.Snapshot...)
+		sort.SliceStable(trades, func(i, j int) bool { return trades[i].MTS < trades[j].MTS })
+		for _, t := range trades {
+			b.Add(t)
+		}
+	}
+}
+
+// Add adds a trade to the candle of its interval. Trades of intervals which
+// were already closed are ignored.
+func (b *Builder) Add(t *trade.Trade) {
+	start := t.MTS - t.MTS%b.length
+	amount := t.Amount
+	if amount < 0 {
+		amount = -amount
+	}
+
+	b.mu.Lock()
+	c, ok := b.current[t.Pair]
+	var closed *candle.Candle
+	switch {
+	case ok && start < c.MTS:
+		b.mu.Unlock()
+		return
+	case !ok && b.isClosed(t.Pair, start):
+		b.mu.Unlock()
+		return
+	case ok && start == c.MTS:
+		if t.Price > c.High {
+			c.High = t.Price
+		}
+		if t.Price < c.Low {
+			c.Low = t.Price
+		}
+		c.Close = t.Price
+		c.Volume += amount
+		b.mu.Unlock()
+		return
+	case ok:
+		closed = c
+		b.closed[t.Pair] = c.MTS
+	}
+	b.current[t.Pair] = &candle.Candle{
+		Symbol:     t.Pair,
+		Resolution: b.resolution,
+		MTS:        start,
+		Open:       t.Price,
+		Close:      t.Price,
+		High:       t.Price,
+		Low:        t.Price,
+		Volume:     amount,
+	}
+	b.mu.Unlock()
+
+	if closed != nil {
+		b.onClose(closed)
+	}
+}
+
+func (b *Builder) isClosed(symbol string, start int64) bool {
+	last, ok := b.closed[symbol]
+	return ok && start <= last
+}
+
+// Current returns a copy of the open candle of the symbol
+func (b *Builder) Current(symbol string) (*candle.Candle, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.current[symbol]
+	if !ok {
+		return nil, false
+	}
+	cp := *c
+	return &cp, true
+}
+
+// Flush closes the candles whose interval ended by the time of the clock
+func (b *Builder) Flush() {
+	now := common.MtsFromTime(b.clock.Now())
+
+	b.mu.Lock()
+	var closed []*candle.Candle
+	for s, c := range b.current {
+		if c.MTS+b.length <= int64(now) {
+			closed = append(closed, c)
+			delete(b.current, s)
+			b.closed[s] = c.MTS
+		}
+	}
+	b.mu.Unlock()
+
+	sort.Slice(closed, func(i, j int) bool {
+		if closed[i].MTS != closed[j].MTS {
+			return closed[i].MTS < closed[j].MTS
+		}
+		return closed[i].Symbol < closed[j].Symbol
+	})
+	for _, c := range closed {
+		b.onClose(c)
+	}
+}
+
+// Run calls Flush at the end of every interval until ctx is done
+func (b *Builder) Run(ctx context.Context) error {
+	for {
+		mts := int64(common.MtsFromTime(b.clock.Now()))
+		next := time.Duration(b.length-mts%b.length) * time.Millisecond
+		t := b.clock.NewTimer(next)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C():
+			b.Flush()
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package candlebuilder
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/candle"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// start is the beginning of a minute
+const start = 1600000020000
+
+func tr(pair string, mts int64, amount, price float64) *trade.Trade {
+	return &trade.Trade{Pair: pair, MTS: mts, Amount: amount, Price: price}
+}
+
+type collector struct {
+	mu      sync.Mutex
+	candles []*candle.Candle
+}
+
+func (c *collector) add(cd *candle.Candle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.candles = append(c.candles, cd)
+}
+
+func (c *collector) get() []*candle.Candle {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*candle.Candle(nil), c.candles...)
+}
+
+func TestBuilder(t *testing.T) {
+	_, err := New(common.OneMonth, nil, func(*candle.Candle) {})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	clock := utils.NewManualClock(time.Unix(0, start*int64(time.Millisecond)))
+	var c collector
+	b, err := New(common.OneMinute, clock, c.add)
+	require.Nil(t, err)
+
+	// snapshots are newest first
+	b.Handle(&trade.Snapshot{Snapshot: []*trade.Trade{
+		tr("tBTCUSD", start+3000, -0.5, 99),
+		tr("tBTCUSD", start+2000, 1, 102),
+		tr("tBTCUSD", start+1000, 0.2, 100),
+	}})
+	cur, ok := b.Current("tBTCUSD")
+	require.True(t, ok)
+	assert.Equal(t, &candle.Candle{Symbol: "tBTCUSD", Resolution: common.OneMinute, MTS: start, Open: 100, Close: 99, High: 102, Low: 99, Volume: 1.7}, cur)
+
+	// the next interval closes the candle, late trades are ignored
+	b.Add(tr("tBTCUSD", start+60000, 0.1, 101))
+	b.Add(tr("tBTCUSD", start+59000, 0.1, 120))
+	require.Len(t, c.get(), 1)
+	assert.Equal(t, 1.7, c.get()[0].Volume)
+
+	// the clock closes candles without further trades
+	b.Add(tr("tETHUSD", start+61000, 2, 10))
+	clock.Advance(119 * time.Second)
+	b.Flush()
+	assert.Len(t, c.get(), 1)
+	clock.Advance(time.Second)
+	b.Flush()
+	closed := c.get()
+	require.Len(t, closed, 3)
+	assert.Equal(t, "tBTCUSD", closed[1].Symbol)
+	assert.Equal(t, "tETHUSD", closed[2].Symbol)
+	_, ok = b.Current("tETHUSD")
+	assert.False(t, ok)
+
+	b.Add(tr("tETHUSD", start+62000, 2, 10))
+	_, ok = b.Current("tETHUSD")
+	assert.False(t, ok)
+}
+
+func TestBuilderRun(t *testing.T) {
+	clock := utils.NewManualClock(time.Unix(0, (start+30000)*int64(time.Millisecond)))
+	var c collector
+	b, err := New(common.OneMinute, clock, c.add)
+	require.Nil(t, err)
+	b.Add(tr("tBTCUSD", start+30000, 1, 100))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- b.Run(ctx) }()
+
+	for clock.Timers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(30 * time.Second)
+	for len(c.get()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, int64(start), c.get()[0].MTS)
+
+	cancel()
+	assert.True(t, errors.Is(<-done, context.Canceled))
+}
@@ -3,15 +3,23 @@ package convert
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 )
 
 func F64Slice(in []interface{}) ([]float64, error) {
 	var ret []float64
 	for _, e := range in {
-		if item, ok := e.(float64); ok {
+		switch item := e.(type) {
+		case float64:
 			ret = append(ret, item)
-		} else {
+		case json.Number:
+			f, err := item.Float64()
+			if err != nil {
+				return nil, fmt.Errorf("expected slice of float64 but got: %v", in)
+			}
+			ret = append(ret, f)
+		default:
 			return nil, fmt.Errorf("expected slice of float64 but got: %v", in)
 		}
 	}
@@ -48,6 +56,8 @@ func ToInt(in interface{}) int {
 		}
 	case float64:
 		out = int(v)
+	case json.Number:
+		out = int(I64ValOrZero(v))
 	default:
 		if val, ok := in.(int); ok {
 			out = val
@@ -92,10 +102,19 @@ func FloatToJsonNumber(i interface{}) json.Number {
 	return json.Number(strconv.FormatFloat(i.(float64), 'f', -1, 64))
 }
 
+// I64ValOrZero converts in to an int64. Numbers decoded as json.Number keep
+// their full precision, while float64 values lose it above 2^53.
 func I64ValOrZero(in interface{}) (out int64) {
 	switch v := in.(type) {
 	case int:
 		out = int64(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			out = int64(f)
+		}
 	default:
 		if v, ok := in.(float64); ok {
 			out = int64(v)
@@ -106,8 +125,11 @@ func I64ValOrZero(in interface{}) (out int64) {
 }
 
 func IValOrZero(i interface{}) int {
-	if r, ok := i.(float64); ok {
+	switch r := i.(type) {
+	case float64:
 		return int(r)
+	case json.Number:
+		return int(I64ValOrZero(r))
 	}
 	return 0
 }
@@ -116,6 +138,8 @@ func F64ValOrZero(in interface{}) (out float64) {
 	switch v := in.(type) {
 	case int:
 		out = float64(v)
+	case json.Number:
+		out, _ = v.Float64()
 	default:
 		if v, ok := in.(float64); ok {
 			out = v
@@ -142,6 +166,8 @@ func BValOrFalse(in interface{}) (out bool) {
 		if v == "1" {
 			out = true
 		}
+	case json.Number:
+		out = v == "1"
 	default:
 		if v, ok := in.(bool); ok {
 			out = v
@@ -156,3 +182,39 @@ func SValOrEmpty(i interface{}) string {
 	}
 	return ""
 }
+
+// NumberValOrZero returns the exact textual representation of a number, e.g.
+// to parse amounts into a decimal type without going through float64. Numbers
+// not decoded as json.Number are formatted as precisely as their type allows.
+func NumberValOrZero(in interface{}) json.Number {
+	switch v := in.(type) {
+	case json.Number:
+		return v
+	case float64:
+		return json.Number(strconv.FormatFloat(v, 'f', -1, 64))
+	case int:
+		return json.Number(strconv.Itoa(v))
+	case int64:
+		return json.Number(strconv.FormatInt(v, 10))
+	}
+	return "0"
+}
+
+// I64Val converts in to an int64, failing for non-integral numbers and
+// float64 values that can't represent the integer exactly
+func I64Val(in interface{}) (int64, error) {
+	switch v := in.(type) {
+	case json.Number:
+		return v.Int64()
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return 0, fmt.Errorf("%v is not an exact integer", v)
+		}
+		return int64(v), nil
+	}
+	return 0, fmt.Errorf("expected integer but got %T(%v)", in, in)
+}
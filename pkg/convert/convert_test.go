@@ -1,6 +1,7 @@
 package convert_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
@@ -134,3 +135,30 @@ func TestBValOrFalse(t *testing.T) {
 		assert.Equal(t, expected, got)
 	})
 }
+
+func TestJSONNumbers(t *testing.T) {
+	id := json.Number("9007199254740993") // 2^53 + 1
+	assert.Equal(t, int64(9007199254740993), convert.I64ValOrZero(id))
+	assert.Equal(t, int64(9007199254740992), convert.I64ValOrZero(float64(9007199254740993)))
+	assert.Equal(t, 0.25, convert.F64ValOrZero(json.Number("0.25")))
+	assert.Equal(t, 3, convert.ToInt(json.Number("3")))
+	assert.True(t, convert.BValOrFalse(json.Number("1")))
+
+	assert.Equal(t, json.Number("0.1"), convert.NumberValOrZero(json.Number("0.1")))
+	assert.Equal(t, json.Number("0.1"), convert.NumberValOrZero(0.1))
+	assert.Equal(t, json.Number("0"), convert.NumberValOrZero(nil))
+
+	i, err := convert.I64Val(id)
+	require.Nil(t, err)
+	assert.Equal(t, int64(9007199254740993), i)
+	_, err = convert.I64Val(json.Number("1.5"))
+	assert.NotNil(t, err)
+	_, err = convert.I64Val(float64(1 << 54))
+	assert.NotNil(t, err)
+	_, err = convert.I64Val("1")
+	assert.NotNil(t, err)
+
+	f, err := convert.F64Slice([]interface{}{json.Number("1.5"), 2.0})
+	require.Nil(t, err)
+	assert.Equal(t, []float64{1.5, 2}, f)
+}
@@ -0,0 +1,287 @@
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Anomaly describes a field of a raw payload that did not match the type
+// expected by its parser
+type Anomaly struct {
+	Parser   string
+	Index    int
+	Expected string
+	Value    interface{}
+	// Fatal anomalies fail the parser in strict mode, all others are only
+	// reported, e.g. a fractional number read as an integer
+	Fatal bool
+}
+
+func (a Anomaly) String() string {
+	if a.Index < 0 {
+		return fmt.Sprintf("%s: %s", a.Parser, a.Expected)
+	}
+	return fmt.Sprintf("%s: field %d: expected %s, got %T(%v)", a.Parser, a.Index, a.Expected, a.Value, a.Value)
+}
+
+// DecodeError is returned by parsers in strict mode if any field of the
+// payload did not match its expected type
+type DecodeError struct {
+	Anomalies []Anomaly
+}
+
+func (e *DecodeError) Error() string {
+	msgs := make([]string, len(e.Anomalies))
+	for i, a := range e.Anomalies {
+		msgs[i] = a.String()
+	}
+	return "decoding payload: " + strings.Join(msgs, "; ")
+}
+
+// DecodeOptions configures the diagnostics of the FromRaw parsers
+type DecodeOptions struct {
+	// Strict makes parsers return a *DecodeError for mismatched fields
+	// instead of zero-filling them
+	Strict bool
+	// OnAnomaly is called for every mismatched field, fatal or not
+	OnAnomaly func(Anomaly)
+}
+
+var decodeOptions atomic.Value
+
+// SetDecodeOptions sets the default diagnostics of the parsers, used by every
+// parse which is not given options of its own. Clients pass their options to
+// the parsers instead, so the default is only of use for direct FromRaw calls.
+func SetDecodeOptions(o DecodeOptions) {
+	decodeOptions.Store(o)
+}
+
+// CurrentDecodeOptions returns the default options set by SetDecodeOptions
+func CurrentDecodeOptions() DecodeOptions {
+	o, _ := decodeOptions.Load().(DecodeOptions)
+	return o
+}
+
+// Fields reads the fields of a raw payload by index, converting them like the
+// ValOrZero functions. Nil values are zero-filled silently as the API uses
+// them as placeholders. Mismatches are recorded if diagnostics are enabled
+// and returned by Err in strict mode, e.g.:
+//
+//	f := convert.NewFields("order", raw)
+//	o := &Order{ID: f.I64(0), Symbol: f.S(3)}
+//	if err := f.Err(); err != nil {
+//		return nil, err
+//	}
+type Fields struct {
+	parser    string
+	raw       []interface{}
+	opts      DecodeOptions
+	anomalies []Anomaly
+}
+
+// NewFields returns a reader of the given raw payload, parser names the model
+// in reported anomalies. The first of opts configures the diagnostics, the
+// default of SetDecodeOptions applies without.
+func NewFields(parser string, raw []interface{}, opts ...DecodeOptions) *Fields {
+	f := &Fields{parser: parser, raw: raw}
+	if len(opts) > 0 {
+		f.opts = opts[0]
+	} else {
+		f.opts = CurrentDecodeOptions()
+	}
+	return f
+}
+
+func (f *Fields) enabled() bool {
+	return f.opts.Strict || f.opts.OnAnomaly != nil
+}
+
+func (f *Fields) value(i int, expected string) (interface{}, bool) {
+	if i < len(f.raw) {
+		return f.raw[i], true
+	}
+	if f.enabled() {
+		f.report(Anomaly{Index: i, Expected: expected, Fatal: true})
+	}
+	return nil, false
+}
+
+func (f *Fields) report(a Anomaly) {
+	a.Parser = f.parser
+	if a.Fatal {
+		f.anomalies = append(f.anomalies, a)
+	}
+	if f.opts.OnAnomaly != nil {
+		f.opts.OnAnomaly(a)
+	}
+}
+
+func (f *Fields) mismatch(i int, expected string, v interface{}) {
+	if v != nil && f.enabled() {
+		f.report(Anomaly{Index: i, Expected: expected, Value: v, Fatal: true})
+	}
+}
+
+// F64 reads the float at index i
+func (f *Fields) F64(i int) float64 {
+	v, ok := f.value(i, "float64")
+	if !ok {
+		return 0
+	}
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	case json.Number:
+		if x, err := n.Float64(); err == nil {
+			return x
+		}
+	}
+	f.mismatch(i, "float64", v)
+	return 0
+}
+
+// Number reads the number at index i, which may also be sent as a numeric
+// string, e.g. the order size limits of the pair configs
+func (f *Fields) Number(i int) float64 {
+	v, ok := f.value(i, "number")
+	if !ok {
+		return 0
+	}
+	if s, ok := v.(string); ok {
+		x, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			f.mismatch(i, "number", v)
+			return 0
+		}
+		return x
+	}
+	return f.F64(i)
+}
+
+// I64 reads the integer at index i
+func (f *Fields) I64(i int) int64 {
+	v, ok := f.value(i, "int64")
+	if !ok {
+		return 0
+	}
+	switch n := v.(type) {
+	case float64:
+		if n != float64(int64(n)) && f.enabled() {
+			f.report(Anomaly{Index: i, Expected: "int64", Value: v})
+		}
+		return int64(n)
+	case int:
+		return int64(n)
+	case json.Number:
+		if x, err := n.Int64(); err == nil {
+			return x
+		}
+		if x, err := n.Float64(); err == nil {
+			if f.enabled() {
+				f.report(Anomaly{Index: i, Expected: "int64", Value: v})
+			}
+			return int64(x)
+		}
+	}
+	f.mismatch(i, "int64", v)
+	return 0
+}
+
+// Int reads the integer at index i like ToInt, also accepting numeric strings
+func (f *Fields) Int(i int) int {
+	v, ok := f.value(i, "int")
+	if !ok {
+		return 0
+	}
+	switch n := v.(type) {
+	case string:
+		if _, err := strconv.Atoi(n); err != nil {
+			f.mismatch(i, "int", v)
+		}
+	case json.Number:
+		if _, err := n.Float64(); err != nil {
+			f.mismatch(i, "int", v)
+		}
+	case float64, int:
+	default:
+		f.mismatch(i, "int", v)
+	}
+	return ToInt(v)
+}
+
+// S reads the string at index i
+func (f *Fields) S(i int) string {
+	v, ok := f.value(i, "string")
+	if !ok {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	f.mismatch(i, "string", v)
+	return ""
+}
+
+// List reads the nested list at index i
+func (f *Fields) List(i int) []interface{} {
+	v, ok := f.value(i, "list")
+	if !ok {
+		return nil
+	}
+	if l, ok := v.([]interface{}); ok {
+		return l
+	}
+	f.mismatch(i, "list", v)
+	return nil
+}
+
+// Bool reads the flag at index i like BValOrFalse, also accepting the numbers
+// 0 and 1 the API uses for flags
+func (f *Fields) Bool(i int) bool {
+	v, ok := f.value(i, "bool")
+	if !ok {
+		return false
+	}
+	switch n := v.(type) {
+	case bool, string:
+	case float64:
+		if n != 0 && n != 1 {
+			f.mismatch(i, "bool", v)
+		}
+		return n == 1
+	case int:
+		if n != 0 && n != 1 {
+			f.mismatch(i, "bool", v)
+		}
+	case json.Number:
+		if n != "0" && n != "1" {
+			f.mismatch(i, "bool", v)
+		}
+	default:
+		f.mismatch(i, "bool", v)
+	}
+	return BValOrFalse(v)
+}
+
+// Len returns the number of fields of the payload
+func (f *Fields) Len() int {
+	return len(f.raw)
+}
+
+// Anomalies returns all fatal anomalies recorded so far
+func (f *Fields) Anomalies() []Anomaly {
+	return f.anomalies
+}
+
+// Err returns a *DecodeError listing the fatal anomalies in strict mode
+func (f *Fields) Err() error {
+	if !f.opts.Strict || len(f.anomalies) == 0 {
+		return nil
+	}
+	return &DecodeError{Anomalies: f.anomalies}
+}
@@ -0,0 +1,100 @@
+package convert_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldsLenient(t *testing.T) {
+	convert.SetDecodeOptions(convert.DecodeOptions{})
+
+	f := convert.NewFields("test", []interface{}{1.0, "a", nil, "x"})
+	assert.Equal(t, int64(1), f.I64(0))
+	assert.Equal(t, "a", f.S(1))
+	assert.Equal(t, 0.0, f.F64(2))
+	assert.Equal(t, 0.0, f.F64(3))
+	assert.Equal(t, "", f.S(4))
+	assert.Nil(t, f.Err())
+	assert.Empty(t, f.Anomalies())
+}
+
+func TestFieldsStrict(t *testing.T) {
+	var anomalies []convert.Anomaly
+	opts := convert.DecodeOptions{
+		Strict:    true,
+		OnAnomaly: func(a convert.Anomaly) { anomalies = append(anomalies, a) },
+	}
+
+	f := convert.NewFields("test", []interface{}{1.5, "a", nil, "1", 2.0, "2"}, opts)
+	assert.Equal(t, int64(1), f.I64(0)) // non-fatal truncation
+	assert.Equal(t, "a", f.S(1))
+	assert.Equal(t, 0.0, f.F64(2)) // placeholders are fine
+	assert.Equal(t, 0.0, f.F64(3))
+	assert.False(t, f.Bool(4))
+	assert.Equal(t, 2, f.Int(5))
+	assert.Equal(t, "", f.S(6))
+
+	require.Len(t, anomalies, 4)
+	assert.False(t, anomalies[0].Fatal)
+	assert.Equal(t, convert.Anomaly{Parser: "test", Index: 3, Expected: "float64", Value: "1", Fatal: true}, anomalies[1])
+	assert.Equal(t, 4, anomalies[2].Index)
+	assert.Equal(t, 6, anomalies[3].Index)
+
+	err := f.Err()
+	var de *convert.DecodeError
+	require.True(t, errors.As(err, &de))
+	assert.Len(t, de.Anomalies, 3)
+	assert.Contains(t, err.Error(), `test: field 3: expected float64, got string(1)`)
+}
+
+func TestFieldsNumber(t *testing.T) {
+	f := convert.NewFields("test", []interface{}{"0.5", 2.0, nil, "x"}, convert.DecodeOptions{Strict: true})
+	assert.Equal(t, 0.5, f.Number(0))
+	assert.Equal(t, 2.0, f.Number(1))
+	assert.Equal(t, 0.0, f.Number(2))
+	assert.Equal(t, 0.0, f.Number(3))
+
+	var de *convert.DecodeError
+	require.True(t, errors.As(f.Err(), &de))
+	require.Len(t, de.Anomalies, 1)
+	assert.Equal(t, 3, de.Anomalies[0].Index)
+}
+
+func TestFieldsList(t *testing.T) {
+	f := convert.NewFields("test", []interface{}{[]interface{}{1.0}, nil, "x"}, convert.DecodeOptions{Strict: true})
+	assert.Equal(t, []interface{}{1.0}, f.List(0))
+	assert.Nil(t, f.List(1))
+	assert.Nil(t, f.List(2))
+	assert.Equal(t, 3, f.Len())
+
+	var de *convert.DecodeError
+	require.True(t, errors.As(f.Err(), &de))
+	require.Len(t, de.Anomalies, 1)
+	assert.Equal(t, 2, de.Anomalies[0].Index)
+}
+
+func TestFieldsDefaultOptions(t *testing.T) {
+	convert.SetDecodeOptions(convert.DecodeOptions{Strict: true})
+	defer convert.SetDecodeOptions(convert.DecodeOptions{})
+
+	raw := []interface{}{"x"}
+	assert.NotNil(t, func() error { f := convert.NewFields("test", raw); f.F64(0); return f.Err() }())
+	// options given to the parse take precedence over the default
+	f := convert.NewFields("test", raw, convert.DecodeOptions{})
+	f.F64(0)
+	assert.Nil(t, f.Err())
+}
+
+func TestFieldsBoolNumbers(t *testing.T) {
+	f := convert.NewFields("test", []interface{}{1.0, 0.0, 1, json.Number("1")}, convert.DecodeOptions{Strict: true})
+	assert.True(t, f.Bool(0))
+	assert.False(t, f.Bool(1))
+	assert.True(t, f.Bool(2))
+	assert.True(t, f.Bool(3))
+	assert.Nil(t, f.Err())
+}
@@ -0,0 +1,102 @@
+// Package exchange defines small exchange-agnostic interfaces for the core
+// operations of trading applications. Applications working with several
+// exchanges can code against them and replace the exchange with a mock in
+// tests. The Bitfinex implementation is in the v2/adapter package.
+package exchange
+
+import (
+	"context"
+	"time"
+)
+
+// Side is the side of an order or a trade
+type Side int
+
+const (
+	Buy Side = iota + 1
+	Sell
+)
+
+func (s Side) String() string {
+	switch s {
+	case Buy:
+		return "buy"
+	case Sell:
+		return "sell"
+	}
+	return "unknown"
+}
+
+// Balance is the balance of a currency in an account of the exchange, e.g.
+// a wallet.
+type Balance struct {
+	Account   string
+	Currency  string
+	Total     float64
+	Available float64
+}
+
+// Order is a new order. Amount is always positive, a zero Price places a
+// market order. Symbols use the notation of the exchange.
+type Order struct {
+	Symbol   string
+	Side     Side
+	Amount   float64
+	Price    float64
+	ClientID int64 // optional
+}
+
+// Trade is a public trade. Amount is always positive, Side is the side of
+// the taker.
+type Trade struct {
+	Symbol string
+	ID     string
+	Time   time.Time
+	Side   Side
+	Amount float64
+	Price  float64
+}
+
+// BookUpdate is a change of a price level of an order book. An Amount of
+// zero removes the level.
+type BookUpdate struct {
+	Symbol string
+	Side   Side // Buy for bids, Sell for asks
+	Price  float64
+	Amount float64
+}
+
+// Balances returns the balances of all accounts
+type Balances interface {
+	Balances(ctx context.Context) ([]Balance, error)
+}
+
+// OrderSubmitter places orders and returns their id
+type OrderSubmitter interface {
+	SubmitOrder(ctx context.Context, o Order) (string, error)
+}
+
+// OrderCanceler cancels orders by the id returned by SubmitOrder
+type OrderCanceler interface {
+	CancelOrder(ctx context.Context, id string) error
+}
+
+// TradeStreamer streams the public trades of a symbol until ctx is done
+type TradeStreamer interface {
+	StreamTrades(ctx context.Context, symbol string) (<-chan Trade, error)
+}
+
+// BookStreamer streams the order book of a symbol until ctx is done. The
+// first updates of the stream make up the current state of the book.
+type BookStreamer interface {
+	StreamBook(ctx context.Context, symbol string) (<-chan BookUpdate, error)
+}
+
+// Exchange combines all operations
+type Exchange interface {
+	Balances
+	OrderSubmitter
+	OrderCanceler
+	TradeStreamer
+	BookStreamer
+}
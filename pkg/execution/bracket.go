@@ -0,0 +1,324 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+)
+
+// OrderUpdater submits, updates and cancels orders. It is implemented by the
+// websocket client.
+type OrderUpdater interface {
+	OrderSubmitter
+	SubmitUpdateOrder(ctx context.Context, our *order.UpdateRequest) error
+}
+
+// BracketConfig describes an entry order with its exits.
+type BracketConfig struct {
+	Symbol     string
+	Amount     float64 // signed entry amount
+	Price      float64 // entry price, 0 for a market entry
+	TakeProfit float64 // take-profit price, 0 for none
+	StopLoss   float64 // stop-loss price, 0 for none
+	Margin     bool    // use margin instead of exchange order types
+}
+
+// Bracket places an entry order and protects every fill with take-profit and
+// stop-loss orders. If both exits are set they are placed as a single OCO
+// order, so that executing one cancels the other. Order events of the
+// websocket client have to be passed to Handle.
+type Bracket struct {
+	cfg     BracketConfig
+	updater OrderUpdater
+
+	mu       sync.Mutex
+	ctx      context.Context
+	entryCID int64
+	entry    *child
+	exits    map[int64]*child
+	pending  map[int64]float64 // exit amount changes awaiting the order id
+	finished bool
+	err      error
+	done     chan struct{}
+}
+
+// NewBracket validates the configuration and returns a bracket manager.
+func NewBracket(u OrderUpdater, cfg BracketConfig) (*Bracket, error) {
+	if cfg.Symbol == "" {
+		return nil, fmt.Errorf("%w: symbol is required", common.ErrBadRequest)
+	}
+	if cfg.Amount == 0 {
+		return nil, fmt.Errorf("%w: amount must not be zero", common.ErrBadRequest)
+	}
+	if cfg.TakeProfit == 0 && cfg.StopLoss == 0 {
+		return nil, fmt.Errorf("%w: take-profit or stop-loss is required", common.ErrBadRequest)
+	}
+	if cfg.TakeProfit != 0 && cfg.StopLoss != 0 {
+		long := cfg.Amount > 0
+		if long && cfg.TakeProfit <= cfg.StopLoss || !long && cfg.TakeProfit >= cfg.StopLoss {
+			return nil, fmt.Errorf("%w: take-profit and stop-loss prices are on the wrong side", common.ErrBadRequest)
+		}
+	}
+
+	return &Bracket{
+		cfg:     cfg,
+		updater: u,
+		exits:   make(map[int64]*child),
+		pending: make(map[int64]float64),
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Start submits the entry order.
+func (b *Bracket) Start(ctx context.Context) error {
+	b.mu.Lock()
+	if b.ctx != nil {
+		b.mu.Unlock()
+		return fmt.Errorf("bracket already started")
+	}
+	b.ctx = ctx
+
+	orderType := b.orderType(common.OrderTypeExchangeMarket, common.OrderTypeMarket)
+	if b.cfg.Price > 0 {
+		orderType = b.orderType(common.OrderTypeExchangeLimit, common.OrderTypeLimit)
+	}
+
+	// tracked before it is submitted, as its events may arrive before the
+	// updater returns
+	b.entryCID = newCID()
+	b.entry = &child{created: time.Now(), amount: b.cfg.Amount}
+	b.mu.Unlock()
+
+	err := b.updater.SubmitOrder(ctx, &order.NewRequest{
+		CID:    b.entryCID,
+		Type:   orderType,
+		Symbol: b.cfg.Symbol,
+		Amount: b.cfg.Amount,
+		Price:  b.cfg.Price,
+	})
+	if err != nil {
+		b.mu.Lock()
+		b.finish(err)
+		b.mu.Unlock()
+	}
+	return err
+}
+
+// bracketOrder is an exit order or an amount change of one, recorded by the
+// bracket before it is submitted
+type bracketOrder struct {
+	ctx    context.Context
+	cid    int64
+	exit   *order.NewRequest
+	update *order.UpdateRequest
+}
+
+// Handle tracks the entry and exit orders. Events which do not belong to the
+// bracket are ignored.
+func (b *Bracket) Handle(ev interface{}) {
+	o, closed := orderEvent(ev)
+	if o == nil {
+		return
+	}
+	if bo := b.handle(o, closed); bo != nil {
+		b.submit(bo)
+	}
+}
+
+// handle applies the order event and returns the exit order to submit, if
+// any
+func (b *Bracket) handle(o *order.Order, closed bool) *bracketOrder {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.entry == nil || b.finished {
+		return nil
+	}
+
+	if o.CID == b.entryCID {
+		return b.handleEntry(o, closed)
+	}
+
+	c, ok := b.exits[o.CID]
+	if !ok || c.closed {
+		return nil
+	}
+	c.id = o.ID
+	c.filled = o.AmountOrig - o.Amount
+	c.price = o.PriceAvg
+	c.closed = closed
+
+	var bo *bracketOrder
+	if delta, ok := b.pending[o.CID]; ok && !closed {
+		delete(b.pending, o.CID)
+		bo = b.resize(o.CID, c, delta)
+	}
+	b.checkDone()
+	return bo
+}
+
+func (b *Bracket) handleEntry(o *order.Order, closed bool) *bracketOrder {
+	e := b.entry
+	e.id = o.ID
+	e.price = o.PriceAvg
+	filled := o.AmountOrig - o.Amount
+	delta := filled - e.filled
+	e.filled = filled
+	e.closed = e.closed || closed
+
+	var bo *bracketOrder
+	if !isZero(delta) {
+		bo = b.protect(delta)
+	}
+	b.checkDone()
+	return bo
+}
+
+// protect covers a new entry fill, growing the open exit order if there is
+// one. The exit is recorded and returned to be submitted.
+func (b *Bracket) protect(fill float64) *bracketOrder {
+	for cid, c := range b.exits {
+		if c.closed {
+			continue
+		}
+		if c.id == 0 {
+			b.pending[cid] += -fill
+			return nil
+		}
+		return b.resize(cid, c, -fill)
+	}
+
+	cid := newCID()
+	exit := &order.NewRequest{CID: cid, Symbol: b.cfg.Symbol, Amount: -fill}
+	switch {
+	case b.cfg.TakeProfit != 0 && b.cfg.StopLoss != 0:
+		exit.Type = b.orderType(common.OrderTypeExchangeLimit, common.OrderTypeLimit)
+		exit.Price = b.cfg.TakeProfit
+		exit.PriceOcoStop = b.cfg.StopLoss
+		exit.OcoOrder = true
+	case b.cfg.TakeProfit != 0:
+		exit.Type = b.orderType(common.OrderTypeExchangeLimit, common.OrderTypeLimit)
+		exit.Price = b.cfg.TakeProfit
+	default:
+		exit.Type = b.orderType(common.OrderTypeExchangeStop, common.OrderTypeStop)
+		exit.Price = b.cfg.StopLoss
+	}
+
+	b.exits[cid] = &child{created: time.Now(), amount: exit.Amount}
+	return &bracketOrder{ctx: b.ctx, cid: cid, exit: exit}
+}
+
+func (b *Bracket) resize(cid int64, c *child, delta float64) *bracketOrder {
+	c.amount += delta
+	return &bracketOrder{ctx: b.ctx, cid: cid, update: &order.UpdateRequest{ID: c.id, Delta: delta}}
+}
+
+// submit sends the exit order without holding the lock and rolls back its
+// record if that fails, which ends the bracket
+func (b *Bracket) submit(bo *bracketOrder) {
+	var err error
+	if bo.exit != nil {
+		err = b.updater.SubmitOrder(bo.ctx, bo.exit)
+	} else {
+		err = b.updater.SubmitUpdateOrder(bo.ctx, bo.update)
+	}
+	if err == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if bo.exit != nil {
+		delete(b.exits, bo.cid)
+		delete(b.pending, bo.cid)
+	} else if c, ok := b.exits[bo.cid]; ok {
+		c.amount -= bo.update.Delta
+	}
+	b.finish(err)
+}
+
+// Cancel cancels the entry and all exit orders which are still open.
+func (b *Bracket) Cancel(ctx context.Context) error {
+	b.mu.Lock()
+	var cancels []*order.CancelRequest
+	if b.entry != nil && !b.entry.closed {
+		cancels = append(cancels, cancelRequest(b.entryCID, b.entry))
+	}
+	for cid, c := range b.exits {
+		if !c.closed {
+			cancels = append(cancels, cancelRequest(cid, c))
+		}
+	}
+	b.mu.Unlock()
+
+	var err error
+	for _, ocr := range cancels {
+		if cerr := b.updater.SubmitCancel(ctx, ocr); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.finish(err)
+	return err
+}
+
+// Done is closed once the entry and all exits are closed.
+func (b *Bracket) Done() <-chan struct{} {
+	return b.done
+}
+
+// Err returns the error which ended the bracket, if any.
+func (b *Bracket) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.err
+}
+
+// Position returns the amount which was entered and not yet exited.
+func (b *Bracket) Position() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.entry == nil {
+		return 0
+	}
+	pos := b.entry.filled
+	for _, c := range b.exits {
+		pos += c.filled
+	}
+	return pos
+}
+
+func (b *Bracket) checkDone() {
+	if !b.entry.closed {
+		return
+	}
+	for _, c := range b.exits {
+		if !c.closed {
+			return
+		}
+	}
+	b.finish(nil)
+}
+
+func (b *Bracket) finish(err error) {
+	if b.finished {
+		return
+	}
+	b.finished = true
+	b.err = err
+	close(b.done)
+}
+
+func (b *Bracket) orderType(exchange, margin string) string {
+	if b.cfg.Margin {
+		return margin
+	}
+	return exchange
+}
@@ -0,0 +1,158 @@
+package execution_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/execution"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUpdater struct {
+	mockSubmitter
+	updates []*order.UpdateRequest
+}
+
+func (m *mockUpdater) SubmitUpdateOrder(ctx context.Context, our *order.UpdateRequest) error {
+	m.updates = append(m.updates, our)
+	return nil
+}
+
+func TestBracket(t *testing.T) {
+	m := &mockUpdater{}
+	b, err := execution.NewBracket(m, execution.BracketConfig{
+		Symbol:     "tBTCUSD",
+		Amount:     1,
+		Price:      9000,
+		TakeProfit: 9500,
+		StopLoss:   8800,
+	})
+	require.Nil(t, err)
+	require.Nil(t, b.Start(context.Background()))
+
+	orders := m.submitted()
+	require.Len(t, orders, 1)
+	entry := orders[0]
+	assert.Equal(t, common.OrderTypeExchangeLimit, entry.Type)
+
+	// partial fill places the oco exit
+	b.Handle(&order.Update{ID: 1, CID: entry.CID, AmountOrig: 1, Amount: 0.6, PriceAvg: 9000})
+	orders = m.submitted()
+	require.Len(t, orders, 2)
+	exit := orders[1]
+	assert.InDelta(t, -0.4, exit.Amount, 1e-9)
+	assert.Equal(t, 9500.0, exit.Price)
+	assert.Equal(t, 8800.0, exit.PriceOcoStop)
+	assert.True(t, exit.OcoOrder)
+
+	// further fill before the exit is acknowledged is applied on ack
+	b.Handle(&order.Update{ID: 1, CID: entry.CID, AmountOrig: 1, Amount: 0.2, PriceAvg: 9000})
+	assert.Empty(t, m.updates)
+	b.Handle(&order.New{ID: 2, CID: exit.CID, AmountOrig: -0.4, Amount: -0.4})
+	require.Len(t, m.updates, 1)
+	assert.Equal(t, int64(2), m.updates[0].ID)
+	assert.InDelta(t, -0.4, m.updates[0].Delta, 1e-9)
+
+	// final fill grows the exit directly
+	b.Handle(&order.Cancel{ID: 1, CID: entry.CID, AmountOrig: 1, Amount: 0, PriceAvg: 9000, Status: "EXECUTED @ 9000.0"})
+	require.Len(t, m.updates, 2)
+	assert.InDelta(t, -0.2, m.updates[1].Delta, 1e-9)
+	assert.InDelta(t, 1, b.Position(), 1e-9)
+
+	b.Handle(&order.Cancel{ID: 2, CID: exit.CID, AmountOrig: -1, Amount: 0, PriceAvg: 9500, Status: "EXECUTED @ 9500.0"})
+	<-b.Done()
+	assert.Nil(t, b.Err())
+	assert.InDelta(t, 0, b.Position(), 1e-9)
+}
+
+func TestBracketCancel(t *testing.T) {
+	m := &mockUpdater{}
+	b, err := execution.NewBracket(m, execution.BracketConfig{
+		Symbol:   "tBTCUSD",
+		Amount:   -1,
+		StopLoss: 9500,
+		Margin:   true,
+	})
+	require.Nil(t, err)
+	require.Nil(t, b.Start(context.Background()))
+
+	entry := m.submitted()[0]
+	assert.Equal(t, common.OrderTypeMarket, entry.Type)
+
+	b.Handle(&order.Update{ID: 1, CID: entry.CID, AmountOrig: -1, Amount: -0.5})
+	exit := m.submitted()[1]
+	assert.Equal(t, common.OrderTypeStop, exit.Type)
+	assert.Equal(t, 0.5, exit.Amount)
+	assert.False(t, exit.OcoOrder)
+
+	require.Nil(t, b.Cancel(context.Background()))
+	<-b.Done()
+	require.Len(t, m.cancels, 2)
+	assert.Equal(t, int64(1), m.cancels[0].ID)
+	assert.Equal(t, exit.CID, m.cancels[1].CID)
+}
+
+func TestBracketValidation(t *testing.T) {
+	_, err := execution.NewBracket(&mockUpdater{}, execution.BracketConfig{Symbol: "tBTCUSD", Amount: 1})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	_, err = execution.NewBracket(&mockUpdater{}, execution.BracketConfig{
+		Symbol: "tBTCUSD", Amount: 1, TakeProfit: 8000, StopLoss: 9000,
+	})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
+
+// handlingUpdater hands the events of its orders to the bracket before
+// SubmitOrder returns, as a websocket reader may: the entry is half filled
+// and exits are acknowledged
+type handlingUpdater struct {
+	mockUpdater
+	b    *execution.Bracket
+	fail error // returned for exits
+}
+
+func (h *handlingUpdater) SubmitOrder(ctx context.Context, onr *order.NewRequest) error {
+	entry := len(h.submitted()) == 0
+	if !entry && h.fail != nil {
+		return h.fail
+	}
+	_ = h.mockUpdater.SubmitOrder(ctx, onr)
+	if entry {
+		h.b.Handle(&order.Update{ID: 1, CID: onr.CID, AmountOrig: onr.Amount, Amount: onr.Amount / 2})
+	} else {
+		h.b.Handle(&order.New{ID: 2, CID: onr.CID, AmountOrig: onr.Amount, Amount: onr.Amount})
+	}
+	return nil
+}
+
+func TestBracketEventsDuringSubmit(t *testing.T) {
+	cfg := execution.BracketConfig{Symbol: "tBTCUSD", Amount: 1, Price: 9000, TakeProfit: 9500}
+
+	h := &handlingUpdater{}
+	b, err := execution.NewBracket(h, cfg)
+	require.Nil(t, err)
+	h.b = b
+	require.Nil(t, b.Start(context.Background()))
+	orders := h.submitted()
+	require.Len(t, orders, 2)
+	assert.InDelta(t, -0.5, orders[1].Amount, 1e-9)
+
+	b.Handle(&order.Cancel{ID: 1, CID: orders[0].CID, AmountOrig: 1, Amount: 0, PriceAvg: 9000, Status: "EXECUTED @ 9000.0"})
+	require.Len(t, h.updates, 1)
+	assert.Equal(t, int64(2), h.updates[0].ID)
+	assert.InDelta(t, -0.5, h.updates[0].Delta, 1e-9)
+
+	// a failed exit ends the bracket
+	h = &handlingUpdater{fail: errors.New("not connected")}
+	b, err = execution.NewBracket(h, cfg)
+	require.Nil(t, err)
+	h.b = b
+	require.Nil(t, b.Start(context.Background()))
+	<-b.Done()
+	assert.Equal(t, h.fail, b.Err())
+	assert.InDelta(t, 0.5, b.Position(), 1e-9)
+}
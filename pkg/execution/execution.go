@@ -0,0 +1,47 @@
+// Package execution provides client-side order execution helpers built on top
+// of the websocket order and fill stream.
+package execution
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+)
+
+// OrderSubmitter submits and cancels orders. It is implemented by the
+// websocket client.
+type OrderSubmitter interface {
+	SubmitOrder(ctx context.Context, onr *order.NewRequest) error
+	SubmitCancel(ctx context.Context, ocr *order.CancelRequest) error
+}
+
+// epsilon is the tolerance used when comparing amounts
+const epsilon = 1e-8
+
+var cidCounter = time.Now().UnixNano() / int64(time.Millisecond)
+
+// newCID returns a client order id which is unique within the process
+func newCID() int64 {
+	return atomic.AddInt64(&cidCounter, 1)
+}
+
+func isZero(amount float64) bool {
+	return math.Abs(amount) < epsilon
+}
+
+// orderEvent normalizes the order events emitted by the websocket client.
+// The returned bool reports whether the order is closed.
+func orderEvent(ev interface{}) (*order.Order, bool) {
+	switch o := ev.(type) {
+	case *order.New:
+		return (*order.Order)(o), false
+	case *order.Update:
+		return (*order.Order)(o), false
+	case *order.Cancel:
+		return (*order.Order)(o), true
+	}
+	return nil, false
+}
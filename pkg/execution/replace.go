@@ -0,0 +1,255 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+)
+
+// ReplaceState is the outcome of a cancel-and-replace.
+type ReplaceState int
+
+const (
+	// ReplacePending means the outcome is not known yet
+	ReplacePending ReplaceState = iota
+	// ReplaceDone means the original was cancelled and the replacement
+	// accepted
+	ReplaceDone
+	// ReplaceCancelRejected means the cancel was rejected. The original is
+	// unchanged and no replacement was placed.
+	ReplaceCancelRejected
+	// ReplaceOriginalFilled means the original was executed before it
+	// could be cancelled. No replacement was placed.
+	ReplaceOriginalFilled
+	// ReplaceRejected means the original was cancelled but the replacement
+	// was rejected, so neither order is open.
+	ReplaceRejected
+)
+
+func (s ReplaceState) String() string {
+	switch s {
+	case ReplacePending:
+		return "pending"
+	case ReplaceDone:
+		return "done"
+	case ReplaceCancelRejected:
+		return "cancel rejected"
+	case ReplaceOriginalFilled:
+		return "original filled"
+	case ReplaceRejected:
+		return "replacement rejected"
+	}
+	return fmt.Sprintf("ReplaceState(%d)", int(s))
+}
+
+// ReplaceOutcome reports the state of both orders of a cancel-and-replace.
+// On ReplaceRejected, Request can be resubmitted to restore the quote.
+type ReplaceOutcome struct {
+	State       ReplaceState
+	Original    *order.Order // last known state of the original order
+	Replacement *order.Order // accepted replacement, set on ReplaceDone
+	Request     order.NewRequest
+	Err         error
+}
+
+// Replace is a cancel-and-replace in progress.
+type Replace struct {
+	ctx     context.Context
+	id      int64
+	outcome ReplaceOutcome
+	done    chan struct{}
+}
+
+// Done is closed once the outcome is known.
+func (r *Replace) Done() <-chan struct{} {
+	return r.done
+}
+
+// Outcome returns the outcome, which is ReplacePending until Done is closed.
+func (r *Replace) Outcome() ReplaceOutcome {
+	select {
+	case <-r.done:
+		return r.outcome
+	default:
+		return ReplaceOutcome{State: ReplacePending, Request: r.outcome.Request}
+	}
+}
+
+// Wait blocks until the outcome is known or ctx is done.
+func (r *Replace) Wait(ctx context.Context) (ReplaceOutcome, error) {
+	select {
+	case <-r.done:
+		return r.outcome, nil
+	case <-ctx.Done():
+		return r.Outcome(), ctx.Err()
+	}
+}
+
+// Replacer cancels orders and places their replacements once the cancel is
+// confirmed, so that at no time both orders are open. Every replacement ends
+// in one of the ReplaceStates, in particular a rejected replacement is
+// reported instead of silently leaving the caller without an order. Order
+// events and notifications of the websocket client have to be passed to
+// Handle.
+type Replacer struct {
+	submitter OrderSubmitter
+
+	mu        sync.Mutex
+	canceling map[int64]*Replace // by order id of the original
+	placing   map[int64]*Replace // by client id of the replacement
+}
+
+// NewReplacer returns a replacer submitting through s.
+func NewReplacer(s OrderSubmitter) *Replacer {
+	return &Replacer{
+		submitter: s,
+		canceling: make(map[int64]*Replace),
+		placing:   make(map[int64]*Replace),
+	}
+}
+
+// Replace cancels the order with the given id and places onr once the
+// cancel is confirmed. A client id is assigned to the replacement if it has
+// none. ctx is used to submit the replacement.
+func (r *Replacer) Replace(ctx context.Context, id int64, onr *order.NewRequest) (*Replace, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("%w: order id is required", common.ErrBadRequest)
+	}
+
+	req := *onr
+	if req.CID == 0 {
+		req.CID = newCID()
+	}
+
+	rp := &Replace{
+		ctx:     ctx,
+		id:      id,
+		outcome: ReplaceOutcome{Request: req},
+		done:    make(chan struct{}),
+	}
+	// registered before the cancel is submitted, as its events may arrive
+	// before the submitter returns
+	r.mu.Lock()
+	if _, ok := r.canceling[id]; ok {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%w: order %d is already being replaced", common.ErrBadRequest, id)
+	}
+	r.canceling[id] = rp
+	r.mu.Unlock()
+
+	if err := r.submitter.SubmitCancel(ctx, &order.CancelRequest{ID: id}); err != nil {
+		r.mu.Lock()
+		if r.canceling[id] == rp {
+			delete(r.canceling, id)
+		}
+		r.mu.Unlock()
+		return nil, err
+	}
+	return rp, nil
+}
+
+// Handle advances the replacements the event belongs to. Other events are
+// ignored.
+func (r *Replacer) Handle(ev interface{}) {
+	if rp := r.handle(ev); rp != nil {
+		r.place(rp)
+	}
+}
+
+// handle advances the replacements with the lock held and returns the
+// replacement to place once its original was cancelled
+func (r *Replacer) handle(ev interface{}) *Replace {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if n, ok := ev.(*notification.Notification); ok {
+		r.handleNotification(n)
+		return nil
+	}
+
+	o, closed := orderEvent(ev)
+	if o == nil {
+		return nil
+	}
+	if rp, ok := r.canceling[o.ID]; ok && closed {
+		delete(r.canceling, o.ID)
+		return r.handleCanceled(rp, o)
+	}
+	if rp, ok := r.placing[o.CID]; ok {
+		delete(r.placing, o.CID)
+		state := ReplaceDone
+		if closed && strings.HasPrefix(o.Status, common.OrderStatusPostOnlyCanceled) {
+			state = ReplaceRejected
+		}
+		rp.outcome.Replacement = o
+		r.finish(rp, state, nil)
+	}
+	return nil
+}
+
+// Pending returns the number of replacements with an unknown outcome
+func (r *Replacer) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.canceling) + len(r.placing)
+}
+
+func (r *Replacer) handleNotification(n *notification.Notification) {
+	rejection := n.Err()
+	if rejection == nil {
+		return
+	}
+
+	switch info := n.NotifyInfo.(type) {
+	case order.Cancel:
+		rp, ok := r.canceling[info.ID]
+		if !ok {
+			return
+		}
+		delete(r.canceling, info.ID)
+		r.finish(rp, ReplaceCancelRejected, fmt.Errorf("cancel of order %d rejected: %w", info.ID, rejection))
+	case order.New:
+		rp, ok := r.placing[info.CID]
+		if !ok {
+			return
+		}
+		delete(r.placing, info.CID)
+		r.finish(rp, ReplaceRejected, fmt.Errorf("replacement of order %d rejected: %w", rp.id, rejection))
+	}
+}
+
+// handleCanceled records the closed original and returns rp if its
+// replacement is to be placed, which is registered already
+func (r *Replacer) handleCanceled(rp *Replace, o *order.Order) *Replace {
+	rp.outcome.Original = o
+	if !strings.HasPrefix(o.Status, common.OrderStatusCanceled) {
+		r.finish(rp, ReplaceOriginalFilled, nil)
+		return nil
+	}
+	r.placing[rp.outcome.Request.CID] = rp
+	return rp
+}
+
+// place submits the replacement of rp without holding the lock
+func (r *Replacer) place(rp *Replace) {
+	req := rp.outcome.Request
+	if err := r.submitter.SubmitOrder(rp.ctx, &req); err != nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.placing[req.CID] == rp {
+			delete(r.placing, req.CID)
+			r.finish(rp, ReplaceRejected, err)
+		}
+	}
+}
+
+func (r *Replacer) finish(rp *Replace, state ReplaceState, err error) {
+	rp.outcome.State = state
+	rp.outcome.Err = err
+	close(rp.done)
+}
@@ -0,0 +1,125 @@
+package execution_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/execution"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplacer(t *testing.T) {
+	ctx := context.Background()
+	replacement := &order.NewRequest{Symbol: "tBTCUSD", Type: common.OrderTypeExchangeLimit, Amount: 1, Price: 99}
+
+	t.Run("replaces the order once cancelled", func(t *testing.T) {
+		m := &mockSubmitter{}
+		r := execution.NewReplacer(m)
+		rp, err := r.Replace(ctx, 1, replacement)
+		require.Nil(t, err)
+		require.Len(t, m.cancels, 1)
+		assert.Equal(t, int64(1), m.cancels[0].ID)
+		assert.Empty(t, m.submitted())
+
+		r.Handle(&order.Cancel{ID: 1, Amount: 1, Status: common.OrderStatusCanceled})
+		orders := m.submitted()
+		require.Len(t, orders, 1)
+		assert.NotZero(t, orders[0].CID)
+		assert.Equal(t, execution.ReplacePending, rp.Outcome().State)
+
+		r.Handle(&order.New{ID: 2, CID: orders[0].CID, Amount: 1, Status: common.OrderStatusActive})
+		out, err := rp.Wait(ctx)
+		require.Nil(t, err)
+		assert.Equal(t, execution.ReplaceDone, out.State)
+		assert.Equal(t, int64(1), out.Original.ID)
+		assert.Equal(t, int64(2), out.Replacement.ID)
+		assert.Zero(t, r.Pending())
+	})
+
+	t.Run("reports rejected replacements", func(t *testing.T) {
+		m := &mockSubmitter{}
+		r := execution.NewReplacer(m)
+		rp, err := r.Replace(ctx, 1, replacement)
+		require.Nil(t, err)
+
+		r.Handle(&order.Cancel{ID: 1, Amount: 1, Status: common.OrderStatusCanceled})
+		cid := m.submitted()[0].CID
+		r.Handle(&notification.Notification{
+			Type:       "on-req",
+			NotifyInfo: order.New{CID: cid},
+			Status:     "ERROR",
+			Text:       "Invalid order: not enough exchange balance",
+		})
+
+		out := rp.Outcome()
+		assert.Equal(t, execution.ReplaceRejected, out.State)
+		assert.Contains(t, out.Err.Error(), "not enough exchange balance")
+		assert.Equal(t, cid, out.Request.CID)
+		assert.Nil(t, out.Replacement)
+	})
+
+	t.Run("does not replace filled orders", func(t *testing.T) {
+		m := &mockSubmitter{}
+		r := execution.NewReplacer(m)
+		rp, err := r.Replace(ctx, 1, replacement)
+		require.Nil(t, err)
+
+		r.Handle(&order.Cancel{ID: 1, Status: "EXECUTED @ 100.0(1.0)"})
+		assert.Equal(t, execution.ReplaceOriginalFilled, rp.Outcome().State)
+		assert.Empty(t, m.submitted())
+	})
+
+	t.Run("reports rejected cancels", func(t *testing.T) {
+		m := &mockSubmitter{}
+		r := execution.NewReplacer(m)
+		rp, err := r.Replace(ctx, 1, replacement)
+		require.Nil(t, err)
+
+		_, err = r.Replace(ctx, 1, replacement)
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+		r.Handle(&notification.Notification{
+			Type:       "oc-req",
+			NotifyInfo: order.Cancel{ID: 1},
+			Status:     "ERROR",
+			Text:       "Order not found.",
+		})
+		out := rp.Outcome()
+		assert.Equal(t, execution.ReplaceCancelRejected, out.State)
+		assert.NotNil(t, out.Err)
+		assert.Empty(t, m.submitted())
+	})
+	t.Run("handles events arriving during submission", func(t *testing.T) {
+		m := &echoSubmitter{}
+		r := execution.NewReplacer(m)
+		m.r = r
+		rp, err := r.Replace(ctx, 1, replacement)
+		require.Nil(t, err)
+
+		out, err := rp.Wait(ctx)
+		require.Nil(t, err)
+		assert.Equal(t, execution.ReplaceDone, out.State)
+		assert.Zero(t, r.Pending())
+	})
+}
+
+// echoSubmitter confirms cancels and orders to the replacer before it
+// returns, as a websocket reader may
+type echoSubmitter struct {
+	r *execution.Replacer
+}
+
+func (e *echoSubmitter) SubmitOrder(ctx context.Context, onr *order.NewRequest) error {
+	e.r.Handle(&order.New{ID: 2, CID: onr.CID, Amount: onr.Amount, Status: common.OrderStatusActive})
+	return nil
+}
+
+func (e *echoSubmitter) SubmitCancel(ctx context.Context, ocr *order.CancelRequest) error {
+	e.r.Handle(&order.Cancel{ID: ocr.ID, Amount: 1, Status: common.OrderStatusCanceled})
+	return nil
+}
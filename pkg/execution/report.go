@@ -0,0 +1,151 @@
+package execution
+
+import (
+	"math"
+	"strings"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecutionupdate"
+)
+
+// ExecType is the kind of change reported by an ExecutionReport. The values
+// follow the names of the ExecType field of FIX execution reports.
+type ExecType string
+
+const (
+	ExecNew      ExecType = "NEW"
+	ExecReplaced ExecType = "REPLACED"     // the price or amount of the order was changed
+	ExecStatus   ExecType = "ORDER_STATUS" // the status changed otherwise, e.g. a stop was triggered
+	ExecTrade    ExecType = "TRADE"
+	ExecCanceled ExecType = "CANCELED"
+	ExecDone     ExecType = "DONE" // the order was fully executed
+	ExecRejected ExecType = "REJECTED"
+)
+
+// ExecutionReport is the normalized form of the order, trade and
+// notification events of an order. Quantities are signed, negative for
+// sells.
+type ExecutionReport struct {
+	ExecType    ExecType
+	OrderID     int64
+	CID         int64
+	Symbol      string
+	PrevStatus  string // status before the change, empty for new orders
+	Status      string
+	LastQty     float64 // amount of the fill, TRADE only
+	LastPrice   float64 // price of the fill, TRADE only
+	CumQty      float64
+	LeavesQty   float64
+	AvgPrice    float64
+	Fee         float64 // fee of the fill, TRADE only
+	FeeCurrency string
+	Text        string // reason of rejections
+	MTS         int64
+}
+
+// OnReport registers fn to be called with an execution report for every
+// change of a tracked order. Fills are reported once per trade when the tu
+// event carrying the fee is received. fn is called with the lock of the
+// tracker held.
+func (t *OrderTracker) OnReport(fn func(ExecutionReport)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onReport = fn
+}
+
+// reportOrder reports the order event o, prev is the tracked state before.
+// Updates caused by a fill are not reported, the fill is reported by
+// reportTrade once its tu arrives.
+func (t *OrderTracker) reportOrder(prev *tracked, o *order.Order, closed bool) {
+	r := ExecutionReport{
+		ExecType:  ExecNew,
+		OrderID:   o.ID,
+		CID:       o.CID,
+		Symbol:    o.Symbol,
+		Status:    o.Status,
+		CumQty:    o.AmountOrig - o.Amount,
+		LeavesQty: o.Amount,
+		AvgPrice:  o.PriceAvg,
+		MTS:       o.MTSUpdated,
+	}
+	if prev != nil {
+		p := prev.order
+		r.PrevStatus = p.Status
+		switch {
+		case closed:
+		case !isZero(r.CumQty - (p.AmountOrig - p.Amount)):
+			return
+		case o.Price != p.Price || o.PriceAuxLimit != p.PriceAuxLimit || !isZero(o.AmountOrig-p.AmountOrig):
+			r.ExecType = ExecReplaced
+		case o.Status != p.Status:
+			r.ExecType = ExecStatus
+		default:
+			return
+		}
+	}
+	if closed {
+		r.LeavesQty = 0
+		r.ExecType = ExecCanceled
+		if strings.HasPrefix(o.Status, common.OrderStatusExecuted) {
+			r.ExecType = ExecDone
+		}
+	}
+	t.onReport(r)
+}
+
+// reportTrade reports the fill tu of the order with the fills f. The status
+// is the one of the order event of the fill if it arrived first, or else the
+// one the fill leads to.
+func (t *OrderTracker) reportTrade(tu *tradeexecutionupdate.TradeExecutionUpdate, f *fills) {
+	r := ExecutionReport{
+		ExecType:    ExecTrade,
+		OrderID:     tu.OrderID,
+		Symbol:      tu.Pair,
+		LastQty:     tu.ExecAmount,
+		LastPrice:   tu.ExecPrice,
+		CumQty:      f.Filled,
+		AvgPrice:    f.AvgPrice,
+		Fee:         tu.Fee,
+		FeeCurrency: tu.FeeCurrency,
+		MTS:         tu.MTS,
+	}
+	if o, ok := t.orders[tu.OrderID]; ok {
+		r.CID = o.order.CID
+		r.LeavesQty = o.order.AmountOrig - f.Filled
+		if math.Abs(r.LeavesQty) < epsilon {
+			r.LeavesQty = 0
+		}
+		if isZero(o.order.AmountOrig - o.order.Amount - f.Filled) {
+			r.Status, r.PrevStatus = o.order.Status, o.prevStatus
+		} else {
+			r.PrevStatus = o.order.Status
+			r.Status = common.OrderStatusPartiallyFilled
+			if r.LeavesQty == 0 {
+				r.Status = common.OrderStatusExecuted
+			}
+		}
+	}
+	t.onReport(r)
+}
+
+// reportRejection reports orders rejected by the exchange, which are only
+// announced by an error notification
+func (t *OrderTracker) reportRejection(n *notification.Notification) {
+	if n.Err() == nil {
+		return
+	}
+	o, ok := n.NotifyInfo.(order.New)
+	if !ok {
+		return
+	}
+	t.onReport(ExecutionReport{
+		ExecType: ExecRejected,
+		CID:      o.CID,
+		Symbol:   o.Symbol,
+		Status:   string(n.Status),
+		Text:     n.Text,
+		MTS:      n.MTS,
+	})
+}
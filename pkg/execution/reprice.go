@@ -0,0 +1,207 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+)
+
+// DefaultRepriceAttempts is the number of resubmissions of a post-only order
+const DefaultRepriceAttempts = 3
+
+// Quote returns the best bid and ask of a symbol, false if either is
+// unknown, e.g. from the managed orderbook of the websocket client:
+//
+//	func(symbol string) (float64, float64, bool) {
+//		ob, err := client.GetOrderbook(symbol)
+//		if err != nil {
+//			return 0, 0, false
+//		}
+//		bid, okBid := ob.BestBid()
+//		ask, okAsk := ob.BestAsk()
+//		return bid.Price, ask.Price, okBid && okAsk
+//	}
+type Quote func(symbol string) (bid, ask float64, ok bool)
+
+// RepriceConfig configures a Repricer.
+type RepriceConfig struct {
+	Quote    Quote
+	Attempts int // resubmissions per order, defaults to DefaultRepriceAttempts
+
+	// OnReprice is called by Handle for every resubmission and for orders
+	// which are given up on.
+	OnReprice func(RepriceEvent)
+}
+
+// RepriceEvent reports the resubmission of a post-only order. Err is set if
+// the order was given up on, either because the attempts are exhausted or
+// the resubmission failed.
+type RepriceEvent struct {
+	Symbol  string
+	PrevCID int64 // client id of the cancelled order
+	CID     int64 // client id of the resubmitted order, 0 if given up
+	Attempt int
+	Price   float64
+	Amount  float64
+	Err     error
+}
+
+type repriced struct {
+	ctx     context.Context
+	req     order.NewRequest
+	attempt int
+}
+
+// Repricer submits post-only orders and resubmits them at the best passive
+// price once they are cancelled for crossing the book, i.e. buys join the
+// best bid and sells the best ask. Order events of the websocket client have
+// to be passed to Handle.
+type Repricer struct {
+	cfg       RepriceConfig
+	submitter OrderSubmitter
+
+	mu     sync.Mutex
+	orders map[int64]*repriced
+}
+
+// NewRepricer returns a repricer using the given quotes.
+func NewRepricer(s OrderSubmitter, cfg RepriceConfig) (*Repricer, error) {
+	if cfg.Quote == nil {
+		return nil, fmt.Errorf("%w: quote is required", common.ErrBadRequest)
+	}
+	if cfg.Attempts <= 0 {
+		cfg.Attempts = DefaultRepriceAttempts
+	}
+	return &Repricer{
+		cfg:       cfg,
+		submitter: s,
+		orders:    make(map[int64]*repriced),
+	}, nil
+}
+
+// Submit submits the order as post-only and tracks it for repricing. A
+// client id is assigned if the order has none. ctx is used for all
+// resubmissions of the order.
+func (r *Repricer) Submit(ctx context.Context, onr *order.NewRequest) error {
+	if onr.Price <= 0 {
+		return fmt.Errorf("%w: post-only orders need a price", common.ErrBadRequest)
+	}
+
+	req := *onr
+	req.PostOnly = true
+	if req.CID == 0 {
+		req.CID = newCID()
+	}
+
+	// tracked before it is submitted, as its events may arrive before the
+	// submitter returns
+	r.track(&repriced{ctx: ctx, req: req})
+	if err := r.submitter.SubmitOrder(ctx, &req); err != nil {
+		r.untrack(req.CID)
+		return err
+	}
+	return nil
+}
+
+// Handle resubmits tracked orders cancelled for crossing the book and stops
+// tracking orders which are closed otherwise. Other events are ignored.
+func (r *Repricer) Handle(ev interface{}) {
+	o, closed := orderEvent(ev)
+	if o == nil || !closed {
+		return
+	}
+	next, event, ok := r.reprice(o)
+	if !ok {
+		return
+	}
+	if next == nil {
+		r.emit(event)
+		return
+	}
+
+	req := next.req
+	if err := r.submitter.SubmitOrder(next.ctx, &req); err != nil {
+		r.untrack(req.CID)
+		event.Err = err
+		r.emit(event)
+		return
+	}
+	event.CID = req.CID
+	r.emit(event)
+}
+
+// reprice stops tracking the closed order o and returns the resubmission of
+// orders cancelled for crossing the book, which is tracked already. It
+// returns no resubmission but an event if o is given up on, and false if o
+// is not repriced.
+func (r *Repricer) reprice(o *order.Order) (*repriced, RepriceEvent, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.orders[o.CID]
+	if !ok {
+		return nil, RepriceEvent{}, false
+	}
+	delete(r.orders, o.CID)
+	if !strings.HasPrefix(o.Status, common.OrderStatusPostOnlyCanceled) || isZero(o.Amount) {
+		return nil, RepriceEvent{}, false
+	}
+
+	event := RepriceEvent{
+		Symbol:  t.req.Symbol,
+		PrevCID: o.CID,
+		Attempt: t.attempt + 1,
+		Amount:  o.Amount,
+	}
+	if t.attempt >= r.cfg.Attempts {
+		event.Err = fmt.Errorf("post-only order %d not placed after %d attempts", o.CID, t.attempt)
+		return nil, event, true
+	}
+
+	bid, ask, ok := r.cfg.Quote(t.req.Symbol)
+	if !ok {
+		event.Err = fmt.Errorf("%w: no quote for %s", common.ErrNotFound, t.req.Symbol)
+		return nil, event, true
+	}
+	price := bid
+	if o.Amount < 0 {
+		price = ask
+	}
+
+	next := &repriced{ctx: t.ctx, req: t.req, attempt: t.attempt + 1}
+	next.req.CID = newCID()
+	next.req.Amount = o.Amount
+	next.req.Price = price
+	event.Price = price
+	r.orders[next.req.CID] = next
+	return next, event, true
+}
+
+// Open returns the number of tracked orders
+func (r *Repricer) Open() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.orders)
+}
+
+func (r *Repricer) track(t *repriced) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[t.req.CID] = t
+}
+
+func (r *Repricer) untrack(cid int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.orders, cid)
+}
+
+func (r *Repricer) emit(ev RepriceEvent) {
+	if r.cfg.OnReprice != nil {
+		r.cfg.OnReprice(ev)
+	}
+}
@@ -0,0 +1,121 @@
+package execution_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/execution"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postOnlyCanceled(onr *order.NewRequest) *order.Cancel {
+	return &order.Cancel{
+		CID:        onr.CID,
+		Symbol:     onr.Symbol,
+		Amount:     onr.Amount,
+		AmountOrig: onr.Amount,
+		Status:     common.OrderStatusPostOnlyCanceled,
+	}
+}
+
+func TestRepricer(t *testing.T) {
+	_, err := execution.NewRepricer(&mockSubmitter{}, execution.RepriceConfig{})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	m := &mockSubmitter{}
+	bid, ask := 99.0, 101.0
+	var events []execution.RepriceEvent
+	r, err := execution.NewRepricer(m, execution.RepriceConfig{
+		Quote:     func(string) (float64, float64, bool) { return bid, ask, true },
+		Attempts:  2,
+		OnReprice: func(ev execution.RepriceEvent) { events = append(events, ev) },
+	})
+	require.Nil(t, err)
+	ctx := context.Background()
+
+	require.Nil(t, r.Submit(ctx, &order.NewRequest{Symbol: "tBTCUSD", Type: common.OrderTypeExchangeLimit, Amount: 1, Price: 102}))
+	orders := m.submitted()
+	require.Len(t, orders, 1)
+	assert.True(t, orders[0].PostOnly)
+	assert.NotZero(t, orders[0].CID)
+
+	// crossing buys join the best bid
+	r.Handle(postOnlyCanceled(orders[0]))
+	orders = m.submitted()
+	require.Len(t, orders, 2)
+	assert.Equal(t, 99.0, orders[1].Price)
+	assert.Equal(t, 1.0, orders[1].Amount)
+	assert.True(t, orders[1].PostOnly)
+	require.Len(t, events, 1)
+	assert.Equal(t, execution.RepriceEvent{Symbol: "tBTCUSD", PrevCID: orders[0].CID, CID: orders[1].CID, Attempt: 1, Price: 99, Amount: 1}, events[0])
+
+	bid = 100
+	r.Handle(postOnlyCanceled(orders[1]))
+	orders = m.submitted()
+	require.Len(t, orders, 3)
+	assert.Equal(t, 100.0, orders[2].Price)
+
+	// the attempts are exhausted
+	r.Handle(postOnlyCanceled(orders[2]))
+	assert.Len(t, m.submitted(), 3)
+	require.Len(t, events, 3)
+	assert.NotNil(t, events[2].Err)
+	assert.Zero(t, events[2].CID)
+	assert.Equal(t, 0, r.Open())
+
+	// sells join the best ask, other cancels are not repriced
+	require.Nil(t, r.Submit(ctx, &order.NewRequest{Symbol: "tBTCUSD", Amount: -1, Price: 98}))
+	orders = m.submitted()
+	r.Handle(postOnlyCanceled(orders[3]))
+	orders = m.submitted()
+	require.Len(t, orders, 5)
+	assert.Equal(t, 101.0, orders[4].Price)
+
+	r.Handle(&order.Cancel{CID: orders[4].CID, Amount: -1, Status: common.OrderStatusCanceled})
+	assert.Len(t, m.submitted(), 5)
+	assert.Equal(t, 0, r.Open())
+}
+
+// handlingSubmitter hands the events of its orders to the repricer before
+// SubmitOrder returns, as a websocket reader may
+type handlingSubmitter struct {
+	mockSubmitter
+	r    *execution.Repricer
+	fail error
+}
+
+func (h *handlingSubmitter) SubmitOrder(ctx context.Context, onr *order.NewRequest) error {
+	if h.fail != nil {
+		return h.fail
+	}
+	first := len(h.submitted()) == 0
+	_ = h.mockSubmitter.SubmitOrder(ctx, onr)
+	if first {
+		h.r.Handle(postOnlyCanceled(onr))
+	}
+	return nil
+}
+
+func TestRepricerEventsDuringSubmit(t *testing.T) {
+	h := &handlingSubmitter{}
+	r, err := execution.NewRepricer(h, execution.RepriceConfig{
+		Quote: func(string) (float64, float64, bool) { return 99, 101, true },
+	})
+	require.Nil(t, err)
+	h.r = r
+
+	require.Nil(t, r.Submit(context.Background(), &order.NewRequest{Symbol: "tBTCUSD", Amount: 1, Price: 102}))
+	orders := h.submitted()
+	require.Len(t, orders, 2)
+	assert.Equal(t, 99.0, orders[1].Price)
+	assert.Equal(t, 1, r.Open())
+
+	// a failed submission is not tracked
+	h.fail = errors.New("not connected")
+	assert.Equal(t, h.fail, r.Submit(context.Background(), &order.NewRequest{Symbol: "tBTCUSD", Amount: 1, Price: 102}))
+	assert.Equal(t, 1, r.Open())
+}
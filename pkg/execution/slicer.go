@@ -0,0 +1,399 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+)
+
+// Strategy determines how a parent order is sliced into child orders.
+type Strategy int
+
+const (
+	// TWAP submits a slice of the parent order at every interval.
+	TWAP Strategy = iota
+	// Iceberg keeps a single visible clip on the book and replenishes it once
+	// it is executed, keeping the rest of the amount hidden.
+	Iceberg
+)
+
+// Config describes the parent order to execute.
+type Config struct {
+	Symbol string
+	Type   string  // defaults to EXCHANGE LIMIT if Price is set, EXCHANGE MARKET otherwise
+	Amount float64 // total signed amount
+	Price  float64
+
+	Slices   int           // number of child orders, TWAP only
+	Interval time.Duration // time between child orders, TWAP only
+
+	Clip float64 // visible amount of each child order, Iceberg only
+}
+
+// Report summarizes the progress of an execution.
+type Report struct {
+	Filled    float64
+	AvgPrice  float64
+	Orders    int
+	Completed bool // the full amount was filled
+	Err       error
+}
+
+type child struct {
+	id      int64
+	created time.Time
+	amount  float64
+	filled  float64
+	price   float64
+	closed  bool
+}
+
+// Slicer executes a parent order as a sequence of child orders. Order events
+// of the websocket client have to be passed to Handle to track fills, e.g.:
+//
+//	s, _ := execution.NewTWAP(client, cfg)
+//	s.Start(ctx)
+//	for ev := range client.Listen() {
+//		s.Handle(ev)
+//	}
+type Slicer struct {
+	strategy  Strategy
+	cfg       Config
+	submitter OrderSubmitter
+
+	mu       sync.Mutex
+	ctx      context.Context
+	children map[int64]*child
+	sent     int
+	paused   bool
+	pending  bool
+	finished bool
+	err      error
+	done     chan struct{}
+}
+
+// NewTWAP returns a slicer which splits the amount into cfg.Slices child
+// orders, submitted every cfg.Interval.
+func NewTWAP(s OrderSubmitter, cfg Config) (*Slicer, error) {
+	if cfg.Slices < 1 {
+		return nil, fmt.Errorf("%w: slices must be positive", common.ErrBadRequest)
+	}
+	if cfg.Interval <= 0 {
+		return nil, fmt.Errorf("%w: interval must be positive", common.ErrBadRequest)
+	}
+	return newSlicer(TWAP, s, cfg)
+}
+
+// NewIceberg returns a slicer which shows cfg.Clip of the amount at a time.
+func NewIceberg(s OrderSubmitter, cfg Config) (*Slicer, error) {
+	if cfg.Clip <= 0 {
+		return nil, fmt.Errorf("%w: clip must be positive", common.ErrBadRequest)
+	}
+	return newSlicer(Iceberg, s, cfg)
+}
+
+func newSlicer(strategy Strategy, s OrderSubmitter, cfg Config) (*Slicer, error) {
+	if cfg.Symbol == "" {
+		return nil, fmt.Errorf("%w: symbol is required", common.ErrBadRequest)
+	}
+	if cfg.Amount == 0 {
+		return nil, fmt.Errorf("%w: amount must not be zero", common.ErrBadRequest)
+	}
+	if cfg.Type == "" {
+		cfg.Type = common.OrderTypeExchangeMarket
+		if cfg.Price > 0 {
+			cfg.Type = common.OrderTypeExchangeLimit
+		}
+	}
+
+	return &Slicer{
+		strategy:  strategy,
+		cfg:       cfg,
+		submitter: s,
+		children:  make(map[int64]*child),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// Start submits the first child order and schedules the following ones.
+// Cancelling ctx stops the execution.
+func (s *Slicer) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.ctx != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("execution already started")
+	}
+	s.ctx = ctx
+	next := s.next()
+	s.mu.Unlock()
+	if err := s.submit(next); err != nil {
+		return err
+	}
+
+	go s.run(ctx)
+	return nil
+}
+
+// run submits the TWAP slices as they are due and finishes the execution
+// once ctx is done
+func (s *Slicer) run(ctx context.Context) {
+	var tick <-chan time.Time
+	if s.strategy == TWAP {
+		ticker := time.NewTicker(s.cfg.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			s.finish(ctx.Err())
+			s.mu.Unlock()
+			return
+		case <-s.done:
+			return
+		case <-tick:
+			s.mu.Lock()
+			var next *childOrder
+			if s.paused {
+				s.pending = true
+			} else if s.sent < s.cfg.Slices {
+				next = s.next()
+			}
+			s.mu.Unlock()
+			_ = s.submit(next)
+		}
+	}
+}
+
+// Handle tracks the fills of the child orders. Events which do not belong to
+// the execution are ignored.
+func (s *Slicer) Handle(ev interface{}) {
+	o, closed := orderEvent(ev)
+	if o == nil {
+		return
+	}
+	_ = s.submit(s.handle(o, closed))
+}
+
+// handle applies the order event and returns the child order to submit next,
+// if any
+func (s *Slicer) handle(o *order.Order, closed bool) *childOrder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.children[o.CID]
+	if !ok || c.closed {
+		return nil
+	}
+
+	c.id = o.ID
+	c.filled = o.AmountOrig - o.Amount
+	c.price = o.PriceAvg
+	if !closed {
+		s.checkDone()
+		return nil
+	}
+
+	c.closed = true
+	var next *childOrder
+	if s.strategy == Iceberg && !s.finished {
+		if strings.HasPrefix(o.Status, common.OrderStatusCanceled) && !isZero(c.amount-c.filled) {
+			// the clip was cancelled externally
+			s.finish(nil)
+			return nil
+		}
+		if s.paused {
+			s.pending = true
+		} else {
+			next = s.next()
+		}
+	}
+	s.checkDone()
+	return next
+}
+
+// Pause stops submitting new child orders, open orders are left untouched.
+func (s *Slicer) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused = true
+}
+
+// Resume continues the execution, submitting a child order if one was due
+// while paused.
+func (s *Slicer) Resume() error {
+	s.mu.Lock()
+	s.paused = false
+	if !s.pending || s.finished {
+		s.mu.Unlock()
+		return nil
+	}
+	s.pending = false
+	next := s.next()
+	s.mu.Unlock()
+	return s.submit(next)
+}
+
+// Stop cancels all open child orders and ends the execution.
+func (s *Slicer) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	var cancels []*order.CancelRequest
+	for cid, c := range s.children {
+		if !c.closed {
+			cancels = append(cancels, cancelRequest(cid, c))
+		}
+	}
+	s.mu.Unlock()
+
+	var err error
+	for _, ocr := range cancels {
+		if cerr := s.submitter.SubmitCancel(ctx, ocr); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finish(err)
+	return err
+}
+
+// Done is closed once the execution has finished.
+func (s *Slicer) Done() <-chan struct{} {
+	return s.done
+}
+
+// Report returns the current progress of the execution.
+func (s *Slicer) Report() Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var filled, notional float64
+	for _, c := range s.children {
+		filled += c.filled
+		notional += math.Abs(c.filled) * c.price
+	}
+
+	r := Report{
+		Filled:    filled,
+		Orders:    len(s.children),
+		Completed: isZero(s.cfg.Amount - filled),
+		Err:       s.err,
+	}
+	if !isZero(filled) {
+		r.AvgPrice = notional / math.Abs(filled)
+	}
+	return r
+}
+
+// childOrder is a child order recorded by the slicer before it is submitted
+type childOrder struct {
+	ctx context.Context
+	req *order.NewRequest
+}
+
+// next records the next child order and returns it to be submitted, must be
+// called with the lock held
+func (s *Slicer) next() *childOrder {
+	if s.finished {
+		return nil
+	}
+
+	var committed float64
+	for _, c := range s.children {
+		if c.closed {
+			committed += c.filled
+		} else {
+			committed += c.amount
+		}
+	}
+	remaining := s.cfg.Amount - committed
+
+	var amount float64
+	switch s.strategy {
+	case TWAP:
+		amount = remaining / float64(s.cfg.Slices-s.sent)
+	case Iceberg:
+		amount = math.Copysign(math.Min(s.cfg.Clip, math.Abs(remaining)), remaining)
+	}
+	s.sent++
+
+	if isZero(amount) {
+		s.checkDone()
+		return nil
+	}
+
+	// tracked before it is submitted, as its events may arrive before the
+	// submitter returns
+	cid := newCID()
+	s.children[cid] = &child{created: time.Now(), amount: amount}
+	return &childOrder{ctx: s.ctx, req: &order.NewRequest{
+		CID:    cid,
+		Type:   s.cfg.Type,
+		Symbol: s.cfg.Symbol,
+		Amount: amount,
+		Price:  s.cfg.Price,
+	}}
+}
+
+// submit sends the child order without holding the lock and removes it if
+// that fails, which ends the execution
+func (s *Slicer) submit(co *childOrder) error {
+	if co == nil {
+		return nil
+	}
+	err := s.submitter.SubmitOrder(co.ctx, co.req)
+	if err == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.children, co.req.CID)
+	s.sent--
+	s.finish(err)
+	return err
+}
+
+// checkDone finishes the execution once the amount is filled or no further
+// orders are expected, must be called with the lock held
+func (s *Slicer) checkDone() {
+	var filled float64
+	open := false
+	for _, c := range s.children {
+		filled += c.filled
+		open = open || !c.closed
+	}
+
+	if isZero(s.cfg.Amount - filled) {
+		s.finish(nil)
+		return
+	}
+	if s.strategy == TWAP && s.sent >= s.cfg.Slices && !open {
+		s.finish(nil)
+	}
+}
+
+func (s *Slicer) finish(err error) {
+	if s.finished {
+		return
+	}
+	s.finished = true
+	s.err = err
+	close(s.done)
+}
+
+func cancelRequest(cid int64, c *child) *order.CancelRequest {
+	if c.id != 0 {
+		return &order.CancelRequest{ID: c.id}
+	}
+	return &order.CancelRequest{CID: cid, CIDDate: c.created.UTC().Format("2006-01-02")}
+}
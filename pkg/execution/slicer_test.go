@@ -0,0 +1,204 @@
+package execution_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/execution"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSubmitter struct {
+	mu      sync.Mutex
+	orders  []*order.NewRequest
+	cancels []*order.CancelRequest
+}
+
+func (m *mockSubmitter) SubmitOrder(ctx context.Context, onr *order.NewRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.orders = append(m.orders, onr)
+	return nil
+}
+
+func (m *mockSubmitter) SubmitCancel(ctx context.Context, ocr *order.CancelRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cancels = append(m.cancels, ocr)
+	return nil
+}
+
+func (m *mockSubmitter) submitted() []*order.NewRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*order.NewRequest(nil), m.orders...)
+}
+
+func executed(onr *order.NewRequest, id int64, price float64) *order.Cancel {
+	return &order.Cancel{
+		ID:         id,
+		CID:        onr.CID,
+		Symbol:     onr.Symbol,
+		AmountOrig: onr.Amount,
+		Status:     "EXECUTED @ 100.0",
+		PriceAvg:   price,
+	}
+}
+
+func TestTWAP(t *testing.T) {
+	m := &mockSubmitter{}
+	s, err := execution.NewTWAP(m, execution.Config{
+		Symbol:   "tBTCUSD",
+		Amount:   -0.3,
+		Slices:   3,
+		Interval: 10 * time.Millisecond,
+	})
+	require.Nil(t, err)
+	require.Nil(t, s.Start(context.Background()))
+
+	require.Eventually(t, func() bool { return len(m.submitted()) == 3 }, time.Second, time.Millisecond)
+	orders := m.submitted()
+	for i, o := range orders {
+		assert.Equal(t, common.OrderTypeExchangeMarket, o.Type)
+		assert.InDelta(t, -0.1, o.Amount, 1e-9)
+		s.Handle(executed(o, int64(i+1), 100+float64(i)))
+	}
+
+	select {
+	case <-s.Done():
+	case <-time.After(time.Second):
+		t.Fatal("execution did not complete")
+	}
+
+	r := s.Report()
+	assert.True(t, r.Completed)
+	assert.InDelta(t, -0.3, r.Filled, 1e-9)
+	assert.InDelta(t, 101, r.AvgPrice, 1e-9)
+	assert.Equal(t, 3, r.Orders)
+}
+
+func TestIceberg(t *testing.T) {
+	m := &mockSubmitter{}
+	s, err := execution.NewIceberg(m, execution.Config{
+		Symbol: "tBTCUSD",
+		Amount: 1,
+		Price:  9000,
+		Clip:   0.4,
+	})
+	require.Nil(t, err)
+	require.Nil(t, s.Start(context.Background()))
+
+	orders := m.submitted()
+	require.Len(t, orders, 1)
+	assert.Equal(t, common.OrderTypeExchangeLimit, orders[0].Type)
+	assert.Equal(t, 0.4, orders[0].Amount)
+
+	// partial fill does not replenish
+	s.Handle(&order.Update{ID: 1, CID: orders[0].CID, AmountOrig: 0.4, Amount: 0.1, PriceAvg: 9000})
+	assert.Len(t, m.submitted(), 1)
+
+	s.Pause()
+	s.Handle(executed(orders[0], 1, 9000))
+	assert.Len(t, m.submitted(), 1)
+
+	require.Nil(t, s.Resume())
+	orders = m.submitted()
+	require.Len(t, orders, 2)
+	assert.InDelta(t, 0.4, orders[1].Amount, 1e-9)
+
+	s.Handle(executed(orders[1], 2, 9000))
+	orders = m.submitted()
+	require.Len(t, orders, 3)
+	assert.InDelta(t, 0.2, orders[2].Amount, 1e-9)
+
+	s.Handle(executed(orders[2], 3, 9000))
+	<-s.Done()
+	assert.True(t, s.Report().Completed)
+}
+
+// executingSubmitter executes every child order before SubmitOrder returns,
+// as a websocket reader may deliver its events
+type executingSubmitter struct {
+	mockSubmitter
+	s    *execution.Slicer
+	fail error // returned from the third order on
+}
+
+func (e *executingSubmitter) SubmitOrder(ctx context.Context, onr *order.NewRequest) error {
+	if e.fail != nil && len(e.submitted()) == 2 {
+		return e.fail
+	}
+	_ = e.mockSubmitter.SubmitOrder(ctx, onr)
+	e.s.Handle(executed(onr, int64(len(e.submitted())), 9000))
+	return nil
+}
+
+func TestIcebergEventsDuringSubmit(t *testing.T) {
+	cfg := execution.Config{Symbol: "tBTCUSD", Amount: 1, Price: 9000, Clip: 0.4}
+
+	e := &executingSubmitter{}
+	s, err := execution.NewIceberg(e, cfg)
+	require.Nil(t, err)
+	e.s = s
+	require.Nil(t, s.Start(context.Background()))
+	<-s.Done()
+	assert.Len(t, e.submitted(), 3)
+	assert.True(t, s.Report().Completed)
+
+	// a failed clip ends the execution and is not reported
+	e = &executingSubmitter{fail: errors.New("not connected")}
+	s, err = execution.NewIceberg(e, cfg)
+	require.Nil(t, err)
+	e.s = s
+	require.Nil(t, s.Start(context.Background()))
+	<-s.Done()
+	r := s.Report()
+	assert.Equal(t, e.fail, r.Err)
+	assert.Equal(t, 2, r.Orders)
+	assert.InDelta(t, 0.8, r.Filled, 1e-9)
+}
+
+func TestIcebergContext(t *testing.T) {
+	m := &mockSubmitter{}
+	s, err := execution.NewIceberg(m, execution.Config{Symbol: "tBTCUSD", Amount: 1, Clip: 0.4})
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.Nil(t, s.Start(ctx))
+	cancel()
+	select {
+	case <-s.Done():
+	case <-time.After(time.Second):
+		t.Fatal("iceberg not finished after its context was cancelled")
+	}
+	assert.Equal(t, context.Canceled, s.Report().Err)
+}
+
+func TestSlicerStop(t *testing.T) {
+	m := &mockSubmitter{}
+	s, err := execution.NewIceberg(m, execution.Config{Symbol: "tBTCUSD", Amount: 1, Price: 9000, Clip: 0.5})
+	require.Nil(t, err)
+	require.Nil(t, s.Start(context.Background()))
+
+	s.Handle(&order.New{ID: 77, CID: m.submitted()[0].CID, AmountOrig: 0.5, Amount: 0.5})
+	require.Nil(t, s.Stop(context.Background()))
+	<-s.Done()
+
+	require.Len(t, m.cancels, 1)
+	assert.Equal(t, int64(77), m.cancels[0].ID)
+	assert.False(t, s.Report().Completed)
+}
+
+func TestSlicerValidation(t *testing.T) {
+	_, err := execution.NewTWAP(&mockSubmitter{}, execution.Config{Symbol: "tBTCUSD", Amount: 1, Slices: 2})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	_, err = execution.NewIceberg(&mockSubmitter{}, execution.Config{Symbol: "tBTCUSD", Amount: 1})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
@@ -0,0 +1,318 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecution"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecutionupdate"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/store"
+)
+
+// Fills aggregates the trades of an order.
+type Fills struct {
+	OrderID  int64
+	Symbol   string
+	Trades   int
+	Filled   float64 // signed cumulative amount, negative for sells
+	AvgPrice float64 // volume weighted execution price
+	// Fees by fee currency, negative as reported by the exchange
+	Fees map[string]float64
+}
+
+type fills struct {
+	Fills
+	value  float64
+	trades map[int64]bool // trade id to whether its fee was added
+}
+
+type tracked struct {
+	order  *order.Order
+	closed bool
+	// prevStatus is the status of the order before its last event
+	prevStatus string
+}
+
+// OrderTracker keeps the latest state and the fills of the orders of the
+// account. Trades are reported twice by the websocket client, first as te
+// without and then as tu with the fee, and are counted once. Events have to
+// be passed to Handle, e.g.:
+//
+//	for ev := range client.Listen() {
+//		tracker.Handle(ev)
+//	}
+type OrderTracker struct {
+	mu       sync.Mutex
+	orders   map[int64]*tracked
+	fills    map[int64]*fills
+	onReport func(ExecutionReport)
+	// order updates are discarded until the next snapshot, see Resync
+	stale bool
+
+	store store.Store
+	dirty map[int64]bool // orders changed since the last save
+	err   error
+}
+
+// NewOrderTracker returns an empty tracker
+func NewOrderTracker() *OrderTracker {
+	return &OrderTracker{
+		orders: make(map[int64]*tracked),
+		fills:  make(map[int64]*fills),
+		dirty:  make(map[int64]bool),
+	}
+}
+
+// NewOrderTrackerWithStore returns a tracker which saves every change of an
+// order to s and is restored from the orders saved before, so that it
+// survives restarts.
+func NewOrderTrackerWithStore(s store.Store) (*OrderTracker, error) {
+	t := NewOrderTracker()
+	t.store = s
+	err := s.Scan(orderKeyPrefix, func(key string, value []byte) error {
+		var r orderRecord
+		if err := json.Unmarshal(value, &r); err != nil {
+			return fmt.Errorf("decoding %s: %w", key, err)
+		}
+		if r.Order != nil {
+			t.orders[r.Order.ID] = &tracked{order: r.Order, closed: r.Closed}
+		}
+		if r.Fills != nil {
+			t.fills[r.Fills.OrderID] = &fills{Fills: r.Fills.Fills, value: r.Fills.Value, trades: r.Fills.Trades}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Handle updates the tracker with order and trade events, other events are
+// ignored.
+func (t *OrderTracker) Handle(ev interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	defer t.save()
+
+	switch e := ev.(type) {
+	case *order.Snapshot:
+		t.stale = false
+		for id, o := range t.orders {
+			if !o.closed {
+				delete(t.orders, id)
+				t.dirty[id] = true
+			}
+		}
+		for _, o := range e.Snapshot {
+			t.orders[o.ID] = &tracked{order: o}
+			t.dirty[o.ID] = true
+		}
+	case *tradeexecution.TradeExecution:
+		t.add(e.ID, e.OrderID, e.Pair, e.ExecAmount, e.ExecPrice)
+	case *tradeexecutionupdate.TradeExecutionUpdate:
+		t.addUpdate(e)
+	case *tradeexecutionupdate.HistoricalTradeSnapshot:
+		for _, tu := range e.Snapshot {
+			t.addUpdate(tu)
+		}
+	case *notification.Notification:
+		if t.onReport != nil {
+			t.reportRejection(e)
+		}
+	default:
+		o, closed := orderEvent(ev)
+		if o == nil || t.stale {
+			return
+		}
+		prev := t.orders[o.ID]
+		next := &tracked{order: o, closed: closed}
+		if prev != nil {
+			next.prevStatus = prev.order.Status
+		}
+		t.orders[o.ID] = next
+		t.dirty[o.ID] = true
+		if t.onReport != nil {
+			t.reportOrder(prev, o, closed)
+		}
+	}
+}
+
+// Resync marks the orders as stale once the websocket client authenticated
+// again, e.g. after a reconnect. Order updates are discarded until the next
+// order snapshot, which replaces the open orders, as updates received in
+// between may predate it. Trades are still counted. E.g.:
+//
+//	for ev := range client.Listen() {
+//		if a, ok := ev.(*websocket.AuthSucceededEvent); ok && a.Generation > 1 {
+//			tracker.Resync()
+//		}
+//		tracker.Handle(ev)
+//	}
+func (t *OrderTracker) Resync() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stale = true
+}
+
+// Stale reports whether the tracker awaits an order snapshot after Resync
+func (t *OrderTracker) Stale() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stale
+}
+
+// Order returns the latest state of the order with the given id
+func (t *OrderTracker) Order(id int64) (*order.Order, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	o, ok := t.orders[id]
+	if !ok {
+		return nil, false
+	}
+	return o.order, true
+}
+
+// Open returns the open orders sorted by id
+func (t *OrderTracker) Open() []*order.Order {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var open []*order.Order
+	for _, o := range t.orders {
+		if !o.closed {
+			open = append(open, o.order)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool { return open[i].ID < open[j].ID })
+	return open
+}
+
+// Fills returns the aggregated fills of the order with the given id, false
+// if none were received.
+func (t *OrderTracker) Fills(id int64) (Fills, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f, ok := t.fills[id]
+	if !ok {
+		return Fills{}, false
+	}
+	out := f.Fills
+	out.Fees = make(map[string]float64, len(f.Fees))
+	for ccy, fee := range f.Fees {
+		out.Fees[ccy] = fee
+	}
+	return out, true
+}
+
+// Forget drops the order with the given id and its fills, e.g. once a
+// closed order has been processed.
+func (t *OrderTracker) Forget(id int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.orders, id)
+	delete(t.fills, id)
+	t.dirty[id] = true
+	t.save()
+}
+
+// Err returns the first error saving to the store of the tracker, if any
+func (t *OrderTracker) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *OrderTracker) addUpdate(tu *tradeexecutionupdate.TradeExecutionUpdate) {
+	f := t.add(tu.ID, tu.OrderID, tu.Pair, tu.ExecAmount, tu.ExecPrice)
+	if f.trades[tu.ID] || tu.FeeCurrency == "" {
+		return
+	}
+	f.trades[tu.ID] = true
+	t.dirty[tu.OrderID] = true
+	f.Fees[tu.FeeCurrency] += tu.Fee
+	if t.onReport != nil {
+		t.reportTrade(tu, f)
+	}
+}
+
+// add counts the trade unless it was seen before and returns the fills of
+// its order
+func (t *OrderTracker) add(tradeID, orderID int64, symbol string, amount, price float64) *fills {
+	f, ok := t.fills[orderID]
+	if !ok {
+		f = &fills{
+			Fills:  Fills{OrderID: orderID, Symbol: symbol, Fees: make(map[string]float64)},
+			trades: make(map[int64]bool),
+		}
+		t.fills[orderID] = f
+	}
+	if _, seen := f.trades[tradeID]; seen {
+		return f
+	}
+
+	f.trades[tradeID] = false
+	t.dirty[orderID] = true
+	f.Trades++
+	f.Filled += amount
+	f.value += math.Abs(amount) * price
+	if filled := math.Abs(f.Filled); filled > 0 {
+		f.AvgPrice = f.value / filled
+	}
+	return f
+}
+
+// orderKeyPrefix prefixes the store keys of the orders of a tracker
+const orderKeyPrefix = "order/"
+
+// orderRecord is the saved state of an order and its fills
+type orderRecord struct {
+	Order  *order.Order `json:"order,omitempty"`
+	Closed bool         `json:"closed,omitempty"`
+	Fills  *fillsRecord `json:"fills,omitempty"`
+}
+
+type fillsRecord struct {
+	Fills
+	Value  float64        `json:"value"`
+	Trades map[int64]bool `json:"trades"`
+}
+
+// save writes the changed orders to the store, removing forgotten ones
+func (t *OrderTracker) save() {
+	if t.store == nil {
+		t.dirty = make(map[int64]bool)
+		return
+	}
+	for id := range t.dirty {
+		delete(t.dirty, id)
+		key := orderKeyPrefix + strconv.FormatInt(id, 10)
+
+		var r orderRecord
+		if o, ok := t.orders[id]; ok {
+			r.Order, r.Closed = o.order, o.closed
+		}
+		if f, ok := t.fills[id]; ok {
+			r.Fills = &fillsRecord{Fills: f.Fills, Value: f.value, Trades: f.trades}
+		}
+
+		var err error
+		if r.Order == nil && r.Fills == nil {
+			err = t.store.Delete(key)
+		} else {
+			err = store.PutJSON(t.store, key, r)
+		}
+		if err != nil && t.err == nil {
+			t.err = err
+		}
+	}
+}
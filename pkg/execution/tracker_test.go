@@ -0,0 +1,149 @@
+package execution_test
+
+import (
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/execution"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecution"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecutionupdate"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderTracker(t *testing.T) {
+	tr := execution.NewOrderTracker()
+
+	tr.Handle(&order.Snapshot{Snapshot: []*order.Order{{ID: 1, Amount: -2, AmountOrig: -2}}})
+	tr.Handle(&order.New{ID: 2, Amount: 1, AmountOrig: 1})
+	require.Len(t, tr.Open(), 2)
+
+	_, ok := tr.Fills(1)
+	assert.False(t, ok)
+
+	// te and tu of the same trade count once, the fee is taken from tu
+	tr.Handle(&tradeexecution.TradeExecution{ID: 10, Pair: "tBTCUSD", OrderID: 1, ExecAmount: -1, ExecPrice: 100})
+	tr.Handle(&tradeexecutionupdate.TradeExecutionUpdate{ID: 10, Pair: "tBTCUSD", OrderID: 1, ExecAmount: -1, ExecPrice: 100, Fee: -0.2, FeeCurrency: "USD"})
+	tr.Handle(&tradeexecutionupdate.TradeExecutionUpdate{ID: 10, Pair: "tBTCUSD", OrderID: 1, ExecAmount: -1, ExecPrice: 100, Fee: -0.2, FeeCurrency: "USD"})
+	tr.Handle(&tradeexecutionupdate.TradeExecutionUpdate{ID: 11, Pair: "tBTCUSD", OrderID: 1, ExecAmount: -1, ExecPrice: 103, Fee: -0.0001, FeeCurrency: "BTC"})
+	tr.Handle(&order.Cancel{ID: 1, Status: "EXECUTED @ 101.5(-2.0)"})
+
+	f, ok := tr.Fills(1)
+	require.True(t, ok)
+	assert.Equal(t, execution.Fills{
+		OrderID:  1,
+		Symbol:   "tBTCUSD",
+		Trades:   2,
+		Filled:   -2,
+		AvgPrice: 101.5,
+		Fees:     map[string]float64{"USD": -0.2, "BTC": -0.0001},
+	}, f)
+
+	open := tr.Open()
+	require.Len(t, open, 1)
+	assert.Equal(t, int64(2), open[0].ID)
+	o, ok := tr.Order(1)
+	require.True(t, ok)
+	assert.Equal(t, "EXECUTED @ 101.5(-2.0)", o.Status)
+
+	tr.Forget(1)
+	_, ok = tr.Order(1)
+	assert.False(t, ok)
+	_, ok = tr.Fills(1)
+	assert.False(t, ok)
+}
+
+func TestOrderTrackerResync(t *testing.T) {
+	tr := execution.NewOrderTracker()
+	tr.Handle(&order.Snapshot{Snapshot: []*order.Order{{ID: 1, Amount: 1, AmountOrig: 1}}})
+
+	// updates received before the snapshot following a reconnect are stale
+	tr.Resync()
+	assert.True(t, tr.Stale())
+	tr.Handle(&order.Update{ID: 1, Amount: 0.5, AmountOrig: 1})
+	tr.Handle(&order.New{ID: 2, Amount: 1, AmountOrig: 1})
+	tr.Handle(&tradeexecution.TradeExecution{ID: 10, Pair: "tBTCUSD", OrderID: 1, ExecAmount: 0.5, ExecPrice: 100})
+	o, ok := tr.Order(1)
+	require.True(t, ok)
+	assert.Equal(t, 1.0, o.Amount)
+	require.Len(t, tr.Open(), 1)
+	_, ok = tr.Fills(1)
+	assert.True(t, ok)
+
+	tr.Handle(&order.Snapshot{Snapshot: []*order.Order{{ID: 1, Amount: 0.5, AmountOrig: 1}}})
+	assert.False(t, tr.Stale())
+	tr.Handle(&order.New{ID: 2, Amount: 1, AmountOrig: 1})
+	require.Len(t, tr.Open(), 2)
+}
+
+func TestOrderTrackerReports(t *testing.T) {
+	tr := execution.NewOrderTracker()
+	var reports []execution.ExecutionReport
+	tr.OnReport(func(r execution.ExecutionReport) { reports = append(reports, r) })
+
+	tr.Handle(&order.New{ID: 1, CID: 5, Symbol: "tBTCUSD", Amount: 2, AmountOrig: 2, Price: 100, Status: "ACTIVE", MTSUpdated: 1})
+	tr.Handle(&order.Update{ID: 1, CID: 5, Symbol: "tBTCUSD", Amount: 2, AmountOrig: 2, Price: 101, Status: "ACTIVE", MTSUpdated: 2})
+	// the tu of the fill arrives before its ou
+	tr.Handle(&tradeexecution.TradeExecution{ID: 10, Pair: "tBTCUSD", OrderID: 1, ExecAmount: 0.5, ExecPrice: 101})
+	tr.Handle(&tradeexecutionupdate.TradeExecutionUpdate{ID: 10, Pair: "tBTCUSD", MTS: 3, OrderID: 1, ExecAmount: 0.5, ExecPrice: 101, Fee: -0.001, FeeCurrency: "BTC"})
+	tr.Handle(&order.Update{ID: 1, CID: 5, Symbol: "tBTCUSD", Amount: 1.5, AmountOrig: 2, Price: 101, Status: "PARTIALLY FILLED @ 101.0(0.5)", PriceAvg: 101, MTSUpdated: 3})
+	// the ou of the fill arrives before its tu
+	tr.Handle(&tradeexecution.TradeExecution{ID: 11, Pair: "tBTCUSD", OrderID: 1, ExecAmount: 0.5, ExecPrice: 101})
+	tr.Handle(&order.Update{ID: 1, CID: 5, Symbol: "tBTCUSD", Amount: 1, AmountOrig: 2, Price: 101, Status: "PARTIALLY FILLED @ 101.0(1.0)", PriceAvg: 101, MTSUpdated: 4})
+	tr.Handle(&tradeexecutionupdate.TradeExecutionUpdate{ID: 11, Pair: "tBTCUSD", MTS: 4, OrderID: 1, ExecAmount: 0.5, ExecPrice: 101, Fee: -0.001, FeeCurrency: "BTC"})
+	tr.Handle(&order.Cancel{ID: 1, CID: 5, Symbol: "tBTCUSD", Amount: 1, AmountOrig: 2, Price: 101, Status: "CANCELED was: PARTIALLY FILLED @ 101.0(1.0)", PriceAvg: 101, MTSUpdated: 5})
+	tr.Handle(&notification.Notification{MTS: 6, Type: "on-req", NotifyInfo: order.New{CID: 6, Symbol: "tBTCUSD"}, Status: "ERROR", Text: "Invalid order: minimum size"})
+
+	require.Len(t, reports, 6)
+	assert.Equal(t, execution.ExecutionReport{
+		ExecType: execution.ExecNew, OrderID: 1, CID: 5, Symbol: "tBTCUSD",
+		Status: "ACTIVE", LeavesQty: 2, MTS: 1,
+	}, reports[0])
+	assert.Equal(t, execution.ExecReplaced, reports[1].ExecType)
+	assert.Equal(t, "ACTIVE", reports[1].PrevStatus)
+	assert.Equal(t, execution.ExecutionReport{
+		ExecType: execution.ExecTrade, OrderID: 1, CID: 5, Symbol: "tBTCUSD",
+		PrevStatus: "ACTIVE", Status: "PARTIALLY FILLED", LastQty: 0.5, LastPrice: 101,
+		CumQty: 0.5, LeavesQty: 1.5, AvgPrice: 101, Fee: -0.001, FeeCurrency: "BTC", MTS: 3,
+	}, reports[2])
+	assert.Equal(t, execution.ExecTrade, reports[3].ExecType)
+	assert.Equal(t, "PARTIALLY FILLED @ 101.0(0.5)", reports[3].PrevStatus)
+	assert.Equal(t, "PARTIALLY FILLED @ 101.0(1.0)", reports[3].Status)
+	assert.Equal(t, 1.0, reports[3].CumQty)
+	assert.Equal(t, execution.ExecCanceled, reports[4].ExecType)
+	assert.Equal(t, 0.0, reports[4].LeavesQty)
+	assert.Equal(t, execution.ExecutionReport{
+		ExecType: execution.ExecRejected, CID: 6, Symbol: "tBTCUSD",
+		Status: "ERROR", Text: "Invalid order: minimum size", MTS: 6,
+	}, reports[5])
+}
+
+func TestOrderTrackerStore(t *testing.T) {
+	s := store.NewMemory()
+	tr, err := execution.NewOrderTrackerWithStore(s)
+	require.Nil(t, err)
+
+	tr.Handle(&order.New{ID: 1, Symbol: "tBTCUSD", Amount: 2, AmountOrig: 2, Status: "ACTIVE"})
+	tr.Handle(&order.New{ID: 2, Symbol: "tBTCUSD", Amount: 1, AmountOrig: 1, Status: "ACTIVE"})
+	tr.Handle(&tradeexecutionupdate.TradeExecutionUpdate{ID: 10, Pair: "tBTCUSD", OrderID: 1, ExecAmount: 0.5, ExecPrice: 100, Fee: -0.001, FeeCurrency: "BTC"})
+	tr.Forget(2)
+	require.Nil(t, tr.Err())
+
+	// a restarted tracker continues with the saved state
+	restored, err := execution.NewOrderTrackerWithStore(s)
+	require.Nil(t, err)
+	open := restored.Open()
+	require.Len(t, open, 1)
+	assert.Equal(t, int64(1), open[0].ID)
+
+	restored.Handle(&tradeexecutionupdate.TradeExecutionUpdate{ID: 10, Pair: "tBTCUSD", OrderID: 1, ExecAmount: 0.5, ExecPrice: 100, Fee: -0.001, FeeCurrency: "BTC"})
+	restored.Handle(&tradeexecutionupdate.TradeExecutionUpdate{ID: 11, Pair: "tBTCUSD", OrderID: 1, ExecAmount: 0.5, ExecPrice: 110, Fee: -0.001, FeeCurrency: "BTC"})
+	f, ok := restored.Fills(1)
+	require.True(t, ok)
+	assert.Equal(t, 2, f.Trades)
+	assert.Equal(t, 1.0, f.Filled)
+	assert.Equal(t, 105.0, f.AvgPrice)
+	assert.InDelta(t, -0.002, f.Fees["BTC"], 1e-12)
+}
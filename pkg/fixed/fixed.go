@@ -0,0 +1,271 @@
+// Package fixed represents prices and amounts as int64 values scaled by a
+// power of ten, for users that can't tolerate float math in their hot path.
+// Values are parsed from the exact decimal representation of the API
+// responses where available (see json.Number), so no precision is lost.
+package fixed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// Scale is the number of decimal places of a fixed-point value
+type Scale uint8
+
+// MaxScale is the largest supported scale, larger scales leave too little
+// room for the integer part of an int64
+const MaxScale Scale = 18
+
+const (
+	// DefaultAmountScale is the precision of amounts of all currencies
+	DefaultAmountScale Scale = 8
+	// PriceSignificantDigits is the number of significant digits of prices
+	// accepted by the exchange
+	PriceSignificantDigits = 5
+)
+
+// ErrOverflow is returned if a value does not fit into an int64 at the
+// requested scale
+var ErrOverflow = errors.New("fixed-point overflow")
+
+var pow10 [MaxScale + 1]int64
+
+func init() {
+	pow10[0] = 1
+	for i := 1; i < len(pow10); i++ {
+		pow10[i] = pow10[i-1] * 10
+	}
+}
+
+// Unit returns the scaled representation of 1, i.e. 10^s
+func (s Scale) Unit() int64 {
+	return pow10[s]
+}
+
+// Parse converts a decimal string like "-123.456" or "1e-5" exactly. Digits
+// beyond the scale are rejected rather than rounded.
+func (s Scale) Parse(str string) (int64, error) {
+	if s > MaxScale {
+		return 0, fmt.Errorf("%w: scale %d exceeds %d", common.ErrBadRequest, s, MaxScale)
+	}
+	if strings.ContainsAny(str, "eE") {
+		// exponent notation is rare, fall back to a float with enough digits
+		f, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return 0, err
+		}
+		return s.parseDigits(strconv.FormatFloat(f, 'f', -1, 64), str)
+	}
+	return s.parseDigits(str, str)
+}
+
+func (s Scale) parseDigits(str, orig string) (int64, error) {
+	neg := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(strings.TrimPrefix(str, "-"), "+")
+	intPart, frac := str, ""
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		intPart, frac = str[:i], str[i+1:]
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > int(s) {
+		return 0, fmt.Errorf("%s has more than %d decimals", orig, s)
+	}
+	if intPart == "" && frac == "" {
+		return 0, fmt.Errorf("invalid number %q", orig)
+	}
+	frac += strings.Repeat("0", int(s)-len(frac))
+
+	digits := strings.TrimLeft(intPart+frac, "0")
+	if digits == "" {
+		return 0, nil
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("invalid number %q", orig)
+		}
+	}
+	u, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil || u > math.MaxInt64 {
+		return 0, fmt.Errorf("%w: %s at scale %d", ErrOverflow, orig, s)
+	}
+	if neg {
+		return -int64(u), nil
+	}
+	return int64(u), nil
+}
+
+// ParseNumber converts a number decoded as json.Number exactly
+func (s Scale) ParseNumber(n json.Number) (int64, error) {
+	return s.Parse(n.String())
+}
+
+// FromFloat converts f, rounding half away from zero
+func (s Scale) FromFloat(f float64) (int64, error) {
+	v := math.Round(f * float64(pow10[s]))
+	if math.IsNaN(v) || v >= math.MaxInt64 || v <= math.MinInt64 {
+		return 0, fmt.Errorf("%w: %v at scale %d", ErrOverflow, f, s)
+	}
+	return int64(v), nil
+}
+
+// Float converts v back to a float, e.g. for display purposes
+func (s Scale) Float(v int64) float64 {
+	return float64(v) / float64(pow10[s])
+}
+
+// Format renders v with exactly s decimals, as accepted by the API
+func (s Scale) Format(v int64) string {
+	u := uint64(v)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		u = uint64(-v)
+	}
+	str := strconv.FormatUint(u, 10)
+	if s == 0 {
+		return sign + str
+	}
+	if len(str) <= int(s) {
+		str = strings.Repeat("0", int(s)-len(str)+1) + str
+	}
+	i := len(str) - int(s)
+	return sign + str[:i] + "." + str[i:]
+}
+
+// Rescale converts v from scale s to scale to, rounding half away from zero
+// when decimals are dropped
+func (s Scale) Rescale(v int64, to Scale) (int64, error) {
+	switch {
+	case to == s:
+		return v, nil
+	case to > s:
+		m := pow10[to-s]
+		r := v * m
+		if r/m != v {
+			return 0, fmt.Errorf("%w: rescaling %d from %d to %d", ErrOverflow, v, s, to)
+		}
+		return r, nil
+	default:
+		d := pow10[s-to]
+		q, rem := v/d, v%d
+		if rem*2 >= d {
+			q++
+		} else if rem*2 <= -d {
+			q--
+		}
+		return q, nil
+	}
+}
+
+// Mul multiplies a at scale s with b at scale bs, e.g. a price with an amount
+// to get a notional, and returns the product at scale s. The intermediate
+// product uses 128 bits, so only the result has to fit into an int64.
+func (s Scale) Mul(a int64, b int64, bs Scale) (int64, error) {
+	neg := (a < 0) != (b < 0)
+	ua, ub := abs(a), abs(b)
+	hi, lo := bits.Mul64(ua, ub)
+	d := uint64(pow10[bs])
+	if hi >= d {
+		return 0, fmt.Errorf("%w: %d * %d", ErrOverflow, a, b)
+	}
+	q, rem := bits.Div64(hi, lo, d)
+	if rem*2 >= d {
+		q++
+	}
+	if q > math.MaxInt64 {
+		return 0, fmt.Errorf("%w: %d * %d", ErrOverflow, a, b)
+	}
+	if neg {
+		return -int64(q), nil
+	}
+	return int64(q), nil
+}
+
+func abs(v int64) uint64 {
+	if v < 0 {
+		return uint64(-v)
+	}
+	return uint64(v)
+}
+
+// PriceScale returns the scale needed to represent prices around ref with
+// PriceSignificantDigits significant digits
+func PriceScale(ref float64) Scale {
+	ref = math.Abs(ref)
+	if ref == 0 {
+		return DefaultAmountScale
+	}
+	intDigits := int(math.Floor(math.Log10(ref))) + 1
+	s := PriceSignificantDigits - intDigits
+	if s < 0 {
+		return 0
+	}
+	if s > int(MaxScale) {
+		return MaxScale
+	}
+	return Scale(s)
+}
+
+// RoundPrice rounds price to the given number of significant digits, e.g.
+// PriceSignificantDigits, rounding half away from zero
+func RoundPrice(price float64, digits int) float64 {
+	if price == 0 || digits <= 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		return price
+	}
+	intDigits := int(math.Floor(math.Log10(math.Abs(price)))) + 1
+	return round(price, digits-intDigits)
+}
+
+// RoundAmount rounds amount to s decimals, rounding half away from zero
+func RoundAmount(amount float64, s Scale) float64 {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return amount
+	}
+	return round(amount, int(s))
+}
+
+// round rounds f to the given number of decimals, which may be negative. The
+// shortest decimal representation of f is rounded rather than its binary
+// value, so that e.g. 1.005 rounds up to 1.01.
+func round(f float64, decimals int) float64 {
+	str := strconv.FormatFloat(math.Abs(f), 'e', -1, 64)
+	parts := strings.SplitN(str, "e", 2)
+	exp, _ := strconv.Atoi(parts[1])
+	digits := strings.Replace(parts[0], ".", "", 1)
+
+	// keep the digits down to the requested decimal, i.e. those of the
+	// powers of ten from exp down to -decimals
+	keep := exp + decimals + 1
+	if keep >= len(digits) {
+		return f
+	}
+	if keep < 0 {
+		return 0
+	}
+	var mantissa int64
+	for _, d := range digits[:keep] {
+		mantissa = mantissa*10 + int64(d-'0')
+	}
+	if digits[keep] >= '5' {
+		mantissa++
+	}
+
+	r, _ := strconv.ParseFloat(strconv.FormatInt(mantissa, 10)+"e"+strconv.Itoa(-decimals), 64)
+	if f < 0 {
+		return -r
+	}
+	return r
+}
+
+// SymbolScale holds the scales of prices and amounts of a symbol
+type SymbolScale struct {
+	Price  Scale
+	Amount Scale
+}
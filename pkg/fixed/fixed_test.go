@@ -0,0 +1,127 @@
+package fixed_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/fixed"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		in    string
+		scale fixed.Scale
+		out   int64
+		err   bool
+	}{
+		"integer":         {"42", 2, 4200, false},
+		"decimals":        {"-0.12345678", 8, -12345678, false},
+		"trailing zeros":  {"1.500", 1, 15, false},
+		"leading dot":     {".5", 2, 50, false},
+		"exponent":        {"1e-5", 8, 1000, false},
+		"zero":            {"0.000", 0, 0, false},
+		"too precise":     {"0.123", 2, 0, true},
+		"overflow":        {"100000000000", 9, 0, true},
+		"garbage":         {"1.2x", 2, 0, true},
+		"empty":           {"", 2, 0, true},
+		"large id":        {"9007199254740993", 0, 9007199254740993, false},
+		"max price scale": {"0.000012345", 9, 12345, false},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			out, err := v.scale.Parse(v.in)
+			if v.err {
+				assert.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			assert.Equal(t, v.out, out)
+		})
+	}
+
+	_, err := fixed.Scale(10).Parse("1000000000")
+	assert.True(t, errors.Is(err, fixed.ErrOverflow))
+}
+
+func TestFormatAndRescale(t *testing.T) {
+	s := fixed.Scale(8)
+	assert.Equal(t, "-0.00000001", s.Format(-1))
+	assert.Equal(t, "123.45000000", s.Format(12345000000))
+	assert.Equal(t, "7", fixed.Scale(0).Format(7))
+	assert.Equal(t, 0.5, s.Float(50000000))
+
+	v, err := s.FromFloat(0.1 + 0.2)
+	require.Nil(t, err)
+	assert.Equal(t, int64(30000000), v)
+	_, err = s.FromFloat(math.Inf(1))
+	assert.True(t, errors.Is(err, fixed.ErrOverflow))
+
+	r, err := s.Rescale(12345678, 2)
+	require.Nil(t, err)
+	assert.Equal(t, int64(12), r)
+	r, err = s.Rescale(-15000000, 1)
+	require.Nil(t, err)
+	assert.Equal(t, int64(-2), r)
+	r, err = fixed.Scale(2).Rescale(150, 8)
+	require.Nil(t, err)
+	assert.Equal(t, int64(150000000), r)
+	_, err = fixed.Scale(0).Rescale(math.MaxInt64/10, 2)
+	assert.True(t, errors.Is(err, fixed.ErrOverflow))
+}
+
+func TestMul(t *testing.T) {
+	// 43210.5 USD * 1.25 BTC = 54013.125 USD
+	price, amount := int64(432105), int64(125000000)
+	n, err := fixed.Scale(1).Mul(price, amount, 8)
+	require.Nil(t, err)
+	assert.Equal(t, int64(540131), n)
+
+	n, err = fixed.Scale(1).Mul(price, -amount, 8)
+	require.Nil(t, err)
+	assert.Equal(t, int64(-540131), n)
+
+	_, err = fixed.Scale(8).Mul(math.MaxInt64, math.MaxInt64, 8)
+	assert.True(t, errors.Is(err, fixed.ErrOverflow))
+}
+
+func TestPriceScale(t *testing.T) {
+	assert.Equal(t, fixed.Scale(0), fixed.PriceScale(43210))
+	assert.Equal(t, fixed.Scale(0), fixed.PriceScale(123456))
+	assert.Equal(t, fixed.Scale(2), fixed.PriceScale(123.45))
+	assert.Equal(t, fixed.Scale(4), fixed.PriceScale(1.2345))
+	assert.Equal(t, fixed.Scale(9), fixed.PriceScale(0.000012345))
+}
+
+func TestRound(t *testing.T) {
+	assert.Equal(t, 43211.0, fixed.RoundPrice(43210.5, fixed.PriceSignificantDigits))
+	assert.Equal(t, 123460.0, fixed.RoundPrice(123456, fixed.PriceSignificantDigits))
+	assert.Equal(t, -1.2346, fixed.RoundPrice(-1.23456, fixed.PriceSignificantDigits))
+	assert.Equal(t, 0.000012346, fixed.RoundPrice(0.0000123456, fixed.PriceSignificantDigits))
+	assert.Equal(t, 100000.0, fixed.RoundPrice(99999.5, fixed.PriceSignificantDigits))
+	assert.Equal(t, 1.005, fixed.RoundPrice(1.005, fixed.PriceSignificantDigits))
+	assert.Equal(t, 0.0, fixed.RoundPrice(0, fixed.PriceSignificantDigits))
+
+	assert.Equal(t, 0.12345679, fixed.RoundAmount(0.123456789, fixed.DefaultAmountScale))
+	assert.Equal(t, -1.01, fixed.RoundAmount(-1.005, 2))
+	assert.Equal(t, 0.5, fixed.RoundAmount(0.5, fixed.DefaultAmountScale))
+	assert.Equal(t, 0.0, fixed.RoundAmount(0.000000004, fixed.DefaultAmountScale))
+	assert.Equal(t, 0.00000001, fixed.RoundAmount(0.000000005, fixed.DefaultAmountScale))
+}
+
+func TestBookFixed(t *testing.T) {
+	b, err := book.FromRaw("tBTCUSD", "P0", []interface{}{43210.5, 2.0, -0.12345678}, []interface{}{
+		json.Number("43210.5"), json.Number("2"), json.Number("-0.12345678"),
+	})
+	require.Nil(t, err)
+
+	price, amount, err := b.Fixed(fixed.SymbolScale{Price: 1, Amount: 8})
+	require.Nil(t, err)
+	assert.Equal(t, int64(432105), price)
+	assert.Equal(t, int64(12345678), amount)
+}
@@ -0,0 +1,391 @@
+// Package fundingbot places and manages funding offers according to a lending
+// Strategy, driven by the websocket funding book, offer and wallet streams.
+package fundingbot
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/fundingoffer"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/ticker"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/wallet"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+)
+
+const (
+	// DefaultInterval is the time between evaluations of Run
+	DefaultInterval = time.Minute
+	// DefaultMinAmount is the smallest offer of fUSD accepted by the platform
+	DefaultMinAmount = 150
+	// DefaultTolerance is the relative rate difference up to which an open
+	// offer is kept instead of being replaced
+	DefaultTolerance = 0.02
+	// MinPeriod is the shortest period of a funding offer in days
+	MinPeriod = 2
+
+	// pendingTimeout drops submitted offers which were not confirmed, e.g.
+	// because the platform rejected them
+	pendingTimeout = 30 * time.Second
+	// epsilon is the tolerance used when comparing amounts
+	epsilon = 1e-8
+)
+
+// OfferSubmitter submits and cancels funding offers. It is implemented by the
+// websocket client.
+type OfferSubmitter interface {
+	SubmitFundingOffer(ctx context.Context, fo *fundingoffer.SubmitRequest) error
+	SubmitFundingCancel(ctx context.Context, fc *fundingoffer.CancelRequest) error
+}
+
+// Config describes the funding market a Bot lends on.
+type Config struct {
+	Symbol    string // funding symbol, e.g. fUSD
+	Strategy  Strategy
+	Interval  time.Duration // time between evaluations, defaults to DefaultInterval
+	MinAmount float64       // smallest offer submitted, defaults to DefaultMinAmount
+	Tolerance float64       // see DefaultTolerance
+	// Clock times the evaluations and pending offers, defaults to
+	// utils.SystemClock
+	Clock utils.Clock
+}
+
+type levelKey struct {
+	id     int64
+	rate   float64
+	period int64
+}
+
+type pendingOffer struct {
+	req  *fundingoffer.SubmitRequest
+	sent time.Time
+}
+
+// Bot keeps the offers wanted by its Strategy on the funding book. The events
+// of the websocket client have to be passed to Handle, e.g.:
+//
+//	b, _ := fundingbot.New(client, fundingbot.Config{Symbol: "fUSD", Strategy: fundingbot.RateFloor{Floor: 0.0002}})
+//	go b.Run(ctx)
+//	for ev := range client.Listen() {
+//		b.Handle(ev)
+//	}
+//
+// The client has to be authenticated and subscribed to the funding book of the
+// symbol, and to its ticker for strategies using the flash return rate.
+// Funds returned by loans and partially taken offers are offered again on
+// the next evaluation.
+type Bot struct {
+	cfg       Config
+	submitter OfferSubmitter
+
+	mu        sync.Mutex
+	levels    map[levelKey]*book.FundingBookUpdate
+	frr       float64
+	available float64
+	offers    map[int64]*fundingoffer.Offer
+	canceling map[int64]bool
+	pending   []pendingOffer
+}
+
+// New returns a bot for the given funding symbol and strategy.
+func New(s OfferSubmitter, cfg Config) (*Bot, error) {
+	if !strings.HasPrefix(cfg.Symbol, "f") {
+		return nil, fmt.Errorf("%w: %q is not a funding symbol", common.ErrBadRequest, cfg.Symbol)
+	}
+	if cfg.Strategy == nil {
+		return nil, fmt.Errorf("%w: strategy is required", common.ErrBadRequest)
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.MinAmount <= 0 {
+		cfg.MinAmount = DefaultMinAmount
+	}
+	if cfg.Tolerance <= 0 {
+		cfg.Tolerance = DefaultTolerance
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = utils.SystemClock
+	}
+	return &Bot{
+		cfg:       cfg,
+		submitter: s,
+		levels:    make(map[levelKey]*book.FundingBookUpdate),
+		offers:    make(map[int64]*fundingoffer.Offer),
+		canceling: make(map[int64]bool),
+	}, nil
+}
+
+// Handle updates the state of the bot. Events of other symbols are ignored.
+func (b *Bot) Handle(ev interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch e := ev.(type) {
+	case *book.FundingSnapshot:
+		if len(e.Snapshot) == 0 || e.Snapshot[0].Symbol != b.cfg.Symbol {
+			return
+		}
+		b.levels = make(map[levelKey]*book.FundingBookUpdate)
+		for _, l := range e.Snapshot {
+			b.applyLevel(l)
+		}
+	case *book.FundingBookUpdate:
+		if e.Symbol == b.cfg.Symbol {
+			b.applyLevel(e)
+		}
+	case *ticker.FundingTicker:
+		if e.Symbol == b.cfg.Symbol {
+			b.frr = e.Frr
+		}
+	case *wallet.Snapshot:
+		for _, w := range e.Snapshot {
+			b.applyWallet(w)
+		}
+	case *wallet.Update:
+		b.applyWallet((*wallet.Wallet)(e))
+	case *fundingoffer.Snapshot:
+		b.offers = make(map[int64]*fundingoffer.Offer)
+		for _, o := range e.Snapshot {
+			if o.Symbol == b.cfg.Symbol {
+				b.offers[o.ID] = o
+			}
+		}
+	case *fundingoffer.New:
+		b.applyOffer((*fundingoffer.Offer)(e))
+	case *fundingoffer.Update:
+		b.applyOffer((*fundingoffer.Offer)(e))
+	case *fundingoffer.Cancel:
+		if e.Symbol == b.cfg.Symbol {
+			delete(b.offers, e.ID)
+			delete(b.canceling, e.ID)
+		}
+	}
+}
+
+func (b *Bot) applyLevel(l *book.FundingBookUpdate) {
+	key := levelKey{id: l.ID, rate: l.Rate, period: l.Period}
+	if l.ID != 0 {
+		// entries of raw books are removed with a zero rate
+		key = levelKey{id: l.ID}
+	}
+	if l.Action == book.BookRemoveEntry {
+		delete(b.levels, key)
+		return
+	}
+	b.levels[key] = l
+}
+
+// applyWallet takes the available funds of the funding wallet, updates
+// before the available balance was calculated keep the last known funds
+func (b *Bot) applyWallet(w *wallet.Wallet) {
+	if w.Type == "funding" && "f"+w.Currency == b.cfg.Symbol && w.HasBalanceAvailable {
+		b.available = w.BalanceAvailable
+	}
+}
+
+func (b *Bot) applyOffer(o *fundingoffer.Offer) {
+	if o.Symbol != b.cfg.Symbol {
+		return
+	}
+	if _, ok := b.offers[o.ID]; !ok {
+		// confirms a submitted offer
+		for i, p := range b.pending {
+			if p.req.Period == o.Period && b.sameRate(p.req.Rate, o.Rate) && math.Abs(p.req.Amount-o.AmountOrig) < epsilon {
+				b.pending = append(b.pending[:i], b.pending[i+1:]...)
+				break
+			}
+		}
+	}
+	b.offers[o.ID] = o
+}
+
+func (b *Bot) sameRate(a, c float64) bool {
+	if a == c {
+		return true
+	}
+	return math.Abs(a-c) <= b.cfg.Tolerance*math.Max(math.Abs(a), math.Abs(c))
+}
+
+// Market returns the state the strategy is evaluated with.
+func (b *Bot) Market() Market {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.market(b.cfg.Clock.Now())
+}
+
+// market must be called with the lock held
+func (b *Bot) market(now time.Time) Market {
+	m := Market{
+		Symbol:    b.cfg.Symbol,
+		FRR:       b.frr,
+		MinAmount: b.cfg.MinAmount,
+	}
+	for _, l := range b.levels {
+		lvl := Level{Rate: l.Rate, Period: l.Period, Amount: l.Amount, Count: l.Count}
+		if l.Side == common.Ask {
+			m.Asks = append(m.Asks, lvl)
+		} else {
+			m.Bids = append(m.Bids, lvl)
+		}
+	}
+	sort.Slice(m.Asks, func(i, j int) bool { return m.Asks[i].Rate < m.Asks[j].Rate })
+	sort.Slice(m.Bids, func(i, j int) bool { return m.Bids[i].Rate > m.Bids[j].Rate })
+
+	// funds of submitted offers are not available, even if the wallet was
+	// not updated yet
+	pending := b.pending[:0]
+	for _, p := range b.pending {
+		if now.Sub(p.sent) > pendingTimeout {
+			continue
+		}
+		pending = append(pending, p)
+		m.Pending += p.req.Amount
+	}
+	b.pending = pending
+	m.Available = b.available - m.Pending
+	if m.Available < 0 {
+		m.Available = 0
+	}
+
+	for _, id := range b.offerIDs() {
+		if !b.canceling[id] {
+			m.Offers = append(m.Offers, b.offers[id])
+		}
+	}
+	return m
+}
+
+// offerIDs returns the ids of the open offers in ascending order
+func (b *Bot) offerIDs() []int64 {
+	ids := make([]int64, 0, len(b.offers))
+	for id := range b.offers {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Evaluate asks the strategy for the wanted offers, cancels the open offers
+// which are not wanted anymore and submits the missing ones. Requests which
+// fail do not stop the others, the first error is returned.
+func (b *Bot) Evaluate(ctx context.Context) error {
+	cancels, submits := b.plan()
+
+	var err error
+	for _, id := range cancels {
+		if cerr := b.submitter.SubmitFundingCancel(ctx, &fundingoffer.CancelRequest{ID: id}); cerr != nil {
+			b.mu.Lock()
+			delete(b.canceling, id)
+			b.mu.Unlock()
+			if err == nil {
+				err = cerr
+			}
+		}
+	}
+	for _, req := range submits {
+		if serr := b.submitter.SubmitFundingOffer(ctx, req); serr != nil {
+			b.dropPending(req)
+			if err == nil {
+				err = serr
+			}
+		}
+	}
+	return err
+}
+
+// plan returns the open offers to cancel and the offers to submit. They are
+// recorded as canceling and pending before they are sent, as their events
+// may arrive before the submitter returns.
+func (b *Bot) plan() ([]int64, []*fundingoffer.SubmitRequest) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.cfg.Clock.Now()
+	m := b.market(now)
+	want := b.cfg.Strategy.Offers(m)
+
+	used := make(map[int64]bool)
+	usedPending := make(map[int]bool)
+	free := m.Available
+	var submits []*fundingoffer.SubmitRequest
+	for _, w := range want {
+		if w.Period < MinPeriod {
+			w.Period = MinPeriod
+		}
+		// open and submitted offers at the rate cover the wanted amount
+		var covered float64
+		for _, o := range m.Offers {
+			if !used[o.ID] && o.Period == w.Period && b.sameRate(o.Rate, w.Rate) {
+				used[o.ID] = true
+				covered += o.Amount
+			}
+		}
+		for i, p := range b.pending {
+			if !usedPending[i] && p.req.Period == w.Period && b.sameRate(p.req.Rate, w.Rate) {
+				usedPending[i] = true
+				covered += p.req.Amount
+			}
+		}
+
+		amount := math.Min(w.Amount-covered, free)
+		if amount+epsilon < b.cfg.MinAmount {
+			continue
+		}
+		free -= amount
+		submits = append(submits, &fundingoffer.SubmitRequest{
+			Type:   "LIMIT",
+			Symbol: b.cfg.Symbol,
+			Amount: amount,
+			Rate:   w.Rate,
+			Period: w.Period,
+			Hidden: w.Hidden,
+		})
+	}
+
+	var cancels []int64
+	for _, o := range m.Offers {
+		if !used[o.ID] {
+			cancels = append(cancels, o.ID)
+			b.canceling[o.ID] = true
+		}
+	}
+	for _, req := range submits {
+		b.pending = append(b.pending, pendingOffer{req: req, sent: now})
+	}
+	return cancels, submits
+}
+
+// dropPending removes the offer which failed to be submitted
+func (b *Bot) dropPending(req *fundingoffer.SubmitRequest) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, p := range b.pending {
+		if p.req == req {
+			b.pending = append(b.pending[:i], b.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// Run evaluates the strategy every interval until ctx is done.
+func (b *Bot) Run(ctx context.Context) error {
+	for {
+		if err := b.Evaluate(ctx); err != nil {
+			return err
+		}
+		t := b.cfg.Clock.NewTimer(b.cfg.Interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C():
+		}
+	}
+}
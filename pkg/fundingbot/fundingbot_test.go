@@ -0,0 +1,312 @@
+package fundingbot_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/fundingbot"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/fundingoffer"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/ticker"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/wallet"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSubmitter struct {
+	mu      sync.Mutex
+	offers  []*fundingoffer.SubmitRequest
+	cancels []int64
+}
+
+func (m *mockSubmitter) SubmitFundingOffer(ctx context.Context, fo *fundingoffer.SubmitRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.offers = append(m.offers, fo)
+	return nil
+}
+
+func (m *mockSubmitter) SubmitFundingCancel(ctx context.Context, fc *fundingoffer.CancelRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cancels = append(m.cancels, fc.ID)
+	return nil
+}
+
+func (m *mockSubmitter) take() ([]*fundingoffer.SubmitRequest, []int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	offers, cancels := m.offers, m.cancels
+	m.offers, m.cancels = nil, nil
+	return offers, cancels
+}
+
+func fundingBook(rates ...float64) *book.FundingSnapshot {
+	snap := &book.FundingSnapshot{}
+	for _, r := range rates {
+		snap.Snapshot = append(snap.Snapshot, &book.FundingBookUpdate{Symbol: "fUSD", Rate: r, Period: 2, Count: 1, Amount: 5000, Side: common.Ask})
+	}
+	snap.Snapshot = append(snap.Snapshot, &book.FundingBookUpdate{Symbol: "fUSD", Rate: 0.0001, Period: 2, Count: 1, Amount: 5000, Side: common.Bid})
+	return snap
+}
+
+func available(amount float64) *wallet.Update {
+	return &wallet.Update{Type: "funding", Currency: "USD", BalanceAvailable: amount, HasBalanceAvailable: true}
+}
+
+func confirm(req *fundingoffer.SubmitRequest, id int64) *fundingoffer.New {
+	return &fundingoffer.New{
+		ID:         id,
+		Symbol:     req.Symbol,
+		Amount:     req.Amount,
+		AmountOrig: req.Amount,
+		Rate:       req.Rate,
+		Period:     req.Period,
+		Status:     "ACTIVE",
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := fundingbot.New(&mockSubmitter{}, fundingbot.Config{Symbol: "tBTCUSD", Strategy: fundingbot.RateFloor{}})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+	_, err = fundingbot.New(&mockSubmitter{}, fundingbot.Config{Symbol: "fUSD"})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
+
+func TestLadderReplacesOffers(t *testing.T) {
+	m := &mockSubmitter{}
+	b, err := fundingbot.New(m, fundingbot.Config{
+		Symbol:   "fUSD",
+		Strategy: fundingbot.Ladder{Floor: 0.0002, Steps: 3, Step: 0.1, Period: 2},
+	})
+	require.Nil(t, err)
+	ctx := context.Background()
+
+	b.Handle(fundingBook(0.0004, 0.0003))
+	b.Handle(available(1000))
+	mkt := b.Market()
+	assert.Equal(t, 0.0003, mkt.BestAsk())
+	assert.Len(t, mkt.Bids, 1)
+
+	require.Nil(t, b.Evaluate(ctx))
+	offers, cancels := m.take()
+	require.Len(t, offers, 3)
+	assert.Empty(t, cancels)
+	assert.InDelta(t, 0.0003, offers[0].Rate, 1e-12)
+	assert.InDelta(t, 0.00033, offers[1].Rate, 1e-12)
+	assert.InDelta(t, 0.000363, offers[2].Rate, 1e-12)
+	for _, o := range offers {
+		assert.InDelta(t, 1000.0/3, o.Amount, 1e-9)
+	}
+
+	// submitted offers are not submitted again before they are confirmed
+	require.Nil(t, b.Evaluate(ctx))
+	again, _ := m.take()
+	assert.Empty(t, again)
+
+	// a partial confirmation does not change the ladder
+	b.Handle(confirm(offers[0], 1))
+	require.Nil(t, b.Evaluate(ctx))
+	again, cancels = m.take()
+	assert.Empty(t, again)
+	assert.Empty(t, cancels)
+
+	for i, o := range offers[1:] {
+		b.Handle(confirm(o, int64(i+2)))
+	}
+	b.Handle(available(0))
+	require.Nil(t, b.Evaluate(ctx))
+	again, cancels = m.take()
+	assert.Empty(t, again)
+	assert.Empty(t, cancels)
+
+	// the book moved, all offers are replaced once cancelled
+	b.Handle(fundingBook(0.0005))
+	require.Nil(t, b.Evaluate(ctx))
+	again, cancels = m.take()
+	assert.Empty(t, again)
+	assert.Equal(t, []int64{1, 2, 3}, cancels)
+
+	require.Nil(t, b.Evaluate(ctx))
+	_, cancels = m.take()
+	assert.Empty(t, cancels, "offers are cancelled once")
+
+	for i := range offers {
+		b.Handle(&fundingoffer.Cancel{ID: int64(i + 1), Symbol: "fUSD", Status: "CANCELED"})
+	}
+	b.Handle(available(1000))
+	require.Nil(t, b.Evaluate(ctx))
+	offers, _ = m.take()
+	require.Len(t, offers, 3)
+	assert.InDelta(t, 0.0005, offers[0].Rate, 1e-12)
+}
+
+func TestPartialTakeAndRenewal(t *testing.T) {
+	m := &mockSubmitter{}
+	b, err := fundingbot.New(m, fundingbot.Config{
+		Symbol:   "fUSD",
+		Strategy: fundingbot.RateFloor{Floor: 0.0003},
+	})
+	require.Nil(t, err)
+	ctx := context.Background()
+
+	b.Handle(fundingBook(0.0001))
+	b.Handle(available(1000))
+	require.Nil(t, b.Evaluate(ctx))
+	offers, _ := m.take()
+	require.Len(t, offers, 1)
+	assert.Equal(t, 0.0003, offers[0].Rate)
+	assert.Equal(t, int64(fundingbot.MinPeriod), offers[0].Period)
+
+	// 600 were taken, the rest stays on the book
+	b.Handle(confirm(offers[0], 1))
+	b.Handle(&fundingoffer.Update{ID: 1, Symbol: "fUSD", Amount: 400, AmountOrig: 1000, Rate: 0.0003, Period: 2})
+	b.Handle(available(0))
+	require.Nil(t, b.Evaluate(ctx))
+	again, cancels := m.take()
+	assert.Empty(t, again)
+	assert.Empty(t, cancels)
+
+	// the loan was repaid, the funds are offered again, also if an update
+	// without the calculated available balance follows
+	b.Handle(available(600))
+	b.Handle(&wallet.Update{Type: "funding", Currency: "USD", Balance: 1000})
+	assert.Equal(t, 600.0, b.Market().Available)
+	require.Nil(t, b.Evaluate(ctx))
+	again, cancels = m.take()
+	assert.Empty(t, cancels)
+	require.Len(t, again, 1)
+	assert.Equal(t, 600.0, again[0].Amount)
+
+	// less than the minimum is not offered
+	b.Handle(confirm(again[0], 2))
+	b.Handle(available(100))
+	require.Nil(t, b.Evaluate(ctx))
+	again, _ = m.take()
+	assert.Empty(t, again)
+}
+
+func TestFRRTracking(t *testing.T) {
+	m := &mockSubmitter{}
+	b, err := fundingbot.New(m, fundingbot.Config{
+		Symbol:   "fUSD",
+		Strategy: fundingbot.FRRTracking{Delta: 0.00001, Floor: 0.0001, Period: 30},
+	})
+	require.Nil(t, err)
+	ctx := context.Background()
+
+	b.Handle(available(500))
+	require.Nil(t, b.Evaluate(ctx))
+	offers, _ := m.take()
+	assert.Empty(t, offers, "nothing is offered without the flash return rate")
+
+	b.Handle(&ticker.FundingTicker{Symbol: "fUSD", Frr: 0.0002})
+	require.Nil(t, b.Evaluate(ctx))
+	offers, _ = m.take()
+	require.Len(t, offers, 1)
+	assert.InDelta(t, 0.00021, offers[0].Rate, 1e-12)
+	assert.Equal(t, int64(30), offers[0].Period)
+}
+
+// handlingSubmitter confirms offers and cancels before the submitter returns,
+// as a websocket reader may, unless they are set to fail
+type handlingSubmitter struct {
+	mockSubmitter
+	b          *fundingbot.Bot
+	next       int64
+	failCancel error
+	failOffer  error
+}
+
+func (h *handlingSubmitter) SubmitFundingOffer(ctx context.Context, fo *fundingoffer.SubmitRequest) error {
+	if h.failOffer != nil {
+		return h.failOffer
+	}
+	_ = h.mockSubmitter.SubmitFundingOffer(ctx, fo)
+	h.next++
+	h.b.Handle(confirm(fo, h.next))
+	return nil
+}
+
+func (h *handlingSubmitter) SubmitFundingCancel(ctx context.Context, fc *fundingoffer.CancelRequest) error {
+	if h.failCancel != nil {
+		return h.failCancel
+	}
+	_ = h.mockSubmitter.SubmitFundingCancel(ctx, fc)
+	h.b.Handle(&fundingoffer.Cancel{ID: fc.ID, Symbol: "fUSD", Status: "CANCELED"})
+	return nil
+}
+
+func TestEventsDuringEvaluate(t *testing.T) {
+	h := &handlingSubmitter{}
+	b, err := fundingbot.New(h, fundingbot.Config{
+		Symbol:   "fUSD",
+		Strategy: fundingbot.RateFloor{Floor: 0.0003},
+	})
+	require.Nil(t, err)
+	h.b = b
+	ctx := context.Background()
+
+	b.Handle(fundingBook(0.0001))
+	b.Handle(available(1000))
+	require.Nil(t, b.Evaluate(ctx))
+	mkt := b.Market()
+	require.Len(t, mkt.Offers, 1)
+	assert.Equal(t, 0.0, mkt.Pending)
+
+	// a failed cancel leaves the offer open and does not stop the
+	// submission of the funds which became available
+	h.failCancel = errors.New("not connected")
+	b.Handle(fundingBook(0.0005))
+	b.Handle(available(500))
+	assert.Equal(t, h.failCancel, b.Evaluate(ctx))
+	offers, cancels := h.take()
+	assert.Empty(t, cancels)
+	require.Len(t, offers, 2)
+	assert.Equal(t, 500.0, offers[1].Amount)
+	mkt = b.Market()
+	assert.Len(t, mkt.Offers, 2, "the offer which failed to be cancelled is still open")
+
+	// a failed offer is not counted as pending
+	h.failCancel = nil
+	h.failOffer = errors.New("not connected")
+	b.Handle(available(1500))
+	assert.Equal(t, h.failOffer, b.Evaluate(ctx))
+	_, cancels = h.take()
+	assert.Equal(t, []int64{1}, cancels)
+	mkt = b.Market()
+	assert.Equal(t, 0.0, mkt.Pending)
+	assert.Len(t, mkt.Offers, 1)
+}
+
+func TestPendingTimeout(t *testing.T) {
+	m := &mockSubmitter{}
+	clock := utils.NewManualClock(time.Unix(0, 0))
+	b, err := fundingbot.New(m, fundingbot.Config{
+		Symbol:   "fUSD",
+		Strategy: fundingbot.RateFloor{Floor: 0.0003},
+		Clock:    clock,
+	})
+	require.Nil(t, err)
+	ctx := context.Background()
+
+	b.Handle(available(1000))
+	require.Nil(t, b.Evaluate(ctx))
+	offers, _ := m.take()
+	require.Len(t, offers, 1)
+
+	// an offer which is never confirmed is submitted again after a while
+	clock.Advance(10 * time.Second)
+	require.Nil(t, b.Evaluate(ctx))
+	offers, _ = m.take()
+	assert.Empty(t, offers)
+	clock.Advance(time.Minute)
+	require.Nil(t, b.Evaluate(ctx))
+	offers, _ = m.take()
+	assert.Len(t, offers, 1)
+}
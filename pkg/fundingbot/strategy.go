@@ -0,0 +1,135 @@
+package fundingbot
+
+import (
+	"math"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/fundingoffer"
+)
+
+// Level is an aggregated level of the funding book
+type Level struct {
+	Rate   float64 // daily rate
+	Period int64   // days
+	Amount float64
+	Count  int64
+}
+
+// Market is the state a Strategy decides on
+type Market struct {
+	Symbol    string
+	Asks      []Level // offers of lenders, lowest rate first
+	Bids      []Level // demand of borrowers, highest rate first
+	FRR       float64 // flash return rate, 0 without ticker subscription
+	Available float64 // funds which are not offered or lent
+	Pending   float64 // funds of submitted offers which were not confirmed yet
+	MinAmount float64 // smallest offer the bot submits
+	Offers    []*fundingoffer.Offer
+}
+
+// Capital returns the funds a strategy can offer, i.e. the available funds
+// and the amount of the open and submitted offers
+func (m Market) Capital() float64 {
+	capital := m.Available + m.Pending
+	for _, o := range m.Offers {
+		capital += o.Amount
+	}
+	return capital
+}
+
+// BestAsk returns the lowest rate offered by lenders, or 0 for an empty book
+func (m Market) BestAsk() float64 {
+	if len(m.Asks) == 0 {
+		return 0
+	}
+	return m.Asks[0].Rate
+}
+
+// Offer is an offer wanted by a Strategy
+type Offer struct {
+	Amount float64
+	Rate   float64 // daily rate
+	Period int64   // days, at least MinPeriod
+	Hidden bool
+}
+
+// Strategy returns the offers which should be on the book. Open offers at the
+// same period and rate, within the tolerance of the bot, count towards the
+// amount of a wanted offer, all other open offers are cancelled.
+type Strategy interface {
+	Offers(m Market) []Offer
+}
+
+// StrategyFunc adapts a function to a Strategy
+type StrategyFunc func(m Market) []Offer
+
+// Offers implements Strategy
+func (f StrategyFunc) Offers(m Market) []Offer {
+	return f(m)
+}
+
+// RateFloor offers all funds in a single offer at the lowest rate of the
+// book, but never below Floor
+type RateFloor struct {
+	Floor  float64
+	Period int64
+}
+
+// Offers implements Strategy
+func (s RateFloor) Offers(m Market) []Offer {
+	return []Offer{{
+		Amount: m.Capital(),
+		Rate:   math.Max(s.Floor, m.BestAsk()),
+		Period: s.Period,
+	}}
+}
+
+// Ladder splits the funds into Steps offers, the first at the lowest rate of
+// the book but never below Floor, each further one Step higher, e.g. a Step
+// of 0.1 raises the rate by 10% per offer. Fewer offers are placed if the
+// funds do not allow Steps offers of the minimum amount.
+type Ladder struct {
+	Floor  float64
+	Steps  int
+	Step   float64
+	Period int64
+}
+
+// Offers implements Strategy
+func (s Ladder) Offers(m Market) []Offer {
+	capital := m.Capital()
+	steps := s.Steps
+	if m.MinAmount > 0 {
+		steps = int(math.Min(float64(steps), math.Floor(capital/m.MinAmount)))
+	}
+	if steps < 1 {
+		steps = 1
+	}
+
+	rate := math.Max(s.Floor, m.BestAsk())
+	offers := make([]Offer, steps)
+	for i := range offers {
+		offers[i] = Offer{Amount: capital / float64(steps), Rate: rate, Period: s.Period}
+		rate *= 1 + s.Step
+	}
+	return offers
+}
+
+// FRRTracking offers all funds at the flash return rate plus Delta, but never
+// below Floor. Nothing is offered until the flash return rate is known.
+type FRRTracking struct {
+	Delta  float64
+	Floor  float64
+	Period int64
+}
+
+// Offers implements Strategy
+func (s FRRTracking) Offers(m Market) []Offer {
+	if m.FRR == 0 {
+		return nil
+	}
+	return []Offer{{
+		Amount: m.Capital(),
+		Rate:   math.Max(s.Floor, m.FRR+s.Delta),
+		Period: s.Period,
+	}}
+}
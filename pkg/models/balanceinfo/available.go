@@ -0,0 +1,44 @@
+package balanceinfo
+
+import (
+	"fmt"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+)
+
+// AvailableType selects the wallet and order kind the available balance is
+// calculated for
+type AvailableType string
+
+const (
+	AvailableExchange AvailableType = "EXCHANGE"
+	AvailableMargin   AvailableType = "MARGIN"
+	AvailableDeriv    AvailableType = "DERIV"
+	AvailableFunding  AvailableType = "FUNDING"
+)
+
+// Available is the maximum amount that can be used for an order or offer
+// in the given direction, as calculated by the exchange
+type Available struct {
+	Symbol string
+	Dir    int
+	Rate   float64
+	Type   AvailableType
+	Amount float64
+}
+
+// AvailableFromRaw maps the response of the calc/order/avail endpoint
+func AvailableFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (*Available, error) {
+	if len(raw) < 1 {
+		return nil, fmt.Errorf("data slice too short for available balance: %#v", raw)
+	}
+
+	f := convert.NewFields("available balance", raw, opts...)
+	a := &Available{Amount: f.F64(0)}
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
@@ -13,21 +13,27 @@ type BalanceInfo struct {
 
 type Update BalanceInfo
 
-func FromRaw(raw []interface{}) (o *BalanceInfo, err error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (o *BalanceInfo, err error) {
 	if len(raw) < 2 {
 		return o, fmt.Errorf("data slice too short for balance info: %#v", raw)
 	}
 
+	f := convert.NewFields("balance info", raw, opts...)
+
 	o = &BalanceInfo{
-		TotalAUM: convert.F64ValOrZero(raw[0]),
-		NetAUM:   convert.F64ValOrZero(raw[1]),
+		TotalAUM: f.F64(0),
+		NetAUM:   f.F64(1),
+	}
+
+	if err = f.Err(); err != nil {
+		return nil, err
 	}
 
 	return
 }
 
-func UpdateFromRaw(raw []interface{}) (Update, error) {
-	bi, err := FromRaw(raw)
+func UpdateFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (Update, error) {
+	bi, err := FromRaw(raw, opts...)
 	if err != nil {
 		return Update{}, err
 	}
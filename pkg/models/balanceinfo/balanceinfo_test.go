@@ -30,3 +30,12 @@ func TestBalanceInfoFromRaw(t *testing.T) {
 		assert.Equal(t, expected, b)
 	})
 }
+
+func TestAvailableFromRaw(t *testing.T) {
+	_, err := balanceinfo.AvailableFromRaw([]interface{}{})
+	require.NotNil(t, err)
+
+	a, err := balanceinfo.AvailableFromRaw([]interface{}{-1.5})
+	require.Nil(t, err)
+	assert.Equal(t, -1.5, a.Amount)
+}
@@ -7,6 +7,7 @@ import (
 	"math"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/fixed"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 )
 
@@ -35,18 +36,44 @@ type Book struct {
 	Action      BookAction       // action (add/remove)
 }
 
+// Fixed returns the price and amount as fixed-point values at the given
+// scales. They are parsed from the exact numbers of the payload if available.
+func (b *Book) Fixed(s fixed.SymbolScale) (price, amount int64, err error) {
+	if b.PriceJsNum != "" {
+		price, err = s.Price.ParseNumber(b.PriceJsNum)
+	} else {
+		price, err = s.Price.FromFloat(b.Price)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if b.AmountJsNum != "" {
+		amount, err = s.Amount.ParseNumber(b.AmountJsNum)
+	} else {
+		amount, err = s.Amount.FromFloat(b.Amount)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if amount < 0 {
+		amount = -amount
+	}
+	return price, amount, nil
+}
+
 type Snapshot struct {
 	Snapshot []*Book
 }
 
-func SnapshotFromRaw(symbol, precision string, raw [][]interface{}, rawNumbers interface{}) (*Snapshot, error) {
+func SnapshotFromRaw(symbol, precision string, raw [][]interface{}, rawNumbers interface{}, opts ...convert.DecodeOptions) (*Snapshot, error) {
 	if len(raw) <= 0 {
 		return nil, fmt.Errorf("data slice too short for book snapshot: %#v", raw)
 	}
 
 	snap := make([]*Book, len(raw))
 	for i, v := range raw {
-		b, err := FromRaw(symbol, precision, v, rawNumbers.([]interface{})[i])
+		b, err := FromRaw(symbol, precision, v, rawNumbers.([]interface{})[i], opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -63,49 +90,53 @@ func IsRawBook(precision string) bool {
 // FromRaw creates a new book object from raw data. Precision determines how
 // to interpret the side (baked into Count versus Amount)
 // raw book updates [ID, price, qty], aggregated book updates [price, amount, count]
-func FromRaw(symbol, precision string, raw []interface{}, rawNumbers interface{}) (b *Book, err error) {
+func FromRaw(symbol, precision string, raw []interface{}, rawNumbers interface{}, opts ...convert.DecodeOptions) (b *Book, err error) {
 	if len(raw) < 3 {
 		return b, fmt.Errorf("raw slice too short for book, expected %d got %d: %#v", 3, len(raw), raw)
 	}
 
+	f := convert.NewFields("book", raw, opts...)
 	rawBook := IsRawBook(precision)
 
 	if len(raw) == 3 && rawBook {
-		b = rawTradingPairsBook(raw, rawNumbers)
+		b = rawTradingPairsBook(f, rawNumbers)
 	}
 
 	if len(raw) == 3 && !rawBook {
-		b = tradingPairsBook(raw, rawNumbers)
+		b = tradingPairsBook(f, rawNumbers)
 	}
 
 	if len(raw) >= 4 && rawBook {
-		b = rawFundingPairsBook(raw, rawNumbers)
+		b = rawFundingPairsBook(f, rawNumbers)
 	}
 
 	if len(raw) >= 4 && !rawBook {
-		b = fundingPairsBook(raw, rawNumbers)
+		b = fundingPairsBook(f, rawNumbers)
 	}
 
+	if err = f.Err(); err != nil {
+		return nil, err
+	}
 	b.Symbol = symbol
 
 	return
 }
 
 // FromWSRaw - based on condition will return snapshot of books or single book
-func FromWSRaw(symbol, precision string, data []interface{}) (interface{}, error) {
+func FromWSRaw(symbol, precision string, data []interface{}, opts ...convert.DecodeOptions) (interface{}, error) {
 	if len(data) == 0 {
 		return nil, errors.New("empty data slice")
 	}
 
 	_, isSnapshot := data[0].([]interface{})
 	if isSnapshot {
-		return SnapshotFromRaw(symbol, precision, convert.ToInterfaceArray(data), data)
+		return SnapshotFromRaw(symbol, precision, convert.ToInterfaceArray(data), data, opts...)
 	}
 
-	return FromRaw(symbol, precision, data, data)
+	return FromRaw(symbol, precision, data, data, opts...)
 }
 
-func rawTradingPairsBook(raw []interface{}, rawNumbers interface{}) *Book {
+func rawTradingPairsBook(f *convert.Fields, rawNumbers interface{}) *Book {
 	// [ ORDER_ID, PRICE, AMOUNT ] - raw trading pairs signature
 	var (
 		side   common.OrderSide
@@ -113,8 +144,8 @@ func rawTradingPairsBook(raw []interface{}, rawNumbers interface{}) *Book {
 	)
 
 	rawNumSlice := rawNumbers.([]interface{})
-	price := convert.F64ValOrZero(raw[1])
-	amount := convert.F64ValOrZero(raw[2])
+	price := f.F64(1)
+	amount := f.F64(2)
 
 	if amount > 0 {
 		side = common.Bid
@@ -135,11 +166,11 @@ func rawTradingPairsBook(raw []interface{}, rawNumbers interface{}) *Book {
 		AmountJsNum: convert.FloatToJsonNumber(rawNumSlice[2]),
 		Side:        side,
 		Action:      action,
-		ID:          convert.I64ValOrZero(raw[0]),
+		ID:          f.I64(0),
 	}
 }
 
-func tradingPairsBook(raw []interface{}, rawNumbers interface{}) *Book {
+func tradingPairsBook(f *convert.Fields, rawNumbers interface{}) *Book {
 	// [ PRICE, COUNT, AMOUNT ] - trading pairs signature
 	var (
 		price    float64
@@ -150,12 +181,12 @@ func tradingPairsBook(raw []interface{}, rawNumbers interface{}) *Book {
 	)
 
 	rawNumSlice := rawNumbers.([]interface{})
-	amount := convert.F64ValOrZero(raw[2])
+	amount := f.F64(2)
 	amountNum := convert.FloatToJsonNumber(rawNumSlice[2])
 
-	price = convert.F64ValOrZero(raw[0])
+	price = f.F64(0)
 	priceNum = convert.FloatToJsonNumber(rawNumSlice[0])
-	count = convert.I64ValOrZero(raw[1])
+	count = f.I64(1)
 
 	if amount > 0 {
 		side = common.Bid
@@ -180,28 +211,28 @@ func tradingPairsBook(raw []interface{}, rawNumbers interface{}) *Book {
 	}
 }
 
-func rawFundingPairsBook(raw []interface{}, rawNumbers interface{}) *Book {
+func rawFundingPairsBook(f *convert.Fields, rawNumbers interface{}) *Book {
 	// [ ORDER_ID, PERIOD, RATE, AMOUNT ] - raw funding pairs signature
 	rawNumSlice := rawNumbers.([]interface{})
 
 	return &Book{
-		ID:          convert.I64ValOrZero(raw[0]),
-		Period:      convert.I64ValOrZero(raw[1]),
-		Rate:        convert.F64ValOrZero(raw[2]),
-		Amount:      convert.F64ValOrZero(raw[3]),
+		ID:          f.I64(0),
+		Period:      f.I64(1),
+		Rate:        f.F64(2),
+		Amount:      f.F64(3),
 		AmountJsNum: convert.FloatToJsonNumber(rawNumSlice[3]),
 	}
 }
 
-func fundingPairsBook(raw []interface{}, rawNumbers interface{}) *Book {
+func fundingPairsBook(f *convert.Fields, rawNumbers interface{}) *Book {
 	// [ RATE, PERIOD, COUNT, AMOUNT ], - funding pairs signature
 	rawNumSlice := rawNumbers.([]interface{})
 
 	return &Book{
-		Rate:        convert.F64ValOrZero(raw[0]),
-		Period:      convert.I64ValOrZero(raw[1]),
-		Count:       convert.I64ValOrZero(raw[2]),
-		Amount:      convert.F64ValOrZero(raw[3]),
+		Rate:        f.F64(0),
+		Period:      f.I64(1),
+		Count:       f.I64(2),
+		Amount:      f.F64(3),
 		AmountJsNum: convert.FloatToJsonNumber(rawNumSlice[3]),
 	}
 }
@@ -108,3 +108,87 @@ func TestNewBookFromRaw(t *testing.T) {
 		assert.Equal(t, expected, b)
 	})
 }
+
+func TestFundingFromRaw(t *testing.T) {
+	t.Run("insufficient arguments", func(t *testing.T) {
+		b, err := book.FundingFromRaw("fUSD", "P0", []interface{}{0.0002, 2, 1}, nil)
+		require.NotNil(t, err)
+		require.Nil(t, b)
+	})
+
+	t.Run("aggregated", func(t *testing.T) {
+		payload := []interface{}{0.0002, 2, 5, 1500.5}
+		b, err := book.FundingFromRaw("fUSD", "P0", payload, payload)
+		require.Nil(t, err)
+		assert.Equal(t, &book.FundingBookUpdate{
+			Symbol:      "fUSD",
+			Rate:        0.0002,
+			Period:      2,
+			Count:       5,
+			Amount:      1500.5,
+			AmountJsNum: "1500.5",
+			Side:        common.Ask,
+			Action:      book.BookEntry,
+		}, b)
+
+		payload = []interface{}{0.0002, 30, 0, -1.0}
+		b, err = book.FundingFromRaw("fUSD", "P0", payload, payload)
+		require.Nil(t, err)
+		assert.Equal(t, common.Bid, b.Side)
+		assert.Equal(t, book.BookRemoveEntry, b.Action)
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		payload := []interface{}{645902785, 2, 0.00017, -250.0}
+		b, err := book.FundingFromRaw("fUSD", "R0", payload, payload)
+		require.Nil(t, err)
+		assert.Equal(t, int64(645902785), b.ID)
+		assert.Equal(t, 0.00017, b.Rate)
+		assert.Equal(t, 250.0, b.Amount)
+		assert.Equal(t, common.Bid, b.Side)
+		assert.Equal(t, book.BookEntry, b.Action)
+
+		payload = []interface{}{645902785, 2, 0, 1.0}
+		b, err = book.FundingFromRaw("fUSD", "R0", payload, payload)
+		require.Nil(t, err)
+		assert.Equal(t, book.BookRemoveEntry, b.Action)
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		payload := []interface{}{
+			[]interface{}{0.0002, 2, 5, 1500.5},
+			[]interface{}{0.0001, 2, 1, -100.0},
+		}
+		got, err := book.FundingFromWSRaw("fUSD", "P0", payload)
+		require.Nil(t, err)
+		snap, ok := got.(*book.FundingSnapshot)
+		require.True(t, ok)
+		require.Len(t, snap.Snapshot, 2)
+		assert.Equal(t, "-100", string(snap.Snapshot[1].AmountJsNum))
+	})
+}
+
+func TestEstimateFill(t *testing.T) {
+	levels := []*book.Book{
+		{Price: 101, Amount: 2, Side: common.Ask},
+		{Price: 100, Amount: 1, Side: common.Ask},
+		{Price: 99, Amount: 1, Side: common.Bid},
+		{Price: 98, Amount: 5, Side: common.Bid},
+		{Price: 97, Amount: 5, Side: common.Bid, Action: book.BookRemoveEntry},
+	}
+
+	buy := book.EstimateFill(levels, common.Bid, 2)
+	assert.Equal(t, book.Estimate{Amount: 2, Filled: 2, BestPrice: 100, AvgPrice: 100.5, WorstPrice: 101, Sufficient: true}, buy)
+	assert.InDelta(t, 50, buy.SlippageBps(), 1e-9)
+
+	sell := book.EstimateFill(levels, common.Ask, -8)
+	assert.Equal(t, 8.0, sell.Amount)
+	assert.Equal(t, 6.0, sell.Filled)
+	assert.Equal(t, 98.0, sell.WorstPrice)
+	assert.InDelta(t, (99+98*5)/6.0, sell.AvgPrice, 1e-9)
+	assert.False(t, sell.Sufficient)
+
+	empty := book.EstimateFill(nil, common.Bid, 1)
+	assert.False(t, empty.Sufficient)
+	assert.Equal(t, 0.0, empty.SlippageBps())
+}
@@ -0,0 +1,72 @@
+package book
+
+import (
+	"math"
+	"sort"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// Estimate is the expected execution of an order against a book
+type Estimate struct {
+	Amount     float64 // requested amount
+	Filled     float64 // amount the book can fill, at most Amount
+	BestPrice  float64 // price of the first level taken
+	AvgPrice   float64 // average price of the filled amount
+	WorstPrice float64 // price of the last level taken
+	Sufficient bool    // the book can fill the full amount
+}
+
+// SlippageBps returns the distance of the average price from the best price
+// in basis points
+func (e Estimate) SlippageBps() float64 {
+	if e.BestPrice == 0 {
+		return 0
+	}
+	return math.Abs(e.AvgPrice-e.BestPrice) / e.BestPrice * 1e4
+}
+
+// EstimateFill walks the book for an order of the given side and absolute
+// amount: buys (common.Bid) take the asks from the lowest price, sells
+// (common.Ask) the bids from the highest price. levels may contain both
+// sides in any order, removed entries are skipped.
+func EstimateFill(levels []*Book, side common.OrderSide, amount float64) Estimate {
+	amount = math.Abs(amount)
+	take := common.Ask
+	if side == common.Ask {
+		take = common.Bid
+	}
+
+	var opposite []*Book
+	for _, l := range levels {
+		if l.Side == take && l.Action != BookRemoveEntry && l.Amount > 0 {
+			opposite = append(opposite, l)
+		}
+	}
+	sort.SliceStable(opposite, func(i, j int) bool {
+		if take == common.Bid {
+			return opposite[i].Price > opposite[j].Price
+		}
+		return opposite[i].Price < opposite[j].Price
+	})
+
+	e := Estimate{Amount: amount}
+	var value float64
+	for _, l := range opposite {
+		if e.Filled >= amount {
+			break
+		}
+		if e.BestPrice == 0 {
+			e.BestPrice = l.Price
+		}
+		fill := math.Min(amount-e.Filled, l.Amount)
+		e.Filled += fill
+		value += fill * l.Price
+		e.WorstPrice = l.Price
+	}
+	if e.Filled > 0 {
+		e.AvgPrice = value / e.Filled
+	}
+	e.Sufficient = amount > 0 && e.Filled >= amount
+	return e
+}
@@ -0,0 +1,100 @@
+package book
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// FundingBookUpdate is an entry of a funding book, e.g. fUSD. Offers of
+// lenders are asks, requests of borrowers are bids.
+type FundingBookUpdate struct {
+	Symbol      string
+	ID          int64       // offer ID, raw books only
+	Rate        float64     // daily rate
+	Period      int64       // days
+	Count       int64       // number of offers, aggregated books only
+	Amount      float64     // absolute amount
+	AmountJsNum json.Number // signed amount as json.Number
+	Side        common.OrderSide
+	Action      BookAction
+}
+
+type FundingSnapshot struct {
+	Snapshot []*FundingBookUpdate
+}
+
+// FundingFromRaw decodes a funding book entry, [RATE, PERIOD, COUNT, AMOUNT]
+// for aggregated books and [OFFER_ID, PERIOD, RATE, AMOUNT] for raw books
+func FundingFromRaw(symbol, precision string, raw []interface{}, rawNumbers interface{}, opts ...convert.DecodeOptions) (*FundingBookUpdate, error) {
+	if len(raw) < 4 {
+		return nil, fmt.Errorf("raw slice too short for funding book, expected %d got %d: %#v", 4, len(raw), raw)
+	}
+
+	f := convert.NewFields("funding book", raw, opts...)
+	b := &FundingBookUpdate{Symbol: symbol, Period: f.I64(1)}
+	if IsRawBook(precision) {
+		b.ID = f.I64(0)
+		b.Rate = f.F64(2)
+	} else {
+		b.Rate = f.F64(0)
+		b.Count = f.I64(2)
+	}
+	amount := f.F64(3)
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+
+	b.Amount = math.Abs(amount)
+	if nums, ok := rawNumbers.([]interface{}); ok && len(nums) > 3 {
+		b.AmountJsNum = convert.FloatToJsonNumber(nums[3])
+	}
+	// positive amounts are offered by lenders
+	b.Side = common.Bid
+	if amount > 0 {
+		b.Side = common.Ask
+	}
+	// raw books remove offers with a zero rate, aggregated ones empty levels
+	if (IsRawBook(precision) && b.Rate == 0) || (!IsRawBook(precision) && b.Count == 0) {
+		b.Action = BookRemoveEntry
+	}
+	return b, nil
+}
+
+func FundingSnapshotFromRaw(symbol, precision string, raw [][]interface{}, rawNumbers interface{}, opts ...convert.DecodeOptions) (*FundingSnapshot, error) {
+	if len(raw) <= 0 {
+		return nil, fmt.Errorf("data slice too short for funding book snapshot: %#v", raw)
+	}
+
+	nums, _ := rawNumbers.([]interface{})
+	snap := make([]*FundingBookUpdate, len(raw))
+	for i, v := range raw {
+		var n interface{}
+		if i < len(nums) {
+			n = nums[i]
+		}
+		b, err := FundingFromRaw(symbol, precision, v, n, opts...)
+		if err != nil {
+			return nil, err
+		}
+		snap[i] = b
+	}
+	return &FundingSnapshot{Snapshot: snap}, nil
+}
+
+// FundingFromWSRaw returns a funding book snapshot or a single entry
+func FundingFromWSRaw(symbol, precision string, data []interface{}, opts ...convert.DecodeOptions) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty data slice")
+	}
+
+	_, isSnapshot := data[0].([]interface{})
+	if isSnapshot {
+		return FundingSnapshotFromRaw(symbol, precision, convert.ToInterfaceArray(data), data, opts...)
+	}
+	return FundingFromRaw(symbol, precision, data, data, opts...)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
@@ -20,18 +21,23 @@ type Candle struct {
 	Volume     float64
 }
 
+// Time returns the opening time of the candle
+func (c *Candle) Time() time.Time {
+	return common.Mts(c.MTS).Time()
+}
+
 type Snapshot struct {
 	Snapshot []*Candle
 }
 
-func SnapshotFromRaw(symbol string, resolution common.CandleResolution, raw [][]interface{}) (*Snapshot, error) {
+func SnapshotFromRaw(symbol string, resolution common.CandleResolution, raw [][]interface{}, opts ...convert.DecodeOptions) (*Snapshot, error) {
 	if len(raw) <= 0 {
 		return nil, fmt.Errorf("data slice too short for candle snapshot: %#v", raw)
 	}
 
 	snap := make([]*Candle, 0)
 	for _, f := range raw {
-		c, err := FromRaw(symbol, resolution, f)
+		c, err := FromRaw(symbol, resolution, f, opts...)
 		if err == nil {
 			snap = append(snap, c)
 		}
@@ -40,27 +46,33 @@ func SnapshotFromRaw(symbol string, resolution common.CandleResolution, raw [][]
 	return &Snapshot{Snapshot: snap}, nil
 }
 
-func FromRaw(symbol string, resolution common.CandleResolution, raw []interface{}) (c *Candle, err error) {
+func FromRaw(symbol string, resolution common.CandleResolution, raw []interface{}, opts ...convert.DecodeOptions) (c *Candle, err error) {
 	if len(raw) < 6 {
 		return c, fmt.Errorf("data slice too short for candle, expected %d got %d: %#v", 6, len(raw), raw)
 	}
 
+	f := convert.NewFields("candle", raw, opts...)
+
 	c = &Candle{
 		Symbol:     symbol,
 		Resolution: resolution,
-		MTS:        convert.I64ValOrZero(raw[0]),
-		Open:       convert.F64ValOrZero(raw[1]),
-		Close:      convert.F64ValOrZero(raw[2]),
-		High:       convert.F64ValOrZero(raw[3]),
-		Low:        convert.F64ValOrZero(raw[4]),
-		Volume:     convert.F64ValOrZero(raw[5]),
+		MTS:        f.I64(0),
+		Open:       f.F64(1),
+		Close:      f.F64(2),
+		High:       f.F64(3),
+		Low:        f.F64(4),
+		Volume:     f.F64(5),
+	}
+
+	if err = f.Err(); err != nil {
+		return nil, err
 	}
 
 	return
 }
 
 // FromWSRaw - based on condition will return snapshot of candles or single candle
-func FromWSRaw(key string, data []interface{}) (interface{}, error) {
+func FromWSRaw(key string, data []interface{}, opts ...convert.DecodeOptions) (interface{}, error) {
 	if len(data) == 0 {
 		return nil, errors.New("empty data slice")
 	}
@@ -77,7 +89,7 @@ func FromWSRaw(key string, data []interface{}) (interface{}, error) {
 	symbol := ss[2]
 
 	if isSnapshot {
-		return SnapshotFromRaw(symbol, res, convert.ToInterfaceArray(data))
+		return SnapshotFromRaw(symbol, res, convert.ToInterfaceArray(data), opts...)
 	}
-	return FromRaw(symbol, res, data)
+	return FromRaw(symbol, res, data, opts...)
 }
@@ -0,0 +1,103 @@
+package candle
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// Gap is a run of missing candles in a series, e.g. around a maintenance
+// window or in periods without trades, for which the API sends no candles
+type Gap struct {
+	From          int64 // opening time of the first missing candle in milliseconds
+	To            int64 // opening time of the last missing candle in milliseconds
+	Backfilled    int   // candles fetched again
+	ForwardFilled int   // candles filled with the previous close
+}
+
+// Count returns the number of missing candles of the given period
+func (g Gap) Count(period time.Duration) int {
+	return int((g.To-g.From)/period.Milliseconds()) + 1
+}
+
+// Repaired reports whether all candles of the gap have been filled
+func (g Gap) Repaired(period time.Duration) bool {
+	return g.Backfilled+g.ForwardFilled >= g.Count(period)
+}
+
+// Gaps returns the runs of missing candles between the first and last
+// candle of the series, oldest first. The candles may be in any order.
+// Resolutions without a fixed duration, i.e. 1M, are rejected.
+func Gaps(candles []*Candle, resolution common.CandleResolution) ([]Gap, error) {
+	period, ok := resolution.Duration()
+	if !ok {
+		return nil, fmt.Errorf("%w: resolution %s has no fixed duration", common.ErrBadRequest, resolution)
+	}
+
+	step := period.Milliseconds()
+	gaps := []Gap{}
+	sorted := sortedByTime(candles)
+	for i := 1; i < len(sorted); i++ {
+		prev, cur := sorted[i-1].MTS, sorted[i].MTS
+		if cur-prev > step {
+			gaps = append(gaps, Gap{From: prev + step, To: cur - step})
+		}
+	}
+	return gaps, nil
+}
+
+// ForwardFill returns the series sorted by time with a candle for every
+// missing interval, which opens and closes at the close of the previous
+// candle without volume, along with the filled gaps
+func ForwardFill(candles []*Candle, resolution common.CandleResolution) ([]*Candle, []Gap, error) {
+	gaps, err := Gaps(candles, resolution)
+	if err != nil {
+		return nil, nil, err
+	}
+	period, _ := resolution.Duration()
+	step := period.Milliseconds()
+
+	sorted := sortedByTime(candles)
+	filled := make([]*Candle, 0, len(sorted))
+	for i, c := range sorted {
+		if i > 0 {
+			prev := sorted[i-1]
+			for mts := prev.MTS + step; mts < c.MTS; mts += step {
+				filled = append(filled, &Candle{
+					Symbol:     prev.Symbol,
+					Resolution: prev.Resolution,
+					MTS:        mts,
+					Open:       prev.Close,
+					Close:      prev.Close,
+					High:       prev.Close,
+					Low:        prev.Close,
+				})
+			}
+		}
+		filled = append(filled, c)
+	}
+
+	for i := range gaps {
+		gaps[i].ForwardFilled = gaps[i].Count(period)
+	}
+	return filled, gaps, nil
+}
+
+// sortedByTime returns a copy of the candles sorted by time, keeping the
+// first of candles with the same time
+func sortedByTime(candles []*Candle) []*Candle {
+	sorted := make([]*Candle, len(candles))
+	copy(sorted, candles)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].MTS < sorted[j].MTS })
+
+	out := sorted[:0]
+	for _, c := range sorted {
+		if len(out) > 0 && out[len(out)-1].MTS == c.MTS {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
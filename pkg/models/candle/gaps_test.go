@@ -0,0 +1,55 @@
+package candle_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/candle"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGaps(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cs := []*candle.Candle{
+		minute(start, 5, 12, 13, 14, 11, 4),
+		minute(start, 0, 10, 11, 12, 9, 1),
+		minute(start, 1, 11, 12, 15, 10, 2),
+		minute(start, 1, 11, 12, 15, 10, 2),
+		minute(start, 3, 12, 12, 12, 8, 3),
+	}
+	mts := func(i int) int64 { return minute(start, i, 0, 0, 0, 0, 0).MTS }
+
+	gaps, err := candle.Gaps(cs, common.OneMinute)
+	require.Nil(t, err)
+	assert.Equal(t, []candle.Gap{{From: mts(2), To: mts(2)}, {From: mts(4), To: mts(4)}}, gaps)
+
+	filled, gaps, err := candle.ForwardFill(cs, common.OneMinute)
+	require.Nil(t, err)
+	require.Len(t, filled, 6)
+	for i, c := range filled {
+		assert.Equal(t, mts(i), c.MTS)
+	}
+	assert.Equal(t, &candle.Candle{
+		Symbol:     "tBTCUSD",
+		Resolution: common.OneMinute,
+		MTS:        mts(2),
+		Open:       12,
+		Close:      12,
+		High:       12,
+		Low:        12,
+	}, filled[2])
+	assert.Equal(t, 1, gaps[1].ForwardFilled)
+	assert.True(t, gaps[1].Repaired(time.Minute))
+
+	gaps, err = candle.Gaps([]*candle.Candle{minute(start, 0, 1, 1, 1, 1, 1), minute(start, 10, 1, 1, 1, 1, 1)}, common.OneMinute)
+	require.Nil(t, err)
+	require.Len(t, gaps, 1)
+	assert.Equal(t, 9, gaps[0].Count(time.Minute))
+	assert.False(t, gaps[0].Repaired(time.Minute))
+
+	_, err = candle.Gaps(cs, common.OneMonth)
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
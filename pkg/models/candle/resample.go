@@ -0,0 +1,88 @@
+package candle
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+const day = 24 * time.Hour
+
+// Resample aggregates candles, usually 1m ones, into candles of the given
+// period. Buckets are anchored to midnight in loc, so a period of 24h yields
+// daily candles of that timezone even across daylight saving changes. Periods
+// which are a multiple of a day are aligned on calendar days counted from
+// 1970-01-01. The last candle may cover an incomplete period.
+func Resample(candles []*Candle, period time.Duration, loc *time.Location) ([]*Candle, error) {
+	if period < time.Minute || period%time.Minute != 0 {
+		return nil, fmt.Errorf("%w: period must be a multiple of one minute, got %s", common.ErrBadRequest, period)
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	sorted := make([]*Candle, len(candles))
+	copy(sorted, candles)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].MTS < sorted[j].MTS })
+
+	resolution := resolutionOf(period)
+	out := make([]*Candle, 0)
+	var cur *Candle
+	for _, c := range sorted {
+		start := bucketStart(c.Time(), period, loc).UnixNano() / int64(time.Millisecond)
+		if cur == nil || cur.MTS != start {
+			cur = &Candle{
+				Symbol:     c.Symbol,
+				Resolution: resolution,
+				MTS:        start,
+				Open:       c.Open,
+				Close:      c.Close,
+				High:       c.High,
+				Low:        c.Low,
+				Volume:     c.Volume,
+			}
+			out = append(out, cur)
+			continue
+		}
+
+		cur.Close = c.Close
+		if c.High > cur.High {
+			cur.High = c.High
+		}
+		if c.Low < cur.Low {
+			cur.Low = c.Low
+		}
+		cur.Volume += c.Volume
+	}
+
+	return out, nil
+}
+
+func bucketStart(t time.Time, period time.Duration, loc *time.Location) time.Time {
+	t = t.In(loc)
+	y, m, d := t.Date()
+
+	if period%day == 0 {
+		days := int64(period / day)
+		n := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / int64(day/time.Second)
+		n -= ((n % days) + days) % days
+		return time.Date(1970, 1, 1+int(n), 0, 0, 0, 0, loc)
+	}
+
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	return midnight.Add(t.Sub(midnight) / period * period)
+}
+
+// resolutionOf names the period the way the API names its resolutions
+func resolutionOf(period time.Duration) common.CandleResolution {
+	switch {
+	case period%day == 0:
+		return common.CandleResolution(fmt.Sprintf("%dD", period/day))
+	case period%time.Hour == 0:
+		return common.CandleResolution(fmt.Sprintf("%dh", period/time.Hour))
+	default:
+		return common.CandleResolution(fmt.Sprintf("%dm", period/time.Minute))
+	}
+}
@@ -0,0 +1,97 @@
+package candle_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/candle"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func minute(start time.Time, i int, o, c, h, l, v float64) *candle.Candle {
+	return &candle.Candle{
+		Symbol:     "tBTCUSD",
+		Resolution: common.OneMinute,
+		MTS:        start.Add(time.Duration(i)*time.Minute).UnixNano() / int64(time.Millisecond),
+		Open:       o,
+		Close:      c,
+		High:       h,
+		Low:        l,
+		Volume:     v,
+	}
+}
+
+func TestResample(t *testing.T) {
+	start := time.Date(2020, 1, 1, 3, 58, 0, 0, time.UTC)
+	cs := []*candle.Candle{
+		minute(start, 3, 12, 13, 14, 11, 4),
+		minute(start, 0, 10, 11, 12, 9, 1),
+		minute(start, 1, 11, 12, 15, 10, 2),
+		minute(start, 2, 12, 12, 12, 8, 3),
+	}
+
+	out, err := candle.Resample(cs, 4*time.Hour, time.UTC)
+	require.Nil(t, err)
+	require.Len(t, out, 2)
+
+	assert.Equal(t, &candle.Candle{
+		Symbol:     "tBTCUSD",
+		Resolution: "4h",
+		MTS:        time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond),
+		Open:       10,
+		Close:      12,
+		High:       15,
+		Low:        9,
+		Volume:     3,
+	}, out[0])
+
+	assert.Equal(t, time.Date(2020, 1, 1, 4, 0, 0, 0, time.UTC), out[1].Time().UTC())
+	assert.Equal(t, 12.0, out[1].Open)
+	assert.Equal(t, 13.0, out[1].Close)
+	assert.Equal(t, 8.0, out[1].Low)
+	assert.Equal(t, 7.0, out[1].Volume)
+}
+
+func TestResampleDailyInTimezone(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	start := time.Date(2020, 1, 1, 21, 59, 0, 0, time.UTC)
+	cs := []*candle.Candle{
+		minute(start, 0, 1, 2, 2, 1, 1),
+		minute(start, 1, 2, 3, 3, 2, 1),
+	}
+
+	out, err := candle.Resample(cs, 24*time.Hour, loc)
+	require.Nil(t, err)
+	require.Len(t, out, 2)
+	assert.Equal(t, common.CandleResolution("1D"), out[0].Resolution)
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, loc).Unix(), out[0].Time().Unix())
+	assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, loc).Unix(), out[1].Time().Unix())
+}
+
+func TestResampleDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone data unavailable")
+	}
+
+	// 2020-03-29 has 23 hours in Berlin
+	cs := []*candle.Candle{
+		minute(time.Date(2020, 3, 29, 0, 30, 0, 0, loc), 0, 1, 1, 1, 1, 1),
+		minute(time.Date(2020, 3, 29, 23, 30, 0, 0, loc), 0, 2, 2, 2, 2, 1),
+		minute(time.Date(2020, 3, 30, 0, 30, 0, 0, loc), 0, 3, 3, 3, 3, 1),
+	}
+
+	out, err := candle.Resample(cs, 24*time.Hour, loc)
+	require.Nil(t, err)
+	require.Len(t, out, 2)
+	assert.Equal(t, 2.0, out[0].Volume)
+	assert.Equal(t, time.Date(2020, 3, 30, 0, 0, 0, 0, loc).Unix(), out[1].Time().Unix())
+}
+
+func TestResampleInvalidPeriod(t *testing.T) {
+	_, err := candle.Resample(nil, 90*time.Second, time.UTC)
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
@@ -3,18 +3,22 @@ package common
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 const (
 	OrderFlagHidden               int              = 64
 	OrderFlagClose                int              = 512
+	OrderFlagReduceOnly           int              = 1024
 	OrderFlagPostOnly             int              = 4096
 	OrderFlagOCO                  int              = 16384
+	OrderFlagNoVarRates           int              = 524288
 	Checksum                      int              = 131072
 	OrderStatusActive                              = "ACTIVE"
 	OrderStatusExecuted                            = "EXECUTED"
 	OrderStatusPartiallyFilled                     = "PARTIALLY FILLED"
 	OrderStatusCanceled                            = "CANCELED"
+	OrderStatusPostOnlyCanceled                    = "POSTONLY CANCELED"
 	OrderTypeExchangeLimit                         = "EXCHANGE LIMIT"
 	OrderTypeMarket                                = "MARKET"
 	OrderTypeExchangeMarket                        = "EXCHANGE MARKET"
@@ -35,6 +39,13 @@ const (
 	CreditSizeKey                 StatKey          = "credits.size"
 	CreditSizeSymKey              StatKey          = "credits.size.sym"
 	PositionSizeKey               StatKey          = "pos.size"
+	VolumeOneDayKey               StatKey          = "vol.1d"
+	VolumeSevenDaysKey            StatKey          = "vol.7d"
+	VolumeThirtyDaysKey           StatKey          = "vol.30d"
+	StatSectionLast               StatSection      = "last"
+	StatSectionHistory            StatSection      = "hist"
+	DerivativeStatusType          StatusType       = "deriv"
+	LiquidationStatusType         StatusType       = "liq"
 	Bid                           OrderSide        = 1
 	Ask                           OrderSide        = 2
 	Long                          OrderSide        = 1
@@ -64,10 +75,31 @@ const (
 	FrequencyTwoPerSecond BookFrequency = "F1"
 	// PriceLevelDefault provides a constant default price level for book subscriptions.
 	PriceLevelDefault int = 25
+	// TimeInForceLayout is the datetime format expected by the tif field of orders.
+	TimeInForceLayout = "2006-01-02 15:04:05"
 )
 
+// Sentinel errors shared by the rest and websocket clients. Errors returned by
+// the API are wrapped so that they can be matched with errors.Is.
 var (
-	ErrNotFound = errors.New("not found")
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrMaintenance  = errors.New("platform in maintenance")
+	ErrBadRequest   = errors.New("bad request")
+)
+
+// MaintenanceMode decides how the rest and websocket clients handle write
+// requests while the platform is in maintenance.
+type MaintenanceMode int
+
+const (
+	// MaintenanceAllow sends write requests regardless of maintenance.
+	MaintenanceAllow MaintenanceMode = iota
+	// MaintenanceReject fails write requests with ErrMaintenance.
+	MaintenanceReject
+	// MaintenanceQueue holds write requests until the maintenance ended or
+	// their context is done.
+	MaintenanceQueue
 )
 
 // OrderSide provides a typed set of order sides.
@@ -88,10 +120,28 @@ type QueryLimit int
 
 type PermissionType string
 
+// Mts is a millisecond unix timestamp as used throughout the API.
 type Mts int64
 
+// MtsFromTime converts t into a millisecond timestamp.
+func MtsFromTime(t time.Time) Mts {
+	return Mts(t.UnixNano() / int64(time.Millisecond))
+}
+
+// Time converts the timestamp into time.Time. A zero timestamp is converted
+// into the zero time so that IsZero can be used to check for missing values.
+func (m Mts) Time() time.Time {
+	if m == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(m)*int64(time.Millisecond))
+}
+
 type StatKey string
 
+// StatSection selects the last value or the history of a stat.
+type StatSection string
+
 type StatusType string
 
 type OrderType string
@@ -125,3 +175,33 @@ func CandleResolutionFromString(str string) (CandleResolution, error) {
 	}
 	return OneMinute, fmt.Errorf("could not convert string to resolution: %s", str)
 }
+
+// Duration returns the length of the candles of the resolution. It returns
+// false for OneMonth, whose candles vary in length.
+func (r CandleResolution) Duration() (time.Duration, bool) {
+	switch r {
+	case OneMinute:
+		return time.Minute, true
+	case FiveMinutes:
+		return 5 * time.Minute, true
+	case FifteenMinutes:
+		return 15 * time.Minute, true
+	case ThirtyMinutes:
+		return 30 * time.Minute, true
+	case OneHour:
+		return time.Hour, true
+	case ThreeHours:
+		return 3 * time.Hour, true
+	case SixHours:
+		return 6 * time.Hour, true
+	case TwelveHours:
+		return 12 * time.Hour, true
+	case OneDay:
+		return 24 * time.Hour, true
+	case OneWeek:
+		return 7 * 24 * time.Hour, true
+	case TwoWeeks:
+		return 14 * 24 * time.Hour, true
+	}
+	return 0, false
+}
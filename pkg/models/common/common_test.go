@@ -0,0 +1,25 @@
+package common_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMts(t *testing.T) {
+	t.Run("from time", func(t *testing.T) {
+		tm := time.Unix(1568123933, 123456789)
+		assert.Equal(t, common.Mts(1568123933123), common.MtsFromTime(tm))
+	})
+
+	t.Run("to time", func(t *testing.T) {
+		got := common.Mts(1568123933123).Time()
+		assert.Equal(t, time.Unix(1568123933, 123000000).UTC(), got.UTC())
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		assert.True(t, common.Mts(0).Time().IsZero())
+	})
+}
@@ -0,0 +1,61 @@
+package currency
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+)
+
+const UnderlyingMap ConfigMapping = "pub:map:currency:undl"
+
+// stableConversions are the conversions between fiat and stablecoins offered
+// by transfers, which are not published in the configs
+var stableConversions = [][2]string{
+	{"USD", "UST"},
+}
+
+// Conversions holds the pairs of equivalent currencies which a transfer
+// between wallets can convert between, e.g. UST and USTF0
+type Conversions struct {
+	pairs map[[2]string]bool
+}
+
+// ConversionsFromRaw returns the conversions of the underlying currency
+// config, which maps the collateral currencies of derivatives to their
+// underlying, along with the fiat and stablecoin conversions
+func ConversionsFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (*Conversions, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("data slice too short for currency conversions: %#v", raw)
+	}
+
+	d := &decoder{opts: opts}
+	confs := d.entry("currency conversions", raw)
+
+	cv := &Conversions{pairs: map[[2]string]bool{}}
+	for _, p := range stableConversions {
+		cv.add(p[0], p[1])
+	}
+	for _, e := range d.entries("currency underlying", confs.List(0)) {
+		cv.add(e.S(0), e.S(1))
+	}
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+	return cv, nil
+}
+
+func (cv *Conversions) add(a, b string) {
+	a, b = strings.ToUpper(a), strings.ToUpper(b)
+	if a == "" || b == "" || a == b {
+		return
+	}
+	cv.pairs[[2]string{a, b}] = true
+	cv.pairs[[2]string{b, a}] = true
+}
+
+// Supports reports whether a transfer can convert from one currency into the
+// other
+func (cv *Conversions) Supports(from, to string) bool {
+	return cv.pairs[[2]string{strings.ToUpper(from), strings.ToUpper(to)}]
+}
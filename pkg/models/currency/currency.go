@@ -1,6 +1,11 @@
 package currency
 
-import "strings"
+import (
+	"errors"
+	"strings"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+)
 
 type Conf struct {
 	Currency  string
@@ -26,6 +31,7 @@ const (
 	UnitMap     ConfigMapping = "pub:map:currency:unit"
 	ExplorerMap ConfigMapping = "pub:map:currency:explorer"
 	ExchangeMap ConfigMapping = "pub:list:pair:exchange"
+	FuturesList ConfigMapping = "pub:list:pair:futures"
 )
 
 type RawConf struct {
@@ -33,65 +39,61 @@ type RawConf struct {
 	Data    interface{}
 }
 
-func parseLabelMap(config map[string]Conf, raw []interface{}) {
-	for _, rawLabel := range raw {
-		data := rawLabel.([]interface{})
-		cur := data[0].(string)
+func parseLabelMap(config map[string]Conf, d *decoder, raw []interface{}) {
+	for _, e := range d.entries("currency label", raw) {
+		cur := e.S(0)
 		if val, ok := config[cur]; ok {
 			// add value
-			val.Label = data[1].(string)
+			val.Label = e.S(1)
 			config[cur] = val
 		} else {
 			// create new empty config instance
 			cfg := Conf{}
-			cfg.Label = data[1].(string)
+			cfg.Label = e.S(1)
 			cfg.Currency = cur
 			config[cur] = cfg
 		}
 	}
 }
 
-func parseSymbMap(config map[string]Conf, raw []interface{}) {
-	for _, rawLabel := range raw {
-		data := rawLabel.([]interface{})
-		cur := data[0].(string)
+func parseSymbMap(config map[string]Conf, d *decoder, raw []interface{}) {
+	for _, e := range d.entries("currency symbol", raw) {
+		cur := e.S(0)
 		if val, ok := config[cur]; ok {
 			// add value
-			val.Symbol = data[1].(string)
+			val.Symbol = e.S(1)
 			config[cur] = val
 		} else {
 			// create new empty config instance
 			cfg := Conf{}
-			cfg.Symbol = data[1].(string)
+			cfg.Symbol = e.S(1)
 			cfg.Currency = cur
 			config[cur] = cfg
 		}
 	}
 }
 
-func parseUnitMap(config map[string]Conf, raw []interface{}) {
-	for _, rawLabel := range raw {
-		data := rawLabel.([]interface{})
-		cur := data[0].(string)
+func parseUnitMap(config map[string]Conf, d *decoder, raw []interface{}) {
+	for _, e := range d.entries("currency unit", raw) {
+		cur := e.S(0)
 		if val, ok := config[cur]; ok {
 			// add value
-			val.Unit = data[1].(string)
+			val.Unit = e.S(1)
 			config[cur] = val
 		} else {
 			// create new empty config instance
 			cfg := Conf{}
-			cfg.Unit = data[1].(string)
+			cfg.Unit = e.S(1)
 			cfg.Currency = cur
 			config[cur] = cfg
 		}
 	}
 }
 
-func parseExplorerMap(config map[string]Conf, raw []interface{}) {
-	for _, rawLabel := range raw {
-		data := rawLabel.([]interface{})
-		cur := data[0].(string)
-		explorers := data[1].([]interface{})
+func parseExplorerMap(config map[string]Conf, d *decoder, raw []interface{}) {
+	for _, e := range d.entries("currency explorer", raw) {
+		cur := e.S(0)
+		explorers := d.entry("currency explorer", e.List(1))
 		var cfg Conf
 		if val, ok := config[cur]; ok {
 			cfg = val
@@ -102,26 +104,33 @@ func parseExplorerMap(config map[string]Conf, raw []interface{}) {
 			cfg = cc
 		}
 		ec := ExplorerConf{
-			explorers[0].(string),
-			explorers[1].(string),
-			explorers[2].(string),
+			explorers.S(0),
+			explorers.S(1),
+			explorers.S(2),
 		}
 		cfg.Explorers = ec
 		config[cur] = cfg
 	}
 }
 
-func parseExchangeMap(config map[string]Conf, raw []interface{}) {
-	for _, rs := range raw {
-		symbol := rs.(string)
+func parseExchangeMap(config map[string]Conf, d *decoder, raw []interface{}) {
+	pairs := d.entry("exchange pair", raw)
+	for i := range raw {
+		symbol := pairs.S(i)
 		var base, quote string
 
 		if len(symbol) > 6 {
-			base = strings.Split(symbol, ":")[0]
-			quote = strings.Split(symbol, ":")[1]
-		} else {
+			parts := strings.SplitN(symbol, ":", 2)
+			if len(parts) < 2 {
+				continue
+			}
+			base = parts[0]
+			quote = parts[1]
+		} else if len(symbol) == 6 {
 			base = symbol[3:]
 			quote = symbol[:3]
+		} else {
+			continue
 		}
 
 		// append if base exists in configs
@@ -138,27 +147,32 @@ func parseExchangeMap(config map[string]Conf, raw []interface{}) {
 	}
 }
 
-func FromRaw(raw []RawConf) ([]Conf, error) {
+func FromRaw(raw []RawConf, opts ...convert.DecodeOptions) ([]Conf, error) {
+	d := &decoder{opts: opts}
+	data := make([]interface{}, len(raw))
+	for i, r := range raw {
+		data[i] = r.Data
+	}
+	confs := d.entry("currency configs", data)
+
 	configMap := make(map[string]Conf)
-	for _, r := range raw {
+	for i, r := range raw {
 		switch ConfigMapping(r.Mapping) {
 		case LabelMap:
-			data := r.Data.([]interface{})
-			parseLabelMap(configMap, data)
+			parseLabelMap(configMap, d, confs.List(i))
 		case SymbolMap:
-			data := r.Data.([]interface{})
-			parseSymbMap(configMap, data)
+			parseSymbMap(configMap, d, confs.List(i))
 		case UnitMap:
-			data := r.Data.([]interface{})
-			parseUnitMap(configMap, data)
+			parseUnitMap(configMap, d, confs.List(i))
 		case ExplorerMap:
-			data := r.Data.([]interface{})
-			parseExplorerMap(configMap, data)
+			parseExplorerMap(configMap, d, confs.List(i))
 		case ExchangeMap:
-			data := r.Data.([]interface{})
-			parseExchangeMap(configMap, data)
+			parseExchangeMap(configMap, d, confs.List(i))
 		}
 	}
+	if err := d.err(); err != nil {
+		return nil, err
+	}
 
 	// convert map to array
 	configs := make([]Conf, 0)
@@ -168,3 +182,46 @@ func FromRaw(raw []RawConf) ([]Conf, error) {
 
 	return configs, nil
 }
+
+// decoder reads the configs with the given options, collecting the fields of
+// every list and entry read so their errors are returned together
+type decoder struct {
+	opts   []convert.DecodeOptions
+	fields []*convert.Fields
+}
+
+// entry returns the fields of a single list
+func (d *decoder) entry(parser string, raw []interface{}) *convert.Fields {
+	f := convert.NewFields(parser, raw, d.opts...)
+	d.fields = append(d.fields, f)
+	return f
+}
+
+// entries returns the fields of the key/value entries of a config, skipping
+// entries with less than 2 values
+func (d *decoder) entries(parser string, raw []interface{}) []*convert.Fields {
+	list := d.entry(parser, raw)
+	out := make([]*convert.Fields, 0, len(raw))
+	for i := range raw {
+		if e := list.List(i); len(e) > 1 {
+			out = append(out, d.entry(parser, e))
+		}
+	}
+	return out
+}
+
+// err returns a *convert.DecodeError listing the anomalies of all fields in
+// strict mode
+func (d *decoder) err() error {
+	var anomalies []convert.Anomaly
+	for _, f := range d.fields {
+		var de *convert.DecodeError
+		if errors.As(f.Err(), &de) {
+			anomalies = append(anomalies, de.Anomalies...)
+		}
+	}
+	if len(anomalies) == 0 {
+		return nil
+	}
+	return &convert.DecodeError{Anomalies: anomalies}
+}
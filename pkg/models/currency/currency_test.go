@@ -0,0 +1,70 @@
+package currency_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/currency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromRaw(t *testing.T) {
+	raw := []currency.RawConf{
+		{Mapping: string(currency.LabelMap), Data: []interface{}{
+			[]interface{}{"BTC", "Bitcoin"},
+			[]interface{}{"USD", "US Dollar"},
+		}},
+		{Mapping: string(currency.ExchangeMap), Data: []interface{}{"BTCUSD", "BTC", "TESTBTC:TESTUSD"}},
+	}
+
+	confs, err := currency.FromRaw(raw)
+	require.Nil(t, err)
+	require.Len(t, confs, 2)
+	for _, c := range confs {
+		assert.Equal(t, []string{"BTCUSD"}, c.Pairs)
+	}
+}
+
+func TestFromRawStrict(t *testing.T) {
+	raw := []currency.RawConf{
+		{Mapping: string(currency.LabelMap), Data: []interface{}{
+			[]interface{}{"BTC", 1.0},
+			"USD",
+		}},
+	}
+
+	confs, err := currency.FromRaw(raw)
+	require.Nil(t, err)
+	require.Len(t, confs, 1)
+
+	_, err = currency.FromRaw(raw, convert.DecodeOptions{Strict: true})
+	var de *convert.DecodeError
+	require.True(t, errors.As(err, &de))
+	require.Len(t, de.Anomalies, 2)
+	assert.Equal(t, 1, de.Anomalies[0].Index)
+	assert.Equal(t, "USD", de.Anomalies[0].Value)
+	assert.Equal(t, 1, de.Anomalies[1].Index)
+	assert.Equal(t, 1.0, de.Anomalies[1].Value)
+}
+
+func TestSymbolDetailsFromRawStrict(t *testing.T) {
+	raw := []interface{}{
+		[]interface{}{
+			[]interface{}{"BTCUSD", []interface{}{nil, nil, nil, "0.0002", "2000.0", nil, nil, nil, 0.2, "x"}},
+		},
+	}
+
+	details, err := currency.SymbolDetailsFromRaw(raw)
+	require.Nil(t, err)
+	assert.Equal(t, 0.0002, details["tBTCUSD"].MinOrderSize)
+	assert.Equal(t, 2000.0, details["tBTCUSD"].MaxOrderSize)
+
+	_, err = currency.SymbolDetailsFromRaw(raw, convert.DecodeOptions{Strict: true})
+	var de *convert.DecodeError
+	require.True(t, errors.As(err, &de))
+	require.Len(t, de.Anomalies, 1)
+	assert.Equal(t, "pair info", de.Anomalies[0].Parser)
+	assert.Equal(t, 9, de.Anomalies[0].Index)
+}
@@ -0,0 +1,164 @@
+package currency
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/fixed"
+)
+
+const (
+	TxFeeMap      ConfigMapping = "pub:map:currency:tx:fee"
+	TxMethodMap   ConfigMapping = "pub:map:tx:method"
+	TxStatusInfo  ConfigMapping = "pub:info:tx:status"
+	DefaultDigits               = 8
+)
+
+// Info holds the metadata needed to move funds of a currency
+type Info struct {
+	Currency string
+	Label    string
+	// Precision is the number of decimals of amounts, which is the same for
+	// all currencies
+	Precision int
+	// WithdrawalFee is the fee of the methods of the currency which do not
+	// publish a fee of their own, see MethodFees
+	WithdrawalFee float64
+	// MethodFees holds the fees published for single methods, e.g. one
+	// network of a token served on several, keyed by method
+	MethodFees map[string]float64
+	// MinWithdrawal is the smallest amount which results in a positive
+	// withdrawal, as no explicit minimum is published
+	MinWithdrawal        float64
+	Methods              []string
+	DepositActive        bool
+	WithdrawalActive     bool
+	DepositConfirmations int64
+	PaymentIDRequired    bool
+}
+
+// InfoFromRaw joins the label, tx fee, tx method and tx status configs, as
+// returned by the conf endpoint in that order, into the Info of each currency
+func InfoFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (map[string]*Info, error) {
+	if len(raw) < 4 {
+		return nil, fmt.Errorf("data slice too short for currency info: %#v", raw)
+	}
+
+	d := &decoder{opts: opts}
+	confs := d.entry("currency info", raw)
+
+	infos := map[string]*Info{}
+	get := func(cur string) *Info {
+		i, ok := infos[cur]
+		if !ok {
+			i = &Info{Currency: cur, Precision: DefaultDigits}
+			infos[cur] = i
+		}
+		return i
+	}
+
+	for _, e := range d.entries("currency label", confs.List(0)) {
+		get(e.S(0)).Label = e.S(1)
+	}
+
+	// tx status is reported per method
+	status := map[string]*convert.Fields{}
+	for _, e := range d.entries("tx status", confs.List(3)) {
+		status[strings.ToUpper(e.S(0))] = e
+	}
+
+	served := map[string][]string{}
+	for _, e := range d.entries("tx method", confs.List(2)) {
+		method := strings.ToUpper(e.S(0))
+		curs := e.List(1)
+		cf := d.entry("tx method", curs)
+		for c := range curs {
+			i := get(cf.S(c))
+			i.Methods = append(i.Methods, method)
+			served[method] = append(served[method], i.Currency)
+
+			s, ok := status[method]
+			if !ok {
+				continue
+			}
+			i.DepositActive = i.DepositActive || s.I64(1) == 1
+			i.WithdrawalActive = i.WithdrawalActive || s.I64(2) == 1
+			if s.Len() > 6 {
+				i.PaymentIDRequired = i.PaymentIDRequired || s.I64(5) == 1 || s.I64(6) == 1
+			}
+			if s.Len() > 11 {
+				if n := s.I64(11); n > i.DepositConfirmations {
+					i.DepositConfirmations = n
+				}
+			}
+		}
+	}
+
+	// fees are keyed by currency, or by method where a method has its own
+	for _, e := range d.entries("tx fee", confs.List(1)) {
+		fee := e.List(1)
+		if len(fee) < 2 {
+			continue
+		}
+		key := e.S(0)
+		amount := d.entry("tx fee", fee).F64(1)
+		if curs, ok := served[strings.ToUpper(key)]; ok {
+			for _, c := range curs {
+				i := get(c)
+				if i.MethodFees == nil {
+					i.MethodFees = map[string]float64{}
+				}
+				i.MethodFees[strings.ToUpper(key)] = amount
+			}
+			continue
+		}
+		i := get(key)
+		i.WithdrawalFee = amount
+		i.MinWithdrawal = amount
+	}
+
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+// HasMethod reports whether the currency can be moved with the given
+// method, e.g. BITCOIN
+func (i *Info) HasMethod(method string) bool {
+	for _, m := range i.Methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fee returns the withdrawal fee of the given method, e.g. BITCOIN
+func (i *Info) Fee(method string) float64 {
+	if fee, ok := i.MethodFees[strings.ToUpper(method)]; ok {
+		return fee
+	}
+	return i.WithdrawalFee
+}
+
+// NetWithdrawal returns the amount received when withdrawing amount with the
+// given method, i.e. the amount less the fee of the method, or 0 if the fee
+// exceeds it. The result keeps the decimals of amount and fee.
+func (i *Info) NetWithdrawal(method string, amount float64) float64 {
+	fee := i.Fee(method)
+	scale := decimals(amount)
+	if d := decimals(fee); d > scale {
+		scale = d
+	}
+	if scale > int(fixed.MaxScale) {
+		scale = int(fixed.MaxScale)
+	}
+	net := fixed.RoundAmount(amount-fee, fixed.Scale(scale))
+	if net < 0 {
+		return 0
+	}
+	return net
+}
@@ -0,0 +1,111 @@
+package currency
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/fixed"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+const (
+	PairInfoMap    ConfigMapping = "pub:info:pair"
+	FuturesInfoMap ConfigMapping = "pub:info:pair:futures"
+)
+
+// SymbolDetails holds the order constraints of a trading symbol
+type SymbolDetails struct {
+	Symbol       string
+	MinOrderSize float64
+	MaxOrderSize float64 // zero if unlimited
+	// PricePrecision is the number of significant digits of prices and
+	// AmountPrecision the number of decimals of amounts
+	PricePrecision  int
+	AmountPrecision int
+	InitialMargin   float64 // share of the position required to open it
+	MinimumMargin   float64 // share of the position required to keep it
+}
+
+// SymbolDetailsFromRaw maps the pair and futures info configs, as returned by
+// the conf endpoint in that order, to the details of each trading symbol
+func SymbolDetailsFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (map[string]*SymbolDetails, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("data slice too short for symbol details: %#v", raw)
+	}
+
+	d := &decoder{opts: opts}
+	confs := d.entry("symbol details", raw)
+
+	details := map[string]*SymbolDetails{}
+	for l := range raw {
+		for _, e := range d.entries("pair info", confs.List(l)) {
+			info := e.List(1)
+			if len(info) < 10 {
+				continue
+			}
+			f := d.entry("pair info", info)
+			sym := common.TradingPrefix + e.S(0)
+			details[sym] = &SymbolDetails{
+				Symbol:          sym,
+				MinOrderSize:    f.Number(3),
+				MaxOrderSize:    f.Number(4),
+				PricePrecision:  fixed.PriceSignificantDigits,
+				AmountPrecision: int(fixed.DefaultAmountScale),
+				InitialMargin:   f.Number(8),
+				MinimumMargin:   f.Number(9),
+			}
+		}
+	}
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// Validate returns an error wrapping common.ErrBadRequest if an order of the
+// amount at the prices violates the constraints of the symbol. Zero prices,
+// e.g. of market orders, are not checked.
+func (d *SymbolDetails) Validate(amount float64, prices ...float64) error {
+	size := math.Abs(amount)
+	if size < d.MinOrderSize {
+		return fmt.Errorf("%w: amount %v of %s is below the minimum order size %v", common.ErrBadRequest, amount, d.Symbol, d.MinOrderSize)
+	}
+	if d.MaxOrderSize > 0 && size > d.MaxOrderSize {
+		return fmt.Errorf("%w: amount %v of %s is above the maximum order size %v", common.ErrBadRequest, amount, d.Symbol, d.MaxOrderSize)
+	}
+	if decimals(size) > d.AmountPrecision {
+		return fmt.Errorf("%w: amount %v of %s has more than %d decimals", common.ErrBadRequest, amount, d.Symbol, d.AmountPrecision)
+	}
+	for _, p := range prices {
+		if p != 0 && significantDigits(p) > d.PricePrecision {
+			return fmt.Errorf("%w: price %v of %s has more than %d significant digits", common.ErrBadRequest, p, d.Symbol, d.PricePrecision)
+		}
+	}
+	return nil
+}
+
+// RoundPrice rounds price to the price precision of the symbol
+func (d *SymbolDetails) RoundPrice(price float64) float64 {
+	return fixed.RoundPrice(price, d.PricePrecision)
+}
+
+// RoundAmount rounds amount to the amount precision of the symbol
+func (d *SymbolDetails) RoundAmount(amount float64) float64 {
+	return fixed.RoundAmount(amount, fixed.Scale(d.AmountPrecision))
+}
+
+func decimals(f float64) int {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+func significantDigits(f float64) int {
+	mantissa := strings.SplitN(strconv.FormatFloat(math.Abs(f), 'e', -1, 64), "e", 2)[0]
+	return len(strings.Replace(mantissa, ".", "", 1))
+}
@@ -0,0 +1,75 @@
+package depositaddress
+
+import (
+	"fmt"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+)
+
+// Address is a deposit address of the account. Currencies which need a tag,
+// e.g. XRP, share a pool address and Address holds the tag.
+type Address struct {
+	Method      string
+	Currency    string
+	Address     string
+	PoolAddress string
+}
+
+// FromNotificationRaw returns the address of a deposit address notification
+func FromNotificationRaw(raw []interface{}, opts ...convert.DecodeOptions) (*Address, error) {
+	if len(raw) < 5 {
+		return nil, fmt.Errorf("data slice too short for deposit address: %#v", raw)
+	}
+
+	f := convert.NewFields("deposit address", raw, opts...)
+	a := &Address{
+		Method:   f.S(1),
+		Currency: f.S(2),
+		Address:  f.S(4),
+	}
+	if len(raw) > 5 {
+		a.PoolAddress = f.S(5)
+	}
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// FromRaw returns an entry of the deposit address listing of a method
+func FromRaw(method string, raw []interface{}, opts ...convert.DecodeOptions) (*Address, error) {
+	if len(raw) < 4 {
+		return nil, fmt.Errorf("data slice too short for deposit address: %#v", raw)
+	}
+
+	f := convert.NewFields("deposit address", raw, opts...)
+	a := &Address{
+		Method:   method,
+		Currency: f.S(1),
+		Address:  f.S(3),
+	}
+	if len(raw) > 4 {
+		a.PoolAddress = f.S(4)
+	}
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// SnapshotFromRaw returns the deposit address listing of a method
+func SnapshotFromRaw(method string, raw []interface{}, opts ...convert.DecodeOptions) ([]*Address, error) {
+	addrs := make([]*Address, 0, len(raw))
+	for _, r := range raw {
+		row, ok := r.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected deposit address: %#v", r)
+		}
+		a, err := FromRaw(method, row, opts...)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, a)
+	}
+	return addrs, nil
+}
@@ -0,0 +1,38 @@
+package depositaddress_test
+
+import (
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/depositaddress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromNotificationRaw(t *testing.T) {
+	_, err := depositaddress.FromNotificationRaw([]interface{}{nil, "ripple", "XRP"})
+	require.NotNil(t, err)
+
+	a, err := depositaddress.FromNotificationRaw([]interface{}{nil, "ripple", "XRP", nil, "123456", "rLW9gnQo7BQhU6igk5keqYnH3TVrCxGRzm"})
+	require.Nil(t, err)
+	assert.Equal(t, &depositaddress.Address{
+		Method:      "ripple",
+		Currency:    "XRP",
+		Address:     "123456",
+		PoolAddress: "rLW9gnQo7BQhU6igk5keqYnH3TVrCxGRzm",
+	}, a)
+}
+
+func TestSnapshotFromRaw(t *testing.T) {
+	addrs, err := depositaddress.SnapshotFromRaw("bitcoin", []interface{}{
+		[]interface{}{nil, "BTC", nil, "bc1qa"},
+		[]interface{}{nil, "BTC", nil, "bc1qb", nil},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, []*depositaddress.Address{
+		{Method: "bitcoin", Currency: "BTC", Address: "bc1qa"},
+		{Method: "bitcoin", Currency: "BTC", Address: "bc1qb"},
+	}, addrs)
+
+	_, err = depositaddress.SnapshotFromRaw("bitcoin", []interface{}{"bc1qa"})
+	require.NotNil(t, err)
+}
@@ -24,70 +24,82 @@ type DerivativeStatus struct {
 	OpenInterest         float64
 }
 
-func FromWsRaw(symbol string, raw []interface{}) (*DerivativeStatus, error) {
+func FromWsRaw(symbol string, raw []interface{}, opts ...convert.DecodeOptions) (*DerivativeStatus, error) {
 	if len(raw) < 18 {
 		return nil, fmt.Errorf("unexpected data slice length for derivative status: %#v", raw)
 	}
 
+	f := convert.NewFields("derivative status", raw, opts...)
+
 	ds := &DerivativeStatus{
 		Symbol: symbol,
-		MTS:    convert.I64ValOrZero(raw[0]),
+		MTS:    f.I64(0),
 		// placeholder
-		Price:     convert.F64ValOrZero(raw[2]),
-		SpotPrice: convert.F64ValOrZero(raw[3]),
+		Price:     f.F64(2),
+		SpotPrice: f.F64(3),
 		// placeholder
-		InsuranceFundBalance: convert.F64ValOrZero(raw[5]),
+		InsuranceFundBalance: f.F64(5),
 		// placeholder
-		FundingEventMTS: convert.I64ValOrZero(raw[7]),
-		FundingAccrued:  convert.F64ValOrZero(raw[8]),
-		FundingStep:     convert.F64ValOrZero(raw[9]),
+		FundingEventMTS: f.I64(7),
+		FundingAccrued:  f.F64(8),
+		FundingStep:     f.F64(9),
 		// placeholder
-		CurrentFunding: convert.F64ValOrZero(raw[11]),
+		CurrentFunding: f.F64(11),
 		// placeholder
 		// placeholder
-		MarkPrice: convert.F64ValOrZero(raw[14]),
+		MarkPrice: f.F64(14),
 		// placeholder
 		// placeholder
-		OpenInterest: convert.F64ValOrZero(raw[17]),
+		OpenInterest: f.F64(17),
+	}
+
+	if err := f.Err(); err != nil {
+		return nil, err
 	}
 
 	return ds, nil
 }
 
-func FromRaw(raw []interface{}) (*DerivativeStatus, error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (*DerivativeStatus, error) {
 	if len(raw) < 19 {
 		return nil, fmt.Errorf("unexpected data slice length for derivative status: %#v", raw)
 	}
 
+	f := convert.NewFields("derivative status", raw, opts...)
+
 	ds := &DerivativeStatus{
-		Symbol: convert.SValOrEmpty(raw[0]),
-		MTS:    convert.I64ValOrZero(raw[1]),
+		Symbol: f.S(0),
+		MTS:    f.I64(1),
 		// placeholder
-		Price:     convert.F64ValOrZero(raw[3]),
-		SpotPrice: convert.F64ValOrZero(raw[4]),
+		Price:     f.F64(3),
+		SpotPrice: f.F64(4),
 		// placeholder
-		InsuranceFundBalance: convert.F64ValOrZero(raw[6]),
+		InsuranceFundBalance: f.F64(6),
 		// placeholder
-		FundingEventMTS: convert.I64ValOrZero(raw[8]),
-		FundingAccrued:  convert.F64ValOrZero(raw[9]),
-		FundingStep:     convert.F64ValOrZero(raw[10]),
+		FundingEventMTS: f.I64(8),
+		FundingAccrued:  f.F64(9),
+		FundingStep:     f.F64(10),
 		// placeholder
-		CurrentFunding: convert.F64ValOrZero(raw[12]),
+		CurrentFunding: f.F64(12),
 		// placeholder
 		// placeholder
-		MarkPrice: convert.F64ValOrZero(raw[15]),
+		MarkPrice: f.F64(15),
 		// placeholder
 		// placeholder
-		OpenInterest: convert.F64ValOrZero(raw[18]),
+		OpenInterest: f.F64(18),
+	}
+
+	if err := f.Err(); err != nil {
+		return nil, err
 	}
 
 	return ds, nil
 }
 
-func SnapshotFromRaw(raw [][]interface{}) (*Snapshot, error) {
+func SnapshotFromRaw(raw [][]interface{}, opts ...convert.DecodeOptions) (*Snapshot, error) {
 	snapshot := make([]*DerivativeStatus, len(raw))
 	for i, rStatus := range raw {
-		pStatus, err := FromRaw(rStatus)
+		pStatus, err := FromRaw(rStatus, opts...)
 		if err != nil {
 			return nil, err
 		}
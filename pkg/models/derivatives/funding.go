@@ -0,0 +1,60 @@
+package derivatives
+
+// FundingRate is the funding of a perpetual contract at a point in time.
+// Rates are applied per funding event, positive rates are paid by longs.
+type FundingRate struct {
+	Symbol         string
+	MTS            int64
+	Current        float64 // rate of the last funding event
+	Predicted      float64 // rate accrued for the next funding event
+	NextFundingMTS int64
+	MarkPrice      float64
+	SpotPrice      float64
+}
+
+// FundingRateFromStatus extracts the funding of a derivative status
+func FundingRateFromStatus(ds *DerivativeStatus) *FundingRate {
+	return &FundingRate{
+		Symbol:         ds.Symbol,
+		MTS:            ds.MTS,
+		Current:        ds.CurrentFunding,
+		Predicted:      ds.FundingAccrued,
+		NextFundingMTS: ds.FundingEventMTS,
+		MarkPrice:      ds.MarkPrice,
+		SpotPrice:      ds.SpotPrice,
+	}
+}
+
+// FundingRateSeries is a series of funding rates in the order returned by the
+// platform
+type FundingRateSeries []*FundingRate
+
+// Mean returns the mean of the current rates, or 0 for an empty series
+func (s FundingRateSeries) Mean() float64 {
+	if len(s) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, r := range s {
+		sum += r.Current
+	}
+	return sum / float64(len(s))
+}
+
+// Basis returns the mean relative difference of the mark price to the spot
+// price, or 0 for a series without spot prices
+func (s FundingRateSeries) Basis() float64 {
+	var sum float64
+	var n int
+	for _, r := range s {
+		if r.SpotPrice == 0 {
+			continue
+		}
+		sum += (r.MarkPrice - r.SpotPrice) / r.SpotPrice
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
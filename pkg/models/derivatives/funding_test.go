@@ -0,0 +1,35 @@
+package derivatives_test
+
+import (
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/derivatives"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFundingRateSeries(t *testing.T) {
+	ds := &derivatives.DerivativeStatus{
+		Symbol:          "tBTCF0:USTF0",
+		MTS:             1617000000000,
+		SpotPrice:       100,
+		MarkPrice:       101,
+		FundingEventMTS: 1617001000000,
+		FundingAccrued:  0.0002,
+		CurrentFunding:  0.0001,
+	}
+	fr := derivatives.FundingRateFromStatus(ds)
+	assert.Equal(t, &derivatives.FundingRate{
+		Symbol:         "tBTCF0:USTF0",
+		MTS:            1617000000000,
+		Current:        0.0001,
+		Predicted:      0.0002,
+		NextFundingMTS: 1617001000000,
+		MarkPrice:      101,
+		SpotPrice:      100,
+	}, fr)
+
+	s := derivatives.FundingRateSeries{fr, {Current: 0.0003, MarkPrice: 99, SpotPrice: 100}, {Current: 0.0002}}
+	assert.InDelta(t, 0.0002, s.Mean(), 1e-12)
+	assert.InDelta(t, 0, s.Basis(), 1e-12)
+	assert.Equal(t, 0.0, derivatives.FundingRateSeries{}.Mean())
+}
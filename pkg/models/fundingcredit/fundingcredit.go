@@ -37,65 +37,71 @@ type Snapshot struct {
 	Snapshot []*Credit
 }
 
-func FromRaw(raw []interface{}) (c *Credit, err error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (c *Credit, err error) {
 	if len(raw) < 22 {
 		return c, fmt.Errorf("data slice too short for funding credit: %#v", raw)
 	}
 
+	f := convert.NewFields("funding credit", raw, opts...)
+
 	c = &Credit{
-		ID:            convert.I64ValOrZero(raw[0]),
-		Symbol:        convert.SValOrEmpty(raw[1]),
-		Side:          convert.ToInt(raw[2]),
-		MTSCreated:    convert.I64ValOrZero(raw[3]),
-		MTSUpdated:    convert.I64ValOrZero(raw[4]),
-		Amount:        convert.F64ValOrZero(raw[5]),
-		Status:        convert.SValOrEmpty(raw[7]),
-		RateType:      convert.SValOrEmpty(raw[8]),
-		Rate:          convert.F64ValOrZero(raw[11]),
-		Period:        convert.I64ValOrZero(raw[12]),
-		MTSOpened:     convert.I64ValOrZero(raw[13]),
-		MTSLastPayout: convert.I64ValOrZero(raw[14]),
-		Notify:        convert.BValOrFalse(raw[15]),
-		Hidden:        convert.BValOrFalse(raw[16]),
-		Insure:        convert.BValOrFalse(raw[17]),
-		Renew:         convert.BValOrFalse(raw[18]),
-		RateReal:      convert.F64ValOrZero(raw[19]),
-		NoClose:       convert.BValOrFalse(raw[20]),
-		PositionPair:  convert.SValOrEmpty(raw[21]),
+		ID:            f.I64(0),
+		Symbol:        f.S(1),
+		Side:          f.Int(2),
+		MTSCreated:    f.I64(3),
+		MTSUpdated:    f.I64(4),
+		Amount:        f.F64(5),
+		Status:        f.S(7),
+		RateType:      f.S(8),
+		Rate:          f.F64(11),
+		Period:        f.I64(12),
+		MTSOpened:     f.I64(13),
+		MTSLastPayout: f.I64(14),
+		Notify:        f.Bool(15),
+		Hidden:        f.Bool(16),
+		Insure:        f.Bool(17),
+		Renew:         f.Bool(18),
+		RateReal:      f.F64(19),
+		NoClose:       f.Bool(20),
+		PositionPair:  f.S(21),
 	}
 
 	if flags, ok := raw[6].(map[string]interface{}); ok {
 		c.Flags = flags
 	}
 
+	if err = f.Err(); err != nil {
+		return nil, err
+	}
+
 	return
 }
 
-func NewFromRaw(raw []interface{}) (New, error) {
-	c, err := FromRaw(raw)
+func NewFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (New, error) {
+	c, err := FromRaw(raw, opts...)
 	if err != nil {
 		return New{}, nil
 	}
 	return New(*c), nil
 }
 
-func UpdateFromRaw(raw []interface{}) (Update, error) {
-	c, err := FromRaw(raw)
+func UpdateFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (Update, error) {
+	c, err := FromRaw(raw, opts...)
 	if err != nil {
 		return Update{}, nil
 	}
 	return Update(*c), nil
 }
 
-func CancelFromRaw(raw []interface{}) (Cancel, error) {
-	c, err := FromRaw(raw)
+func CancelFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (Cancel, error) {
+	c, err := FromRaw(raw, opts...)
 	if err != nil {
 		return Cancel{}, nil
 	}
 	return Cancel(*c), nil
 }
 
-func SnapshotFromRaw(raw []interface{}) (snap *Snapshot, err error) {
+func SnapshotFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (snap *Snapshot, err error) {
 	if len(raw) == 0 {
 		return snap, fmt.Errorf("data slice too short for funding credit: %#v", raw)
 	}
@@ -105,7 +111,7 @@ func SnapshotFromRaw(raw []interface{}) (snap *Snapshot, err error) {
 	case []interface{}:
 		for _, v := range raw {
 			if l, ok := v.([]interface{}); ok {
-				o, err := FromRaw(l)
+				o, err := FromRaw(l, opts...)
 				if err != nil {
 					return snap, err
 				}
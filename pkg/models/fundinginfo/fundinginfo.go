@@ -14,7 +14,7 @@ type FundingInfo struct {
 	DurationLend float64
 }
 
-func FromRaw(raw []interface{}) (fi *FundingInfo, err error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (fi *FundingInfo, err error) {
 	if len(raw) < 3 { // "sym", symbol, data
 		return fi, fmt.Errorf("data slice too short for funding info: %#v", raw)
 	}
@@ -33,12 +33,18 @@ func FromRaw(raw []interface{}) (fi *FundingInfo, err error) {
 		return fi, fmt.Errorf("data too short: %#v", data)
 	}
 
+	f := convert.NewFields("funding info", data, opts...)
+
 	fi = &FundingInfo{
 		Symbol:       sym,
-		YieldLoan:    convert.F64ValOrZero(data[0]),
-		YieldLend:    convert.F64ValOrZero(data[1]),
-		DurationLoan: convert.F64ValOrZero(data[2]),
-		DurationLend: convert.F64ValOrZero(data[3]),
+		YieldLoan:    f.F64(0),
+		YieldLend:    f.F64(1),
+		DurationLoan: f.F64(2),
+		DurationLend: f.F64(3),
+	}
+
+	if err = f.Err(); err != nil {
+		return nil, err
 	}
 
 	return
@@ -1,8 +1,10 @@
 package fundinginfo_test
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/fundinginfo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -17,6 +19,22 @@ func TestNewFundingInfoFromRaw(t *testing.T) {
 		require.Nil(t, got)
 	})
 
+	t.Run("strict", func(t *testing.T) {
+		payload := []interface{}{"sym", "fUST", []interface{}{0.0024, "0.0024", 1.95, 1.48}}
+
+		got, err := fundinginfo.FromRaw(payload)
+		require.Nil(t, err)
+		require.NotNil(t, got)
+
+		got, err = fundinginfo.FromRaw(payload, convert.DecodeOptions{Strict: true})
+		require.Nil(t, got)
+		var de *convert.DecodeError
+		require.True(t, errors.As(err, &de))
+		require.Len(t, de.Anomalies, 1)
+		assert.Equal(t, "funding info", de.Anomalies[0].Parser)
+		assert.Equal(t, 1, de.Anomalies[0].Index)
+	})
+
 	t.Run("valid arguments", func(t *testing.T) {
 		payload := []interface{}{
 			"sym",
@@ -32,61 +32,67 @@ type Snapshot struct {
 	Snapshot []*Offer
 }
 
-func FromRaw(raw []interface{}) (o *Offer, err error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (o *Offer, err error) {
 	if len(raw) < 21 {
 		return o, fmt.Errorf("data slice too short for funding offer: %#v", raw)
 	}
 
+	f := convert.NewFields("funding offer", raw, opts...)
+
 	o = &Offer{
-		ID:         convert.I64ValOrZero(raw[0]),
-		Symbol:     convert.SValOrEmpty(raw[1]),
-		MTSCreated: convert.I64ValOrZero(raw[2]),
-		MTSUpdated: convert.I64ValOrZero(raw[3]),
-		Amount:     convert.F64ValOrZero(raw[4]),
-		AmountOrig: convert.F64ValOrZero(raw[5]),
-		Type:       convert.SValOrEmpty(raw[6]),
-		Status:     convert.SValOrEmpty(raw[10]),
-		Rate:       convert.F64ValOrZero(raw[14]),
-		Period:     convert.I64ValOrZero(raw[15]),
-		Notify:     convert.BValOrFalse(raw[16]),
-		Hidden:     convert.BValOrFalse(raw[17]),
-		Insure:     convert.BValOrFalse(raw[18]),
-		Renew:      convert.BValOrFalse(raw[19]),
-		RateReal:   convert.F64ValOrZero(raw[20]),
+		ID:         f.I64(0),
+		Symbol:     f.S(1),
+		MTSCreated: f.I64(2),
+		MTSUpdated: f.I64(3),
+		Amount:     f.F64(4),
+		AmountOrig: f.F64(5),
+		Type:       f.S(6),
+		Status:     f.S(10),
+		Rate:       f.F64(14),
+		Period:     f.I64(15),
+		Notify:     f.Bool(16),
+		Hidden:     f.Bool(17),
+		Insure:     f.Bool(18),
+		Renew:      f.Bool(19),
+		RateReal:   f.F64(20),
 	}
 
 	if flags, ok := raw[9].(map[string]interface{}); ok {
 		o.Flags = flags
 	}
 
+	if err = f.Err(); err != nil {
+		return nil, err
+	}
+
 	return
 }
 
-func CancelFromRaw(raw []interface{}) (Cancel, error) {
-	o, err := FromRaw(raw)
+func CancelFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (Cancel, error) {
+	o, err := FromRaw(raw, opts...)
 	if err != nil {
 		return Cancel{}, err
 	}
 	return Cancel(*o), nil
 }
 
-func NewFromRaw(raw []interface{}) (New, error) {
-	o, err := FromRaw(raw)
+func NewFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (New, error) {
+	o, err := FromRaw(raw, opts...)
 	if err != nil {
 		return New{}, err
 	}
 	return New(*o), nil
 }
 
-func UpdateFromRaw(raw []interface{}) (Update, error) {
-	o, err := FromRaw(raw)
+func UpdateFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (Update, error) {
+	o, err := FromRaw(raw, opts...)
 	if err != nil {
 		return Update{}, err
 	}
 	return Update(*o), nil
 }
 
-func SnapshotFromRaw(raw []interface{}) (snap *Snapshot, err error) {
+func SnapshotFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (snap *Snapshot, err error) {
 	if len(raw) == 0 {
 		return snap, fmt.Errorf("data slice too short for funding offer: %#v", raw)
 	}
@@ -96,7 +102,7 @@ func SnapshotFromRaw(raw []interface{}) (snap *Snapshot, err error) {
 	case []interface{}:
 		for _, v := range raw {
 			if l, ok := v.([]interface{}); ok {
-				o, err := FromRaw(l)
+				o, err := FromRaw(l, opts...)
 				if err != nil {
 					return snap, err
 				}
@@ -24,26 +24,32 @@ type Snapshot struct {
 }
 type HistoricalSnapshot Snapshot
 
-func FromRaw(raw []interface{}) (ft *FundingTrade, err error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (ft *FundingTrade, err error) {
 	if len(raw) < 8 {
 		return ft, fmt.Errorf("data slice too short for funding trade: %#v", raw)
 	}
 
+	f := convert.NewFields("funding trade", raw, opts...)
+
 	ft = &FundingTrade{
-		ID:         convert.I64ValOrZero(raw[0]),
-		Symbol:     convert.SValOrEmpty(raw[1]),
-		MTSCreated: convert.I64ValOrZero(raw[2]),
-		OfferID:    convert.I64ValOrZero(raw[3]),
-		Amount:     convert.F64ValOrZero(raw[4]),
-		Rate:       convert.F64ValOrZero(raw[5]),
-		Period:     convert.I64ValOrZero(raw[6]),
-		Maker:      convert.I64ValOrZero(raw[7]),
+		ID:         f.I64(0),
+		Symbol:     f.S(1),
+		MTSCreated: f.I64(2),
+		OfferID:    f.I64(3),
+		Amount:     f.F64(4),
+		Rate:       f.F64(5),
+		Period:     f.I64(6),
+		Maker:      f.I64(7),
+	}
+
+	if err = f.Err(); err != nil {
+		return nil, err
 	}
 
 	return
 }
 
-func SnapshotFromRaw(raw []interface{}) (snap *Snapshot, err error) {
+func SnapshotFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (snap *Snapshot, err error) {
 	if len(raw) == 0 {
 		return snap, fmt.Errorf("data slice too short for funding trade")
 	}
@@ -53,7 +59,7 @@ func SnapshotFromRaw(raw []interface{}) (snap *Snapshot, err error) {
 	case []interface{}:
 		for _, v := range raw {
 			if l, ok := v.([]interface{}); ok {
-				o, err := FromRaw(l)
+				o, err := FromRaw(l, opts...)
 				if err != nil {
 					return snap, err
 				}
@@ -70,8 +76,8 @@ func SnapshotFromRaw(raw []interface{}) (snap *Snapshot, err error) {
 	return
 }
 
-func HistoricalSnapshotFromRaw(raw []interface{}) (HistoricalSnapshot, error) {
-	s, err := SnapshotFromRaw(raw)
+func HistoricalSnapshotFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (HistoricalSnapshot, error) {
+	s, err := SnapshotFromRaw(raw, opts...)
 	if err != nil {
 		return HistoricalSnapshot{}, err
 	}
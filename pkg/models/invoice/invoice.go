@@ -21,16 +21,22 @@ var invoiceFields = map[string]int{
 
 // NewFromRaw takes in slice of interfaces and converts them to
 // pointer to Invoice
-func NewFromRaw(raw []interface{}) (*Invoice, error) {
+func NewFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (*Invoice, error) {
 	if len(raw) < 5 {
 		return nil, fmt.Errorf("data slice too short for Invoice: %#v", raw)
 	}
 
+	f := convert.NewFields("invoice", raw, opts...)
+
 	invc := &Invoice{}
 
-	invc.InvoiceHash = convert.SValOrEmpty(raw[invoiceFields["InvoiceHash"]])
-	invc.Invoice = convert.SValOrEmpty(raw[invoiceFields["Invoice"]])
-	invc.Amount = convert.SValOrEmpty(raw[invoiceFields["Amount"]])
+	invc.InvoiceHash = f.S(invoiceFields["InvoiceHash"])
+	invc.Invoice = f.S(invoiceFields["Invoice"])
+	invc.Amount = f.S(invoiceFields["Amount"])
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
 
 	return invc, nil
 }
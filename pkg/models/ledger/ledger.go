@@ -2,8 +2,10 @@ package ledger
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 )
 
 type Ledger struct {
@@ -18,26 +20,37 @@ type Ledger struct {
 	Description string
 }
 
+// Time returns the time of the ledger entry
+func (l *Ledger) Time() time.Time {
+	return common.Mts(l.MTS).Time()
+}
+
 type Snapshot struct {
 	Snapshot []*Ledger
 }
 
-type transformerFn func(raw []interface{}) (w *Ledger, err error)
+type transformerFn func(raw []interface{}, opts ...convert.DecodeOptions) (w *Ledger, err error)
 
 // FromRaw takes the raw list of values as returned from the websocket
 // service and tries to convert it into an Ledger.
-func FromRaw(raw []interface{}) (o *Ledger, err error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (o *Ledger, err error) {
 	if len(raw) < 9 {
 		return o, fmt.Errorf("data slice too short for ledger: %#v", raw)
 	}
 
+	f := convert.NewFields("ledger", raw, opts...)
+
 	o = &Ledger{
-		ID:          convert.I64ValOrZero(raw[0]),
-		Currency:    convert.SValOrEmpty(raw[1]),
-		MTS:         convert.I64ValOrZero(raw[3]),
-		Amount:      convert.F64ValOrZero(raw[5]),
-		Balance:     convert.F64ValOrZero(raw[6]),
-		Description: convert.SValOrEmpty(raw[8]),
+		ID:          f.I64(0),
+		Currency:    f.S(1),
+		MTS:         f.I64(3),
+		Amount:      f.F64(5),
+		Balance:     f.F64(6),
+		Description: f.S(8),
+	}
+
+	if err = f.Err(); err != nil {
+		return nil, err
 	}
 
 	return
@@ -45,7 +58,7 @@ func FromRaw(raw []interface{}) (o *Ledger, err error) {
 
 // SnapshotFromRaw takes a raw list of values as returned from the websocket
 // service and tries to convert it into an Snapshot.
-func SnapshotFromRaw(raw []interface{}, t transformerFn) (s *Snapshot, err error) {
+func SnapshotFromRaw(raw []interface{}, t transformerFn, opts ...convert.DecodeOptions) (s *Snapshot, err error) {
 	if len(raw) == 0 {
 		return s, fmt.Errorf("data slice too short for ledgers: %#v", raw)
 	}
@@ -55,7 +68,7 @@ func SnapshotFromRaw(raw []interface{}, t transformerFn) (s *Snapshot, err error
 	case []interface{}:
 		for _, v := range raw {
 			if l, ok := v.([]interface{}); ok {
-				o, err := t(l)
+				o, err := t(l, opts...)
 				if err != nil {
 					return s, err
 				}
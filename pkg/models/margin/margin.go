@@ -24,7 +24,7 @@ type InfoUpdate struct {
 
 // FromRaw returns either a InfoBase or InfoUpdate, since
 // the Margin Info is split up into a base and per symbol parts.
-func FromRaw(raw []interface{}) (o interface{}, err error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (o interface{}, err error) {
 	if len(raw) < 2 {
 		return o, fmt.Errorf("data slice too short for margin info base: %#v", raw)
 	}
@@ -40,7 +40,7 @@ func FromRaw(raw []interface{}) (o interface{}, err error) {
 			return o, fmt.Errorf("expected margin info array in second position for margin info but got %#v", raw)
 		}
 
-		return baseFromRaw(data)
+		return baseFromRaw(data, opts...)
 	}
 
 	if len(raw) > 2 && typ == "sym" { // This should be ["sym", SYMBOL, [...]]
@@ -54,39 +54,51 @@ func FromRaw(raw []interface{}) (o interface{}, err error) {
 			return o, fmt.Errorf("expected margin info array in third position for margin info update but got %#v", raw)
 		}
 
-		return updateFromRaw(symbol, data)
+		return updateFromRaw(symbol, data, opts...)
 	}
 
 	return nil, fmt.Errorf("invalid margin info type in %#v", raw)
 }
 
-func updateFromRaw(symbol string, raw []interface{}) (o *InfoUpdate, err error) {
+func updateFromRaw(symbol string, raw []interface{}, opts ...convert.DecodeOptions) (o *InfoUpdate, err error) {
 	if len(raw) < 4 {
 		return o, fmt.Errorf("data slice too short for margin info update: %#v", raw)
 	}
 
+	f := convert.NewFields("margin info update", raw, opts...)
+
 	o = &InfoUpdate{
 		Symbol:          symbol,
-		TradableBalance: convert.F64ValOrZero(raw[0]),
-		GrossBalance:    convert.F64ValOrZero(raw[1]),
-		Buy:             convert.F64ValOrZero(raw[2]),
-		Sell:            convert.F64ValOrZero(raw[3]),
+		TradableBalance: f.F64(0),
+		GrossBalance:    f.F64(1),
+		Buy:             f.F64(2),
+		Sell:            f.F64(3),
+	}
+
+	if err = f.Err(); err != nil {
+		return nil, err
 	}
 
 	return
 }
 
-func baseFromRaw(raw []interface{}) (ib *InfoBase, err error) {
+func baseFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (ib *InfoBase, err error) {
 	if len(raw) < 5 {
 		return ib, fmt.Errorf("data slice too short for margin info base: %#v", raw)
 	}
 
+	f := convert.NewFields("margin info base", raw, opts...)
+
 	ib = &InfoBase{
-		UserProfitLoss: convert.F64ValOrZero(raw[0]),
-		UserSwaps:      convert.F64ValOrZero(raw[1]),
-		MarginBalance:  convert.F64ValOrZero(raw[2]),
-		MarginNet:      convert.F64ValOrZero(raw[3]),
-		MarginRequired: convert.F64ValOrZero(raw[4]),
+		UserProfitLoss: f.F64(0),
+		UserSwaps:      f.F64(1),
+		MarginBalance:  f.F64(2),
+		MarginNet:      f.F64(3),
+		MarginRequired: f.F64(4),
+	}
+
+	if err = f.Err(); err != nil {
+		return nil, err
 	}
 
 	return
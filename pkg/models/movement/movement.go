@@ -0,0 +1,81 @@
+package movement
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// Movement is a deposit or withdrawal, as returned by the movements history
+// and announced by deposit notifications of the websocket api
+type Movement struct {
+	ID                      int64
+	Currency                string
+	CurrencyName            string
+	MtsStarted              int64
+	MtsUpdated              int64
+	Status                  Status
+	Amount                  float64 // negative for withdrawals
+	Fees                    float64
+	DestinationAddress      string
+	TransactionID           string
+	WithdrawTransactionNote string
+}
+
+// StartedAt returns the time the movement was initiated
+func (m Movement) StartedAt() time.Time {
+	return common.Mts(m.MtsStarted).Time()
+}
+
+// UpdatedAt returns the time of the last status change of the movement
+func (m Movement) UpdatedAt() time.Time {
+	return common.Mts(m.MtsUpdated).Time()
+}
+
+// rawLength is the number of fields of a movement
+const rawLength = 22
+
+// FromRaw decodes a movement
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (*Movement, error) {
+	if len(raw) < rawLength {
+		return nil, fmt.Errorf("data slice too short for movement: %#v", raw)
+	}
+
+	f := convert.NewFields("movement", raw, opts...)
+	m := &Movement{
+		ID:                      f.I64(0),
+		Currency:                f.S(1),
+		CurrencyName:            f.S(2),
+		MtsStarted:              f.I64(5),
+		MtsUpdated:              f.I64(6),
+		Status:                  ParseStatus(f.S(9)),
+		Amount:                  f.F64(12),
+		Fees:                    f.F64(13),
+		DestinationAddress:      f.S(16),
+		TransactionID:           f.S(20),
+		WithdrawTransactionNote: f.S(21),
+	}
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SnapshotFromRaw decodes a list of movements
+func SnapshotFromRaw(raw []interface{}, opts ...convert.DecodeOptions) ([]Movement, error) {
+	ms := make([]Movement, 0, len(raw))
+	for _, item := range raw {
+		v, ok := item.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected movement but got %#v", item)
+		}
+		m, err := FromRaw(v, opts...)
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, *m)
+	}
+	return ms, nil
+}
@@ -0,0 +1,56 @@
+package movement_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/movement"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromRaw(t *testing.T) {
+	raw := []interface{}{
+		13105603.0, "ETH", "ETHEREUM", nil, nil, 1569348774000.0, 1569348774000.0, nil, nil, "COMPLETED", nil, nil,
+		0.26300954, -0.00135, nil, nil, "0x5f3ac27a8ba", nil, nil, nil, "0x3f4dbf42e1", nil,
+	}
+	m, err := movement.FromRaw(raw)
+	require.Nil(t, err)
+	assert.Equal(t, &movement.Movement{
+		ID:                 13105603,
+		Currency:           "ETH",
+		CurrencyName:       "ETHEREUM",
+		MtsStarted:         1569348774000,
+		MtsUpdated:         1569348774000,
+		Status:             movement.StatusCompleted,
+		Amount:             0.26300954,
+		Fees:               -0.00135,
+		DestinationAddress: "0x5f3ac27a8ba",
+		TransactionID:      "0x3f4dbf42e1",
+	}, m)
+
+	_, err = movement.FromRaw(raw[:21])
+	assert.NotNil(t, err)
+
+	ms, err := movement.SnapshotFromRaw([]interface{}{raw, raw})
+	require.Nil(t, err)
+	assert.Len(t, ms, 2)
+}
+
+func TestFromRawStrict(t *testing.T) {
+	raw := []interface{}{
+		13105603.0, "ETH", "ETHEREUM", nil, nil, 1569348774000.0, 1569348774000.0, nil, nil, "COMPLETED", nil, nil,
+		"0.26300954", -0.00135, nil, nil, "0x5f3ac27a8ba", nil, nil, nil, "0x3f4dbf42e1", nil,
+	}
+	m, err := movement.FromRaw(raw)
+	require.Nil(t, err)
+	assert.Equal(t, 0.0, m.Amount)
+
+	_, err = movement.SnapshotFromRaw([]interface{}{raw}, convert.DecodeOptions{Strict: true})
+	var de *convert.DecodeError
+	require.True(t, errors.As(err, &de))
+	require.Len(t, de.Anomalies, 1)
+	assert.Equal(t, "movement", de.Anomalies[0].Parser)
+	assert.Equal(t, 12, de.Anomalies[0].Index)
+}
@@ -0,0 +1,54 @@
+package movement
+
+import "strings"
+
+// Status is the state of a deposit or withdrawal
+type Status string
+
+const (
+	StatusUserEmailed     Status = "USER EMAILED"
+	StatusUserApproved    Status = "USER APPROVED"
+	StatusPendingReview   Status = "PENDING REVIEW"
+	StatusPendingApproval Status = "PENDING APPROVAL"
+	StatusApproved        Status = "APPROVED"
+	StatusProcessing      Status = "PROCESSING"
+	StatusSending         Status = "SENDING"
+	StatusProcessed       Status = "PROCESSED"
+	StatusUnconfirmed     Status = "UNCONFIRMED"
+	StatusCompleted       Status = "COMPLETED"
+	StatusCanceled        Status = "CANCELED"
+	StatusFailed          Status = "FAILED"
+)
+
+// ParseStatus normalizes a status as returned by the API
+func ParseStatus(s string) Status {
+	return Status(strings.ToUpper(strings.TrimSpace(s)))
+}
+
+// IsFinal reports whether the movement won't change anymore
+func (s Status) IsFinal() bool {
+	switch s {
+	case StatusCompleted, StatusCanceled, StatusFailed:
+		return true
+	}
+	return false
+}
+
+// IsSuccessful reports whether the funds have been moved
+func (s Status) IsSuccessful() bool {
+	return s == StatusCompleted
+}
+
+// IsFailed reports whether the movement ended without moving funds
+func (s Status) IsFailed() bool {
+	return s == StatusCanceled || s == StatusFailed
+}
+
+// IsPending reports whether the movement is still in progress
+func (s Status) IsPending() bool {
+	return s != "" && !s.IsFinal()
+}
+
+func (s Status) String() string {
+	return string(s)
+}
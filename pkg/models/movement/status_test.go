@@ -0,0 +1,28 @@
+package movement_test
+
+import (
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/movement"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	assert.Equal(t, movement.StatusPendingReview, movement.ParseStatus(" pending review"))
+
+	for _, s := range []movement.Status{movement.StatusCompleted, movement.StatusCanceled, movement.StatusFailed} {
+		assert.True(t, s.IsFinal(), s)
+		assert.False(t, s.IsPending(), s)
+	}
+	assert.True(t, movement.StatusCompleted.IsSuccessful())
+	assert.False(t, movement.StatusCompleted.IsFailed())
+	assert.True(t, movement.StatusCanceled.IsFailed())
+	assert.False(t, movement.StatusFailed.IsSuccessful())
+
+	for _, s := range []movement.Status{movement.StatusPendingReview, movement.StatusApproved, movement.StatusSending, movement.StatusUnconfirmed} {
+		assert.False(t, s.IsFinal(), s)
+		assert.True(t, s.IsPending(), s)
+		assert.False(t, s.IsSuccessful(), s)
+	}
+	assert.False(t, movement.Status("").IsPending())
+}
@@ -2,9 +2,14 @@ package notification
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/depositaddress"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/fundingoffer"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/movement"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/position"
 )
@@ -15,22 +20,65 @@ type Notification struct {
 	MessageID  int64
 	NotifyInfo interface{}
 	Code       int64
-	Status     string
+	Status     Status
 	Text       string
 }
 
-func FromRaw(raw []interface{}) (n *Notification, err error) {
+// Status is the outcome of the request reported by a notification
+type Status string
+
+const (
+	StatusSuccess Status = "SUCCESS"
+	StatusError   Status = "ERROR"
+	StatusFailure Status = "FAILURE"
+	StatusInfo    Status = "INFO"
+)
+
+// IsError reports whether the status reports a rejected request
+func (s Status) IsError() bool {
+	return s == StatusError || s == StatusFailure
+}
+
+// Error is returned for a notification reporting a rejected request, e.g. an
+// order below the minimum size, with the text of the exchange
+type Error struct {
+	Notification *Notification
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Notification.Type, strings.ToLower(string(e.Notification.Status)), e.Notification.Text)
+}
+
+// Err returns an *Error if the notification reports a rejected request, nil
+// otherwise
+func (n *Notification) Err() error {
+	if n.Status.IsError() {
+		return &Error{Notification: n}
+	}
+	return nil
+}
+
+// Time returns the time the notification was created
+func (n *Notification) Time() time.Time {
+	return common.Mts(n.MTS).Time()
+}
+
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (n *Notification, err error) {
 	if len(raw) < 8 {
 		return n, fmt.Errorf("data slice too short for notification: %#v", raw)
 	}
 
+	f := convert.NewFields("notification", raw, opts...)
 	n = &Notification{
-		MTS:       convert.I64ValOrZero(raw[0]),
-		Type:      convert.SValOrEmpty(raw[1]),
-		MessageID: convert.I64ValOrZero(raw[2]),
-		Code:      convert.I64ValOrZero(raw[5]),
-		Status:    convert.SValOrEmpty(raw[6]),
-		Text:      convert.SValOrEmpty(raw[7]),
+		MTS:       f.I64(0),
+		Type:      f.S(1),
+		MessageID: f.I64(2),
+		Code:      f.I64(5),
+		Status:    Status(f.S(6)),
+		Text:      f.S(7),
+	}
+	if err = f.Err(); err != nil {
+		return nil, err
 	}
 
 	// raw[4] = notify info
@@ -51,26 +99,33 @@ func FromRaw(raw []interface{}) (n *Notification, err error) {
 		// will be a set of orders if created via rest
 		// this is to accommodate OCO orders
 		if _, isSnapshot := nraw[0].([]interface{}); isSnapshot {
-			n.NotifyInfo, err = order.SnapshotFromRaw(nraw)
+			n.NotifyInfo, err = order.SnapshotFromRaw(nraw, opts...)
 			return
 		}
 
-		n.NotifyInfo, err = order.NewFromRaw(nraw)
+		n.NotifyInfo, err = order.NewFromRaw(nraw, opts...)
 		return
 	case "ou-req", "ou":
-		n.NotifyInfo, err = order.UpdateFromRaw(nraw)
+		n.NotifyInfo, err = order.UpdateFromRaw(nraw, opts...)
 		return
 	case "oc-req":
-		n.NotifyInfo, err = order.CancelFromRaw(nraw)
+		n.NotifyInfo, err = order.CancelFromRaw(nraw, opts...)
 		return
 	case "fon-req":
-		n.NotifyInfo, err = fundingoffer.NewFromRaw(nraw)
+		n.NotifyInfo, err = fundingoffer.NewFromRaw(nraw, opts...)
 		return
 	case "foc-req":
-		n.NotifyInfo, err = fundingoffer.CancelFromRaw(nraw)
+		n.NotifyInfo, err = fundingoffer.CancelFromRaw(nraw, opts...)
+		return
+	case "acc_dep":
+		n.NotifyInfo, err = depositaddress.FromNotificationRaw(nraw, opts...)
 		return
 	case "pm-req", "pc":
-		n.NotifyInfo, err = position.CancelFromRaw(nraw)
+		n.NotifyInfo, err = position.CancelFromRaw(nraw, opts...)
+		return
+	case "deposit_new", "deposit_complete":
+		// decoded like the movements history
+		n.NotifyInfo, err = movement.FromRaw(nraw, opts...)
 		return
 	default:
 		n.NotifyInfo = raw[4]
@@ -2,9 +2,12 @@ package notification_test
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/fundingoffer"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/movement"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
 	"github.com/stretchr/testify/assert"
@@ -230,3 +233,42 @@ func TestNotificationMapping(t *testing.T) {
 		})
 	}
 }
+
+func TestNotificationErr(t *testing.T) {
+	n, err := notification.FromRaw([]interface{}{1568742390999.0, "acc_wd-req", nil, nil, nil, nil, "ERROR", "Invalid bitcoin address (abc)"})
+	require.Nil(t, err)
+	assert.Equal(t, notification.StatusError, n.Status)
+
+	var ne *notification.Error
+	require.True(t, errors.As(n.Err(), &ne))
+	assert.Equal(t, n, ne.Notification)
+	assert.Equal(t, "acc_wd-req error: Invalid bitcoin address (abc)", ne.Error())
+
+	n.Status = notification.StatusFailure
+	assert.NotNil(t, n.Err())
+	n.Status = notification.StatusSuccess
+	assert.Nil(t, n.Err())
+}
+
+func TestNotificationDeposit(t *testing.T) {
+	dep := []interface{}{
+		13105603.0, "ETH", "ETHEREUM", nil, nil, 1569348774000.0, 1569348774000.0, nil, nil, "PROCESSING", nil, nil,
+		"0.263", 0.0, nil, nil, "0x5f3ac27a8ba", nil, nil, nil, "0x3f4dbf42e1", nil,
+	}
+	raw := []interface{}{1569348774000.0, "deposit_new", nil, nil, dep, nil, "SUCCESS", "Deposit of 0.263 ETH"}
+
+	n, err := notification.FromRaw(raw)
+	require.Nil(t, err)
+	m, ok := n.NotifyInfo.(*movement.Movement)
+	require.True(t, ok)
+	assert.Equal(t, int64(13105603), m.ID)
+
+	_, err = notification.FromRaw(raw, convert.DecodeOptions{Strict: true})
+	var de *convert.DecodeError
+	require.True(t, errors.As(err, &de))
+	assert.Equal(t, "movement", de.Anomalies[0].Parser)
+
+	raw[4] = dep[:21]
+	_, err = notification.FromRaw(raw)
+	assert.NotNil(t, err)
+}
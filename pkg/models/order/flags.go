@@ -0,0 +1,86 @@
+package order
+
+import "github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+
+// FlagsBuilder composes the flags bitmask of an order, e.g.:
+//
+//	flags := order.Flags().Hidden().PostOnly().Build()
+type FlagsBuilder struct {
+	flags int
+}
+
+// Flags returns an empty flags builder
+func Flags() *FlagsBuilder {
+	return &FlagsBuilder{}
+}
+
+// Hidden hides the order from the public order book
+func (fb *FlagsBuilder) Hidden() *FlagsBuilder {
+	return fb.set(common.OrderFlagHidden)
+}
+
+// Close closes the position if the order is executed
+func (fb *FlagsBuilder) Close() *FlagsBuilder {
+	return fb.set(common.OrderFlagClose)
+}
+
+// ReduceOnly ensures the order does not open or increase a position
+func (fb *FlagsBuilder) ReduceOnly() *FlagsBuilder {
+	return fb.set(common.OrderFlagReduceOnly)
+}
+
+// PostOnly cancels the order if it would match at placement
+func (fb *FlagsBuilder) PostOnly() *FlagsBuilder {
+	return fb.set(common.OrderFlagPostOnly)
+}
+
+// OCO places a one-cancels-other stop order along with the order
+func (fb *FlagsBuilder) OCO() *FlagsBuilder {
+	return fb.set(common.OrderFlagOCO)
+}
+
+// NoVarRates excludes variable rate funding offers from matching the order
+func (fb *FlagsBuilder) NoVarRates() *FlagsBuilder {
+	return fb.set(common.OrderFlagNoVarRates)
+}
+
+// Build returns the resulting bitmask
+func (fb *FlagsBuilder) Build() int {
+	return fb.flags
+}
+
+func (fb *FlagsBuilder) set(flag int) *FlagsBuilder {
+	fb.flags |= flag
+	return fb
+}
+
+// FlagSet is the decoded representation of the flags bitmask of an order
+type FlagSet struct {
+	Hidden     bool
+	Close      bool
+	ReduceOnly bool
+	PostOnly   bool
+	OCO        bool
+	NoVarRates bool
+}
+
+// DecodeFlags decodes a flags bitmask into a FlagSet
+func DecodeFlags(flags int64) FlagSet {
+	has := func(flag int) bool {
+		return flags&int64(flag) != 0
+	}
+
+	return FlagSet{
+		Hidden:     has(common.OrderFlagHidden),
+		Close:      has(common.OrderFlagClose),
+		ReduceOnly: has(common.OrderFlagReduceOnly),
+		PostOnly:   has(common.OrderFlagPostOnly),
+		OCO:        has(common.OrderFlagOCO),
+		NoVarRates: has(common.OrderFlagNoVarRates),
+	}
+}
+
+// FlagSet returns the decoded flags of the order
+func (o *Order) FlagSet() FlagSet {
+	return DecodeFlags(o.Flags)
+}
@@ -0,0 +1,38 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlags(t *testing.T) {
+	t.Run("builds bitmask", func(t *testing.T) {
+		got := order.Flags().Hidden().PostOnly().Hidden().Build()
+		assert.Equal(t, common.OrderFlagHidden|common.OrderFlagPostOnly, got)
+	})
+
+	t.Run("empty bitmask", func(t *testing.T) {
+		assert.Equal(t, 0, order.Flags().Build())
+	})
+
+	t.Run("decodes bitmask", func(t *testing.T) {
+		flags := order.Flags().ReduceOnly().OCO().NoVarRates().Build()
+		o := order.Order{Flags: int64(flags)}
+		assert.Equal(t, order.FlagSet{ReduceOnly: true, OCO: true, NoVarRates: true}, o.FlagSet())
+	})
+
+	t.Run("raw flags merged with booleans", func(t *testing.T) {
+		nr := order.NewRequest{
+			Symbol:   "tBTCUSD",
+			Hidden:   true,
+			PostOnly: true,
+			Flags:    order.Flags().Hidden().ReduceOnly().Build(),
+		}
+		got, err := nr.ToJSON()
+		assert.Nil(t, err)
+		assert.Contains(t, string(got), `"flags":5184`)
+	})
+}
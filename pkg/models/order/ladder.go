@@ -0,0 +1,151 @@
+package order
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// MaxLadderSize is the maximum number of orders accepted by a single
+// order/multi request.
+const MaxLadderSize = 75
+
+// Distribution determines how the total amount of a ladder is split among
+// its orders.
+type Distribution string
+
+const (
+	// DistributionFlat assigns the same amount to every order.
+	DistributionFlat Distribution = "flat"
+	// DistributionLinear increases the amount linearly from the first to the
+	// last price of the ladder.
+	DistributionLinear Distribution = "linear"
+	// DistributionExponential multiplies the amount by Factor from one order
+	// to the next.
+	DistributionExponential Distribution = "exponential"
+)
+
+// Ladder describes a set of limit orders spread evenly between two prices,
+// e.g. to buy 1 BTC in 5 orders from 9000 down to 8600 with growing size:
+//
+//	l := order.Ladder{
+//		Symbol:       "tBTCUSD",
+//		Amount:       1,
+//		From:         9000,
+//		To:           8600,
+//		Count:        5,
+//		Distribution: order.DistributionLinear,
+//	}
+type Ladder struct {
+	GID          int64
+	Symbol       string
+	Type         string  // defaults to EXCHANGE LIMIT
+	Amount       float64 // total signed amount of all orders
+	From         float64 // price of the first order
+	To           float64 // price of the last order
+	Count        int
+	Distribution Distribution // defaults to DistributionFlat
+	Factor       float64      // growth factor for DistributionExponential, defaults to 2
+	Hidden       bool
+	PostOnly     bool
+}
+
+var gidCounter = time.Now().Unix()
+
+// NewGID returns a group id which is unique within the process.
+func NewGID() int64 {
+	return atomic.AddInt64(&gidCounter, 1)
+}
+
+// Build returns the orders of the ladder, all sharing the ladder GID.
+func (l Ladder) Build() ([]NewRequest, error) {
+	if err := l.validate(); err != nil {
+		return nil, err
+	}
+
+	orderType := l.Type
+	if orderType == "" {
+		orderType = common.OrderTypeExchangeLimit
+	}
+
+	weights := l.weights()
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+
+	orders := make([]NewRequest, l.Count)
+	var allocated float64
+	for i := range orders {
+		price := l.From
+		if l.Count > 1 {
+			price = l.From + (l.To-l.From)*float64(i)/float64(l.Count-1)
+		}
+
+		amount := l.Amount * weights[i] / total
+		if i == l.Count-1 {
+			// assign the remainder to avoid rounding drift
+			amount = l.Amount - allocated
+		}
+		allocated += amount
+
+		orders[i] = NewRequest{
+			GID:      l.GID,
+			Type:     orderType,
+			Symbol:   l.Symbol,
+			Amount:   amount,
+			Price:    price,
+			Hidden:   l.Hidden,
+			PostOnly: l.PostOnly,
+		}
+	}
+
+	return orders, nil
+}
+
+func (l Ladder) validate() error {
+	if l.Symbol == "" {
+		return fmt.Errorf("%w: symbol is required", common.ErrBadRequest)
+	}
+	if l.Amount == 0 {
+		return fmt.Errorf("%w: amount must not be zero", common.ErrBadRequest)
+	}
+	if l.From <= 0 || l.To <= 0 {
+		return fmt.Errorf("%w: prices must be positive", common.ErrBadRequest)
+	}
+	if l.Count < 1 || l.Count > MaxLadderSize {
+		return fmt.Errorf("%w: count must be between 1 and %d", common.ErrBadRequest, MaxLadderSize)
+	}
+	switch l.Distribution {
+	case "", DistributionFlat, DistributionLinear, DistributionExponential:
+	default:
+		return fmt.Errorf("%w: unknown distribution %q", common.ErrBadRequest, l.Distribution)
+	}
+	if l.Factor < 0 {
+		return fmt.Errorf("%w: factor must not be negative", common.ErrBadRequest)
+	}
+	return nil
+}
+
+func (l Ladder) weights() []float64 {
+	factor := l.Factor
+	if factor == 0 {
+		factor = 2
+	}
+
+	w := make([]float64, l.Count)
+	for i := range w {
+		switch l.Distribution {
+		case DistributionLinear:
+			w[i] = float64(i + 1)
+		case DistributionExponential:
+			w[i] = math.Pow(factor, float64(i))
+		default:
+			w[i] = 1
+		}
+	}
+	return w
+}
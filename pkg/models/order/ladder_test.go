@@ -0,0 +1,78 @@
+package order_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLadderBuild(t *testing.T) {
+	l := order.Ladder{
+		GID:    7,
+		Symbol: "tBTCUSD",
+		Amount: 1,
+		From:   9000,
+		To:     8600,
+		Count:  5,
+	}
+
+	amounts := func(orders []order.NewRequest) []float64 {
+		out := make([]float64, len(orders))
+		for i, o := range orders {
+			out[i] = o.Amount
+		}
+		return out
+	}
+
+	t.Run("flat", func(t *testing.T) {
+		orders, err := l.Build()
+		require.Nil(t, err)
+		require.Len(t, orders, 5)
+
+		for i, p := range []float64{9000, 8900, 8800, 8700, 8600} {
+			assert.Equal(t, p, orders[i].Price)
+			assert.Equal(t, int64(7), orders[i].GID)
+			assert.Equal(t, common.OrderTypeExchangeLimit, orders[i].Type)
+		}
+		assert.InDeltaSlice(t, []float64{0.2, 0.2, 0.2, 0.2, 0.2}, amounts(orders), 1e-9)
+	})
+
+	t.Run("linear", func(t *testing.T) {
+		ll := l
+		ll.Distribution = order.DistributionLinear
+		orders, err := ll.Build()
+		require.Nil(t, err)
+		assert.InDeltaSlice(t, []float64{1.0 / 15, 2.0 / 15, 3.0 / 15, 4.0 / 15, 5.0 / 15}, amounts(orders), 1e-9)
+	})
+
+	t.Run("exponential", func(t *testing.T) {
+		ll := l
+		ll.Amount = -3
+		ll.Count = 2
+		ll.Distribution = order.DistributionExponential
+		orders, err := ll.Build()
+		require.Nil(t, err)
+		assert.InDeltaSlice(t, []float64{-1, -2}, amounts(orders), 1e-9)
+		assert.Equal(t, []float64{9000, 8600}, []float64{orders[0].Price, orders[1].Price})
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ll := l
+		ll.Count = order.MaxLadderSize + 1
+		_, err := ll.Build()
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+		ll = l
+		ll.Distribution = "random"
+		_, err = ll.Build()
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+	})
+}
+
+func TestNewGID(t *testing.T) {
+	assert.NotEqual(t, order.NewGID(), order.NewGID())
+}
@@ -2,8 +2,10 @@ package order
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 )
 
 type Order struct {
@@ -31,6 +33,31 @@ type Order struct {
 	Meta          map[string]interface{}
 }
 
+// CreatedAt returns the creation time of the order
+func (o *Order) CreatedAt() time.Time {
+	return common.Mts(o.MTSCreated).Time()
+}
+
+// UpdatedAt returns the time of the last update of the order
+func (o *Order) UpdatedAt() time.Time {
+	return common.Mts(o.MTSUpdated).Time()
+}
+
+// Expiry returns the good-till-date expiry of the order, or the zero time if
+// the order has none
+func (o *Order) Expiry() time.Time {
+	return common.Mts(o.MTSTif).Time()
+}
+
+// Leverage returns the leverage applied to a derivative order as reported in
+// the order meta, or 0 if none was set
+func (o *Order) Leverage() float64 {
+	if o.Meta == nil {
+		return 0
+	}
+	return convert.F64ValOrZero(o.Meta["lev"])
+}
+
 // Snapshot is a collection of Orders that would usually be sent on
 // inital connection.
 type Snapshot struct {
@@ -48,46 +75,52 @@ type Cancel Order
 
 // FromRaw takes the raw list of values as returned from the websocket
 // service and tries to convert it into an Order.
-func FromRaw(raw []interface{}) (o *Order, err error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (o *Order, err error) {
 	if len(raw) < 32 {
 		return o, fmt.Errorf("data slice too short for order: %#v", raw)
 	}
 
+	f := convert.NewFields("order", raw, opts...)
+
 	o = &Order{
-		ID:            convert.I64ValOrZero(raw[0]),
-		GID:           convert.I64ValOrZero(raw[1]),
-		CID:           convert.I64ValOrZero(raw[2]),
-		Symbol:        convert.SValOrEmpty(raw[3]),
-		MTSCreated:    convert.I64ValOrZero(raw[4]),
-		MTSUpdated:    convert.I64ValOrZero(raw[5]),
-		Amount:        convert.F64ValOrZero(raw[6]),
-		AmountOrig:    convert.F64ValOrZero(raw[7]),
-		Type:          convert.SValOrEmpty(raw[8]),
-		TypePrev:      convert.SValOrEmpty(raw[9]),
-		MTSTif:        convert.I64ValOrZero(raw[10]),
-		Flags:         convert.I64ValOrZero(raw[12]),
-		Status:        convert.SValOrEmpty(raw[13]),
-		Price:         convert.F64ValOrZero(raw[16]),
-		PriceAvg:      convert.F64ValOrZero(raw[17]),
-		PriceTrailing: convert.F64ValOrZero(raw[18]),
-		PriceAuxLimit: convert.F64ValOrZero(raw[19]),
-		Notify:        convert.BValOrFalse(raw[23]),
-		Hidden:        convert.BValOrFalse(raw[24]),
-		PlacedID:      convert.I64ValOrZero(raw[25]),
-		Routing:       convert.SValOrEmpty(raw[28]),
+		ID:            f.I64(0),
+		GID:           f.I64(1),
+		CID:           f.I64(2),
+		Symbol:        f.S(3),
+		MTSCreated:    f.I64(4),
+		MTSUpdated:    f.I64(5),
+		Amount:        f.F64(6),
+		AmountOrig:    f.F64(7),
+		Type:          f.S(8),
+		TypePrev:      f.S(9),
+		MTSTif:        f.I64(10),
+		Flags:         f.I64(12),
+		Status:        f.S(13),
+		Price:         f.F64(16),
+		PriceAvg:      f.F64(17),
+		PriceTrailing: f.F64(18),
+		PriceAuxLimit: f.F64(19),
+		Notify:        f.Bool(23),
+		Hidden:        f.Bool(24),
+		PlacedID:      f.I64(25),
+		Routing:       f.S(28),
 	}
 
 	if meta, ok := raw[31].(map[string]interface{}); ok {
 		o.Meta = meta
 	}
 
+	if err = f.Err(); err != nil {
+		return nil, err
+	}
+
 	return
 }
 
 // NewFromRaw reds "on" type message from data stream and
 // maps it to order.New data structure
-func NewFromRaw(raw []interface{}) (New, error) {
-	o, err := FromRaw(raw)
+func NewFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (New, error) {
+	o, err := FromRaw(raw, opts...)
 	if err != nil {
 		return New{}, err
 	}
@@ -96,8 +129,8 @@ func NewFromRaw(raw []interface{}) (New, error) {
 
 // UpdateFromRaw reds "ou" type message from data stream and
 // maps it to order.Update data structure
-func UpdateFromRaw(raw []interface{}) (Update, error) {
-	o, err := FromRaw(raw)
+func UpdateFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (Update, error) {
+	o, err := FromRaw(raw, opts...)
 	if err != nil {
 		return Update{}, err
 	}
@@ -106,8 +139,8 @@ func UpdateFromRaw(raw []interface{}) (Update, error) {
 
 // CancelFromRaw reds "oc" type message from data stream and
 // maps it to order.Cancel data structure
-func CancelFromRaw(raw []interface{}) (Cancel, error) {
-	o, err := FromRaw(raw)
+func CancelFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (Cancel, error) {
+	o, err := FromRaw(raw, opts...)
 	if err != nil {
 		return Cancel{}, err
 	}
@@ -116,7 +149,7 @@ func CancelFromRaw(raw []interface{}) (Cancel, error) {
 
 // SnapshotFromRaw takes a raw list of values as returned from the websocket
 // service and tries to convert it into an Snapshot.
-func SnapshotFromRaw(raw []interface{}) (s *Snapshot, err error) {
+func SnapshotFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (s *Snapshot, err error) {
 	if len(raw) == 0 {
 		return s, fmt.Errorf("data slice too short for order: %#v", raw)
 	}
@@ -126,7 +159,7 @@ func SnapshotFromRaw(raw []interface{}) (s *Snapshot, err error) {
 	case []interface{}:
 		for _, v := range raw {
 			if l, ok := v.([]interface{}); ok {
-				o, err := FromRaw(l)
+				o, err := FromRaw(l, opts...)
 				if err != nil {
 					return s, err
 				}
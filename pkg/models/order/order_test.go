@@ -1,11 +1,15 @@
 package order_test
 
 import (
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFromRaw(t *testing.T) {
@@ -388,3 +392,56 @@ func TestCancelFromRaw(t *testing.T) {
 	got := reflect.TypeOf(o).String()
 	assert.Equal(t, expected, got)
 }
+
+func TestOrderTimes(t *testing.T) {
+	pld := []interface{}{
+		33950998276, nil, 1573476747887, "tETHUSD", 1573476748000, 1573476749000, -0.5,
+		-0.5, "LIMIT", nil, nil, nil, 0, "ACTIVE", nil, nil, 220, 0, 0, 0, nil, nil,
+		nil, 0, 1, nil, nil, nil, "BFX", nil, nil, nil,
+	}
+	o, err := order.FromRaw(pld)
+	assert.Nil(t, err)
+
+	assert.Equal(t, time.Unix(1573476748, 0).UTC(), o.CreatedAt().UTC())
+	assert.Equal(t, time.Unix(1573476749, 0).UTC(), o.UpdatedAt().UTC())
+	assert.True(t, o.Expiry().IsZero())
+
+	pld[10] = 1573563148000
+	o, err = order.FromRaw(pld)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Unix(1573563148, 0).UTC(), o.Expiry().UTC())
+}
+
+func TestOrderLeverage(t *testing.T) {
+	pld := []interface{}{
+		33950998276, nil, 1573476747887, "tBTCF0:USTF0", 1573476748000, 1573476749000, -0.5,
+		-0.5, "LIMIT", nil, nil, nil, 0, "ACTIVE", nil, nil, 220, 0, 0, 0, nil, nil,
+		nil, 0, 1, nil, nil, nil, "BFX", nil, nil, map[string]interface{}{"lev": 10.0},
+	}
+	o, err := order.FromRaw(pld)
+	assert.Nil(t, err)
+	assert.Equal(t, 10.0, o.Leverage())
+
+	assert.Equal(t, 0.0, (&order.Order{}).Leverage())
+}
+
+func TestFromRawStrict(t *testing.T) {
+	pld := []interface{}{
+		33950998275, nil, 1573476747887, "tETHUSD", 1573476748000, 1573476748000,
+		"-0.5", -0.5, "LIMIT", nil, nil, nil, 0, "ACTIVE", nil, nil, 220, 0, 0, 0, nil, nil,
+		nil, 0, 0, nil, nil, nil, "BFX", nil, nil, nil,
+	}
+
+	o, err := order.FromRaw(pld)
+	require.Nil(t, err)
+	require.NotNil(t, o)
+
+	o, err = order.FromRaw(pld, convert.DecodeOptions{Strict: true})
+	assert.Nil(t, o)
+	var de *convert.DecodeError
+	require.True(t, errors.As(err, &de))
+	require.Len(t, de.Anomalies, 1)
+	assert.Equal(t, "order", de.Anomalies[0].Parser)
+	assert.Equal(t, 6, de.Anomalies[0].Index)
+	assert.Equal(t, "-0.5", de.Anomalies[0].Value)
+}
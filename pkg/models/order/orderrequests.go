@@ -3,6 +3,8 @@ package order
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 )
@@ -24,7 +26,11 @@ type NewRequest struct {
 	PostOnly      bool                   `json:"postonly,omitempty"`
 	Close         bool                   `json:"close,omitempty"`
 	OcoOrder      bool                   `json:"oco_order,omitempty"`
+	ReduceOnly    bool                   `json:"reduce_only,omitempty"`
+	NoVarRates    bool                   `json:"no_var_rates,omitempty"`
+	Flags         int                    `json:"flags,omitempty"` // additional raw flags, see Flags()
 	TimeInForce   string                 `json:"tif,omitempty"`
+	Expiry        time.Time              `json:"-"` // good-till-date expiry, takes precedence over TimeInForce
 	AffiliateCode string                 `json:"-"`
 	Meta          map[string]interface{} `json:"meta,omitempty"`
 }
@@ -66,23 +72,32 @@ func (nr *NewRequest) EnrichedPayload() interface{} {
 		PriceTrailing: nr.PriceTrailing,
 		PriceAuxLimit: nr.PriceAuxLimit,
 		PriceOcoStop:  nr.PriceOcoStop,
-		TimeInForce:   nr.TimeInForce,
+		TimeInForce:   timeInForce(nr.TimeInForce, nr.Expiry),
+		Flags:         nr.Flags,
 	}
 
 	if nr.Hidden {
-		pld.Flags = pld.Flags + common.OrderFlagHidden
+		pld.Flags = pld.Flags | common.OrderFlagHidden
 	}
 
 	if nr.PostOnly {
-		pld.Flags = pld.Flags + common.OrderFlagPostOnly
+		pld.Flags = pld.Flags | common.OrderFlagPostOnly
 	}
 
 	if nr.OcoOrder {
-		pld.Flags = pld.Flags + common.OrderFlagOCO
+		pld.Flags = pld.Flags | common.OrderFlagOCO
 	}
 
 	if nr.Close {
-		pld.Flags = pld.Flags + common.OrderFlagClose
+		pld.Flags = pld.Flags | common.OrderFlagClose
+	}
+
+	if nr.ReduceOnly {
+		pld.Flags = pld.Flags | common.OrderFlagReduceOnly
+	}
+
+	if nr.NoVarRates {
+		pld.Flags = pld.Flags | common.OrderFlagNoVarRates
 	}
 
 	if nr.Meta == nil {
@@ -111,7 +126,9 @@ type UpdateRequest struct {
 	PriceAuxLimit float64                `json:"price_aux_limit,string,omitempty"`
 	Hidden        bool                   `json:"hidden,omitempty"`
 	PostOnly      bool                   `json:"postonly,omitempty"`
+	Flags         int                    `json:"flags,omitempty"` // additional raw flags, see Flags()
 	TimeInForce   string                 `json:"tif,omitempty"`
+	Expiry        time.Time              `json:"-"` // good-till-date expiry, takes precedence over TimeInForce
 	Meta          map[string]interface{} `json:"meta,omitempty"`
 }
 
@@ -149,7 +166,8 @@ func (ur *UpdateRequest) EnrichedPayload() interface{} {
 		PriceTrailing: ur.PriceTrailing,
 		PriceAuxLimit: ur.PriceAuxLimit,
 		Delta:         ur.Delta,
-		TimeInForce:   ur.TimeInForce,
+		TimeInForce:   timeInForce(ur.TimeInForce, ur.Expiry),
+		Flags:         ur.Flags,
 	}
 
 	if ur.Meta == nil {
@@ -157,11 +175,11 @@ func (ur *UpdateRequest) EnrichedPayload() interface{} {
 	}
 
 	if ur.Hidden {
-		pld.Flags = pld.Flags + common.OrderFlagHidden
+		pld.Flags = pld.Flags | common.OrderFlagHidden
 	}
 
 	if ur.PostOnly {
-		pld.Flags = pld.Flags + common.OrderFlagPostOnly
+		pld.Flags = pld.Flags | common.OrderFlagPostOnly
 	}
 
 	return pld
@@ -171,6 +189,57 @@ func (ur *UpdateRequest) ToJSON() ([]byte, error) {
 	return json.Marshal(ur.EnrichedPayload())
 }
 
+// Validate checks the request before it is submitted. Amount replaces the
+// remaining amount of the order while Delta adds to it, so only one of both
+// may be set.
+func (ur *UpdateRequest) Validate() error {
+	if ur.ID == 0 {
+		return fmt.Errorf("%w: order id is required", common.ErrBadRequest)
+	}
+	if ur.Amount != 0 && ur.Delta != 0 {
+		return fmt.Errorf("%w: amount and delta are mutually exclusive", common.ErrBadRequest)
+	}
+	if ur.Price < 0 {
+		return fmt.Errorf("%w: price must not be negative", common.ErrBadRequest)
+	}
+	return nil
+}
+
+// PreservesPriority reports whether applying the update to the current
+// state of the order keeps its place in the queue of its price level. The
+// exchange only keeps it if the remaining amount is reduced without
+// changing sides, or if the update touches fields which are not part of
+// the book: GID, leverage, time in force and meta data. A new price, an
+// increased amount or hiding the order moves it to the end of the queue.
+func (ur *UpdateRequest) PreservesPriority(current *Order) bool {
+	if ur.Price != 0 && ur.Price != current.Price {
+		return false
+	}
+
+	amount := current.Amount
+	if ur.Amount != 0 {
+		amount = ur.Amount
+	}
+	if ur.Delta != 0 {
+		amount = current.Amount + ur.Delta
+	}
+	if amount*current.Amount < 0 || math.Abs(amount) > math.Abs(current.Amount) {
+		return false
+	}
+
+	hidden := ur.Hidden || ur.Flags&common.OrderFlagHidden != 0
+	return !hidden || current.Hidden || DecodeFlags(current.Flags).Hidden
+}
+
+// timeInForce formats the expiry as expected by the tif field, falling back
+// to the raw value if no expiry is set
+func timeInForce(tif string, expiry time.Time) string {
+	if expiry.IsZero() {
+		return tif
+	}
+	return expiry.UTC().Format(common.TimeInForceLayout)
+}
+
 // CancelRequest represents an order cancel request.
 // An order can be cancelled using the internal ID or a
 // combination of Client ID (CID) and the daten for the given
@@ -1,8 +1,11 @@
 package order_test
 
 import (
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,6 +50,35 @@ func TestOrderNewRequest(t *testing.T) {
 		expected := "[0, \"on\", null, {\"gid\":876,\"cid\":987,\"type\":\"EXCHANGE LIMIT\",\"symbol\":\"tBTCUSD\",\"amount\":\"0.001\",\"price\":\"13\",\"flags\":21056,\"meta\":{\"aff_code\":\"abc\"}}]"
 		assert.Equal(t, expected, string(got))
 	})
+
+	t.Run("MarshalJSON with leverage", func(t *testing.T) {
+		our := order.NewRequest{
+			Type:     "LIMIT",
+			Symbol:   "tBTCF0:USTF0",
+			Price:    13,
+			Amount:   0.001,
+			Leverage: 10,
+		}
+
+		got, err := our.MarshalJSON()
+		require.Nil(t, err)
+		assert.Contains(t, string(got), `"lev":10`)
+	})
+
+	t.Run("MarshalJSON with expiry", func(t *testing.T) {
+		our := order.NewRequest{
+			Type:        "EXCHANGE LIMIT",
+			Symbol:      "tBTCUSD",
+			Price:       13,
+			Amount:      0.001,
+			TimeInForce: "2019-01-01 00:00:00",
+			Expiry:      time.Date(2020, 1, 15, 10, 45, 23, 0, time.FixedZone("CET", 3600)),
+		}
+
+		got, err := our.MarshalJSON()
+		require.Nil(t, err)
+		assert.Contains(t, string(got), `"tif":"2020-01-15 09:45:23"`)
+	})
 }
 
 func TestOrderUpdateRequest(t *testing.T) {
@@ -116,6 +148,55 @@ func TestOrderUpdateRequest(t *testing.T) {
 	})
 }
 
+func TestOrderUpdateRequestValidate(t *testing.T) {
+	cases := map[string]struct {
+		our   order.UpdateRequest
+		valid bool
+	}{
+		"price":            {order.UpdateRequest{ID: 1, Price: 100}, true},
+		"delta":            {order.UpdateRequest{ID: 1, Delta: -0.5}, true},
+		"missing id":       {order.UpdateRequest{Price: 100}, false},
+		"amount and delta": {order.UpdateRequest{ID: 1, Amount: 1, Delta: -0.5}, false},
+		"negative price":   {order.UpdateRequest{ID: 1, Price: -1}, false},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			err := v.our.Validate()
+			if v.valid {
+				assert.Nil(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, common.ErrBadRequest))
+		})
+	}
+}
+
+func TestOrderUpdateRequestPreservesPriority(t *testing.T) {
+	current := &order.Order{ID: 1, Amount: -2, Price: 100}
+
+	cases := map[string]struct {
+		our      order.UpdateRequest
+		expected bool
+	}{
+		"reduce by delta":    {order.UpdateRequest{ID: 1, Delta: 0.5}, true},
+		"reduce by amount":   {order.UpdateRequest{ID: 1, Amount: -1}, true},
+		"same price":         {order.UpdateRequest{ID: 1, Price: 100}, true},
+		"gid and leverage":   {order.UpdateRequest{ID: 1, GID: 7, Leverage: 10}, true},
+		"new price":          {order.UpdateRequest{ID: 1, Price: 101}, false},
+		"increase by delta":  {order.UpdateRequest{ID: 1, Delta: -0.5}, false},
+		"increase by amount": {order.UpdateRequest{ID: 1, Amount: -3}, false},
+		"flip side":          {order.UpdateRequest{ID: 1, Amount: 1}, false},
+		"hide":               {order.UpdateRequest{ID: 1, Hidden: true}, false},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, v.expected, v.our.PreservesPriority(current))
+		})
+	}
+}
+
 func TestOrderCancelRequest(t *testing.T) {
 	t.Run("MarshalJSON", func(t *testing.T) {
 		ocr := order.CancelRequest{
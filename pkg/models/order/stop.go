@@ -0,0 +1,92 @@
+package order
+
+import (
+	"fmt"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// NewStopLimitRequest returns a margin STOP LIMIT order, which places a limit
+// order at limitPrice once stopPrice is reached.
+func NewStopLimitRequest(symbol string, amount, stopPrice, limitPrice float64) (*NewRequest, error) {
+	return stopLimit(common.OrderTypeStopLimit, symbol, amount, stopPrice, limitPrice)
+}
+
+// NewExchangeStopLimitRequest returns an EXCHANGE STOP LIMIT order, which places
+// a limit order at limitPrice once stopPrice is reached.
+func NewExchangeStopLimitRequest(symbol string, amount, stopPrice, limitPrice float64) (*NewRequest, error) {
+	return stopLimit(common.OrderTypeExchangeStopLimit, symbol, amount, stopPrice, limitPrice)
+}
+
+// NewTrailingStopRequest returns a margin TRAILING STOP order, which follows the
+// market price at the given distance.
+func NewTrailingStopRequest(symbol string, amount, distance float64) (*NewRequest, error) {
+	return trailingStop(common.OrderTypeTrailingStop, symbol, amount, distance)
+}
+
+// NewExchangeTrailingStopRequest returns an EXCHANGE TRAILING STOP order, which
+// follows the market price at the given distance.
+func NewExchangeTrailingStopRequest(symbol string, amount, distance float64) (*NewRequest, error) {
+	return trailingStop(common.OrderTypeExchangeTrailingStop, symbol, amount, distance)
+}
+
+func stopLimit(orderType, symbol string, amount, stopPrice, limitPrice float64) (*NewRequest, error) {
+	nr := &NewRequest{
+		Type:          orderType,
+		Symbol:        symbol,
+		Amount:        amount,
+		Price:         stopPrice,
+		PriceAuxLimit: limitPrice,
+	}
+	if err := nr.Validate(); err != nil {
+		return nil, err
+	}
+	return nr, nil
+}
+
+func trailingStop(orderType, symbol string, amount, distance float64) (*NewRequest, error) {
+	nr := &NewRequest{
+		Type:          orderType,
+		Symbol:        symbol,
+		Amount:        amount,
+		PriceTrailing: distance,
+	}
+	if err := nr.Validate(); err != nil {
+		return nil, err
+	}
+	return nr, nil
+}
+
+// Validate checks the combination of prices required by the order type, which
+// would otherwise be rejected by the exchange.
+func (nr *NewRequest) Validate() error {
+	if nr.Symbol == "" {
+		return fmt.Errorf("%w: symbol is required", common.ErrBadRequest)
+	}
+
+	if nr.Amount == 0 {
+		return fmt.Errorf("%w: amount must not be zero", common.ErrBadRequest)
+	}
+
+	switch nr.Type {
+	case common.OrderTypeStopLimit, common.OrderTypeExchangeStopLimit:
+		if nr.Price <= 0 {
+			return fmt.Errorf("%w: %s order requires a positive stop price", common.ErrBadRequest, nr.Type)
+		}
+		if nr.PriceAuxLimit <= 0 {
+			return fmt.Errorf("%w: %s order requires a positive limit price", common.ErrBadRequest, nr.Type)
+		}
+		if nr.PriceTrailing != 0 {
+			return fmt.Errorf("%w: %s order does not support a trailing distance", common.ErrBadRequest, nr.Type)
+		}
+	case common.OrderTypeTrailingStop, common.OrderTypeExchangeTrailingStop:
+		if nr.PriceTrailing <= 0 {
+			return fmt.Errorf("%w: %s order requires a positive trailing distance", common.ErrBadRequest, nr.Type)
+		}
+		if nr.PriceAuxLimit != 0 {
+			return fmt.Errorf("%w: %s order does not support a limit price", common.ErrBadRequest, nr.Type)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package order_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStopLimitRequest(t *testing.T) {
+	t.Run("populates prices", func(t *testing.T) {
+		nr, err := order.NewExchangeStopLimitRequest("tBTCUSD", -0.5, 9000, 8950)
+		require.Nil(t, err)
+		assert.Equal(t, &order.NewRequest{
+			Type:          common.OrderTypeExchangeStopLimit,
+			Symbol:        "tBTCUSD",
+			Amount:        -0.5,
+			Price:         9000,
+			PriceAuxLimit: 8950,
+		}, nr)
+
+		got, err := nr.ToJSON()
+		require.Nil(t, err)
+		assert.Contains(t, string(got), `"price":"9000","price_aux_limit":"8950"`)
+	})
+
+	t.Run("requires limit price", func(t *testing.T) {
+		_, err := order.NewStopLimitRequest("tBTCUSD", 0.5, 9000, 0)
+		require.NotNil(t, err)
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+	})
+
+	t.Run("requires amount", func(t *testing.T) {
+		_, err := order.NewStopLimitRequest("tBTCUSD", 0, 9000, 9100)
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+	})
+}
+
+func TestNewTrailingStopRequest(t *testing.T) {
+	t.Run("populates distance", func(t *testing.T) {
+		nr, err := order.NewTrailingStopRequest("tBTCUSD", -0.5, 150)
+		require.Nil(t, err)
+		assert.Equal(t, common.OrderTypeTrailingStop, nr.Type)
+		assert.Equal(t, 150.0, nr.PriceTrailing)
+	})
+
+	t.Run("requires distance", func(t *testing.T) {
+		_, err := order.NewExchangeTrailingStopRequest("tBTCUSD", -0.5, 0)
+		require.NotNil(t, err)
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+	})
+
+	t.Run("rejects limit price", func(t *testing.T) {
+		nr := order.NewRequest{
+			Type:          common.OrderTypeTrailingStop,
+			Symbol:        "tBTCUSD",
+			Amount:        -0.5,
+			PriceTrailing: 150,
+			PriceAuxLimit: 9000,
+		}
+		assert.True(t, errors.Is(nr.Validate(), common.ErrBadRequest))
+	})
+}
@@ -3,8 +3,10 @@ package position
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 )
 
 type Position struct {
@@ -28,6 +30,16 @@ type Position struct {
 	Meta                 map[string]interface{}
 }
 
+// CreatedAt returns the time the position was opened
+func (p *Position) CreatedAt() time.Time {
+	return common.Mts(p.MtsCreate).Time()
+}
+
+// UpdatedAt returns the time of the last update of the position
+func (p *Position) UpdatedAt() time.Time {
+	return common.Mts(p.MtsUpdate).Time()
+}
+
 type New Position
 type Update Position
 type Cancel Position
@@ -36,39 +48,45 @@ type Snapshot struct {
 	Snapshot []*Position
 }
 
-func FromRaw(raw []interface{}) (p *Position, err error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (p *Position, err error) {
 	if len(raw) < 20 {
 		return p, fmt.Errorf("data slice too short for position: %#v", raw)
 	}
 
+	f := convert.NewFields("position", raw, opts...)
+
 	p = &Position{
-		Symbol:               convert.SValOrEmpty(raw[0]),
-		Status:               convert.SValOrEmpty(raw[1]),
-		Amount:               convert.F64ValOrZero(raw[2]),
-		BasePrice:            convert.F64ValOrZero(raw[3]),
-		MarginFunding:        convert.F64ValOrZero(raw[4]),
-		MarginFundingType:    convert.I64ValOrZero(raw[5]),
-		ProfitLoss:           convert.F64ValOrZero(raw[6]),
-		ProfitLossPercentage: convert.F64ValOrZero(raw[7]),
-		LiquidationPrice:     convert.F64ValOrZero(raw[8]),
-		Leverage:             convert.F64ValOrZero(raw[9]),
-		Id:                   convert.I64ValOrZero(raw[11]),
-		MtsCreate:            convert.I64ValOrZero(raw[12]),
-		MtsUpdate:            convert.I64ValOrZero(raw[13]),
-		Type:                 convert.SValOrEmpty(raw[15]),
-		Collateral:           convert.F64ValOrZero(raw[17]),
-		CollateralMin:        convert.F64ValOrZero(raw[18]),
+		Symbol:               f.S(0),
+		Status:               f.S(1),
+		Amount:               f.F64(2),
+		BasePrice:            f.F64(3),
+		MarginFunding:        f.F64(4),
+		MarginFundingType:    f.I64(5),
+		ProfitLoss:           f.F64(6),
+		ProfitLossPercentage: f.F64(7),
+		LiquidationPrice:     f.F64(8),
+		Leverage:             f.F64(9),
+		Id:                   f.I64(11),
+		MtsCreate:            f.I64(12),
+		MtsUpdate:            f.I64(13),
+		Type:                 f.S(15),
+		Collateral:           f.F64(17),
+		CollateralMin:        f.F64(18),
 	}
 
 	if meta, ok := raw[19].(map[string]interface{}); ok {
 		p.Meta = meta
 	}
 
+	if err = f.Err(); err != nil {
+		return nil, err
+	}
+
 	return
 }
 
-func NewFromRaw(raw []interface{}) (New, error) {
-	p, err := FromRaw(raw)
+func NewFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (New, error) {
+	p, err := FromRaw(raw, opts...)
 	if err != nil {
 		return New{}, err
 	}
@@ -76,8 +94,8 @@ func NewFromRaw(raw []interface{}) (New, error) {
 	return New(*p), nil
 }
 
-func UpdateFromRaw(raw []interface{}) (Update, error) {
-	p, err := FromRaw(raw)
+func UpdateFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (Update, error) {
+	p, err := FromRaw(raw, opts...)
 	if err != nil {
 		return Update{}, err
 	}
@@ -85,8 +103,8 @@ func UpdateFromRaw(raw []interface{}) (Update, error) {
 	return Update(*p), nil
 }
 
-func CancelFromRaw(raw []interface{}) (Cancel, error) {
-	p, err := FromRaw(raw)
+func CancelFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (Cancel, error) {
+	p, err := FromRaw(raw, opts...)
 	if err != nil {
 		return Cancel{}, err
 	}
@@ -94,7 +112,7 @@ func CancelFromRaw(raw []interface{}) (Cancel, error) {
 	return Cancel(*p), nil
 }
 
-func SnapshotFromRaw(raw []interface{}) (s *Snapshot, err error) {
+func SnapshotFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (s *Snapshot, err error) {
 	if len(raw) == 0 {
 		return s, fmt.Errorf("data slice too short for position: %#v", raw)
 	}
@@ -104,7 +122,7 @@ func SnapshotFromRaw(raw []interface{}) (s *Snapshot, err error) {
 	case []interface{}:
 		for _, v := range raw {
 			if l, ok := v.([]interface{}); ok {
-				p, err := FromRaw(l)
+				p, err := FromRaw(l, opts...)
 				if err != nil {
 					return s, err
 				}
@@ -51,24 +51,31 @@ var pulseFields = map[string]int{
 }
 
 // FromRaw returns pointer to Pulse message
-func FromRaw(raw []interface{}) (*Pulse, error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (*Pulse, error) {
 	if len(raw) < 19 {
 		return nil, fmt.Errorf("data slice too short for Pulse Message: %#v", raw)
 	}
 
+	f := convert.NewFields("pulse", raw, opts...)
+
 	p := &Pulse{}
 	var err error
 
-	p.ID = convert.SValOrEmpty(raw[pulseFields["ID"]])
-	p.MTS = convert.I64ValOrZero(raw[pulseFields["Mts"]])
-	p.UserID = convert.SValOrEmpty(raw[pulseFields["UserID"]])
-	p.Title = convert.SValOrEmpty(raw[pulseFields["Title"]])
-	p.Content = convert.SValOrEmpty(raw[pulseFields["Content"]])
-	p.IsPin = convert.ToInt(raw[pulseFields["IsPin"]])
-	p.IsPublic = convert.ToInt(raw[pulseFields["IsPublic"]])
-	p.CommentsDisabled = convert.ToInt(raw[pulseFields["CommentsDisabled"]])
-	p.Likes = convert.ToInt(raw[pulseFields["Likes"]])
-	p.Comments = convert.ToInt(raw[pulseFields["Comments"]])
+	p.ID = f.S(pulseFields["ID"])
+	p.MTS = f.I64(pulseFields["Mts"])
+	p.UserID = f.S(pulseFields["UserID"])
+	p.Title = f.S(pulseFields["Title"])
+	p.Content = f.S(pulseFields["Content"])
+	p.IsPin = f.Int(pulseFields["IsPin"])
+	p.IsPublic = f.Int(pulseFields["IsPublic"])
+	p.CommentsDisabled = f.Int(pulseFields["CommentsDisabled"])
+	p.Likes = f.Int(pulseFields["Likes"])
+	if len(raw) > pulseFields["Comments"] {
+		p.Comments = f.Int(pulseFields["Comments"])
+	}
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
 
 	p.Tags, err = convert.ItfToStrSlice(raw[pulseFields["Tags"]])
 	if err != nil {
@@ -91,7 +98,7 @@ func FromRaw(raw []interface{}) (*Pulse, error) {
 		return p, nil
 	}
 
-	p.PulseProfile, err = pulseprofile.NewFromRaw(profilePayload[0])
+	p.PulseProfile, err = pulseprofile.NewFromRaw(profilePayload[0], opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -100,7 +107,7 @@ func FromRaw(raw []interface{}) (*Pulse, error) {
 }
 
 // SnapshotFromRaw returns slice of Pulse message pointers
-func SnapshotFromRaw(raws []interface{}) ([]*Pulse, error) {
+func SnapshotFromRaw(raws []interface{}, opts ...convert.DecodeOptions) ([]*Pulse, error) {
 	if len(raws) < 1 {
 		return nil, fmt.Errorf("data slice is too short for Pulse History: %#v", raws)
 	}
@@ -108,8 +115,11 @@ func SnapshotFromRaw(raws []interface{}) ([]*Pulse, error) {
 	res := []*Pulse{}
 
 	for _, raw := range raws {
-		raw := raw.([]interface{})
-		p, err := FromRaw(raw)
+		raw, ok := raw.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected Pulse Message but got %#v", raw)
+		}
+		p, err := FromRaw(raw, opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -41,22 +41,30 @@ var pulseProfileFields = map[string]int{
 
 // NewFromRaw takes in slice of interfaces and converts them to
 // pointer to Pulse Profile
-func NewFromRaw(raw []interface{}) (*PulseProfile, error) {
+func NewFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (*PulseProfile, error) {
 	if len(raw) < 14 {
 		return nil, fmt.Errorf("data slice too short for PulseProfile: %#v", raw)
 	}
 
+	f := convert.NewFields("pulse profile", raw, opts...)
+
 	pp := &PulseProfile{}
 
-	pp.ID = convert.SValOrEmpty(raw[pulseProfileFields["ID"]])
-	pp.MTS = convert.I64ValOrZero(raw[pulseProfileFields["Mts"]])
-	pp.Nickname = convert.SValOrEmpty(raw[pulseProfileFields["Nickname"]])
-	pp.Picture = convert.SValOrEmpty(raw[pulseProfileFields["Picture"]])
-	pp.Text = convert.SValOrEmpty(raw[pulseProfileFields["Text"]])
-	pp.TwitterHandle = convert.SValOrEmpty(raw[pulseProfileFields["TwitterHandle"]])
-	pp.Followers = convert.I64ValOrZero(raw[pulseProfileFields["Followers"]])
-	pp.Following = convert.I64ValOrZero(raw[pulseProfileFields["Following"]])
-	pp.TippingStatus = convert.I64ValOrZero(raw[pulseProfileFields["TippingStatus"]])
+	pp.ID = f.S(pulseProfileFields["ID"])
+	pp.MTS = f.I64(pulseProfileFields["Mts"])
+	pp.Nickname = f.S(pulseProfileFields["Nickname"])
+	pp.Picture = f.S(pulseProfileFields["Picture"])
+	pp.Text = f.S(pulseProfileFields["Text"])
+	pp.TwitterHandle = f.S(pulseProfileFields["TwitterHandle"])
+	pp.Followers = f.I64(pulseProfileFields["Followers"])
+	pp.Following = f.I64(pulseProfileFields["Following"])
+	if len(raw) > pulseProfileFields["TippingStatus"] {
+		pp.TippingStatus = f.I64(pulseProfileFields["TippingStatus"])
+	}
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
 
 	return pp, nil
 }
@@ -11,18 +11,26 @@ type Stat struct {
 	Volume float64
 }
 
-func FromRaw(raw []interface{}) (*Stat, error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (*Stat, error) {
 	if len(raw) < 2 {
 		return nil, fmt.Errorf("data slice too short (len=%d) for Stat: %#v", len(raw), raw)
 	}
 
-	return &Stat{
-		Period: convert.I64ValOrZero(raw[0]),
-		Volume: convert.F64ValOrZero(raw[1]),
-	}, nil
+	f := convert.NewFields("stat", raw, opts...)
+
+	s := &Stat{
+		Period: f.I64(0),
+		Volume: f.F64(1),
+	}
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
 }
 
-func SnapshotFromRaw(raw []interface{}) (snap []*Stat, err error) {
+func SnapshotFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (snap []*Stat, err error) {
 	if len(raw) == 0 {
 		return snap, fmt.Errorf("data slice too short for stats: %#v", raw)
 	}
@@ -30,7 +38,7 @@ func SnapshotFromRaw(raw []interface{}) (snap []*Stat, err error) {
 	stats := make([]*Stat, 0)
 	for _, v := range raw {
 		if v, ok := v.([]interface{}); ok {
-			s, err := FromRaw(v)
+			s, err := FromRaw(v, opts...)
 			if err != nil {
 				return snap, err
 			}
@@ -42,3 +50,19 @@ func SnapshotFromRaw(raw []interface{}) (snap []*Stat, err error) {
 
 	return stats, nil
 }
+
+// LongShortRatio compares the size of the long and short positions of a
+// trading pair.
+type LongShortRatio struct {
+	Period int64
+	Long   float64
+	Short  float64
+}
+
+// Ratio returns Long / Short, or 0 if there are no short positions.
+func (r *LongShortRatio) Ratio() float64 {
+	if r.Short == 0 {
+		return 0
+	}
+	return r.Long / r.Short
+}
@@ -70,3 +70,10 @@ func TestNewStatsSnapshotFromRaw(t *testing.T) {
 		assert.Equal(t, expected, got)
 	})
 }
+
+func TestLongShortRatio(t *testing.T) {
+	r := &stats.LongShortRatio{Long: 300, Short: 100}
+	assert.Equal(t, 3.0, r.Ratio())
+	r.Short = 0
+	assert.Equal(t, 0.0, r.Ratio())
+}
@@ -26,36 +26,44 @@ type Derivative struct {
 	ClampMAX             float64
 }
 
-func DerivFromRaw(symbol string, raw []interface{}) (*Derivative, error) {
+func DerivFromRaw(symbol string, raw []interface{}, opts ...convert.DecodeOptions) (*Derivative, error) {
 	if len(raw) < 22 {
 		return nil, fmt.Errorf("data slice too short for derivative status: %#v", raw)
 	}
 
-	return &Derivative{
+	f := convert.NewFields("derivative status", raw, opts...)
+
+	d := &Derivative{
 		Symbol:               symbol,
-		MTS:                  convert.I64ValOrZero(raw[0]),
-		Price:                convert.F64ValOrZero(raw[2]),
-		SpotPrice:            convert.F64ValOrZero(raw[3]),
-		InsuranceFundBalance: convert.F64ValOrZero(raw[5]),
-		FundingEventMTS:      convert.I64ValOrZero(raw[7]),
-		FundingAccrued:       convert.F64ValOrZero(raw[8]),
-		FundingStep:          convert.F64ValOrZero(raw[9]),
-		CurrentFunding:       convert.F64ValOrZero(raw[11]),
-		MarkPrice:            convert.F64ValOrZero(raw[14]),
-		OpenInterest:         convert.F64ValOrZero(raw[17]),
-		ClampMIN:             convert.F64ValOrZero(raw[21]),
-		ClampMAX:             convert.F64ValOrZero(raw[22]),
-	}, nil
+		MTS:                  f.I64(0),
+		Price:                f.F64(2),
+		SpotPrice:            f.F64(3),
+		InsuranceFundBalance: f.F64(5),
+		FundingEventMTS:      f.I64(7),
+		FundingAccrued:       f.F64(8),
+		FundingStep:          f.F64(9),
+		CurrentFunding:       f.F64(11),
+		MarkPrice:            f.F64(14),
+		OpenInterest:         f.F64(17),
+		ClampMIN:             f.F64(21),
+		ClampMAX:             f.F64(22),
+	}
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
 }
 
-func DerivSnapshotFromRaw(symbol string, raw [][]interface{}) (*DerivativesSnapshot, error) {
+func DerivSnapshotFromRaw(symbol string, raw [][]interface{}, opts ...convert.DecodeOptions) (*DerivativesSnapshot, error) {
 	if len(raw) == 0 {
 		return nil, fmt.Errorf("empty data slice")
 	}
 
 	snapshot := make([]*Derivative, len(raw))
 	for i, r := range raw {
-		d, err := DerivFromRaw(symbol, r)
+		d, err := DerivFromRaw(symbol, r, opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -64,10 +72,10 @@ func DerivSnapshotFromRaw(symbol string, raw [][]interface{}) (*DerivativesSnaps
 	return &DerivativesSnapshot{Snapshot: snapshot}, nil
 }
 
-func DerivFromRestRaw(raw []interface{}) (t *Derivative, err error) {
+func DerivFromRestRaw(raw []interface{}, opts ...convert.DecodeOptions) (t *Derivative, err error) {
 	if len(raw) < 2 {
 		return t, fmt.Errorf("data slice too short for derivatives: %#v", raw)
 	}
 
-	return DerivFromRaw(raw[0].(string), raw[1:])
+	return DerivFromRaw(raw[0].(string), raw[1:], opts...)
 }
@@ -21,31 +21,39 @@ type Liquidation struct {
 	PriceAcquired float64
 }
 
-func LiqFromRaw(raw []interface{}) (*Liquidation, error) {
+func LiqFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (*Liquidation, error) {
 	if len(raw) < 12 {
 		return nil, fmt.Errorf("data slice too short for liquidation status: %#v", raw)
 	}
 
-	return &Liquidation{
-		PositionID:    convert.I64ValOrZero(raw[1]),
-		MTS:           convert.I64ValOrZero(raw[2]),
-		Symbol:        convert.SValOrEmpty(raw[4]),
-		Amount:        convert.F64ValOrZero(raw[5]),
-		BasePrice:     convert.F64ValOrZero(raw[6]),
-		IsMatch:       convert.ToInt(raw[8]),
-		IsMarketSold:  convert.ToInt(raw[9]),
-		PriceAcquired: convert.F64ValOrZero(raw[11]),
-	}, nil
+	f := convert.NewFields("liquidation status", raw, opts...)
+
+	l := &Liquidation{
+		PositionID:    f.I64(1),
+		MTS:           f.I64(2),
+		Symbol:        f.S(4),
+		Amount:        f.F64(5),
+		BasePrice:     f.F64(6),
+		IsMatch:       f.Int(8),
+		IsMarketSold:  f.Int(9),
+		PriceAcquired: f.F64(11),
+	}
+
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
 }
 
-func LiqSnapshotFromRaw(raw [][]interface{}) (*LiquidationsSnapshot, error) {
+func LiqSnapshotFromRaw(raw [][]interface{}, opts ...convert.DecodeOptions) (*LiquidationsSnapshot, error) {
 	if len(raw) == 0 {
 		return nil, fmt.Errorf("empty data slice")
 	}
 
 	snapshot := make([]*Liquidation, len(raw))
 	for i, r := range raw {
-		l, err := LiqFromRaw(r)
+		l, err := LiqFromRaw(r, opts...)
 		if err != nil {
 			return nil, err
 		}
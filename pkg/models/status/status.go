@@ -10,7 +10,7 @@ import (
 
 // FromWSRaw - based on condition will return snapshot or single record of
 // derivative or liquidation data structure
-func FromWSRaw(key string, data []interface{}) (interface{}, error) {
+func FromWSRaw(key string, data []interface{}, opts ...convert.DecodeOptions) (interface{}, error) {
 	if len(data) == 0 {
 		return nil, errors.New("empty data slice")
 	}
@@ -22,19 +22,19 @@ func FromWSRaw(key string, data []interface{}) (interface{}, error) {
 	}
 
 	if isSnapshot && ss[0] == "deriv" {
-		return DerivSnapshotFromRaw(ss[1], convert.ToInterfaceArray(data))
+		return DerivSnapshotFromRaw(ss[1], convert.ToInterfaceArray(data), opts...)
 	}
 
 	if !isSnapshot && ss[0] == "deriv" {
-		return DerivFromRaw(ss[1], data)
+		return DerivFromRaw(ss[1], data, opts...)
 	}
 
 	if isSnapshot && ss[0] == "liq" {
-		return LiqSnapshotFromRaw(convert.ToInterfaceArray(data))
+		return LiqSnapshotFromRaw(convert.ToInterfaceArray(data), opts...)
 	}
 
 	if !isSnapshot && ss[0] == "liq" {
-		return LiqFromRaw(data)
+		return LiqFromRaw(data, opts...)
 	}
 
 	return nil, fmt.Errorf("%s: unrecognized data slice:%#v", key, data)
@@ -0,0 +1,85 @@
+package summary
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/symbol"
+)
+
+// FiatCurrencies lists the quote currencies charged with fiat fee rates
+var FiatCurrencies = map[string]bool{"USD": true, "EUR": true, "GBP": true, "JPY": true}
+
+// StableCurrencies lists the quote currencies charged with stablecoin fee rates
+var StableCurrencies = map[string]bool{"UST": true, "UDC": true, "EUT": true, "CNHT": true, "XCHF": true}
+
+// FeeCalculator estimates trading fees based on the account summary
+type FeeCalculator struct {
+	summary     *Summary
+	leoDiscount float64
+}
+
+// NewFeeCalculator returns a calculator using the fee rates of the summary
+func NewFeeCalculator(s *Summary) *FeeCalculator {
+	return &FeeCalculator{summary: s}
+}
+
+// WithLeoDiscount sets the share of taker fees discounted for LEO holders,
+// e.g. 0.15 for 15%. The discount applies if the summary reports a LEO level.
+func (fc *FeeCalculator) WithLeoDiscount(discount float64) *FeeCalculator {
+	fc.leoDiscount = discount
+	return fc
+}
+
+// Rate returns the fee rate for trading the symbol. Negative rates are rebates.
+func (fc *FeeCalculator) Rate(sym string, maker bool) (float64, error) {
+	parsed, err := symbol.Parse(sym)
+	if err != nil {
+		return 0, err
+	}
+	if !parsed.IsTrading() {
+		return 0, fmt.Errorf("%w: invalid trading symbol %q", common.ErrBadRequest, sym)
+	}
+
+	s := fc.summary
+	if parsed.IsPerpetual() {
+		if maker {
+			return s.DerivRebate, nil
+		}
+		return fc.discount(s.DerivTakerFee), nil
+	}
+
+	quote := parsed.Quote
+	switch {
+	case FiatCurrencies[quote] && maker:
+		return s.MakerFeeToFiat, nil
+	case FiatCurrencies[quote]:
+		return fc.discount(s.TakerFeeToFiat), nil
+	case StableCurrencies[quote] && maker:
+		return s.MakerFeeToStable, nil
+	case StableCurrencies[quote]:
+		return fc.discount(s.TakerFeeToStable), nil
+	case maker:
+		return s.MakerFeeToCrypto, nil
+	default:
+		return fc.discount(s.TakerFeeToCrypto), nil
+	}
+}
+
+// EstimateFee returns the expected fee in the quote currency for an order of
+// the given amount and price. Negative fees are rebates.
+func (fc *FeeCalculator) EstimateFee(symbol string, amount, price float64, maker bool) (float64, error) {
+	rate, err := fc.Rate(symbol, maker)
+	if err != nil {
+		return 0, err
+	}
+	return math.Abs(amount) * price * rate, nil
+}
+
+func (fc *FeeCalculator) discount(rate float64) float64 {
+	if fc.summary.LeoLevel > 0 {
+		return rate * (1 - fc.leoDiscount)
+	}
+	return rate
+}
@@ -0,0 +1,72 @@
+package summary
+
+import (
+	"fmt"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+)
+
+// Summary holds the fee rates and LEO level of an account
+type Summary struct {
+	MakerFeeToCrypto float64
+	MakerFeeToStable float64
+	MakerFeeToFiat   float64
+	DerivRebate      float64
+	TakerFeeToCrypto float64
+	TakerFeeToStable float64
+	TakerFeeToFiat   float64
+	DerivTakerFee    float64
+	LeoLevel         int64
+	LeoAmountAvg     float64
+}
+
+// FromRaw maps the raw account summary response to a Summary. The LEO level
+// is read from an object, which strict decoding does not cover.
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (s *Summary, err error) {
+	if len(raw) < 10 {
+		return s, fmt.Errorf("data slice too short for summary: %#v", raw)
+	}
+
+	fees, ok := raw[4].([]interface{})
+	if !ok || len(fees) < 2 {
+		return s, fmt.Errorf("unexpected fee data for summary: %#v", raw[4])
+	}
+
+	maker, ok := fees[0].([]interface{})
+	if !ok || len(maker) < 6 {
+		return s, fmt.Errorf("unexpected maker fee data for summary: %#v", fees[0])
+	}
+
+	taker, ok := fees[1].([]interface{})
+	if !ok || len(taker) < 6 {
+		return s, fmt.Errorf("unexpected taker fee data for summary: %#v", fees[1])
+	}
+
+	mf := convert.NewFields("summary maker fees", maker, opts...)
+	tf := convert.NewFields("summary taker fees", taker, opts...)
+
+	s = &Summary{
+		MakerFeeToCrypto: mf.F64(0),
+		MakerFeeToStable: mf.F64(1),
+		MakerFeeToFiat:   mf.F64(2),
+		DerivRebate:      mf.F64(5),
+		TakerFeeToCrypto: tf.F64(0),
+		TakerFeeToStable: tf.F64(1),
+		TakerFeeToFiat:   tf.F64(2),
+		DerivTakerFee:    tf.F64(5),
+	}
+
+	if err = mf.Err(); err != nil {
+		return nil, err
+	}
+	if err = tf.Err(); err != nil {
+		return nil, err
+	}
+
+	if leo, ok := raw[9].(map[string]interface{}); ok {
+		s.LeoLevel = convert.I64ValOrZero(leo["leo_lev"])
+		s.LeoAmountAvg = convert.F64ValOrZero(leo["leo_amount_avg"])
+	}
+
+	return
+}
@@ -0,0 +1,92 @@
+package summary_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/summary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var raw = []interface{}{
+	nil, nil, nil, nil,
+	[]interface{}{
+		[]interface{}{0.001, 0.0008, 0.0009, nil, nil, -0.0002},
+		[]interface{}{0.002, 0.0018, 0.0019, nil, nil, 0.00075},
+	},
+	nil, nil, nil, nil,
+	map[string]interface{}{"leo_lev": 1.0, "leo_amount_avg": 0.002},
+}
+
+func TestFromRaw(t *testing.T) {
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := summary.FromRaw([]interface{}{nil})
+		require.NotNil(t, err)
+	})
+
+	t.Run("valid arguments", func(t *testing.T) {
+		got, err := summary.FromRaw(raw)
+		require.Nil(t, err)
+
+		expected := &summary.Summary{
+			MakerFeeToCrypto: 0.001,
+			MakerFeeToStable: 0.0008,
+			MakerFeeToFiat:   0.0009,
+			DerivRebate:      -0.0002,
+			TakerFeeToCrypto: 0.002,
+			TakerFeeToStable: 0.0018,
+			TakerFeeToFiat:   0.0019,
+			DerivTakerFee:    0.00075,
+			LeoLevel:         1,
+			LeoAmountAvg:     0.002,
+		}
+		assert.Equal(t, expected, got)
+	})
+}
+
+func TestEstimateFee(t *testing.T) {
+	s, err := summary.FromRaw(raw)
+	require.Nil(t, err)
+	fc := summary.NewFeeCalculator(s)
+
+	cases := map[string]struct {
+		symbol   string
+		maker    bool
+		expected float64
+	}{
+		"fiat maker":     {"tBTCUSD", true, 0.0009 * 10000},
+		"fiat taker":     {"tBTCUSD", false, 0.0019 * 10000},
+		"stable taker":   {"tBTCUST", false, 0.0018 * 10000},
+		"crypto maker":   {"tETHBTC", true, 0.001 * 10000},
+		"colon quote":    {"tTESTBTC:TESTUSD", false, 0.002 * 10000},
+		"deriv rebate":   {"tBTCF0:USTF0", true, -0.0002 * 10000},
+		"deriv taker":    {"tBTCF0:USTF0", false, 0.00075 * 10000},
+		"long fiat pair": {"tDUSK:USD", true, 0.0009 * 10000},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			got, err := fc.EstimateFee(v.symbol, -0.5, 20000, v.maker)
+			require.Nil(t, err)
+			assert.InDelta(t, v.expected, got, 1e-9)
+		})
+	}
+
+	t.Run("leo discount on taker fees", func(t *testing.T) {
+		fc := summary.NewFeeCalculator(s).WithLeoDiscount(0.25)
+		got, err := fc.EstimateFee("tBTCUSD", 1, 10000, false)
+		require.Nil(t, err)
+		assert.InDelta(t, 0.0019*0.75*10000, got, 1e-9)
+
+		got, err = fc.EstimateFee("tBTCUSD", 1, 10000, true)
+		require.Nil(t, err)
+		assert.InDelta(t, 0.0009*10000, got, 1e-9)
+	})
+
+	t.Run("invalid symbol", func(t *testing.T) {
+		_, err := fc.EstimateFee("fUSD", 1, 1, true)
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+	})
+}
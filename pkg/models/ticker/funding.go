@@ -0,0 +1,81 @@
+package ticker
+
+import (
+	"fmt"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+)
+
+// FundingTicker is the ticker of a funding currency, e.g. fUSD. Rates are
+// daily rates and periods are in days.
+type FundingTicker struct {
+	Symbol             string
+	Frr                float64 // flash return rate
+	Bid                float64
+	BidPeriod          int64
+	BidSize            float64
+	Ask                float64
+	AskPeriod          int64
+	AskSize            float64
+	DailyChange        float64
+	DailyChangePerc    float64
+	LastPrice          float64
+	Volume             float64
+	High               float64
+	Low                float64
+	FrrAmountAvailable float64
+}
+
+type FundingSnapshot struct {
+	Snapshot []*FundingTicker
+}
+
+// FundingFromRaw decodes a funding ticker
+// [FRR, BID, BID_PERIOD, BID_SIZE, ASK, ASK_PERIOD, ASK_SIZE, DAILY_CHANGE,
+// DAILY_CHANGE_RELATIVE, LAST_PRICE, VOLUME, HIGH, LOW, _, _, FRR_AMOUNT_AVAILABLE]
+func FundingFromRaw(symbol string, raw []interface{}, opts ...convert.DecodeOptions) (*FundingTicker, error) {
+	if len(raw) < 13 {
+		return nil, fmt.Errorf("data slice too short for funding ticker: %#v", raw)
+	}
+
+	f := convert.NewFields("funding ticker", raw, opts...)
+	t := &FundingTicker{
+		Symbol:          symbol,
+		Frr:             f.F64(0),
+		Bid:             f.F64(1),
+		BidPeriod:       f.I64(2),
+		BidSize:         f.F64(3),
+		Ask:             f.F64(4),
+		AskPeriod:       f.I64(5),
+		AskSize:         f.F64(6),
+		DailyChange:     f.F64(7),
+		DailyChangePerc: f.F64(8),
+		LastPrice:       f.F64(9),
+		Volume:          f.F64(10),
+		High:            f.F64(11),
+		Low:             f.F64(12),
+	}
+	if len(raw) >= 16 {
+		t.FrrAmountAvailable = f.F64(15)
+	}
+	if err := f.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func FundingSnapshotFromRaw(symbol string, raw [][]interface{}, opts ...convert.DecodeOptions) (*FundingSnapshot, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("data slice too short for funding ticker snapshot: %#v", raw)
+	}
+
+	snap := make([]*FundingTicker, 0, len(raw))
+	for _, r := range raw {
+		t, err := FundingFromRaw(symbol, r, opts...)
+		if err != nil {
+			return nil, err
+		}
+		snap = append(snap, t)
+	}
+	return &FundingSnapshot{Snapshot: snap}, nil
+}
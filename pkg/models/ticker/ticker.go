@@ -33,14 +33,14 @@ type Snapshot struct {
 	Snapshot []*Ticker
 }
 
-func SnapshotFromRaw(symbol string, raw [][]interface{}) (*Snapshot, error) {
+func SnapshotFromRaw(symbol string, raw [][]interface{}, opts ...convert.DecodeOptions) (*Snapshot, error) {
 	if len(raw) == 0 {
 		return nil, fmt.Errorf("data slice too short for ticker snapshot: %#v", raw)
 	}
 
 	snap := make([]*Ticker, 0)
 	for _, f := range raw {
-		c, err := FromRaw(symbol, f)
+		c, err := FromRaw(symbol, f, opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -50,75 +50,61 @@ func SnapshotFromRaw(symbol string, raw [][]interface{}) (*Snapshot, error) {
 	return &Snapshot{Snapshot: snap}, nil
 }
 
-func FromRaw(symbol string, raw []interface{}) (t *Ticker, err error) {
+func FromRaw(symbol string, raw []interface{}, opts ...convert.DecodeOptions) (t *Ticker, err error) {
+	f := convert.NewFields("ticker", raw, opts...)
+
 	// trading pair update / snapshot
 	if strings.HasPrefix(symbol, "t") && len(raw) >= 10 {
 		t = &Ticker{
 			Symbol:          symbol,
-			Bid:             convert.F64ValOrZero(raw[0]),
-			BidSize:         convert.F64ValOrZero(raw[1]),
-			Ask:             convert.F64ValOrZero(raw[2]),
-			AskSize:         convert.F64ValOrZero(raw[3]),
-			DailyChange:     convert.F64ValOrZero(raw[4]),
-			DailyChangePerc: convert.F64ValOrZero(raw[5]),
-			LastPrice:       convert.F64ValOrZero(raw[6]),
-			Volume:          convert.F64ValOrZero(raw[7]),
-			High:            convert.F64ValOrZero(raw[8]),
-			Low:             convert.F64ValOrZero(raw[9]),
+			Bid:             f.F64(0),
+			BidSize:         f.F64(1),
+			Ask:             f.F64(2),
+			AskSize:         f.F64(3),
+			DailyChange:     f.F64(4),
+			DailyChangePerc: f.F64(5),
+			LastPrice:       f.F64(6),
+			Volume:          f.F64(7),
+			High:            f.F64(8),
+			Low:             f.F64(9),
+		}
+		if err = f.Err(); err != nil {
+			return nil, err
 		}
 		return
 	}
 
-	// funding pair update
+	// funding pair update / snapshot
 	if strings.HasPrefix(symbol, "f") {
-		if len(raw) >= 13 {
-			t = &Ticker{
-				Symbol:          symbol,
-				Frr:             convert.F64ValOrZero(raw[0]),
-				Bid:             convert.F64ValOrZero(raw[1]),
-				BidPeriod:       convert.I64ValOrZero(raw[2]),
-				BidSize:         convert.F64ValOrZero(raw[3]),
-				Ask:             convert.F64ValOrZero(raw[4]),
-				AskPeriod:       convert.I64ValOrZero(raw[5]),
-				AskSize:         convert.F64ValOrZero(raw[6]),
-				DailyChange:     convert.F64ValOrZero(raw[7]),
-				DailyChangePerc: convert.F64ValOrZero(raw[8]),
-				LastPrice:       convert.F64ValOrZero(raw[9]),
-				Volume:          convert.F64ValOrZero(raw[10]),
-				High:            convert.F64ValOrZero(raw[11]),
-				Low:             convert.F64ValOrZero(raw[12]),
-			}
-		}
-
-		// funding pair snapshot
-		if len(raw) >= 16 {
-			t.FrrAmountAvailable = convert.F64ValOrZero(raw[15])
+		ft, err := FundingFromRaw(symbol, raw, opts...)
+		if err != nil {
+			return nil, err
 		}
-		return
+		return (*Ticker)(ft), nil
 	}
 
 	err = fmt.Errorf("unrecognized data slice format for pair:%s, data:%#v", symbol, raw)
 	return
 }
 
-func FromRestRaw(raw []interface{}) (t *Ticker, err error) {
+func FromRestRaw(raw []interface{}, opts ...convert.DecodeOptions) (t *Ticker, err error) {
 	if len(raw) == 0 {
 		return t, fmt.Errorf("data slice too short for ticker")
 	}
 
-	return FromRaw(raw[0].(string), raw[1:])
+	return FromRaw(raw[0].(string), raw[1:], opts...)
 }
 
 // FromWSRaw - based on condition will return snapshot of tickers or single tick
-func FromWSRaw(symbol string, data []interface{}) (interface{}, error) {
+func FromWSRaw(symbol string, data []interface{}, opts ...convert.DecodeOptions) (interface{}, error) {
 	if len(data) == 0 {
 		return nil, errors.New("empty data slice")
 	}
 
 	_, isSnapshot := data[0].([]interface{})
 	if isSnapshot {
-		return SnapshotFromRaw(symbol, convert.ToInterfaceArray(data))
+		return SnapshotFromRaw(symbol, convert.ToInterfaceArray(data), opts...)
 	}
 
-	return FromRaw(symbol, data)
+	return FromRaw(symbol, data, opts...)
 }
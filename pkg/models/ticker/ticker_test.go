@@ -345,3 +345,41 @@ func TestFromWSRaw(t *testing.T) {
 		assert.Equal(t, expected, got)
 	})
 }
+
+func TestFundingFromRaw(t *testing.T) {
+	t.Run("insufficient arguments", func(t *testing.T) {
+		got, err := ticker.FundingFromRaw("fUSD", []interface{}{0.0002, 0.0001})
+		require.NotNil(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("valid arguments", func(t *testing.T) {
+		payload := []interface{}{
+			0.0003447013698630137, 0.000316, 30, 1575.2, 0.000195, 2, 24812.0,
+			-0.00005, -0.1398, 0.000195, 53424.2, 0.000411, 0.00012, nil, nil, 3466.5,
+		}
+		got, err := ticker.FundingFromRaw("fUSD", payload)
+		require.Nil(t, err)
+		assert.Equal(t, &ticker.FundingTicker{
+			Symbol:             "fUSD",
+			Frr:                0.0003447013698630137,
+			Bid:                0.000316,
+			BidPeriod:          30,
+			BidSize:            1575.2,
+			Ask:                0.000195,
+			AskPeriod:          2,
+			AskSize:            24812.0,
+			DailyChange:        -0.00005,
+			DailyChangePerc:    -0.1398,
+			LastPrice:          0.000195,
+			Volume:             53424.2,
+			High:               0.000411,
+			Low:                0.00012,
+			FrrAmountAvailable: 3466.5,
+		}, got)
+
+		snap, err := ticker.FundingSnapshotFromRaw("fUSD", [][]interface{}{payload})
+		require.Nil(t, err)
+		assert.Equal(t, got, snap.Snapshot[0])
+	})
+}
@@ -33,35 +33,43 @@ type Snapshot struct {
 	Snapshot []TickerHist
 }
 
-func SnapshotFromRaw(raw [][]interface{}) (ss Snapshot) {
+// SnapshotFromRaw decodes the ticker history, skipping entries which are too
+// short. Entries failing strict decoding fail the snapshot.
+func SnapshotFromRaw(raw [][]interface{}, opts ...convert.DecodeOptions) (ss Snapshot, err error) {
 	if len(raw) == 0 {
 		return
 	}
 
 	snap := make([]TickerHist, 0)
 	for _, r := range raw {
-		th, err := FromRaw(r)
-		if err != nil {
+		if len(r) < 13 {
 			continue
 		}
+		th, err := FromRaw(r, opts...)
+		if err != nil {
+			return ss, err
+		}
 		snap = append(snap, th)
 	}
 
-	return Snapshot{Snapshot: snap}
+	return Snapshot{Snapshot: snap}, nil
 }
 
-func FromRaw(raw []interface{}) (t TickerHist, err error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (t TickerHist, err error) {
 	// to avoid index out of range issue
 	if len(raw) < 13 {
 		err = fmt.Errorf("data slice too short for ticker history, data:%#v", raw)
 		return
 	}
 
+	f := convert.NewFields("ticker history", raw, opts...)
+
 	t = TickerHist{
-		Symbol: convert.SValOrEmpty(raw[tickerHistFields["Symbol"]]),
-		Bid:    convert.F64ValOrZero(raw[tickerHistFields["Bid"]]),
-		Ask:    convert.F64ValOrZero(raw[tickerHistFields["Ask"]]),
-		MTS:    convert.I64ValOrZero(raw[tickerHistFields["Mts"]]),
+		Symbol: f.S(tickerHistFields["Symbol"]),
+		Bid:    f.F64(tickerHistFields["Bid"]),
+		Ask:    f.F64(tickerHistFields["Ask"]),
+		MTS:    f.I64(tickerHistFields["Mts"]),
 	}
+	err = f.Err()
 	return
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tickerhist"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTickerHistFromRaw(t *testing.T) {
@@ -80,7 +81,8 @@ func TestTickerHistSnapshotFromRaw(t *testing.T) {
 
 	for k, v := range cases {
 		t.Run(k, func(t *testing.T) {
-			got := tickerhist.SnapshotFromRaw(v.pld)
+			got, err := tickerhist.SnapshotFromRaw(v.pld)
+			require.Nil(t, err)
 			assert.Equal(t, v.expected, got)
 		})
 	}
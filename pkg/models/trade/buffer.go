@@ -0,0 +1,204 @@
+package trade
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+)
+
+type pending struct {
+	trades   []*Trade    // sorted by ID
+	arrived  []time.Time // arrival of trades
+	released int64       // ID of the last released trade
+}
+
+// Buffer deduplicates and orders the trades of the public trade stream by
+// ID. The stream sends a snapshot of recent trades on every subscription, so
+// after a resubscribe trades which were already seen arrive again, and trades
+// of snapshots and updates may interleave out of order. The buffer holds up to
+// window trades per pair and releases them in ascending ID order, dropping
+// trades which are already held or were released before, e.g.:
+//
+//	for ev := range client.Listen() {
+//		for _, t := range buf.Handle(ev) {
+//			builder.Add(t)
+//		}
+//	}
+//
+// A larger window tolerates more reordering at the cost of delaying trades.
+// Trades are held until window newer trades of their pair arrive, so the last
+// trades of a quiet pair stay held. WithMaxAge releases trades once they were
+// held for a while, which Add checks for every pair. Callers whose stream may
+// go quiet call Expire or Flush periodically, e.g. from a ticker.
+type Buffer struct {
+	mu      sync.Mutex
+	window  int
+	maxAge  time.Duration
+	clock   utils.Clock
+	pairs   map[string]*pending
+	dropped int
+}
+
+// NewBuffer returns a buffer holding up to window trades per pair. A window
+// below one releases trades as soon as they arrive, which still drops
+// duplicates and trades older than the ones released.
+func NewBuffer(window int) *Buffer {
+	if window < 0 {
+		window = 0
+	}
+	return &Buffer{window: window, clock: utils.SystemClock, pairs: make(map[string]*pending)}
+}
+
+// WithMaxAge releases trades held for longer than d regardless of the window,
+// along with the older trades of their pair. The clock times the age,
+// defaulting to utils.SystemClock.
+func (b *Buffer) WithMaxAge(d time.Duration, clock utils.Clock) *Buffer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if clock == nil {
+		clock = utils.SystemClock
+	}
+	b.maxAge, b.clock = d, clock
+	return b
+}
+
+// Handle adds trade and trade snapshot events and returns the trades
+// released, other events are ignored.
+func (b *Buffer) Handle(ev interface{}) []*Trade {
+	switch e := ev.(type) {
+	case *Trade:
+		return b.Add(e)
+	case *Snapshot:
+		return b.Add(e.Snapshot...)
+	}
+	return nil
+}
+
+// Add adds trades in any order and returns the trades released, sorted by ID
+// per pair
+func (b *Buffer) Add(trades ...*Trade) []*Trade {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	touched := []string{}
+	for _, t := range trades {
+		if t == nil {
+			continue
+		}
+		p, ok := b.pairs[t.Pair]
+		if !ok {
+			p = &pending{}
+			b.pairs[t.Pair] = p
+		}
+		if !p.insert(t, now) {
+			b.dropped++
+			continue
+		}
+		touched = append(touched, t.Pair)
+	}
+
+	released := []*Trade{}
+	seen := make(map[string]bool, len(touched))
+	for _, pair := range touched {
+		if seen[pair] {
+			continue
+		}
+		seen[pair] = true
+		released = append(released, b.pairs[pair].release(b.window)...)
+	}
+	return append(released, b.expire(now)...)
+}
+
+// Expire releases the trades held for longer than the max age, see
+// WithMaxAge, sorted by ID per pair
+func (b *Buffer) Expire() []*Trade {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.expire(b.clock.Now())
+}
+
+// Flush releases all trades held, sorted by ID per pair
+func (b *Buffer) Flush() []*Trade {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	released := []*Trade{}
+	for _, pair := range b.sortedPairs() {
+		released = append(released, b.pairs[pair].release(0)...)
+	}
+	return released
+}
+
+// Dropped returns the number of duplicate and late trades dropped so far
+func (b *Buffer) Dropped() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
+
+// expire releases the trades held for longer than the max age at now
+func (b *Buffer) expire(now time.Time) []*Trade {
+	released := []*Trade{}
+	if b.maxAge <= 0 {
+		return released
+	}
+	cutoff := now.Add(-b.maxAge)
+	for _, pair := range b.sortedPairs() {
+		p := b.pairs[pair]
+		// trades are released in ID order, so an expired trade releases
+		// the older ones as well
+		n := 0
+		for i, at := range p.arrived {
+			if !at.After(cutoff) {
+				n = i + 1
+			}
+		}
+		released = append(released, p.release(len(p.trades)-n)...)
+	}
+	return released
+}
+
+func (b *Buffer) sortedPairs() []string {
+	pairs := make([]string, 0, len(b.pairs))
+	for pair := range b.pairs {
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// insert adds the trade in ID order, reporting false for a trade which is
+// held already or not newer than the last one released
+func (p *pending) insert(t *Trade, now time.Time) bool {
+	if t.ID <= p.released {
+		return false
+	}
+	i := sort.Search(len(p.trades), func(i int) bool { return p.trades[i].ID >= t.ID })
+	if i < len(p.trades) && p.trades[i].ID == t.ID {
+		return false
+	}
+	p.trades = append(p.trades, nil)
+	copy(p.trades[i+1:], p.trades[i:])
+	p.trades[i] = t
+	p.arrived = append(p.arrived, time.Time{})
+	copy(p.arrived[i+1:], p.arrived[i:])
+	p.arrived[i] = now
+	return true
+}
+
+// release removes and returns the oldest trades exceeding the window
+func (p *pending) release(window int) []*Trade {
+	n := len(p.trades) - window
+	if n <= 0 {
+		return nil
+	}
+	out := make([]*Trade, n)
+	copy(out, p.trades[:n])
+	p.trades = append(p.trades[:0], p.trades[n:]...)
+	p.arrived = append(p.arrived[:0], p.arrived[n:]...)
+	p.released = out[n-1].ID
+	return out
+}
@@ -0,0 +1,80 @@
+package trade_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func ids(trades []*trade.Trade) []int64 {
+	out := []int64{}
+	for _, t := range trades {
+		out = append(out, t.ID)
+	}
+	return out
+}
+
+func tr(pair string, id int64) *trade.Trade {
+	return &trade.Trade{Pair: pair, ID: id, MTS: 1000 + id, Amount: 1, Price: 100}
+}
+
+func TestBuffer(t *testing.T) {
+	t.Run("releases in id order beyond the window", func(t *testing.T) {
+		b := trade.NewBuffer(2)
+		assert.Empty(t, b.Add(tr("tBTCUSD", 3), tr("tBTCUSD", 1)))
+		assert.Equal(t, []int64{1}, ids(b.Add(tr("tBTCUSD", 2))))
+		assert.Equal(t, []int64{2, 3}, ids(b.Add(tr("tBTCUSD", 5), tr("tBTCUSD", 4))))
+		assert.Equal(t, []int64{4, 5}, ids(b.Flush()))
+		assert.Empty(t, b.Flush())
+	})
+
+	t.Run("drops retransmitted trades", func(t *testing.T) {
+		b := trade.NewBuffer(1)
+		assert.Empty(t, b.Handle(tr("tBTCUSD", 1)))
+		assert.Equal(t, []int64{1}, ids(b.Handle(tr("tBTCUSD", 2))))
+
+		// snapshot after a resubscribe, newest first
+		snap := &trade.Snapshot{Snapshot: []*trade.Trade{
+			tr("tBTCUSD", 4), tr("tBTCUSD", 3), tr("tBTCUSD", 2), tr("tBTCUSD", 1),
+		}}
+		assert.Equal(t, []int64{2, 3}, ids(b.Handle(snap)))
+		assert.Equal(t, 2, b.Dropped())
+		assert.Equal(t, []int64{4}, ids(b.Flush()))
+	})
+
+	t.Run("keeps pairs apart", func(t *testing.T) {
+		b := trade.NewBuffer(1)
+		assert.Empty(t, b.Add(tr("tETHUSD", 5), tr("tBTCUSD", 7)))
+		assert.Equal(t, []int64{5}, ids(b.Add(tr("tETHUSD", 6))))
+		assert.Equal(t, []int64{7, 6}, ids(b.Flush()))
+		assert.Equal(t, 0, b.Dropped())
+	})
+
+	t.Run("zero window", func(t *testing.T) {
+		b := trade.NewBuffer(0)
+		assert.Equal(t, []int64{2}, ids(b.Add(tr("tBTCUSD", 2))))
+		assert.Empty(t, b.Add(tr("tBTCUSD", 1), tr("tBTCUSD", 2)))
+		assert.Equal(t, 2, b.Dropped())
+		assert.Empty(t, b.Handle("ignored"))
+	})
+
+	t.Run("releases trades held beyond the max age", func(t *testing.T) {
+		clock := utils.NewManualClock(time.Unix(0, 0))
+		b := trade.NewBuffer(3).WithMaxAge(time.Second, clock)
+		assert.Empty(t, b.Add(tr("tBTCUSD", 2), tr("tETHUSD", 9)))
+		clock.Advance(500 * time.Millisecond)
+		assert.Empty(t, b.Add(tr("tBTCUSD", 1), tr("tBTCUSD", 4)))
+		assert.Empty(t, b.Expire())
+
+		// 2 expired and releases 1 with it, 9 expires on a quiet pair
+		clock.Advance(500 * time.Millisecond)
+		assert.Equal(t, []int64{1, 2, 9}, ids(b.Add(tr("tBTCUSD", 3))))
+		clock.Advance(time.Second)
+		assert.Equal(t, []int64{3, 4}, ids(b.Expire()))
+		assert.Empty(t, b.Flush())
+		assert.Equal(t, 0, b.Dropped())
+	})
+}
@@ -4,8 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/fixed"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 )
 
 // Trade represents a trade on the public data feed.
@@ -19,18 +22,39 @@ type Trade struct {
 	Period int
 }
 
+// Time returns the execution time of the trade
+func (t *Trade) Time() time.Time {
+	return common.Mts(t.MTS).Time()
+}
+
+// Fixed returns the price and amount as fixed-point values at the given scales
+func (t *Trade) Fixed(s fixed.SymbolScale) (price, amount int64, err error) {
+	if price, err = s.Price.FromFloat(t.Price); err != nil {
+		return 0, 0, err
+	}
+	if amount, err = s.Amount.FromFloat(t.Amount); err != nil {
+		return 0, 0, err
+	}
+	return price, amount, nil
+}
+
 type Snapshot struct {
 	Snapshot []*Trade
 }
 
-func FromRaw(pair string, raw []interface{}) (t *Trade, err error) {
+func FromRaw(pair string, raw []interface{}, opts ...convert.DecodeOptions) (t *Trade, err error) {
+	f := convert.NewFields("trade", raw, opts...)
+
 	if strings.HasPrefix(pair, "t") && len(raw) >= 4 {
 		t = &Trade{
 			Pair:   pair,
-			ID:     convert.I64ValOrZero(raw[0]),
-			MTS:    convert.I64ValOrZero(raw[1]),
-			Amount: convert.F64ValOrZero(raw[2]),
-			Price:  convert.F64ValOrZero(raw[3]),
+			ID:     f.I64(0),
+			MTS:    f.I64(1),
+			Amount: f.F64(2),
+			Price:  f.F64(3),
+		}
+		if err = f.Err(); err != nil {
+			return nil, err
 		}
 		return
 	}
@@ -38,11 +62,14 @@ func FromRaw(pair string, raw []interface{}) (t *Trade, err error) {
 	if strings.HasPrefix(pair, "f") && len(raw) >= 5 {
 		t = &Trade{
 			Pair:   pair,
-			ID:     convert.I64ValOrZero(raw[0]),
-			MTS:    convert.I64ValOrZero(raw[1]),
-			Amount: convert.F64ValOrZero(raw[2]),
-			Rate:   convert.F64ValOrZero(raw[3]),
-			Period: convert.ToInt(raw[4]),
+			ID:     f.I64(0),
+			MTS:    f.I64(1),
+			Amount: f.F64(2),
+			Rate:   f.F64(3),
+			Period: f.Int(4),
+		}
+		if err = f.Err(); err != nil {
+			return nil, err
 		}
 		return
 	}
@@ -51,14 +78,14 @@ func FromRaw(pair string, raw []interface{}) (t *Trade, err error) {
 	return
 }
 
-func SnapshotFromRaw(pair string, raw [][]interface{}) (*Snapshot, error) {
+func SnapshotFromRaw(pair string, raw [][]interface{}, opts ...convert.DecodeOptions) (*Snapshot, error) {
 	if len(raw) == 0 {
 		return nil, fmt.Errorf("data slice is too short for trade snapshot: %#v", raw)
 	}
 
 	snapshot := make([]*Trade, 0)
 	for _, v := range raw {
-		t, err := FromRaw(pair, v)
+		t, err := FromRaw(pair, v, opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -69,15 +96,15 @@ func SnapshotFromRaw(pair string, raw [][]interface{}) (*Snapshot, error) {
 }
 
 // FromWSRaw - based on condition will return snapshot of trades or single trade
-func FromWSRaw(pair string, data []interface{}) (interface{}, error) {
+func FromWSRaw(pair string, data []interface{}, opts ...convert.DecodeOptions) (interface{}, error) {
 	if len(data) == 0 {
 		return nil, errors.New("empty data slice")
 	}
 
 	_, isSnapshot := data[0].([]interface{})
 	if isSnapshot {
-		return SnapshotFromRaw(pair, convert.ToInterfaceArray(data))
+		return SnapshotFromRaw(pair, convert.ToInterfaceArray(data), opts...)
 	}
 
-	return FromRaw(pair, data)
+	return FromRaw(pair, data, opts...)
 }
@@ -19,25 +19,31 @@ type TradeExecution struct {
 	Maker      int
 }
 
-func FromRaw(raw []interface{}) (te *TradeExecution, err error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (te *TradeExecution, err error) {
 	if len(raw) < 6 {
 		return te, fmt.Errorf("data slice too short for trade execution: %#v", raw)
 	}
 
+	f := convert.NewFields("trade execution", raw, opts...)
+
 	// trade executions sometimes omit order type, price, and maker flag
 	te = &TradeExecution{
-		ID:         convert.I64ValOrZero(raw[0]),
-		Pair:       convert.SValOrEmpty(raw[1]),
-		MTS:        convert.I64ValOrZero(raw[2]),
-		OrderID:    convert.I64ValOrZero(raw[3]),
-		ExecAmount: convert.F64ValOrZero(raw[4]),
-		ExecPrice:  convert.F64ValOrZero(raw[5]),
+		ID:         f.I64(0),
+		Pair:       f.S(1),
+		MTS:        f.I64(2),
+		OrderID:    f.I64(3),
+		ExecAmount: f.F64(4),
+		ExecPrice:  f.F64(5),
 	}
 
 	if len(raw) >= 9 {
-		te.OrderType = convert.SValOrEmpty(raw[6])
-		te.OrderPrice = convert.F64ValOrZero(raw[7])
-		te.Maker = convert.ToInt(raw[8])
+		te.OrderType = f.S(6)
+		te.OrderPrice = f.F64(7)
+		te.Maker = f.Int(8)
+	}
+
+	if err = f.Err(); err != nil {
+		return nil, err
 	}
 
 	return
@@ -2,8 +2,10 @@ package tradeexecutionupdate
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 )
 
 // TradeExecutionUpdate represents a full update to a trade on the private data feed.  Following a TradeExecution,
@@ -20,6 +22,18 @@ type TradeExecutionUpdate struct {
 	Maker       int
 	Fee         float64
 	FeeCurrency string
+	// ClientOrderID is only set by the trades history endpoint
+	ClientOrderID int64
+}
+
+// IsMaker reports whether the trade added liquidity to the book
+func (tu *TradeExecutionUpdate) IsMaker() bool {
+	return tu.Maker == 1
+}
+
+// Time returns the execution time of the trade
+func (tu *TradeExecutionUpdate) Time() time.Time {
+	return common.Mts(tu.MTS).Time()
 }
 
 type Snapshot struct {
@@ -29,36 +43,47 @@ type Snapshot struct {
 type HistoricalTradeSnapshot Snapshot
 
 // public trade update just looks like a trade
-func FromRaw(raw []interface{}) (tu *TradeExecutionUpdate, err error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (tu *TradeExecutionUpdate, err error) {
+	f := convert.NewFields("trade execution update", raw, opts...)
+
 	if len(raw) == 4 {
 		tu = &TradeExecutionUpdate{
-			ID:         convert.I64ValOrZero(raw[0]),
-			MTS:        convert.I64ValOrZero(raw[1]),
-			ExecAmount: convert.F64ValOrZero(raw[2]),
-			ExecPrice:  convert.F64ValOrZero(raw[3]),
+			ID:         f.I64(0),
+			MTS:        f.I64(1),
+			ExecAmount: f.F64(2),
+			ExecPrice:  f.F64(3),
+		}
+		if err = f.Err(); err != nil {
+			return nil, err
 		}
 		return
 	}
 	if len(raw) > 10 {
 		tu = &TradeExecutionUpdate{
-			ID:          convert.I64ValOrZero(raw[0]),
-			Pair:        convert.SValOrEmpty(raw[1]),
-			MTS:         convert.I64ValOrZero(raw[2]),
-			OrderID:     convert.I64ValOrZero(raw[3]),
-			ExecAmount:  convert.F64ValOrZero(raw[4]),
-			ExecPrice:   convert.F64ValOrZero(raw[5]),
-			OrderType:   convert.SValOrEmpty(raw[6]),
-			OrderPrice:  convert.F64ValOrZero(raw[7]),
-			Maker:       convert.ToInt(raw[8]),
-			Fee:         convert.F64ValOrZero(raw[9]),
-			FeeCurrency: convert.SValOrEmpty(raw[10]),
+			ID:          f.I64(0),
+			Pair:        f.S(1),
+			MTS:         f.I64(2),
+			OrderID:     f.I64(3),
+			ExecAmount:  f.F64(4),
+			ExecPrice:   f.F64(5),
+			OrderType:   f.S(6),
+			OrderPrice:  f.F64(7),
+			Maker:       f.Int(8),
+			Fee:         f.F64(9),
+			FeeCurrency: f.S(10),
+		}
+		if len(raw) > 11 {
+			tu.ClientOrderID = f.I64(11)
+		}
+		if err = f.Err(); err != nil {
+			return nil, err
 		}
 		return
 	}
 	return tu, fmt.Errorf("data slice too short for trade update: %#v", raw)
 }
 
-func SnapshotFromRaw(raw []interface{}) (s *Snapshot, err error) {
+func SnapshotFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (s *Snapshot, err error) {
 	if len(raw) == 0 {
 		return nil, fmt.Errorf("data slice is too short for trade execution update snapshot: %#v", raw)
 	}
@@ -66,7 +91,7 @@ func SnapshotFromRaw(raw []interface{}) (s *Snapshot, err error) {
 	ts := make([]*TradeExecutionUpdate, 0)
 	for _, v := range raw {
 		if l, ok := v.([]interface{}); ok {
-			t, err := FromRaw(l)
+			t, err := FromRaw(l, opts...)
 			if err != nil {
 				return s, err
 			}
@@ -26,28 +26,34 @@ type AuthFundingTradeSnapshot struct {
 }
 
 // AFTFromRaw maps raw data slice to instance of AuthFundingTrade
-func AFTFromRaw(raw []interface{}) (aft AuthFundingTrade, err error) {
+func AFTFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (aft AuthFundingTrade, err error) {
 	if len(raw) < 8 {
 		return AuthFundingTrade{}, fmt.Errorf("data slice too short for funding trade: %#v", raw)
 	}
 
+	f := convert.NewFields("funding trade", raw, opts...)
+
 	aft = AuthFundingTrade{
-		ID:         convert.I64ValOrZero(raw[0]),
-		Symbol:     convert.SValOrEmpty(raw[1]),
-		MTSCreated: convert.I64ValOrZero(raw[2]),
-		OfferID:    convert.I64ValOrZero(raw[3]),
-		Amount:     convert.F64ValOrZero(raw[4]),
-		Rate:       convert.F64ValOrZero(raw[5]),
-		Period:     convert.I64ValOrZero(raw[6]),
-		Maker:      convert.I64ValOrZero(raw[7]),
+		ID:         f.I64(0),
+		Symbol:     f.S(1),
+		MTSCreated: f.I64(2),
+		OfferID:    f.I64(3),
+		Amount:     f.F64(4),
+		Rate:       f.F64(5),
+		Period:     f.I64(6),
+		Maker:      f.I64(7),
+	}
+
+	if err = f.Err(); err != nil {
+		return AuthFundingTrade{}, err
 	}
 
 	return
 }
 
 // AFTUFromRaw maps raw data slice to instance of AuthFundingTradeUpdate
-func AFTUFromRaw(raw []interface{}) (AuthFundingTradeUpdate, error) {
-	aft, err := AFTFromRaw(raw)
+func AFTUFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (AuthFundingTradeUpdate, error) {
+	aft, err := AFTFromRaw(raw, opts...)
 	if err != nil {
 		return AuthFundingTradeUpdate{}, err
 	}
@@ -56,8 +62,8 @@ func AFTUFromRaw(raw []interface{}) (AuthFundingTradeUpdate, error) {
 }
 
 // AFTEFromRaw maps raw data slice to instance of AuthFundingTradeExecuted
-func AFTEFromRaw(raw []interface{}) (AuthFundingTradeExecuted, error) {
-	aft, err := AFTFromRaw(raw)
+func AFTEFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (AuthFundingTradeExecuted, error) {
+	aft, err := AFTFromRaw(raw, opts...)
 	if err != nil {
 		return AuthFundingTradeExecuted{}, err
 	}
@@ -66,14 +72,14 @@ func AFTEFromRaw(raw []interface{}) (AuthFundingTradeExecuted, error) {
 }
 
 // AFTSnapshotFromRaw maps raw data slice to authenticated funding trade data structures
-func AFTSnapshotFromRaw(raw [][]interface{}) (AuthFundingTradeSnapshot, error) {
+func AFTSnapshotFromRaw(raw [][]interface{}, opts ...convert.DecodeOptions) (AuthFundingTradeSnapshot, error) {
 	if len(raw) == 0 {
 		return AuthFundingTradeSnapshot{}, fmt.Errorf("data slice too short for funding trade snapshot: %#v", raw)
 	}
 
 	snap := make([]AuthFundingTrade, 0)
 	for _, r := range raw {
-		ft, err := AFTFromRaw(r)
+		ft, err := AFTFromRaw(r, opts...)
 		if err != nil {
 			return AuthFundingTradeSnapshot{}, err
 		}
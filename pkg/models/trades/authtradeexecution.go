@@ -21,22 +21,28 @@ type AuthTradeExecution struct {
 }
 
 // ATEFromRaw - authenticated trade execution mapping to data type
-func ATEFromRaw(raw []interface{}) (e AuthTradeExecution, err error) {
+func ATEFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (e AuthTradeExecution, err error) {
 	if len(raw) < 12 {
 		return AuthTradeExecution{}, fmt.Errorf("data slice too short for auth trade execution: %#v", raw)
 	}
 
+	f := convert.NewFields("auth trade execution", raw, opts...)
+
 	e = AuthTradeExecution{
-		ID:            convert.I64ValOrZero(raw[0]),
-		Pair:          convert.SValOrEmpty(raw[1]),
-		MTS:           convert.I64ValOrZero(raw[2]),
-		OrderID:       convert.I64ValOrZero(raw[3]),
-		ExecAmount:    convert.F64ValOrZero(raw[4]),
-		ExecPrice:     convert.F64ValOrZero(raw[5]),
-		OrderType:     convert.SValOrEmpty(raw[6]),
-		OrderPrice:    convert.F64ValOrZero(raw[7]),
-		Maker:         convert.ToInt(raw[8]),
-		ClientOrderID: convert.I64ValOrZero(raw[11]),
+		ID:            f.I64(0),
+		Pair:          f.S(1),
+		MTS:           f.I64(2),
+		OrderID:       f.I64(3),
+		ExecAmount:    f.F64(4),
+		ExecPrice:     f.F64(5),
+		OrderType:     f.S(6),
+		OrderPrice:    f.F64(7),
+		Maker:         f.Int(8),
+		ClientOrderID: f.I64(11),
+	}
+
+	if err = f.Err(); err != nil {
+		return AuthTradeExecution{}, err
 	}
 
 	return
@@ -22,23 +22,29 @@ type AuthTradeExecutionUpdate struct {
 }
 
 // ATEUFromRaw authenticated trade execution update mapping to data type
-func ATEUFromRaw(raw []interface{}) (eu AuthTradeExecutionUpdate, err error) {
+func ATEUFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (eu AuthTradeExecutionUpdate, err error) {
 	if len(raw) < 11 {
 		return AuthTradeExecutionUpdate{}, fmt.Errorf("data slice too short for auth trade execution update: %#v", raw)
 	}
 
+	f := convert.NewFields("auth trade execution update", raw, opts...)
+
 	eu = AuthTradeExecutionUpdate{
-		ID:          convert.I64ValOrZero(raw[0]),
-		Pair:        convert.SValOrEmpty(raw[1]),
-		MTS:         convert.I64ValOrZero(raw[2]),
-		OrderID:     convert.I64ValOrZero(raw[3]),
-		ExecAmount:  convert.F64ValOrZero(raw[4]),
-		ExecPrice:   convert.F64ValOrZero(raw[5]),
-		OrderType:   convert.SValOrEmpty(raw[6]),
-		OrderPrice:  convert.F64ValOrZero(raw[7]),
-		Maker:       convert.ToInt(raw[8]),
-		Fee:         convert.F64ValOrZero(raw[9]),
-		FeeCurrency: convert.SValOrEmpty(raw[10]),
+		ID:          f.I64(0),
+		Pair:        f.S(1),
+		MTS:         f.I64(2),
+		OrderID:     f.I64(3),
+		ExecAmount:  f.F64(4),
+		ExecPrice:   f.F64(5),
+		OrderType:   f.S(6),
+		OrderPrice:  f.F64(7),
+		Maker:       f.Int(8),
+		Fee:         f.F64(9),
+		FeeCurrency: f.S(10),
+	}
+
+	if err = f.Err(); err != nil {
+		return AuthTradeExecutionUpdate{}, err
 	}
 
 	return
@@ -25,15 +25,19 @@ type FundingTradeSnapshot struct {
 }
 
 // FTFromRaw maps raw data slice to instance of FundingTrade
-func FTFromRaw(pair string, raw []interface{}) (t FundingTrade, err error) {
+func FTFromRaw(pair string, raw []interface{}, opts ...convert.DecodeOptions) (t FundingTrade, err error) {
+	f := convert.NewFields("funding trade", raw, opts...)
 	if len(raw) >= 5 {
 		t = FundingTrade{
 			Symbol: pair,
-			ID:     convert.I64ValOrZero(raw[0]),
-			MTS:    convert.I64ValOrZero(raw[1]),
-			Amount: convert.F64ValOrZero(raw[2]),
-			Rate:   convert.F64ValOrZero(raw[3]),
-			Period: convert.ToInt(raw[4]),
+			ID:     f.I64(0),
+			MTS:    f.I64(1),
+			Amount: f.F64(2),
+			Rate:   f.F64(3),
+			Period: f.Int(4),
+		}
+		if err = f.Err(); err != nil {
+			return FundingTrade{}, err
 		}
 		return
 	}
@@ -43,8 +47,8 @@ func FTFromRaw(pair string, raw []interface{}) (t FundingTrade, err error) {
 }
 
 // FTEFromRaw maps raw data slice to instance of FundingTradeExecuted
-func FTEFromRaw(pair string, raw []interface{}) (FundingTradeExecuted, error) {
-	ft, err := FTFromRaw(pair, raw)
+func FTEFromRaw(pair string, raw []interface{}, opts ...convert.DecodeOptions) (FundingTradeExecuted, error) {
+	ft, err := FTFromRaw(pair, raw, opts...)
 	if err != nil {
 		return FundingTradeExecuted{}, err
 	}
@@ -53,8 +57,8 @@ func FTEFromRaw(pair string, raw []interface{}) (FundingTradeExecuted, error) {
 }
 
 // FTEUFromRaw maps raw data slice to instance of FundingTradeExecutionUpdate
-func FTEUFromRaw(pair string, raw []interface{}) (FundingTradeExecutionUpdate, error) {
-	ft, err := FTFromRaw(pair, raw)
+func FTEUFromRaw(pair string, raw []interface{}, opts ...convert.DecodeOptions) (FundingTradeExecutionUpdate, error) {
+	ft, err := FTFromRaw(pair, raw, opts...)
 	if err != nil {
 		return FundingTradeExecutionUpdate{}, err
 	}
@@ -63,14 +67,14 @@ func FTEUFromRaw(pair string, raw []interface{}) (FundingTradeExecutionUpdate, e
 }
 
 // FTSnapshotFromRaw maps raw data slice to funding trade data structures
-func FTSnapshotFromRaw(pair string, raw [][]interface{}) (FundingTradeSnapshot, error) {
+func FTSnapshotFromRaw(pair string, raw [][]interface{}, opts ...convert.DecodeOptions) (FundingTradeSnapshot, error) {
 	if len(raw) == 0 {
 		return FundingTradeSnapshot{}, fmt.Errorf("funding trade snapshot data slice too short:%#v", raw)
 	}
 
 	snapshot := make([]FundingTrade, 0)
 	for _, v := range raw {
-		t, err := FTFromRaw(pair, v)
+		t, err := FTFromRaw(pair, v, opts...)
 		if err != nil {
 			return FundingTradeSnapshot{}, err
 		}
@@ -2,8 +2,10 @@ package trades
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 )
 
 // Trade data structure for mapping trading pair currency raw
@@ -16,6 +18,11 @@ type Trade struct {
 	Price  float64
 }
 
+// Time returns the execution time of the trade
+func (t Trade) Time() time.Time {
+	return common.Mts(t.MTS).Time()
+}
+
 type TradeExecutionUpdate Trade
 type TradeExecuted Trade
 
@@ -24,14 +31,18 @@ type TradeSnapshot struct {
 }
 
 // TFromRaw maps raw data slice to instance of Trade
-func TFromRaw(pair string, raw []interface{}) (t Trade, err error) {
+func TFromRaw(pair string, raw []interface{}, opts ...convert.DecodeOptions) (t Trade, err error) {
+	f := convert.NewFields("trade", raw, opts...)
 	if len(raw) >= 4 {
 		t = Trade{
 			Pair:   pair,
-			ID:     convert.I64ValOrZero(raw[0]),
-			MTS:    convert.I64ValOrZero(raw[1]),
-			Amount: convert.F64ValOrZero(raw[2]),
-			Price:  convert.F64ValOrZero(raw[3]),
+			ID:     f.I64(0),
+			MTS:    f.I64(1),
+			Amount: f.F64(2),
+			Price:  f.F64(3),
+		}
+		if err = f.Err(); err != nil {
+			return Trade{}, err
 		}
 		return
 	}
@@ -41,8 +52,8 @@ func TFromRaw(pair string, raw []interface{}) (t Trade, err error) {
 }
 
 // TEUFromRaw maps raw data slice to instance of TradeExecutionUpdate
-func TEUFromRaw(pair string, raw []interface{}) (TradeExecutionUpdate, error) {
-	t, err := TFromRaw(pair, raw)
+func TEUFromRaw(pair string, raw []interface{}, opts ...convert.DecodeOptions) (TradeExecutionUpdate, error) {
+	t, err := TFromRaw(pair, raw, opts...)
 	if err != nil {
 		return TradeExecutionUpdate{}, err
 	}
@@ -51,8 +62,8 @@ func TEUFromRaw(pair string, raw []interface{}) (TradeExecutionUpdate, error) {
 }
 
 // TEFromRaw maps raw data slice to instance of TradeExecuted
-func TEFromRaw(pair string, raw []interface{}) (TradeExecuted, error) {
-	t, err := TFromRaw(pair, raw)
+func TEFromRaw(pair string, raw []interface{}, opts ...convert.DecodeOptions) (TradeExecuted, error) {
+	t, err := TFromRaw(pair, raw, opts...)
 	if err != nil {
 		return TradeExecuted{}, err
 	}
@@ -61,14 +72,14 @@ func TEFromRaw(pair string, raw []interface{}) (TradeExecuted, error) {
 }
 
 // TSnapshotFromRaw maps raw data slice to trading data structures
-func TSnapshotFromRaw(pair string, raw [][]interface{}) (TradeSnapshot, error) {
+func TSnapshotFromRaw(pair string, raw [][]interface{}, opts ...convert.DecodeOptions) (TradeSnapshot, error) {
 	if len(raw) == 0 {
 		return TradeSnapshot{}, fmt.Errorf("trade snapshot data slice too short:%#v", raw)
 	}
 
 	snapshot := make([]Trade, 0)
 	for _, v := range raw {
-		t, err := TFromRaw(pair, v)
+		t, err := TFromRaw(pair, v, opts...)
 		if err != nil {
 			return TradeSnapshot{}, err
 		}
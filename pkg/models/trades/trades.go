@@ -10,7 +10,7 @@ import (
 // FromWSRaw acts as a relay for public trades channel to abstract complexity from msg.
 // Data arrives under "trades" channel and then splits into sub types:
 // ["tu", "te", "ftu", "fte"] and can also be a snapshot.
-func FromWSRaw(pair string, raw, data []interface{}) (interface{}, error) {
+func FromWSRaw(pair string, raw, data []interface{}, opts ...convert.DecodeOptions) (interface{}, error) {
 	if len(data) == 0 {
 		return nil, errors.New("empty data slice for trade")
 	}
@@ -19,11 +19,11 @@ func FromWSRaw(pair string, raw, data []interface{}) (interface{}, error) {
 	hasType := len(raw) == 3
 
 	if isSnapshot && strings.HasPrefix(pair, "f") {
-		return FTSnapshotFromRaw(pair, convert.ToInterfaceArray(data))
+		return FTSnapshotFromRaw(pair, convert.ToInterfaceArray(data), opts...)
 	}
 
 	if isSnapshot && strings.HasPrefix(pair, "t") {
-		return TSnapshotFromRaw(pair, convert.ToInterfaceArray(data))
+		return TSnapshotFromRaw(pair, convert.ToInterfaceArray(data), opts...)
 	}
 
 	if hasType {
@@ -31,15 +31,15 @@ func FromWSRaw(pair string, raw, data []interface{}) (interface{}, error) {
 
 		switch opType {
 		case "tu":
-			return TEUFromRaw(pair, data)
+			return TEUFromRaw(pair, data, opts...)
 		case "te":
-			return TEFromRaw(pair, data)
+			return TEFromRaw(pair, data, opts...)
 		case "fte":
-			return FTEFromRaw(pair, data)
+			return FTEFromRaw(pair, data, opts...)
 		case "ftu":
-			return FTEUFromRaw(pair, data)
+			return FTEUFromRaw(pair, data, opts...)
 		}
 	}
 
-	return TFromRaw(pair, data)
+	return TFromRaw(pair, data, opts...)
 }
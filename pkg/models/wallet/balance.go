@@ -0,0 +1,42 @@
+package wallet
+
+import "strings"
+
+// Balance sums the wallets of one currency across the wallet types
+type Balance struct {
+	Currency         string
+	Balance          float64
+	BalanceAvailable float64
+	// HasBalanceAvailable is false if the available balance of a wallet is
+	// not calculated yet, BalanceAvailable then only sums the other wallets
+	HasBalanceAvailable bool
+	Wallets             []*Wallet // empty if the account holds no wallet of the currency
+}
+
+// Balances returns the balance of each of the currencies in the given order,
+// with zero balances for currencies without a wallet in the snapshot
+func (s *Snapshot) Balances(currencies ...string) []*Balance {
+	balances := make([]*Balance, 0, len(currencies))
+	byCurrency := make(map[string]*Balance, len(currencies))
+	for _, ccy := range currencies {
+		ccy = strings.ToUpper(ccy)
+		if _, ok := byCurrency[ccy]; ok {
+			continue
+		}
+		b := &Balance{Currency: ccy, HasBalanceAvailable: true, Wallets: []*Wallet{}}
+		byCurrency[ccy] = b
+		balances = append(balances, b)
+	}
+
+	for _, w := range s.Snapshot {
+		b, ok := byCurrency[strings.ToUpper(w.Currency)]
+		if !ok {
+			continue
+		}
+		b.Balance += w.Balance
+		b.BalanceAvailable += w.BalanceAvailable
+		b.HasBalanceAvailable = b.HasBalanceAvailable && w.HasBalanceAvailable
+		b.Wallets = append(b.Wallets, w)
+	}
+	return balances
+}
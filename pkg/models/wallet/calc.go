@@ -0,0 +1,33 @@
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CalcWallet names a wallet of a CalcRequest
+type CalcWallet struct {
+	Type     string
+	Currency string
+}
+
+// CalcRequest asks the websocket api to calculate the available balance of
+// the wallets, which wallet snapshots and updates of the websocket api carry
+// as null otherwise. The balances are sent as wallet updates.
+type CalcRequest struct {
+	Wallets []CalcWallet
+}
+
+// MarshalJSON converts the calc request into the format required by the
+// websocket api
+func (cr *CalcRequest) MarshalJSON() ([]byte, error) {
+	names := make([][]string, 0, len(cr.Wallets))
+	for _, w := range cr.Wallets {
+		names = append(names, []string{fmt.Sprintf("wallet_%s_%s", w.Type, w.Currency)})
+	}
+	b, err := json.Marshal(names)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("[0, \"calc\", null, %s]", string(b))), nil
+}
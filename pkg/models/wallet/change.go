@@ -0,0 +1,91 @@
+package wallet
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+)
+
+// Reasons of a wallet change
+const (
+	ReasonTrade    = "TRADE"
+	ReasonTransfer = "TRANSFER"
+)
+
+// Change is the cause of the last change of a wallet as sent along with the
+// balance, so that the change can be attributed without querying the
+// ledgers.
+type Change struct {
+	Reason   string          // e.g. ReasonTrade, as sent by the API
+	Trade    *TradeChange    // set for trades
+	Transfer *TransferChange // set for transfers between wallets
+}
+
+// TradeChange describes the trade which changed a wallet
+type TradeChange struct {
+	OrderID     int64
+	OrderIDOppo int64 // id of the matched order of the counterparty
+	OrderCID    int64
+	OrderGID    int64
+	Price       float64
+	Amount      float64 // negative for sells
+}
+
+// TransferChange describes the transfer between wallets which changed a
+// wallet
+type TransferChange struct {
+	Amount   float64
+	Currency string
+	From     string // wallet type, e.g. Exchange
+	To       string
+}
+
+// transferDescription matches e.g.
+// "Transfer of 100.0 USD from wallet Exchange to Margin on wallet margin"
+var transferDescription = regexp.MustCompile(`^Transfer of ([0-9.]+) (\S+) from wallet (\S+) to (\S+)`)
+
+// changeFromRaw returns the change of a wallet described by the description
+// and the meta data of a wallet update, nil if neither is known. The meta data
+// is a free-form object which is read leniently and not subject to strict
+// decoding.
+func changeFromRaw(description string, meta map[string]interface{}) *Change {
+	var c *Change
+	if reason, ok := meta["reason"].(string); ok {
+		c = &Change{Reason: reason}
+	}
+	if c != nil && c.Reason == ReasonTrade {
+		c.Trade = &TradeChange{
+			OrderID:     convert.I64ValOrZero(meta["order_id"]),
+			OrderIDOppo: convert.I64ValOrZero(meta["order_id_oppo"]),
+			OrderCID:    convert.I64ValOrZero(meta["order_cid"]),
+			OrderGID:    convert.I64ValOrZero(meta["order_gid"]),
+			Price:       number(meta["trade_price"]),
+			Amount:      number(meta["trade_amount"]),
+		}
+	}
+
+	if m := transferDescription.FindStringSubmatch(description); m != nil {
+		if c == nil {
+			c = &Change{Reason: ReasonTransfer}
+		}
+		amount, _ := strconv.ParseFloat(m[1], 64)
+		c.Transfer = &TransferChange{
+			Amount:   amount,
+			Currency: m[2],
+			From:     strings.ToLower(m[3]),
+			To:       strings.ToLower(m[4]),
+		}
+	}
+	return c
+}
+
+// number converts a number sent as string or number
+func number(v interface{}) float64 {
+	if s, ok := v.(string); ok {
+		v = json.Number(s)
+	}
+	return convert.F64ValOrZero(v)
+}
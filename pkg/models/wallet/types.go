@@ -0,0 +1,51 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// Wallet types
+const (
+	Exchange = "exchange"
+	Margin   = "margin"
+	Funding  = "funding"
+)
+
+// Types lists all wallet types accepted by the API
+var Types = []string{Exchange, Margin, Funding}
+
+// ValidateType returns an error wrapping common.ErrBadRequest if t is not a
+// known wallet type
+func ValidateType(t string) error {
+	for _, v := range Types {
+		if t == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: invalid wallet type %q, expected one of %v", common.ErrBadRequest, t, Types)
+}
+
+// ValidateTransfer checks the parameters of a transfer between wallets.
+// currencyTo may be empty or equal to currency for a plain transfer; a
+// different currencyTo converts the funds, which is also the only valid
+// reason to transfer into the same wallet.
+func ValidateTransfer(from, to, currency, currencyTo string, amount float64) error {
+	if err := ValidateType(from); err != nil {
+		return err
+	}
+	if err := ValidateType(to); err != nil {
+		return err
+	}
+	if currency == "" {
+		return fmt.Errorf("%w: missing transfer currency", common.ErrBadRequest)
+	}
+	if amount <= 0 {
+		return fmt.Errorf("%w: transfer amount must be positive, got %v", common.ErrBadRequest, amount)
+	}
+	if from == to && (currencyTo == "" || currencyTo == currency) {
+		return fmt.Errorf("%w: transfer of %s from %s wallet to itself", common.ErrBadRequest, currency, from)
+	}
+	return nil
+}
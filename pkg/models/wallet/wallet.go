@@ -12,8 +12,13 @@ type Wallet struct {
 	Balance           float64
 	UnsettledInterest float64
 	BalanceAvailable  float64
-	LastChange        string
-	TradeDetails      map[string]interface{}
+	// HasBalanceAvailable is false if the available balance was sent as
+	// null, which the websocket api does until it is calculated, see
+	// CalcRequest
+	HasBalanceAvailable bool
+	LastChange          string
+	TradeDetails        map[string]interface{}
+	Change              *Change // parsed from LastChange and TradeDetails, nil if unknown
 }
 
 type Update Wallet
@@ -22,32 +27,40 @@ type Snapshot struct {
 	Snapshot []*Wallet
 }
 
-func FromRaw(raw []interface{}) (w *Wallet, err error) {
+func FromRaw(raw []interface{}, opts ...convert.DecodeOptions) (w *Wallet, err error) {
 	if len(raw) < 7 {
 		err = fmt.Errorf("data slice too short for wallet: %#v", raw)
 		return
 	}
 
+	f := convert.NewFields("wallet", raw, opts...)
+
 	w = &Wallet{
-		Type:              convert.SValOrEmpty(raw[0]),
-		Currency:          convert.SValOrEmpty(raw[1]),
-		Balance:           convert.F64ValOrZero(raw[2]),
-		UnsettledInterest: convert.F64ValOrZero(raw[3]),
-		BalanceAvailable:  convert.F64ValOrZero(raw[4]),
-		LastChange:        convert.SValOrEmpty(raw[5]),
+		Type:              f.S(0),
+		Currency:          f.S(1),
+		Balance:           f.F64(2),
+		UnsettledInterest: f.F64(3),
+		BalanceAvailable:  f.F64(4),
+		LastChange:        f.S(5),
 	}
+	w.HasBalanceAvailable = raw[4] != nil
 
 	if meta, ok := raw[6].(map[string]interface{}); ok {
 		w.TradeDetails = meta
 	}
+	w.Change = changeFromRaw(w.LastChange, w.TradeDetails)
+
+	if err = f.Err(); err != nil {
+		return nil, err
+	}
 
 	return
 }
 
 // UpdateFromRaw reds "wu" type message from authenticated data
 // sream and maps it to wallet.Update data structure
-func UpdateFromRaw(raw []interface{}) (Update, error) {
-	w, err := FromRaw(raw)
+func UpdateFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (Update, error) {
+	w, err := FromRaw(raw, opts...)
 	if err != nil {
 		return Update{}, err
 	}
@@ -55,7 +68,7 @@ func UpdateFromRaw(raw []interface{}) (Update, error) {
 	return Update(*w), nil
 }
 
-func SnapshotFromRaw(raw []interface{}) (s *Snapshot, err error) {
+func SnapshotFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (s *Snapshot, err error) {
 	if len(raw) == 0 {
 		return s, fmt.Errorf("data slice too short for wallet: %#v", raw)
 	}
@@ -65,7 +78,7 @@ func SnapshotFromRaw(raw []interface{}) (s *Snapshot, err error) {
 	case []interface{}:
 		for _, v := range raw {
 			if l, ok := v.([]interface{}); ok {
-				w, err := FromRaw(l)
+				w, err := FromRaw(l, opts...)
 				if err != nil {
 					return s, err
 				}
@@ -1,8 +1,10 @@
 package wallet_test
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/wallet"
 	"github.com/stretchr/testify/assert"
 )
@@ -35,12 +37,13 @@ func TestUpdateFromRaw(t *testing.T) {
 				},
 			},
 			expected: &wallet.Wallet{
-				Type:              "exchange",
-				Currency:          "UST",
-				Balance:           19788.6529257,
-				UnsettledInterest: 0,
-				BalanceAvailable:  19788.6529257,
-				LastChange:        "Exchange 2.0 UST for USD @ 11.696",
+				Type:                "exchange",
+				Currency:            "UST",
+				Balance:             19788.6529257,
+				UnsettledInterest:   0,
+				BalanceAvailable:    19788.6529257,
+				HasBalanceAvailable: true,
+				LastChange:          "Exchange 2.0 UST for USD @ 11.696",
 				TradeDetails: map[string]interface{}{
 					"order_cid":     1598516362757,
 					"order_gid":     1598516362629,
@@ -50,6 +53,17 @@ func TestUpdateFromRaw(t *testing.T) {
 					"trade_amount":  "-2.0",
 					"trade_price":   "11.696",
 				},
+				Change: &wallet.Change{
+					Reason: wallet.ReasonTrade,
+					Trade: &wallet.TradeChange{
+						OrderID:     1189740779,
+						OrderIDOppo: 1189785673,
+						OrderCID:    1598516362757,
+						OrderGID:    1598516362629,
+						Price:       11.696,
+						Amount:      -2,
+					},
+				},
 			},
 			err: func(t *testing.T, err error) {
 				assert.Nil(t, err)
@@ -61,12 +75,13 @@ func TestUpdateFromRaw(t *testing.T) {
 				"Exchange 2.0 UST for USD @ 11.696", nil,
 			},
 			expected: &wallet.Wallet{
-				Type:              "exchange",
-				Currency:          "UST",
-				Balance:           19788.6529257,
-				UnsettledInterest: 0,
-				BalanceAvailable:  19788.6529257,
-				LastChange:        "Exchange 2.0 UST for USD @ 11.696",
+				Type:                "exchange",
+				Currency:            "UST",
+				Balance:             19788.6529257,
+				UnsettledInterest:   0,
+				BalanceAvailable:    19788.6529257,
+				HasBalanceAvailable: true,
+				LastChange:          "Exchange 2.0 UST for USD @ 11.696",
 			},
 			err: func(t *testing.T, err error) {
 				assert.Nil(t, err)
@@ -98,6 +113,39 @@ func TestUpdateFromRaw(t *testing.T) {
 					"trade_amount":  "0.01",
 					"trade_price":   "7804.6",
 				},
+				Change: &wallet.Change{
+					Reason: wallet.ReasonTrade,
+					Trade: &wallet.TradeChange{
+						OrderID:     34988418651,
+						OrderIDOppo: 34990541044,
+						Price:       7804.6,
+						Amount:      0.01,
+					},
+				},
+			},
+			err: func(t *testing.T, err error) {
+				assert.Nil(t, err)
+			},
+		},
+		"valid ws wallet transfer": {
+			pld: []interface{}{
+				"margin", "USD", 150, 0, nil,
+				"Transfer of 100.0 USD from wallet Exchange to Margin on wallet margin", nil,
+			},
+			expected: &wallet.Wallet{
+				Type:       "margin",
+				Currency:   "USD",
+				Balance:    150,
+				LastChange: "Transfer of 100.0 USD from wallet Exchange to Margin on wallet margin",
+				Change: &wallet.Change{
+					Reason: wallet.ReasonTransfer,
+					Transfer: &wallet.TransferChange{
+						Amount:   100,
+						Currency: "USD",
+						From:     wallet.Exchange,
+						To:       wallet.Margin,
+					},
+				},
 			},
 			err: func(t *testing.T, err error) {
 				assert.Nil(t, err)
@@ -167,12 +215,13 @@ func TestSnapshotFromRaw(t *testing.T) {
 			expected: &wallet.Snapshot{
 				Snapshot: []*wallet.Wallet{
 					{
-						Type:              "exchange",
-						Currency:          "UST",
-						Balance:           19788.6529257,
-						UnsettledInterest: 0,
-						BalanceAvailable:  19788.6529257,
-						LastChange:        "Exchange 2.0 UST for USD @ 11.696",
+						Type:                "exchange",
+						Currency:            "UST",
+						Balance:             19788.6529257,
+						UnsettledInterest:   0,
+						BalanceAvailable:    19788.6529257,
+						HasBalanceAvailable: true,
+						LastChange:          "Exchange 2.0 UST for USD @ 11.696",
 						TradeDetails: map[string]interface{}{
 							"order_cid":     1598516362757,
 							"order_gid":     1598516362629,
@@ -182,14 +231,26 @@ func TestSnapshotFromRaw(t *testing.T) {
 							"trade_amount":  "-2.0",
 							"trade_price":   "11.696",
 						},
+						Change: &wallet.Change{
+							Reason: wallet.ReasonTrade,
+							Trade: &wallet.TradeChange{
+								OrderID:     1189740779,
+								OrderIDOppo: 1189785673,
+								OrderCID:    1598516362757,
+								OrderGID:    1598516362629,
+								Price:       11.696,
+								Amount:      -2,
+							},
+						},
 					},
 					{
-						Type:              "exchange",
-						Currency:          "UST",
-						Balance:           19788.6529257,
-						UnsettledInterest: 0,
-						BalanceAvailable:  19788.6529257,
-						LastChange:        "Exchange 2.0 UST for USD @ 11.696",
+						Type:                "exchange",
+						Currency:            "UST",
+						Balance:             19788.6529257,
+						UnsettledInterest:   0,
+						BalanceAvailable:    19788.6529257,
+						HasBalanceAvailable: true,
+						LastChange:          "Exchange 2.0 UST for USD @ 11.696",
 					},
 				},
 			},
@@ -226,3 +287,72 @@ func TestSnapshotFromRaw(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateType(t *testing.T) {
+	for _, typ := range wallet.Types {
+		assert.Nil(t, wallet.ValidateType(typ))
+	}
+	err := wallet.ValidateType("Exchange")
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
+
+func TestValidateTransfer(t *testing.T) {
+	cases := map[string]struct {
+		from, to, currency, currencyTo string
+		amount                         float64
+		valid                          bool
+	}{
+		"plain":           {wallet.Exchange, wallet.Margin, "BTC", "", 1, true},
+		"same currency":   {wallet.Funding, wallet.Exchange, "BTC", "BTC", 1, true},
+		"conversion":      {wallet.Exchange, wallet.Exchange, "UST", "USD", 1, true},
+		"unknown wallet":  {"trading", wallet.Exchange, "BTC", "", 1, false},
+		"missing to":      {wallet.Exchange, "", "BTC", "", 1, false},
+		"no currency":     {wallet.Exchange, wallet.Margin, "", "", 1, false},
+		"zero amount":     {wallet.Exchange, wallet.Margin, "BTC", "", 0, false},
+		"same wallet":     {wallet.Margin, wallet.Margin, "BTC", "", 1, false},
+		"same wallet ccy": {wallet.Margin, wallet.Margin, "BTC", "BTC", 1, false},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			err := wallet.ValidateTransfer(v.from, v.to, v.currency, v.currencyTo, v.amount)
+			if v.valid {
+				assert.Nil(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, common.ErrBadRequest))
+		})
+	}
+}
+
+func TestCalcRequestMarshalJSON(t *testing.T) {
+	cr := &wallet.CalcRequest{Wallets: []wallet.CalcWallet{
+		{Type: wallet.Exchange, Currency: "USD"},
+		{Type: wallet.Margin, Currency: "BTC"},
+	}}
+	b, err := cr.MarshalJSON()
+	assert.Nil(t, err)
+	assert.Equal(t, `[0, "calc", null, [["wallet_exchange_USD"],["wallet_margin_BTC"]]]`, string(b))
+}
+
+func TestSnapshotBalances(t *testing.T) {
+	snap := &wallet.Snapshot{Snapshot: []*wallet.Wallet{
+		{Type: "exchange", Currency: "BTC", Balance: 1.5, BalanceAvailable: 1.25, HasBalanceAvailable: true},
+		{Type: "margin", Currency: "BTC", Balance: 0.5},
+		{Type: "exchange", Currency: "USD", Balance: 100, BalanceAvailable: 100, HasBalanceAvailable: true},
+	}}
+
+	balances := snap.Balances("BTC", "usd", "ETH")
+	assert.Len(t, balances, 3)
+
+	// the available balance of the margin wallet is not calculated yet
+	assert.Equal(t, 2.0, balances[0].Balance)
+	assert.Equal(t, 1.25, balances[0].BalanceAvailable)
+	assert.False(t, balances[0].HasBalanceAvailable)
+
+	assert.Equal(t, 100.0, balances[1].BalanceAvailable)
+	assert.True(t, balances[1].HasBalanceAvailable)
+
+	assert.Empty(t, balances[2].Wallets)
+	assert.True(t, balances[2].HasBalanceAvailable)
+}
@@ -163,7 +163,7 @@ func (m Msg) ProcessPrivate(raw []interface{}, pld interface{}, chID int64, op s
 
 func (m Msg) ProcessEvent() (i event.Info, err error) {
 	if err = json.Unmarshal(m.Data, &i); err != nil {
-		return i, fmt.Errorf("parsing msg: %s, err: %s", m.Data, err)
+		return i, fmt.Errorf("parsing msg: %s, err: %w", m.Data, err)
 	}
 	return
 }
@@ -1003,6 +1003,15 @@ func TestProcessPrivate(t *testing.T) {
 					"trade_amount":  "0.01",
 					"trade_price":   "7804.6",
 				},
+				Change: &wallet.Change{
+					Reason: wallet.ReasonTrade,
+					Trade: &wallet.TradeChange{
+						OrderID:     34988418651,
+						OrderIDOppo: 34990541044,
+						Price:       7804.6,
+						Amount:      0.01,
+					},
+				},
 			},
 		},
 		"order snapshot": {
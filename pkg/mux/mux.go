@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/event"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/mux/client"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/mux/msg"
@@ -163,7 +164,7 @@ func (m *Mux) Listen(cb func(interface{}, error)) error {
 				return errors.New("public channel has closed unexpectedly")
 			}
 			if ms.Err != nil {
-				cb(nil, fmt.Errorf("conn:%d has failed | err:%s | reconnecting", ms.CID, ms.Err))
+				cb(nil, fmt.Errorf("conn:%d has failed | err:%w | reconnecting", ms.CID, ms.Err))
 				m.resetPublicClient(ms.CID)
 				continue
 			}
@@ -199,7 +200,7 @@ func (m *Mux) Listen(cb func(interface{}, error)) error {
 				return errors.New("private channel has closed unexpectedly")
 			}
 			if ms.Err != nil {
-				cb(nil, fmt.Errorf("err: %s | reconnecting", ms.Err))
+				cb(nil, fmt.Errorf("err: %w | reconnecting", ms.Err))
 				m.resetPrivateClient()
 				continue
 			}
@@ -253,7 +254,7 @@ func (m *Mux) Listen(cb func(interface{}, error)) error {
 // and calls client with it
 func (m *Mux) Send(pld interface{}) error {
 	if !m.authenticated || m.privateClient == nil {
-		return errors.New("not authorized")
+		return fmt.Errorf("not authorized: %w", common.ErrUnauthorized)
 	}
 	return m.privateClient.Send(pld)
 }
@@ -0,0 +1,149 @@
+package portfolio
+
+import (
+	"fmt"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// Threshold is the balance range of a currency, e.g. to replenish a wallet
+// once it runs low or to sweep it once it holds too much.
+type Threshold struct {
+	Currency string
+	Wallet   string  // wallet type, empty for the sum over all wallets
+	Min      float64 // alert below Min, zero disables the lower bound
+	Max      float64 // alert above Max, zero disables the upper bound
+}
+
+// AlertKind is the range a balance entered
+type AlertKind string
+
+const (
+	AlertBelowMin AlertKind = "BELOW_MIN"
+	AlertAboveMax AlertKind = "ABOVE_MAX"
+	AlertInRange  AlertKind = "IN_RANGE" // back within the thresholds
+)
+
+// Alert reports that a balance crossed a threshold.
+type Alert struct {
+	Kind      AlertKind
+	Threshold Threshold
+	Balance   float64
+	Previous  float64 // balance before the crossing, zero if unknown
+}
+
+type watch struct {
+	threshold Threshold
+	fn        func(Alert)
+	kind      AlertKind // empty until the balance is known
+	balance   float64
+}
+
+func (th Threshold) kind(balance float64) AlertKind {
+	switch {
+	case th.Min != 0 && balance < th.Min:
+		return AlertBelowMin
+	case th.Max != 0 && balance > th.Max:
+		return AlertAboveMax
+	}
+	return AlertInRange
+}
+
+// OnThreshold registers fn to be called whenever the balance crosses one of
+// the thresholds, and once it is back within them. A balance which is out of
+// range when it is first known is reported as well, right away if the
+// tracker already holds it. fn is called without the lock of the tracker
+// held. The returned func removes the threshold.
+func (t *Tracker) OnThreshold(th Threshold, fn func(Alert)) (func(), error) {
+	if th.Currency == "" {
+		return nil, fmt.Errorf("%w: currency is required", common.ErrBadRequest)
+	}
+	if th.Min != 0 && th.Max != 0 && th.Min > th.Max {
+		return nil, fmt.Errorf("%w: min %v is above max %v", common.ErrBadRequest, th.Min, th.Max)
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("%w: callback is required", common.ErrBadRequest)
+	}
+
+	w := &watch{threshold: th, fn: fn}
+	t.mu.Lock()
+	t.watches = append(t.watches, w)
+	alerts := t.checkWatches([]*watch{w})
+	t.mu.Unlock()
+	deliver(alerts)
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		for i, x := range t.watches {
+			if x == w {
+				t.watches = append(t.watches[:i], t.watches[i+1:]...)
+				return
+			}
+		}
+	}, nil
+}
+
+// Balance returns the balance of the currency in the wallet type, or summed
+// over all wallets if walletType is empty. It returns false if no such
+// wallet was received.
+func (t *Tracker) Balance(currency, walletType string) (float64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.balance(currency, walletType)
+}
+
+func (t *Tracker) balance(currency, walletType string) (float64, bool) {
+	var sum float64
+	var found bool
+	for _, w := range t.wallets {
+		if w.Currency == currency && (walletType == "" || w.Type == walletType) {
+			sum += w.Balance
+			found = true
+		}
+	}
+	return sum, found
+}
+
+type pendingAlert struct {
+	fn    func(Alert)
+	alert Alert
+}
+
+// checkWatches updates the state of the watches and returns the alerts of the
+// crossed thresholds. It is called with the lock held.
+func (t *Tracker) checkWatches(watches []*watch) []pendingAlert {
+	var alerts []pendingAlert
+	for _, w := range watches {
+		balance, ok := t.balance(w.threshold.Currency, w.threshold.Wallet)
+		if !ok {
+			// a wallet missing from a snapshot is empty once it was known
+			if w.kind == "" {
+				continue
+			}
+			balance = 0
+		}
+
+		kind := w.threshold.kind(balance)
+		prev := w.kind
+		w.kind = kind
+		if kind == prev || (prev == "" && kind == AlertInRange) {
+			w.balance = balance
+			continue
+		}
+		alerts = append(alerts, pendingAlert{fn: w.fn, alert: Alert{
+			Kind:      kind,
+			Threshold: w.threshold,
+			Balance:   balance,
+			Previous:  w.balance,
+		}})
+		w.balance = balance
+	}
+	return alerts
+}
+
+func deliver(alerts []pendingAlert) {
+	for _, a := range alerts {
+		a.fn(a.alert)
+	}
+}
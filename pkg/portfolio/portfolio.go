@@ -0,0 +1,227 @@
+// Package portfolio values wallet balances in a common quote currency and
+// alerts when balances cross thresholds.
+package portfolio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/ticker"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/wallet"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/symbol"
+)
+
+// bridge is the currency used to price assets without a direct pair
+const bridge = "USD"
+
+// Asset is the valuation of a single currency summed over all wallets.
+type Asset struct {
+	Currency   string
+	Balance    float64
+	Price      float64 // price in the quote currency
+	Value      float64 // value in the quote currency
+	Allocation float64 // share of the total value, between 0 and 1
+	Change24h  float64 // value change over the last 24h in the quote currency
+}
+
+// Valuation is the value of all wallets in a quote currency.
+type Valuation struct {
+	Quote         string
+	Total         float64
+	Change24h     float64
+	Change24hPerc float64
+	Assets        []Asset  // sorted by value, largest first
+	Unpriced      []string // currencies without a known price
+}
+
+// Value returns the valuation of the wallets in the quote currency using the
+// last prices of the given trading pair tickers, e.g. as returned by the
+// rest client:
+//
+//	ws, _ := c.Wallet.Wallet()
+//	ts, _ := c.Tickers.All()
+//	v, _ := portfolio.Value(ws.Snapshot, ts, "USD")
+func Value(wallets []*wallet.Wallet, tickers []*ticker.Ticker, quote string) (*Valuation, error) {
+	if quote == "" {
+		return nil, fmt.Errorf("%w: quote currency is required", common.ErrBadRequest)
+	}
+
+	prices := make(map[string]*ticker.Ticker, len(tickers))
+	for _, t := range tickers {
+		if strings.HasPrefix(t.Symbol, common.TradingPrefix) {
+			prices[t.Symbol] = t
+		}
+	}
+
+	balances := map[string]float64{}
+	for _, w := range wallets {
+		balances[w.Currency] += w.Balance
+	}
+
+	v := &Valuation{Quote: quote}
+	var prev float64
+	for cur, balance := range balances {
+		if balance == 0 {
+			continue
+		}
+
+		now, before, ok := price(prices, cur, quote)
+		if !ok {
+			v.Unpriced = append(v.Unpriced, cur)
+			continue
+		}
+
+		a := Asset{
+			Currency:  cur,
+			Balance:   balance,
+			Price:     now,
+			Value:     balance * now,
+			Change24h: balance * (now - before),
+		}
+		v.Assets = append(v.Assets, a)
+		v.Total += a.Value
+		v.Change24h += a.Change24h
+		prev += balance * before
+	}
+
+	for i := range v.Assets {
+		if v.Total != 0 {
+			v.Assets[i].Allocation = v.Assets[i].Value / v.Total
+		}
+	}
+	if prev != 0 {
+		v.Change24hPerc = v.Change24h / prev
+	}
+
+	sort.Slice(v.Assets, func(i, j int) bool { return v.Assets[i].Value > v.Assets[j].Value })
+	sort.Strings(v.Unpriced)
+	return v, nil
+}
+
+// price returns the current and 24h old price of base in quote, using the
+// direct pair, the inverse pair or a conversion through the bridge currency
+func price(prices map[string]*ticker.Ticker, base, quote string) (now, before float64, ok bool) {
+	if base == quote {
+		return 1, 1, true
+	}
+
+	if t, ok := lookup(prices, base, quote); ok {
+		return t.LastPrice, t.LastPrice - t.DailyChange, true
+	}
+
+	if t, ok := lookup(prices, quote, base); ok && t.LastPrice != 0 && t.LastPrice != t.DailyChange {
+		return 1 / t.LastPrice, 1 / (t.LastPrice - t.DailyChange), true
+	}
+
+	if base == bridge || quote == bridge {
+		return 0, 0, false
+	}
+
+	baseNow, baseBefore, ok := price(prices, base, bridge)
+	if !ok {
+		return 0, 0, false
+	}
+	quoteNow, quoteBefore, ok := price(prices, quote, bridge)
+	if !ok || quoteNow == 0 || quoteBefore == 0 {
+		return 0, 0, false
+	}
+	return baseNow / quoteNow, baseBefore / quoteBefore, true
+}
+
+func lookup(prices map[string]*ticker.Ticker, base, quote string) (*ticker.Ticker, bool) {
+	t, ok := prices[symbol.NewTrading(base, quote).String()]
+	return t, ok
+}
+
+// Tracker keeps the latest wallets and tickers received from the websocket
+// client in order to value the account at any time. Events have to be passed
+// to Handle, e.g.:
+//
+//	for ev := range client.Listen() {
+//		tracker.Handle(ev)
+//	}
+type Tracker struct {
+	mu      sync.Mutex
+	wallets map[string]*wallet.Wallet
+	tickers map[string]*ticker.Ticker
+	watches []*watch
+	// wallet updates are discarded until the next snapshot, see Resync
+	stale bool
+}
+
+// NewTracker returns an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{
+		wallets: make(map[string]*wallet.Wallet),
+		tickers: make(map[string]*ticker.Ticker),
+	}
+}
+
+// Handle updates the tracker with wallet and ticker events, other events are
+// ignored. Balances crossing a registered threshold are reported once the
+// event is applied.
+func (t *Tracker) Handle(ev interface{}) {
+	t.mu.Lock()
+	var alerts []pendingAlert
+	switch e := ev.(type) {
+	case *wallet.Snapshot:
+		t.stale = false
+		t.wallets = make(map[string]*wallet.Wallet, len(e.Snapshot))
+		for _, w := range e.Snapshot {
+			t.wallets[w.Type+":"+w.Currency] = w
+		}
+		alerts = t.checkWatches(t.watches)
+	case *wallet.Update:
+		if t.stale {
+			break
+		}
+		w := wallet.Wallet(*e)
+		t.wallets[w.Type+":"+w.Currency] = &w
+		alerts = t.checkWatches(t.watches)
+	case *ticker.Snapshot:
+		for _, tk := range e.Snapshot {
+			t.tickers[tk.Symbol] = tk
+		}
+	case *ticker.Ticker:
+		t.tickers[e.Symbol] = e
+	}
+	t.mu.Unlock()
+
+	deliver(alerts)
+}
+
+// Resync discards wallet updates until the next wallet snapshot arrives. Call
+// it on every AuthSucceededEvent with a Generation above 1, the snapshot sent
+// after such a re-authentication supersedes the updates received before it.
+// The balances known so far stay available in the meantime.
+func (t *Tracker) Resync() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stale = true
+}
+
+// Stale reports whether the tracker awaits a wallet snapshot after Resync
+func (t *Tracker) Stale() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stale
+}
+
+// Valuation returns the current valuation of the tracked wallets.
+func (t *Tracker) Valuation(quote string) (*Valuation, error) {
+	t.mu.Lock()
+	wallets := make([]*wallet.Wallet, 0, len(t.wallets))
+	for _, w := range t.wallets {
+		wallets = append(wallets, w)
+	}
+	tickers := make([]*ticker.Ticker, 0, len(t.tickers))
+	for _, tk := range t.tickers {
+		tickers = append(tickers, tk)
+	}
+	t.mu.Unlock()
+
+	return Value(wallets, tickers, quote)
+}
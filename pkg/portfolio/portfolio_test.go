@@ -0,0 +1,157 @@
+package portfolio_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/ticker"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/wallet"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/portfolio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	wallets = []*wallet.Wallet{
+		{Type: "exchange", Currency: "BTC", Balance: 1},
+		{Type: "margin", Currency: "BTC", Balance: 1},
+		{Type: "exchange", Currency: "USD", Balance: 1000},
+		{Type: "exchange", Currency: "EUR", Balance: 500},
+		{Type: "exchange", Currency: "TESTX", Balance: 10},
+		{Type: "funding", Currency: "ETH", Balance: 0},
+	}
+	tickers = []*ticker.Ticker{
+		{Symbol: "tBTCUSD", LastPrice: 10000, DailyChange: 500},
+		{Symbol: "tEURUSD", LastPrice: 1.25, DailyChange: 0},
+		{Symbol: "tTESTX:USD", LastPrice: 5, DailyChange: -5},
+		{Symbol: "fUSD", LastPrice: 0.0002},
+	}
+)
+
+func TestValue(t *testing.T) {
+	t.Run("in USD", func(t *testing.T) {
+		v, err := portfolio.Value(wallets, tickers, "USD")
+		require.Nil(t, err)
+
+		assert.InDelta(t, 20000+1000+625+50, v.Total, 1e-9)
+		assert.InDelta(t, 1000-50, v.Change24h, 1e-9)
+		assert.InDelta(t, 950.0/(21675-950), v.Change24hPerc, 1e-9)
+		assert.Empty(t, v.Unpriced)
+
+		require.Len(t, v.Assets, 4)
+		assert.Equal(t, "BTC", v.Assets[0].Currency)
+		assert.Equal(t, 2.0, v.Assets[0].Balance)
+		assert.InDelta(t, 20000/21675.0, v.Assets[0].Allocation, 1e-9)
+	})
+
+	t.Run("in EUR through inverse and bridge pairs", func(t *testing.T) {
+		v, err := portfolio.Value(wallets, tickers, "EUR")
+		require.Nil(t, err)
+
+		assets := map[string]portfolio.Asset{}
+		for _, a := range v.Assets {
+			assets[a.Currency] = a
+		}
+		assert.InDelta(t, 0.8, assets["USD"].Price, 1e-9)
+		assert.InDelta(t, 8000, assets["BTC"].Price, 1e-9)
+		assert.Equal(t, 1.0, assets["EUR"].Price)
+	})
+
+	t.Run("unpriced assets", func(t *testing.T) {
+		v, err := portfolio.Value(wallets, tickers[:1], "USD")
+		require.Nil(t, err)
+		assert.Equal(t, []string{"EUR", "TESTX"}, v.Unpriced)
+	})
+
+	t.Run("requires quote", func(t *testing.T) {
+		_, err := portfolio.Value(wallets, tickers, "")
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+	})
+}
+
+func TestTracker(t *testing.T) {
+	tr := portfolio.NewTracker()
+	tr.Handle(&wallet.Snapshot{Snapshot: wallets[:1]})
+	tr.Handle(&ticker.Snapshot{Snapshot: tickers[:1]})
+
+	v, err := tr.Valuation("USD")
+	require.Nil(t, err)
+	assert.Equal(t, 10000.0, v.Total)
+
+	tr.Handle(&wallet.Update{Type: "exchange", Currency: "BTC", Balance: 3})
+	tr.Handle(&ticker.Ticker{Symbol: "tBTCUSD", LastPrice: 11000})
+	tr.Handle("ignored")
+
+	v, err = tr.Valuation("USD")
+	require.Nil(t, err)
+	assert.Equal(t, 33000.0, v.Total)
+}
+
+func TestTrackerResync(t *testing.T) {
+	tr := portfolio.NewTracker()
+	tr.Handle(&wallet.Snapshot{Snapshot: wallets[:1]})
+
+	tr.Resync()
+	assert.True(t, tr.Stale())
+	tr.Handle(&wallet.Update{Type: "exchange", Currency: "BTC", Balance: 3})
+	b, _ := tr.Balance("BTC", "exchange")
+	assert.Equal(t, 1.0, b)
+
+	tr.Handle(&wallet.Snapshot{Snapshot: []*wallet.Wallet{{Type: "exchange", Currency: "BTC", Balance: 2}}})
+	assert.False(t, tr.Stale())
+	tr.Handle(&wallet.Update{Type: "exchange", Currency: "BTC", Balance: 3})
+	b, _ = tr.Balance("BTC", "exchange")
+	assert.Equal(t, 3.0, b)
+}
+
+func TestThresholds(t *testing.T) {
+	tr := portfolio.NewTracker()
+	_, err := tr.OnThreshold(portfolio.Threshold{Currency: "USD", Min: 10, Max: 5}, func(portfolio.Alert) {})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	var alerts []portfolio.Alert
+	collect := func(a portfolio.Alert) { alerts = append(alerts, a) }
+	_, err = tr.OnThreshold(portfolio.Threshold{Currency: "USD", Wallet: "exchange", Min: 100, Max: 1000}, collect)
+	require.Nil(t, err)
+	_, err = tr.OnThreshold(portfolio.Threshold{Currency: "BTC", Max: 1.5}, collect)
+	require.Nil(t, err)
+
+	// balances within range when first known are not reported
+	tr.Handle(&wallet.Snapshot{Snapshot: []*wallet.Wallet{
+		{Type: "exchange", Currency: "USD", Balance: 500},
+		{Type: "exchange", Currency: "BTC", Balance: 1},
+		{Type: "margin", Currency: "BTC", Balance: 0.4},
+	}})
+	assert.Empty(t, alerts)
+
+	tr.Handle(&wallet.Update{Type: "exchange", Currency: "USD", Balance: 50})
+	tr.Handle(&wallet.Update{Type: "exchange", Currency: "USD", Balance: 40})
+	tr.Handle(&wallet.Update{Type: "margin", Currency: "BTC", Balance: 0.6})
+	tr.Handle(&wallet.Update{Type: "margin", Currency: "USD", Balance: 5000})
+	require.Len(t, alerts, 2)
+	assert.Equal(t, portfolio.AlertBelowMin, alerts[0].Kind)
+	assert.Equal(t, 50.0, alerts[0].Balance)
+	assert.Equal(t, 500.0, alerts[0].Previous)
+	assert.Equal(t, portfolio.AlertAboveMax, alerts[1].Kind)
+	assert.Equal(t, 1.6, alerts[1].Balance)
+
+	tr.Handle(&wallet.Update{Type: "exchange", Currency: "USD", Balance: 200})
+	require.Len(t, alerts, 3)
+	assert.Equal(t, portfolio.AlertInRange, alerts[2].Kind)
+	assert.Equal(t, 40.0, alerts[2].Previous)
+
+	// out of range balances are reported on registration and removed
+	// thresholds are not checked anymore
+	remove, err := tr.OnThreshold(portfolio.Threshold{Currency: "USD", Max: 1000}, collect)
+	require.Nil(t, err)
+	require.Len(t, alerts, 4)
+	assert.Equal(t, 5200.0, alerts[3].Balance)
+	remove()
+	tr.Handle(&wallet.Update{Type: "margin", Currency: "USD", Balance: 0})
+	assert.Len(t, alerts, 4)
+
+	balance, ok := tr.Balance("BTC", "")
+	assert.True(t, ok)
+	assert.Equal(t, 1.6, balance)
+}
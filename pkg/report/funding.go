@@ -0,0 +1,130 @@
+// Package report aggregates account history into reports.
+package report
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/ledger"
+)
+
+const dayLayout = "2006-01-02"
+
+// LedgerKind classifies ledger entries for the funding report.
+type LedgerKind int
+
+const (
+	// LedgerOther is not part of the funding report.
+	LedgerOther LedgerKind = iota
+	// LedgerFundingPayment is interest received for provided funding.
+	LedgerFundingPayment
+	// LedgerFundingFee is a fee charged on funding.
+	LedgerFundingFee
+)
+
+// ClassifyFunding classifies ledger entries by their description.
+func ClassifyFunding(l *ledger.Ledger) LedgerKind {
+	desc := strings.ToLower(l.Description)
+	switch {
+	case strings.Contains(desc, "funding payment"):
+		return LedgerFundingPayment
+	case strings.Contains(desc, "funding") && (strings.Contains(desc, "fee") || strings.Contains(desc, "charge")):
+		return LedgerFundingFee
+	}
+	return LedgerOther
+}
+
+// FundingDay is the funding income of a currency on a single day.
+type FundingDay struct {
+	Currency string
+	Day      time.Time // midnight UTC
+	Payments float64
+	Fees     float64 // negative amount
+	Net      float64
+	Balance  float64 // funding wallet balance before the first payment of the day
+	Rate     float64 // daily return on Balance
+}
+
+// FundingSummary is the funding income of a currency over the whole period.
+type FundingSummary struct {
+	Currency string
+	Payments float64
+	Fees     float64
+	Net      float64
+	Days     []FundingDay // ordered by day, oldest first
+	APR      float64      // annualized average daily rate
+}
+
+// FundingEarnings aggregates funding payments and fees of the ledger entries
+// per currency and day. Entries are classified by classify, which defaults to
+// ClassifyFunding. The entries may be fetched with LedgersAll of the rest
+// client, e.g.:
+//
+//	ls, _ := c.Ledgers.LedgersAll("USD", rest.NewQuery().From(start))
+//	summaries := report.FundingEarnings(ls, nil)
+func FundingEarnings(entries []*ledger.Ledger, classify func(*ledger.Ledger) LedgerKind) []FundingSummary {
+	if classify == nil {
+		classify = ClassifyFunding
+	}
+
+	sorted := make([]*ledger.Ledger, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].MTS < sorted[j].MTS })
+
+	days := map[string]*FundingDay{}
+	for _, l := range sorted {
+		kind := classify(l)
+		if kind == LedgerOther {
+			continue
+		}
+
+		day := l.Time().UTC().Truncate(24 * time.Hour)
+		key := l.Currency + ":" + day.Format(dayLayout)
+		d, ok := days[key]
+		if !ok {
+			d = &FundingDay{Currency: l.Currency, Day: day, Balance: l.Balance - l.Amount}
+			days[key] = d
+		}
+
+		switch kind {
+		case LedgerFundingPayment:
+			d.Payments += l.Amount
+		case LedgerFundingFee:
+			d.Fees += l.Amount
+		}
+		d.Net = d.Payments + d.Fees
+	}
+
+	summaries := map[string]*FundingSummary{}
+	for _, d := range days {
+		if d.Balance > 0 {
+			d.Rate = d.Net / d.Balance
+		}
+
+		s, ok := summaries[d.Currency]
+		if !ok {
+			s = &FundingSummary{Currency: d.Currency}
+			summaries[d.Currency] = s
+		}
+		s.Payments += d.Payments
+		s.Fees += d.Fees
+		s.Net += d.Net
+		s.Days = append(s.Days, *d)
+	}
+
+	out := make([]FundingSummary, 0, len(summaries))
+	for _, s := range summaries {
+		sort.Slice(s.Days, func(i, j int) bool { return s.Days[i].Day.Before(s.Days[j].Day) })
+
+		var rates float64
+		for _, d := range s.Days {
+			rates += d.Rate
+		}
+		s.APR = rates / float64(len(s.Days)) * 365
+		out = append(out, *s)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Currency < out[j].Currency })
+	return out
+}
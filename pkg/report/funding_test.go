@@ -0,0 +1,55 @@
+package report_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/ledger"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFundingEarnings(t *testing.T) {
+	day := int64(24 * time.Hour / time.Millisecond)
+	start := int64(1568073600000) // 2019-09-10 00:00 UTC
+
+	entries := []*ledger.Ledger{
+		{ID: 4, Currency: "USD", MTS: start + day + 1000, Amount: 2, Balance: 1002, Description: "Margin Funding Payment on wallet funding"},
+		{ID: 1, Currency: "USD", MTS: start + 1000, Amount: 1.2, Balance: 1001.2, Description: "Margin Funding Payment on wallet funding"},
+		{ID: 2, Currency: "USD", MTS: start + 2000, Amount: -0.2, Balance: 1001, Description: "Funding fee on wallet funding"},
+		{ID: 3, Currency: "USD", MTS: start + 3000, Amount: -500, Balance: 501, Description: "Transfer of 500 USD from wallet funding to exchange"},
+		{ID: 5, Currency: "BTC", MTS: start, Amount: 0.001, Balance: 1.001, Description: "Margin Funding Payment on wallet funding"},
+	}
+
+	got := report.FundingEarnings(entries, nil)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "BTC", got[0].Currency)
+	assert.InDelta(t, 0.001*365, got[0].APR, 1e-9)
+
+	usd := got[1]
+	assert.Equal(t, "USD", usd.Currency)
+	assert.InDelta(t, 3.2, usd.Payments, 1e-9)
+	assert.InDelta(t, -0.2, usd.Fees, 1e-9)
+	assert.InDelta(t, 3, usd.Net, 1e-9)
+
+	require.Len(t, usd.Days, 2)
+	assert.Equal(t, time.Unix(1568073600, 0).UTC(), usd.Days[0].Day)
+	assert.InDelta(t, 1, usd.Days[0].Net, 1e-9)
+	assert.InDelta(t, 1000, usd.Days[0].Balance, 1e-9)
+	assert.InDelta(t, 0.001, usd.Days[0].Rate, 1e-9)
+	assert.InDelta(t, 0.002, usd.Days[1].Rate, 1e-9)
+	assert.InDelta(t, 0.0015*365, usd.APR, 1e-9)
+}
+
+func TestFundingEarningsClassifier(t *testing.T) {
+	entries := []*ledger.Ledger{
+		{ID: 1, Currency: "USD", MTS: 1568073600000, Amount: 1, Balance: 101, Description: "custom"},
+	}
+	got := report.FundingEarnings(entries, func(l *ledger.Ledger) report.LedgerKind {
+		return report.LedgerFundingPayment
+	})
+	require.Len(t, got, 1)
+	assert.Equal(t, 1.0, got[0].Net)
+}
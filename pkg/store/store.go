@@ -0,0 +1,266 @@
+// Package store provides a small key-value interface used to persist the
+// state of long-running components, e.g. the deposit watcher, the order
+// tracker or export checkpoints, together with in-memory and file based
+// implementations.
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// Store persists values by key. Implementations have to be safe for
+// concurrent use.
+type Store interface {
+	// Get returns the value of key, or an error wrapping common.ErrNotFound
+	// if there is none
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+	// Delete removes key, deleting a missing key is not an error
+	Delete(key string) error
+	// Scan calls fn for every key with the given prefix in lexical order
+	// and stops at the first error returned by fn
+	Scan(prefix string, fn func(key string, value []byte) error) error
+}
+
+// Swapper is implemented by stores which can replace a value conditionally,
+// letting several writers update a key without losing each other's writes
+type Swapper interface {
+	// CompareAndSwap stores value under key if its current value equals old,
+	// a nil old requiring that key has no value. It reports whether value
+	// was stored.
+	CompareAndSwap(key string, old, value []byte) (bool, error)
+}
+
+// GetJSON decodes the value of key into v. It returns false if there is no
+// value.
+func GetJSON(s Store, key string, v interface{}) (bool, error) {
+	b, err := s.Get(key)
+	if errors.Is(err, common.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(b, v)
+}
+
+// PutJSON stores v encoded as JSON under key
+func PutJSON(s Store, key string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Put(key, b)
+}
+
+func notFound(key string) error {
+	return fmt.Errorf("%w: key %q", common.ErrNotFound, key)
+}
+
+// Memory keeps the values in memory, e.g. for tests
+type Memory struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+// NewMemory returns an empty in-memory store
+func NewMemory() *Memory {
+	return &Memory{values: make(map[string][]byte)}
+}
+
+func (m *Memory) Get(key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, ok := m.values[key]
+	if !ok {
+		return nil, notFound(key)
+	}
+	return append([]byte(nil), v...), nil
+}
+
+func (m *Memory) Put(key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+func (m *Memory) CompareAndSwap(key string, old, value []byte) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cur, ok := m.values[key]
+	if ok != (old != nil) || !bytes.Equal(cur, old) {
+		return false, nil
+	}
+	m.values[key] = append([]byte(nil), value...)
+	return true, nil
+}
+
+func (m *Memory) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.values, key)
+	return nil
+}
+
+func (m *Memory) Scan(prefix string, fn func(key string, value []byte) error) error {
+	m.mu.Lock()
+	keys := make([]string, 0, len(m.values))
+	values := make(map[string][]byte)
+	for k, v := range m.values {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+			values[k] = append([]byte(nil), v...)
+		}
+	}
+	m.mu.Unlock()
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := fn(k, values[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// File stores every value in a file of a directory. Values are replaced
+// atomically, so that a crash never leaves a partially written value. The
+// keys "." and ".." and keys ending in ".tmp", which names the files of
+// values being written, are rejected.
+// CompareAndSwap is atomic among the users of one File only, it does not
+// guard against other processes writing into the same directory.
+type File struct {
+	dir string
+	// mu serializes CompareAndSwap
+	mu sync.Mutex
+}
+
+// NewFile returns a store writing into dir, which is created if it does
+// not exist
+func NewFile(dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &File{dir: dir}, nil
+}
+
+// path escapes the key, so that keys may contain path separators
+func (f *File) path(key string) (string, error) {
+	if key == "" || key == "." || key == ".." || strings.HasSuffix(key, ".tmp") {
+		return "", fmt.Errorf("%w: invalid key %q", common.ErrBadRequest, key)
+	}
+	return filepath.Join(f.dir, url.PathEscape(key)), nil
+}
+
+func (f *File) Get(key string) ([]byte, error) {
+	p, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil, notFound(key)
+	}
+	return b, err
+}
+
+func (f *File) Put(key string, value []byte) error {
+	p, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	// a temporary file of its own per write, so concurrent writers of a key
+	// never rename each other's partial values into place
+	tmp, err := ioutil.TempFile(f.dir, filepath.Base(p)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(value)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), p)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+func (f *File) CompareAndSwap(key string, old, value []byte) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cur, err := f.Get(key)
+	switch {
+	case errors.Is(err, common.ErrNotFound):
+		if old != nil {
+			return false, nil
+		}
+	case err != nil:
+		return false, err
+	case old == nil || !bytes.Equal(cur, old):
+		return false, nil
+	}
+	return true, f.Put(key, value)
+}
+
+func (f *File) Delete(key string) error {
+	p, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(p)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (f *File) Scan(prefix string, fn func(key string, value []byte) error) error {
+	entries, err := ioutil.ReadDir(f.dir)
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	for _, e := range entries {
+		if e.IsDir() || strings.HasSuffix(e.Name(), ".tmp") {
+			continue
+		}
+		k, err := url.PathUnescape(e.Name())
+		if err != nil || !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		b, err := f.Get(k)
+		if errors.Is(err, common.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(k, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
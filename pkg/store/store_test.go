@@ -0,0 +1,121 @@
+package store_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file, err := store.NewFile(dir)
+	require.Nil(t, err)
+
+	for name, s := range map[string]store.Store{"memory": store.NewMemory(), "file": file} {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.Get("missing")
+			assert.True(t, errors.Is(err, common.ErrNotFound))
+
+			require.Nil(t, s.Put("order/2", []byte("two")))
+			require.Nil(t, s.Put("order/1", []byte("one")))
+			require.Nil(t, s.Put("cursor", []byte("c")))
+			require.Nil(t, s.Put("order/1", []byte("uno")))
+
+			v, err := s.Get("order/1")
+			require.Nil(t, err)
+			assert.Equal(t, "uno", string(v))
+
+			var keys, values []string
+			err = s.Scan("order/", func(k string, v []byte) error {
+				keys = append(keys, k)
+				values = append(values, string(v))
+				return nil
+			})
+			require.Nil(t, err)
+			assert.Equal(t, []string{"order/1", "order/2"}, keys)
+			assert.Equal(t, []string{"uno", "two"}, values)
+
+			stop := errors.New("stop")
+			assert.Equal(t, stop, s.Scan("", func(string, []byte) error { return stop }))
+
+			require.Nil(t, s.Delete("order/1"))
+			require.Nil(t, s.Delete("order/1"))
+			_, err = s.Get("order/1")
+			assert.True(t, errors.Is(err, common.ErrNotFound))
+
+			sw := s.(store.Swapper)
+			ok, err := sw.CompareAndSwap("cas", []byte("x"), []byte("1"))
+			require.Nil(t, err)
+			assert.False(t, ok)
+			ok, err = sw.CompareAndSwap("cas", nil, []byte("1"))
+			require.Nil(t, err)
+			assert.True(t, ok)
+			ok, err = sw.CompareAndSwap("cas", nil, []byte("2"))
+			require.Nil(t, err)
+			assert.False(t, ok)
+			ok, err = sw.CompareAndSwap("cas", []byte("1"), []byte("2"))
+			require.Nil(t, err)
+			assert.True(t, ok)
+			v, err = s.Get("cas")
+			require.Nil(t, err)
+			assert.Equal(t, "2", string(v))
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	s := store.NewMemory()
+	var v struct{ A int }
+	ok, err := store.GetJSON(s, "k", &v)
+	require.Nil(t, err)
+	assert.False(t, ok)
+
+	require.Nil(t, store.PutJSON(s, "k", map[string]int{"A": 3}))
+	ok, err = store.GetJSON(s, "k", &v)
+	require.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 3, v.A)
+}
+
+func TestFileKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file, err := store.NewFile(dir)
+	require.Nil(t, err)
+
+	for _, key := range []string{"", ".", "..", "value.tmp"} {
+		assert.True(t, errors.Is(file.Put(key, []byte("x")), common.ErrBadRequest), key)
+		_, err := file.Get(key)
+		assert.True(t, errors.Is(err, common.ErrBadRequest), key)
+		assert.True(t, errors.Is(file.Delete(key), common.ErrBadRequest), key)
+	}
+
+	// concurrent writers of a key each write a file of their own
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.Nil(t, file.Put("cursor", bytes.Repeat([]byte{'a' + byte(i)}, 4096)))
+		}(i)
+	}
+	wg.Wait()
+
+	v, err := file.Get("cursor")
+	require.Nil(t, err)
+	assert.Equal(t, bytes.Repeat(v[:1], 4096), v)
+	entries, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,484 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/execution"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecution"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecutionupdate"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/symbol"
+)
+
+// epsilon is the tolerance used when comparing amounts
+const epsilon = 1e-8
+
+// FeeModel returns the fee of a fill, negative as reported by the exchange,
+// and the currency it is charged in
+type FeeModel interface {
+	Fee(symbol string, maker bool, amount, price float64) (fee float64, currency string)
+}
+
+// RateFees charges a fraction of every fill, e.g. RateFees{Maker: 0.001,
+// Taker: 0.002}. Like on the exchange, the fee is charged in the received
+// currency: the base currency on buys and the quote currency on sells.
+type RateFees struct {
+	Maker float64
+	Taker float64
+}
+
+func (f RateFees) Fee(sym string, maker bool, amount, price float64) (float64, string) {
+	rate := f.Taker
+	if maker {
+		rate = f.Maker
+	}
+	s, err := symbol.Parse(sym)
+	if err != nil {
+		return 0, ""
+	}
+	if amount > 0 {
+		return -rate * amount, s.Base
+	}
+	return rate * amount * price, s.Quote
+}
+
+// SimConfig configures a simulation
+type SimConfig struct {
+	// Latency delays the execution of submissions and cancellations in
+	// simulated time, i.e. they are executed once a trade at least Latency
+	// after the previous one was observed
+	Latency time.Duration
+	// Fees charged on fills, none if nil
+	Fees FeeModel
+	// Tracker, if set, handles every event emitted by the simulation, so
+	// that backtests use the order state, fills and execution reports of the
+	// tracker used live
+	Tracker *execution.OrderTracker
+}
+
+// levels is an aggregated book, amounts by price
+type levels struct {
+	bids map[float64]float64
+	asks map[float64]float64
+}
+
+func newLevels() *levels {
+	return &levels{bids: make(map[float64]float64), asks: make(map[float64]float64)}
+}
+
+func (l *levels) apply(b *book.Book) {
+	side := l.bids
+	if b.Side == common.Ask {
+		side = l.asks
+	}
+	if b.Action == book.BookRemoveEntry {
+		delete(side, b.Price)
+		return
+	}
+	side[b.Price] = math.Abs(b.Amount)
+}
+
+func (l *levels) side(bid bool) map[float64]float64 {
+	if bid {
+		return l.bids
+	}
+	return l.asks
+}
+
+// best returns the best price of the side and its amount, false if it is
+// empty
+func (l *levels) best(bid bool) (float64, float64, bool) {
+	var best float64
+	found := false
+	for p := range l.side(bid) {
+		if !found || (bid && p > best) || (!bid && p < best) {
+			best, found = p, true
+		}
+	}
+	return best, l.side(bid)[best], found
+}
+
+// consume removes the filled amount from a level
+func (l *levels) consume(bid bool, price, amount float64) {
+	side := l.side(bid)
+	side[price] -= amount
+	if side[price] < epsilon {
+		delete(side, price)
+	}
+}
+
+type queuedIntent struct {
+	at int64
+	in Intent
+}
+
+// Simulated fills orders in memory against the aggregated books and the
+// trades it observes, so that strategies can be run on recorded events. It
+// emits the same events as the exchange: notifications, order events and
+// te/tu fills. The simulation is deterministic, the same events and intents
+// always result in the same fills.
+//
+// Incoming orders take the liquidity of the opposite side of the book,
+// market orders until they are filled or the book is exhausted, limit orders
+// up to their price. The remaining amount of limit orders rests and is
+// filled at its price, as maker, by opposite levels crossing it or by trades
+// at or through it. Filled liquidity is removed from the book until the
+// level is updated. Event times are taken from the observed trades. A
+// simulation is not safe for concurrent use.
+type Simulated struct {
+	cfg     SimConfig
+	books   map[string]*levels
+	open    []*order.Order // in order of submission
+	queued  []queuedIntent
+	pending []interface{} // events of SubmitOrder and SubmitCancel
+	mts     int64
+	nextID  int64
+	nextTr  int64
+}
+
+var _ execution.OrderSubmitter = (*Simulated)(nil)
+
+// NewSimulated returns a simulation without latency and fees
+func NewSimulated() *Simulated {
+	return NewSimulatedWithConfig(SimConfig{})
+}
+
+// NewSimulatedWithConfig returns a simulation configured by cfg
+func NewSimulatedWithConfig(cfg SimConfig) *Simulated {
+	return &Simulated{cfg: cfg, books: make(map[string]*levels)}
+}
+
+// Open returns the open orders
+func (s *Simulated) Open() []*order.Order {
+	out := make([]*order.Order, len(s.open))
+	for i, o := range s.open {
+		out[i] = copyOrder(o)
+	}
+	return out
+}
+
+func (s *Simulated) Observe(ev interface{}) []interface{} {
+	var evs []interface{}
+	switch e := ev.(type) {
+	case *book.Snapshot:
+		if len(e.Snapshot) == 0 {
+			break
+		}
+		sym := e.Snapshot[0].Symbol
+		l := newLevels()
+		for _, b := range e.Snapshot {
+			l.apply(b)
+		}
+		s.books[sym] = l
+		evs = s.match(sym)
+	case *book.Book:
+		l, ok := s.books[e.Symbol]
+		if !ok {
+			l = newLevels()
+			s.books[e.Symbol] = l
+		}
+		l.apply(e)
+		evs = s.match(e.Symbol)
+	case *trade.Trade:
+		if e.MTS > s.mts {
+			s.mts = e.MTS
+		}
+		evs = s.due()
+		evs = append(evs, s.tradeFills(e)...)
+	}
+	return append(s.drain(), s.emit(evs)...)
+}
+
+func (s *Simulated) Execute(ctx context.Context, in Intent) ([]interface{}, error) {
+	evs, err := s.enqueue(in)
+	return append(s.drain(), evs...), err
+}
+
+// SubmitOrder submits an order to the simulation, so that the helpers of
+// the execution package can be backtested. The resulting events are
+// returned by the next call of Observe or Execute.
+func (s *Simulated) SubmitOrder(ctx context.Context, onr *order.NewRequest) error {
+	evs, err := s.enqueue(Submit(onr))
+	s.pending = append(s.pending, evs...)
+	return err
+}
+
+// SubmitCancel cancels an order of the simulation like SubmitOrder
+func (s *Simulated) SubmitCancel(ctx context.Context, ocr *order.CancelRequest) error {
+	evs, err := s.enqueue(Cancel(ocr))
+	s.pending = append(s.pending, evs...)
+	return err
+}
+
+// enqueue executes the intent, or queues it if there is latency
+func (s *Simulated) enqueue(in Intent) ([]interface{}, error) {
+	if in.Submit == nil && in.Cancel == nil {
+		return nil, fmt.Errorf("%w: empty intent", common.ErrBadRequest)
+	}
+	if s.cfg.Latency > 0 {
+		s.queued = append(s.queued, queuedIntent{at: s.mts + s.cfg.Latency.Milliseconds(), in: in})
+		return nil, nil
+	}
+	return s.emit(s.execute(in)), nil
+}
+
+// due executes the queued intents whose latency has passed
+func (s *Simulated) due() []interface{} {
+	var evs []interface{}
+	for len(s.queued) > 0 && s.queued[0].at <= s.mts {
+		in := s.queued[0].in
+		s.queued = s.queued[1:]
+		evs = append(evs, s.execute(in)...)
+	}
+	return evs
+}
+
+func (s *Simulated) drain() []interface{} {
+	evs := s.pending
+	s.pending = nil
+	return evs
+}
+
+// emit passes the events to the tracker
+func (s *Simulated) emit(evs []interface{}) []interface{} {
+	if s.cfg.Tracker != nil {
+		for _, ev := range evs {
+			s.cfg.Tracker.Handle(ev)
+		}
+	}
+	return evs
+}
+
+func (s *Simulated) execute(in Intent) []interface{} {
+	if in.Submit != nil {
+		return s.submit(in.Submit)
+	}
+	return s.cancel(in.Cancel)
+}
+
+func (s *Simulated) submit(onr *order.NewRequest) []interface{} {
+	o := &order.Order{
+		CID:        onr.CID,
+		GID:        onr.GID,
+		Symbol:     onr.Symbol,
+		MTSCreated: s.mts,
+		MTSUpdated: s.mts,
+		Amount:     onr.Amount,
+		AmountOrig: onr.Amount,
+		Type:       onr.Type,
+		Status:     common.OrderStatusActive,
+		Price:      onr.Price,
+	}
+
+	market := strings.HasSuffix(onr.Type, "MARKET")
+	var reason string
+	switch {
+	case onr.Amount == 0:
+		reason = "amount must not be zero"
+	case !market && !strings.HasSuffix(onr.Type, "LIMIT"):
+		reason = fmt.Sprintf("unsupported order type %q", onr.Type)
+	case !market && onr.Price <= 0:
+		reason = "price must be positive"
+	case market && !s.liquid(o):
+		reason = "no liquidity"
+	}
+	if reason != "" {
+		return []interface{}{s.notify("on-req", order.New(*o), notification.StatusError, reason)}
+	}
+
+	s.nextID++
+	o.ID = s.nextID
+	evs := []interface{}{
+		s.notify("on-req", order.New(*o), notification.StatusSuccess, "Submitting order."),
+		(*order.New)(copyOrder(o)),
+	}
+	evs = append(evs, s.take(o, market)...)
+	switch {
+	case math.Abs(o.Amount) < epsilon:
+	case market:
+		// the book is exhausted
+		o.Status = common.OrderStatusCanceled
+		evs = append(evs, (*order.Cancel)(copyOrder(o)))
+	default:
+		s.open = append(s.open, o)
+	}
+	return evs
+}
+
+func (s *Simulated) cancel(ocr *order.CancelRequest) []interface{} {
+	for i, o := range s.open {
+		if (ocr.ID != 0 && o.ID == ocr.ID) || (ocr.ID == 0 && ocr.CID != 0 && o.CID == ocr.CID) {
+			s.open = append(s.open[:i], s.open[i+1:]...)
+			o.Status = common.OrderStatusCanceled
+			o.MTSUpdated = s.mts
+			return []interface{}{
+				s.notify("oc-req", order.Cancel(*o), notification.StatusSuccess, "Submitted for cancellation."),
+				(*order.Cancel)(o),
+			}
+		}
+	}
+	return []interface{}{s.notify("oc-req", order.Cancel{ID: ocr.ID, CID: ocr.CID}, notification.StatusError, "Order not found.")}
+}
+
+// liquid reports whether the opposite side of the book of the order has
+// levels
+func (s *Simulated) liquid(o *order.Order) bool {
+	l, ok := s.books[o.Symbol]
+	if !ok {
+		return false
+	}
+	_, _, ok = l.best(o.Amount < 0)
+	return ok
+}
+
+// take fills an incoming order against the opposite side of the book
+func (s *Simulated) take(o *order.Order, market bool) []interface{} {
+	l, ok := s.books[o.Symbol]
+	if !ok {
+		return nil
+	}
+	bid := o.Amount < 0
+	var evs []interface{}
+	for math.Abs(o.Amount) >= epsilon {
+		price, avail, ok := l.best(bid)
+		if !ok || (!market && !crosses(o, price)) {
+			break
+		}
+		qty := math.Min(math.Abs(o.Amount), avail)
+		l.consume(bid, price, qty)
+		evs = append(evs, s.fill(o, math.Copysign(qty, o.Amount), price, false)...)
+	}
+	return evs
+}
+
+// match fills the open orders of the symbol crossed by opposite levels
+func (s *Simulated) match(sym string) []interface{} {
+	l := s.books[sym]
+	var evs []interface{}
+	for _, o := range s.open {
+		if o.Symbol != sym {
+			continue
+		}
+		bid := o.Amount < 0
+		for math.Abs(o.Amount) >= epsilon {
+			price, avail, ok := l.best(bid)
+			if !ok || !crosses(o, price) {
+				break
+			}
+			qty := math.Min(math.Abs(o.Amount), avail)
+			l.consume(bid, price, qty)
+			evs = append(evs, s.fill(o, math.Copysign(qty, o.Amount), o.Price, true)...)
+		}
+	}
+	s.removeFilled()
+	return evs
+}
+
+// tradeFills fills the open orders of the symbol traded through by t, the
+// traded amount is shared in order of submission
+func (s *Simulated) tradeFills(t *trade.Trade) []interface{} {
+	volume := math.Abs(t.Amount)
+	var evs []interface{}
+	for _, o := range s.open {
+		if volume < epsilon {
+			break
+		}
+		// buys are filled by sells and the other way round
+		if o.Symbol != t.Pair || (o.Amount > 0) == (t.Amount > 0) || !crosses(o, t.Price) {
+			continue
+		}
+		qty := math.Min(math.Abs(o.Amount), volume)
+		volume -= qty
+		evs = append(evs, s.fill(o, math.Copysign(qty, o.Amount), o.Price, true)...)
+	}
+	s.removeFilled()
+	return evs
+}
+
+func (s *Simulated) removeFilled() {
+	open := s.open[:0]
+	for _, o := range s.open {
+		if math.Abs(o.Amount) >= epsilon {
+			open = append(open, o)
+		}
+	}
+	s.open = open
+}
+
+func crosses(o *order.Order, price float64) bool {
+	if o.Amount > 0 {
+		return price <= o.Price
+	}
+	return price >= o.Price
+}
+
+// fill executes the signed amount of the order at the price and returns the
+// trade and the order update
+func (s *Simulated) fill(o *order.Order, amount, price float64, maker bool) []interface{} {
+	s.nextTr++
+	flag := -1
+	if maker {
+		flag = 1
+	}
+	te := &tradeexecution.TradeExecution{
+		ID:         s.nextTr,
+		Pair:       o.Symbol,
+		MTS:        s.mts,
+		OrderID:    o.ID,
+		ExecAmount: amount,
+		ExecPrice:  price,
+		OrderType:  o.Type,
+		OrderPrice: o.Price,
+		Maker:      flag,
+	}
+	tu := &tradeexecutionupdate.TradeExecutionUpdate{
+		ID:            te.ID,
+		Pair:          te.Pair,
+		MTS:           te.MTS,
+		OrderID:       te.OrderID,
+		ExecAmount:    te.ExecAmount,
+		ExecPrice:     te.ExecPrice,
+		OrderType:     te.OrderType,
+		OrderPrice:    te.OrderPrice,
+		Maker:         te.Maker,
+		ClientOrderID: o.CID,
+	}
+	if s.cfg.Fees != nil {
+		tu.Fee, tu.FeeCurrency = s.cfg.Fees.Fee(o.Symbol, maker, amount, price)
+	}
+
+	filled := math.Abs(o.AmountOrig - o.Amount)
+	o.PriceAvg = (o.PriceAvg*filled + price*math.Abs(amount)) / (filled + math.Abs(amount))
+	o.Amount -= amount
+	o.MTSUpdated = s.mts
+	if math.Abs(o.Amount) < epsilon {
+		o.Amount = 0
+		o.Status = fmt.Sprintf("%s @ %v(%v)", common.OrderStatusExecuted, price, amount)
+		return []interface{}{te, tu, (*order.Cancel)(copyOrder(o))}
+	}
+	o.Status = fmt.Sprintf("%s @ %v(%v)", common.OrderStatusPartiallyFilled, price, amount)
+	return []interface{}{te, tu, (*order.Update)(copyOrder(o))}
+}
+
+func (s *Simulated) notify(typ string, info interface{}, status notification.Status, text string) *notification.Notification {
+	return &notification.Notification{
+		MTS:        s.mts,
+		Type:       typ,
+		NotifyInfo: info,
+		Status:     status,
+		Text:       text,
+	}
+}
+
+func copyOrder(o *order.Order) *order.Order {
+	c := *o
+	return &c
+}
@@ -0,0 +1,150 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/execution"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecutionupdate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fillsOf(evs []interface{}) []*tradeexecutionupdate.TradeExecutionUpdate {
+	var out []*tradeexecutionupdate.TradeExecutionUpdate
+	for _, ev := range evs {
+		if tu, ok := ev.(*tradeexecutionupdate.TradeExecutionUpdate); ok {
+			out = append(out, tu)
+		}
+	}
+	return out
+}
+
+func TestSimulatedDepth(t *testing.T) {
+	sim := NewSimulated()
+	ctx := context.Background()
+	sim.Observe(&book.Snapshot{Snapshot: []*book.Book{level(99, 1), level(100, -0.3), level(101, -0.5)}})
+
+	// a market order walks the book and the rest is canceled once exhausted
+	evs, err := sim.Execute(ctx, Submit(&order.NewRequest{Symbol: "tBTCUSD", Type: common.OrderTypeExchangeMarket, Amount: 1}))
+	require.Nil(t, err)
+	fills := fillsOf(evs)
+	require.Len(t, fills, 2)
+	assert.Equal(t, 0.3, fills[0].ExecAmount)
+	assert.Equal(t, float64(100), fills[0].ExecPrice)
+	assert.Equal(t, 0.5, fills[1].ExecAmount)
+	assert.Equal(t, float64(101), fills[1].ExecPrice)
+	assert.Equal(t, -1, fills[1].Maker)
+	c := evs[len(evs)-1].(*order.Cancel)
+	assert.Equal(t, common.OrderStatusCanceled, c.Status)
+	assert.InDelta(t, 0.2, c.Amount, 1e-9)
+	assert.InDelta(t, 100.625, c.PriceAvg, 1e-9)
+
+	// the consumed liquidity is back once the book is updated, a limit order
+	// takes up to its price and rests with the remainder
+	sim.Observe(level(100, -0.3))
+	evs, err = sim.Execute(ctx, Submit(&order.NewRequest{Symbol: "tBTCUSD", Type: common.OrderTypeExchangeLimit, Amount: 0.5, Price: 100}))
+	require.Nil(t, err)
+	fills = fillsOf(evs)
+	require.Len(t, fills, 1)
+	assert.Equal(t, 0.3, fills[0].ExecAmount)
+	u := evs[len(evs)-1].(*order.Update)
+	assert.Equal(t, "PARTIALLY FILLED @ 100(0.3)", u.Status)
+	open := sim.Open()
+	require.Len(t, open, 1)
+	assert.InDelta(t, 0.2, open[0].Amount, 1e-9)
+}
+
+func TestSimulatedTradeFills(t *testing.T) {
+	sim := NewSimulatedWithConfig(SimConfig{Fees: RateFees{Maker: 0.001, Taker: 0.002}})
+	ctx := context.Background()
+	sim.Observe(&book.Snapshot{Snapshot: []*book.Book{level(98, 1), level(100, -1)}})
+
+	_, err := sim.Execute(ctx, Submit(&order.NewRequest{Symbol: "tBTCUSD", Type: common.OrderTypeExchangeLimit, Amount: 1, Price: 99}))
+	require.Nil(t, err)
+
+	// buys of others and sells above the price do not fill the bid
+	assert.Empty(t, sim.Observe(&trade.Trade{Pair: "tBTCUSD", MTS: 1000, Amount: 2, Price: 99}))
+	assert.Empty(t, sim.Observe(&trade.Trade{Pair: "tBTCUSD", MTS: 1001, Amount: -2, Price: 99.5}))
+
+	evs := sim.Observe(&trade.Trade{Pair: "tBTCUSD", MTS: 1002, Amount: -0.4, Price: 99})
+	fills := fillsOf(evs)
+	require.Len(t, fills, 1)
+	assert.Equal(t, 0.4, fills[0].ExecAmount)
+	assert.Equal(t, 1, fills[0].Maker)
+	assert.InDelta(t, -0.0004, fills[0].Fee, 1e-12)
+	assert.Equal(t, "BTC", fills[0].FeeCurrency)
+	assert.Equal(t, int64(1002), fills[0].MTS)
+	assert.IsType(t, &order.Update{}, evs[len(evs)-1])
+
+	evs = sim.Observe(&trade.Trade{Pair: "tBTCUSD", MTS: 1003, Amount: -5, Price: 98.5})
+	fills = fillsOf(evs)
+	require.Len(t, fills, 1)
+	assert.InDelta(t, 0.6, fills[0].ExecAmount, 1e-9)
+	assert.Equal(t, float64(99), fills[0].ExecPrice)
+	assert.Equal(t, "EXECUTED @ 99(0.6)", evs[len(evs)-1].(*order.Cancel).Status)
+	assert.Empty(t, sim.Open())
+
+	// takers pay the taker fee, sells in the quote currency
+	evs, err = sim.Execute(ctx, Submit(&order.NewRequest{Symbol: "tBTCUSD", Type: common.OrderTypeExchangeMarket, Amount: -0.5}))
+	require.Nil(t, err)
+	fills = fillsOf(evs)
+	require.Len(t, fills, 1)
+	assert.InDelta(t, -0.098, fills[0].Fee, 1e-12)
+	assert.Equal(t, "USD", fills[0].FeeCurrency)
+}
+
+func TestSimulatedLatency(t *testing.T) {
+	sim := NewSimulatedWithConfig(SimConfig{Latency: 100 * time.Millisecond})
+	ctx := context.Background()
+	sim.Observe(&book.Snapshot{Snapshot: []*book.Book{level(98, 1), level(100, -1)}})
+	sim.Observe(&trade.Trade{Pair: "tBTCUSD", MTS: 1000, Amount: 0.1, Price: 100})
+
+	evs, err := sim.Execute(ctx, Submit(&order.NewRequest{Symbol: "tBTCUSD", Type: common.OrderTypeExchangeMarket, Amount: 0.5}))
+	require.Nil(t, err)
+	assert.Empty(t, evs)
+	assert.Empty(t, sim.Observe(&trade.Trade{Pair: "tBTCUSD", MTS: 1099, Amount: 0.1, Price: 100}))
+
+	// the book moved while the order was in flight
+	sim.Observe(&book.Book{Symbol: "tBTCUSD", Price: 100, Side: common.Ask, Action: book.BookRemoveEntry})
+	sim.Observe(level(100.5, -1))
+
+	evs = sim.Observe(&trade.Trade{Pair: "tBTCUSD", MTS: 1100, Amount: 0.1, Price: 100.5})
+	fills := fillsOf(evs)
+	require.Len(t, fills, 1)
+	assert.Equal(t, 100.5, fills[0].ExecPrice)
+	assert.Equal(t, int64(1100), fills[0].MTS)
+}
+
+func TestSimulatedTracker(t *testing.T) {
+	tracker := execution.NewOrderTracker()
+	var reports []execution.ExecutionReport
+	tracker.OnReport(func(r execution.ExecutionReport) { reports = append(reports, r) })
+
+	sim := NewSimulatedWithConfig(SimConfig{Tracker: tracker, Fees: RateFees{Taker: 0.002}})
+	ctx := context.Background()
+	sim.Observe(&book.Snapshot{Snapshot: []*book.Book{level(98, 1), level(100, -1)}})
+
+	// submitted like the websocket client by the execution helpers
+	require.Nil(t, sim.SubmitOrder(ctx, &order.NewRequest{CID: 3, Symbol: "tBTCUSD", Type: common.OrderTypeExchangeMarket, Amount: 0.5}))
+	f, ok := tracker.Fills(1)
+	require.True(t, ok)
+	assert.Equal(t, 0.5, f.Filled)
+	assert.InDelta(t, -0.001, f.Fees["BTC"], 1e-12)
+
+	var types []execution.ExecType
+	for _, r := range reports {
+		types = append(types, r.ExecType)
+	}
+	assert.Equal(t, []execution.ExecType{execution.ExecNew, execution.ExecTrade, execution.ExecDone}, types)
+
+	// the events are also returned by the next call
+	evs := sim.Observe(&trade.Trade{Pair: "tBTCUSD", MTS: 1000, Amount: 0.1, Price: 100})
+	assert.Len(t, fillsOf(evs), 1)
+	assert.Empty(t, sim.Observe(&trade.Trade{Pair: "tBTCUSD", MTS: 1001, Amount: 0.1, Price: 100}))
+}
@@ -0,0 +1,141 @@
+// Package strategy runs trading strategies against interchangeable
+// execution backends. A Strategy receives the market and account events of
+// the websocket client and answers with order intents, which the Runner
+// executes with a Backend: Live sends them to the exchange, Simulated fills
+// them in memory against the books it observes. The same strategy can
+// thereby be backtested on recorded events and deployed unchanged, e.g.:
+//
+//	r := strategy.NewRunner(myStrategy, strategy.NewLive(client))
+//	err := r.Run(ctx, client.Listen(), func(err error) { log.Print(err) })
+//
+// or, replaying recorded events:
+//
+//	r := strategy.NewRunner(myStrategy, strategy.NewSimulated())
+//	err := r.Run(ctx, recorded, nil)
+package strategy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/execution"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+)
+
+// Intent is an order operation requested by a strategy, either a submission
+// or a cancellation
+type Intent struct {
+	Submit *order.NewRequest
+	Cancel *order.CancelRequest
+}
+
+// Submit returns the intent to submit the order
+func Submit(onr *order.NewRequest) Intent {
+	return Intent{Submit: onr}
+}
+
+// Cancel returns the intent to cancel the order
+func Cancel(ocr *order.CancelRequest) Intent {
+	return Intent{Cancel: ocr}
+}
+
+// Strategy decides on orders. OnEvent is called with every event in order,
+// market data such as books and trades as well as the order events, fills
+// and notifications of the account, and returns the intents to execute.
+type Strategy interface {
+	OnEvent(ev interface{}) []Intent
+}
+
+// StrategyFunc adapts a function to a Strategy
+type StrategyFunc func(ev interface{}) []Intent
+
+func (f StrategyFunc) OnEvent(ev interface{}) []Intent {
+	return f(ev)
+}
+
+// Backend executes intents
+type Backend interface {
+	// Observe is called with every event before the strategy and returns the
+	// events caused by it, e.g. fills of a simulated order
+	Observe(ev interface{}) []interface{}
+	// Execute executes the intent and returns the events it caused
+	// immediately. Backends connected to the exchange return none, as the
+	// events are received by the websocket client.
+	Execute(ctx context.Context, in Intent) ([]interface{}, error)
+}
+
+// Runner feeds the events to the strategy and executes its intents
+type Runner struct {
+	strategy Strategy
+	backend  Backend
+}
+
+// NewRunner returns a runner of the strategy executing with the backend
+func NewRunner(s Strategy, b Backend) *Runner {
+	return &Runner{strategy: s, backend: b}
+}
+
+// Run handles the events until ctx is done or events is closed. Failed
+// executions are passed to onError, if set, and do not stop the runner.
+func (r *Runner) Run(ctx context.Context, events <-chan interface{}, onError func(error)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := r.Handle(ctx, ev); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Handle passes a single event, and all events caused by it, to the
+// backend and the strategy. It returns the first failed execution, the
+// remaining intents are executed nonetheless.
+func (r *Runner) Handle(ctx context.Context, ev interface{}) error {
+	var first error
+	queue := []interface{}{ev}
+	for len(queue) > 0 {
+		ev := queue[0]
+		queue = queue[1:]
+
+		queue = append(queue, r.backend.Observe(ev)...)
+		for _, in := range r.strategy.OnEvent(ev) {
+			evs, err := r.backend.Execute(ctx, in)
+			if err != nil && first == nil {
+				first = err
+			}
+			queue = append(queue, evs...)
+		}
+	}
+	return first
+}
+
+// Live executes intents on the exchange, e.g. with the websocket client
+type Live struct {
+	s execution.OrderSubmitter
+}
+
+// NewLive returns a backend submitting to s
+func NewLive(s execution.OrderSubmitter) *Live {
+	return &Live{s: s}
+}
+
+func (l *Live) Observe(ev interface{}) []interface{} {
+	return nil
+}
+
+func (l *Live) Execute(ctx context.Context, in Intent) ([]interface{}, error) {
+	switch {
+	case in.Submit != nil:
+		return nil, l.s.SubmitOrder(ctx, in.Submit)
+	case in.Cancel != nil:
+		return nil, l.s.SubmitCancel(ctx, in.Cancel)
+	}
+	return nil, fmt.Errorf("%w: empty intent", common.ErrBadRequest)
+}
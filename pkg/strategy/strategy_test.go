@@ -0,0 +1,160 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/execution"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func level(price, amount float64) *book.Book {
+	b := &book.Book{Symbol: "tBTCUSD", Price: price, Amount: amount, Count: 1, Side: common.Bid}
+	if amount < 0 {
+		b.Side = common.Ask
+	}
+	return b
+}
+
+// bidBelowAsk places a bid one below the best ask of the first snapshot and
+// sells at market once it is filled
+type bidBelowAsk struct {
+	tracker *execution.OrderTracker
+	events  []interface{}
+}
+
+func (s *bidBelowAsk) OnEvent(ev interface{}) []Intent {
+	s.events = append(s.events, ev)
+	s.tracker.Handle(ev)
+
+	switch e := ev.(type) {
+	case *book.Snapshot:
+		return []Intent{Submit(&order.NewRequest{CID: 1, Symbol: "tBTCUSD", Type: common.OrderTypeExchangeLimit, Amount: 0.5, Price: 99})}
+	case *order.Cancel:
+		if e.CID == 1 {
+			return []Intent{Submit(&order.NewRequest{CID: 2, Symbol: "tBTCUSD", Type: common.OrderTypeExchangeMarket, Amount: -0.5})}
+		}
+	}
+	return nil
+}
+
+func TestSimulatedRun(t *testing.T) {
+	s := &bidBelowAsk{tracker: execution.NewOrderTracker()}
+	sim := NewSimulated()
+	r := NewRunner(s, sim)
+	ctx := context.Background()
+
+	require.Nil(t, r.Handle(ctx, &trade.Trade{Pair: "tBTCUSD", MTS: 1600000000000, Amount: 1, Price: 100}))
+	require.Nil(t, r.Handle(ctx, &book.Snapshot{Snapshot: []*book.Book{level(98, 2), level(100, -1)}}))
+	open := sim.Open()
+	require.Len(t, open, 1)
+	assert.Equal(t, float64(99), open[0].Price)
+	assert.Equal(t, int64(1600000000000), open[0].MTSCreated)
+
+	// the bid is filled once the ask reaches it, then sold at the bid
+	require.Nil(t, r.Handle(ctx, level(99, -1)))
+	assert.Empty(t, sim.Open())
+
+	buy, ok := s.tracker.Fills(1)
+	require.True(t, ok)
+	assert.Equal(t, 0.5, buy.Filled)
+	assert.Equal(t, float64(99), buy.AvgPrice)
+	assert.Equal(t, 1, buy.Trades)
+
+	sell, ok := s.tracker.Fills(2)
+	require.True(t, ok)
+	assert.Equal(t, -0.5, sell.Filled)
+	assert.Equal(t, float64(98), sell.AvgPrice)
+	assert.Empty(t, s.tracker.Open())
+
+	o, ok := s.tracker.Order(2)
+	require.True(t, ok)
+	assert.Equal(t, "EXECUTED @ 98(-0.5)", o.Status)
+}
+
+func TestSimulatedRejectAndCancel(t *testing.T) {
+	sim := NewSimulated()
+	ctx := context.Background()
+
+	// no book to fill a market order
+	evs, err := sim.Execute(ctx, Submit(&order.NewRequest{Symbol: "tBTCUSD", Type: common.OrderTypeExchangeMarket, Amount: 1}))
+	require.Nil(t, err)
+	require.Len(t, evs, 1)
+	n := evs[0].(*notification.Notification)
+	assert.Equal(t, notification.StatusError, n.Status)
+	assert.Equal(t, "no liquidity", n.Text)
+
+	sim.Observe(&book.Snapshot{Snapshot: []*book.Book{level(98, 2), level(100, -1)}})
+	evs, err = sim.Execute(ctx, Submit(&order.NewRequest{CID: 7, Symbol: "tBTCUSD", Type: common.OrderTypeExchangeLimit, Amount: -1, Price: 101}))
+	require.Nil(t, err)
+	require.Len(t, evs, 2)
+	assert.Equal(t, notification.StatusSuccess, evs[0].(*notification.Notification).Status)
+	assert.Len(t, sim.Open(), 1)
+
+	evs, err = sim.Execute(ctx, Cancel(&order.CancelRequest{CID: 7}))
+	require.Nil(t, err)
+	require.Len(t, evs, 2)
+	assert.Equal(t, common.OrderStatusCanceled, evs[1].(*order.Cancel).Status)
+	assert.Empty(t, sim.Open())
+
+	evs, err = sim.Execute(ctx, Cancel(&order.CancelRequest{ID: 1}))
+	require.Nil(t, err)
+	assert.Equal(t, notification.StatusError, evs[0].(*notification.Notification).Status)
+
+	_, err = sim.Execute(ctx, Intent{})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
+
+type recordingSubmitter struct {
+	submitted []*order.NewRequest
+	canceled  []*order.CancelRequest
+	err       error
+}
+
+func (r *recordingSubmitter) SubmitOrder(ctx context.Context, onr *order.NewRequest) error {
+	r.submitted = append(r.submitted, onr)
+	return r.err
+}
+
+func (r *recordingSubmitter) SubmitCancel(ctx context.Context, ocr *order.CancelRequest) error {
+	r.canceled = append(r.canceled, ocr)
+	return r.err
+}
+
+func TestLiveRun(t *testing.T) {
+	sub := &recordingSubmitter{}
+	r := NewRunner(StrategyFunc(func(ev interface{}) []Intent {
+		if _, ok := ev.(*book.Snapshot); ok {
+			return []Intent{
+				Submit(&order.NewRequest{Symbol: "tBTCUSD", Type: common.OrderTypeExchangeLimit, Amount: 1, Price: 99}),
+				Cancel(&order.CancelRequest{ID: 5}),
+			}
+		}
+		return nil
+	}), NewLive(sub))
+
+	snap := &book.Snapshot{Snapshot: []*book.Book{level(98, 2)}}
+	require.Nil(t, r.Handle(context.Background(), snap))
+	assert.Len(t, sub.submitted, 1)
+	assert.Len(t, sub.canceled, 1)
+
+	// failed executions are reported and do not stop the runner
+	sub.err = errors.New("not connected")
+	events := make(chan interface{}, 2)
+	events <- snap
+	events <- snap
+	close(events)
+
+	var errs []error
+	require.Nil(t, r.Run(context.Background(), events, func(err error) { errs = append(errs, err) }))
+	assert.Len(t, sub.submitted, 3)
+	assert.Len(t, sub.canceled, 3)
+	assert.Len(t, errs, 2)
+}
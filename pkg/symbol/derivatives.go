@@ -0,0 +1,51 @@
+package symbol
+
+import "strings"
+
+// PerpetualSuffix marks both currencies of perpetual contract symbols, e.g.
+// tBTCF0:USTF0
+const PerpetualSuffix = "F0"
+
+// IndexQuotes maps the margin currency of perpetual contracts to the quote
+// currency of the spot pair their price is indexed on
+var IndexQuotes = map[string]string{
+	"UST": "USD",
+	"EUT": "EUR",
+}
+
+// NewPerpetual returns the perpetual contract symbol for the given base and
+// margin currency, e.g. NewPerpetual("BTC", "UST") is tBTCF0:USTF0
+func NewPerpetual(base, margin string) Symbol {
+	return NewTrading(base+PerpetualSuffix, margin+PerpetualSuffix)
+}
+
+// IsPerpetual reports whether the symbol is a perpetual contract
+func (s Symbol) IsPerpetual() bool {
+	return s.Type == Trading &&
+		len(s.Base) > len(PerpetualSuffix) && strings.HasSuffix(s.Base, PerpetualSuffix) &&
+		len(s.Quote) > len(PerpetualSuffix) && strings.HasSuffix(s.Quote, PerpetualSuffix)
+}
+
+// MarginCurrency returns the currency perpetual contracts are collateralized
+// and settled in, e.g. UST for tBTCF0:USTF0. Returns false for other symbols.
+func (s Symbol) MarginCurrency() (string, bool) {
+	if !s.IsPerpetual() {
+		return "", false
+	}
+	return strings.TrimSuffix(s.Quote, PerpetualSuffix), true
+}
+
+// Underlying returns the spot pair the price of a perpetual contract is
+// indexed on, e.g. tBTCUSD for tBTCF0:USTF0. Returns false for other symbols.
+func (s Symbol) Underlying() (Symbol, bool) {
+	margin, ok := s.MarginCurrency()
+	if !ok {
+		return Symbol{}, false
+	}
+
+	quote := margin
+	if q, ok := IndexQuotes[margin]; ok {
+		quote = q
+	}
+	return NewTrading(strings.TrimSuffix(s.Base, PerpetualSuffix), quote), true
+}
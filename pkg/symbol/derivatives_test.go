@@ -0,0 +1,38 @@
+package symbol_test
+
+import (
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/symbol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPerpetual(t *testing.T) {
+	s := symbol.MustParse("tBTCF0:USTF0")
+	assert.True(t, s.IsPerpetual())
+	assert.Equal(t, s, symbol.NewPerpetual("BTC", "UST"))
+
+	margin, ok := s.MarginCurrency()
+	assert.True(t, ok)
+	assert.Equal(t, "UST", margin)
+
+	u, ok := s.Underlying()
+	assert.True(t, ok)
+	assert.Equal(t, "tBTCUSD", u.String())
+
+	u, ok = symbol.MustParse("tETHF0:BTCF0").Underlying()
+	assert.True(t, ok)
+	assert.Equal(t, "tETHBTC", u.String())
+}
+
+func TestNotPerpetual(t *testing.T) {
+	for _, in := range []string{"tBTCUSD", "tF0:USTF0", "tBTCF0:UST", "fUSTF0"} {
+		s := symbol.MustParse(in)
+		assert.False(t, s.IsPerpetual(), in)
+
+		_, ok := s.MarginCurrency()
+		assert.False(t, ok)
+		_, ok = s.Underlying()
+		assert.False(t, ok)
+	}
+}
@@ -0,0 +1,125 @@
+// Package symbol converts between API symbols such as tBTCUSD, tDUSK:USD or
+// fUSD and their base, quote and type components.
+package symbol
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// Type is the kind of market a symbol refers to
+type Type string
+
+const (
+	Trading Type = "trading"
+	Funding Type = "funding"
+)
+
+// currencyLen is the length of short currency codes, pairs of two short codes
+// are written without separator
+const currencyLen = 3
+
+// Symbol is the structured form of an API symbol. Funding symbols only have a
+// base currency.
+type Symbol struct {
+	Type  Type
+	Base  string
+	Quote string
+}
+
+// Parse converts an API symbol to its components.
+//
+//	Parse("tBTCUSD")   // {Trading, BTC, USD}
+//	Parse("tDUSK:USD") // {Trading, DUSK, USD}
+//	Parse("fUSD")      // {Funding, USD, ""}
+func Parse(s string) (Symbol, error) {
+	switch {
+	case strings.HasPrefix(s, common.TradingPrefix):
+		base, quote, err := splitPair(s[len(common.TradingPrefix):])
+		if err != nil {
+			return Symbol{}, fmt.Errorf("%w: invalid trading symbol %q", common.ErrBadRequest, s)
+		}
+		return Symbol{Type: Trading, Base: base, Quote: quote}, nil
+	case strings.HasPrefix(s, common.FundingPrefix):
+		ccy := s[len(common.FundingPrefix):]
+		if len(ccy) < currencyLen || strings.Contains(ccy, ":") {
+			return Symbol{}, fmt.Errorf("%w: invalid funding symbol %q", common.ErrBadRequest, s)
+		}
+		return Symbol{Type: Funding, Base: ccy}, nil
+	default:
+		return Symbol{}, fmt.Errorf("%w: unknown symbol prefix %q", common.ErrBadRequest, s)
+	}
+}
+
+// MustParse is like Parse but panics on invalid symbols. It is meant for
+// constant symbols known to be valid.
+func MustParse(s string) Symbol {
+	sym, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return sym
+}
+
+// ParsePair converts a pair without prefix, as listed by the conf endpoint
+// (BTCUSD, DUSK:USD), to a trading symbol.
+func ParsePair(pair string) (Symbol, error) {
+	return Parse(common.TradingPrefix + pair)
+}
+
+// NewTrading returns the trading symbol of the given currencies
+func NewTrading(base, quote string) Symbol {
+	return Symbol{Type: Trading, Base: strings.ToUpper(base), Quote: strings.ToUpper(quote)}
+}
+
+// NewFunding returns the funding symbol of the given currency
+func NewFunding(ccy string) Symbol {
+	return Symbol{Type: Funding, Base: strings.ToUpper(ccy)}
+}
+
+// Pair returns the symbol without prefix, e.g. BTCUSD or DUSK:USD. Funding
+// symbols return their currency.
+func (s Symbol) Pair() string {
+	if s.Type == Funding {
+		return s.Base
+	}
+	if len(s.Base) == currencyLen && len(s.Quote) == currencyLen {
+		return s.Base + s.Quote
+	}
+	return s.Base + ":" + s.Quote
+}
+
+// String returns the API symbol, e.g. tBTCUSD, tDUSK:USD or fUSD
+func (s Symbol) String() string {
+	if s.Type == Funding {
+		return common.FundingPrefix + s.Base
+	}
+	return common.TradingPrefix + s.Pair()
+}
+
+// IsTrading reports whether the symbol is a trading pair
+func (s Symbol) IsTrading() bool {
+	return s.Type == Trading
+}
+
+// IsFunding reports whether the symbol is a funding currency
+func (s Symbol) IsFunding() bool {
+	return s.Type == Funding
+}
+
+func splitPair(pair string) (string, string, error) {
+	if i := strings.Index(pair, ":"); i >= 0 {
+		base, quote := pair[:i], pair[i+1:]
+		if base == "" || quote == "" || strings.Contains(quote, ":") {
+			return "", "", fmt.Errorf("invalid pair %q", pair)
+		}
+		return base, quote, nil
+	}
+
+	if len(pair) != 2*currencyLen {
+		return "", "", fmt.Errorf("invalid pair %q", pair)
+	}
+	return pair[:currencyLen], pair[currencyLen:], nil
+}
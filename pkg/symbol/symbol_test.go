@@ -0,0 +1,53 @@
+package symbol_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/symbol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]symbol.Symbol{
+		"tBTCUSD":      {Type: symbol.Trading, Base: "BTC", Quote: "USD"},
+		"tDUSK:USD":    {Type: symbol.Trading, Base: "DUSK", Quote: "USD"},
+		"tBTC:CNHT":    {Type: symbol.Trading, Base: "BTC", Quote: "CNHT"},
+		"tBTCF0:USTF0": {Type: symbol.Trading, Base: "BTCF0", Quote: "USTF0"},
+		"fUSD":         {Type: symbol.Funding, Base: "USD"},
+		"fUST":         {Type: symbol.Funding, Base: "UST"},
+	}
+
+	for in, expected := range cases {
+		t.Run(in, func(t *testing.T) {
+			s, err := symbol.Parse(in)
+			require.Nil(t, err)
+			assert.Equal(t, expected, s)
+			assert.Equal(t, in, s.String())
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{"", "BTCUSD", "tBTCUS", "tBTC:", "t:USD", "tA:B:C", "fUS", "xBTCUSD"} {
+		_, err := symbol.Parse(in)
+		assert.True(t, errors.Is(err, common.ErrBadRequest), in)
+	}
+}
+
+func TestParsePair(t *testing.T) {
+	s, err := symbol.ParsePair("DUSK:USD")
+	require.Nil(t, err)
+	assert.Equal(t, "tDUSK:USD", s.String())
+	assert.Equal(t, "DUSK:USD", s.Pair())
+}
+
+func TestConstructors(t *testing.T) {
+	assert.Equal(t, "tETHBTC", symbol.NewTrading("eth", "btc").String())
+	assert.Equal(t, "tLUNA2:USD", symbol.NewTrading("LUNA2", "USD").String())
+	assert.Equal(t, "fBTC", symbol.NewFunding("btc").String())
+	assert.True(t, symbol.MustParse("fUSD").IsFunding())
+	assert.Panics(t, func() { symbol.MustParse("BTC") })
+}
@@ -0,0 +1,170 @@
+// Package tradestats computes rolling statistics such as VWAP and buy/sell
+// volume imbalance from the public trade stream.
+package tradestats
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+)
+
+// Stats summarizes the trades of a symbol within a window
+type Stats struct {
+	Symbol     string
+	Window     time.Duration
+	Count      int
+	Volume     float64
+	BuyVolume  float64
+	SellVolume float64
+	// VWAP is the volume weighted average price, zero without trades
+	VWAP float64
+	// Imbalance is (buy - sell) / (buy + sell) volume, between -1 and 1
+	Imbalance float64
+}
+
+type series struct {
+	trades []*trade.Trade
+	ids    map[int64]struct{}
+}
+
+// Tracker keeps the trades of the configured windows received from the
+// websocket client. Windows are measured back from the latest trade of each
+// symbol, so replaying recorded trades gives the same results as live ones.
+// Trades have to be passed to Handle, e.g.:
+//
+//	for ev := range client.Listen() {
+//		tracker.Handle(ev)
+//	}
+type Tracker struct {
+	mu      sync.Mutex
+	windows []time.Duration
+	longest time.Duration
+	series  map[string]*series
+}
+
+// NewTracker returns a tracker computing statistics over the given windows
+func NewTracker(windows ...time.Duration) (*Tracker, error) {
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("%w: at least one window is required", common.ErrBadRequest)
+	}
+
+	t := &Tracker{series: make(map[string]*series)}
+	for _, w := range windows {
+		if w <= 0 {
+			return nil, fmt.Errorf("%w: window must be positive, got %s", common.ErrBadRequest, w)
+		}
+		if w > t.longest {
+			t.longest = w
+		}
+		t.windows = append(t.windows, w)
+	}
+	sort.Slice(t.windows, func(i, j int) bool { return t.windows[i] < t.windows[j] })
+
+	return t, nil
+}
+
+// Handle adds trade and trade snapshot events, other events are ignored.
+func (t *Tracker) Handle(ev interface{}) {
+	switch e := ev.(type) {
+	case *trade.Trade:
+		t.Add(e)
+	case *trade.Snapshot:
+		for _, tr := range e.Snapshot {
+			t.Add(tr)
+		}
+	}
+}
+
+// Add records a trade. Funding trades and trades seen before are ignored.
+func (t *Tracker) Add(tr *trade.Trade) {
+	if tr == nil || tr.Price == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.series[tr.Pair]
+	if !ok {
+		s = &series{ids: make(map[int64]struct{})}
+		t.series[tr.Pair] = s
+	}
+	if _, ok := s.ids[tr.ID]; ok {
+		return
+	}
+	s.ids[tr.ID] = struct{}{}
+
+	// keep trades ordered by time, snapshots arrive newest first
+	i := sort.Search(len(s.trades), func(i int) bool { return s.trades[i].MTS > tr.MTS })
+	s.trades = append(s.trades, nil)
+	copy(s.trades[i+1:], s.trades[i:])
+	s.trades[i] = tr
+
+	cutoff := s.trades[len(s.trades)-1].MTS - t.longest.Milliseconds()
+	n := 0
+	for n < len(s.trades) && s.trades[n].MTS <= cutoff {
+		delete(s.ids, s.trades[n].ID)
+		n++
+	}
+	s.trades = s.trades[n:]
+}
+
+// Stats returns the statistics of the symbol over the window, which has to be
+// one of the windows the tracker was created with.
+func (t *Tracker) Stats(symbol string, window time.Duration) (Stats, error) {
+	if i := sort.Search(len(t.windows), func(i int) bool { return t.windows[i] >= window }); i == len(t.windows) || t.windows[i] != window {
+		return Stats{}, fmt.Errorf("%w: window %s is not tracked", common.ErrBadRequest, window)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st := Stats{Symbol: symbol, Window: window}
+	s, ok := t.series[symbol]
+	if !ok || len(s.trades) == 0 {
+		return st, nil
+	}
+
+	var notional float64
+	cutoff := s.trades[len(s.trades)-1].MTS - window.Milliseconds()
+	for i := len(s.trades) - 1; i >= 0 && s.trades[i].MTS > cutoff; i-- {
+		tr := s.trades[i]
+		st.Count++
+		if tr.Amount > 0 {
+			st.BuyVolume += tr.Amount
+		} else {
+			st.SellVolume -= tr.Amount
+		}
+		notional += tr.Price * abs(tr.Amount)
+	}
+
+	st.Volume = st.BuyVolume + st.SellVolume
+	if st.Volume > 0 {
+		st.VWAP = notional / st.Volume
+		st.Imbalance = (st.BuyVolume - st.SellVolume) / st.Volume
+	}
+
+	return st, nil
+}
+
+// All returns the statistics of the symbol for every tracked window, from the
+// shortest to the longest.
+func (t *Tracker) All(symbol string) []Stats {
+	out := make([]Stats, 0, len(t.windows))
+	for _, w := range t.windows {
+		s, _ := t.Stats(symbol, w)
+		out = append(out, s)
+	}
+	return out
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,91 @@
+package tradestats_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/tradestats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tr(id, mts int64, amount, price float64) *trade.Trade {
+	return &trade.Trade{Pair: "tBTCUSD", ID: id, MTS: mts, Amount: amount, Price: price}
+}
+
+func TestStats(t *testing.T) {
+	tracker, err := tradestats.NewTracker(time.Minute, time.Second)
+	require.Nil(t, err)
+
+	tracker.Handle(&trade.Snapshot{Snapshot: []*trade.Trade{
+		tr(3, 60000, -1, 110),
+		tr(2, 59500, 2, 100),
+		tr(1, 1000, 1, 90),
+	}})
+	// duplicate and funding trades are ignored
+	tracker.Handle(tr(2, 59500, 2, 100))
+	tracker.Handle(&trade.Trade{Pair: "fUSD", ID: 4, MTS: 60000, Amount: 10, Rate: 0.0002, Period: 2})
+
+	s, err := tracker.Stats("tBTCUSD", time.Second)
+	require.Nil(t, err)
+	assert.Equal(t, 2, s.Count)
+	assert.Equal(t, 3.0, s.Volume)
+	assert.Equal(t, 2.0, s.BuyVolume)
+	assert.Equal(t, 1.0, s.SellVolume)
+	assert.InDelta(t, 310.0/3, s.VWAP, 1e-9)
+	assert.InDelta(t, 1.0/3, s.Imbalance, 1e-9)
+
+	s, err = tracker.Stats("tBTCUSD", time.Minute)
+	require.Nil(t, err)
+	assert.Equal(t, 3, s.Count)
+	assert.InDelta(t, 400.0/4, s.VWAP, 1e-9)
+
+	// the oldest trade falls out of the longest window
+	tracker.Add(tr(5, 61000, 1, 100))
+	all := tracker.All("tBTCUSD")
+	require.Len(t, all, 2)
+	assert.Equal(t, time.Second, all[0].Window)
+	assert.Equal(t, 1, all[0].Count)
+	assert.Equal(t, 3, all[1].Count)
+
+	_, err = tracker.Stats("tBTCUSD", time.Hour)
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	s, err = tracker.Stats("tETHUSD", time.Minute)
+	require.Nil(t, err)
+	assert.Equal(t, 0, s.Count)
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	tracker, err := tradestats.NewTracker(time.Minute)
+	require.Nil(t, err)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				tracker.Add(tr(int64(g*100+i), int64(i*100), 1, 100))
+				_, _ = tracker.Stats("tBTCUSD", time.Minute)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	s, err := tracker.Stats("tBTCUSD", time.Minute)
+	require.Nil(t, err)
+	assert.Equal(t, 400, s.Count)
+	assert.Equal(t, 100.0, s.VWAP)
+}
+
+func TestNewTrackerInvalid(t *testing.T) {
+	_, err := tradestats.NewTracker()
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+	_, err = tradestats.NewTracker(0)
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
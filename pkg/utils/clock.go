@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers to time-dependent components,
+// so that they can be run in tests and simulations with a ManualClock
+// instead of the time of the system.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	Sleep(d time.Duration)
+}
+
+// Timer delivers the time on C once it expires, like time.Timer
+type Timer interface {
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, it returns false if the timer
+	// already expired or was stopped
+	Stop() bool
+}
+
+// SystemClock is the clock of the system
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t systemTimer) Stop() bool {
+	return t.t.Stop()
+}
+
+// ManualClock is a clock which only moves when it is advanced. Timers and
+// sleeps expire once the clock is advanced past their deadline, which makes
+// time-dependent behavior deterministic.
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+// NewManualClock returns a clock set to now
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d and fires the expired timers in the
+// order of their deadlines
+func (c *ManualClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to t, which must not be before the current time, and
+// fires the expired timers in the order of their deadlines
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	if t.After(c.now) {
+		c.now = t
+	}
+	var due, pending []*manualTimer
+	for _, mt := range c.timers {
+		if !mt.at.After(c.now) {
+			due = append(due, mt)
+		} else {
+			pending = append(pending, mt)
+		}
+	}
+	c.timers = pending
+	now := c.now
+	c.mu.Unlock()
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+	for _, mt := range due {
+		mt.ch <- now
+	}
+}
+
+// Timers returns the number of pending timers, including sleeps. Tests can
+// use it to wait until a goroutine is waiting on the clock.
+func (c *ManualClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+func (c *ManualClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	mt := &manualTimer{clock: c, at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		now := c.now
+		c.mu.Unlock()
+		mt.ch <- now
+		return mt
+	}
+	c.timers = append(c.timers, mt)
+	c.mu.Unlock()
+	return mt
+}
+
+func (c *ManualClock) Sleep(d time.Duration) {
+	<-c.NewTimer(d).C()
+}
+
+type manualTimer struct {
+	clock *ManualClock
+	at    time.Time
+	ch    chan time.Time
+}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, mt := range t.clock.timers {
+		if mt == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManualClock(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	c := NewManualClock(now)
+	assert.Equal(t, now, c.Now())
+
+	late := c.NewTimer(2 * time.Second)
+	early := c.NewTimer(time.Second)
+	stopped := c.NewTimer(time.Second)
+	assert.True(t, stopped.Stop())
+	assert.False(t, stopped.Stop())
+	assert.Equal(t, 2, c.Timers())
+
+	c.Advance(999 * time.Millisecond)
+	select {
+	case <-early.C():
+		t.Fatal("timer fired early")
+	default:
+	}
+
+	c.Advance(time.Second)
+	assert.Equal(t, now.Add(1999*time.Millisecond), <-early.C())
+	assert.Equal(t, 1, c.Timers())
+
+	// the clock does not move backwards
+	c.Set(now)
+	assert.Equal(t, now.Add(1999*time.Millisecond), c.Now())
+
+	done := make(chan struct{})
+	go func() {
+		c.Sleep(time.Second)
+		close(done)
+	}()
+	for c.Timers() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	c.Advance(time.Second)
+	<-done
+	<-late.C()
+	assert.False(t, late.Stop())
+	assert.Equal(t, 0, c.Timers())
+}
+
+func TestEpochNonceGeneratorWithClock(t *testing.T) {
+	g := NewEpochNonceGeneratorWithClock(NewManualClock(time.Unix(1600000000, 0)))
+	assert.Equal(t, "1600000000000001", g.GetNonce())
+	assert.Equal(t, "1600000000000002", g.GetNonce())
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// MaintenanceGate holds back write requests while the platform is in
+// maintenance according to its Mode. The zero value allows all requests.
+// Mode may be set directly before the gate is in use, afterwards use SetMode.
+type MaintenanceGate struct {
+	Mode common.MaintenanceMode
+
+	mu     sync.Mutex
+	active bool
+	resume chan struct{} // closed once the maintenance ended
+}
+
+// SetMode changes the mode of the gate
+func (g *MaintenanceGate) SetMode(mode common.MaintenanceMode) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.Mode = mode
+}
+
+// CurrentMode returns the mode of the gate
+func (g *MaintenanceGate) CurrentMode() common.MaintenanceMode {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.Mode
+}
+
+// Start marks the platform in maintenance
+func (g *MaintenanceGate) Start() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.active {
+		g.active = true
+		g.resume = make(chan struct{})
+	}
+}
+
+// End marks the platform operative and releases queued requests
+func (g *MaintenanceGate) End() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.active {
+		g.active = false
+		close(g.resume)
+	}
+}
+
+// Active reports whether the platform is in maintenance
+func (g *MaintenanceGate) Active() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.active
+}
+
+// Wait returns once a write request may be sent. During maintenance it fails
+// with common.ErrMaintenance if the mode rejects writes or ctx is done before
+// the maintenance ended.
+func (g *MaintenanceGate) Wait(ctx context.Context) error {
+	g.mu.Lock()
+	active, resume, mode := g.active, g.resume, g.Mode
+	g.mu.Unlock()
+	if !active || mode == common.MaintenanceAllow {
+		return nil
+	}
+	if mode == common.MaintenanceReject {
+		return fmt.Errorf("%w: write requests are rejected", common.ErrMaintenance)
+	}
+	select {
+	case <-resume:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %s", common.ErrMaintenance, ctx.Err())
+	}
+}
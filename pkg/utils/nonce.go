@@ -1,9 +1,15 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/store"
 )
 
 // v2 types
@@ -26,9 +32,138 @@ func (u *EpochNonceGenerator) GetNonce() string {
 }
 
 func NewEpochNonceGenerator() *EpochNonceGenerator {
+	return NewEpochNonceGeneratorWithClock(SystemClock)
+}
+
+// NewEpochNonceGeneratorWithClock returns a generator starting at the current
+// time of the clock
+func NewEpochNonceGeneratorWithClock(c Clock) *EpochNonceGenerator {
 	return &EpochNonceGenerator{
-		nonce: uint64(time.Now().Unix()) * 1000000,
+		nonce: uint64(c.Now().Unix()) * 1000000,
+	}
+}
+
+// DefaultNonceReservation is the number of nonces reserved at once by a
+// PersistentNonceGenerator
+const DefaultNonceReservation = 1000
+
+// PersistentNonceGenerator hands out nonces which keep increasing across
+// restarts. Nonces are reserved in blocks whose upper bound is persisted
+// before any nonce of the block is used, so a restarted process continues
+// above everything handed out before, even if the clock went backwards.
+// Instances sharing an api key should share the store as well, so that a
+// restarted instance also continues above the reservations of the others.
+// Concurrent reservations are only safe with a store implementing
+// store.Swapper atomically across the instances, e.g. store.Memory or
+// store.File within one process; with any other store run a single instance
+// per key at a time.
+type PersistentNonceGenerator struct {
+	mu       sync.Mutex
+	store    store.Store
+	key      string
+	clock    Clock
+	block    uint64
+	next     uint64
+	reserved uint64 // first nonce which is not reserved
+	onError  func(error)
+}
+
+// NewPersistentNonceGenerator returns a generator persisting its
+// reservations under key in s. It starts at the current time of the clock,
+// like the EpochNonceGenerator, or above the persisted reservation if that
+// is higher. utils.SystemClock is used if clock is nil.
+func NewPersistentNonceGenerator(s store.Store, key string, clock Clock) (*PersistentNonceGenerator, error) {
+	if s == nil || key == "" {
+		return nil, fmt.Errorf("%w: store and key are required", common.ErrBadRequest)
+	}
+	if clock == nil {
+		clock = SystemClock
+	}
+	g := &PersistentNonceGenerator{
+		store: s,
+		key:   key,
+		clock: clock,
+		block: DefaultNonceReservation,
+	}
+	if err := g.reserve(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// OnError registers fn to be called when a reservation cannot be persisted.
+// Nonces keep increasing within the process in that case, but may be reused
+// after a restart.
+func (g *PersistentNonceGenerator) OnError(fn func(error)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.onError = fn
+}
+
+func (g *PersistentNonceGenerator) GetNonce() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.next >= g.reserved {
+		if err := g.reserve(); err != nil {
+			if g.onError != nil {
+				g.onError(err)
+			}
+			g.reserved = g.next + 1
+		}
+	}
+	n := g.next
+	g.next++
+	return strconv.FormatUint(n, 10)
+}
+
+// nonceReserveAttempts bounds the retries of a reservation which conflicted
+// with the one of another instance
+const nonceReserveAttempts = 10
+
+// reserve persists the next block of nonces, starting at the highest of the
+// current nonce, the time of the clock and the persisted reservation. Stores
+// implementing store.Swapper replace the reservation only if no other
+// instance changed it in the meantime, otherwise it is read again.
+func (g *PersistentNonceGenerator) reserve() error {
+	for attempt := 0; attempt < nonceReserveAttempts; attempt++ {
+		next := g.next
+		if now := uint64(g.clock.Now().Unix()) * 1000000; now > next {
+			next = now
+		}
+		b, err := g.store.Get(g.key)
+		switch {
+		case errors.Is(err, common.ErrNotFound):
+			b = nil
+		case err != nil:
+			return err
+		default:
+			persisted, err := strconv.ParseUint(string(b), 10, 64)
+			if err != nil {
+				return fmt.Errorf("parsing persisted nonce %q: %w", b, err)
+			}
+			if persisted > next {
+				next = persisted
+			}
+		}
+
+		reserved := next + g.block
+		value := []byte(strconv.FormatUint(reserved, 10))
+		if sw, ok := g.store.(store.Swapper); ok {
+			swapped, err := sw.CompareAndSwap(g.key, b, value)
+			if err != nil {
+				return err
+			}
+			if !swapped {
+				continue
+			}
+		} else if err := g.store.Put(g.key, value); err != nil {
+			return err
+		}
+		g.next, g.reserved = next, reserved
+		return nil
 	}
+	return fmt.Errorf("reserving nonces: %d attempts conflicted with other instances", nonceReserveAttempts)
 }
 
 // v1 support
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingStore struct {
+	store.Store
+	fail bool
+}
+
+func (s *failingStore) Put(key string, value []byte) error {
+	if s.fail {
+		return errors.New("disk full")
+	}
+	return s.Store.Put(key, value)
+}
+
+func TestPersistentNonceGenerator(t *testing.T) {
+	_, err := NewPersistentNonceGenerator(nil, "nonce", nil)
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	s := store.NewMemory()
+	clock := NewManualClock(time.Unix(1600000000, 0))
+	g, err := NewPersistentNonceGenerator(s, "nonce", clock)
+	require.Nil(t, err)
+	assert.Equal(t, "1600000000000000", g.GetNonce())
+	assert.Equal(t, "1600000000000001", g.GetNonce())
+
+	// a restart with the clock set back continues above the reservation
+	restarted, err := NewPersistentNonceGenerator(s, "nonce", NewManualClock(time.Unix(1500000000, 0)))
+	require.Nil(t, err)
+	assert.Equal(t, "1600000000001000", restarted.GetNonce())
+
+	// new blocks start above the reservations of other instances
+	for i := 2; i < DefaultNonceReservation; i++ {
+		g.GetNonce()
+	}
+	assert.Equal(t, "1600000000002000", g.GetNonce())
+	v, err := s.Get("nonce")
+	require.Nil(t, err)
+	assert.Equal(t, "1600000000003000", string(v))
+}
+
+// racingStore lets another instance reserve a block right before the first
+// swap of the generator
+type racingStore struct {
+	*store.Memory
+	raced bool
+}
+
+func (s *racingStore) CompareAndSwap(key string, old, value []byte) (bool, error) {
+	if !s.raced {
+		s.raced = true
+		if err := s.Memory.Put(key, []byte("1700000000000000")); err != nil {
+			return false, err
+		}
+	}
+	return s.Memory.CompareAndSwap(key, old, value)
+}
+
+func TestPersistentNonceGeneratorConflict(t *testing.T) {
+	s := &racingStore{Memory: store.NewMemory()}
+	g, err := NewPersistentNonceGenerator(s, "nonce", NewManualClock(time.Unix(1600000000, 0)))
+	require.Nil(t, err)
+	// the reservation is read again and continues above the other instance
+	assert.Equal(t, "1700000000000000", g.GetNonce())
+	v, err := s.Get("nonce")
+	require.Nil(t, err)
+	assert.Equal(t, "1700000000001000", string(v))
+}
+
+func TestPersistentNonceGeneratorErrors(t *testing.T) {
+	s := &failingStore{Store: store.NewMemory()}
+	g, err := NewPersistentNonceGenerator(s, "nonce", NewManualClock(time.Unix(1600000000, 0)))
+	require.Nil(t, err)
+	for i := 1; i < DefaultNonceReservation; i++ {
+		g.GetNonce()
+	}
+
+	var errs []error
+	g.OnError(func(err error) { errs = append(errs, err) })
+	s.fail = true
+	assert.Equal(t, "1600000000000999", g.GetNonce())
+	assert.Equal(t, "1600000000001000", g.GetNonce())
+	assert.Equal(t, "1600000000001001", g.GetNonce())
+	assert.Len(t, errs, 2)
+
+	s.fail = false
+	assert.Equal(t, "1600000000001002", g.GetNonce())
+	assert.Len(t, errs, 2)
+	v, err := s.Get("nonce")
+	require.Nil(t, err)
+	assert.Equal(t, "1600000000002002", string(v))
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/v2/websocket"
+)
+
+func TestSeparateAuthEndpoint(t *testing.T) {
+	public := newTestAsync()
+	auth := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(public), nonce).
+		AuthAsyncFactory(newTestAsyncFactory(auth)).
+		Credentials("apiKeyABC", "apiSecretXYZ")
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	if ws.ConnectionCount() != 2 {
+		t.Fatalf("expected a public and an auth connection, got %d", ws.ConnectionCount())
+	}
+
+	// only the auth endpoint authenticates
+	public.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+	auth.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.waitForMessage(0); err != nil {
+		t.Fatal(err)
+	}
+	if req, ok := auth.Sent[0].(*websocket.SubscriptionRequest); !ok || req.Event != "auth" {
+		t.Fatalf("expected auth request on auth endpoint, got %#v", auth.Sent[0])
+	}
+	if public.SentCount() != 0 {
+		t.Fatalf("expected no messages on public endpoint, got %d", public.SentCount())
+	}
+
+	auth.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce1","auth_id":"valid-auth-guid"}`)
+	if _, err := listener.nextAuthEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	// public subscriptions stay off the auth endpoint
+	if _, err := ws.SubscribeTicker(context.Background(), "tBTCUSD"); err != nil {
+		t.Fatal(err)
+	}
+	if err := public.waitForMessage(0); err != nil {
+		t.Fatal(err)
+	}
+	if auth.SentCount() != 1 {
+		t.Fatalf("expected only the auth request on auth endpoint, got %d", auth.SentCount())
+	}
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/websocket"
+)
+
+func TestOrderbookFastForward(t *testing.T) {
+	ob := &websocket.Orderbook{}
+	ob.SetWithSnapshot(&book.Snapshot{Snapshot: []*book.Book{
+		level(common.Bid, 99, 1, 1),
+		level(common.Bid, 95, 1, 1),
+		level(common.Bid, 90, 1, 1),
+		level(common.Ask, 101, 1, 1),
+		level(common.Ask, 110, 1, 1),
+	}})
+
+	ob.FastForward(&book.Snapshot{Snapshot: []*book.Book{
+		level(common.Bid, 98, 2, 1),
+		level(common.Bid, 96, 2, 1),
+		level(common.Ask, 102, 2, 1),
+	}})
+
+	bids := ob.Bids()
+	if len(bids) != 4 || bids[0].Price != 98 || bids[1].Price != 96 || bids[2].Price != 95 || bids[3].Price != 90 {
+		t.Fatalf("unexpected bids after fast forward: %#v", bids)
+	}
+	asks := ob.Asks()
+	if len(asks) != 2 || asks[0].Price != 102 || asks[1].Price != 110 {
+		t.Fatalf("unexpected asks after fast forward: %#v", asks)
+	}
+	if b, a := ob.Volume(); !near(b, 6) || !near(a, 3) {
+		t.Fatalf("expected volume 6/3, got %f/%f", b, a)
+	}
+}
+
+func TestOrderbookRestore(t *testing.T) {
+	store := websocket.FileBookStore{Dir: t.TempDir()}
+
+	p := websocket.NewDefaultParameters()
+	p.ManageOrderbook = true
+	ws := websocket.NewWithParamsAsyncFactory(p, newTestAsyncFactory(newTestAsync()))
+
+	ok, err := ws.RestoreOrderbook("tBTCUSD", store, nil)
+	if err != nil || ok {
+		t.Fatalf("expected nothing to restore, got %t %v", ok, err)
+	}
+
+	saved := &websocket.Orderbook{}
+	saved.SetWithSnapshot(&book.Snapshot{Snapshot: []*book.Book{
+		level(common.Bid, 99, 1, 1),
+		level(common.Ask, 101, 1, 1),
+	}})
+	if err := store.SaveBook("tBTCUSD", saved.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+
+	fetch := func(symbol string) (*book.Snapshot, error) {
+		return &book.Snapshot{Snapshot: []*book.Book{level(common.Bid, 100, 3, 1)}}, nil
+	}
+	ok, err = ws.RestoreOrderbook("tBTCUSD", store, fetch)
+	if err != nil || !ok {
+		t.Fatalf("expected restored book, got %t %v", ok, err)
+	}
+
+	ob, err := ws.GetOrderbook("tBTCUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ob.BestBid(); b.Price != 100 {
+		t.Fatalf("expected fast forwarded best bid 100, got %f", b.Price)
+	}
+	if a, _ := ob.BestAsk(); a.Price != 101 {
+		t.Fatalf("expected restored best ask 101, got %f", a.Price)
+	}
+
+	dir := websocket.FileBookStore{Dir: t.TempDir()}
+	if err := ws.SaveOrderbooks(dir); err != nil {
+		t.Fatal(err)
+	}
+	s, err := dir.LoadBook("tBTCUSD")
+	if err != nil || s == nil || len(s.Snapshot) != 3 {
+		t.Fatalf("expected saved book with 3 levels, got %#v %v", s, err)
+	}
+}
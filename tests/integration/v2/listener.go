@@ -24,6 +24,8 @@ type listener struct {
 	ticks                chan *ticker.Ticker
 	subscriptionEvents   chan *websocket.SubscribeEvent
 	unsubscriptionEvents chan *websocket.UnsubscribeEvent
+	reconnectEvents      chan *websocket.ReconnectEvent
+	lifecycleEvents      chan interface{}
 	walletUpdates        chan *wallet.Update
 	balanceUpdates       chan *balanceinfo.Update
 	walletSnapshot       chan *wallet.Snapshot
@@ -48,6 +50,8 @@ func newListener() *listener {
 		ticks:                make(chan *ticker.Ticker, 10),
 		subscriptionEvents:   make(chan *websocket.SubscribeEvent, 10),
 		unsubscriptionEvents: make(chan *websocket.UnsubscribeEvent, 10),
+		reconnectEvents:      make(chan *websocket.ReconnectEvent, 10),
+		lifecycleEvents:      make(chan interface{}, 10),
 		walletUpdates:        make(chan *wallet.Update, 10),
 		balanceUpdates:       make(chan *balanceinfo.Update, 10),
 		walletSnapshot:       make(chan *wallet.Snapshot, 10),
@@ -94,6 +98,20 @@ func (l *listener) nextAuthEvent() (*websocket.AuthEvent, error) {
 	}
 }
 
+func (l *listener) nextLifecycleEvent() (interface{}, error) {
+	timeout := make(chan bool)
+	go func() {
+		time.Sleep(time.Second * 2)
+		close(timeout)
+	}()
+	select {
+	case ev := <-l.lifecycleEvents:
+		return ev, nil
+	case <-timeout:
+		return nil, errors.New("timed out waiting for lifecycle event")
+	}
+}
+
 func (l *listener) nextWalletUpdate() (*wallet.Update, error) {
 	timeout := make(chan bool)
 	go func() {
@@ -192,19 +210,19 @@ func (l *listener) nextTick() (*ticker.Ticker, error) {
 	}
 }
 
-// func (l *listener) nextNotification() (*notification.Notification, error) {
-// 	timeout := make(chan bool)
-// 	go func() {
-// 		time.Sleep(time.Second * 2)
-// 		close(timeout)
-// 	}()
-// 	select {
-// 	case ev := <-l.notifications:
-// 		return ev, nil
-// 	case <-timeout:
-// 		return nil, errors.New("timed out waiting for Notification")
-// 	}
-// }
+func (l *listener) nextNotification() (*notification.Notification, error) {
+	timeout := make(chan bool)
+	go func() {
+		time.Sleep(time.Second * 2)
+		close(timeout)
+	}()
+	select {
+	case ev := <-l.notifications:
+		return ev, nil
+	case <-timeout:
+		return nil, errors.New("timed out waiting for Notification")
+	}
+}
 
 // func (l *listener) nextTradeExecution() (*tradeexecution.TradeExecution, error) {
 // 	timeout := make(chan bool)
@@ -333,6 +351,20 @@ func (l *listener) nextTick() (*ticker.Ticker, error) {
 // }
 
 // strongly types messages and places them into a channel
+func (l *listener) nextReconnectEvent() (*websocket.ReconnectEvent, error) {
+	timeout := make(chan bool)
+	go func() {
+		time.Sleep(time.Second * 2)
+		close(timeout)
+	}()
+	select {
+	case ev := <-l.reconnectEvents:
+		return ev, nil
+	case <-timeout:
+		return nil, errors.New("timed out waiting for ReconnectEvent")
+	}
+}
+
 func (l *listener) run(ch <-chan interface{}) {
 	go func() {
 		// nolint:megacheck
@@ -357,6 +389,13 @@ func (l *listener) run(ch <-chan interface{}) {
 					l.unsubscriptionEvents <- msg.(*websocket.UnsubscribeEvent)
 				case *websocket.AuthEvent:
 					l.authEvents <- msg.(*websocket.AuthEvent)
+				case *websocket.ReconnectEvent:
+					l.reconnectEvents <- msg.(*websocket.ReconnectEvent)
+				case *websocket.ConnectedEvent, *websocket.DisconnectedEvent,
+					*websocket.AuthSucceededEvent, *websocket.AuthFailedEvent,
+					*websocket.MaintenanceStartedEvent, *websocket.MaintenanceEndedEvent,
+					*websocket.KeyRotatedEvent, *websocket.CancelOnDisconnectArmedEvent:
+					l.lifecycleEvents <- msg
 				case *wallet.Update:
 					l.walletUpdates <- msg.(*wallet.Update)
 				case *balanceinfo.Update:
@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/websocket"
+)
+
+func TestAwaitResponses(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce).Credentials("apiKeyABC", "apiSecretXYZ")
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce1","auth_id":"valid-auth-guid","caps":{}}`)
+	if _, err := listener.nextAuthEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	// subscription ack
+	go func() {
+		if err := async.waitForMessage(1); err != nil {
+			return
+		}
+		async.Publish(`{"event":"subscribed","channel":"ticker","chanId":5,"symbol":"tBTCUSD","subId":"sub1","pair":"BTCUSD"}`)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	sub, err := ws.SubscribeAndWait(ctx, &websocket.SubscriptionRequest{SubID: "sub1", Event: websocket.EventSubscribe, Channel: websocket.ChanTicker, Symbol: "tBTCUSD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, int64(5), sub.ChanID)
+
+	// rejected subscription
+	go func() {
+		if err := async.waitForMessage(2); err != nil {
+			return
+		}
+		async.Publish(`{"event":"error","msg":"subscribe: dup","code":10301,"subId":"sub2","channel":"ticker","symbol":"tBTCUSD"}`)
+	}()
+	if _, err := ws.SubscribeAndWait(ctx, &websocket.SubscriptionRequest{SubID: "sub2", Event: websocket.EventSubscribe, Channel: websocket.ChanTicker, Symbol: "tBTCUSD"}); err == nil {
+		t.Fatal("expected rejected subscription")
+	}
+
+	// order ack, notifications of other orders are not matched
+	go func() {
+		if err := async.waitForMessage(3); err != nil {
+			return
+		}
+		async.Publish(`[0,"n",[null,"on-req",null,null,[1234566,null,122,"tBTCUSD",null,null,1,1,"LIMIT",null,null,null,null,null,null,null,900,null,null,null,null,null,null,0,null,null,null,null,null,null,null,null],null,"SUCCESS","Submitting limit buy order for 1.0 BTC."]]`)
+		async.Publish(`[0,"n",[null,"on-req",null,null,[1234567,null,123,"tBTCUSD",null,null,1,1,"LIMIT",null,null,null,null,null,null,null,900,null,null,null,null,null,null,0,null,null,null,null,null,null,null,null],null,"SUCCESS","Submitting limit buy order for 1.0 BTC."]]`)
+	}()
+	n, err := ws.SubmitOrderAndWait(ctx, &order.NewRequest{CID: 123, Symbol: "tBTCUSD", Amount: 1, Price: 900, Type: "LIMIT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, int64(1234567), n.NotifyInfo.(order.New).ID)
+
+	// rejected cancel
+	go func() {
+		if err := async.waitForMessage(4); err != nil {
+			return
+		}
+		async.Publish(`[0,"n",[null,"oc-req",null,null,[1234567,null,123,"tBTCUSD",null,null,1,1,"LIMIT",null,null,null,null,null,null,null,900,null,null,null,null,null,null,0,null,null,null,null,null,null,null,null],null,"ERROR","Order not found."]]`)
+	}()
+	n, err = ws.SubmitCancelAndWait(ctx, &order.CancelRequest{ID: 1234567})
+	var rejected *notification.Error
+	if !errors.As(err, &rejected) || n == nil || n.Text != "Order not found." {
+		t.Fatalf("expected rejected cancel, got %v", err)
+	}
+
+	// cancels need an id to be matched
+	if _, err := ws.SubmitCancelAndWait(ctx, &order.CancelRequest{}); !errors.Is(err, common.ErrBadRequest) {
+		t.Fatalf("expected bad request, got %v", err)
+	}
+
+	// orders without client id are assigned one to be matched
+	onr := &order.NewRequest{Symbol: "tBTCUSD", Amount: 1, Price: 900, Type: "LIMIT"}
+	go func() {
+		if err := async.waitForMessage(5); err != nil {
+			return
+		}
+		async.Publish(fmt.Sprintf(`[0,"n",[null,"on-req",null,null,[1234568,null,%d,"tBTCUSD",null,null,1,1,"LIMIT",null,null,null,null,null,null,null,900,null,null,null,null,null,null,0,null,null,null,null,null,null,null,null],null,"SUCCESS","Submitting limit buy order for 1.0 BTC."]]`, onr.CID))
+	}()
+	n, err = ws.SubmitOrderAndWait(ctx, onr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if onr.CID == 0 {
+		t.Fatal("expected a client id to be assigned")
+	}
+	assert(t, int64(1234568), n.NotifyInfo.(order.New).ID)
+
+	// unanswered requests time out with the request
+	short, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req := &order.UpdateRequest{ID: 1234567, Price: 901}
+	_, err = ws.SubmitUpdateOrderAndWait(short, req)
+	var timeout *websocket.TimeoutError
+	if !errors.As(err, &timeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	if timeout.Request != req {
+		t.Fatalf("expected the update request, got %#v", timeout.Request)
+	}
+}
@@ -0,0 +1,90 @@
+//go:build go1.23
+
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/websocket"
+)
+
+func TestTradesSeq(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce)
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	trades := make(chan *trade.Trade)
+	errs := make(chan error, 1)
+	go func() {
+		for tr, err := range ws.TradesSeq(ctx, "tBTCUSD") {
+			if err != nil {
+				errs <- err
+				return
+			}
+			trades <- tr
+			if tr.ID == 3 {
+				return
+			}
+		}
+	}()
+
+	if err := async.waitForMessage(0); err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`{"event":"subscribed","channel":"trades","chanId":7,"symbol":"tBTCUSD","subId":"nonce1","pair":"BTCUSD"}`)
+	if _, err := listener.nextSubscriptionEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	// other messages still reach Listen while the loop runs
+	tickerID, err := ws.SubscribeTicker(ctx, "tETHUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`{"event":"subscribed","channel":"ticker","chanId":8,"symbol":"tETHUSD","subId":"` + tickerID + `","pair":"ETHUSD"}`)
+	if _, err := listener.nextSubscriptionEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	async.Publish(`[7,[[2,1568123933000,0.1,10000],[1,1568123932000,-0.2,10001]]]`)
+	async.Publish(`[8,[14957,68.17,14958,55.29,-659,-0.0422,14971,53723.08,16494,14454]]`)
+	async.Publish(`[7,"te",[3,1568123934000,0.3,10002]]`)
+	for _, id := range []int64{2, 1, 3} {
+		select {
+		case tr := <-trades:
+			assert(t, id, tr.ID)
+		case err := <-errs:
+			t.Fatal(err)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("did not receive trade %d", id)
+		}
+	}
+	tick, err := listener.nextTick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, "tETHUSD", tick.Symbol)
+
+	// ending the loop unsubscribes
+	if err := async.waitForMessage(2); err != nil {
+		t.Fatal(err)
+	}
+}
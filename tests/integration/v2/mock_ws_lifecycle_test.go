@@ -0,0 +1,355 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	bfxauth "github.com/bitfinexcom/bitfinex-api-go/pkg/auth"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/wallet"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/websocket"
+	gorilla "github.com/gorilla/websocket"
+)
+
+func TestLifecycleEvents(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce).Credentials("apiKeyABC", "apiSecretXYZ")
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	next := func() interface{} {
+		ev, err := listener.nextLifecycleEvent()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ev
+	}
+
+	async.Publish(`{"event":"info","version":2,"serverId":"abc"}`)
+	connected, ok := next().(*websocket.ConnectedEvent)
+	if !ok {
+		t.Fatal("expected ConnectedEvent")
+	}
+	assert(t, "abc", connected.ServerId)
+
+	async.Publish(`{"event":"auth","status":"FAILED","chanId":0,"msg":"apikey: invalid","code":10100}`)
+	failed, ok := next().(*websocket.AuthFailedEvent)
+	if !ok {
+		t.Fatal("expected AuthFailedEvent")
+	}
+	assert(t, 10100, failed.Code)
+	assert(t, "apikey: invalid", failed.Message)
+
+	async.Publish(`{"event":"info","code":20060,"msg":"Entering in Maintenance mode"}`)
+	if _, ok := next().(*websocket.MaintenanceStartedEvent); !ok {
+		t.Fatal("expected MaintenanceStartedEvent")
+	}
+	async.Publish(`{"event":"info","code":20061,"msg":"Maintenance ended"}`)
+	if _, ok := next().(*websocket.MaintenanceEndedEvent); !ok {
+		t.Fatal("expected MaintenanceEndedEvent")
+	}
+
+	async.Close()
+	disconnected, ok := next().(*websocket.DisconnectedEvent)
+	if !ok {
+		t.Fatal("expected DisconnectedEvent")
+	}
+	if disconnected.Err != nil {
+		t.Fatalf("expected clean close, got %s", disconnected.Err)
+	}
+}
+
+func TestMaintenanceGate(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	params := websocket.NewDefaultParameters()
+	params.Maintenance = common.MaintenanceReject
+	ws := websocket.NewWithParamsAsyncFactoryNonce(params, newTestAsyncFactory(async), nonce).Credentials("apiKeyABC", "apiSecretXYZ")
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce1","auth_id":"valid-auth-guid","caps":{}}`)
+	if _, err := listener.nextAuthEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	async.Publish(`{"event":"info","code":20060,"msg":"Entering in Maintenance mode"}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+	if !ws.InMaintenance() {
+		t.Fatal("expected maintenance")
+	}
+	err := ws.SubmitOrder(context.Background(), &order.NewRequest{CID: 1, Symbol: "tBTCUSD", Amount: 1, Price: 1})
+	if !errors.Is(err, common.ErrMaintenance) {
+		t.Fatalf("expected ErrMaintenance, got %v", err)
+	}
+
+	async.Publish(`{"event":"info","code":20061,"msg":"Maintenance ended"}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.SubmitOrder(context.Background(), &order.NewRequest{CID: 2, Symbol: "tBTCUSD", Amount: 1, Price: 1}); err != nil {
+		t.Fatal(err)
+	}
+	// auth request, order
+	if err := async.waitForMessage(1); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, int64(2), async.Sent[1].(*order.NewRequest).CID)
+}
+
+// sequenceAsyncFactory hands out the given transports in order
+type sequenceAsyncFactory struct {
+	asyncs []*TestAsync
+}
+
+func (f *sequenceAsyncFactory) Create() websocket.Asynchronous {
+	a := f.asyncs[0]
+	f.asyncs = f.asyncs[1:]
+	return a
+}
+
+func TestRotateCredentials(t *testing.T) {
+	first, second := newTestAsync(), newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(&sequenceAsyncFactory{asyncs: []*TestAsync{first, second}}, nonce).Credentials("key1", "secret1")
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	first.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+	first.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce1","auth_id":"valid-auth-guid","caps":{}}`)
+	if _, err := listener.nextAuthEvent(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ws.SubscribeTrades(context.Background(), "tBTCUSD"); err != nil {
+		t.Fatal(err)
+	}
+	first.Publish(`{"event":"subscribed","channel":"trades","chanId":5,"symbol":"tBTCUSD","subId":"nonce2","pair":"BTCUSD"}`)
+	if _, err := listener.nextSubscriptionEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	// polling the connection state while rotating must not race
+	polled := make(chan struct{})
+	go func() {
+		defer close(polled)
+		for i := 0; i < 100; i++ {
+			ws.IsConnected()
+		}
+	}()
+	if err := ws.RotateCredentials(context.Background(), bfxauth.StaticCredentials("key2", "secret2")); err != nil {
+		t.Fatal(err)
+	}
+	<-polled
+
+	// the new connection authenticates with the rotated key
+	second.Publish(`{"event":"info","version":2}`)
+	if err := second.waitForMessage(0); err != nil {
+		t.Fatal(err)
+	}
+	req := second.Sent[0].(*websocket.SubscriptionRequest)
+	assert(t, "auth", req.Event)
+	assert(t, "key2", req.APIKey)
+
+	second.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce3","auth_id":"valid-auth-guid","caps":{}}`)
+	var rotated *websocket.KeyRotatedEvent
+	for rotated == nil {
+		ev, err := listener.nextLifecycleEvent()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rotated, _ = ev.(*websocket.KeyRotatedEvent)
+	}
+	assert(t, "key2", rotated.APIKey)
+	assert(t, int64(1), rotated.UserID)
+
+	// and resubscribes the channels
+	if err := second.waitForMessage(1); err != nil {
+		t.Fatal(err)
+	}
+	sub := second.Sent[1].(*websocket.SubscriptionRequest)
+	assert(t, "trades", sub.Channel)
+}
+
+// generationsClient connects a client with credentials and returns a reader
+// of its events, skipping those of the connection handshake
+func generationsClient(t *testing.T, tag bool) (*TestAsync, *websocket.Client, func() interface{}) {
+	async := newTestAsync()
+	params := websocket.NewDefaultParameters()
+	params.TagPreSnapshot = tag
+	ws := websocket.NewWithParamsAsyncFactoryNonce(params, newTestAsyncFactory(async), &IncrementingNonceGenerator{}).
+		Credentials("apiKeyABC", "apiSecretXYZ")
+	events := make(chan interface{}, 10)
+	go func() {
+		for ev := range ws.Listen() {
+			events <- ev
+		}
+	}()
+	next := func() interface{} {
+		for {
+			select {
+			case ev := <-events:
+				switch ev.(type) {
+				case *websocket.InfoEvent, *websocket.AuthEvent, *websocket.ConnectedEvent:
+					continue
+				}
+				return ev
+			case <-time.After(2 * time.Second):
+				t.Fatal("timed out waiting for event")
+			}
+		}
+	}
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	return async, ws, next
+}
+
+func TestSnapshotGenerations(t *testing.T) {
+	async, ws, next := generationsClient(t, true)
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	async.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce1","auth_id":"valid-auth-guid","caps":{}}`)
+	if ev, ok := next().(*websocket.AuthSucceededEvent); !ok || ev.Generation != 1 {
+		t.Fatalf("expected first generation, got %#v", ev)
+	}
+	// updates of the first generation are passed on without a snapshot
+	async.Publish(`[0,"wu",["exchange","BTC",30,0,30,null,null,null]]`)
+	if _, ok := next().(*wallet.Update); !ok {
+		t.Fatal("expected wallet update")
+	}
+
+	// after a re-authentication updates are tagged until their snapshot
+	async.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce2","auth_id":"valid-auth-guid","caps":{}}`)
+	if ev, ok := next().(*websocket.AuthSucceededEvent); !ok || ev.Generation != 2 {
+		t.Fatalf("expected second generation, got %#v", ev)
+	}
+	async.Publish(`[0,"wu",["exchange","BTC",20,0,20,null,null,null]]`)
+	pre, ok := next().(*websocket.PreSnapshotEvent)
+	if !ok || pre.Generation != 2 {
+		t.Fatalf("expected pre-snapshot event, got %#v", pre)
+	}
+	if _, ok := pre.Event.(*wallet.Update); !ok {
+		t.Fatalf("expected wallet update, got %#v", pre.Event)
+	}
+	async.Publish(`[0,"n",[null,"on-req",null,null,[1234567,null,123,"tBTCUSD",null,null,1,1,"LIMIT",null,null,null,null,null,null,null,900,null,null,null,null,null,null,0,null,null,null,null,null,null,null,null],null,"SUCCESS","Submitting limit buy order for 1.0 BTC."]]`)
+	if _, ok := next().(*notification.Notification); !ok {
+		t.Fatal("expected notification")
+	}
+
+	// an empty snapshot ends the wait as well
+	async.Publish(`[0,"ws",[]]`)
+	async.Publish(`[0,"wu",["exchange","BTC",10,0,10,null,null,null]]`)
+	if _, ok := next().(*wallet.Update); !ok {
+		t.Fatal("expected wallet update")
+	}
+	assert(t, uint64(2), ws.Generation())
+}
+
+func TestSnapshotGenerationsUntagged(t *testing.T) {
+	async, ws, next := generationsClient(t, false)
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	async.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce1","auth_id":"valid-auth-guid","caps":{}}`)
+	next()
+	async.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce2","auth_id":"valid-auth-guid","caps":{}}`)
+	if ev, ok := next().(*websocket.AuthSucceededEvent); !ok || ev.Generation != 2 {
+		t.Fatalf("expected second generation, got %#v", ev)
+	}
+	// updates keep their type unless tagging is enabled
+	async.Publish(`[0,"wu",["exchange","BTC",20,0,20,null,null,null]]`)
+	if _, ok := next().(*wallet.Update); !ok {
+		t.Fatal("expected wallet update")
+	}
+}
+
+func TestCancelOnDisconnectRearmed(t *testing.T) {
+	first, second := newTestAsync(), newTestAsync()
+	params := websocket.NewDefaultParameters()
+	params.ReconnectInterval = 10 * time.Millisecond
+	ws := websocket.NewWithParamsAsyncFactoryNonce(params, &sequenceAsyncFactory{asyncs: []*TestAsync{first, second}}, &IncrementingNonceGenerator{}).
+		Credentials("apiKeyABC", "apiSecretXYZ").
+		CancelOnDisconnect(true).
+		AuthFilter("trading", "wallet")
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	nextArmed := func() *websocket.CancelOnDisconnectArmedEvent {
+		for {
+			ev, err := listener.nextLifecycleEvent()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if armed, ok := ev.(*websocket.CancelOnDisconnectArmedEvent); ok {
+				return armed
+			}
+		}
+	}
+
+	first.Publish(`{"event":"info","version":2}`)
+	if err := first.waitForMessage(0); err != nil {
+		t.Fatal(err)
+	}
+	req := first.Sent[0].(*websocket.SubscriptionRequest)
+	assert(t, websocket.DMSCancelOnDisconnect, req.DMS)
+	assert(t, "trading,wallet", strings.Join(req.Filter, ","))
+	first.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce1","auth_id":"valid-auth-guid","caps":{}}`)
+	armed := nextArmed()
+	assert(t, uint64(1), armed.Generation)
+
+	// the connection drops and the new one authenticates with the same settings
+	first.done <- &gorilla.CloseError{Code: gorilla.CloseAbnormalClosure}
+	second.Publish(`{"event":"info","version":2}`)
+	if err := second.waitForMessage(0); err != nil {
+		t.Fatal(err)
+	}
+	req = second.Sent[0].(*websocket.SubscriptionRequest)
+	assert(t, "auth", req.Event)
+	assert(t, websocket.DMSCancelOnDisconnect, req.DMS)
+	assert(t, "trading,wallet", strings.Join(req.Filter, ","))
+	second.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce2","auth_id":"valid-auth-guid","caps":{}}`)
+	armed = nextArmed()
+	assert(t, uint64(2), armed.Generation)
+	assert(t, "trading,wallet", strings.Join(armed.Filter, ","))
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/v2/websocket"
+)
+
+func TestPing(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce)
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		if err := async.waitForMessage(0); err != nil {
+			return
+		}
+		ping := async.Sent[0].(*websocket.PingRequest)
+		async.Publish(fmt.Sprintf(`{"event":"pong","ts":1568123933000,"cid":%d}`, ping.CID))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	st, err := ws.Ping(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, int64(1568123933000), st.ServerTime.UnixNano()/int64(time.Millisecond))
+	assert(t, 0, st.Heartbeat.Samples)
+
+	// unanswered pings time out
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := ws.Ping(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
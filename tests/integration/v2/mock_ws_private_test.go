@@ -1,10 +1,14 @@
 package tests
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
+	bfxauth "github.com/bitfinexcom/bitfinex-api-go/pkg/auth"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/balanceinfo"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/movement"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/wallet"
 	"github.com/bitfinexcom/bitfinex-api-go/v2/websocket"
 )
@@ -113,19 +117,19 @@ func TestWalletBalanceUpdates(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	assert(t, fmt.Sprint(wallet.Update{Type: "exchange", Currency: "BTC", Balance: 30, BalanceAvailable: 30}), fmt.Sprint(*wu))
+	assert(t, fmt.Sprint(wallet.Update{Type: "exchange", Currency: "BTC", Balance: 30, BalanceAvailable: 30, HasBalanceAvailable: true}), fmt.Sprint(*wu))
 	wu, _ = listener.nextWalletUpdate()
-	assert(t, fmt.Sprint(wallet.Update{Type: "exchange", Currency: "USD", Balance: 80000, BalanceAvailable: 80000}), fmt.Sprint(*wu))
+	assert(t, fmt.Sprint(wallet.Update{Type: "exchange", Currency: "USD", Balance: 80000, BalanceAvailable: 80000, HasBalanceAvailable: true}), fmt.Sprint(*wu))
 	wu, _ = listener.nextWalletUpdate()
-	assert(t, fmt.Sprint(wallet.Update{Type: "exchange", Currency: "ETH", Balance: 100, BalanceAvailable: 100}), fmt.Sprint(*wu))
+	assert(t, fmt.Sprint(wallet.Update{Type: "exchange", Currency: "ETH", Balance: 100, BalanceAvailable: 100, HasBalanceAvailable: true}), fmt.Sprint(*wu))
 	wu, _ = listener.nextWalletUpdate()
-	assert(t, fmt.Sprint(wallet.Update{Type: "margin", Currency: "BTC", Balance: 10, BalanceAvailable: 10}), fmt.Sprint(*wu))
+	assert(t, fmt.Sprint(wallet.Update{Type: "margin", Currency: "BTC", Balance: 10, BalanceAvailable: 10, HasBalanceAvailable: true}), fmt.Sprint(*wu))
 	wu, _ = listener.nextWalletUpdate()
-	assert(t, fmt.Sprint(wallet.Update{Type: "funding", Currency: "BTC", Balance: 10, BalanceAvailable: 10}), fmt.Sprint(*wu))
+	assert(t, fmt.Sprint(wallet.Update{Type: "funding", Currency: "BTC", Balance: 10, BalanceAvailable: 10, HasBalanceAvailable: true}), fmt.Sprint(*wu))
 	wu, _ = listener.nextWalletUpdate()
-	assert(t, fmt.Sprint(wallet.Update{Type: "funding", Currency: "USD", Balance: 10000, BalanceAvailable: 10000}), fmt.Sprint(*wu))
+	assert(t, fmt.Sprint(wallet.Update{Type: "funding", Currency: "USD", Balance: 10000, BalanceAvailable: 10000, HasBalanceAvailable: true}), fmt.Sprint(*wu))
 	wu, _ = listener.nextWalletUpdate()
-	assert(t, fmt.Sprint(wallet.Update{Type: "margin", Currency: "USD", Balance: 10000, BalanceAvailable: 10000}), fmt.Sprint(*wu))
+	assert(t, fmt.Sprint(wallet.Update{Type: "margin", Currency: "USD", Balance: 10000, BalanceAvailable: 10000, HasBalanceAvailable: true}), fmt.Sprint(*wu))
 	bu, err := listener.nextBalanceUpdate()
 	if err != nil {
 		t.Fatal(err)
@@ -643,3 +647,206 @@ func TestWalletBalanceUpdates(t *testing.T) {
 // 	}
 // 	fmt.Println(*authSocket)
 // }
+
+func TestAuthenticationWithToken(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce).
+		CredentialsProvider(bfxauth.StaticToken("authToken123"))
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := async.waitForMessage(0); err != nil {
+		t.Fatal(err.Error())
+	}
+	actual := *async.Sent[0].(*websocket.SubscriptionRequest)
+	assert(t, "auth", actual.Event)
+	assert(t, "authToken123", actual.AuthToken)
+	assert(t, "", actual.APIKey)
+	assert(t, "", actual.AuthSig)
+}
+
+func TestCalcAvailableBalance(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce).
+		Credentials("apiKeyABC", "apiSecretXYZ").
+		CalcAvailableBalance(true)
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce1","auth_id":"valid-auth-guid","caps":{}}`)
+	if _, err := listener.nextAuthEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the available balance of wallets received without it is requested
+	async.Publish(`[0,"ws",[["exchange","BTC",30,0,null,null,null],["exchange","USD",80000,0,75000,null,null]]]`)
+	if err := async.waitForMessage(1); err != nil {
+		t.Fatal(err)
+	}
+	cr, ok := async.Sent[1].(*wallet.CalcRequest)
+	if !ok {
+		t.Fatalf("expected calc request, got %#v", async.Sent[1])
+	}
+	assert(t, 1, len(cr.Wallets))
+	assert(t, "exchange", cr.Wallets[0].Type)
+	assert(t, "BTC", cr.Wallets[0].Currency)
+
+	// the pending wallet is not requested again
+	async.Publish(`[0,"wu",["exchange","BTC",31,0,null,null,null]]`)
+	if _, err := listener.nextWalletUpdate(); err != nil {
+		t.Fatal(err)
+	}
+
+	async.Publish(`[0,"wu",["exchange","BTC",30,0,25,null,null]]`)
+	wu, err := listener.nextWalletUpdate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, true, wu.HasBalanceAvailable)
+	assert(t, 25.0, wu.BalanceAvailable)
+	assert(t, 2, async.SentCount())
+
+	// once it arrived, it is requested again after the request interval
+	async.Publish(`[0,"wu",["exchange","BTC",32,0,null,null,null]]`)
+	if err := async.waitForMessage(2); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, 3, async.SentCount())
+}
+
+type userAccountConfig struct {
+	Name  string
+	Value interface{}
+}
+
+func TestUnknownEvents(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce).Credentials("apiKeyABC", "apiSecretXYZ")
+	events := make(chan interface{}, 10)
+	go func() {
+		for ev := range ws.Listen() {
+			events <- ev
+		}
+	}()
+	next := func() interface{} {
+		for {
+			select {
+			case ev := <-events:
+				switch ev.(type) {
+				case *websocket.InfoEvent, *websocket.AuthEvent, *websocket.ConnectedEvent,
+					*websocket.AuthSucceededEvent:
+					continue
+				}
+				return ev
+			case <-time.After(2 * time.Second):
+				t.Fatal("timed out waiting for event")
+			}
+		}
+	}
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	async.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce1","auth_id":"valid-auth-guid","caps":{}}`)
+
+	// unknown events and terms are delivered with the raw message
+	async.Publish(`{"event":"foo","bar":1}`)
+	ev, ok := next().(*websocket.UnknownEvent)
+	if !ok {
+		t.Fatal("expected unknown event")
+	}
+	assert(t, "foo", ev.Name)
+	assert(t, `{"event":"foo","bar":1}`, string(ev.Raw))
+
+	async.Publish(`[0,"uac",["dark_mode",true]]`)
+	ev, ok = next().(*websocket.UnknownEvent)
+	if !ok {
+		t.Fatal("expected unknown event")
+	}
+	assert(t, "uac", ev.Name)
+	assert(t, `[0,"uac",["dark_mode",true]]`, string(ev.Raw))
+
+	// registered decoders take over
+	ws.RegisterDecoder("uac", func(raw json.RawMessage) (interface{}, error) {
+		var msg []interface{}
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return nil, err
+		}
+		data := msg[2].([]interface{})
+		return &userAccountConfig{Name: data[0].(string), Value: data[1]}, nil
+	})
+	async.Publish(`[0,"uac",["dark_mode",false]]`)
+	uac, ok := next().(*userAccountConfig)
+	if !ok {
+		t.Fatal("expected decoded user account config")
+	}
+	assert(t, "dark_mode", uac.Name)
+	assert(t, false, uac.Value)
+}
+
+func TestDepositNotification(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce).Credentials("apiKeyABC", "apiSecretXYZ")
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`{"event":"auth","status":"OK","chanId":0,"userId":1,"subId":"nonce1","auth_id":"valid-auth-guid","caps":{}}`)
+	if _, err := listener.nextAuthEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	// deposits are decoded into the model of the movements history
+	async.Publish(`[0,"n",[1569348774000,"deposit_new",null,null,[13105603,"ETH","ETHEREUM",null,null,1569348774000,1569348774000,null,null,"PROCESSING",null,null,0.263,0,null,null,"0x5f3ac27a8ba",null,null,null,"0x3f4dbf42e1",null],null,"SUCCESS","Deposit of 0.263 ETH"]]`)
+	n, err := listener.nextNotification()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, ok := n.NotifyInfo.(*movement.Movement)
+	if !ok {
+		t.Fatalf("expected movement, got %#v", n.NotifyInfo)
+	}
+	assert(t, int64(13105603), m.ID)
+	assert(t, "ETH", m.Currency)
+	assert(t, movement.StatusProcessing, m.Status)
+	assert(t, 0.263, m.Amount)
+	assert(t, "0x3f4dbf42e1", m.TransactionID)
+}
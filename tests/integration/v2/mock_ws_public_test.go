@@ -158,6 +158,14 @@ func TestOrderbook(t *testing.T) {
 	if newTrade.AmountJsNum.String() != "266122.94" {
 		t.Fatal("Newly submitted trade did not update into orderbook")
 	}
+	// selling into the best bid and one level below it
+	est, err := ws.EstimateFill("tXRPBTC", common.Ask, 300000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !est.Sufficient || est.BestPrice != 0.0000011 || est.WorstPrice != 0.00000109 {
+		t.Fatalf("unexpected fill estimate %+v", est)
+	}
 	// check that we did not send an unsubscribe message
 	// because that would mean the checksum was incorrect
 	if err_unsub := async.waitForMessage(pre); err_unsub != nil {
@@ -0,0 +1,306 @@
+//go:build go1.18
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/exchange"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/derivatives"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/status"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/ticker"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/adapter"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/websocket"
+)
+
+func TestTypedSubscribe(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce)
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := &websocket.SubscriptionRequest{
+		Event:   websocket.EventSubscribe,
+		Channel: websocket.ChanTrades,
+		Symbol:  "tBTCUSD",
+	}
+
+	// the type must match the channel
+	_, err := websocket.Subscribe[*ticker.Ticker](context.Background(), ws, req)
+	if !errors.Is(err, common.ErrBadRequest) {
+		t.Fatalf("expected bad request for mismatched type, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	trades, err := websocket.Subscribe[*trade.Trade](ctx, ws, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	async.Publish(`{"event":"subscribed","channel":"trades","chanId":7,"symbol":"tBTCUSD","subId":"nonce1","pair":"BTCUSD"}`)
+	if _, err := listener.nextSubscriptionEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the snapshot is split into trades, followed by the update
+	async.Publish(`[7,[[2,1568123933000,0.1,10000],[1,1568123932000,-0.2,10001]]]`)
+	async.Publish(`[7,"te",[3,1568123934000,0.3,10002]]`)
+	for _, id := range []int64{2, 1, 3} {
+		select {
+		case tr := <-trades:
+			assert(t, id, tr.ID)
+			assert(t, "tBTCUSD", tr.Pair)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("did not receive trade %d", id)
+		}
+	}
+
+	// canceling the context unsubscribes and closes the channel
+	cancel()
+	select {
+	case _, ok := <-trades:
+		if ok {
+			t.Fatal("expected closed channel")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("channel was not closed")
+	}
+	if err := async.waitForMessage(1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTypedFundingSubscribe(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce)
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	// funding symbols do not decode into trading types
+	tickerReq := &websocket.SubscriptionRequest{Event: websocket.EventSubscribe, Channel: websocket.ChanTicker, Symbol: "fUSD"}
+	_, err := websocket.Subscribe[*ticker.Ticker](context.Background(), ws, tickerReq)
+	if !errors.Is(err, common.ErrBadRequest) {
+		t.Fatalf("expected bad request for trading ticker type, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tickers, err := websocket.Subscribe[*ticker.FundingTicker](ctx, ws, tickerReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`{"event":"subscribed","channel":"ticker","chanId":8,"symbol":"fUSD","subId":"nonce1","currency":"USD"}`)
+	if _, err := listener.nextSubscriptionEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	bookReq := &websocket.SubscriptionRequest{Event: websocket.EventSubscribe, Channel: websocket.ChanBook, Symbol: "fUSD", Precision: "P0"}
+	books, err := websocket.Subscribe[*book.FundingBookUpdate](ctx, ws, bookReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`{"event":"subscribed","channel":"book","chanId":9,"symbol":"fUSD","subId":"nonce2","prec":"P0","freq":"F0","len":"25","currency":"USD"}`)
+	if _, err := listener.nextSubscriptionEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	async.Publish(`[8,[0.00034,0.000316,30,1575.2,0.000195,2,24812.0,-0.00005,-0.1398,0.000195,53424.2,0.000411,0.00012,null,null,3466.5]]`)
+	select {
+	case tk := <-tickers:
+		assert(t, 0.00034, tk.Frr)
+		assert(t, int64(30), tk.BidPeriod)
+		assert(t, 3466.5, tk.FrrAmountAvailable)
+	case <-time.After(2 * time.Second):
+		t.Fatal("did not receive funding ticker")
+	}
+
+	async.Publish(`[9,[[0.0002,2,5,1500.5],[0.0001,30,1,-100]]]`)
+	async.Publish(`[9,[0.0002,2,0,1]]`)
+	expected := []struct {
+		rate   float64
+		side   common.OrderSide
+		action book.BookAction
+	}{
+		{0.0002, common.Ask, book.BookEntry},
+		{0.0001, common.Bid, book.BookEntry},
+		{0.0002, common.Ask, book.BookRemoveEntry},
+	}
+	for _, e := range expected {
+		select {
+		case b := <-books:
+			assert(t, e.rate, b.Rate)
+			if b.Side != e.side || b.Action != e.action {
+				t.Fatalf("unexpected side %d action %d for rate %f", b.Side, b.Action, b.Rate)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("did not receive funding book entry")
+		}
+	}
+}
+
+func TestTypedStatusSubscribe(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce)
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	derivs, err := websocket.Subscribe[*derivatives.DerivativeStatus](ctx, ws, &websocket.SubscriptionRequest{
+		Event:   websocket.EventSubscribe,
+		Channel: websocket.ChanStatus,
+		Key:     "deriv:tBTCF0:USTF0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`{"event":"subscribed","channel":"status","chanId":11,"key":"deriv:tBTCF0:USTF0","subId":"nonce1"}`)
+	if _, err := listener.nextSubscriptionEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	liqs, err := websocket.Subscribe[*status.Liquidation](ctx, ws, &websocket.SubscriptionRequest{
+		Event:   websocket.EventSubscribe,
+		Channel: websocket.ChanStatus,
+		Key:     "liq:global",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`{"event":"subscribed","channel":"status","chanId":12,"key":"liq:global","subId":"nonce2"}`)
+	if _, err := listener.nextSubscriptionEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	async.Publish(`[11,[1617000000000,null,58000,57900,null,1000,null,1617001000000,0.0002,10,null,0.0001,null,null,58010,null,null,1500.5,null,null,null,-0.3,0.3]]`)
+	select {
+	case d := <-derivs:
+		assert(t, "tBTCF0:USTF0", d.Symbol)
+		assert(t, 58010.0, d.MarkPrice)
+		assert(t, 1500.5, d.OpenInterest)
+	case <-time.After(2 * time.Second):
+		t.Fatal("did not receive derivative status")
+	}
+
+	async.Publish(`[12,[["pos",145400868,1617000000000,null,"tETHF0:USTF0",-1.2,1800.5,null,1,1,null,1790.1]]]`)
+	select {
+	case l := <-liqs:
+		assert(t, int64(145400868), l.PositionID)
+		assert(t, "tETHF0:USTF0", l.Symbol)
+		assert(t, -1.2, l.Amount)
+		assert(t, 1790.1, l.PriceAcquired)
+	case <-time.After(2 * time.Second):
+		t.Fatal("did not receive liquidation")
+	}
+}
+
+func TestAdapterStreams(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce)
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ex := adapter.New(nil, ws)
+
+	trades, err := ex.StreamTrades(ctx, "tBTCUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`{"event":"subscribed","channel":"trades","chanId":5,"symbol":"tBTCUSD","subId":"nonce1","pair":"BTCUSD"}`)
+	if _, err := listener.nextSubscriptionEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	books, err := ex.StreamBook(ctx, "tBTCUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`{"event":"subscribed","channel":"book","chanId":6,"symbol":"tBTCUSD","subId":"nonce2","prec":"P0","freq":"F0","len":"25","pair":"BTCUSD"}`)
+	if _, err := listener.nextSubscriptionEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	async.Publish(`[5,"te",[401597395,1574694478808,-0.005,7245.3]]`)
+	select {
+	case tr := <-trades:
+		assert(t, "401597395", tr.ID)
+		assert(t, int64(1574694478808), tr.Time.UnixNano()/int64(time.Millisecond))
+		assert(t, exchange.Sell, tr.Side)
+		assert(t, 0.005, tr.Amount)
+		assert(t, 7245.3, tr.Price)
+	case <-time.After(2 * time.Second):
+		t.Fatal("did not receive trade")
+	}
+
+	async.Publish(`[6,[[7254.7,3,3.3],[7254.8,1,-0.5]]]`)
+	async.Publish(`[6,[7254.7,0,1]]`)
+	expected := []exchange.BookUpdate{
+		{Symbol: "tBTCUSD", Side: exchange.Buy, Price: 7254.7, Amount: 3.3},
+		{Symbol: "tBTCUSD", Side: exchange.Sell, Price: 7254.8, Amount: 0.5},
+		{Symbol: "tBTCUSD", Side: exchange.Buy, Price: 7254.7, Amount: 0},
+	}
+	for _, e := range expected {
+		select {
+		case u := <-books:
+			assert(t, &e, &u)
+		case <-time.After(2 * time.Second):
+			t.Fatal("did not receive book update")
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/websocket"
+)
+
+func level(side common.OrderSide, price, amount float64, count int64) *book.Book {
+	return &book.Book{Symbol: "tBTCUSD", Side: side, Price: price, Amount: amount, Count: count}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestOrderbookAnalytics(t *testing.T) {
+	ob := &websocket.Orderbook{}
+	if _, ok := ob.Spread(); ok {
+		t.Fatal("expected no spread for an empty book")
+	}
+
+	ob.SetWithSnapshot(&book.Snapshot{Snapshot: []*book.Book{
+		level(common.Bid, 99, 1, 1),
+		level(common.Bid, 98, 2, 1),
+		level(common.Bid, 90, 5, 1),
+		level(common.Ask, 101, 3, 1),
+		level(common.Ask, 102, 1, 1),
+		level(common.Ask, 110, 4, 1),
+	}})
+
+	if s, _ := ob.Spread(); !near(s, 2) {
+		t.Fatalf("expected spread 2, got %f", s)
+	}
+	if m, _ := ob.Mid(); !near(m, 100) {
+		t.Fatalf("expected mid 100, got %f", m)
+	}
+	// (99*3 + 101*1) / 4
+	if m, _ := ob.Microprice(); !near(m, 99.5) {
+		t.Fatalf("expected microprice 99.5, got %f", m)
+	}
+	if bids, asks := ob.DepthWithin(200); !near(bids, 3) || !near(asks, 4) {
+		t.Fatalf("expected depth 3/4 within 200bps, got %f/%f", bids, asks)
+	}
+	if i, _ := ob.Imbalance(); !near(i, 0) {
+		t.Fatalf("expected balanced book, got %f", i)
+	}
+
+	// update a level, remove another and add a new one
+	ob.UpdateWith(level(common.Bid, 99, 4, 2))
+	ob.UpdateWith(level(common.Ask, 101, 1, 0))
+	ob.UpdateWith(level(common.Bid, 100, 1, 1))
+
+	if bids, asks := ob.Volume(); !near(bids, 12) || !near(asks, 5) {
+		t.Fatalf("expected volume 12/5, got %f/%f", bids, asks)
+	}
+	if i, _ := ob.Imbalance(); !near(i, 7.0/17) {
+		t.Fatalf("expected imbalance 7/17, got %f", i)
+	}
+	if b, _ := ob.BestBid(); !near(b.Price, 100) {
+		t.Fatalf("expected best bid 100, got %f", b.Price)
+	}
+	if a, _ := ob.BestAsk(); !near(a.Price, 102) {
+		t.Fatalf("expected best ask 102, got %f", a.Price)
+	}
+}
@@ -0,0 +1,229 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/v2/proxy"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/websocket"
+	gorilla "github.com/gorilla/websocket"
+)
+
+type proxyFrame struct {
+	Event    string          `json:"event"`
+	ID       string          `json:"id"`
+	Msg      string          `json:"msg"`
+	Snapshot bool            `json:"snapshot"`
+	Data     json.RawMessage `json:"data"`
+}
+
+func dialProxy(t *testing.T, server *httptest.Server) *gorilla.Conn {
+	conn, _, err := gorilla.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func readProxyFrame(t *testing.T, conn *gorilla.Conn) proxyFrame {
+	if err := conn.SetReadDeadline(time.Now().Add(2 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	var f proxyFrame
+	if err := conn.ReadJSON(&f); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func bookLevels(t *testing.T, f proxyFrame) []float64 {
+	var snap struct {
+		Snapshot []struct {
+			Price  float64
+			Amount float64
+		}
+	}
+	if err := json.Unmarshal(f.Data, &snap); err != nil {
+		t.Fatal(err)
+	}
+	prices := make([]float64, len(snap.Snapshot))
+	for i, l := range snap.Snapshot {
+		prices[i] = l.Price
+	}
+	return prices
+}
+
+func TestProxyFanOut(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce)
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	p := proxy.New(ws)
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	sub := map[string]string{"event": "subscribe", "channel": "book", "symbol": "tBTCUSD", "prec": "P0", "freq": "F0", "len": "25"}
+	a := dialProxy(t, server)
+	defer a.Close()
+
+	if err := a.WriteJSON(map[string]string{"event": "subscribe", "channel": "auth"}); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, "error", readProxyFrame(t, a).Event)
+
+	if err := a.WriteJSON(sub); err != nil {
+		t.Fatal(err)
+	}
+	f := readProxyFrame(t, a)
+	assert(t, "subscribed", f.Event)
+	id := f.ID
+	if err := async.waitForMessage(0); err != nil {
+		t.Fatal(err)
+	}
+
+	async.Publish(`{"event":"subscribed","channel":"book","chanId":5,"symbol":"tBTCUSD","prec":"P0","freq":"F0","len":"25","subId":"nonce1","pair":"BTCUSD"}`)
+	if _, err := listener.nextSubscriptionEvent(); err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`[5,[[10000,1,1],[10001,2,-1]]]`)
+	f = readProxyFrame(t, a)
+	assert(t, id, f.ID)
+	assert(t, true, f.Snapshot)
+	assert(t, 2, len(bookLevels(t, f)))
+
+	// a level is added and one removed
+	async.Publish(`[5,[9999,1,0.5]]`)
+	async.Publish(`[5,[10001,0,-1]]`)
+	for i := 0; i < 2; i++ {
+		f = readProxyFrame(t, a)
+		assert(t, false, f.Snapshot)
+	}
+
+	// a late consumer shares the upstream subscription and receives the
+	// current book
+	b := dialProxy(t, server)
+	defer b.Close()
+	if err := b.WriteJSON(sub); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, "subscribed", readProxyFrame(t, b).Event)
+	f = readProxyFrame(t, b)
+	assert(t, true, f.Snapshot)
+	prices := bookLevels(t, f)
+	if len(prices) != 2 || prices[0] != 10000 || prices[1] != 9999 {
+		t.Fatalf("unexpected book %v", prices)
+	}
+	assert(t, 1, p.Feeds())
+	assert(t, 1, async.SentCount())
+
+	// updates reach both consumers
+	async.Publish(`[5,[10002,1,-0.1]]`)
+	assert(t, false, readProxyFrame(t, a).Snapshot)
+	assert(t, false, readProxyFrame(t, b).Snapshot)
+
+	if err := a.WriteJSON(map[string]string{"event": "unsubscribe", "id": id}); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, "unsubscribed", readProxyFrame(t, a).Event)
+	assert(t, 1, p.Feeds())
+
+	// the upstream channel is unsubscribed once the last consumer is gone
+	b.Close()
+	if err := async.waitForMessage(1); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(async.Sent[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, `{"event":"unsubscribe","chanId":5}`, string(raw))
+	assert(t, 0, p.Feeds())
+}
+
+func TestProxyFundingBook(t *testing.T) {
+	async := newTestAsync()
+	nonce := &IncrementingNonceGenerator{}
+	ws := websocket.NewWithAsyncFactoryNonce(newTestAsyncFactory(async), nonce)
+
+	listener := newListener()
+	listener.run(ws.Listen())
+
+	if err := ws.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	async.Publish(`{"event":"info","version":2}`)
+	if _, err := listener.nextInfoEvent(); err != nil {
+		t.Fatal(err)
+	}
+
+	p := proxy.New(ws)
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	sub := map[string]string{"event": "subscribe", "channel": "book", "symbol": "fUSD", "prec": "P0", "freq": "F0", "len": "25"}
+	a := dialProxy(t, server)
+	defer a.Close()
+	if err := a.WriteJSON(sub); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, "subscribed", readProxyFrame(t, a).Event)
+	if err := async.waitForMessage(0); err != nil {
+		t.Fatal(err)
+	}
+
+	async.Publish(`{"event":"subscribed","channel":"book","chanId":7,"symbol":"fUSD","prec":"P0","freq":"F0","len":"25","subId":"nonce1","currency":"USD"}`)
+	if _, err := listener.nextSubscriptionEvent(); err != nil {
+		t.Fatal(err)
+	}
+	async.Publish(`[7,[[0.0002,2,1,100],[0.0003,30,2,500]]]`)
+	assert(t, true, readProxyFrame(t, a).Snapshot)
+
+	// a level is added and one removed
+	async.Publish(`[7,[0.00025,7,1,200]]`)
+	async.Publish(`[7,[0.0002,2,0,100]]`)
+	for i := 0; i < 2; i++ {
+		assert(t, false, readProxyFrame(t, a).Snapshot)
+	}
+
+	// a late consumer receives the current funding book
+	b := dialProxy(t, server)
+	defer b.Close()
+	if err := b.WriteJSON(sub); err != nil {
+		t.Fatal(err)
+	}
+	assert(t, "subscribed", readProxyFrame(t, b).Event)
+	f := readProxyFrame(t, b)
+	assert(t, true, f.Snapshot)
+	var snap struct {
+		Snapshot []struct {
+			Rate   float64
+			Period int64
+			Amount float64
+		}
+	}
+	if err := json.Unmarshal(f.Data, &snap); err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Snapshot) != 2 || snap.Snapshot[0].Rate != 0.00025 || snap.Snapshot[0].Period != 7 || snap.Snapshot[1].Rate != 0.0003 {
+		t.Fatalf("unexpected funding book %+v", snap.Snapshot)
+	}
+	assert(t, 1, async.SentCount())
+}
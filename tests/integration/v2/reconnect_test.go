@@ -352,6 +352,14 @@ func TestHeartbeatTimeoutReconnectBlah(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	reconnect, err := apiRecv.nextReconnectEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reconnect.Attempt != 1 || !reconnect.Reconnected() || reconnect.Downtime < reconnect.Delay {
+		t.Fatalf("unexpected reconnect event: %#v", reconnect)
+	}
+
 	// check reconnect subscriptions
 	m, err = wsService.WaitForMessage(0, 0)
 	if err != nil {
@@ -0,0 +1,215 @@
+//go:build go1.18
+
+// Package adapter implements the exchange-agnostic interfaces of the
+// pkg/exchange package with the rest and websocket clients, e.g.:
+//
+//	var ex exchange.Exchange = adapter.New(restClient, wsClient)
+//
+// Balances and orders are handled by the rest client, which requires
+// credentials, and the streams by the websocket client, which has to be
+// connected.
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/exchange"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/wallet"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/rest"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/websocket"
+)
+
+// bookLen is the number of price levels per side of streamed books
+const bookLen = 25
+
+var _ exchange.Exchange = (*Client)(nil)
+
+// Client implements exchange.Exchange for Bitfinex. Orders are placed in
+// the exchange wallet.
+type Client struct {
+	rest *rest.Client
+	ws   *websocket.Client
+}
+
+// New returns an adapter using the given clients. Either may be nil if the
+// operations using it are not needed.
+func New(rc *rest.Client, wc *websocket.Client) *Client {
+	return &Client{rest: rc, ws: wc}
+}
+
+// Balances returns the balances of all wallets, with the wallet type as
+// account. Wallets whose available balance was not calculated yet report
+// nothing available.
+func (c *Client) Balances(ctx context.Context) ([]exchange.Balance, error) {
+	if c.rest == nil {
+		return nil, fmt.Errorf("%w: no rest client", common.ErrBadRequest)
+	}
+	req, err := c.rest.NewAuthenticatedRequest(common.PermissionRead, "wallets")
+	if err != nil {
+		return nil, err
+	}
+	raw, err := c.rest.Request(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return []exchange.Balance{}, nil
+	}
+	snap, err := wallet.SnapshotFromRaw(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]exchange.Balance, len(snap.Snapshot))
+	for i, w := range snap.Snapshot {
+		out[i] = exchange.Balance{
+			Account:  w.Type,
+			Currency: w.Currency,
+			Total:    w.Balance,
+		}
+		if w.HasBalanceAvailable {
+			out[i].Available = w.BalanceAvailable
+		}
+	}
+	return out, nil
+}
+
+// SubmitOrder places an exchange limit order, or an exchange market order if
+// the price is zero
+func (c *Client) SubmitOrder(ctx context.Context, o exchange.Order) (string, error) {
+	if c.rest == nil {
+		return "", fmt.Errorf("%w: no rest client", common.ErrBadRequest)
+	}
+	if o.Amount <= 0 {
+		return "", fmt.Errorf("%w: amount must be positive", common.ErrBadRequest)
+	}
+
+	onr := &order.NewRequest{
+		CID:    o.ClientID,
+		Symbol: o.Symbol,
+		Type:   common.OrderTypeExchangeLimit,
+		Amount: o.Amount,
+		Price:  o.Price,
+	}
+	switch o.Side {
+	case exchange.Buy:
+	case exchange.Sell:
+		onr.Amount = -o.Amount
+	default:
+		return "", fmt.Errorf("%w: invalid side %d", common.ErrBadRequest, o.Side)
+	}
+	if o.Price == 0 {
+		onr.Type = common.OrderTypeExchangeMarket
+	}
+
+	placed, err := c.rest.Orders.PlaceOrder(ctx, onr)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(placed.ID, 10), nil
+}
+
+// CancelOrder cancels the order with the given id
+func (c *Client) CancelOrder(ctx context.Context, id string) error {
+	if c.rest == nil {
+		return fmt.Errorf("%w: no rest client", common.ErrBadRequest)
+	}
+	oid, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid order id %q", common.ErrBadRequest, id)
+	}
+	return c.rest.Orders.CancelOrder(ctx, oid)
+}
+
+// StreamTrades subscribes to the trades channel of the symbol
+func (c *Client) StreamTrades(ctx context.Context, symbol string) (<-chan exchange.Trade, error) {
+	if c.ws == nil {
+		return nil, fmt.Errorf("%w: no websocket client", common.ErrBadRequest)
+	}
+	trades, err := websocket.Subscribe[*trade.Trade](ctx, c.ws, &websocket.SubscriptionRequest{
+		Event:   websocket.EventSubscribe,
+		Channel: websocket.ChanTrades,
+		Symbol:  symbol,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan exchange.Trade, cap(trades))
+	go func() {
+		defer close(out)
+		for t := range trades {
+			et := exchange.Trade{
+				Symbol: symbol,
+				ID:     strconv.FormatInt(t.ID, 10),
+				Time:   t.Time(),
+				Side:   exchange.Buy,
+				Amount: t.Amount,
+				Price:  t.Price,
+			}
+			if t.Amount < 0 {
+				et.Side = exchange.Sell
+				et.Amount = -t.Amount
+			}
+			select {
+			case out <- et:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// StreamBook subscribes to the book channel of the symbol, aggregated by
+// price at full precision with the top 25 levels per side
+func (c *Client) StreamBook(ctx context.Context, symbol string) (<-chan exchange.BookUpdate, error) {
+	if c.ws == nil {
+		return nil, fmt.Errorf("%w: no websocket client", common.ErrBadRequest)
+	}
+	levels, err := websocket.Subscribe[*book.Book](ctx, c.ws, &websocket.SubscriptionRequest{
+		Event:     websocket.EventSubscribe,
+		Channel:   websocket.ChanBook,
+		Symbol:    symbol,
+		Precision: string(common.Precision0),
+		Frequency: string(common.FrequencyRealtime),
+		Len:       strconv.Itoa(bookLen),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan exchange.BookUpdate, cap(levels))
+	go func() {
+		defer close(out)
+		for b := range levels {
+			u := exchange.BookUpdate{
+				Symbol: symbol,
+				Side:   exchange.Buy,
+				Price:  b.Price,
+				Amount: b.Amount,
+			}
+			if b.Side == common.Ask {
+				u.Side = exchange.Sell
+			}
+			if u.Amount < 0 {
+				u.Amount = -u.Amount
+			}
+			if b.Action == book.BookRemoveEntry {
+				u.Amount = 0
+			}
+			select {
+			case out <- u:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,75 @@
+//go:build go1.18
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/exchange"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalances(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/r/wallets", r.RequestURI)
+		_, err := w.Write([]byte(`[["exchange","BTC",1.5,0,1.2,null,null],["margin","USD",100,0,100,null,null]]`))
+		require.Nil(t, err)
+	}))
+	defer server.Close()
+
+	c := New(rest.NewClientWithURL(server.URL).Credentials("key", "secret"), nil)
+	balances, err := c.Balances(context.Background())
+	require.Nil(t, err)
+	assert.Equal(t, []exchange.Balance{
+		{Account: "exchange", Currency: "BTC", Total: 1.5, Available: 1.2},
+		{Account: "margin", Currency: "USD", Total: 100, Available: 100},
+	}, balances)
+}
+
+func TestOrders(t *testing.T) {
+	var payloads []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pld := map[string]interface{}{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&pld))
+		payloads = append(payloads, pld)
+
+		switch r.RequestURI {
+		case "/auth/w/order/submit":
+			_, err := w.Write([]byte(`[1567590617442,"on-req",null,null,[[30630788061,null,1567590617439,"tBTCUSD",1567590617439,1567590617439,-0.5,-0.5,"EXCHANGE LIMIT",null,null,null,0,"ACTIVE",null,null,10000,0,0,0,null,null,null,0,null,null,null,null,"API>BFX",null,null,null]],null,"SUCCESS","Submitting 1 orders."]`))
+			require.Nil(t, err)
+		case "/auth/w/order/cancel":
+			_, err := w.Write([]byte(`[1567590617442,"oc-req",null,null,[30630788061,null,1567590617439,"tBTCUSD",1567590617439,1567590617439,-0.5,-0.5,"EXCHANGE LIMIT",null,null,null,0,"ACTIVE",null,null,10000,0,0,0,null,null,null,0,0,null,null,null,"API>BFX",null,null,null],null,"SUCCESS","Submitted for cancellation; waiting for confirmation (ID: 30630788061)."]`))
+			require.Nil(t, err)
+		default:
+			t.Fatalf("unexpected request %s", r.RequestURI)
+		}
+	}))
+	defer server.Close()
+
+	c := New(rest.NewClientWithURL(server.URL).Credentials("key", "secret"), nil)
+	ctx := context.Background()
+
+	_, err := c.SubmitOrder(ctx, exchange.Order{Symbol: "tBTCUSD", Amount: 0.5})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	id, err := c.SubmitOrder(ctx, exchange.Order{Symbol: "tBTCUSD", Side: exchange.Sell, Amount: 0.5, Price: 10000})
+	require.Nil(t, err)
+	assert.Equal(t, "30630788061", id)
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "EXCHANGE LIMIT", payloads[0]["type"])
+	assert.Equal(t, "-0.5", payloads[0]["amount"])
+
+	require.Nil(t, c.CancelOrder(ctx, id))
+	require.Len(t, payloads, 2)
+	assert.Equal(t, float64(30630788061), payloads[1]["id"])
+
+	assert.True(t, errors.Is(c.CancelOrder(ctx, "abc"), common.ErrBadRequest))
+}
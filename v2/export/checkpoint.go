@@ -0,0 +1,77 @@
+package export
+
+import (
+	"path/filepath"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/store"
+)
+
+// Checkpoint is the progress of an export which allows to resume it
+type Checkpoint struct {
+	Datasets map[Dataset]*Progress `json:"datasets"`
+}
+
+// Progress is the state of the export of a single dataset. The history is
+// exported newest first, End moves towards the start of the range.
+type Progress struct {
+	End   int64   `json:"end"`   // end of the remaining range in milliseconds
+	Seen  []int64 `json:"seen"`  // ids of the exported records at End
+	Count int     `json:"count"` // number of exported records
+	Done  bool    `json:"done"`
+}
+
+// CheckpointStore persists the checkpoint of an Exporter
+type CheckpointStore interface {
+	Load() (*Checkpoint, error)
+	Save(*Checkpoint) error
+}
+
+// FileCheckpointStore stores the checkpoint as JSON in a file, written
+// through a store.File of its directory
+type FileCheckpointStore struct {
+	Path string
+}
+
+// Load returns the stored checkpoint, or nil if the file does not exist
+func (fs FileCheckpointStore) Load() (*Checkpoint, error) {
+	ss, err := fs.store()
+	if err != nil {
+		return nil, err
+	}
+	return ss.Load()
+}
+
+// Save writes the checkpoint to the file
+func (fs FileCheckpointStore) Save(cp *Checkpoint) error {
+	ss, err := fs.store()
+	if err != nil {
+		return err
+	}
+	return ss.Save(cp)
+}
+
+func (fs FileCheckpointStore) store() (StoreCheckpointStore, error) {
+	s, err := store.NewFile(filepath.Dir(fs.Path))
+	return StoreCheckpointStore{Store: s, Key: filepath.Base(fs.Path)}, err
+}
+
+// StoreCheckpointStore stores the checkpoint as JSON under Key of Store
+type StoreCheckpointStore struct {
+	Store store.Store
+	Key   string
+}
+
+// Load returns the stored checkpoint, or nil if there is none
+func (ss StoreCheckpointStore) Load() (*Checkpoint, error) {
+	cp := &Checkpoint{}
+	ok, err := store.GetJSON(ss.Store, ss.Key, cp)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// Save stores the checkpoint
+func (ss StoreCheckpointStore) Save(cp *Checkpoint) error {
+	return store.PutJSON(ss.Store, ss.Key, cp)
+}
@@ -0,0 +1,205 @@
+// Package export copies the history of an account into a pluggable sink, e.g.
+// for audits or migrations to another system.
+package export
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/rest"
+)
+
+// Dataset names a part of the account history
+type Dataset string
+
+const (
+	Orders         Dataset = "orders"
+	Trades         Dataset = "trades"
+	Movements      Dataset = "movements"
+	Ledgers        Dataset = "ledgers"
+	FundingOffers  Dataset = "funding_offers"
+	FundingLoans   Dataset = "funding_loans"
+	FundingCredits Dataset = "funding_credits"
+	FundingTrades  Dataset = "funding_trades"
+)
+
+// Datasets lists all datasets in the order they are exported
+var Datasets = []Dataset{
+	Orders,
+	Trades,
+	Movements,
+	Ledgers,
+	FundingOffers,
+	FundingLoans,
+	FundingCredits,
+	FundingTrades,
+}
+
+// Sink receives the exported records. Records are the typed models of the
+// rest package, e.g. *order.Order for Orders or rest.Movement2 for Movements,
+// and arrive newest first per dataset.
+type Sink interface {
+	Write(ds Dataset, records []interface{}) error
+}
+
+// Options configures an Exporter
+type Options struct {
+	From time.Time // start of the exported range, inclusive
+	To   time.Time // end of the exported range, inclusive. Defaults to now
+
+	Datasets []Dataset // exported datasets, defaults to all
+
+	// Interval is the minimum delay between two requests, defaults to one
+	// second which stays below the limits of the history endpoints
+	Interval time.Duration
+	// Backoff is the delay after a rate limited or otherwise transient
+	// failure, see rest.Retryable, doubled on every further attempt.
+	// Defaults to one minute
+	Backoff time.Duration
+	// MaxRetries is the number of retries of a failed request, defaults to 5
+	MaxRetries int
+
+	// Store persists the progress after every page written to the sink, an
+	// interrupted export resumes from the stored checkpoint
+	Store CheckpointStore
+}
+
+// Exporter pages through the history of every dataset, newest first, and
+// writes each page to the sink before it records its progress. Records of a
+// page written right before an interruption may be written again on resume.
+type Exporter struct {
+	client *rest.Client
+	sink   Sink
+	opts   Options
+	cp     *Checkpoint
+	retry  rest.Retrier
+}
+
+// New returns an exporter of the account of the authenticated client c
+func New(c *rest.Client, sink Sink, opts Options) *Exporter {
+	if opts.To.IsZero() {
+		opts.To = time.Now()
+	}
+	if len(opts.Datasets) == 0 {
+		opts.Datasets = Datasets
+	}
+	if opts.Interval == 0 {
+		opts.Interval = time.Second
+	}
+	if opts.Backoff == 0 {
+		opts.Backoff = time.Minute
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 5
+	}
+	return &Exporter{
+		client: c,
+		sink:   sink,
+		opts:   opts,
+		retry: rest.Retrier{
+			MaxRetries: opts.MaxRetries,
+			Backoff:    opts.Backoff,
+			Limiter:    rest.NewLimiter(opts.Interval, nil),
+		},
+	}
+}
+
+// Checkpoint returns the current progress of the export
+func (e *Exporter) Checkpoint() *Checkpoint {
+	return e.cp
+}
+
+// Run exports all configured datasets until they are complete or ctx is done
+func (e *Exporter) Run(ctx context.Context) error {
+	if e.opts.To.Before(e.opts.From) {
+		return fmt.Errorf("%w: export range ends before it starts", common.ErrBadRequest)
+	}
+	if err := e.load(); err != nil {
+		return err
+	}
+
+	for _, ds := range e.opts.Datasets {
+		src, ok := sources[ds]
+		if !ok {
+			return fmt.Errorf("%w: unknown dataset %q", common.ErrBadRequest, ds)
+		}
+		if err := e.export(ctx, ds, src); err != nil {
+			return fmt.Errorf("export %s: %w", ds, err)
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) load() error {
+	if e.cp != nil {
+		return nil
+	}
+	if e.opts.Store != nil {
+		cp, err := e.opts.Store.Load()
+		if err != nil {
+			return err
+		}
+		e.cp = cp
+	}
+	if e.cp == nil {
+		e.cp = &Checkpoint{}
+	}
+	if e.cp.Datasets == nil {
+		e.cp.Datasets = map[Dataset]*Progress{}
+	}
+	return nil
+}
+
+func (e *Exporter) export(ctx context.Context, ds Dataset, src source) error {
+	p := e.cp.Datasets[ds]
+	if p == nil {
+		p = &Progress{End: int64(common.MtsFromTime(e.opts.To))}
+		e.cp.Datasets[ds] = p
+	}
+	q := rest.NewQuery().FromMts(common.MtsFromTime(e.opts.From)).ToMts(common.Mts(p.End))
+	pager := rest.NewPager(q, src.limit).Resume(rest.PageCursor{Bound: p.End, Seen: p.Seen, Count: p.Count, Done: p.Done})
+
+	for !pager.Done() {
+		var page []record
+		err := e.retry.Do(ctx, func() (err error) {
+			page, err = src.fetch(e.client, pager.Query())
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		fresh := pager.Page(len(page), func(i int) (int64, int64) { return page[i].id, page[i].mts })
+		records := make([]interface{}, len(fresh))
+		for i, j := range fresh {
+			records[i] = page[j].value
+		}
+		if len(records) > 0 {
+			if err := e.sink.Write(ds, records); err != nil {
+				return err
+			}
+		}
+
+		c := pager.Cursor()
+		p.End, p.Seen, p.Count, p.Done = c.Bound, c.Seen, c.Count, c.Done
+		if err := e.save(); err != nil {
+			return err
+		}
+	}
+	return pager.Err()
+}
+
+func (e *Exporter) save() error {
+	if e.opts.Store == nil {
+		return nil
+	}
+	return e.opts.Store.Save(e.cp)
+}
+
+// RateLimited reports whether err was returned because the rate limit of the
+// API was exceeded
+func RateLimited(err error) bool {
+	return rest.RateLimited(err)
+}
@@ -0,0 +1,142 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func movementRaw(id, mts int64) []interface{} {
+	return []interface{}{
+		id, "BTC", "BITCOIN", nil, nil, mts, mts, nil, nil, "COMPLETED", nil, nil,
+		0.1, -0.0001, nil, nil, "addr", nil, nil, nil, "txid", nil,
+	}
+}
+
+// movementServer serves 1500 movements, two of them sharing each timestamp,
+// and answers the first request as rate limited
+func movementServer(t *testing.T) (*httptest.Server, *int) {
+	var all [][]interface{}
+	for id := int64(1500); id > 0; id-- {
+		all = append(all, movementRaw(id, 100000+id/2))
+	}
+
+	requests := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`["error",11010,"ERR_RATE_LIMIT"]`))
+			return
+		}
+
+		assert.Equal(t, "/auth/r/movements/hist", r.RequestURI)
+		var body map[string]float64
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		page := make([][]interface{}, 0)
+		for _, m := range all {
+			mts := m[6].(int64)
+			if mts >= int64(body["start"]) && mts <= int64(body["end"]) && len(page) < int(body["limit"]) {
+				page = append(page, m)
+			}
+		}
+		require.Nil(t, json.NewEncoder(w).Encode(page))
+	}
+	return httptest.NewServer(http.HandlerFunc(handler)), &requests
+}
+
+type memorySink struct {
+	ids  map[int64]int
+	fail int
+}
+
+func (s *memorySink) Write(ds Dataset, records []interface{}) error {
+	if s.fail--; s.fail == 0 {
+		return errors.New("disk full")
+	}
+	for _, r := range records {
+		s.ids[r.(rest.Movement2).ID]++
+	}
+	return nil
+}
+
+func newTestExporter(url string, sink Sink, store CheckpointStore) *Exporter {
+	c := rest.NewClientWithURL(url).Credentials("key", "secret")
+	e := New(c, sink, Options{
+		From:     common.Mts(0).Time(),
+		To:       common.Mts(200000).Time(),
+		Datasets: []Dataset{Movements},
+		Interval: time.Millisecond,
+		Backoff:  time.Millisecond,
+		Store:    store,
+	})
+	return e
+}
+
+func TestExportResume(t *testing.T) {
+	server, requests := movementServer(t)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "export")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	store := FileCheckpointStore{Path: filepath.Join(dir, "checkpoint.json")}
+
+	// the second page fails to be written, the first one is checkpointed
+	sink := &memorySink{ids: map[int64]int{}, fail: 2}
+	err = newTestExporter(server.URL, sink, store).Run(context.Background())
+	require.NotNil(t, err)
+	assert.Len(t, sink.ids, 1000)
+
+	cp, err := store.Load()
+	require.Nil(t, err)
+	p := cp.Datasets[Movements]
+	assert.False(t, p.Done)
+	assert.Equal(t, 1000, p.Count)
+	assert.Equal(t, int64(100250), p.End)
+	assert.Equal(t, []int64{501}, p.Seen)
+
+	e := newTestExporter(server.URL, sink, store)
+	require.Nil(t, e.Run(context.Background()))
+	assert.Len(t, sink.ids, 1500)
+	for id, n := range sink.ids {
+		assert.Equal(t, 1, n, "movement %d exported %d times", id, n)
+	}
+	assert.True(t, e.Checkpoint().Datasets[Movements].Done)
+	assert.Equal(t, 1500, e.Checkpoint().Datasets[Movements].Count)
+	// rate limited, first page, failed page and the failed page again
+	assert.Equal(t, 4, *requests)
+}
+
+func TestJSONLinesSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	sink := NewJSONLinesSink(dir)
+	require.Nil(t, sink.Write(Ledgers, []interface{}{map[string]int{"id": 1}, map[string]int{"id": 2}}))
+	require.Nil(t, sink.Write(Ledgers, []interface{}{map[string]int{"id": 3}}))
+	require.Nil(t, sink.Close())
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "ledgers.jsonl"))
+	require.Nil(t, err)
+	assert.Equal(t, "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n", string(b))
+}
+
+func TestRateLimited(t *testing.T) {
+	assert.True(t, RateLimited(&rest.ErrorResponse{Code: rest.ErrorCodeRateLimit}))
+	assert.False(t, RateLimited(&rest.ErrorResponse{Code: rest.ErrorCodeParams}))
+	assert.False(t, RateLimited(errors.New("ERR_RATE_LIMIT")))
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// JSONLinesSink writes every dataset to a file <dataset>.jsonl in Dir, one
+// JSON encoded record per line. Files are appended to, so a resumed export
+// continues the files of the interrupted one.
+type JSONLinesSink struct {
+	Dir string
+
+	mu    sync.Mutex
+	files map[Dataset]*os.File
+}
+
+// NewJSONLinesSink returns a sink writing to the directory dir
+func NewJSONLinesSink(dir string) *JSONLinesSink {
+	return &JSONLinesSink{Dir: dir}
+}
+
+// Write appends the records to the file of the dataset
+func (s *JSONLinesSink) Write(ds Dataset, records []interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.file(ds)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return f.Sync()
+}
+
+func (s *JSONLinesSink) file(ds Dataset) (*os.File, error) {
+	if f, ok := s.files[ds]; ok {
+		return f, nil
+	}
+	if s.files == nil {
+		s.files = map[Dataset]*os.File{}
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.Dir, string(ds)+".jsonl"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	s.files[ds] = f
+	return f, nil
+}
+
+// Close closes all files of the sink
+func (s *JSONLinesSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var first error
+	for ds, f := range s.files {
+		if err := f.Close(); err != nil && first == nil {
+			first = err
+		}
+		delete(s.files, ds)
+	}
+	return first
+}
@@ -0,0 +1,109 @@
+package export
+
+import (
+	"github.com/bitfinexcom/bitfinex-api-go/v2/rest"
+)
+
+// record is a single exported model with the id and the timestamp the
+// history endpoint of its dataset filters by
+type record struct {
+	id    int64
+	mts   int64
+	value interface{}
+}
+
+type source struct {
+	limit int
+	fetch func(c *rest.Client, q *rest.Query) ([]record, error)
+}
+
+var sources = map[Dataset]source{
+	Orders: {limit: 2500, fetch: func(c *rest.Client, q *rest.Query) ([]record, error) {
+		snap, err := c.Orders.HistoryQuery("", q)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]record, len(snap.Snapshot))
+		for i, o := range snap.Snapshot {
+			out[i] = record{id: o.ID, mts: o.MTSUpdated, value: o}
+		}
+		return out, nil
+	}},
+	Trades: {limit: 2500, fetch: func(c *rest.Client, q *rest.Query) ([]record, error) {
+		snap, err := c.Trades.AccountHistoryQuery("", q)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]record, len(snap.Snapshot))
+		for i, t := range snap.Snapshot {
+			out[i] = record{id: t.ID, mts: t.MTS, value: t}
+		}
+		return out, nil
+	}},
+	Movements: {limit: 1000, fetch: func(c *rest.Client, q *rest.Query) ([]record, error) {
+		ms, err := c.Wallet.MovementsQuery("", q)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]record, len(ms))
+		for i, m := range ms {
+			out[i] = record{id: m.ID, mts: m.MtsStarted, value: m}
+		}
+		return out, nil
+	}},
+	Ledgers: {limit: 2500, fetch: func(c *rest.Client, q *rest.Query) ([]record, error) {
+		snap, err := c.Ledgers.LedgersQuery("", q)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]record, len(snap.Snapshot))
+		for i, l := range snap.Snapshot {
+			out[i] = record{id: l.ID, mts: l.MTS, value: l}
+		}
+		return out, nil
+	}},
+	FundingOffers: {limit: 500, fetch: func(c *rest.Client, q *rest.Query) ([]record, error) {
+		snap, err := c.Funding.OfferHistoryQuery("", q)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]record, len(snap.Snapshot))
+		for i, o := range snap.Snapshot {
+			out[i] = record{id: o.ID, mts: o.MTSUpdated, value: o}
+		}
+		return out, nil
+	}},
+	FundingLoans: {limit: 500, fetch: func(c *rest.Client, q *rest.Query) ([]record, error) {
+		snap, err := c.Funding.LoansHistoryQuery("", q)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]record, len(snap.Snapshot))
+		for i, l := range snap.Snapshot {
+			out[i] = record{id: l.ID, mts: l.MTSUpdated, value: l}
+		}
+		return out, nil
+	}},
+	FundingCredits: {limit: 500, fetch: func(c *rest.Client, q *rest.Query) ([]record, error) {
+		snap, err := c.Funding.CreditsHistoryQuery("", q)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]record, len(snap.Snapshot))
+		for i, cr := range snap.Snapshot {
+			out[i] = record{id: cr.ID, mts: cr.MTSUpdated, value: cr}
+		}
+		return out, nil
+	}},
+	FundingTrades: {limit: 500, fetch: func(c *rest.Client, q *rest.Query) ([]record, error) {
+		snap, err := c.Funding.TradesQuery("", q)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]record, len(snap.Snapshot))
+		for i, t := range snap.Snapshot {
+			out[i] = record{id: t.ID, mts: t.MTSCreated, value: t}
+		}
+		return out, nil
+	}},
+}
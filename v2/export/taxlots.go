@@ -0,0 +1,270 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecutionupdate"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/symbol"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/rest"
+)
+
+// LotMethod selects the lots a disposal is matched against
+type LotMethod int
+
+const (
+	// FIFO matches disposals against the oldest lots first
+	FIFO LotMethod = iota
+	// LIFO matches disposals against the newest lots first
+	LIFO
+)
+
+// TaxOptions configures RealizedGains
+type TaxOptions struct {
+	Method LotMethod
+	// Quote is the currency cost and proceeds are measured in, defaults to
+	// USD. Trades on pairs with a different quote currency are skipped.
+	Quote string
+}
+
+// Lot is an acquired amount of a currency which was not disposed yet
+type Lot struct {
+	Currency    string
+	AcquiredMTS int64
+	Amount      float64
+	UnitCost    float64 // cost of a unit of the lot in the quote currency
+	// UnknownBasis is set for lots deposited to the account, their cost is
+	// reported as 0
+	UnknownBasis bool
+}
+
+// RealizedGain is the part of a sell trade matched against a single lot
+type RealizedGain struct {
+	Currency     string
+	TradeID      int64
+	AcquiredMTS  int64
+	DisposedMTS  int64
+	Amount       float64
+	Proceeds     float64
+	Cost         float64
+	Gain         float64
+	UnknownBasis bool // the matched lot, or no lot at all, has no known cost
+}
+
+// CurrencyGains sums the realized gains and the open lots of a currency
+type CurrencyGains struct {
+	Currency   string
+	Proceeds   float64
+	Cost       float64
+	Gain       float64
+	OpenAmount float64
+	OpenCost   float64
+}
+
+// TaxReport is the result of RealizedGains
+type TaxReport struct {
+	Method     LotMethod
+	Quote      string
+	Gains      []RealizedGain  // ordered by disposal time
+	Currencies []CurrencyGains // ordered by currency
+	Open       []Lot           // lots left after all disposals, ordered by currency
+	Skipped    []int64         // ids of trades not measured in the quote currency
+}
+
+type lotEvent struct {
+	mts      int64
+	trade    *tradeexecutionupdate.TradeExecutionUpdate
+	movement *rest.Movement2
+}
+
+// RealizedGains matches the sell trades of the account against the lots
+// acquired by buy trades and deposits and reports the realized gains per
+// currency, e.g. from the records of an export:
+//
+//	report := export.RealizedGains(trades, movements, export.TaxOptions{Method: export.FIFO})
+//
+// Deposits add lots without a known cost, withdrawals remove lots without
+// realizing a gain. Fees paid in the quote currency add to the cost of a buy
+// and reduce the proceeds of a sell, fees paid in the bought currency reduce
+// the acquired amount.
+func RealizedGains(trades []*tradeexecutionupdate.TradeExecutionUpdate, movements []rest.Movement2, opts TaxOptions) *TaxReport {
+	if opts.Quote == "" {
+		opts.Quote = "USD"
+	}
+
+	events := make([]lotEvent, 0, len(trades)+len(movements))
+	for _, t := range trades {
+		events = append(events, lotEvent{mts: t.MTS, trade: t})
+	}
+	for i := range movements {
+		m := &movements[i]
+		if m.Status.IsSuccessful() && m.Currency != opts.Quote {
+			events = append(events, lotEvent{mts: m.MtsUpdated, movement: m})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].mts < events[j].mts
+	})
+
+	r := &TaxReport{Method: opts.Method, Quote: opts.Quote}
+	lots := map[string][]Lot{}
+	for _, ev := range events {
+		if m := ev.movement; m != nil {
+			if m.Amount > 0 {
+				lots[m.Currency] = append(lots[m.Currency], Lot{
+					Currency:     m.Currency,
+					AcquiredMTS:  ev.mts,
+					Amount:       m.Amount,
+					UnknownBasis: true,
+				})
+				continue
+			}
+			lots[m.Currency], _ = takeLots(lots[m.Currency], m.Currency, -m.Amount+absFee(m.Fees), opts.Method)
+			continue
+		}
+
+		t := ev.trade
+		sym, err := symbol.Parse(t.Pair)
+		if err != nil || !sym.IsTrading() || sym.Quote != opts.Quote {
+			r.Skipped = append(r.Skipped, t.ID)
+			continue
+		}
+		base, quote := sym.Base, sym.Quote
+
+		amount := t.ExecAmount
+		fee := absFee(t.Fee)
+		if amount > 0 {
+			cost := amount * t.ExecPrice
+			switch t.FeeCurrency {
+			case quote:
+				cost += fee
+			case base:
+				amount -= fee
+			}
+			if amount <= 0 {
+				continue
+			}
+			lots[base] = append(lots[base], Lot{
+				Currency:    base,
+				AcquiredMTS: t.MTS,
+				Amount:      amount,
+				UnitCost:    cost / amount,
+			})
+			continue
+		}
+
+		amount = -amount
+		proceeds := amount * t.ExecPrice
+		switch t.FeeCurrency {
+		case quote:
+			proceeds -= fee
+		case base:
+			amount += fee
+		}
+
+		var matched []RealizedGain
+		lots[base], matched = takeLots(lots[base], base, amount, opts.Method)
+		for _, g := range matched {
+			g.TradeID = t.ID
+			g.DisposedMTS = t.MTS
+			g.Proceeds = proceeds * g.Amount / amount
+			g.Gain = g.Proceeds - g.Cost
+			r.Gains = append(r.Gains, g)
+		}
+	}
+
+	sums := map[string]*CurrencyGains{}
+	sum := func(cur string) *CurrencyGains {
+		if s, ok := sums[cur]; ok {
+			return s
+		}
+		s := &CurrencyGains{Currency: cur}
+		sums[cur] = s
+		return s
+	}
+	for _, g := range r.Gains {
+		s := sum(g.Currency)
+		s.Proceeds += g.Proceeds
+		s.Cost += g.Cost
+		s.Gain += g.Gain
+	}
+	for cur, ls := range lots {
+		for _, l := range ls {
+			s := sum(cur)
+			s.OpenAmount += l.Amount
+			s.OpenCost += l.Amount * l.UnitCost
+		}
+	}
+
+	for _, s := range sums {
+		r.Currencies = append(r.Currencies, *s)
+	}
+	sort.Slice(r.Currencies, func(i, j int) bool {
+		return r.Currencies[i].Currency < r.Currencies[j].Currency
+	})
+	for _, c := range r.Currencies {
+		r.Open = append(r.Open, lots[c.Currency]...)
+	}
+	return r
+}
+
+// takeLots removes amount from the lots, in the order of the method, and
+// returns the remaining lots and the matched parts. An amount exceeding the
+// lots is matched without a known basis.
+func takeLots(lots []Lot, currency string, amount float64, method LotMethod) ([]Lot, []RealizedGain) {
+	var matched []RealizedGain
+	for amount > dust && len(lots) > 0 {
+		i := 0
+		if method == LIFO {
+			i = len(lots) - 1
+		}
+		l := &lots[i]
+
+		take := amount
+		if l.Amount < take {
+			take = l.Amount
+		}
+		matched = append(matched, RealizedGain{
+			Currency:     currency,
+			AcquiredMTS:  l.AcquiredMTS,
+			Amount:       take,
+			Cost:         take * l.UnitCost,
+			UnknownBasis: l.UnknownBasis,
+		})
+		amount -= take
+		l.Amount -= take
+
+		if l.Amount <= dust {
+			if method == LIFO {
+				lots = lots[:i]
+			} else {
+				lots = lots[1:]
+			}
+		}
+	}
+	if amount > dust {
+		matched = append(matched, RealizedGain{Currency: currency, Amount: amount, UnknownBasis: true})
+	}
+	return lots, matched
+}
+
+// dust is the remaining amount of a lot treated as fully disposed, it
+// absorbs float rounding of repeated partial disposals
+const dust = 1e-12
+
+func absFee(fee float64) float64 {
+	if fee < 0 {
+		return -fee
+	}
+	return fee
+}
+
+func (m LotMethod) String() string {
+	switch m {
+	case FIFO:
+		return "FIFO"
+	case LIFO:
+		return "LIFO"
+	}
+	return fmt.Sprintf("LotMethod(%d)", int(m))
+}
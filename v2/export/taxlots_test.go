@@ -0,0 +1,84 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/movement"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecutionupdate"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lotTrades() []*tradeexecutionupdate.TradeExecutionUpdate {
+	return []*tradeexecutionupdate.TradeExecutionUpdate{
+		// buy 1 BTC at 100 and 1 BTC at 200, the fee adds to the cost
+		{ID: 1, Pair: "tBTCUSD", MTS: 1000, ExecAmount: 1, ExecPrice: 100, Fee: -1, FeeCurrency: "USD"},
+		{ID: 2, Pair: "tBTCUSD", MTS: 2000, ExecAmount: 1, ExecPrice: 200, Fee: -0.1, FeeCurrency: "BTC"},
+		// sell 1.5 BTC at 300, the fee reduces the proceeds
+		{ID: 3, Pair: "tBTCUSD", MTS: 3000, ExecAmount: -1.5, ExecPrice: 300, Fee: -4.5, FeeCurrency: "USD"},
+		{ID: 4, Pair: "tETHBTC", MTS: 3500, ExecAmount: 1, ExecPrice: 0.03, Fee: -0.001, FeeCurrency: "ETH"},
+	}
+}
+
+func TestRealizedGainsFIFO(t *testing.T) {
+	r := RealizedGains(lotTrades(), nil, TaxOptions{Method: FIFO})
+	assert.Equal(t, "USD", r.Quote)
+	assert.Equal(t, []int64{4}, r.Skipped)
+
+	require.Len(t, r.Gains, 2)
+	first, second := r.Gains[0], r.Gains[1]
+	assert.Equal(t, int64(1000), first.AcquiredMTS)
+	assert.InDelta(t, 1, first.Amount, 1e-9)
+	assert.InDelta(t, 101, first.Cost, 1e-9)
+	assert.InDelta(t, 297, first.Proceeds, 1e-9)
+	assert.InDelta(t, 196, first.Gain, 1e-9)
+
+	assert.Equal(t, int64(2000), second.AcquiredMTS)
+	assert.InDelta(t, 0.5, second.Amount, 1e-9)
+	assert.InDelta(t, 0.5*200/0.9, second.Cost, 1e-9)
+	assert.InDelta(t, 148.5, second.Proceeds, 1e-9)
+
+	require.Len(t, r.Currencies, 1)
+	btc := r.Currencies[0]
+	assert.Equal(t, "BTC", btc.Currency)
+	assert.InDelta(t, 445.5, btc.Proceeds, 1e-9)
+	assert.InDelta(t, 0.4, btc.OpenAmount, 1e-9)
+	assert.InDelta(t, 0.4*200/0.9, btc.OpenCost, 1e-9)
+}
+
+func TestRealizedGainsLIFO(t *testing.T) {
+	r := RealizedGains(lotTrades(), nil, TaxOptions{Method: LIFO})
+	require.Len(t, r.Gains, 2)
+	assert.Equal(t, int64(2000), r.Gains[0].AcquiredMTS)
+	assert.InDelta(t, 0.9, r.Gains[0].Amount, 1e-9)
+	assert.InDelta(t, 200, r.Gains[0].Cost, 1e-9)
+	assert.Equal(t, int64(1000), r.Gains[1].AcquiredMTS)
+	assert.InDelta(t, 0.6, r.Gains[1].Amount, 1e-9)
+
+	require.Len(t, r.Open, 1)
+	assert.InDelta(t, 0.4, r.Open[0].Amount, 1e-9)
+	assert.InDelta(t, 101, r.Open[0].UnitCost, 1e-9)
+}
+
+func TestRealizedGainsMovements(t *testing.T) {
+	movements := []rest.Movement2{
+		{ID: 1, Currency: "BTC", MtsUpdated: 500, Status: movement.StatusCompleted, Amount: 2},
+		{ID: 2, Currency: "BTC", MtsUpdated: 600, Status: movement.StatusCanceled, Amount: 5},
+		{ID: 3, Currency: "BTC", MtsUpdated: 2500, Status: movement.StatusCompleted, Amount: -2.5, Fees: -0.1},
+	}
+	trades := lotTrades()[:3]
+
+	r := RealizedGains(trades, movements, TaxOptions{Method: FIFO})
+	// the withdrawal takes the deposit and part of the first buy, the sell
+	// takes the rest and exceeds the remaining lots
+	require.Len(t, r.Gains, 3)
+	assert.InDelta(t, 0.4, r.Gains[0].Amount, 1e-9)
+	assert.InDelta(t, 0.4*101, r.Gains[0].Cost, 1e-9)
+	assert.InDelta(t, 0.9, r.Gains[1].Amount, 1e-9)
+	assert.False(t, r.Gains[1].UnknownBasis)
+	assert.InDelta(t, 0.2, r.Gains[2].Amount, 1e-9)
+	assert.True(t, r.Gains[2].UnknownBasis)
+	assert.Equal(t, 0.0, r.Gains[2].Cost)
+	assert.Empty(t, r.Open)
+}
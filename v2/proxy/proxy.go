@@ -0,0 +1,536 @@
+// Package proxy re-serves the public channels of a single upstream
+// websocket connection to several local consumers, e.g. processes of a team
+// which would otherwise exceed the connection limits of Bitfinex:
+//
+//	ws := websocket.New()
+//	if err := ws.Connect(); err != nil { ... }
+//	http.Handle("/ws", proxy.New(ws))
+//	log.Fatal(http.ListenAndServe("127.0.0.1:8080", nil))
+//
+// Consumers connect with a websocket and send subscription requests using
+// the field names of the Bitfinex API:
+//
+//	{"event":"subscribe","channel":"book","symbol":"tBTCUSD","prec":"P0","freq":"F0","len":"25"}
+//	{"event":"unsubscribe","id":"book|tBTCUSD|P0|F0|25|"}
+//
+// The proxy confirms with {"event":"subscribed","id":...} and sends the
+// messages of the channel as {"id":...,"snapshot":true|false,"data":...},
+// where data is the decoded model, e.g. a book.Snapshot or book.Book.
+// Failed requests are answered with {"event":"error","msg":...}.
+//
+// Every distinct channel is subscribed upstream once while it has at least
+// one consumer. Consumers joining later receive the current book of book
+// channels, and the last snapshot of other channels, before the updates.
+// After a reconnect of the upstream connection the channels are subscribed
+// again and a new snapshot is sent, which consumers have to treat as a reset
+// of their state. Consumers not reading fast enough are disconnected rather
+// than slowing down the others.
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/websocket"
+	gorilla "github.com/gorilla/websocket"
+)
+
+// SendBuffer is the number of messages buffered per consumer before it is
+// considered too slow and disconnected
+const SendBuffer = 1024
+
+// Request is a request of a consumer
+type Request struct {
+	Event     string `json:"event"` // subscribe or unsubscribe
+	ID        string `json:"id,omitempty"`
+	Channel   string `json:"channel,omitempty"`
+	Symbol    string `json:"symbol,omitempty"`
+	Precision string `json:"prec,omitempty"`
+	Frequency string `json:"freq,omitempty"`
+	Len       string `json:"len,omitempty"`
+	Key       string `json:"key,omitempty"`
+}
+
+// id identifies the channel of a subscription request
+func (r *Request) id() string {
+	return strings.Join([]string{r.Channel, r.Symbol, r.Precision, r.Frequency, r.Len, r.Key}, "|")
+}
+
+// Event is a response to a request
+type Event struct {
+	Event   string `json:"event"` // subscribed, unsubscribed or error
+	ID      string `json:"id,omitempty"`
+	Channel string `json:"channel,omitempty"`
+	Symbol  string `json:"symbol,omitempty"`
+	Msg     string `json:"msg,omitempty"`
+}
+
+// Data is a message of a channel
+type Data struct {
+	ID       string      `json:"id"`
+	Snapshot bool        `json:"snapshot"`
+	Data     interface{} `json:"data"`
+}
+
+// Server is a http.Handler serving the channels of the websocket client to
+// the consumers connecting to it
+type Server struct {
+	ws       *websocket.Client
+	upgrader gorilla.Upgrader
+
+	mu    sync.Mutex
+	feeds map[string]*feed
+}
+
+// New returns a proxy for the public channels of c, which has to be
+// connected
+func New(c *websocket.Client) *Server {
+	return &Server{
+		ws:    c,
+		feeds: make(map[string]*feed),
+	}
+}
+
+// Feeds returns the number of channels subscribed upstream
+func (s *Server) Feeds() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.feeds)
+}
+
+// ServeHTTP upgrades the request to a websocket and serves the consumer
+// until it disconnects
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := &consumer{
+		conn: conn,
+		send: make(chan []byte, SendBuffer),
+		done: make(chan struct{}),
+		subs: make(map[string]*feed),
+	}
+	go c.write()
+	defer s.leave(c)
+
+	for {
+		var req Request
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		switch req.Event {
+		case websocket.EventSubscribe:
+			s.subscribe(c, &req)
+		case websocket.EventUnsubscribe:
+			s.unsubscribe(c, req.ID)
+		default:
+			c.reply(Event{Event: "error", Msg: fmt.Sprintf("unknown event %q", req.Event)})
+		}
+	}
+}
+
+func (s *Server) subscribe(c *consumer, req *Request) {
+	switch req.Channel {
+	case websocket.ChanBook, websocket.ChanTrades, websocket.ChanTicker, websocket.ChanCandles, websocket.ChanStatus:
+	default:
+		c.reply(Event{Event: "error", Msg: fmt.Sprintf("%s: unsupported channel %q", common.ErrBadRequest, req.Channel)})
+		return
+	}
+
+	id := req.id()
+	if c.subscribed(id) {
+		c.reply(Event{Event: "error", ID: id, Msg: "already subscribed"})
+		return
+	}
+
+	s.mu.Lock()
+	f, ok := s.feeds[id]
+	if !ok {
+		f = newFeed(id, req)
+		s.feeds[id] = f
+	}
+	f.refs++
+	s.mu.Unlock()
+
+	// the first consumer subscribes upstream without holding the lock, the
+	// consumers joining meanwhile wait for the outcome
+	if !ok {
+		f.err = f.start(s.ws)
+		close(f.ready)
+	}
+	<-f.ready
+	if f.err != nil {
+		s.mu.Lock()
+		f.refs--
+		if s.feeds[id] == f {
+			delete(s.feeds, id)
+		}
+		s.mu.Unlock()
+		c.reply(Event{Event: "error", ID: id, Msg: f.err.Error()})
+		return
+	}
+
+	c.add(id, f)
+	// confirm before adding the consumer, so that the confirmation precedes
+	// the data
+	c.reply(Event{Event: "subscribed", ID: id, Channel: req.Channel, Symbol: req.Symbol})
+	f.join(c)
+}
+
+func (s *Server) unsubscribe(c *consumer, id string) {
+	f := c.remove(id)
+	if f == nil {
+		c.reply(Event{Event: "error", ID: id, Msg: "not subscribed"})
+		return
+	}
+	s.release(c, f)
+	c.reply(Event{Event: "unsubscribed", ID: id})
+}
+
+// leave removes all subscriptions of a disconnected consumer
+func (s *Server) leave(c *consumer) {
+	c.close()
+	for _, f := range c.removeAll() {
+		s.release(c, f)
+	}
+}
+
+// release removes the consumer from the feed and unsubscribes upstream once
+// the feed has no consumers left
+func (s *Server) release(c *consumer, f *feed) {
+	f.leave(c)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f.refs--
+	if f.refs == 0 && s.feeds[f.id] == f {
+		delete(s.feeds, f.id)
+		f.cancel()
+	}
+}
+
+// consumer is a connected local client
+type consumer struct {
+	conn *gorilla.Conn
+	send chan []byte
+	done chan struct{}
+	once sync.Once
+
+	mu   sync.Mutex
+	subs map[string]*feed
+}
+
+// write sends the queued messages until the consumer is closed
+func (c *consumer) write() {
+	defer c.conn.Close()
+	for {
+		select {
+		case <-c.done:
+			return
+		case b := <-c.send:
+			if err := c.conn.WriteMessage(gorilla.TextMessage, b); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+// enqueue queues a message without blocking and disconnects the consumer if
+// its buffer is full
+func (c *consumer) enqueue(b []byte) {
+	select {
+	case <-c.done:
+	case c.send <- b:
+	default:
+		c.close()
+	}
+}
+
+func (c *consumer) reply(ev Event) {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	c.enqueue(b)
+}
+
+// close stops the writer, which closes the connection and thereby ends the
+// reading loop of ServeHTTP
+func (c *consumer) close() {
+	c.once.Do(func() { close(c.done) })
+}
+
+func (c *consumer) subscribed(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.subs[id]
+	return ok
+}
+
+func (c *consumer) add(id string, f *feed) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subs[id] = f
+}
+
+func (c *consumer) remove(id string) *feed {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f := c.subs[id]
+	delete(c.subs, id)
+	return f
+}
+
+func (c *consumer) removeAll() []*feed {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	feeds := make([]*feed, 0, len(c.subs))
+	for id, f := range c.subs {
+		feeds = append(feeds, f)
+		delete(c.subs, id)
+	}
+	return feeds
+}
+
+// levelKey identifies a level of a book, the ID is only set for raw books and
+// the rate and period only for funding books
+type levelKey struct {
+	id     int64
+	price  float64
+	rate   float64
+	period int64
+}
+
+// feed is an upstream subscription shared by its consumers
+type feed struct {
+	id     string
+	req    websocket.SubscriptionRequest
+	raw    bool
+	cancel context.CancelFunc
+	// ready is closed once the upstream subscription was made or failed
+	// with err
+	ready chan struct{}
+	err   error
+	// refs counts the consumers subscribed or subscribing, guarded by the
+	// lock of the Server
+	refs int
+
+	mu        sync.Mutex
+	consumers map[*consumer]struct{}
+	// snapshot is the last snapshot of the channel, levels the current book
+	// of trading book channels and funding the one of funding book channels
+	snapshot interface{}
+	levels   map[levelKey]*book.Book
+	funding  map[levelKey]*book.FundingBookUpdate
+}
+
+func newFeed(id string, req *Request) *feed {
+	f := &feed{
+		id: id,
+		req: websocket.SubscriptionRequest{
+			Event:     websocket.EventSubscribe,
+			Channel:   req.Channel,
+			Symbol:    req.Symbol,
+			Precision: req.Precision,
+			Frequency: req.Frequency,
+			Len:       req.Len,
+			Key:       req.Key,
+		},
+		ready:     make(chan struct{}),
+		consumers: make(map[*consumer]struct{}),
+	}
+	if req.Channel == websocket.ChanBook {
+		f.raw = book.IsRawBook(req.Precision)
+		if strings.HasPrefix(req.Symbol, common.FundingPrefix) {
+			f.funding = make(map[levelKey]*book.FundingBookUpdate)
+		} else {
+			f.levels = make(map[levelKey]*book.Book)
+		}
+	}
+	return f
+}
+
+func (f *feed) start(ws *websocket.Client) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+	if _, err := ws.SubscribeFunc(ctx, &f.req, f.handle, nil); err != nil {
+		cancel()
+		return err
+	}
+	return nil
+}
+
+// join adds the consumer and sends it the current state of the channel
+func (f *feed) join(c *consumer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.consumers[c] = struct{}{}
+
+	var snap interface{}
+	switch {
+	case f.snapshot == nil:
+	case f.levels != nil:
+		snap = f.book()
+	case f.funding != nil:
+		snap = f.fundingBook()
+	default:
+		snap = f.snapshot
+	}
+	if snap == nil {
+		return
+	}
+	if b, err := json.Marshal(Data{ID: f.id, Snapshot: true, Data: snap}); err == nil {
+		c.enqueue(b)
+	}
+}
+
+// leave removes the consumer
+func (f *feed) leave(c *consumer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.consumers, c)
+}
+
+// handle updates the state of the channel and forwards the message to the
+// consumers. It is called by the reader goroutine of the websocket client.
+func (f *feed) handle(msg interface{}) {
+	snap := isSnapshot(msg)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if snap {
+		f.snapshot = msg
+	}
+	if f.levels != nil {
+		if snap {
+			f.levels = make(map[levelKey]*book.Book)
+			if s, ok := msg.(*book.Snapshot); ok {
+				for _, b := range s.Snapshot {
+					f.apply(b)
+				}
+			}
+		} else if b, ok := msg.(*book.Book); ok {
+			f.apply(b)
+		}
+	}
+	if f.funding != nil {
+		if snap {
+			f.funding = make(map[levelKey]*book.FundingBookUpdate)
+			if s, ok := msg.(*book.FundingSnapshot); ok {
+				for _, b := range s.Snapshot {
+					f.applyFunding(b)
+				}
+			}
+		} else if b, ok := msg.(*book.FundingBookUpdate); ok {
+			f.applyFunding(b)
+		}
+	}
+
+	b, err := json.Marshal(Data{ID: f.id, Snapshot: snap, Data: msg})
+	if err != nil {
+		return
+	}
+	for c := range f.consumers {
+		c.enqueue(b)
+	}
+}
+
+func (f *feed) apply(b *book.Book) {
+	k := levelKey{price: b.Price, rate: b.Rate, period: b.Period}
+	if f.raw {
+		k = levelKey{id: b.ID}
+	}
+	if b.Action == book.BookRemoveEntry {
+		delete(f.levels, k)
+		return
+	}
+	f.levels[k] = b
+}
+
+func (f *feed) applyFunding(b *book.FundingBookUpdate) {
+	k := levelKey{rate: b.Rate, period: b.Period}
+	if f.raw {
+		k = levelKey{id: b.ID}
+	}
+	if b.Action == book.BookRemoveEntry {
+		delete(f.funding, k)
+		return
+	}
+	f.funding[k] = b
+}
+
+// book returns the current levels as a snapshot, bids by descending and
+// asks by ascending price
+func (f *feed) book() *book.Snapshot {
+	s := &book.Snapshot{Snapshot: make([]*book.Book, 0, len(f.levels))}
+	for _, b := range f.levels {
+		s.Snapshot = append(s.Snapshot, b)
+	}
+	sort.Slice(s.Snapshot, func(i, j int) bool {
+		a, b := s.Snapshot[i], s.Snapshot[j]
+		if a.Side != b.Side {
+			return a.Side < b.Side
+		}
+		pa, pb := a.Price, b.Price
+		if a.Rate != 0 || b.Rate != 0 {
+			pa, pb = a.Rate, b.Rate
+		}
+		if pa != pb {
+			if a.Side == common.Bid {
+				return pa > pb
+			}
+			return pa < pb
+		}
+		return a.ID < b.ID
+	})
+	return s
+}
+
+// fundingBook returns the current levels of a funding book as a snapshot,
+// bids by descending and asks by ascending rate, then by period
+func (f *feed) fundingBook() *book.FundingSnapshot {
+	s := &book.FundingSnapshot{Snapshot: make([]*book.FundingBookUpdate, 0, len(f.funding))}
+	for _, b := range f.funding {
+		s.Snapshot = append(s.Snapshot, b)
+	}
+	sort.Slice(s.Snapshot, func(i, j int) bool {
+		a, b := s.Snapshot[i], s.Snapshot[j]
+		if a.Side != b.Side {
+			return a.Side < b.Side
+		}
+		if a.Rate != b.Rate {
+			if a.Side == common.Bid {
+				return a.Rate > b.Rate
+			}
+			return a.Rate < b.Rate
+		}
+		if a.Period != b.Period {
+			return a.Period < b.Period
+		}
+		return a.ID < b.ID
+	})
+	return s
+}
+
+// isSnapshot reports whether msg is a snapshot model, i.e. a struct with a
+// slice field named Snapshot
+func isSnapshot(msg interface{}) bool {
+	v := reflect.ValueOf(msg)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return false
+	}
+	fv := v.FieldByName("Snapshot")
+	return fv.IsValid() && fv.Kind() == reflect.Slice
+}
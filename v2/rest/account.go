@@ -0,0 +1,105 @@
+package rest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/summary"
+)
+
+// AccountService manages the account endpoints
+type AccountService struct {
+	requestFactory
+	Synchronous
+	fees *feeCache
+}
+
+// DefaultFeeRefresh is the default interval after which the cached account
+// summary of SymbolFees is fetched again
+const DefaultFeeRefresh = time.Hour
+
+// SymbolFees holds the fee rates of the account for a trading symbol.
+// Negative rates are rebates.
+type SymbolFees struct {
+	Symbol string
+	Maker  float64
+	Taker  float64
+}
+
+type feeCache struct {
+	mu       sync.Mutex
+	interval time.Duration
+	fetched  time.Time
+	calc     *summary.FeeCalculator
+	symbols  map[string]SymbolFees
+}
+
+// Summary - retrieves the fee rates and LEO level of the account
+// see https://docs.bitfinex.com/reference#rest-auth-summary for more info
+func (s *AccountService) Summary() (*summary.Summary, error) {
+	req, err := s.requestFactory.NewAuthenticatedRequest(common.PermissionRead, "summary")
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := s.Request(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return summary.FromRaw(raw, decoding(s.Synchronous)...)
+}
+
+// FeeCalculator - returns a fee calculator based on the current account summary
+func (s *AccountService) FeeCalculator() (*summary.FeeCalculator, error) {
+	sum, err := s.Summary()
+	if err != nil {
+		return nil, err
+	}
+	return summary.NewFeeCalculator(sum), nil
+}
+
+// SetFeeRefreshInterval sets the interval after which the cached account
+// summary is considered stale and fetched again on the next SymbolFees call
+func (s *AccountService) SetFeeRefreshInterval(d time.Duration) {
+	c := s.fees
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interval = d
+}
+
+// SymbolFees returns the maker and taker fee rates of the account for the
+// trading symbol, which depend on whether it is quoted in a crypto, stable
+// or fiat currency, or is a perpetual. The account summary the rates are
+// taken from is cached and refreshed once the refresh interval has elapsed.
+func (s *AccountService) SymbolFees(sym string) (SymbolFees, error) {
+	c := s.fees
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.calc == nil || time.Since(c.fetched) > c.interval {
+		calc, err := s.FeeCalculator()
+		if err != nil {
+			return SymbolFees{}, err
+		}
+		c.calc = calc
+		c.fetched = time.Now()
+		c.symbols = make(map[string]SymbolFees)
+	}
+
+	if f, ok := c.symbols[sym]; ok {
+		return f, nil
+	}
+	maker, err := c.calc.Rate(sym, true)
+	if err != nil {
+		return SymbolFees{}, err
+	}
+	taker, err := c.calc.Rate(sym, false)
+	if err != nil {
+		return SymbolFees{}, err
+	}
+	f := SymbolFees{Symbol: sym, Maker: maker, Taker: taker}
+	c.symbols[sym] = f
+	return f, nil
+}
@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountSummary(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/r/summary", r.RequestURI)
+		assert.Equal(t, "POST", r.Method)
+
+		_, err := w.Write([]byte(`[null,null,null,null,[[0.001,0.001,0.001,null,null,-0.0002],[0.002,0.002,0.002,null,null,0.00075]],null,null,null,null,{"leo_lev":0,"leo_amount_avg":0.002}]`))
+		require.Nil(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	fc, err := NewClientWithURL(server.URL).Account.FeeCalculator()
+	require.Nil(t, err)
+
+	fee, err := fc.EstimateFee("tBTCUSD", 2, 10000, false)
+	require.Nil(t, err)
+	assert.InDelta(t, 40, fee, 1e-9)
+}
+
+func TestSymbolFees(t *testing.T) {
+	var calls int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, err := w.Write([]byte(`[null,null,null,null,[[0.001,0.0008,0.0006,null,null,-0.0002],[0.002,0.0018,0.0016,null,null,0.00075]],null,null,null,null,{"leo_lev":0,"leo_amount_avg":0}]`))
+		require.Nil(t, err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	acc := NewClientWithURL(server.URL).Account
+	cases := map[string]SymbolFees{
+		"tETHBTC":      {Symbol: "tETHBTC", Maker: 0.001, Taker: 0.002},
+		"tBTCUST":      {Symbol: "tBTCUST", Maker: 0.0008, Taker: 0.0018},
+		"tBTCUSD":      {Symbol: "tBTCUSD", Maker: 0.0006, Taker: 0.0016},
+		"tBTCF0:USTF0": {Symbol: "tBTCF0:USTF0", Maker: -0.0002, Taker: 0.00075},
+	}
+	for sym, exp := range cases {
+		f, err := acc.SymbolFees(sym)
+		require.Nil(t, err)
+		assert.Equal(t, exp, f)
+	}
+	_, err := acc.SymbolFees("fUSD")
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// the summary is fetched again once stale
+	acc.SetFeeRefreshInterval(0)
+	_, err = acc.SymbolFees("tBTCUSD")
+	require.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,155 @@
+package rest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/depositaddress"
+)
+
+// depositAddressPageSize is the maximum page size of the deposit address listing
+const depositAddressPageSize = 100
+
+// DepositAddresses retrieves all deposit addresses generated for the method,
+// e.g. bitcoin, not only the newest one. The addresses are recorded in the
+// AddressBook of the service.
+// see https://docs.bitfinex.com/reference#rest-auth-deposit-address-all for more info
+func (ws *WalletService) DepositAddresses(method string) ([]*depositaddress.Address, error) {
+	if method == "" {
+		return nil, fmt.Errorf("%w: missing deposit method", common.ErrBadRequest)
+	}
+
+	var addrs []*depositaddress.Address
+	for page := 1; ; page++ {
+		body := map[string]interface{}{
+			"method":    method,
+			"page":      page,
+			"page_size": depositAddressPageSize,
+		}
+		req, err := ws.requestFactory.NewAuthenticatedRequestWithData(common.PermissionRead, "deposit/address/all", body)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := ws.Request(req)
+		if err != nil {
+			return nil, err
+		}
+		res, err := depositaddress.SnapshotFromRaw(method, raw, decoding(ws.Synchronous)...)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, res...)
+		if len(res) < depositAddressPageSize {
+			break
+		}
+	}
+
+	for _, a := range addrs {
+		ws.AddressBook().Record("", a, false)
+	}
+	return addrs, nil
+}
+
+// AddressRecord is a deposit address seen by the client
+type AddressRecord struct {
+	Method      string
+	Currency    string
+	Address     string
+	PoolAddress string
+	Wallet      string // empty if only seen in the listing of DepositAddresses
+	Created     bool   // returned by CreateDepositAddress
+	FirstSeen   time.Time
+	LastSeen    time.Time
+}
+
+// AddressBook records the deposit addresses returned by DepositAddress,
+// CreateDepositAddress and DepositAddresses, so that deposits can be
+// reconciled with the addresses handed out. Records can be persisted with
+// Records and restored with NewAddressBook.
+type AddressBook struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	records []*AddressRecord
+}
+
+// NewAddressBook returns an address book holding the given records
+func NewAddressBook(records ...AddressRecord) *AddressBook {
+	ab := &AddressBook{now: time.Now}
+	for i := range records {
+		r := records[i]
+		ab.records = append(ab.records, &r)
+	}
+	return ab
+}
+
+// Record adds the address or updates the time it was last seen. The wallet
+// and the created flag of known addresses are only ever filled in.
+func (ab *AddressBook) Record(wallet string, a *depositaddress.Address, created bool) AddressRecord {
+	ab.mu.Lock()
+	defer ab.mu.Unlock()
+
+	now := ab.now()
+	for _, r := range ab.records {
+		if r.Method == a.Method && r.Address == a.Address && r.PoolAddress == a.PoolAddress {
+			r.LastSeen = now
+			if r.Wallet == "" {
+				r.Wallet = wallet
+			}
+			r.Created = r.Created || created
+			return *r
+		}
+	}
+	r := &AddressRecord{
+		Method:      a.Method,
+		Currency:    a.Currency,
+		Address:     a.Address,
+		PoolAddress: a.PoolAddress,
+		Wallet:      wallet,
+		Created:     created,
+		FirstSeen:   now,
+		LastSeen:    now,
+	}
+	ab.records = append(ab.records, r)
+	return *r
+}
+
+// Records returns all records in the order they were first seen
+func (ab *AddressBook) Records() []AddressRecord {
+	ab.mu.Lock()
+	defer ab.mu.Unlock()
+
+	res := make([]AddressRecord, len(ab.records))
+	for i, r := range ab.records {
+		res[i] = *r
+	}
+	return res
+}
+
+// Lookup returns the record of the address, for currencies with a pool
+// address the address is the tag
+func (ab *AddressBook) Lookup(address string) (AddressRecord, bool) {
+	ab.mu.Lock()
+	defer ab.mu.Unlock()
+
+	for _, r := range ab.records {
+		if r.Address == address {
+			return *r, true
+		}
+	}
+	return AddressRecord{}, false
+}
+
+// Match returns the record of the address a deposit was sent to
+func (ab *AddressBook) Match(m Movement2) (AddressRecord, bool) {
+	if m.Amount <= 0 || m.DestinationAddress == "" {
+		return AddressRecord{}, false
+	}
+	return ab.Lookup(m.DestinationAddress)
+}
+
+// AddressBook returns the address book the deposit addresses are recorded in
+func (ws *WalletService) AddressBook() *AddressBook {
+	return ws.addresses
+}
@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressBook(t *testing.T) {
+	var pages []float64
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		switch r.URL.Path {
+		case "/auth/w/deposit/address":
+			assert.Equal(t, "exchange", body["wallet"])
+			addr := "bc1qold"
+			if body["op_renew"] == float64(1) {
+				addr = "bc1qnew"
+			}
+			fmt.Fprintf(w, `[1568738594687,"acc_dep",null,null,[null,"bitcoin","BTC",null,%q,null],null,"SUCCESS","success"]`, addr)
+		case "/auth/r/deposit/address/all":
+			assert.Equal(t, "bitcoin", body["method"])
+			assert.Equal(t, float64(depositAddressPageSize), body["page_size"])
+			page := body["page"].(float64)
+			pages = append(pages, page)
+			rows := []interface{}{}
+			if page == 1 {
+				for i := 0; i < depositAddressPageSize; i++ {
+					rows = append(rows, []interface{}{nil, "BTC", nil, fmt.Sprintf("bc1q%d", i)})
+				}
+			} else {
+				rows = append(rows, []interface{}{nil, "BTC", nil, "bc1qold"})
+			}
+			require.Nil(t, json.NewEncoder(w).Encode(rows))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	ab := c.Wallet.AddressBook()
+	now := time.Unix(1600000000, 0)
+	ab.now = func() time.Time { return now }
+
+	_, err := c.Wallet.DepositAddress("exchange", "bitcoin")
+	require.Nil(t, err)
+	now = now.Add(time.Minute)
+	_, err = c.Wallet.CreateDepositAddress("exchange", "bitcoin")
+	require.Nil(t, err)
+
+	now = now.Add(time.Minute)
+	addrs, err := c.Wallet.DepositAddresses("bitcoin")
+	require.Nil(t, err)
+	assert.Len(t, addrs, depositAddressPageSize+1)
+	assert.Equal(t, []float64{1, 2}, pages)
+
+	records := ab.Records()
+	require.Len(t, records, depositAddressPageSize+2)
+	assert.Equal(t, AddressRecord{
+		Method:    "bitcoin",
+		Currency:  "BTC",
+		Address:   "bc1qold",
+		Wallet:    "exchange",
+		FirstSeen: time.Unix(1600000000, 0),
+		LastSeen:  time.Unix(1600000120, 0),
+	}, records[0])
+	assert.True(t, records[1].Created)
+	assert.Equal(t, "", records[2].Wallet)
+
+	rec, ok := ab.Match(Movement2{Amount: 0.5, DestinationAddress: "bc1qnew"})
+	require.True(t, ok)
+	assert.Equal(t, "exchange", rec.Wallet)
+	_, ok = ab.Match(Movement2{Amount: -0.5, DestinationAddress: "bc1qnew"})
+	assert.False(t, ok, "withdrawals are not matched")
+
+	restored := NewAddressBook(records...)
+	assert.Equal(t, records, restored.Records())
+}
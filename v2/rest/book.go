@@ -1,9 +1,11 @@
 package rest
 
 import (
+	"fmt"
 	"net/url"
 	"path"
 	"strconv"
+	"strings"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
@@ -26,5 +28,41 @@ func (b *BookService) All(symbol string, precision common.BookPrecision, priceLe
 		return nil, err
 	}
 
-	return book.SnapshotFromRaw(symbol, string(precision), convert.ToInterfaceArray(raw), raw)
+	return book.SnapshotFromRaw(symbol, string(precision), convert.ToInterfaceArray(raw), raw, decoding(b.Synchronous)...)
+}
+
+// Funding - retrieve the funding book of the given funding symbol, e.g. fUSD,
+// with the given precision at the given price level. Offers of lenders are
+// asks, requests of borrowers are bids.
+// see https://docs.bitfinex.com/reference#rest-public-books for more info
+func (b *BookService) Funding(symbol string, precision common.BookPrecision, priceLevels int) (*book.FundingSnapshot, error) {
+	if !strings.HasPrefix(symbol, common.FundingPrefix) {
+		return nil, fmt.Errorf("%w: %s is not a funding symbol", common.ErrBadRequest, symbol)
+	}
+
+	req := NewRequestWithMethod(path.Join("book", symbol, string(precision)), "GET")
+	req.Params = make(url.Values)
+	req.Params.Add("len", strconv.Itoa(priceLevels))
+
+	raw, err := b.Request(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return book.FundingSnapshotFromRaw(symbol, string(precision), convert.ToInterfaceArray(raw), raw, decoding(b.Synchronous)...)
+}
+
+// estimateBookLength is the number of price levels fetched by EstimateFill
+const estimateBookLength = 100
+
+// EstimateFill fetches the book of the symbol and walks it for an order of
+// the given side and absolute amount, see book.EstimateFill. Only the first
+// 100 price levels of each side are considered.
+// see https://docs.bitfinex.com/reference#rest-public-books for more info
+func (b *BookService) EstimateFill(symbol string, side common.OrderSide, amount float64) (book.Estimate, error) {
+	snap, err := b.All(symbol, common.Precision0, estimateBookLength)
+	if err != nil {
+		return book.Estimate{}, err
+	}
+	return book.EstimateFill(snap.Snapshot, side, amount), nil
 }
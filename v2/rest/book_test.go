@@ -2,6 +2,7 @@ package rest
 
 import (
 	"bytes"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"testing"
@@ -29,3 +30,59 @@ func TestBookAll(t *testing.T) {
 		t.Fatalf("expected 50 book update entries in snapshot, but got %d", len(ba.Snapshot))
 	}
 }
+
+func TestBookFunding(t *testing.T) {
+	httpDo := func(_ *http.Client, req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/v2/book/fUSD/P0" || req.URL.Query().Get("len") != "25" {
+			t.Errorf("unexpected request %s", req.URL)
+		}
+		msg := `[[0.0002,2,3,-1500.5],[0.00021,30,1,2000]]`
+		resp := http.Response{
+			Body:       ioutil.NopCloser(bytes.NewBufferString(msg)),
+			StatusCode: 200,
+		}
+		return &resp, nil
+	}
+
+	c := NewClientWithHttpDo(httpDo)
+	fb, err := c.Book.Funding("fUSD", common.Precision0, 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fb.Snapshot) != 2 {
+		t.Fatalf("expected 2 funding book entries in snapshot, but got %d", len(fb.Snapshot))
+	}
+	bid, ask := fb.Snapshot[0], fb.Snapshot[1]
+	if bid.Rate != 0.0002 || bid.Period != 2 || bid.Count != 3 || bid.Amount != 1500.5 || bid.Side != common.Bid {
+		t.Fatalf("unexpected bid %+v", bid)
+	}
+	if ask.Rate != 0.00021 || ask.Period != 30 || ask.Amount != 2000 || ask.Side != common.Ask {
+		t.Fatalf("unexpected ask %+v", ask)
+	}
+
+	if _, err := c.Book.Funding("tBTCUSD", common.Precision0, 25); !errors.Is(err, common.ErrBadRequest) {
+		t.Fatalf("expected bad request, got %v", err)
+	}
+}
+
+func TestBookEstimateFill(t *testing.T) {
+	httpDo := func(_ *http.Client, req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/v2/book/tBTCUSD/P0" || req.URL.Query().Get("len") != "100" {
+			t.Errorf("unexpected request %s", req.URL)
+		}
+		msg := `[[10579,1,0.5],[10578,1,1],[10580,3,-1],[10581,1,-2]]`
+		resp := http.Response{
+			Body:       ioutil.NopCloser(bytes.NewBufferString(msg)),
+			StatusCode: 200,
+		}
+		return &resp, nil
+	}
+
+	e, err := NewClientWithHttpDo(httpDo).Book.EstimateFill("tBTCUSD", common.Ask, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.Sufficient || e.BestPrice != 10579 || e.WorstPrice != 10578 || e.AvgPrice != 10578.5 {
+		t.Fatalf("unexpected estimate %+v", e)
+	}
+}
@@ -0,0 +1,151 @@
+package rest
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheClass groups public endpoints that share a cache TTL
+type CacheClass string
+
+const (
+	CacheConf    CacheClass = "conf"    // conf/pub:*
+	CacheTicker  CacheClass = "ticker"  // ticker/* and tickers
+	CacheCandles CacheClass = "candles" // candles/*
+)
+
+// cacheClassOf returns the class of cacheable requests, which are public GET
+// requests of the endpoints covered by a CacheClass
+func cacheClassOf(req Request) (CacheClass, bool) {
+	if req.Method != "GET" {
+		return "", false
+	}
+	switch {
+	case strings.HasPrefix(req.RefURL, "conf/"):
+		return CacheConf, true
+	case strings.HasPrefix(req.RefURL, "ticker/"), req.RefURL == "tickers":
+		return CacheTicker, true
+	case strings.HasPrefix(req.RefURL, "candles/"):
+		return CacheCandles, true
+	}
+	return "", false
+}
+
+type cacheEntry struct {
+	done    chan struct{}
+	raw     []interface{}
+	err     error
+	expires time.Time
+}
+
+// responseCache keeps responses of public requests for the TTL of their class.
+// Concurrent identical requests share a single in-flight request.
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     map[CacheClass]time.Duration
+	now     func() time.Time
+	entries map[string]*cacheEntry
+}
+
+func newResponseCache(ttl map[CacheClass]time.Duration) *responseCache {
+	t := make(map[CacheClass]time.Duration, len(ttl))
+	for k, v := range ttl {
+		t[k] = v
+	}
+	return &responseCache{ttl: t, now: time.Now, entries: map[string]*cacheEntry{}}
+}
+
+func (rc *responseCache) do(req Request, fetch func() ([]interface{}, error)) ([]interface{}, error) {
+	class, ok := cacheClassOf(req)
+	if !ok {
+		return fetch()
+	}
+	ttl := rc.ttl[class]
+	if ttl <= 0 {
+		return fetch()
+	}
+	key := req.RefURL + "?" + req.Params.Encode()
+
+	rc.mu.Lock()
+	if e, ok := rc.entries[key]; ok {
+		select {
+		case <-e.done:
+			if rc.now().Before(e.expires) {
+				rc.mu.Unlock()
+				return e.raw, nil
+			}
+		default:
+			rc.mu.Unlock()
+			select {
+			case <-e.done:
+				return e.raw, e.err
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+		}
+	}
+	e := &cacheEntry{done: make(chan struct{})}
+	rc.entries[key] = e
+	rc.mu.Unlock()
+
+	e.raw, e.err = fetch()
+
+	rc.mu.Lock()
+	now := rc.now()
+	if e.err != nil {
+		delete(rc.entries, key)
+	} else {
+		e.expires = now.Add(ttl)
+	}
+	for k, v := range rc.entries {
+		if v != e && !v.expires.IsZero() && !now.Before(v.expires) {
+			delete(rc.entries, k)
+		}
+	}
+	rc.mu.Unlock()
+	close(e.done)
+
+	return e.raw, e.err
+}
+
+func (rc *responseCache) invalidate() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for k, e := range rc.entries {
+		select {
+		case <-e.done:
+			delete(rc.entries, k)
+		default:
+		}
+	}
+}
+
+// WithCache enables an in-memory cache for public conf, ticker and candle
+// requests, keeping responses for the TTL of their class, e.g.:
+//
+//	c := rest.NewClient().WithCache(map[rest.CacheClass]time.Duration{
+//		rest.CacheConf:   time.Hour,
+//		rest.CacheTicker: time.Second,
+//	})
+//
+// Classes without a TTL are not cached. Identical requests issued while one
+// is in flight wait for and share its response. Cached responses are shared
+// between callers and must not be modified; the raw response handler is only
+// called for responses actually received.
+func (c *Client) WithCache(ttl map[CacheClass]time.Duration) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache = newResponseCache(ttl)
+	return c
+}
+
+// InvalidateCache drops all cached responses
+func (c *Client) InvalidateCache() {
+	c.mu.RLock()
+	cache := c.cache
+	c.mu.RUnlock()
+	if cache != nil {
+		cache.invalidate()
+	}
+}
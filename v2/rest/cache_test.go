@@ -0,0 +1,103 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path == "/tickers" {
+			<-release
+		}
+		w.Write([]byte(`[["tBTCUSD",9000,1,9001,2,10,0.01,9000.5,100,9100,8900]]`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	now := time.Unix(1600000000, 0)
+	c := NewClientWithURL(server.URL).WithCache(map[CacheClass]time.Duration{
+		CacheTicker: time.Second,
+	})
+	c.cache.now = func() time.Time { return now }
+
+	// a burst of identical reads results in a single request
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tick, err := c.Tickers.Get("tBTCUSD")
+			require.Nil(t, err)
+			assert.Equal(t, 9000.5, tick.LastPrice)
+		}()
+	}
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	_, err := c.Tickers.Get("tBTCUSD")
+	require.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// other symbols and expired entries are fetched again
+	_, err = c.Tickers.Get("tETHUSD")
+	require.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	now = now.Add(time.Second)
+	_, err = c.Tickers.Get("tBTCUSD")
+	require.Nil(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	c.InvalidateCache()
+	_, err = c.Tickers.Get("tBTCUSD")
+	require.Nil(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+
+	// classes without a ttl are not cached
+	_, err = c.DoPublic(context.Background(), "conf/pub:list:currency", nil)
+	require.Nil(t, err)
+	_, err = c.DoPublic(context.Background(), "conf/pub:list:currency", nil)
+	require.Nil(t, err)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls))
+}
+
+func TestWithCacheDuringRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[["tBTCUSD",9000,1,9001,2,10,0.01,9000.5,100,9100,8900]]`))
+	}))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, err := c.Tickers.Get("tBTCUSD")
+				assert.Nil(t, err)
+			}
+		}()
+	}
+	c.WithCache(map[CacheClass]time.Duration{CacheTicker: time.Second})
+	c.InvalidateCache()
+	wg.Wait()
+}
@@ -1,10 +1,9 @@
 package rest
 
 import (
+	"context"
 	"fmt"
-	"net/url"
 	"path"
-	"strconv"
 	"strings"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
@@ -19,7 +18,7 @@ type CandleService struct {
 
 func getPathSegments(symbol string, resolution common.CandleResolution) (s string, err error) {
 	if len(symbol) == 0 {
-		err = fmt.Errorf("symbol cannot be empty")
+		err = fmt.Errorf("%w: symbol cannot be empty", common.ErrBadRequest)
 		return
 	}
 
@@ -42,7 +41,7 @@ func (c *CandleService) Last(symbol string, resolution common.CandleResolution)
 		return nil, err
 	}
 
-	cs, err := candle.FromRaw(symbol, resolution, raw)
+	cs, err := candle.FromRaw(symbol, resolution, raw, decoding(c.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +63,7 @@ func (c *CandleService) History(symbol string, resolution common.CandleResolutio
 		return nil, err
 	}
 
-	cs, err := candle.SnapshotFromRaw(symbol, resolution, convert.ToInterfaceArray(raw))
+	cs, err := candle.SnapshotFromRaw(symbol, resolution, convert.ToInterfaceArray(raw), decoding(c.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -82,24 +81,33 @@ func (c *CandleService) HistoryWithQuery(
 	limit common.QueryLimit,
 	sort common.SortOrder,
 ) (*candle.Snapshot, error) {
+	return c.HistoryQuery(symbol, resolution, fullQuery(start, end, limit, sort))
+}
+
+// HistoryQuery - retrieves candles with the given symbol and resolution matching the given query
+// See https://docs.bitfinex.com/reference#rest-public-candles for more info
+func (c *CandleService) HistoryQuery(symbol string, resolution common.CandleResolution, q *Query) (*candle.Snapshot, error) {
+	return c.historyQuery(context.Background(), symbol, resolution, q)
+}
+
+func (c *CandleService) historyQuery(ctx context.Context, symbol string, resolution common.CandleResolution, q *Query) (*candle.Snapshot, error) {
 	segments, err := getPathSegments(symbol, resolution)
 	if err != nil {
 		return nil, err
 	}
 
-	req := NewRequestWithMethod(path.Join("candles", segments, "HIST"), "GET")
-	req.Params = make(url.Values)
-	req.Params.Add("end", strconv.FormatInt(int64(end), 10))
-	req.Params.Add("start", strconv.FormatInt(int64(start), 10))
-	req.Params.Add("limit", strconv.FormatInt(int64(limit), 10))
-	req.Params.Add("sort", strconv.FormatInt(int64(sort), 10))
+	req := NewRequestWithMethod(path.Join("candles", segments, "HIST"), "GET").WithContext(ctx)
+	req.Params = q.params()
 
 	raw, err := c.Request(req)
 	if err != nil {
 		return nil, err
 	}
+	if len(raw) == 0 {
+		return &candle.Snapshot{Snapshot: make([]*candle.Candle, 0)}, nil
+	}
 
-	cs, err := candle.SnapshotFromRaw(symbol, resolution, convert.ToInterfaceArray(raw))
+	cs, err := candle.SnapshotFromRaw(symbol, resolution, convert.ToInterfaceArray(raw), decoding(c.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,142 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/candle"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+)
+
+const (
+	// DefaultBulkWorkers is the default number of parallel requests of
+	// HistoryBulk
+	DefaultBulkWorkers = 8
+	// DefaultBulkRate is the default number of requests per minute of
+	// HistoryBulk, which stays below the limit of the candles endpoint
+	DefaultBulkRate = 30
+	// DefaultBulkRetries is the default number of retries per symbol
+	DefaultBulkRetries = 3
+	// DefaultBulkBackoff is the default delay before the first retry
+	DefaultBulkBackoff = 5 * time.Second
+)
+
+// BulkOptions configures HistoryBulk. Zero values are replaced by the
+// defaults.
+type BulkOptions struct {
+	Workers int // parallel requests
+	Rate    int // requests per minute shared by all workers
+
+	// MaxRetries is the number of retries of requests which were rate
+	// limited or failed in transport, see Retryable
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubled on every further
+	// attempt
+	Backoff time.Duration
+	// Clock times the rate budget and backoffs, defaults to
+	// utils.SystemClock
+	Clock utils.Clock
+}
+
+// CandleErrors maps symbols to the error their download failed with
+type CandleErrors map[string]error
+
+func (e CandleErrors) Error() string {
+	symbols := make([]string, 0, len(e))
+	for s := range e {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+
+	msgs := make([]string, len(symbols))
+	for i, s := range symbols {
+		msgs[i] = fmt.Sprintf("%s: %v", s, e[s])
+	}
+	return fmt.Sprintf("candles of %d symbols failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// HistoryBulk retrieves the candles matching q for all symbols in parallel,
+// e.g. for a screener:
+//
+//	candles, err := c.Candles.HistoryBulk(ctx, symbols, common.OneDay, rest.NewQuery().Limit(30), rest.BulkOptions{})
+//
+// All requests share the rate budget of opts. The candles are returned keyed
+// by symbol. If the download of some symbols fails, the others are returned
+// together with a CandleErrors. Once ctx is done the download stops and the
+// error of ctx is returned.
+func (c *CandleService) HistoryBulk(ctx context.Context, symbols []string, resolution common.CandleResolution, q *Query, opts BulkOptions) (map[string]*candle.Snapshot, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultBulkWorkers
+	}
+	if opts.Rate <= 0 {
+		opts.Rate = DefaultBulkRate
+	}
+	if opts.MaxRetries <= 0 {
+		opts.MaxRetries = DefaultBulkRetries
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultBulkBackoff
+	}
+	if opts.Clock == nil {
+		opts.Clock = utils.SystemClock
+	}
+
+	retrier := Retrier{
+		MaxRetries: opts.MaxRetries,
+		Backoff:    opts.Backoff,
+		Limiter:    NewLimiter(time.Minute/time.Duration(opts.Rate), opts.Clock),
+		Clock:      opts.Clock,
+	}
+	jobs := make(chan string)
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		result = make(map[string]*candle.Snapshot, len(symbols))
+		errs   = make(CandleErrors)
+	)
+
+	for i := 0; i < opts.Workers && i < len(symbols); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for symbol := range jobs {
+				var cs *candle.Snapshot
+				err := retrier.Do(ctx, func() (err error) {
+					cs, err = c.historyQuery(ctx, symbol, resolution, q)
+					return err
+				})
+				mu.Lock()
+				if err != nil {
+					errs[symbol] = err
+				} else {
+					result[symbol] = cs
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, s := range symbols {
+		select {
+		case jobs <- s:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	if len(errs) > 0 {
+		return result, errs
+	}
+	return result, nil
+}
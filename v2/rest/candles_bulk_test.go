@@ -0,0 +1,79 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryBulk(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		// /candles/trade:1D:tBTCUSD/hist
+		symbol := strings.Split(strings.Split(r.URL.Path, "/")[2], ":")[2]
+		mu.Lock()
+		calls[symbol]++
+		n := calls[symbol]
+		mu.Unlock()
+
+		switch {
+		case symbol == "tETHUSD" && n == 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`["error",11010,"ERR_RATE_LIMIT"]`))
+			return
+		case symbol == "tXXXUSD":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`["error",10020,"symbol: invalid"]`))
+			return
+		case symbol == "tBADUSD":
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`bad gateway`))
+			return
+		case symbol == "tODDUSD":
+			_, _ = w.Write([]byte(`{"candles":[]}`))
+			return
+		}
+		_, err := w.Write([]byte(`[[1594080000000,9300,9350,9400,9250,120.5],[1593993600000,9200,9300,9310,9150,98.1]]`))
+		require.Nil(t, err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	symbols := []string{"tBTCUSD", "tETHUSD", "tXXXUSD", "tBADUSD", "tODDUSD", "tLTCUSD"}
+	opts := BulkOptions{Workers: 3, Rate: 60000, MaxRetries: 2, Backoff: time.Millisecond}
+	result, err := c.Candles.HistoryBulk(context.Background(), symbols, common.OneDay, NewQuery().Limit(2), opts)
+	require.NotNil(t, err)
+
+	var errs CandleErrors
+	require.True(t, errors.As(err, &errs))
+	assert.Len(t, errs, 3)
+	assert.True(t, errors.Is(errs["tXXXUSD"], common.ErrBadRequest))
+	assert.NotNil(t, errs["tBADUSD"])
+	assert.NotNil(t, errs["tODDUSD"])
+
+	require.Len(t, result, 3)
+	for _, s := range []string{"tBTCUSD", "tETHUSD", "tLTCUSD"} {
+		require.Len(t, result[s].Snapshot, 2)
+		assert.Equal(t, s, result[s].Snapshot[0].Symbol)
+		assert.Equal(t, 9350.0, result[s].Snapshot[0].Close)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 2, calls["tETHUSD"])
+	assert.Equal(t, 1, calls["tXXXUSD"])
+	assert.Equal(t, 3, calls["tBADUSD"])
+	// an undecodable response is not repeated
+	assert.Equal(t, 1, calls["tODDUSD"])
+}
@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/candle"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+)
+
+// backfillLimit is the page size of the requests of RepairGaps
+const backfillLimit = 10000
+
+// RepairOptions configures RepairGaps. Zero values of the request options are
+// replaced by the defaults of HistoryBulk.
+type RepairOptions struct {
+	// Backfill fetches the candles of every gap again, which recovers
+	// candles missed e.g. due to maintenance
+	Backfill bool
+	// ForwardFill fills the candles still missing with the close of the
+	// previous candle, as the API sends no candles for periods without
+	// trades
+	ForwardFill bool
+
+	Rate int // backfill requests per minute
+	// MaxRetries is the number of retries of backfill requests which were
+	// rate limited or failed in transport, see Retryable
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubled on every further
+	// attempt
+	Backoff time.Duration
+	// Clock times the rate budget and backoffs, defaults to
+	// utils.SystemClock
+	Clock utils.Clock
+}
+
+// RepairGaps detects missing candles in a series fetched before, see
+// candle.Gaps, and repairs them as configured by opts. It returns the
+// repaired series sorted by time along with a report of the gaps, whose
+// Backfilled and ForwardFilled counts tell how many of their candles were
+// repaired.
+func (c *CandleService) RepairGaps(ctx context.Context, symbol string, resolution common.CandleResolution, candles []*candle.Candle, opts RepairOptions) ([]*candle.Candle, []candle.Gap, error) {
+	gaps, err := candle.Gaps(candles, resolution)
+	if err != nil {
+		return nil, nil, err
+	}
+	period, _ := resolution.Duration()
+
+	series := make([]*candle.Candle, len(candles))
+	copy(series, candles)
+	if opts.Backfill {
+		retrier := opts.retrier()
+		for i := range gaps {
+			fetched, err := c.backfill(ctx, symbol, resolution, gaps[i], retrier)
+			if err != nil {
+				return nil, nil, err
+			}
+			gaps[i].Backfilled = len(fetched)
+			series = append(series, fetched...)
+		}
+	}
+
+	if opts.ForwardFill {
+		series, _, err = candle.ForwardFill(series, resolution)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range gaps {
+			gaps[i].ForwardFilled = gaps[i].Count(period) - gaps[i].Backfilled
+		}
+		return series, gaps, nil
+	}
+
+	// backfilled candles lie within the gaps, so sorting suffices
+	sort.SliceStable(series, func(i, j int) bool { return series[i].MTS < series[j].MTS })
+	return series, gaps, nil
+}
+
+// retrier returns the retrier of the backfill requests
+func (opts RepairOptions) retrier() Retrier {
+	if opts.Rate <= 0 {
+		opts.Rate = DefaultBulkRate
+	}
+	if opts.MaxRetries <= 0 {
+		opts.MaxRetries = DefaultBulkRetries
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultBulkBackoff
+	}
+	if opts.Clock == nil {
+		opts.Clock = utils.SystemClock
+	}
+	return Retrier{
+		MaxRetries: opts.MaxRetries,
+		Backoff:    opts.Backoff,
+		Limiter:    NewLimiter(time.Minute/time.Duration(opts.Rate), opts.Clock),
+		Clock:      opts.Clock,
+	}
+}
+
+// backfill fetches the candles of the gap, page by page
+func (c *CandleService) backfill(ctx context.Context, symbol string, resolution common.CandleResolution, g candle.Gap, retrier Retrier) ([]*candle.Candle, error) {
+	fetched := []*candle.Candle{}
+	pager := NewPager(NewQuery().FromMts(common.Mts(g.From)).ToMts(common.Mts(g.To)).SortAsc(), backfillLimit)
+	for !pager.Done() {
+		var cs *candle.Snapshot
+		err := retrier.Do(ctx, func() (err error) {
+			cs, err = c.historyQuery(ctx, symbol, resolution, pager.Query())
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		// candles are unique per timestamp, which thus serves as their id
+		for _, i := range pager.Page(len(cs.Snapshot), func(i int) (int64, int64) {
+			return cs.Snapshot[i].MTS, cs.Snapshot[i].MTS
+		}) {
+			if cd := cs.Snapshot[i]; cd.MTS >= g.From && cd.MTS <= g.To {
+				fetched = append(fetched, cd)
+			}
+		}
+	}
+	return fetched, pager.Err()
+}
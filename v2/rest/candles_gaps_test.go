@@ -0,0 +1,101 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/candle"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairGaps(t *testing.T) {
+	const m = int64(60000)
+	queries := []string{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/candles/trade:1m:tBTCUSD/HIST", r.URL.Path)
+		queries = append(queries, r.URL.RawQuery)
+		// the candle at 2m is recovered, the one at 3m had no trades
+		_, _ = w.Write([]byte(fmt.Sprintf(`[[%d,10,11,12,9,1.5]]`, 2*m)))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	at := func(i int64, cls float64) *candle.Candle {
+		return &candle.Candle{Symbol: "tBTCUSD", Resolution: common.OneMinute, MTS: i * m, Open: cls, Close: cls, High: cls, Low: cls, Volume: 1}
+	}
+	series := []*candle.Candle{at(4, 13), at(0, 10), at(1, 10)}
+
+	c := NewClientWithURL(server.URL)
+	repaired, gaps, err := c.Candles.RepairGaps(context.Background(), "tBTCUSD", common.OneMinute, series, RepairOptions{Backfill: true, ForwardFill: true})
+	require.Nil(t, err)
+	assert.Equal(t, []string{fmt.Sprintf("end=%d&limit=10000&sort=1&start=%d", 3*m, 2*m)}, queries)
+	assert.Equal(t, []candle.Gap{{From: 2 * m, To: 3 * m, Backfilled: 1, ForwardFilled: 1}}, gaps)
+	require.Len(t, repaired, 5)
+	for i, cd := range repaired {
+		assert.Equal(t, int64(i)*m, cd.MTS)
+	}
+	assert.Equal(t, 1.5, repaired[2].Volume)
+	assert.Equal(t, 11.0, repaired[3].Open)
+	assert.Equal(t, 0.0, repaired[3].Volume)
+
+	// the report tells what is left without forward filling
+	repaired, gaps, err = c.Candles.RepairGaps(context.Background(), "tBTCUSD", common.OneMinute, series, RepairOptions{Backfill: true})
+	require.Nil(t, err)
+	assert.Len(t, repaired, 4)
+	assert.False(t, gaps[0].Repaired(time.Minute))
+	assert.Equal(t, 13.0, series[0].Close, "input is not modified")
+}
+
+func TestRepairGapsPages(t *testing.T) {
+	const m = int64(60000)
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`["error",11010,"ERR_RATE_LIMIT"]`))
+			return
+		}
+		start, err := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+		require.Nil(t, err)
+		end, err := strconv.ParseInt(r.URL.Query().Get("end"), 10, 64)
+		require.Nil(t, err)
+		limit, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64)
+		require.Nil(t, err)
+
+		rows := []string{}
+		for mts := start; mts <= end && int64(len(rows)) < limit; mts += m {
+			rows = append(rows, fmt.Sprintf(`[%d,10,10,10,10,1]`, mts))
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(rows, ",") + "]"))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	// the gap spans more candles than fit into a page
+	const last = int64(2*backfillLimit + 2)
+	series := []*candle.Candle{
+		{Symbol: "tBTCUSD", Resolution: common.OneMinute, MTS: 0, Close: 10},
+		{Symbol: "tBTCUSD", Resolution: common.OneMinute, MTS: last * m, Close: 10},
+	}
+
+	c := NewClientWithURL(server.URL)
+	opts := RepairOptions{Backfill: true, Rate: 60000, Backoff: time.Millisecond}
+	repaired, gaps, err := c.Candles.RepairGaps(context.Background(), "tBTCUSD", common.OneMinute, series, opts)
+	require.Nil(t, err)
+	assert.Equal(t, 4, calls, "rate limited request is retried, then three pages")
+	assert.Equal(t, int(last-1), gaps[0].Backfilled)
+	require.Len(t, repaired, int(last+1))
+	for i, cd := range repaired {
+		assert.Equal(t, int64(i)*m, cd.MTS)
+	}
+}
@@ -1,17 +1,20 @@
 package rest
 
 import (
-	"crypto/hmac"
-	"crypto/sha512"
-	"encoding/hex"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/auth"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
 )
 
@@ -27,11 +30,92 @@ type Synchronous interface {
 	Request(request Request) ([]interface{}, error)
 }
 
+// rawSynchronous is implemented by transports that are able to hand out the
+// undecoded response body, such as HttpTransport.
+type rawSynchronous interface {
+	RequestRaw(request Request) ([]byte, []interface{}, error)
+}
+
+// RawResponse holds the exact payload returned by the exchange for a request
+// together with the generic JSON structure the typed models are built from.
+type RawResponse struct {
+	Request Request
+	Body    []byte
+	Data    []interface{}
+}
+
+// RawResponseHandler receives the raw payload of every successful response
+// before it is decoded into the typed models.
+type RawResponseHandler func(*RawResponse)
+
+// RequestInfo describes a completed request and is handed to request hooks,
+// e.g. to feed metrics or tracing systems.
+type RequestInfo struct {
+	ID       string
+	Method   string
+	RefURL   string
+	Duration time.Duration
+	Err      error
+}
+
+// RequestHook is called once for every request executed by the client.
+type RequestHook func(RequestInfo)
+
+// RequestError wraps any error returned while executing a request with the
+// ID of that request.
+type RequestError struct {
+	RequestID string
+	Err       error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("request %s: %s", e.RequestID, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// notificationFromRaw parses the notification returned by a write endpoint.
+// Notifications of rejected requests are returned as *notification.Error,
+// which carries the notification and the text of the exchange.
+func notificationFromRaw(raw []interface{}, opts ...convert.DecodeOptions) (*notification.Notification, error) {
+	n, err := notification.FromRaw(raw, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.Err(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Client is safe for concurrent use by multiple goroutines. Requests signed
+// with a nonce are sent one at a time per key in nonce order, as the api
+// rejects a nonce lower than the last one received for the key. A request
+// waiting for its turn gives up once its context is done. Configure several
+// keys with Keys to send authenticated requests in parallel.
 type Client struct {
 	// base members for synchronous API
-	apiKey    string
-	apiSecret string
-	nonce     utils.NonceGenerator
+	credentials auth.CredentialsProvider
+	nonce       utils.NonceGenerator
+
+	// mu guards the configuration set by the builder methods
+	mu sync.RWMutex
+	// nonce order of the requests by api key
+	ordersMu sync.Mutex
+	orders   map[string]*nonceOrder
+	keys     *keyPool
+	// scheduler spaces the requests when a rate limit is set
+	scheduler *scheduler
+
+	onRawResponse RawResponseHandler
+	onRequest     RequestHook
+	requestID     func() string
+	cache         *responseCache
+	stats         *requestStats
+	maintenance   maintenanceState
+	decode        *convert.DecodeOptions
 
 	// service providers
 	Candles        CandleService
@@ -52,6 +136,7 @@ type Client struct {
 	Pulse          PulseService
 	Invoice        InvoiceService
 	Market         MarketService
+	Account        AccountService
 
 	Synchronous
 }
@@ -108,17 +193,21 @@ func NewClientWithSynchronousURLNonce(sync Synchronous, url string, nonce utils.
 	c := &Client{
 		Synchronous: sync,
 		nonce:       nonce,
+		requestID:   utils.NewRequestID,
+		stats:       newRequestStats(),
 	}
+	c.maintenance.poll = DefaultMaintenancePoll
+	c.maintenance.stop = make(chan struct{})
 	c.Orders = OrderService{Synchronous: c, requestFactory: c}
 	c.Book = BookService{Synchronous: c}
 	c.Candles = CandleService{Synchronous: c}
 	c.Trades = TradeService{Synchronous: c, requestFactory: c}
 	c.Tickers = TickerService{Synchronous: c, requestFactory: c}
 	c.TickersHistory = TickerHistoryService{Synchronous: c, requestFactory: c}
-	c.Currencies = CurrenciesService{Synchronous: c, requestFactory: c}
+	c.Currencies = CurrenciesService{Synchronous: c, requestFactory: c, info: &currencyInfoCache{interval: DefaultCurrencyInfoRefresh}}
 	c.Platform = PlatformService{Synchronous: c}
 	c.Positions = PositionService{Synchronous: c, requestFactory: c}
-	c.Wallet = WalletService{Synchronous: c, requestFactory: c}
+	c.Wallet = WalletService{Synchronous: c, requestFactory: c, guard: newIdempotencyGuard(), addresses: NewAddressBook()}
 	c.Ledgers = LedgerService{Synchronous: c, requestFactory: c}
 	c.Stats = StatsService{Synchronous: c, requestFactory: c}
 	c.Status = StatusService{Synchronous: c, requestFactory: c}
@@ -127,23 +216,247 @@ func NewClientWithSynchronousURLNonce(sync Synchronous, url string, nonce utils.
 	c.Pulse = PulseService{Synchronous: c, requestFactory: c}
 	c.Invoice = InvoiceService{Synchronous: c, requestFactory: c}
 	c.Market = MarketService{Synchronous: c, requestFactory: c}
+	c.Account = AccountService{Synchronous: c, requestFactory: c, fees: &feeCache{interval: DefaultFeeRefresh}}
 	return c
 }
 
 // Set the clients credentials in order to make authenticated requests
 func (c *Client) Credentials(key string, secret string) *Client {
-	c.apiKey = key
-	c.apiSecret = secret
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.credentials = nil
+	if key != "" || secret != "" {
+		c.credentials = auth.StaticCredentials(key, secret)
+	}
+	return c
+}
+
+// CredentialsProvider sets the provider of the credentials, which is asked for
+// every authenticated request. This allows rotating keys kept in a secret store
+// without recreating the client.
+func (c *Client) CredentialsProvider(p auth.CredentialsProvider) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.credentials = p
+	return c
+}
+
+// OnRawResponse registers a handler that is called with the raw response of
+// every successful request, allowing the exact exchange payload to be logged
+// or inspected for fields the models do not cover yet.
+func (c *Client) OnRawResponse(handler RawResponseHandler) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onRawResponse = handler
+	return c
+}
+
+// StrictDecoding fails the requests of this client with a *convert.DecodeError
+// if the response contains a field of unexpected type, rather than
+// zero-filling it. onAnomaly, if set, receives every mismatch including
+// non-fatal ones. Other clients keep decoding leniently.
+func (c *Client) StrictDecoding(onAnomaly func(convert.Anomaly)) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.decode = &convert.DecodeOptions{Strict: true, OnAnomaly: onAnomaly}
+	return c
+}
+
+// decodeOptions returns the options set by StrictDecoding to pass to the
+// parsers, none to use the process default
+func (c *Client) decodeOptions() []convert.DecodeOptions {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.decode == nil {
+		return nil
+	}
+	return []convert.DecodeOptions{*c.decode}
+}
+
+// decoding returns the decode options of the client behind a service
+func decoding(s Synchronous) []convert.DecodeOptions {
+	if c, ok := s.(*Client); ok {
+		return c.decodeOptions()
+	}
+	return nil
+}
+
+// OnRequest registers a hook that is called after every request with its ID,
+// duration and outcome.
+func (c *Client) OnRequest(hook RequestHook) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onRequest = hook
 	return c
 }
 
+// WithRequestIDGenerator replaces the default random request ID generator,
+// allowing IDs of an external tracing system to be used instead.
+func (c *Client) WithRequestIDGenerator(gen func() string) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requestID = gen
+	return c
+}
+
+// Request executes the given request using the underlying synchronous transport.
+// Requests without an ID are assigned one, which is attached to returned errors
+// and passed on to the registered hooks.
+func (c *Client) Request(req Request) ([]interface{}, error) {
+	c.mu.RLock()
+	requestID, onRequest, cache := c.requestID, c.onRequest, c.cache
+	c.mu.RUnlock()
+
+	if req.ID == "" {
+		req.ID = requestID()
+	}
+
+	start := time.Now()
+	var raw []interface{}
+	// write requests may be held back during maintenance
+	err := c.awaitMaintenance(req)
+	if err == nil {
+		if cache != nil {
+			raw, err = cache.do(req, func() ([]interface{}, error) { return c.request(req) })
+		} else {
+			raw, err = c.request(req)
+		}
+		c.observeMaintenance(req, raw, err)
+	}
+	info := RequestInfo{
+		ID:       req.ID,
+		Method:   req.Method,
+		RefURL:   req.RefURL,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	c.stats.record(info)
+	if onRequest != nil {
+		onRequest(info)
+	}
+	if err != nil {
+		return nil, &RequestError{RequestID: req.ID, Err: err}
+	}
+	return raw, nil
+}
+
+func (c *Client) request(req Request) ([]interface{}, error) {
+	if req.signing != nil {
+		return c.requestSigned(req)
+	}
+	if err := c.awaitTurn(req); err != nil {
+		return nil, err
+	}
+	return c.send(req)
+}
+
+// awaitTurn blocks until the rate limit of the client, if any, allows the
+// request to be sent
+func (c *Client) awaitTurn(req Request) error {
+	c.mu.RLock()
+	s := c.scheduler
+	c.mu.RUnlock()
+	if s == nil {
+		return nil
+	}
+	return s.acquire(req.Context(), priorityOf(req))
+}
+
+// requestSigned sends a request signed with a nonce in nonce order of its
+// key. With several keys, requests which were rate limited or rejected for
+// the key are sent again with another key.
+func (c *Client) requestSigned(req Request) ([]interface{}, error) {
+	c.mu.RLock()
+	keys := c.keys
+	c.mu.RUnlock()
+
+	for attempt := 1; ; attempt++ {
+		if err := c.awaitTurn(req); err != nil {
+			return nil, err
+		}
+		order := req.signing.order
+		if err := order.acquire(req.Context()); err != nil {
+			return nil, err
+		}
+		var raw []interface{}
+		resigned, err := c.resign(req)
+		if err == nil {
+			req = resigned
+			raw, err = c.send(req)
+		}
+		order.release()
+
+		if keys == nil || !keys.report(req.signing.creds.Key, err) || attempt >= keys.size() {
+			return raw, err
+		}
+		creds, kerr := keys.Credentials(req.Context())
+		if kerr != nil {
+			return raw, err
+		}
+		if req, err = c.sign(req, req.signing.path, creds); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) send(req Request) ([]interface{}, error) {
+	c.mu.RLock()
+	onRawResponse := c.onRawResponse
+	c.mu.RUnlock()
+	if onRawResponse == nil {
+		return c.Synchronous.Request(req)
+	}
+
+	rr := &RawResponse{Request: req}
+	if rs, ok := c.Synchronous.(rawSynchronous); ok {
+		body, raw, err := rs.RequestRaw(req)
+		if err != nil {
+			return nil, err
+		}
+		rr.Body, rr.Data = body, raw
+	} else {
+		raw, err := c.Synchronous.Request(req)
+		if err != nil {
+			return nil, err
+		}
+		// transport does not expose the body, so re-encode what was decoded
+		body, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("encoding raw response: %w", err)
+		}
+		rr.Body, rr.Data = body, raw
+	}
+
+	onRawResponse(rr)
+	return rr.Data, nil
+}
+
 // Request is a wrapper for standard http.Request.  Default method is POST with no data.
 type Request struct {
+	ID      string     // request id used for correlation
 	RefURL  string     // ref url
 	Data    []byte     // body data
 	Method  string     // http method
 	Params  url.Values // query parameters
 	Headers map[string]string
+
+	ctx     context.Context
+	signing *signing
+}
+
+// WithContext returns a copy of the request bound to the given context, which
+// is used to cancel the underlying http request.
+func (r Request) WithContext(ctx context.Context) Request {
+	r.ctx = ctx
+	return r
+}
+
+// Context returns the context of the request, defaulting to context.Background.
+func (r Request) Context() context.Context {
+	if r.ctx != nil {
+		return r.ctx
+	}
+	return context.Background()
 }
 
 // Response is a wrapper for standard http.Response and provides more methods.
@@ -152,13 +465,102 @@ type Response struct {
 	Body     []byte
 }
 
-func (c *Client) sign(msg string) (string, error) {
-	sig := hmac.New(sha512.New384, []byte(c.apiSecret))
-	_, err := sig.Write([]byte(msg))
+func (c *Client) currentCredentials(ctx context.Context) (auth.Credentials, error) {
+	c.mu.RLock()
+	provider := c.credentials
+	c.mu.RUnlock()
+	if provider == nil {
+		// unauthenticated clients send unsigned requests, which the api rejects
+		return auth.Credentials{}, nil
+	}
+	return provider.Credentials(ctx)
+}
+
+// nonceOrder keeps the requests signed with a key in nonce order
+type nonceOrder struct {
+	signMu  sync.Mutex    // guards seq
+	seq     uint64        // sequence number of the last nonce issued for the key
+	sending chan struct{} // holds a token while a request of the key is in flight
+}
+
+// acquire waits until no other request of the key is in flight, or until ctx
+// is done, so a stalled request does not hold up callers beyond their own
+// deadline
+func (o *nonceOrder) acquire(ctx context.Context) error {
+	select {
+	case o.sending <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (o *nonceOrder) release() {
+	<-o.sending
+}
+
+func (c *Client) nonceOrder(key string) *nonceOrder {
+	c.ordersMu.Lock()
+	defer c.ordersMu.Unlock()
+	if c.orders == nil {
+		c.orders = make(map[string]*nonceOrder)
+	}
+	o, ok := c.orders[key]
+	if !ok {
+		o = &nonceOrder{sending: make(chan struct{}, 1)}
+		c.orders[key] = o
+	}
+	return o
+}
+
+// signing holds what is needed to sign a request again with a newer nonce
+type signing struct {
+	path  string
+	creds auth.Credentials
+	order *nonceOrder
+	seq   uint64 // sequence number of the nonce of the request
+}
+
+// sign sets the nonce and signature headers of the request. The headers are
+// copied, so requests sharing the map are not affected.
+func (c *Client) sign(req Request, path string, creds auth.Credentials) (Request, error) {
+	order := c.nonceOrder(creds.Key)
+	order.signMu.Lock()
+	defer order.signMu.Unlock()
+	return c.signLocked(req, path, creds, order)
+}
+
+func (c *Client) signLocked(req Request, path string, creds auth.Credentials, order *nonceOrder) (Request, error) {
+	nonce := c.nonce.GetNonce()
+	sig, err := creds.Sign(SigningPayload(path, nonce, req.Data))
 	if err != nil {
-		return "", nil
+		return Request{}, err
 	}
-	return hex.EncodeToString(sig.Sum(nil)), nil
+	order.seq++
+
+	headers := make(map[string]string, len(req.Headers)+3)
+	for k, v := range req.Headers {
+		headers[k] = v
+	}
+	headers["bfx-nonce"] = nonce
+	headers["bfx-signature"] = sig
+	headers["bfx-apikey"] = creds.Key
+	req.Headers = headers
+	req.signing = &signing{path: path, creds: creds, order: order, seq: order.seq}
+	return req, nil
+}
+
+// resign signs the request again if a nonce was issued for its key after its
+// own, e.g. by another goroutine, which would make the api reject it. It is
+// called while the request holds the nonceOrder of its key.
+func (c *Client) resign(req Request) (Request, error) {
+	s := req.signing
+	s.order.signMu.Lock()
+	defer s.order.signMu.Unlock()
+	if s.seq == s.order.seq {
+		return req, nil
+	}
+	return c.signLocked(req, s.path, s.creds, s.order)
 }
 
 // Create a new authenticated GET request with the given permission type and endpoint url
@@ -174,18 +576,18 @@ func (c *Client) NewAuthenticatedRequest(permissionType common.PermissionType, r
 func (c *Client) NewAuthenticatedRequestWithBytes(permissionType common.PermissionType, refURL string, data []byte) (Request, error) {
 	authURL := fmt.Sprintf("auth/%s/%s", string(permissionType), refURL)
 	req := NewRequestWithBytes(authURL, data)
-	nonce := c.nonce.GetNonce()
-	msg := "/api/v2/" + authURL + nonce + string(data)
-	sig, err := c.sign(msg)
+	creds, err := c.currentCredentials(req.Context())
 	if err != nil {
 		return Request{}, err
 	}
 	req.Headers["Content-Type"] = "application/json"
 	req.Headers["Accept"] = "application/json"
-	req.Headers["bfx-nonce"] = nonce
-	req.Headers["bfx-signature"] = sig
-	req.Headers["bfx-apikey"] = c.apiKey
-	return req, nil
+	if creds.Token != "" {
+		req.Headers["bfx-token"] = creds.Token
+		return req, nil
+	}
+
+	return c.sign(req, authURL, creds)
 }
 
 // Create a new authenticated POST request with the given permission type,endpoint url and data (map[string]interface{}) as the body
@@ -194,7 +596,7 @@ func (c *Client) NewAuthenticatedRequestWithBytes(permissionType common.Permissi
 func (c *Client) NewAuthenticatedRequestWithData(permissionType common.PermissionType, refURL string, data map[string]interface{}) (Request, error) {
 	b, err := json.Marshal(data)
 	if err != nil {
-		return Request{}, err
+		return Request{}, fmt.Errorf("encoding request payload: %w", err)
 	}
 	return c.NewAuthenticatedRequestWithBytes(permissionType, refURL, b)
 }
@@ -218,7 +620,7 @@ func NewRequestWithBytes(refURL string, data []byte) Request {
 func NewRequestWithData(refURL string, data map[string]interface{}) (Request, error) {
 	b, err := json.Marshal(data)
 	if err != nil {
-		return Request{}, err
+		return Request{}, fmt.Errorf("encoding request payload: %w", err)
 	}
 	return NewRequestWithDataMethod(refURL, b, "POST"), nil
 }
@@ -296,6 +698,16 @@ func checkResponse(r *Response) error {
 	return errorResponse
 }
 
+// error codes pulled from v2 docs & API usage
+const (
+	ErrorCodeGeneric     int = 10001
+	ErrorCodeParams      int = 10020
+	ErrorCodeAuthFail    int = 10100
+	ErrorCodeAuthNonce   int = 10114
+	ErrorCodeRateLimit   int = 11010
+	ErrorCodeMaintenance int = 20060
+)
+
 // In case if API will wrong response code
 // ErrorResponse will be returned to caller
 type ErrorResponse struct {
@@ -304,6 +716,36 @@ type ErrorResponse struct {
 	Code     int    `json:"code"`
 }
 
+// Unwrap maps the API error code and HTTP status onto one of the common
+// sentinel errors so callers can use errors.Is to classify failures.
+func (r *ErrorResponse) Unwrap() error {
+	switch {
+	case r.Code == ErrorCodeMaintenance:
+		return common.ErrMaintenance
+	case r.Code >= ErrorCodeAuthFail && r.Code <= ErrorCodeAuthNonce:
+		return common.ErrUnauthorized
+	case r.Code == ErrorCodeParams:
+		return common.ErrBadRequest
+	}
+
+	if r.Response == nil || r.Response.Response == nil {
+		return nil
+	}
+
+	switch r.Response.Response.StatusCode {
+	case http.StatusBadRequest:
+		return common.ErrBadRequest
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return common.ErrUnauthorized
+	case http.StatusNotFound:
+		return common.ErrNotFound
+	case http.StatusServiceUnavailable:
+		return common.ErrMaintenance
+	}
+
+	return nil
+}
+
 func (r *ErrorResponse) Error() string {
 	return fmt.Sprintf("%v %v: %d %v (%d)",
 		r.Response.Response.Request.Method,
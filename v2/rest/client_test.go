@@ -0,0 +1,422 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/auth"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorResponseSentinels(t *testing.T) {
+	cases := map[string]struct {
+		status   int
+		body     string
+		expected error
+	}{
+		"auth failure": {
+			status:   http.StatusInternalServerError,
+			body:     `["error",10100,"apikey: invalid"]`,
+			expected: common.ErrUnauthorized,
+		},
+		"invalid params": {
+			status:   http.StatusInternalServerError,
+			body:     `["error",10020,"symbol: invalid"]`,
+			expected: common.ErrBadRequest,
+		},
+		"maintenance": {
+			status:   http.StatusInternalServerError,
+			body:     `["error",20060,"maintenance"]`,
+			expected: common.ErrMaintenance,
+		},
+		"not found status": {
+			status:   http.StatusNotFound,
+			body:     `not found`,
+			expected: common.ErrNotFound,
+		},
+		"service unavailable status": {
+			status:   http.StatusServiceUnavailable,
+			body:     `["error",10001,"unavailable"]`,
+			expected: common.ErrMaintenance,
+		},
+	}
+
+	for k, v := range cases {
+		t.Run(k, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(v.status)
+				_, err := w.Write([]byte(v.body))
+				require.Nil(t, err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(handler))
+			defer server.Close()
+
+			_, err := NewClientWithURL(server.URL).Platform.Status()
+			require.NotNil(t, err)
+			assert.True(t, errors.Is(err, v.expected), "got %v", err)
+
+			var errResp *ErrorResponse
+			assert.True(t, errors.As(err, &errResp))
+		})
+	}
+
+	t.Run("validation errors wrap ErrBadRequest", func(t *testing.T) {
+		_, err := NewClient().Candles.Last("", common.OneMinute)
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+	})
+}
+
+func TestOnRawResponse(t *testing.T) {
+	body := `[["exchange","UST",19788.6529257,0,19788.6529257,"Exchange 2.0 UST for USD @ 11.696",{"reason":"TRADE"}]]`
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(body))
+		require.Nil(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	var got *RawResponse
+	c := NewClientWithURL(server.URL).OnRawResponse(func(rr *RawResponse) {
+		got = rr
+	})
+
+	ws, err := c.Wallet.Wallet()
+	require.Nil(t, err)
+	require.Len(t, ws.Snapshot, 1)
+
+	require.NotNil(t, got)
+	assert.Equal(t, body, string(got.Body))
+	assert.Equal(t, "auth/r/wallets", got.Request.RefURL)
+	assert.Len(t, got.Data, 1)
+}
+
+func TestRequestIDCorrelation(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, err := w.Write([]byte(`["error",10020,"symbol: invalid"]`))
+		require.Nil(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	var infos []RequestInfo
+	c := NewClientWithURL(server.URL).
+		WithRequestIDGenerator(func() string { return "trace-1" }).
+		OnRequest(func(ri RequestInfo) { infos = append(infos, ri) })
+
+	_, err := c.Platform.Status()
+	require.NotNil(t, err)
+
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	assert.Equal(t, "trace-1", reqErr.RequestID)
+	assert.Contains(t, err.Error(), "trace-1")
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	require.Len(t, infos, 1)
+	assert.Equal(t, "trace-1", infos[0].ID)
+	assert.Equal(t, "platform/status", infos[0].RefURL)
+	assert.NotNil(t, infos[0].Err)
+}
+
+func TestCredentialsProvider(t *testing.T) {
+	var keys []string
+	var tokens []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("bfx-apikey"))
+		tokens = append(tokens, r.Header.Get("bfx-token"))
+		_, err := w.Write([]byte(`[]`))
+		require.Nil(t, err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	var current auth.Credentials
+	c := NewClientWithURL(server.URL).CredentialsProvider(auth.ProviderFunc(func(ctx context.Context) (auth.Credentials, error) {
+		return current, nil
+	}))
+
+	// rotated credentials are used without recreating the client
+	for _, creds := range []auth.Credentials{{Key: "key1", Secret: "secret1"}, {Key: "key2", Secret: "secret2"}, {Token: "token"}} {
+		current = creds
+		req, err := c.NewAuthenticatedRequest(common.PermissionRead, "wallets")
+		require.Nil(t, err)
+		_, err = c.Request(req)
+		require.Nil(t, err)
+	}
+
+	assert.Equal(t, []string{"key1", "key2", ""}, keys)
+	assert.Equal(t, []string{"", "", "token"}, tokens)
+
+	c.CredentialsProvider(auth.ProviderFunc(func(ctx context.Context) (auth.Credentials, error) {
+		return auth.Credentials{}, errors.New("vault unavailable")
+	}))
+	_, err := c.NewAuthenticatedRequest(common.PermissionRead, "wallets")
+	assert.NotNil(t, err)
+}
+
+func TestPing(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/platform/status", r.URL.Path)
+		_, err := w.Write([]byte(`[0]`))
+		require.Nil(t, err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	l, err := NewClientWithURL(server.URL).Ping(context.Background())
+	require.Nil(t, err)
+	assert.False(t, l.Operative)
+	assert.True(t, l.RoundTrip > 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewClientWithURL(server.URL).Ping(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestRequestStats(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/r/orders/tBTCUSD/hist" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err := w.Write([]byte(`["error",10020,"symbol: invalid"]`))
+			require.Nil(t, err)
+			return
+		}
+		_, err := w.Write([]byte(`[1]`))
+		require.Nil(t, err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	for i := 0; i < 2; i++ {
+		_, err := c.Platform.Status()
+		require.Nil(t, err)
+	}
+	_, err := c.Request(NewRequestWithMethod("auth/r/orders/tBTCUSD/hist", "POST"))
+	require.NotNil(t, err)
+
+	stats := c.RequestStats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, int64(2), stats["platform"].Requests)
+	assert.Equal(t, int64(0), stats["platform"].Errors)
+	assert.Nil(t, stats["platform"].LastError)
+
+	orders := stats["auth/r/orders"]
+	assert.Equal(t, int64(1), orders.Requests)
+	assert.Equal(t, int64(1), orders.Errors)
+	assert.True(t, errors.Is(orders.LastError, common.ErrBadRequest))
+	assert.False(t, orders.LastErrorAt.IsZero())
+}
+
+func TestStrictDecoding(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		// the volume is sent as string
+		_, _ = w.Write([]byte(`[1573476747887,7000,7100,7200,6900,"12"]`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	var anomalies []convert.Anomaly
+	strict := NewClientWithURL(server.URL).StrictDecoding(func(a convert.Anomaly) { anomalies = append(anomalies, a) })
+	lenient := NewClientWithURL(server.URL)
+
+	_, err := strict.Candles.Last("tBTCUSD", common.OneMinute)
+	var de *convert.DecodeError
+	require.True(t, errors.As(err, &de))
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, 5, anomalies[0].Index)
+
+	// strict decoding of one client leaves the others lenient
+	c, err := lenient.Candles.Last("tBTCUSD", common.OneMinute)
+	require.Nil(t, err)
+	assert.Equal(t, 7000.0, c.Open)
+	assert.Equal(t, 0.0, c.Volume)
+}
+
+func TestGateMaintenance(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		status = "0"
+		writes int
+	)
+	setStatus := func(s string) {
+		mu.Lock()
+		status = s
+		mu.Unlock()
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/platform/status":
+			_, _ = w.Write([]byte("[" + status + "]"))
+		default:
+			writes++
+			if writes == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`["error",20060,"maintenance"]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL).GateMaintenance(common.MaintenanceReject, 10*time.Millisecond)
+	defer c.Close()
+	write := func(ctx context.Context) error {
+		_, err := c.Request(NewRequestWithMethod("auth/w/order/submit", "POST").WithContext(ctx))
+		return err
+	}
+	writeCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return writes
+	}
+
+	// the failing write starts the maintenance
+	assert.True(t, errors.Is(write(context.Background()), common.ErrMaintenance))
+	assert.True(t, c.InMaintenance())
+
+	// rejected without reaching the api, reads pass
+	assert.True(t, errors.Is(write(context.Background()), common.ErrMaintenance))
+	_, err := c.Platform.Status()
+	require.Nil(t, err)
+	assert.Equal(t, 1, writeCount())
+
+	// the rejecting gate clears once the status poll sees the platform operative
+	setStatus("1")
+	assert.Eventually(t, func() bool { return !c.InMaintenance() }, time.Second, 5*time.Millisecond)
+	require.Nil(t, write(context.Background()))
+	assert.Equal(t, 2, writeCount())
+
+	// queued writes wait for the platform to be operative
+	setStatus("0")
+	_, err = c.Platform.Status()
+	require.Nil(t, err)
+	require.True(t, c.InMaintenance())
+	c.GateMaintenance(common.MaintenanceQueue, 10*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	assert.True(t, errors.Is(write(ctx), common.ErrMaintenance))
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		setStatus("1")
+	}()
+	require.Nil(t, write(context.Background()))
+	assert.False(t, c.InMaintenance())
+	assert.Equal(t, 3, writeCount())
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	creds := auth.Credentials{Key: "key", Secret: "secret"}
+	var mu sync.Mutex
+	var nonces []uint64
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.Nil(t, err)
+		nonce := r.Header.Get("bfx-nonce")
+		sig, err := creds.Sign(SigningPayload(strings.TrimPrefix(r.URL.Path, "/"), nonce, body))
+		require.Nil(t, err)
+		assert.Equal(t, sig, r.Header.Get("bfx-signature"))
+
+		n, err := strconv.ParseUint(nonce, 10, 64)
+		require.Nil(t, err)
+		mu.Lock()
+		nonces = append(nonces, n)
+		mu.Unlock()
+		_, err = w.Write([]byte(`[]`))
+		require.Nil(t, err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL).Credentials(creds.Key, creds.Secret)
+
+	// a request sent after a later one is signed again
+	first, err := c.NewAuthenticatedRequest(common.PermissionRead, "wallets")
+	require.Nil(t, err)
+	second, err := c.NewAuthenticatedRequest(common.PermissionRead, "wallets")
+	require.Nil(t, err)
+	_, err = c.Request(second)
+	require.Nil(t, err)
+	_, err = c.Request(first)
+	require.Nil(t, err)
+	assert.NotEqual(t, first.Headers["bfx-nonce"], strconv.FormatUint(nonces[1], 10))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			req, err := c.NewAuthenticatedRequestWithData(common.PermissionRead, "wallets", map[string]interface{}{"limit": 1})
+			assert.Nil(t, err)
+			// requests sharing the header map are signed on their own copy
+			shared := req
+			go func() {
+				defer wg.Done()
+				_, err := c.Request(shared)
+				assert.Nil(t, err)
+			}()
+			c.OnRequest(func(RequestInfo) {})
+			_, err = c.Request(req)
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, nonces, 42)
+	for i := 1; i < len(nonces); i++ {
+		assert.Greater(t, nonces[i], nonces[i-1])
+	}
+}
+
+func TestSignedRequestWaitHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`[]`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+	defer close(release)
+
+	c := NewClientWithURL(server.URL).Credentials("key", "secret")
+	stalled, err := c.NewAuthenticatedRequest(common.PermissionRead, "wallets")
+	require.Nil(t, err)
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Request(stalled)
+		done <- err
+	}()
+
+	// the next request of the key waits for the stalled one until its deadline
+	time.Sleep(20 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := c.NewAuthenticatedRequest(common.PermissionRead, "wallets")
+	require.Nil(t, err)
+	_, err = c.Request(req.WithContext(ctx))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	release <- struct{}{}
+	assert.Nil(t, <-done)
+}
@@ -0,0 +1,323 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/currency"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecutionupdate"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/symbol"
+)
+
+// ErrSlippage is returned by Convert if the book is too thin to convert the
+// amount within the allowed slippage
+var ErrSlippage = fmt.Errorf("%w: slippage exceeds limit", common.ErrBadRequest)
+
+const (
+	// convertBookDepth is the number of price levels walked by Convert
+	convertBookDepth = 100
+	// convertVia is the currency conversions without a direct pair are
+	// routed through
+	convertVia = "USD"
+	// convertPollInterval is the time between polls for the trades of a
+	// conversion order
+	convertPollInterval = 200 * time.Millisecond
+	// convertPollAttempts limits the polls for the trades of a conversion
+	// order before it is considered unfilled
+	convertPollAttempts = 10
+	// convertTolerance is the relative part of the amount which may remain
+	// unmatched after walking the book due to rounding
+	convertTolerance = 1e-9
+)
+
+// ConversionLeg is an order placed by Convert
+type ConversionLeg struct {
+	Symbol      string
+	OrderID     int64
+	Amount      float64 // signed order amount
+	Expected    float64 // average price estimated from the book
+	Price       float64 // average execution price
+	In          float64 // amount of the sold currency
+	Out         float64 // amount of the bought currency, net of fees
+	Fee         float64
+	FeeCurrency string
+}
+
+// Conversion reports the result of Convert
+type Conversion struct {
+	From     string
+	To       string
+	Amount   float64 // amount of From sold
+	Received float64 // amount of To received, net of fees
+	Legs     []ConversionLeg
+}
+
+// Rate returns the achieved conversion rate in To per From
+func (cv *Conversion) Rate() float64 {
+	if cv.Amount == 0 {
+		return 0
+	}
+	return cv.Received / cv.Amount
+}
+
+type conversionLeg struct {
+	symbol string
+	sell   bool // the sold currency is the base currency of the pair
+}
+
+// Convert exchanges amount of the currency from into the currency to in the
+// exchange wallet, e.g.:
+//
+//	cv, err := c.Convert(ctx, "BTC", "EUR", 0.5, 20)
+//
+// The direct pair is used if listed, otherwise the conversion is routed
+// through USD. Before every order the book is walked to make sure the
+// amount can be converted within maxSlippageBps of the best price. The
+// orders are placed as fill-or-kill at that limit, so that the book moving
+// in between does not exceed it either. If the second leg of a routed
+// conversion fails, the conversion of the first leg is returned with the
+// error.
+func (c *Client) Convert(ctx context.Context, from, to string, amount float64, maxSlippageBps float64) (*Conversion, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return nil, fmt.Errorf("%w: cannot convert %s into itself", common.ErrBadRequest, from)
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", common.ErrBadRequest)
+	}
+	if maxSlippageBps < 0 {
+		return nil, fmt.Errorf("%w: slippage must not be negative", common.ErrBadRequest)
+	}
+
+	legs, err := c.conversionRoute(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	cv := &Conversion{From: from, To: to, Amount: amount}
+	in := amount
+	for _, l := range legs {
+		leg, err := c.convertLeg(ctx, l, in, maxSlippageBps)
+		if err != nil {
+			if len(cv.Legs) == 0 {
+				return nil, err
+			}
+			return cv, err
+		}
+		cv.Legs = append(cv.Legs, *leg)
+		in = leg.Out
+	}
+	cv.Amount = cv.Legs[0].In
+	cv.Received = in
+	return cv, nil
+}
+
+// conversionRoute returns the legs converting from into to
+func (c *Client) conversionRoute(ctx context.Context, from, to string) ([]conversionLeg, error) {
+	req := NewRequestWithMethod(path.Join("conf", string(currency.ExchangeMap)), "GET").WithContext(ctx)
+	raw, err := c.Request(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("data slice too short for pair list: %#v", raw)
+	}
+	list, err := convert.ItfToStrSlice(raw[0])
+	if err != nil {
+		return nil, err
+	}
+	pairs := make(map[[2]string]string, len(list))
+	for _, p := range list {
+		s, err := symbol.ParsePair(p)
+		if err != nil {
+			continue
+		}
+		pairs[[2]string{s.Base, s.Quote}] = s.String()
+	}
+
+	direct := func(from, to string) (conversionLeg, bool) {
+		if s, ok := pairs[[2]string{from, to}]; ok {
+			return conversionLeg{symbol: s, sell: true}, true
+		}
+		if s, ok := pairs[[2]string{to, from}]; ok {
+			return conversionLeg{symbol: s}, true
+		}
+		return conversionLeg{}, false
+	}
+
+	if l, ok := direct(from, to); ok {
+		return []conversionLeg{l}, nil
+	}
+	first, ok1 := direct(from, convertVia)
+	second, ok2 := direct(convertVia, to)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("%w: no pair to convert %s into %s", common.ErrNotFound, from, to)
+	}
+	return []conversionLeg{first, second}, nil
+}
+
+// convertLeg sells in of the sold currency of the leg
+func (c *Client) convertLeg(ctx context.Context, l conversionLeg, in, maxSlippageBps float64) (*ConversionLeg, error) {
+	req := NewRequestWithMethod(path.Join("book", l.symbol, string(common.Precision0)), "GET").WithContext(ctx)
+	req.Params = url.Values{"len": {strconv.Itoa(convertBookDepth)}}
+	raw, err := c.Request(req)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := book.SnapshotFromRaw(l.symbol, string(common.Precision0), convert.ToInterfaceArray(raw), raw, c.decodeOptions()...)
+	if err != nil {
+		return nil, err
+	}
+
+	// sells take the bids, buys the asks, both sorted best first
+	side := common.Ask
+	if l.sell {
+		side = common.Bid
+	}
+	var levels []*book.Book
+	for _, b := range snap.Snapshot {
+		if b.Side == side {
+			levels = append(levels, b)
+		}
+	}
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("%w: empty book of %s", ErrSlippage, l.symbol)
+	}
+
+	// walk the book until in is consumed, in base units for sells and in
+	// quote units for buys
+	remaining, base, quote := in, 0.0, 0.0
+	for _, b := range levels {
+		if remaining <= 0 {
+			break
+		}
+		if l.sell {
+			take := math.Min(remaining, b.Amount)
+			base += take
+			quote += take * b.Price
+			remaining -= take
+		} else {
+			take := math.Min(remaining, b.Amount*b.Price)
+			quote += take
+			base += take / b.Price
+			remaining -= take
+		}
+	}
+	if remaining > convertTolerance*in {
+		return nil, fmt.Errorf("%w: book of %s too thin for %f", ErrSlippage, l.symbol, in)
+	}
+
+	best := levels[0].Price
+	expected := quote / base
+	if math.Abs(expected-best)/best*1e4 > maxSlippageBps {
+		return nil, fmt.Errorf("%w: expected price %f is more than %.1f bps from %f on %s", ErrSlippage, expected, maxSlippageBps, best, l.symbol)
+	}
+
+	// the limit caps the execution price, buys are sized so that they never
+	// spend more than in
+	limit := best * (1 - maxSlippageBps/1e4)
+	amount := -in
+	if !l.sell {
+		limit = best * (1 + maxSlippageBps/1e4)
+		amount = in / limit
+	}
+
+	o, err := c.Orders.PlaceOrder(ctx, &order.NewRequest{
+		Type:   common.OrderTypeExchangeFOK,
+		Symbol: l.symbol,
+		Amount: amount,
+		Price:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	size := math.Abs(o.AmountOrig)
+	if size == 0 {
+		size = math.Abs(amount)
+	}
+	trades, err := c.conversionTrades(ctx, l.symbol, o.ID, size)
+	if err != nil {
+		return nil, err
+	}
+
+	leg := &ConversionLeg{Symbol: l.symbol, OrderID: o.ID, Amount: amount, Expected: expected}
+	s := symbol.MustParse(l.symbol)
+	var filled, value float64
+	for _, t := range trades {
+		filled += math.Abs(t.ExecAmount)
+		value += math.Abs(t.ExecAmount) * t.ExecPrice
+		// fees are reported as negative amounts of the fee currency
+		leg.Fee += t.Fee
+		leg.FeeCurrency = t.FeeCurrency
+	}
+	leg.Price = value / filled
+	if l.sell {
+		leg.In, leg.Out = filled, value
+		if leg.FeeCurrency == s.Quote {
+			leg.Out += leg.Fee
+		}
+	} else {
+		leg.In, leg.Out = value, filled
+		if leg.FeeCurrency == s.Base {
+			leg.Out += leg.Fee
+		}
+	}
+	return leg, nil
+}
+
+// conversionTrades polls the trades of a fill-or-kill order of the given
+// size until they add up to it, as the trades of a fill may be listed one
+// after the other. No trades are listed if the order was killed.
+func (c *Client) conversionTrades(ctx context.Context, symbol string, id int64, size float64) ([]*tradeexecutionupdate.TradeExecutionUpdate, error) {
+	ticker := time.NewTicker(convertPollInterval)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		req, err := c.NewAuthenticatedRequest(common.PermissionRead, path.Join("order", fmt.Sprintf("%s:%d", symbol, id), "trades"))
+		if err != nil {
+			return nil, err
+		}
+		raw, err := c.Request(req.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		var trades []*tradeexecutionupdate.TradeExecutionUpdate
+		filled := 0.0
+		if len(raw) > 0 {
+			snap, err := tradeexecutionupdate.SnapshotFromRaw(raw, c.decodeOptions()...)
+			if err != nil {
+				return nil, err
+			}
+			trades = snap.Snapshot
+			for _, t := range trades {
+				filled += math.Abs(t.ExecAmount)
+			}
+			if size-filled <= math.Max(convertTolerance*size, 1e-8) {
+				return trades, nil
+			}
+		}
+		if i+1 >= convertPollAttempts {
+			if len(trades) > 0 {
+				return nil, fmt.Errorf("trades of order %d on %s add up to %f of %f", id, symbol, filled, size)
+			}
+			return nil, fmt.Errorf("%w: order %d on %s was not filled", ErrSlippage, id, symbol)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	var orders []map[string]interface{}
+	polls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/conf/pub:list:pair:exchange":
+			fmt.Fprint(w, `[["BTCUSD","ETHUSD","EURUSD","ETHBTC"]]`)
+		case "/book/tETHUSD/P0":
+			// bids
+			fmt.Fprint(w, `[[2000,1,1],[1990,1,2],[2010,1,-5]]`)
+		case "/book/tEURUSD/P0":
+			// asks
+			fmt.Fprint(w, `[[1.09,1,1000],[1.1,1,-5000],[1.11,1,-5000]]`)
+		case "/auth/w/order/submit":
+			var o map[string]interface{}
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&o))
+			orders = append(orders, o)
+			fmt.Fprintf(w, `[1611922089073,"on-req",null,null,[[%d,null,null,%q,null,null,%s,%s,"EXCHANGE FOK",null,null,null,0,"ACTIVE",null,null,%s,0,0,0,null,null,null,0,0,null,null,null,"API>BFX",null,null,null]],null,"SUCCESS","Submitting 1 orders."]`,
+				len(orders), o["symbol"], o["amount"], o["amount"], o["price"])
+		case "/auth/r/order/tETHUSD:1/trades":
+			// the second trade of the fill is listed on the next poll
+			if polls++; polls == 1 {
+				fmt.Fprint(w, `[[11,"tETHUSD",1611922089073,1,-1,2000,"EXCHANGE FOK",1985,-1,-4,"USD"]]`)
+				return
+			}
+			fmt.Fprint(w, `[[11,"tETHUSD",1611922089073,1,-1,2000,"EXCHANGE FOK",1985,-1,-4,"USD"],[12,"tETHUSD",1611922089073,1,-0.5,1990,"EXCHANGE FOK",1985,-1,-1.99,"USD"]]`)
+		case "/auth/r/order/tEURUSD:2/trades":
+			amount, _ := strconv.ParseFloat(orders[1]["amount"].(string), 64)
+			fmt.Fprintf(w, `[[13,"tEURUSD",1611922089073,2,%v,1.1,"EXCHANGE FOK",1.1055,-1,-1,"EUR"]]`, amount)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+	c := NewClientWithURL(server.URL).Credentials("key", "secret")
+	ctx := context.Background()
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := c.Convert(ctx, "ETH", "eth", 1, 10)
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+		_, err = c.Convert(ctx, "ETH", "XRP", 1, 10)
+		assert.True(t, errors.Is(err, common.ErrNotFound))
+	})
+
+	t.Run("slippage", func(t *testing.T) {
+		// 1.5 ETH are filled at 1996.67, 16.7 bps below the best bid
+		_, err := c.Convert(ctx, "ETH", "USD", 1.5, 10)
+		assert.True(t, errors.Is(err, ErrSlippage))
+		_, err = c.Convert(ctx, "ETH", "USD", 5, 1000)
+		assert.True(t, errors.Is(err, ErrSlippage), "book too thin")
+		assert.Empty(t, orders)
+	})
+
+	t.Run("via USD", func(t *testing.T) {
+		cv, err := c.Convert(ctx, "ETH", "EUR", 1.5, 50)
+		require.Nil(t, err)
+		require.Len(t, orders, 2)
+		assert.Equal(t, "tETHUSD", orders[0]["symbol"])
+		assert.Equal(t, "-1.5", orders[0]["amount"])
+		assert.Equal(t, "1990", orders[0]["price"])
+		assert.Equal(t, "EXCHANGE FOK", orders[0]["type"])
+		assert.Equal(t, "tEURUSD", orders[1]["symbol"])
+		assert.Equal(t, "1.1055", orders[1]["price"])
+
+		assert.Equal(t, 2, polls)
+		require.Len(t, cv.Legs, 2)
+		assert.InDelta(t, 1996.6667, cv.Legs[0].Price, 1e-4)
+		assert.InDelta(t, 2995-5.99, cv.Legs[0].Out, 1e-9)
+		assert.InDelta(t, 2989.01/1.1055*1.1, cv.Legs[1].In, 1e-9)
+		assert.True(t, cv.Legs[1].In <= cv.Legs[0].Out, "buys never spend more than the proceeds")
+		assert.Equal(t, "EUR", cv.Legs[1].FeeCurrency)
+		assert.Equal(t, 1.5, cv.Amount)
+		assert.InDelta(t, 2989.01/1.1055-1, cv.Received, 1e-9)
+		assert.InDelta(t, cv.Received/1.5, cv.Rate(), 1e-12)
+	})
+}
@@ -1,16 +1,42 @@
 package rest
 
 import (
+	"fmt"
 	"path"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/fixed"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/currency"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/symbol"
 )
 
 // CurrenciesService manages the conf endpoint.
 type CurrenciesService struct {
 	requestFactory
 	Synchronous
+	info *currencyInfoCache
+}
+
+// DefaultCurrencyInfoRefresh is the default interval after which cached
+// currency info is fetched again
+const DefaultCurrencyInfoRefresh = time.Hour
+
+type currencyInfoCache struct {
+	mu       sync.Mutex
+	interval time.Duration
+	fetched  time.Time
+	infos    map[string]*currency.Info
+
+	detailsFetched time.Time
+	details        map[string]*currency.SymbolDetails
+
+	conversionsFetched time.Time
+	conversions        *currency.Conversions
 }
 
 // Conf - retreive currency and symbol service configuration data
@@ -46,10 +72,220 @@ func (cs *CurrenciesService) Conf(label, symbol, unit, explorer, pairs bool) ([]
 	}
 
 	// parse to config object
-	configs, err := currency.FromRaw(parsedRaw)
+	configs, err := currency.FromRaw(parsedRaw, decoding(cs.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
 
 	return configs, nil
 }
+
+// Derivatives retrieves the derivative symbols available for trading, see
+// symbol.Symbol.Underlying for mapping them to their spot pair
+func (cs *CurrenciesService) Derivatives() ([]symbol.Symbol, error) {
+	req := NewRequestWithMethod(path.Join("conf", string(currency.FuturesList)), "GET")
+	raw, err := cs.Request(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("data slice too short for futures list: %#v", raw)
+	}
+
+	pairs, err := convert.ItfToStrSlice(raw[0])
+	if err != nil {
+		return nil, err
+	}
+
+	syms := make([]symbol.Symbol, 0, len(pairs))
+	for _, p := range pairs {
+		s, err := symbol.ParsePair(p)
+		if err != nil {
+			return nil, err
+		}
+		syms = append(syms, s)
+	}
+
+	return syms, nil
+}
+
+// SetInfoRefreshInterval sets the interval after which the cached currency
+// info, symbol details and transfer conversions are considered stale and
+// fetched again on the next CurrencyInfo, SymbolDetails and
+// TransferConversions call
+func (cs *CurrenciesService) SetInfoRefreshInterval(d time.Duration) {
+	c := cs.info
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interval = d
+}
+
+// SymbolScale returns the fixed-point scales of the given trading symbol. The
+// amount scale is the precision of the base currency from the cached currency
+// info, the price scale fits the significant digits of prices around ref,
+// e.g. the last price of the symbol.
+func (cs *CurrenciesService) SymbolScale(sym string, ref float64) (fixed.SymbolScale, error) {
+	s, err := symbol.Parse(sym)
+	if err != nil {
+		return fixed.SymbolScale{}, err
+	}
+	if !s.IsTrading() {
+		return fixed.SymbolScale{}, fmt.Errorf("%w: %s is not a trading symbol", common.ErrBadRequest, sym)
+	}
+
+	info, err := cs.CurrencyInfo(s.Base)
+	if err != nil {
+		return fixed.SymbolScale{}, err
+	}
+	return fixed.SymbolScale{Price: fixed.PriceScale(ref), Amount: fixed.Scale(info.Precision)}, nil
+}
+
+// CurrencyInfo retrieves the precision, withdrawal fee, deposit confirmations
+// and payment ID requirement of the given currency. The underlying configs are
+// cached and refreshed once the refresh interval has elapsed.
+func (cs *CurrenciesService) CurrencyInfo(ccy string) (*currency.Info, error) {
+	c := cs.info
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.infos == nil || time.Since(c.fetched) > c.interval {
+		segments := []string{
+			string(currency.LabelMap),
+			string(currency.TxFeeMap),
+			string(currency.TxMethodMap),
+			string(currency.TxStatusInfo),
+		}
+		req := NewRequestWithMethod(path.Join("conf", strings.Join(segments, ",")), "GET")
+		raw, err := cs.Request(req)
+		if err != nil {
+			return nil, err
+		}
+
+		infos, err := currency.InfoFromRaw(raw, decoding(cs.Synchronous)...)
+		if err != nil {
+			return nil, err
+		}
+
+		c.infos = infos
+		c.fetched = time.Now()
+	}
+
+	info, ok := c.infos[strings.ToUpper(ccy)]
+	if !ok {
+		return nil, fmt.Errorf("%w: currency %s", common.ErrNotFound, ccy)
+	}
+
+	i := *info
+	return &i, nil
+}
+
+// WithdrawalFee returns the current fee of withdrawing the given currency
+// with the given method, e.g. BITCOIN, from the cached currency info. Methods
+// with a fee of their own, e.g. one network of a token, are charged that fee,
+// all others the fee of the currency. See currency.Info.NetWithdrawal for the
+// amount received. Methods which do not serve the currency are rejected.
+func (cs *CurrenciesService) WithdrawalFee(ccy, method string) (float64, error) {
+	info, err := cs.CurrencyInfo(ccy)
+	if err != nil {
+		return 0, err
+	}
+	if !info.HasMethod(method) {
+		return 0, fmt.Errorf("%w: method %s does not serve %s", common.ErrBadRequest, method, info.Currency)
+	}
+	return info.Fee(method), nil
+}
+
+// TransferConversions retrieves the pairs of currencies which a transfer
+// between wallets can convert between, e.g. UST and USTF0. The underlying
+// config is cached and refreshed once the refresh interval has elapsed.
+func (cs *CurrenciesService) TransferConversions() (*currency.Conversions, error) {
+	c := cs.info
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conversions == nil || time.Since(c.conversionsFetched) > c.interval {
+		req := NewRequestWithMethod(path.Join("conf", string(currency.UnderlyingMap)), "GET")
+		raw, err := cs.Request(req)
+		if err != nil {
+			return nil, err
+		}
+
+		conversions, err := currency.ConversionsFromRaw(raw, decoding(cs.Synchronous)...)
+		if err != nil {
+			return nil, err
+		}
+
+		c.conversions = conversions
+		c.conversionsFetched = time.Now()
+	}
+	return c.conversions, nil
+}
+
+// SymbolDetails retrieves the minimum and maximum order size, the precision
+// and the margin requirements of the given trading symbol. The underlying
+// configs are cached and refreshed once the refresh interval has elapsed.
+func (cs *CurrenciesService) SymbolDetails(sym string) (*currency.SymbolDetails, error) {
+	c := cs.info
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.details == nil || time.Since(c.detailsFetched) > c.interval {
+		segments := []string{
+			string(currency.PairInfoMap),
+			string(currency.FuturesInfoMap),
+		}
+		req := NewRequestWithMethod(path.Join("conf", strings.Join(segments, ",")), "GET")
+		raw, err := cs.Request(req)
+		if err != nil {
+			return nil, err
+		}
+
+		details, err := currency.SymbolDetailsFromRaw(raw, decoding(cs.Synchronous)...)
+		if err != nil {
+			return nil, err
+		}
+
+		c.details = details
+		c.detailsFetched = time.Now()
+	}
+
+	details, ok := c.details[sym]
+	if !ok {
+		return nil, fmt.Errorf("%w: symbol %s", common.ErrNotFound, sym)
+	}
+
+	d := *details
+	return &d, nil
+}
+
+// ValidateOrder checks the amount and prices of a new order against the
+// constraints of its symbol, see SymbolDetails, and returns an error wrapping
+// common.ErrBadRequest if the exchange would reject it.
+func (cs *CurrenciesService) ValidateOrder(req *order.NewRequest) error {
+	details, err := cs.SymbolDetails(req.Symbol)
+	if err != nil {
+		return err
+	}
+	return details.Validate(req.Amount, req.Price, req.PriceAuxLimit, req.PriceOcoStop)
+}
+
+// RoundPrice rounds price to the precision accepted by the exchange for the
+// given trading symbol, see SymbolDetails
+func (cs *CurrenciesService) RoundPrice(sym string, price float64) (float64, error) {
+	details, err := cs.SymbolDetails(sym)
+	if err != nil {
+		return 0, err
+	}
+	return details.RoundPrice(price), nil
+}
+
+// RoundAmount rounds amount to the precision accepted by the exchange for the
+// given trading symbol, see SymbolDetails
+func (cs *CurrenciesService) RoundAmount(sym string, amount float64) (float64, error) {
+	details, err := cs.SymbolDetails(sym)
+	if err != nil {
+		return 0, err
+	}
+	return details.RoundAmount(amount), nil
+}
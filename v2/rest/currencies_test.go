@@ -0,0 +1,168 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/fixed"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrencyInfo(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/conf/pub:map:currency:label,pub:map:currency:tx:fee,pub:map:tx:method,pub:info:tx:status", r.URL.Path)
+
+		_, err := w.Write([]byte(`[
+			[["BTC","Bitcoin"],["XMR","Monero"],["UST","Tether"]],
+			[["BTC",[0,0.0004]],["XMR",[0,0.0001]],["UST",[0,1]],["TETHERUSX",[0,0.25]]],
+			[["BITCOIN",["BTC"]],["MONERO",["XMR"]],["TETHERUSE",["UST"]],["TETHERUSX",["UST"]]],
+			[["BITCOIN",1,1,null,null,0,0,null,null,null,null,3],["MONERO",1,0,null,null,1,1,null,null,null,null,10]]
+		]`))
+		require.Nil(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	info, err := c.Currencies.CurrencyInfo("btc")
+	require.Nil(t, err)
+	assert.Equal(t, "Bitcoin", info.Label)
+	assert.Equal(t, 8, info.Precision)
+	assert.Equal(t, 0.0004, info.WithdrawalFee)
+	assert.Equal(t, int64(3), info.DepositConfirmations)
+	assert.False(t, info.PaymentIDRequired)
+
+	info, err = c.Currencies.CurrencyInfo("XMR")
+	require.Nil(t, err)
+	assert.True(t, info.PaymentIDRequired)
+	assert.False(t, info.WithdrawalActive)
+	assert.Equal(t, 1, calls)
+
+	fee, err := c.Currencies.WithdrawalFee("BTC", "bitcoin")
+	require.Nil(t, err)
+	assert.Equal(t, 0.0004, fee)
+	_, err = c.Currencies.WithdrawalFee("BTC", "MONERO")
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+	assert.Equal(t, 0.0999, info.NetWithdrawal("MONERO", 0.1))
+	assert.Equal(t, 0.0, info.NetWithdrawal("MONERO", 0.00005))
+
+	// a method with a fee of its own
+	fee, err = c.Currencies.WithdrawalFee("UST", "TETHERUSX")
+	require.Nil(t, err)
+	assert.Equal(t, 0.25, fee)
+	fee, err = c.Currencies.WithdrawalFee("UST", "tetheruse")
+	require.Nil(t, err)
+	assert.Equal(t, 1.0, fee)
+	info, err = c.Currencies.CurrencyInfo("UST")
+	require.Nil(t, err)
+	assert.Equal(t, 99.75, info.NetWithdrawal("TETHERUSX", 100))
+	assert.Equal(t, 0.123456789, info.NetWithdrawal("TETHERUSX", 0.373456789))
+	_, err = c.Currencies.CurrencyInfo("TETHERUSX")
+	assert.True(t, errors.Is(err, common.ErrNotFound))
+
+	_, err = c.Currencies.CurrencyInfo("FOO")
+	assert.True(t, errors.Is(err, common.ErrNotFound))
+
+	scale, err := c.Currencies.SymbolScale("tBTCUSD", 43210)
+	require.Nil(t, err)
+	assert.Equal(t, fixed.SymbolScale{Price: 0, Amount: 8}, scale)
+	_, err = c.Currencies.SymbolScale("fUSD", 0)
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	c.Currencies.SetInfoRefreshInterval(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	_, err = c.Currencies.CurrencyInfo("BTC")
+	require.Nil(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestDerivatives(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/conf/pub:list:pair:futures", r.URL.Path)
+		_, err := w.Write([]byte(`[["BTCF0:USTF0","ETHF0:USTF0","EURF0:USTF0"]]`))
+		require.Nil(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	syms, err := NewClientWithURL(server.URL).Currencies.Derivatives()
+	require.Nil(t, err)
+	require.Len(t, syms, 3)
+	assert.Equal(t, "tETHF0:USTF0", syms[1].String())
+	assert.True(t, syms[2].IsPerpetual())
+}
+
+func TestSymbolDetails(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/conf/pub:info:pair,pub:info:pair:futures", r.URL.Path)
+		_, err := w.Write([]byte(`[
+			[["BTCUSD",[null,null,null,"0.00006","2000.0",null,null,null,0.2,0.1]]],
+			[["BTCF0:USTF0",[null,null,null,"0.00006","100.0",null,null,null,0.01,0.005]]]
+		]`))
+		require.Nil(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	d, err := c.Currencies.SymbolDetails("tBTCUSD")
+	require.Nil(t, err)
+	assert.Equal(t, 0.00006, d.MinOrderSize)
+	assert.Equal(t, 2000.0, d.MaxOrderSize)
+	assert.Equal(t, 5, d.PricePrecision)
+	assert.Equal(t, 8, d.AmountPrecision)
+	assert.Equal(t, 0.2, d.InitialMargin)
+	assert.Equal(t, 0.1, d.MinimumMargin)
+
+	d, err = c.Currencies.SymbolDetails("tBTCF0:USTF0")
+	require.Nil(t, err)
+	assert.Equal(t, 100.0, d.MaxOrderSize)
+	assert.Equal(t, 0.005, d.MinimumMargin)
+	assert.Equal(t, 1, calls)
+
+	_, err = c.Currencies.SymbolDetails("tFOOBAR")
+	assert.True(t, errors.Is(err, common.ErrNotFound))
+
+	valid := []*order.NewRequest{
+		{Symbol: "tBTCUSD", Amount: 0.5, Price: 43210},
+		{Symbol: "tBTCUSD", Amount: -0.00006, Type: "EXCHANGE MARKET"},
+		{Symbol: "tBTCUSD", Amount: 1.12345678, Price: 0.00012345},
+	}
+	for _, req := range valid {
+		assert.Nil(t, c.Currencies.ValidateOrder(req))
+	}
+
+	invalid := []*order.NewRequest{
+		{Symbol: "tBTCUSD", Amount: 0.00001, Price: 43210},
+		{Symbol: "tBTCUSD", Amount: -2500, Price: 43210},
+		{Symbol: "tBTCUSD", Amount: 0.123456789, Price: 43210},
+		{Symbol: "tBTCUSD", Amount: 0.5, Price: 43210.5},
+		{Symbol: "tBTCUSD", Amount: 0.5, Price: 43210, PriceAuxLimit: 43210.1},
+	}
+	for _, req := range invalid {
+		assert.True(t, errors.Is(c.Currencies.ValidateOrder(req), common.ErrBadRequest), "%+v", req)
+	}
+
+	price, err := c.Currencies.RoundPrice("tBTCUSD", 43210.5)
+	require.Nil(t, err)
+	assert.Equal(t, 43211.0, price)
+	amount, err := c.Currencies.RoundAmount("tBTCUSD", 0.123456789)
+	require.Nil(t, err)
+	assert.Equal(t, 0.12345679, amount)
+	assert.Nil(t, c.Currencies.ValidateOrder(&order.NewRequest{Symbol: "tBTCUSD", Amount: amount, Price: price}))
+	_, err = c.Currencies.RoundPrice("tFOOBAR", 1)
+	assert.True(t, errors.Is(err, common.ErrNotFound))
+}
@@ -0,0 +1,258 @@
+package rest
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/store"
+)
+
+// DepositEventType classifies deposit events
+type DepositEventType int
+
+const (
+	// NewDeposit is emitted the first time a deposit is seen
+	NewDeposit DepositEventType = iota
+	// DepositConfirmed is emitted once a deposit is completed
+	DepositConfirmed
+	// DepositCanceled is emitted once a deposit is canceled or failed
+	DepositCanceled
+)
+
+// DepositEvent is emitted by the DepositWatcher
+type DepositEvent struct {
+	Type     DepositEventType
+	Movement Movement2
+}
+
+// DepositCursor is the state of a DepositWatcher or of MovementsSince which
+// allows to resume polling without returning movements twice. The movements
+// endpoint filters by the start of a movement, so the polled range stays
+// open from the oldest movement which is not final.
+type DepositCursor struct {
+	Since   int64            `json:"since"`             // start of the polled range in milliseconds
+	Seen    map[int64]string `json:"seen"`              // last status of the movements in range
+	Updated map[int64]int64  `json:"updated,omitempty"` // last update of the movements in range
+}
+
+// advance returns the cursor after the movements ms polled from Since were
+// handled. The range moves to the oldest movement which is not final, or to
+// the newest movement if all are final.
+func (c *DepositCursor) advance(ms []Movement2) *DepositCursor {
+	if len(ms) == 0 {
+		return c
+	}
+
+	since, pending := int64(0), false
+	for _, m := range ms {
+		final := m.Status.IsFinal()
+		switch {
+		case !final && (!pending || m.MtsStarted < since):
+			since, pending = m.MtsStarted, true
+		case final && !pending && m.MtsStarted > since:
+			since = m.MtsStarted
+		}
+	}
+
+	next := &DepositCursor{Since: since, Seen: map[int64]string{}, Updated: map[int64]int64{}}
+	for _, m := range ms {
+		if m.MtsStarted >= since {
+			next.Seen[m.ID] = string(m.Status)
+			next.Updated[m.ID] = m.MtsUpdated
+		}
+	}
+	return next
+}
+
+// DepositCursorStore persists the cursor of a DepositWatcher
+type DepositCursorStore interface {
+	Load() (*DepositCursor, error)
+	Save(*DepositCursor) error
+}
+
+// FileCursorStore stores the cursor as JSON in a file, written through a
+// store.File of its directory
+type FileCursorStore struct {
+	Path string
+}
+
+// Load returns the stored cursor, or nil if the file does not exist
+func (fs FileCursorStore) Load() (*DepositCursor, error) {
+	ss, err := fs.store()
+	if err != nil {
+		return nil, err
+	}
+	return ss.Load()
+}
+
+// Save writes the cursor to the file
+func (fs FileCursorStore) Save(c *DepositCursor) error {
+	ss, err := fs.store()
+	if err != nil {
+		return err
+	}
+	return ss.Save(c)
+}
+
+func (fs FileCursorStore) store() (StoreCursorStore, error) {
+	s, err := store.NewFile(filepath.Dir(fs.Path))
+	return StoreCursorStore{Store: s, Key: filepath.Base(fs.Path)}, err
+}
+
+// StoreCursorStore stores the cursor as JSON under Key of Store
+type StoreCursorStore struct {
+	Store store.Store
+	Key   string
+}
+
+// Load returns the stored cursor, or nil if there is none
+func (ss StoreCursorStore) Load() (*DepositCursor, error) {
+	c := &DepositCursor{}
+	ok, err := store.GetJSON(ss.Store, ss.Key, c)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Save stores the cursor
+func (ss StoreCursorStore) Save(c *DepositCursor) error {
+	return store.PutJSON(ss.Store, ss.Key, c)
+}
+
+// DepositWatcher polls the movements of the configured currencies and emits
+// an event for every new, confirmed and canceled deposit
+type DepositWatcher struct {
+	wallet     *WalletService
+	currencies []string
+	interval   time.Duration
+	store      DepositCursorStore
+	cursor     *DepositCursor
+	loaded     bool
+}
+
+// NewDepositWatcher returns a watcher polling the deposits of the given
+// currencies, or of all currencies if none are given, every interval
+func (ws *WalletService) NewDepositWatcher(interval time.Duration, currencies ...string) *DepositWatcher {
+	if len(currencies) == 0 {
+		currencies = []string{""}
+	}
+	return &DepositWatcher{
+		wallet:     ws,
+		currencies: currencies,
+		interval:   interval,
+	}
+}
+
+// WithCursorStore persists the cursor after every poll and resumes from the
+// stored cursor, which takes precedence over Since
+func (dw *DepositWatcher) WithCursorStore(store DepositCursorStore) *DepositWatcher {
+	dw.store = store
+	return dw
+}
+
+// Since sets the start of the watched range if no cursor was stored.
+// Deposits started before are ignored.
+func (dw *DepositWatcher) Since(t time.Time) *DepositWatcher {
+	dw.cursor = &DepositCursor{Since: int64(common.MtsFromTime(t)), Seen: map[int64]string{}}
+	return dw
+}
+
+// Run polls until ctx is done and calls handler for every event. The cursor
+// is persisted after all events of a poll were handled.
+func (dw *DepositWatcher) Run(ctx context.Context, handler func(DepositEvent)) error {
+	ticker := time.NewTicker(dw.interval)
+	defer ticker.Stop()
+
+	for {
+		events, err := dw.Poll()
+		if err != nil {
+			return err
+		}
+		for _, ev := range events {
+			handler(ev)
+		}
+		if err := dw.save(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetches the deposits once and returns the resulting events. The
+// cursor is advanced but not persisted.
+func (dw *DepositWatcher) Poll() ([]DepositEvent, error) {
+	if err := dw.load(); err != nil {
+		return nil, err
+	}
+
+	deposits := []Movement2{}
+	for _, cur := range dw.currencies {
+		ms, err := dw.wallet.movementsFrom(cur, common.Mts(dw.cursor.Since))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range ms {
+			if m.Amount > 0 && m.MtsStarted >= dw.cursor.Since {
+				deposits = append(deposits, m)
+			}
+		}
+	}
+
+	events := []DepositEvent{}
+	for _, m := range deposits {
+		prev, known := dw.cursor.Seen[m.ID]
+		if !known {
+			events = append(events, DepositEvent{Type: NewDeposit, Movement: m})
+		}
+		if string(m.Status) != prev {
+			switch {
+			case m.Status.IsSuccessful():
+				events = append(events, DepositEvent{Type: DepositConfirmed, Movement: m})
+			case m.Status.IsFailed():
+				events = append(events, DepositEvent{Type: DepositCanceled, Movement: m})
+			}
+		}
+	}
+	dw.cursor = dw.cursor.advance(deposits)
+
+	return events, nil
+}
+
+func (dw *DepositWatcher) load() error {
+	if dw.loaded {
+		return nil
+	}
+	dw.loaded = true
+
+	if dw.store != nil {
+		c, err := dw.store.Load()
+		if err != nil {
+			return err
+		}
+		if c != nil {
+			dw.cursor = c
+		}
+	}
+	if dw.cursor == nil {
+		dw.cursor = &DepositCursor{}
+	}
+	if dw.cursor.Seen == nil {
+		dw.cursor.Seen = map[int64]string{}
+	}
+	return nil
+}
+
+func (dw *DepositWatcher) save() error {
+	if dw.store == nil {
+		return nil
+	}
+	return dw.store.Save(dw.cursor)
+}
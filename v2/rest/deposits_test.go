@@ -0,0 +1,153 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func movementRaw(id int64, started int64, status string, amount float64) []interface{} {
+	return []interface{}{
+		id, "BTC", "BITCOIN", nil, nil, started, started + 1000, nil, nil, status, nil, nil,
+		amount, -0.0001, nil, nil, "addr", nil, nil, nil, "txid", nil,
+	}
+}
+
+// movementsServer serves the movements returned by all, newest first by their
+// start, within the start, end and limit of the query like the movements
+// endpoint, whose default limit is 25
+func movementsServer(all func() [][]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pld := map[string]float64{}
+		_ = json.NewDecoder(r.Body).Decode(&pld)
+		start, end, limit := pld["start"], float64(1<<53), 25.0
+		if v, ok := pld["end"]; ok {
+			end = v
+		}
+		if v, ok := pld["limit"]; ok {
+			limit = v
+		}
+
+		ms := all()
+		sort.SliceStable(ms, func(i, j int) bool { return ms[i][5].(int64) > ms[j][5].(int64) })
+		page := make([][]interface{}, 0)
+		for _, m := range ms {
+			if mts := float64(m[5].(int64)); mts >= start && mts <= end && len(page) < int(limit) {
+				page = append(page, m)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+}
+
+func TestDepositWatcher(t *testing.T) {
+	var responses [][]interface{}
+	var starts []float64
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/r/movements/BTC/hist", r.RequestURI)
+
+		pld := map[string]interface{}{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&pld))
+		starts = append(starts, pld["start"].(float64))
+
+		require.Nil(t, json.NewEncoder(w).Encode(responses[len(starts)-1]))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	responses = [][]interface{}{
+		{
+			movementRaw(1, 1000, "PROCESSING", 1),
+			movementRaw(2, 2000, "COMPLETED", 2),
+			movementRaw(3, 3000, "COMPLETED", -1), // withdrawal
+		},
+		{
+			movementRaw(1, 1000, "COMPLETED", 1),
+			movementRaw(2, 2000, "COMPLETED", 2),
+			movementRaw(4, 4000, "PROCESSING", 3),
+		},
+		{
+			movementRaw(4, 4000, "CANCELED", 3),
+		},
+	}
+
+	store := FileCursorStore{Path: filepath.Join(t.TempDir(), "cursor.json")}
+	dw := NewClientWithURL(server.URL).Wallet.NewDepositWatcher(0, "BTC").WithCursorStore(store)
+
+	type ev struct {
+		Type DepositEventType
+		ID   int64
+	}
+	poll := func(dw *DepositWatcher) []ev {
+		events, err := dw.Poll()
+		require.Nil(t, err)
+		require.Nil(t, dw.save())
+
+		out := []ev{}
+		for _, e := range events {
+			out = append(out, ev{e.Type, e.Movement.ID})
+		}
+		return out
+	}
+
+	assert.Equal(t, []ev{{NewDeposit, 1}, {NewDeposit, 2}, {DepositConfirmed, 2}}, poll(dw))
+	assert.Equal(t, []ev{{DepositConfirmed, 1}, {NewDeposit, 4}}, poll(dw))
+
+	// a new watcher resumes from the stored cursor
+	dw = NewClientWithURL(server.URL).Wallet.NewDepositWatcher(0, "BTC").WithCursorStore(store)
+	assert.Equal(t, []ev{{DepositCanceled, 4}}, poll(dw))
+
+	assert.Equal(t, []float64{0, 1000, 4000}, starts)
+
+	c, err := store.Load()
+	require.Nil(t, err)
+	assert.Equal(t, &DepositCursor{Since: 4000, Seen: map[int64]string{4: "CANCELED"}, Updated: map[int64]int64{4: 5000}}, c)
+}
+
+func TestDepositWatcherPages(t *testing.T) {
+	// more deposits than fit into a page, two of them per timestamp
+	var all [][]interface{}
+	for id := int64(1); id <= 2500; id++ {
+		all = append(all, movementRaw(id, 1000+id/2, "COMPLETED", 1))
+	}
+	server := movementsServer(func() [][]interface{} { return all })
+	defer server.Close()
+
+	dw := NewClientWithURL(server.URL).Wallet.NewDepositWatcher(0, "BTC")
+	events, err := dw.Poll()
+	require.Nil(t, err)
+	confirmed := map[int64]bool{}
+	for _, e := range events {
+		if e.Type == DepositConfirmed {
+			confirmed[e.Movement.ID] = true
+		}
+	}
+	assert.Len(t, confirmed, 2500)
+	assert.Equal(t, int64(2250), dw.cursor.Since)
+
+	// nothing new on the next poll
+	events, err = dw.Poll()
+	require.Nil(t, err)
+	assert.Len(t, events, 0)
+}
+
+func TestStoreCursorStore(t *testing.T) {
+	cs := StoreCursorStore{Store: store.NewMemory(), Key: "deposits/BTC"}
+	c, err := cs.Load()
+	require.Nil(t, err)
+	assert.Nil(t, c)
+
+	expected := &DepositCursor{Since: 4000, Seen: map[int64]string{4: "CANCELED"}}
+	require.Nil(t, cs.Save(expected))
+	c, err = cs.Load()
+	require.Nil(t, err)
+	assert.Equal(t, expected, c)
+}
@@ -3,6 +3,7 @@ package rest
 import (
 	"path"
 
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 )
 
@@ -34,7 +35,7 @@ func (s *WalletService) SetCollateral(symbol string, amount float64) (bool, erro
 	}
 	item := raw[0].([]interface{})
 	// [1] == success, [] || [0] == false
-	if len(item) > 0 && item[0].(float64) == 1 {
+	if len(item) > 0 && convert.IValOrZero(item[0]) == 1 {
 		return true, nil
 	}
 	return false, nil
@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+)
+
+// Environment variables read by ClientFromEnv
+const (
+	EnvAPIKey    = "BFX_API_KEY"
+	EnvAPISecret = "BFX_API_SECRET"
+	EnvRestURL   = "BFX_REST_URL"
+	EnvProxyURL  = "BFX_PROXY_URL"
+	EnvTimeout   = "BFX_REST_TIMEOUT"
+)
+
+// authBaseURL serves authenticated endpoints, which are not available on the
+// public production url
+var authBaseURL = "https://api.bitfinex.com/v2/"
+
+// DefaultTimeout is the http timeout of clients created by ClientFromEnv
+const DefaultTimeout = 30 * time.Second
+
+// ClientFromEnv creates a client configured by environment variables:
+//
+//	BFX_API_KEY, BFX_API_SECRET  credentials, optional
+//	BFX_REST_URL                 base url, defaults to the authenticated api if
+//	                             credentials are set and the public one otherwise
+//	BFX_PROXY_URL                proxy for all requests, defaults to HTTP_PROXY,
+//	                             HTTPS_PROXY and NO_PROXY
+//	BFX_REST_TIMEOUT             http timeout such as 10s, defaults to 30s
+func ClientFromEnv() (*Client, error) {
+	key, secret := os.Getenv(EnvAPIKey), os.Getenv(EnvAPISecret)
+	if (key == "") != (secret == "") {
+		return nil, fmt.Errorf("both %s and %s have to be set", EnvAPIKey, EnvAPISecret)
+	}
+
+	base := os.Getenv(EnvRestURL)
+	if base == "" {
+		base = productionBaseURL
+		if key != "" {
+			base = authBaseURL
+		}
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvRestURL, err)
+	}
+
+	proxy, err := ProxyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := DefaultTimeout
+	if t := os.Getenv(EnvTimeout); t != "" {
+		if timeout, err = time.ParseDuration(t); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvTimeout, err)
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+
+	sync := &HttpTransport{
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Transport: transport, Timeout: timeout},
+		httpDo: func(c *http.Client, req *http.Request) (*http.Response, error) {
+			return c.Do(req)
+		},
+	}
+
+	c := NewClientWithSynchronousURLNonce(sync, base, utils.NewEpochNonceGenerator())
+	if key != "" {
+		c.Credentials(key, secret)
+	}
+	return c, nil
+}
+
+// ProxyFromEnv returns the proxy set by BFX_PROXY_URL, or the standard
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY handling if it isn't set
+func ProxyFromEnv() (func(*http.Request) (*url.URL, error), error) {
+	p := os.Getenv(EnvProxyURL)
+	if p == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	u, err := url.Parse(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvProxyURL, err)
+	}
+	return http.ProxyURL(u), nil
+}
@@ -0,0 +1,78 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setEnv(t *testing.T, env map[string]string) {
+	for k, v := range env {
+		prev, ok := os.LookupEnv(k)
+		require.Nil(t, os.Setenv(k, v))
+		k := k
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(k, prev)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+	}
+}
+
+func TestClientFromEnv(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/auth/r/summary", r.URL.Path)
+		assert.Equal(t, "key", r.Header.Get("bfx-apikey"))
+		_, err := w.Write([]byte(`[]`))
+		require.Nil(t, err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	setEnv(t, map[string]string{
+		EnvAPIKey:    "key",
+		EnvAPISecret: "secret",
+		EnvRestURL:   server.URL + "/v2/",
+		EnvTimeout:   "5s",
+		EnvProxyURL:  "",
+	})
+
+	c, err := ClientFromEnv()
+	require.Nil(t, err)
+	assert.Equal(t, server.URL+"/v2/", c.Synchronous.(*HttpTransport).BaseURL.String())
+	assert.Equal(t, "5s", c.Synchronous.(*HttpTransport).HTTPClient.Timeout.String())
+
+	req, err := c.NewAuthenticatedRequest("r", "summary")
+	require.Nil(t, err)
+	_, err = c.Request(req)
+	require.Nil(t, err)
+}
+
+func TestClientFromEnvDefaults(t *testing.T) {
+	setEnv(t, map[string]string{EnvAPIKey: "", EnvAPISecret: "", EnvRestURL: "", EnvTimeout: ""})
+
+	c, err := ClientFromEnv()
+	require.Nil(t, err)
+	assert.Equal(t, productionBaseURL, c.Synchronous.(*HttpTransport).BaseURL.String())
+
+	setEnv(t, map[string]string{EnvAPIKey: "key", EnvAPISecret: "secret"})
+	c, err = ClientFromEnv()
+	require.Nil(t, err)
+	assert.Equal(t, authBaseURL, c.Synchronous.(*HttpTransport).BaseURL.String())
+}
+
+func TestClientFromEnvInvalid(t *testing.T) {
+	setEnv(t, map[string]string{EnvAPIKey: "key", EnvAPISecret: ""})
+	_, err := ClientFromEnv()
+	assert.NotNil(t, err)
+
+	setEnv(t, map[string]string{EnvAPISecret: "secret", EnvTimeout: "soon"})
+	_, err = ClientFromEnv()
+	assert.NotNil(t, err)
+}
@@ -36,7 +36,7 @@ func (fs *FundingService) Offers(symbol string) (*fundingoffer.Snapshot, error)
 	if err != nil {
 		return nil, err
 	}
-	offers, err := fundingoffer.SnapshotFromRaw(raw)
+	offers, err := fundingoffer.SnapshotFromRaw(raw, decoding(fs.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +54,7 @@ func (fs *FundingService) OfferHistory(symbol string) (*fundingoffer.Snapshot, e
 	if err != nil {
 		return nil, err
 	}
-	offers, err := fundingoffer.SnapshotFromRaw(raw)
+	offers, err := fundingoffer.SnapshotFromRaw(raw, decoding(fs.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +72,7 @@ func (fs *FundingService) Loans(symbol string) (*fundingloan.Snapshot, error) {
 	if err != nil {
 		return nil, err
 	}
-	loans, err := fundingloan.SnapshotFromRaw(raw)
+	loans, err := fundingloan.SnapshotFromRaw(raw, decoding(fs.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +90,7 @@ func (fs *FundingService) LoansHistory(symbol string) (*fundingloan.Snapshot, er
 	if err != nil {
 		return nil, err
 	}
-	loans, err := fundingloan.SnapshotFromRaw(raw)
+	loans, err := fundingloan.SnapshotFromRaw(raw, decoding(fs.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +108,7 @@ func (fs *FundingService) Credits(symbol string) (*fundingcredit.Snapshot, error
 	if err != nil {
 		return nil, err
 	}
-	loans, err := fundingcredit.SnapshotFromRaw(raw)
+	loans, err := fundingcredit.SnapshotFromRaw(raw, decoding(fs.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +126,7 @@ func (fs *FundingService) CreditsHistory(symbol string) (*fundingcredit.Snapshot
 	if err != nil {
 		return nil, err
 	}
-	loans, err := fundingcredit.SnapshotFromRaw(raw)
+	loans, err := fundingcredit.SnapshotFromRaw(raw, decoding(fs.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +146,7 @@ func (fs *FundingService) Trades(symbol string) (*fundingtrade.Snapshot, error)
 		return nil, err
 	}
 
-	fts, err := fundingtrade.SnapshotFromRaw(raw)
+	fts, err := fundingtrade.SnapshotFromRaw(raw, decoding(fs.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -154,6 +154,77 @@ func (fs *FundingService) Trades(symbol string) (*fundingtrade.Snapshot, error)
 	return fts, nil
 }
 
+// fundingHistoryLimit is the maximum page size of the funding history endpoints
+const fundingHistoryLimit = 500
+
+func (fs *FundingService) history(kind, symbol string, q *Query) ([]interface{}, error) {
+	if q.exceedsLimit(fundingHistoryLimit) {
+		return nil, fmt.Errorf("%w: max request limit:%d, got: %d", common.ErrBadRequest, fundingHistoryLimit, *q.limit)
+	}
+
+	req, err := fs.requestFactory.NewAuthenticatedRequestWithData(common.PermissionRead, path.Join("funding", kind, symbol, "hist"), q.payload())
+	if err != nil {
+		return nil, err
+	}
+	return fs.Request(req)
+}
+
+// OfferHistoryQuery retrieves past in-active funding offers matching the given
+// query. An empty symbol returns offers of all symbols.
+// see https://docs.bitfinex.com/reference#rest-auth-funding-offers-hist for more info
+func (fs *FundingService) OfferHistoryQuery(symbol string, q *Query) (*fundingoffer.Snapshot, error) {
+	raw, err := fs.history("offers", symbol, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return &fundingoffer.Snapshot{Snapshot: make([]*fundingoffer.Offer, 0)}, nil
+	}
+	return fundingoffer.SnapshotFromRaw(raw, decoding(fs.Synchronous)...)
+}
+
+// LoansHistoryQuery retrieves past in-active funding loans matching the given
+// query. An empty symbol returns loans of all symbols.
+// see https://docs.bitfinex.com/reference#rest-auth-funding-loans-hist for more info
+func (fs *FundingService) LoansHistoryQuery(symbol string, q *Query) (*fundingloan.Snapshot, error) {
+	raw, err := fs.history("loans", symbol, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return &fundingloan.Snapshot{Snapshot: make([]*fundingloan.Loan, 0)}, nil
+	}
+	return fundingloan.SnapshotFromRaw(raw, decoding(fs.Synchronous)...)
+}
+
+// CreditsHistoryQuery retrieves past in-active credits used in positions
+// matching the given query. An empty symbol returns credits of all symbols.
+// see https://docs.bitfinex.com/reference#rest-auth-funding-credits-hist for more info
+func (fs *FundingService) CreditsHistoryQuery(symbol string, q *Query) (*fundingcredit.Snapshot, error) {
+	raw, err := fs.history("credits", symbol, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return &fundingcredit.Snapshot{Snapshot: make([]*fundingcredit.Credit, 0)}, nil
+	}
+	return fundingcredit.SnapshotFromRaw(raw, decoding(fs.Synchronous)...)
+}
+
+// TradesQuery retrieves matched funding trades matching the given query. An
+// empty symbol returns trades of all symbols.
+// see https://docs.bitfinex.com/reference#rest-auth-funding-trades-hist for more info
+func (fs *FundingService) TradesQuery(symbol string, q *Query) (*fundingtrade.Snapshot, error) {
+	raw, err := fs.history("trades", symbol, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return &fundingtrade.Snapshot{Snapshot: make([]*fundingtrade.FundingTrade, 0)}, nil
+	}
+	return fundingtrade.SnapshotFromRaw(raw, decoding(fs.Synchronous)...)
+}
+
 // Submits a request to create a new funding offer
 // see https://docs.bitfinex.com/reference#submit-funding-offer for more info
 func (fs *FundingService) SubmitOffer(fo *fundingoffer.SubmitRequest) (*notification.Notification, error) {
@@ -169,7 +240,7 @@ func (fs *FundingService) SubmitOffer(fo *fundingoffer.SubmitRequest) (*notifica
 	if err != nil {
 		return nil, err
 	}
-	return notification.FromRaw(raw)
+	return notificationFromRaw(raw, decoding(fs.Synchronous)...)
 }
 
 // Submits a request to cancel the given offer
@@ -187,14 +258,14 @@ func (fs *FundingService) CancelOffer(fc *fundingoffer.CancelRequest) (*notifica
 	if err != nil {
 		return nil, err
 	}
-	return notification.FromRaw(raw)
+	return notificationFromRaw(raw, decoding(fs.Synchronous)...)
 }
 
 // KeepFunding - toggle to keep funding taken. Specify loan for unused funding and credit for used funding.
 // see https://docs.bitfinex.com/reference#rest-auth-keep-funding for more info
 func (fs *FundingService) KeepFunding(args KeepFundingRequest) (*notification.Notification, error) {
 	if args.Type != "credit" && args.Type != "loan" {
-		return nil, fmt.Errorf("Expected type: credit or loan, got: %s", args.Type)
+		return nil, fmt.Errorf("%w: expected type: credit or loan, got: %s", common.ErrBadRequest, args.Type)
 	}
 
 	bytes, err := json.Marshal(args)
@@ -216,5 +287,5 @@ func (fs *FundingService) KeepFunding(args KeepFundingRequest) (*notification.No
 		return nil, err
 	}
 
-	return notification.FromRaw(raw)
+	return notificationFromRaw(raw, decoding(fs.Synchronous)...)
 }
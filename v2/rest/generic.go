@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+)
+
+// DoPublic performs a GET request against any public endpoint and returns the
+// decoded JSON response. It allows endpoints that are not yet covered by the
+// services to be used, e.g.:
+//
+//	raw, err := c.DoPublic(ctx, "tickers", url.Values{"symbols": {"tBTCUSD"}})
+func (c *Client) DoPublic(ctx context.Context, refURL string, params url.Values) ([]interface{}, error) {
+	req := NewRequestWithMethod(refURL, "GET")
+	req.Params = params
+	return c.Request(withContext(ctx, req))
+}
+
+// DoAuthenticated performs a signed request against any authenticated endpoint
+// and returns the decoded JSON response. The body is encoded as JSON, a nil body
+// results in an empty object, e.g.:
+//
+//	raw, err := c.DoAuthenticated(ctx, common.PermissionRead, "wallets", nil)
+func (c *Client) DoAuthenticated(ctx context.Context, permission common.PermissionType, refURL string, body interface{}) ([]interface{}, error) {
+	data := []byte("{}")
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encoding request payload: %w", err)
+		}
+		data = b
+	}
+
+	req, err := c.NewAuthenticatedRequestWithBytes(permission, refURL, data)
+	if err != nil {
+		return nil, err
+	}
+	return c.Request(withContext(ctx, req))
+}
+
+// withContext binds the request to ctx and reuses the request id carried by
+// it, if any
+func withContext(ctx context.Context, req Request) Request {
+	if id, ok := utils.RequestIDFromContext(ctx); ok {
+		req.ID = id
+	}
+	return req.WithContext(ctx)
+}
@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoPublic(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/tickers?symbols=tBTCUSD", r.RequestURI)
+		_, err := w.Write([]byte(`[["tBTCUSD",10654,53.62,10655,76.7]]`))
+		require.Nil(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	var info RequestInfo
+	c := NewClientWithURL(server.URL).OnRequest(func(ri RequestInfo) { info = ri })
+
+	ctx := utils.WithRequestID(context.Background(), "trace-2")
+	raw, err := c.DoPublic(ctx, "tickers", url.Values{"symbols": {"tBTCUSD"}})
+	require.Nil(t, err)
+	require.Len(t, raw, 1)
+	assert.Equal(t, "tBTCUSD", raw[0].([]interface{})[0])
+	assert.Equal(t, "trace-2", info.ID)
+}
+
+func TestDoAuthenticated(t *testing.T) {
+	t.Run("signs and encodes body", func(t *testing.T) {
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/auth/r/some/new/endpoint", r.RequestURI)
+			assert.NotEmpty(t, r.Header.Get("bfx-signature"))
+
+			gotReqPld := map[string]interface{}{}
+			err := json.NewDecoder(r.Body).Decode(&gotReqPld)
+			require.Nil(t, err)
+			assert.Equal(t, map[string]interface{}{"symbol": "tBTCUSD"}, gotReqPld)
+
+			_, err = w.Write([]byte(`[1,"ok"]`))
+			require.Nil(t, err)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(handler))
+		defer server.Close()
+
+		c := NewClientWithURL(server.URL).Credentials("key", "secret")
+		raw, err := c.DoAuthenticated(context.Background(), common.PermissionRead, "some/new/endpoint", map[string]string{"symbol": "tBTCUSD"})
+		require.Nil(t, err)
+		assert.Equal(t, []interface{}{json.Number("1"), "ok"}, raw)
+	})
+
+	t.Run("honors cancelled context", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := NewClientWithURL(server.URL).DoAuthenticated(ctx, common.PermissionRead, "wallets", nil)
+		require.NotNil(t, err)
+		assert.True(t, errors.Is(err, context.Canceled), "got %v", err)
+	})
+}
@@ -0,0 +1,103 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+)
+
+// ErrDuplicateOperation is returned by Transfer and Withdraw when an identical
+// operation has already been submitted within the idempotency window
+var ErrDuplicateOperation = fmt.Errorf("%w: duplicate operation", common.ErrBadRequest)
+
+// TransferRef returns the client reference recorded for a transfer
+func TransferRef(from, to, currency, currencyTo string, amount float64) string {
+	return operationRef("transfer", from, to, currency, currencyTo, strconv.FormatFloat(amount, 'f', -1, 64))
+}
+
+// WithdrawalRef returns the client reference recorded for a withdrawal
+func WithdrawalRef(wallet, method string, amount float64, address string, paymentId *string) string {
+	pid := ""
+	if paymentId != nil {
+		pid = *paymentId
+	}
+	return operationRef("withdraw", wallet, method, strconv.FormatFloat(amount, 'f', -1, 64), address, pid)
+}
+
+func operationRef(parts ...string) string {
+	return strings.Join(parts, ":")
+}
+
+// idempotencyGuard remembers submitted operations for a window to refuse
+// resubmitting them, e.g. after a request timed out without a response
+type idempotencyGuard struct {
+	mu     sync.Mutex
+	window time.Duration
+	now    func() time.Time
+	refs   map[string]time.Time
+}
+
+func (g *idempotencyGuard) acquire(ref string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.window <= 0 {
+		return nil
+	}
+
+	now := g.now()
+	for r, at := range g.refs {
+		if now.Sub(at) >= g.window {
+			delete(g.refs, r)
+		}
+	}
+	if at, ok := g.refs[ref]; ok {
+		return fmt.Errorf("%w: %s submitted at %s", ErrDuplicateOperation, ref, at.Format(time.RFC3339))
+	}
+	g.refs[ref] = now
+	return nil
+}
+
+func (g *idempotencyGuard) release(ref string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.refs, ref)
+}
+
+// done releases ref if the API has rejected the operation, with an error
+// response or an error notification. Successful and ambiguous outcomes such
+// as timeouts keep the operation recorded.
+func (g *idempotencyGuard) done(ref string, err error) {
+	var er *ErrorResponse
+	var ne *notification.Error
+	if errors.As(err, &er) || errors.As(err, &ne) {
+		g.release(ref)
+	}
+}
+
+// SetIdempotencyWindow enables refusing Transfer and Withdraw requests
+// identical to one submitted within the last d with ErrDuplicateOperation.
+// A zero window disables the guard, which is the default.
+func (ws *WalletService) SetIdempotencyWindow(d time.Duration) {
+	g := ws.guard
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.window = d
+}
+
+// ForgetOperation allows the operation with the given reference to be
+// submitted again, e.g. once it has been verified that a timed out
+// withdrawal wasn't executed
+func (ws *WalletService) ForgetOperation(ref string) {
+	ws.guard.release(ref)
+}
+
+func newIdempotencyGuard() *idempotencyGuard {
+	return &idempotencyGuard{now: time.Now, refs: map[string]time.Time{}}
+}
@@ -0,0 +1,109 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotentTransfer(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			// connection drops without a response
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.Nil(t, err)
+			conn.Close()
+		case 2:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`["error",10001,"insufficient balance"]`))
+		default:
+			w.Write([]byte(`[1568742390999,"acc_tf",null,null,[],null,"SUCCESS","ok"]`))
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	ws := NewClientWithURL(server.URL).Wallet
+	ws.SetIdempotencyWindow(time.Minute)
+
+	_, err := ws.Transfer("exchange", "margin", "BTC", "", 1)
+	require.NotNil(t, err)
+	_, err = ws.Transfer("exchange", "margin", "BTC", "", 1)
+	assert.True(t, errors.Is(err, ErrDuplicateOperation))
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+	assert.Equal(t, 1, calls)
+
+	// a different amount is a different operation
+	_, err = ws.Transfer("exchange", "margin", "BTC", "", 2)
+	require.NotNil(t, err)
+	assert.False(t, errors.Is(err, ErrDuplicateOperation))
+	assert.Equal(t, 2, calls)
+
+	// rejected by the API, so it may be submitted again
+	_, err = ws.Transfer("exchange", "margin", "BTC", "", 2)
+	require.Nil(t, err)
+	assert.Equal(t, 3, calls)
+
+	ws.ForgetOperation(TransferRef("exchange", "margin", "BTC", "", 1))
+	_, err = ws.Transfer("exchange", "margin", "BTC", "", 1)
+	require.Nil(t, err)
+	assert.Equal(t, 4, calls)
+}
+
+func TestRejectedTransfer(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Write([]byte(`[1568742390999,"acc_tf",null,null,[],null,"ERROR","Currency conversion not supported"]`))
+			return
+		}
+		w.Write([]byte(`[1568742390999,"acc_tf",null,null,[],null,"SUCCESS","ok"]`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	ws := NewClientWithURL(server.URL).Wallet
+	ws.SetIdempotencyWindow(time.Minute)
+
+	// the error notification is returned as error and releases the operation
+	n, err := ws.Transfer("exchange", "margin", "BTC", "ETH", 1)
+	assert.Nil(t, n)
+	var ne *notification.Error
+	require.True(t, errors.As(err, &ne))
+	assert.Equal(t, "Currency conversion not supported", ne.Notification.Text)
+
+	n, err = ws.Transfer("exchange", "margin", "BTC", "ETH", 1)
+	require.Nil(t, err)
+	assert.Equal(t, notification.StatusSuccess, n.Status)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyWindow(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	g := newIdempotencyGuard()
+	g.now = func() time.Time { return now }
+
+	ref := WithdrawalRef("exchange", "bitcoin", 0.1, "addr", nil)
+	require.Nil(t, g.acquire(ref))
+	require.Nil(t, g.acquire(ref), "disabled by default")
+
+	g.window = time.Minute
+	require.Nil(t, g.acquire(ref))
+	assert.True(t, errors.Is(g.acquire(ref), ErrDuplicateOperation))
+
+	now = now.Add(time.Minute)
+	assert.Nil(t, g.acquire(ref))
+}
@@ -48,7 +48,7 @@ func validCurrency(currency string) error {
 
 		sb.WriteString("]")
 
-		return fmt.Errorf(sb.String())
+		return fmt.Errorf("%w: %s", common.ErrBadRequest, sb.String())
 	}
 
 	return nil
@@ -57,12 +57,13 @@ func validCurrency(currency string) error {
 func validAmount(currency, amount string) error {
 	f, err := strconv.ParseFloat(amount, 64)
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: invalid amount %q: %v", common.ErrBadRequest, amount, err)
 	}
 
 	if f < validCurrencies[currency].min {
 		return fmt.Errorf(
-			"Minimum allowed amount for %s is %f. Got: %f",
+			"%w: minimum allowed amount for %s is %f. Got: %f",
+			common.ErrBadRequest,
 			currency,
 			validCurrencies[currency].min,
 			f,
@@ -71,7 +72,8 @@ func validAmount(currency, amount string) error {
 
 	if f > validCurrencies[currency].max {
 		return fmt.Errorf(
-			"Maximum allowed amount for %s is %f. Got: %f",
+			"%w: maximum allowed amount for %s is %f. Got: %f",
+			common.ErrBadRequest,
 			currency,
 			validCurrencies[currency].max,
 			f,
@@ -112,7 +114,7 @@ func (is *InvoiceService) GenerateInvoice(payload DepositInvoiceRequest) (*invoi
 		return nil, err
 	}
 
-	invc, err := invoice.NewFromRaw(raw)
+	invc, err := invoice.NewFromRaw(raw, decoding(is.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"context"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/invoice"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+)
+
+const (
+	// DefaultInvoiceExpiry is the lifetime of a lightning invoice
+	DefaultInvoiceExpiry = time.Hour
+	// DefaultInvoicePoll is the time between two polls of an InvoiceTracker
+	DefaultInvoicePoll = 2 * time.Second
+
+	// invoiceClockSkew widens the polled range to deposits which were
+	// stamped slightly before the invoice was generated locally
+	invoiceClockSkew = time.Minute
+)
+
+// InvoiceEventType classifies invoice events
+type InvoiceEventType int
+
+const (
+	// InvoicePaid is emitted once the deposit of an invoice is completed
+	InvoicePaid InvoiceEventType = iota
+	// InvoiceExpired is emitted if the invoice was not paid in time
+	InvoiceExpired
+)
+
+// InvoiceEvent is emitted by the InvoiceTracker. Movement is only set for
+// paid invoices.
+type InvoiceEvent struct {
+	Type     InvoiceEventType
+	Invoice  *invoice.Invoice
+	Movement Movement2
+}
+
+// InvoiceTracker polls the LNX deposits for the settlement of a single
+// invoice, identified by its hash or payment request
+type InvoiceTracker struct {
+	wallet   *WalletService
+	invoice  *invoice.Invoice
+	created  time.Time
+	expiry   time.Duration
+	interval time.Duration
+	clock    utils.Clock
+}
+
+// TrackInvoice returns a tracker for an invoice just returned by
+// GenerateInvoice, e.g.:
+//
+//	inv, _ := c.Invoice.GenerateInvoice(rest.DepositInvoiceRequest{Currency: "LNX", Wallet: "exchange", Amount: "0.001"})
+//	ev, err := c.TrackInvoice(inv).Wait(ctx)
+func (c *Client) TrackInvoice(inv *invoice.Invoice) *InvoiceTracker {
+	return &InvoiceTracker{
+		wallet:   &c.Wallet,
+		invoice:  inv,
+		created:  utils.SystemClock.Now(),
+		expiry:   DefaultInvoiceExpiry,
+		interval: DefaultInvoicePoll,
+		clock:    utils.SystemClock,
+	}
+}
+
+// Clock sets the clock timing the expiry and the polls, defaulting to
+// utils.SystemClock. The lifetime of the invoice is counted from now on.
+func (it *InvoiceTracker) Clock(clock utils.Clock) *InvoiceTracker {
+	it.clock = clock
+	it.created = clock.Now()
+	return it
+}
+
+// Expiry sets the lifetime of the invoice, counted from TrackInvoice
+func (it *InvoiceTracker) Expiry(d time.Duration) *InvoiceTracker {
+	it.expiry = d
+	return it
+}
+
+// Interval sets the time between two polls
+func (it *InvoiceTracker) Interval(d time.Duration) *InvoiceTracker {
+	it.interval = d
+	return it
+}
+
+// Poll fetches the LNX deposits once and returns the event of the invoice,
+// or nil if it is neither paid nor expired yet. The deposits are fetched
+// once more after the expiry so that late settlements are not missed.
+func (it *InvoiceTracker) Poll() (*InvoiceEvent, error) {
+	now := it.clock.Now()
+	since := common.MtsFromTime(it.created.Add(-invoiceClockSkew))
+	ms, err := it.wallet.movementsFrom("LNX", since)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range ms {
+		if it.matches(m) && m.Status.IsSuccessful() {
+			return &InvoiceEvent{Type: InvoicePaid, Invoice: it.invoice, Movement: m}, nil
+		}
+	}
+	if now.Sub(it.created) >= it.expiry {
+		return &InvoiceEvent{Type: InvoiceExpired, Invoice: it.invoice}, nil
+	}
+	return nil, nil
+}
+
+func (it *InvoiceTracker) matches(m Movement2) bool {
+	if m.Amount <= 0 {
+		return false
+	}
+	return (it.invoice.InvoiceHash != "" && m.TransactionID == it.invoice.InvoiceHash) ||
+		(it.invoice.Invoice != "" && m.DestinationAddress == it.invoice.Invoice)
+}
+
+// Wait polls until the invoice is paid or expired, or ctx is done
+func (it *InvoiceTracker) Wait(ctx context.Context) (*InvoiceEvent, error) {
+	for {
+		ev, err := it.Poll()
+		if err != nil || ev != nil {
+			return ev, err
+		}
+
+		t := it.clock.NewTimer(it.interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C():
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/invoice"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lnxMovementRaw(id int64, status string, amount float64, hash string) []interface{} {
+	raw := movementRaw(id, 1000, status, amount)
+	raw[1] = "LNX"
+	raw[20] = hash
+	return raw
+}
+
+func TestInvoiceTracker(t *testing.T) {
+	var response []interface{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/r/movements/LNX/hist", r.RequestURI)
+		require.Nil(t, json.NewEncoder(w).Encode(response))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	inv := &invoice.Invoice{InvoiceHash: "hash1", Invoice: "lnbc1", Amount: "0.001"}
+
+	t.Run("paid", func(t *testing.T) {
+		response = []interface{}{
+			lnxMovementRaw(1, "COMPLETED", 0.002, "hash0"),
+			lnxMovementRaw(2, "PROCESSING", 0.001, "hash1"),
+		}
+		it := c.TrackInvoice(inv)
+		ev, err := it.Poll()
+		require.Nil(t, err)
+		assert.Nil(t, ev, "pending deposits do not settle the invoice")
+
+		response = []interface{}{
+			lnxMovementRaw(1, "COMPLETED", 0.002, "hash0"),
+			lnxMovementRaw(2, "COMPLETED", 0.001, "hash1"),
+		}
+		ev, err = it.Interval(time.Millisecond).Wait(context.Background())
+		require.Nil(t, err)
+		require.NotNil(t, ev)
+		assert.Equal(t, InvoicePaid, ev.Type)
+		assert.Equal(t, int64(2), ev.Movement.ID)
+		assert.Equal(t, inv, ev.Invoice)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		response = []interface{}{}
+		clock := utils.NewManualClock(time.Now())
+		it := c.TrackInvoice(inv).Clock(clock).Expiry(time.Minute)
+
+		ev, err := it.Poll()
+		require.Nil(t, err)
+		assert.Nil(t, ev)
+
+		clock.Advance(time.Minute)
+		ev, err = it.Poll()
+		require.Nil(t, err)
+		require.NotNil(t, ev)
+		assert.Equal(t, InvoiceExpired, ev.Type)
+	})
+
+	t.Run("paged", func(t *testing.T) {
+		// the settling deposit is older than the first page of movements
+		paged := movementsServer(func() [][]interface{} {
+			ms := [][]interface{}{}
+			for id := int64(1); id <= 50; id++ {
+				raw := lnxMovementRaw(id, "COMPLETED", 0.001, "other")
+				raw[5] = 1000 + id
+				ms = append(ms, raw)
+			}
+			ms[0][20] = "hash1"
+			return ms
+		})
+		defer paged.Close()
+
+		it := NewClientWithURL(paged.URL).TrackInvoice(inv).Clock(utils.NewManualClock(time.Unix(60, 0)))
+		ev, err := it.Poll()
+		require.Nil(t, err)
+		require.NotNil(t, ev)
+		assert.Equal(t, int64(1), ev.Movement.ID)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		response = []interface{}{}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := c.TrackInvoice(inv).Interval(time.Millisecond).Wait(ctx)
+		assert.Equal(t, context.DeadlineExceeded, err)
+	})
+}
@@ -0,0 +1,170 @@
+//go:build go1.23
+
+package rest
+
+import (
+	"context"
+	"iter"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/candle"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/ledger"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tradeexecutionupdate"
+)
+
+// candlesLimit is the maximum page size of the candles endpoint
+const candlesLimit = 10000
+
+// AccountHistorySeq returns an iterator over the trades of the account
+// matching q, fetching further pages as the loop advances, e.g.:
+//
+//	for t, err := range c.Trades.AccountHistorySeq(ctx, "tBTCUSD", rest.NewQuery().From(start)) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// An error is yielded once and ends the iteration.
+func (s *TradeService) AccountHistorySeq(ctx context.Context, symbol string, q *Query) iter.Seq2[*tradeexecutionupdate.TradeExecutionUpdate, error] {
+	return func(yield func(*tradeexecutionupdate.TradeExecutionUpdate, error) bool) {
+		it := s.AccountHistoryIterator(symbol, q)
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			trades, err := it.Next()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(trades) == 0 {
+				return
+			}
+			for _, t := range trades {
+				if !yield(t, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// PublicHistorySeq returns an iterator over the public trades of the symbol
+// matching q, fetching further pages as the loop advances, see
+// PublicTradeIterator. An error is yielded once and ends the iteration.
+func (s *TradeService) PublicHistorySeq(ctx context.Context, symbol string, q *Query) iter.Seq2[*trade.Trade, error] {
+	return func(yield func(*trade.Trade, error) bool) {
+		it := s.PublicHistoryIterator(symbol, q)
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			trades, err := it.Next()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(trades) == 0 {
+				return
+			}
+			for _, t := range trades {
+				if !yield(t, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// HistorySeq returns an iterator over the candles matching q, in the sort
+// order of q, fetching further pages as the loop advances. An error is
+// yielded once and ends the iteration.
+func (c *CandleService) HistorySeq(ctx context.Context, symbol string, resolution common.CandleResolution, q *Query) iter.Seq2[*candle.Candle, error] {
+	p := pager[*candle.Candle]{
+		limit: candlesLimit,
+		fetch: func(q *Query) ([]*candle.Candle, error) {
+			cs, err := c.HistoryQuery(symbol, resolution, q)
+			if err != nil {
+				return nil, err
+			}
+			return cs.Snapshot, nil
+		},
+		key: func(c *candle.Candle) (int64, int64) {
+			return c.MTS, c.MTS
+		},
+	}
+	return p.seq(ctx, q)
+}
+
+// MovementsSeq returns an iterator over the deposits and withdrawals of the
+// given currency matching q, newest first, fetching further pages as the loop
+// advances. An error is yielded once and ends the iteration.
+func (ws *WalletService) MovementsSeq(ctx context.Context, currency string, q *Query) iter.Seq2[Movement2, error] {
+	p := pager[Movement2]{
+		limit: movementsLimit,
+		fetch: func(q *Query) ([]Movement2, error) {
+			return ws.MovementsQuery(currency, q)
+		},
+		key: func(m Movement2) (int64, int64) {
+			return m.ID, m.MtsStarted
+		},
+	}
+	return p.seq(ctx, q)
+}
+
+// LedgersSeq returns an iterator over the ledger entries of the given
+// currency matching q, newest first, fetching further pages as the loop
+// advances. An error is yielded once and ends the iteration.
+func (s *LedgerService) LedgersSeq(ctx context.Context, currency string, q *Query) iter.Seq2[*ledger.Ledger, error] {
+	p := pager[*ledger.Ledger]{
+		limit: int(maxLimit),
+		fetch: func(q *Query) ([]*ledger.Ledger, error) {
+			lss, err := s.LedgersQuery(currency, q)
+			if err != nil {
+				return nil, err
+			}
+			return lss.Snapshot, nil
+		},
+		key: func(l *ledger.Ledger) (int64, int64) {
+			return l.ID, l.MTS
+		},
+	}
+	return p.seq(ctx, q)
+}
+
+// pager yields the records of a history endpoint page by page, see Pager
+type pager[T any] struct {
+	limit int
+	fetch func(q *Query) ([]T, error)
+	key   func(T) (id int64, mts int64)
+}
+
+func (p pager[T]) seq(ctx context.Context, q *Query) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		pg := NewPager(q, p.limit)
+		for !pg.Done() {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			records, err := p.fetch(pg.Query())
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, i := range pg.Page(len(records), func(i int) (int64, int64) { return p.key(records[i]) }) {
+				if !yield(records[i], nil) {
+					return
+				}
+			}
+		}
+		if err := pg.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
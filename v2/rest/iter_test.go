@@ -0,0 +1,95 @@
+//go:build go1.23
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgersSeq(t *testing.T) {
+	// 3000 entries, two of them sharing each timestamp
+	var all [][]interface{}
+	for id := int64(3000); id > 0; id-- {
+		all = append(all, []interface{}{id, "USD", nil, 100000 + id/2, nil, -1, 100, nil, "Settlement"})
+	}
+
+	requests := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var body map[string]float64
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		end, ok := body["end"]
+		if !ok {
+			end = 1 << 53
+		}
+		page := make([][]interface{}, 0)
+		for _, l := range all {
+			if mts := l[3].(int64); float64(mts) <= end && len(page) < int(body["limit"]) {
+				page = append(page, l)
+			}
+		}
+		require.Nil(t, json.NewEncoder(w).Encode(page))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL).Credentials("key", "secret")
+	seen := map[int64]bool{}
+	for l, err := range c.Ledgers.LedgersSeq(context.Background(), "USD", NewQuery()) {
+		require.Nil(t, err)
+		assert.False(t, seen[l.ID], "ledger %d yielded twice", l.ID)
+		seen[l.ID] = true
+	}
+	assert.Len(t, seen, 3000)
+	assert.Equal(t, 2, requests)
+
+	// breaking the loop stops fetching pages
+	requests, count := 0, 0
+	for _, err := range c.Ledgers.LedgersSeq(context.Background(), "USD", NewQuery()) {
+		require.Nil(t, err)
+		if count++; count == 10 {
+			break
+		}
+	}
+	assert.Equal(t, 1, requests)
+
+	// the limit of the query caps the total
+	count = 0
+	for range c.Ledgers.LedgersSeq(context.Background(), "USD", NewQuery().Limit(2600)) {
+		count++
+	}
+	assert.Equal(t, 2600, count)
+}
+
+func TestCandlesSeqError(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `["error",10001,"boom"]`)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	errs := 0
+	for cd, err := range c.Candles.HistorySeq(context.Background(), "tBTCUSD", common.OneHour, NewQuery()) {
+		assert.Nil(t, cd)
+		require.NotNil(t, err)
+		errs++
+	}
+	assert.Equal(t, 1, errs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, err := range c.Candles.HistorySeq(ctx, "tBTCUSD", common.OneHour, NewQuery()) {
+		assert.Equal(t, context.Canceled, err)
+	}
+}
@@ -0,0 +1,233 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/auth"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// DefaultKeyCooldown is how long a rate limited key is skipped by default
+var DefaultKeyCooldown = time.Minute
+
+// DefaultKeyDisable is how long a key rejected as invalid or for its nonce is
+// skipped by default before it is tried again
+var DefaultKeyDisable = 10 * time.Minute
+
+// KeyStats counts the requests sent with an api key
+type KeyStats struct {
+	Key         string
+	Requests    int64
+	Errors      int64
+	RateLimited int64
+	LastUsed    time.Time
+	CoolingDown bool // the key was rate limited and is skipped until CooldownEnd
+	CooldownEnd time.Time
+	// Disabled is set while a key rejected as invalid or for its nonce is
+	// skipped, until DisabledUntil or EnableKey
+	Disabled      bool
+	DisabledUntil time.Time
+	LastError     error
+}
+
+type poolKey struct {
+	creds auth.Credentials
+	stats KeyStats
+}
+
+// keyPool spreads the authenticated requests over several keys of the same
+// account in turn, skipping rate limited and rejected keys
+type keyPool struct {
+	mu       sync.Mutex
+	keys     []*poolKey
+	next     int
+	cooldown time.Duration
+	disable  time.Duration
+	now      func() time.Time
+}
+
+// Keys configures several api keys of the same account, which replace the
+// credentials of the client. Authenticated requests are spread over the keys
+// in turn. A request which is rate limited is sent again with the next key
+// and the key is skipped for DefaultKeyCooldown. A request rejected as the key
+// is invalid or its nonce too small is sent again as well and the key is
+// disabled for DefaultKeyDisable, or until EnableKey. Websocket sessions may
+// use the same keys through KeyProvider.
+func (c *Client) Keys(keys ...auth.Credentials) (*Client, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: at least one key is required", common.ErrBadRequest)
+	}
+	p := &keyPool{cooldown: DefaultKeyCooldown, disable: DefaultKeyDisable, now: time.Now}
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k.Key == "" || k.Secret == "" {
+			return nil, fmt.Errorf("%w: key and secret are required", common.ErrBadRequest)
+		}
+		if seen[k.Key] {
+			return nil, fmt.Errorf("%w: duplicate key %s", common.ErrBadRequest, k.Key)
+		}
+		seen[k.Key] = true
+		p.keys = append(p.keys, &poolKey{creds: k, stats: KeyStats{Key: k.Key}})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keys = p
+	c.credentials = p
+	return c, nil
+}
+
+// KeyProvider returns the provider of the keys configured with Keys, which
+// hands out the keys in turn, or nil without keys.
+func (c *Client) KeyProvider() auth.CredentialsProvider {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.keys == nil {
+		return nil
+	}
+	return c.keys
+}
+
+// KeyStats returns the usage of the keys configured with Keys in the order
+// they were configured.
+func (c *Client) KeyStats() []KeyStats {
+	c.mu.RLock()
+	p := c.keys
+	c.mu.RUnlock()
+	if p == nil {
+		return nil
+	}
+	return p.snapshot()
+}
+
+// EnableKey uses a key configured with Keys again which was disabled, e.g.
+// once its permissions were fixed
+func (c *Client) EnableKey(key string) error {
+	c.mu.RLock()
+	p := c.keys
+	c.mu.RUnlock()
+	if p == nil {
+		return fmt.Errorf("%w: no keys configured", common.ErrBadRequest)
+	}
+	return p.enable(key)
+}
+
+func (p *keyPool) size() int {
+	return len(p.keys)
+}
+
+// Credentials returns the next usable key. If all usable keys are cooling
+// down, the one whose cooldown ends first is returned.
+func (p *keyPool) Credentials(ctx context.Context) (auth.Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var fallback *poolKey
+	for i := 0; i < len(p.keys); i++ {
+		k := p.keys[(p.next+i)%len(p.keys)]
+		if k.stats.Disabled && now.Before(k.stats.DisabledUntil) {
+			continue
+		}
+		k.stats.Disabled = false
+		if k.stats.CoolingDown && now.Before(k.stats.CooldownEnd) {
+			if fallback == nil || k.stats.CooldownEnd.Before(fallback.stats.CooldownEnd) {
+				fallback = k
+			}
+			continue
+		}
+		k.stats.CoolingDown = false
+		p.next = (p.next + i + 1) % len(p.keys)
+		return p.use(k, now), nil
+	}
+	if fallback == nil {
+		return auth.Credentials{}, fmt.Errorf("%w: all keys are disabled", common.ErrUnauthorized)
+	}
+	return p.use(fallback, now), nil
+}
+
+func (p *keyPool) use(k *poolKey, now time.Time) auth.Credentials {
+	k.stats.Requests++
+	k.stats.LastUsed = now
+	return k.creds
+}
+
+// report records the outcome of a request sent with the key and returns
+// whether it should be sent again with another key
+func (p *keyPool) report(key string, err error) bool {
+	if err == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k := p.find(key)
+	if k == nil {
+		return false
+	}
+	k.stats.Errors++
+	k.stats.LastError = err
+
+	var er *ErrorResponse
+	if !errors.As(err, &er) {
+		return false
+	}
+	switch {
+	case RateLimited(er):
+		k.stats.RateLimited++
+		k.stats.CoolingDown = true
+		k.stats.CooldownEnd = p.now().Add(p.cooldown)
+		return true
+	case er.Code == ErrorCodeAuthNonce,
+		er.Code == ErrorCodeAuthFail && strings.Contains(er.Message, "invalid"):
+		// the key is unknown, revoked or its nonce is ahead of this client
+		k.stats.Disabled = true
+		k.stats.DisabledUntil = p.now().Add(p.disable)
+		return true
+	case er.Code == ErrorCodeAuthFail:
+		// e.g. a missing permission, which another key may have
+		return true
+	}
+	return false
+}
+
+func (p *keyPool) enable(key string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	k := p.find(key)
+	if k == nil {
+		return fmt.Errorf("%w: key %s", common.ErrNotFound, key)
+	}
+	k.stats.Disabled = false
+	k.stats.DisabledUntil = time.Time{}
+	return nil
+}
+
+// find returns the key, it is called with the lock held
+func (p *keyPool) find(key string) *poolKey {
+	for _, k := range p.keys {
+		if k.creds.Key == key {
+			return k
+		}
+	}
+	return nil
+}
+
+func (p *keyPool) snapshot() []KeyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	out := make([]KeyStats, 0, len(p.keys))
+	for _, k := range p.keys {
+		s := k.stats
+		s.CoolingDown = s.CoolingDown && now.Before(s.CooldownEnd)
+		s.Disabled = s.Disabled && now.Before(s.DisabledUntil)
+		out = append(out, s)
+	}
+	return out
+}
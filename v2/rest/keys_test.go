@@ -0,0 +1,164 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/auth"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeys(t *testing.T) {
+	var mu sync.Mutex
+	var used []string
+	limited := map[string]bool{"key1": true}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("bfx-apikey")
+		mu.Lock()
+		used = append(used, key)
+		limit := limited[key]
+		mu.Unlock()
+
+		switch {
+		case limit:
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`["error",11010,"ratelimit: error"]`))
+		case key == "key3":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`["error",10100,"apikey: invalid"]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	_, err := NewClientWithURL(server.URL).Keys(auth.Credentials{Key: "key1"})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	c, err := NewClientWithURL(server.URL).Keys(
+		auth.Credentials{Key: "key1", Secret: "secret1"},
+		auth.Credentials{Key: "key2", Secret: "secret2"},
+		auth.Credentials{Key: "key3", Secret: "secret3"},
+	)
+	require.Nil(t, err)
+	now := time.Unix(1600000000, 0)
+	c.keys.now = func() time.Time { return now }
+
+	send := func() error {
+		req, err := c.NewAuthenticatedRequest(common.PermissionRead, "wallets")
+		require.Nil(t, err)
+		_, err = c.Request(req)
+		return err
+	}
+
+	// the rate limited key fails over to the next one
+	require.Nil(t, send())
+	assert.Equal(t, []string{"key1", "key2"}, used)
+
+	// the rejected key fails over and is disabled, the cooling key skipped
+	used = nil
+	require.Nil(t, send())
+	assert.Equal(t, []string{"key3", "key2"}, used)
+	used = nil
+	require.Nil(t, send())
+	require.Nil(t, send())
+	assert.Equal(t, []string{"key2", "key2"}, used)
+
+	stats := c.KeyStats()
+	require.Len(t, stats, 3)
+	assert.Equal(t, int64(1), stats[0].RateLimited)
+	assert.True(t, stats[0].CoolingDown)
+	assert.Equal(t, int64(4), stats[1].Requests)
+	assert.Equal(t, int64(0), stats[1].Errors)
+	assert.True(t, stats[2].Disabled)
+	assert.NotNil(t, stats[2].LastError)
+
+	// the key is used again after the cooldown
+	now = now.Add(DefaultKeyCooldown)
+	mu.Lock()
+	limited["key1"] = false
+	used = nil
+	mu.Unlock()
+	require.Nil(t, send())
+	assert.Equal(t, []string{"key1"}, used)
+	assert.False(t, c.KeyStats()[0].CoolingDown)
+}
+
+func TestKeysDisable(t *testing.T) {
+	var mu sync.Mutex
+	var used []string
+	reject := map[string]string{
+		"key1": `["error",10114,"nonce: small"]`,
+		"key2": `["error",10100,"apikey: no permission"]`,
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("bfx-apikey")
+		mu.Lock()
+		used = append(used, key)
+		body, ok := reject[key]
+		mu.Unlock()
+
+		if ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c, err := NewClientWithURL(server.URL).Keys(
+		auth.Credentials{Key: "key1", Secret: "secret1"},
+		auth.Credentials{Key: "key2", Secret: "secret2"},
+		auth.Credentials{Key: "key3", Secret: "secret3"},
+	)
+	require.Nil(t, err)
+	now := time.Unix(1600000000, 0)
+	c.keys.now = func() time.Time { return now }
+
+	send := func() error {
+		req, err := c.NewAuthenticatedRequest(common.PermissionRead, "wallets")
+		require.Nil(t, err)
+		_, err = c.Request(req)
+		return err
+	}
+
+	// the nonce error disables the key, the missing permission only fails over
+	require.Nil(t, send())
+	assert.Equal(t, []string{"key1", "key2", "key3"}, used)
+	stats := c.KeyStats()
+	assert.True(t, stats[0].Disabled)
+	assert.False(t, stats[1].Disabled)
+
+	used = nil
+	require.Nil(t, send())
+	require.Nil(t, send())
+	assert.NotContains(t, used, "key1")
+
+	// a disabled key is enabled again explicitly or once the period passed
+	mu.Lock()
+	delete(reject, "key1")
+	used = nil
+	mu.Unlock()
+	require.Nil(t, c.EnableKey("key1"))
+	assert.False(t, c.KeyStats()[0].Disabled)
+	assert.True(t, errors.Is(c.EnableKey("key9"), common.ErrNotFound))
+
+	mu.Lock()
+	reject["key1"] = `["error",10100,"apikey: invalid"]`
+	mu.Unlock()
+	for i := 0; i < 3; i++ {
+		_ = send()
+	}
+	assert.True(t, c.KeyStats()[0].Disabled)
+	now = now.Add(DefaultKeyDisable)
+	assert.False(t, c.KeyStats()[0].Disabled)
+}
@@ -19,25 +19,96 @@ var maxLimit int32 = 2500
 // Ledgers - all of the past ledger entreies
 // see https://docs.bitfinex.com/reference#ledgers for more info
 func (s *LedgerService) Ledgers(currency string, start int64, end int64, max int32) (*ledger.Snapshot, error) {
-	if max > maxLimit {
-		return nil, fmt.Errorf("Max request limit:%d, got: %d", maxLimit, max)
+	q := NewQuery().
+		FromMts(common.Mts(start)).
+		ToMts(common.Mts(end)).
+		Limit(int(max))
+
+	return s.LedgersQuery(currency, q)
+}
+
+// LedgersQuery - past ledger entries of the given currency matching the given query
+// see https://docs.bitfinex.com/reference#ledgers for more info
+func (s *LedgerService) LedgersQuery(currency string, q *Query) (*ledger.Snapshot, error) {
+	if q.exceedsLimit(int(maxLimit)) {
+		return nil, fmt.Errorf("%w: max request limit:%d, got: %d", common.ErrBadRequest, maxLimit, *q.limit)
 	}
 
-	payload := map[string]interface{}{"start": start, "end": end, "limit": max}
-	req, err := s.requestFactory.NewAuthenticatedRequestWithData(common.PermissionRead, path.Join("ledgers", currency, "hist"), payload)
+	raw, err := s.ledgers(currency, q)
 	if err != nil {
 		return nil, err
 	}
+	if len(raw) == 0 {
+		return &ledger.Snapshot{Snapshot: make([]*ledger.Ledger, 0)}, nil
+	}
 
-	raw, err := s.Request(req)
+	lss, err := ledger.SnapshotFromRaw(raw, ledger.FromRaw, decoding(s.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
 
-	lss, err := ledger.SnapshotFromRaw(raw, ledger.FromRaw)
+	return lss, nil
+}
+
+// LedgersAll - all past ledger entries of the given currency matching the given query,
+// fetching as many pages as needed. The limit of the query caps the total number of
+// entries returned. Entries are ordered newest first.
+// see https://docs.bitfinex.com/reference#ledgers for more info
+func (s *LedgerService) LedgersAll(currency string, q *Query) ([]*ledger.Ledger, error) {
+	total := 0
+	if q != nil && q.limit != nil {
+		total = *q.limit
+	}
+
+	page := q.clone().Limit(int(maxLimit))
+	page.sort = nil
+	seen := make(map[int64]bool)
+	out := make([]*ledger.Ledger, 0)
+	for {
+		raw, err := s.ledgers(currency, page)
+		if err != nil {
+			return nil, err
+		}
+		if len(raw) == 0 {
+			return out, nil
+		}
+
+		lss, err := ledger.SnapshotFromRaw(raw, ledger.FromRaw, decoding(s.Synchronous)...)
+		if err != nil {
+			return nil, err
+		}
+
+		oldest := lss.Snapshot[0].MTS
+		added := 0
+		for _, l := range lss.Snapshot {
+			if l.MTS < oldest {
+				oldest = l.MTS
+			}
+			if seen[l.ID] {
+				continue
+			}
+			seen[l.ID] = true
+			out = append(out, l)
+			added++
+			if total > 0 && len(out) == total {
+				return out, nil
+			}
+		}
+
+		// entries sharing the oldest timestamp are requested again and
+		// skipped above, a page without new entries ends the pagination
+		if len(raw) < int(maxLimit) || added == 0 {
+			return out, nil
+		}
+		page.ToMts(common.Mts(oldest))
+	}
+}
+
+func (s *LedgerService) ledgers(currency string, q *Query) ([]interface{}, error) {
+	req, err := s.requestFactory.NewAuthenticatedRequestWithData(common.PermissionRead, path.Join("ledgers", currency, "hist"), q.payload())
 	if err != nil {
 		return nil, err
 	}
 
-	return lss, nil
+	return s.Request(req)
 }
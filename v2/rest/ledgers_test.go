@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgersAll(t *testing.T) {
+	// 2500 entries in the first page, the last two share a timestamp with the
+	// first entries of the second page
+	var pages []string
+	entries := make([]string, 0, maxLimit)
+	for i := 0; i < int(maxLimit); i++ {
+		mts := 1568123933000 - i
+		if i == int(maxLimit)-1 {
+			mts++
+		}
+		entries = append(entries, fmt.Sprintf(`[%d,"USD",null,%d,null,-1,100,null,"Settlement"]`, 10000-i, mts))
+	}
+	pages = append(pages, "["+strings.Join(entries, ",")+"]")
+	pages = append(pages, fmt.Sprintf(`[[%d,"USD",null,%d,null,-1,100,null,"Settlement"],[1,"USD",null,1568123930000,null,-1,100,null,"Settlement"]]`, 10000-int(maxLimit)+1, 1568123933000-int(maxLimit)+2))
+
+	var ends []float64
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/r/ledgers/USD/hist", r.RequestURI)
+
+		pld := map[string]interface{}{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&pld))
+		assert.Equal(t, float64(maxLimit), pld["limit"])
+		end, _ := pld["end"].(float64)
+		ends = append(ends, end)
+
+		_, err := w.Write([]byte(pages[len(ends)-1]))
+		require.Nil(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	ls, err := NewClientWithURL(server.URL).Ledgers.LedgersAll("USD", NewQuery())
+	require.Nil(t, err)
+	assert.Len(t, ls, int(maxLimit)+1)
+	assert.Equal(t, []float64{0, float64(1568123933000 - int(maxLimit) + 2)}, ends)
+	assert.Equal(t, int64(1), ls[len(ls)-1].ID)
+
+	t.Run("limit caps total", func(t *testing.T) {
+		ends = nil
+		ls, err := NewClientWithURL(server.URL).Ledgers.LedgersAll("USD", NewQuery().Limit(10))
+		require.Nil(t, err)
+		assert.Len(t, ls, 10)
+	})
+}
@@ -0,0 +1,128 @@
+package rest
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+)
+
+// DefaultMaintenancePoll is the interval in which queued write requests poll
+// the platform status during maintenance
+const DefaultMaintenancePoll = 10 * time.Second
+
+// maintenanceState tracks whether the platform is in maintenance
+type maintenanceState struct {
+	gate     utils.MaintenanceGate
+	poll     time.Duration
+	mu       sync.Mutex
+	polling  bool
+	stop     chan struct{} // closed by Client.Close
+	stopOnce sync.Once
+}
+
+// GateMaintenance sets how write requests, i.e. auth/w/* endpoints, are
+// handled while the platform is in maintenance. A maintenance starts with a
+// response failing with common.ErrMaintenance or a platform status other than
+// operative, and ends with an operative platform status. Unless mode is
+// common.MaintenanceAllow the status is polled every pollInterval, defaulting
+// to DefaultMaintenancePoll, until the maintenance ended or the client is
+// closed.
+func (c *Client) GateMaintenance(mode common.MaintenanceMode, pollInterval time.Duration) *Client {
+	if pollInterval <= 0 {
+		pollInterval = DefaultMaintenancePoll
+	}
+	c.maintenance.mu.Lock()
+	c.maintenance.poll = pollInterval
+	c.maintenance.mu.Unlock()
+	c.maintenance.gate.SetMode(mode)
+	return c
+}
+
+// InMaintenance reports whether the platform was last seen in maintenance
+func (c *Client) InMaintenance() bool {
+	return c.maintenance.gate.Active()
+}
+
+func isWriteRequest(req Request) bool {
+	return strings.HasPrefix(req.RefURL, "auth/w/")
+}
+
+// awaitMaintenance holds back write requests according to the maintenance
+// mode of the client
+func (c *Client) awaitMaintenance(req Request) error {
+	if !isWriteRequest(req) {
+		return nil
+	}
+	if c.maintenance.gate.Active() {
+		c.pollMaintenance()
+	}
+	return c.maintenance.gate.Wait(req.Context())
+}
+
+// observeMaintenance updates the maintenance state from a response
+func (c *Client) observeMaintenance(req Request, raw []interface{}, err error) {
+	switch {
+	case errors.Is(err, common.ErrMaintenance):
+		c.maintenance.gate.Start()
+		c.pollMaintenance()
+	case err == nil && req.RefURL == "platform/status" && len(raw) > 0:
+		if convert.IValOrZero(raw[0]) == 1 {
+			c.maintenance.gate.End()
+		} else {
+			c.maintenance.gate.Start()
+			c.pollMaintenance()
+		}
+	}
+}
+
+// pollMaintenance polls the platform status until the maintenance ended or
+// the client is closed, unless a poll is running already or write requests
+// are not gated
+func (c *Client) pollMaintenance() {
+	m := &c.maintenance
+	if m.gate.CurrentMode() == common.MaintenanceAllow {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.polling {
+		return
+	}
+	m.polling = true
+	ticker := time.NewTicker(m.poll)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stop:
+				m.mu.Lock()
+				m.polling = false
+				m.mu.Unlock()
+				return
+			case <-ticker.C:
+			}
+			// the response updates the gate, errors are retried
+			_, _ = c.Platform.Status()
+			m.mu.Lock()
+			if !m.gate.Active() {
+				m.polling = false
+				m.mu.Unlock()
+				return
+			}
+			m.mu.Unlock()
+		}
+	}()
+}
+
+// Close stops the background work of the client, i.e. polling the platform
+// status during maintenance. Requests may still be sent afterwards.
+func (c *Client) Close() {
+	c.maintenance.stopOnce.Do(func() {
+		close(c.maintenance.stop)
+	})
+}
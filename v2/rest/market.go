@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 )
 
 type MarketService struct {
@@ -57,7 +58,7 @@ func (ms *MarketService) AveragePrice(pld AveragePriceRequest) ([]float64, error
 // See: https://docs.bitfinex.com/reference#rest-public-calc-foreign-exchange-rate
 func (ms *MarketService) ForeignExchangeRate(pld ForeignExchangeRateRequest) ([]float64, error) {
 	if len(pld.FirstCurrency) == 0 || len(pld.SecondCurrency) == 0 {
-		return nil, fmt.Errorf("FirstCurrency and SecondCurrency are required arguments")
+		return nil, fmt.Errorf("%w: FirstCurrency and SecondCurrency are required arguments", common.ErrBadRequest)
 	}
 
 	bytes, err := json.Marshal(pld)
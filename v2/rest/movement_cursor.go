@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"sort"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// MovementsSince retrieves the deposits and withdrawals of all currencies
+// which were created or updated since cursor was returned, oldest update
+// first, along with the cursor to pass on the next call. A nil cursor
+// returns all movements. As the polled range stays open from the oldest
+// movement which is not final, see DepositCursor, a movement completing long
+// after it was started is still returned. Pollers persist the new cursor,
+// e.g. with a StoreCursorStore, only after handling the movements to receive
+// every update at least once across restarts.
+func (ws *WalletService) MovementsSince(cursor *DepositCursor) ([]Movement2, *DepositCursor, error) {
+	if cursor == nil {
+		cursor = &DepositCursor{}
+	}
+	ms, err := ws.movementsFrom("", common.Mts(cursor.Since))
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	since := make([]Movement2, 0, len(ms))
+	for _, m := range ms {
+		if mts, ok := cursor.Updated[m.ID]; !ok || mts != m.MtsUpdated {
+			since = append(since, m)
+		}
+	}
+	sort.SliceStable(since, func(i, j int) bool {
+		if since[i].MtsUpdated != since[j].MtsUpdated {
+			return since[i].MtsUpdated < since[j].MtsUpdated
+		}
+		return since[i].ID < since[j].ID
+	})
+	return since, cursor.advance(ms), nil
+}
@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovementsSince(t *testing.T) {
+	var movements [][]interface{}
+	server := movementsServer(func() [][]interface{} { return movements })
+	defer server.Close()
+	c := NewClientWithURL(server.URL)
+
+	ids := func(ms []Movement2) []int64 {
+		out := []int64{}
+		for _, m := range ms {
+			out = append(out, m.ID)
+		}
+		return out
+	}
+
+	movements = [][]interface{}{
+		movementRaw(1, 1000, "COMPLETED", 1),
+		movementRaw(2, 2000, "PROCESSING", 1),
+		movementRaw(3, 3000, "COMPLETED", -1),
+	}
+	ms, cursor, err := c.Wallet.MovementsSince(nil)
+	require.Nil(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, ids(ms))
+	assert.Equal(t, int64(2000), cursor.Since)
+
+	// 2 was started before the newest movement and completes afterwards
+	completed := movementRaw(2, 2000, "COMPLETED", 1)
+	completed[6] = int64(9000)
+	movements = [][]interface{}{movements[0], completed, movements[2], movementRaw(4, 5000, "COMPLETED", 1)}
+	ms, cursor, err = c.Wallet.MovementsSince(cursor)
+	require.Nil(t, err)
+	assert.Equal(t, []int64{4, 2}, ids(ms))
+	assert.Equal(t, int64(5000), cursor.Since)
+
+	ms, same, err := c.Wallet.MovementsSince(cursor)
+	require.Nil(t, err)
+	assert.Len(t, ms, 0)
+	assert.Equal(t, cursor, same)
+}
+
+func TestMovementsSincePages(t *testing.T) {
+	// more movements than fit into a page, all updated after the cursor
+	var movements [][]interface{}
+	for id := int64(1); id <= 1500; id++ {
+		movements = append(movements, movementRaw(id, 1000+id, "COMPLETED", 1))
+	}
+	server := movementsServer(func() [][]interface{} { return movements })
+	defer server.Close()
+
+	ms, cursor, err := NewClientWithURL(server.URL).Wallet.MovementsSince(nil)
+	require.Nil(t, err)
+	require.Len(t, ms, 1500)
+	for i, m := range ms {
+		assert.Equal(t, int64(i+1), m.ID)
+	}
+	assert.Equal(t, int64(2500), cursor.Since)
+}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"path"
@@ -78,6 +79,28 @@ func (s *OrderService) GetHistoryBySymbol(symbol string) (*order.Snapshot, error
 	return s.getHistoricalOrders(symbol)
 }
 
+// HistoryQuery retrieves past orders of the given symbol matching the given
+// query. An empty symbol returns orders of all symbols.
+// See https://docs.bitfinex.com/reference#orders-history for more info
+func (s *OrderService) HistoryQuery(symbol string, q *Query) (*order.Snapshot, error) {
+	if q.exceedsLimit(int(maxLimit)) {
+		return nil, fmt.Errorf("%w: max request limit:%d, got: %d", common.ErrBadRequest, maxLimit, *q.limit)
+	}
+
+	req, err := s.requestFactory.NewAuthenticatedRequestWithData(common.PermissionRead, path.Join("orders", symbol, "hist"), q.payload())
+	if err != nil {
+		return nil, err
+	}
+	raw, err := s.Request(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return &order.Snapshot{}, nil
+	}
+	return order.SnapshotFromRaw(raw, decoding(s.Synchronous)...)
+}
+
 // Retrieve a single order in history with the given id
 // See https://docs.bitfinex.com/reference#orders-history for more info
 func (s *OrderService) GetHistoryByOrderId(orderID int64) (o *order.Order, err error) {
@@ -105,7 +128,7 @@ func (s *OrderService) OrderTrades(symbol string, orderID int64) (*tradeexecutio
 	if err != nil {
 		return nil, err
 	}
-	return tradeexecutionupdate.SnapshotFromRaw(raw)
+	return tradeexecutionupdate.SnapshotFromRaw(raw, decoding(s.Synchronous)...)
 }
 
 func (s *OrderService) getActiveOrders(symbol string) (*order.Snapshot, error) {
@@ -117,7 +140,7 @@ func (s *OrderService) getActiveOrders(symbol string) (*order.Snapshot, error) {
 	if err != nil {
 		return nil, err
 	}
-	os, err := order.SnapshotFromRaw(raw)
+	os, err := order.SnapshotFromRaw(raw, decoding(s.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -136,7 +159,7 @@ func (s *OrderService) getHistoricalOrders(symbol string) (*order.Snapshot, erro
 	if err != nil {
 		return nil, err
 	}
-	os, err := order.SnapshotFromRaw(raw)
+	os, err := order.SnapshotFromRaw(raw, decoding(s.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +184,43 @@ func (s *OrderService) SubmitOrder(onr *order.NewRequest) (*notification.Notific
 	if err != nil {
 		return nil, err
 	}
-	return notification.FromRaw(raw)
+	return notificationFromRaw(raw, decoding(s.Synchronous)...)
+}
+
+// PlaceOrder submits a new order and returns it as accepted by the exchange.
+// OCO orders are accepted as two orders, of which the first one is returned.
+// see https://docs.bitfinex.com/reference#submit-order for more info
+func (s *OrderService) PlaceOrder(ctx context.Context, onr *order.NewRequest) (*order.Order, error) {
+	bytes, err := onr.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.requestFactory.NewAuthenticatedRequestWithBytes(common.PermissionWrite, path.Join("order", "submit"), bytes)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := s.Request(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	n, err := notification.FromRaw(raw, decoding(s.Synchronous)...)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.Err(); err != nil {
+		return nil, fmt.Errorf("order on %s rejected: %w", onr.Symbol, err)
+	}
+
+	switch info := n.NotifyInfo.(type) {
+	case *order.Snapshot:
+		if len(info.Snapshot) > 0 {
+			return info.Snapshot[0], nil
+		}
+	case order.New:
+		o := order.Order(info)
+		return &o, nil
+	}
+	return nil, fmt.Errorf("unexpected notification for new order: %#v", n.NotifyInfo)
 }
 
 // Submit a request to update an order with the given id with the given changes
@@ -179,7 +238,58 @@ func (s *OrderService) SubmitUpdateOrder(our *order.UpdateRequest) (*notificatio
 	if err != nil {
 		return nil, err
 	}
-	return notification.FromRaw(raw)
+	return notificationFromRaw(raw, decoding(s.Synchronous)...)
+}
+
+// UpdateOrder amends an open order in place and returns its updated state.
+// Only the fields set in the request are changed: Amount replaces the
+// remaining amount, Delta adds to it. Use UpdateRequest.PreservesPriority
+// or UpdateOrderKeepPriority to avoid losing the place in the queue.
+// see https://docs.bitfinex.com/reference#order-update for more info
+func (s *OrderService) UpdateOrder(ctx context.Context, our *order.UpdateRequest) (*order.Update, error) {
+	if err := our.Validate(); err != nil {
+		return nil, err
+	}
+	bytes, err := our.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.requestFactory.NewAuthenticatedRequestWithBytes(common.PermissionWrite, path.Join("order", "update"), bytes)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := s.Request(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	n, err := notification.FromRaw(raw, decoding(s.Synchronous)...)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.Err(); err != nil {
+		return nil, fmt.Errorf("update of order %d rejected: %w", our.ID, err)
+	}
+
+	switch info := n.NotifyInfo.(type) {
+	case order.Update:
+		return &info, nil
+	case *order.Update:
+		return info, nil
+	}
+	return nil, fmt.Errorf("unexpected notification for order update: %#v", n.NotifyInfo)
+}
+
+// UpdateOrderKeepPriority is like UpdateOrder, but rejects updates which
+// would move current to the end of the queue of its price level with
+// ErrBadRequest instead of submitting them.
+func (s *OrderService) UpdateOrderKeepPriority(ctx context.Context, current *order.Order, our *order.UpdateRequest) (*order.Update, error) {
+	if current.ID != our.ID {
+		return nil, fmt.Errorf("%w: update for order %d applied to order %d", common.ErrBadRequest, our.ID, current.ID)
+	}
+	if !our.PreservesPriority(current) {
+		return nil, fmt.Errorf("%w: update would lose queue priority of order %d", common.ErrBadRequest, our.ID)
+	}
+	return s.UpdateOrder(ctx, our)
 }
 
 // Submit a request to cancel an order with the given Id
@@ -200,6 +310,21 @@ func (s *OrderService) SubmitCancelOrder(oc *order.CancelRequest) error {
 	return nil
 }
 
+// CancelOrder cancels the order with the given id
+// see https://docs.bitfinex.com/reference#cancel-order for more info
+func (s *OrderService) CancelOrder(ctx context.Context, id int64) error {
+	bytes, err := (&order.CancelRequest{ID: id}).ToJSON()
+	if err != nil {
+		return err
+	}
+	req, err := s.requestFactory.NewAuthenticatedRequestWithBytes(common.PermissionWrite, path.Join("order", "cancel"), bytes)
+	if err != nil {
+		return err
+	}
+	_, err = s.Request(req.WithContext(ctx))
+	return err
+}
+
 // CancelOrderMulti cancels multiple orders simultaneously. Orders can be canceled based on the Order ID,
 // the combination of Client Order ID and Client Order Date, or the Group Order ID. Alternatively, the body
 // param 'all' can be used with a value of 1 to cancel all orders.
@@ -224,7 +349,7 @@ func (s *OrderService) CancelOrderMulti(args CancelOrderMultiRequest) (*notifica
 		return nil, err
 	}
 
-	return notification.FromRaw(raw)
+	return notificationFromRaw(raw, decoding(s.Synchronous)...)
 }
 
 // CancelOrdersMultiOp cancels multiple orders simultaneously. Accepts a slice of order ID's to be canceled.
@@ -258,7 +383,7 @@ func (s *OrderService) CancelOrdersMultiOp(ids OrderIDs) (*notification.Notifica
 		return nil, err
 	}
 
-	return notification.FromRaw(raw)
+	return notificationFromRaw(raw, decoding(s.Synchronous)...)
 }
 
 // CancelOrderMultiOp cancels order. Accepts orderID to be canceled.
@@ -292,7 +417,7 @@ func (s *OrderService) CancelOrderMultiOp(orderID int) (*notification.Notificati
 		return nil, err
 	}
 
-	return notification.FromRaw(raw)
+	return notificationFromRaw(raw, decoding(s.Synchronous)...)
 }
 
 // OrderNewMultiOp creates new order. Accepts instance of order.NewRequest
@@ -326,7 +451,7 @@ func (s *OrderService) OrderNewMultiOp(onr order.NewRequest) (*notification.Noti
 		return nil, err
 	}
 
-	return notification.FromRaw(raw)
+	return notificationFromRaw(raw, decoding(s.Synchronous)...)
 }
 
 // OrderUpdateMultiOp updates order. Accepts instance of order.UpdateRequest
@@ -360,7 +485,7 @@ func (s *OrderService) OrderUpdateMultiOp(our order.UpdateRequest) (*notificatio
 		return nil, err
 	}
 
-	return notification.FromRaw(raw)
+	return notificationFromRaw(raw, decoding(s.Synchronous)...)
 }
 
 // OrderMultiOp - send Multiple order-related operations. Please note the sent object has
@@ -373,7 +498,7 @@ func (s *OrderService) OrderMultiOp(ops OrderOps) (*notification.Notification, e
 		if v[0] == "on" {
 			o, ok := v[1].(order.NewRequest)
 			if !ok {
-				return nil, fmt.Errorf("Invalid type for `on` operation. Expected: order.NewRequest")
+				return nil, fmt.Errorf("%w: invalid type for `on` operation. Expected: order.NewRequest", common.ErrBadRequest)
 			}
 			v[1] = o.EnrichedPayload()
 		}
@@ -381,7 +506,7 @@ func (s *OrderService) OrderMultiOp(ops OrderOps) (*notification.Notification, e
 		if v[0] == "ou" {
 			o, ok := v[1].(order.UpdateRequest)
 			if !ok {
-				return nil, fmt.Errorf("Invalid type for `ou` operation. Expected: order.UpdateRequest")
+				return nil, fmt.Errorf("%w: invalid type for `ou` operation. Expected: order.UpdateRequest", common.ErrBadRequest)
 			}
 			v[1] = o.EnrichedPayload()
 		}
@@ -409,5 +534,40 @@ func (s *OrderService) OrderMultiOp(ops OrderOps) (*notification.Notification, e
 		return nil, err
 	}
 
-	return notification.FromRaw(raw)
+	return notificationFromRaw(raw, decoding(s.Synchronous)...)
+}
+
+// SubmitLadder submits all orders of the ladder in a single order/multi request.
+// A group id is assigned if the ladder has none, and is returned so that the
+// whole ladder can be cancelled with CancelGroup.
+// see https://docs.bitfinex.com/reference#rest-auth-order-multi for more info
+func (s *OrderService) SubmitLadder(l order.Ladder) (int64, *notification.Notification, error) {
+	if l.GID == 0 {
+		l.GID = order.NewGID()
+	}
+
+	orders, err := l.Build()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	ops := make(OrderOps, 0, len(orders))
+	for _, o := range orders {
+		ops = append(ops, []interface{}{"on", o})
+	}
+
+	n, err := s.OrderMultiOp(ops)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return l.GID, n, nil
+}
+
+// CancelGroup cancels all orders of the given group id
+// see https://docs.bitfinex.com/reference#rest-auth-order-cancel-multi for more info
+func (s *OrderService) CancelGroup(gid int64) (*notification.Notification, error) {
+	return s.CancelOrderMulti(CancelOrderMultiRequest{
+		GroupOrderIDs: GroupOrderIDs{int(gid)},
+	})
 }
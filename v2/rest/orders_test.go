@@ -2,7 +2,9 @@ package rest
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -67,6 +69,29 @@ func TestOrdersHistory(t *testing.T) {
 	}
 }
 
+func TestOrdersHistoryQuery(t *testing.T) {
+	httpDo := func(_ *http.Client, req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/v2/auth/r/orders/hist", req.URL.Path)
+		var body map[string]interface{}
+		require.Nil(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"start": float64(1573482478000), "limit": float64(100)}, body)
+
+		resp := http.Response{
+			Body:       ioutil.NopCloser(bytes.NewBufferString(`[]`)),
+			StatusCode: 200,
+		}
+		return &resp, nil
+	}
+
+	c := NewClientWithHttpDo(httpDo)
+	orders, err := c.Orders.HistoryQuery("", NewQuery().FromMts(1573482478000).Limit(100))
+	require.Nil(t, err)
+	assert.Len(t, orders.Snapshot, 0)
+
+	_, err = c.Orders.HistoryQuery("", NewQuery().Limit(2501))
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
+
 func TestCancelOrderMulti(t *testing.T) {
 	t.Run("calls correct resource with correct payload", func(t *testing.T) {
 		handler := func(w http.ResponseWriter, r *http.Request) {
@@ -375,3 +400,135 @@ func TestOrderMultiOp(t *testing.T) {
 		assert.Equal(t, int64(1568711312683), rsp.MTS)
 	})
 }
+
+func TestSubmitLadder(t *testing.T) {
+	t.Run("submits orders with shared gid", func(t *testing.T) {
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/auth/w/order/multi", r.RequestURI)
+
+			gotReqPld := OrderMultiOpsRequest{}
+			err := json.NewDecoder(r.Body).Decode(&gotReqPld)
+			require.Nil(t, err)
+			require.Len(t, gotReqPld.Ops, 3)
+
+			for i, price := range []string{"9000", "8800", "8600"} {
+				assert.Equal(t, "on", gotReqPld.Ops[i][0])
+				o := gotReqPld.Ops[i][1].(map[string]interface{})
+				assert.Equal(t, float64(42), o["gid"])
+				assert.Equal(t, price, o["price"])
+				assert.Equal(t, "tBTCUSD", o["symbol"])
+			}
+
+			respMock := []interface{}{1568711312683, nil, nil, nil, nil, nil, nil, nil}
+			payload, _ := json.Marshal(respMock)
+			_, err = w.Write(payload)
+			require.Nil(t, err)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(handler))
+		defer server.Close()
+
+		gid, rsp, err := NewClientWithURL(server.URL).Orders.SubmitLadder(order.Ladder{
+			GID:    42,
+			Symbol: "tBTCUSD",
+			Amount: 0.3,
+			From:   9000,
+			To:     8600,
+			Count:  3,
+		})
+		require.Nil(t, err)
+		assert.Equal(t, int64(42), gid)
+		assert.Equal(t, int64(1568711312683), rsp.MTS)
+	})
+
+	t.Run("cancels group", func(t *testing.T) {
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/auth/w/order/cancel/multi", r.RequestURI)
+
+			gotReqPld := CancelOrderMultiRequest{}
+			err := json.NewDecoder(r.Body).Decode(&gotReqPld)
+			require.Nil(t, err)
+			assert.Equal(t, GroupOrderIDs{42}, gotReqPld.GroupOrderIDs)
+
+			respMock := []interface{}{1568711312683, nil, nil, nil, nil, nil, nil, nil}
+			payload, _ := json.Marshal(respMock)
+			_, err = w.Write(payload)
+			require.Nil(t, err)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(handler))
+		defer server.Close()
+
+		_, err := NewClientWithURL(server.URL).Orders.CancelGroup(42)
+		require.Nil(t, err)
+	})
+}
+
+func TestUpdateOrder(t *testing.T) {
+	respMock := []interface{}{
+		1575289447641, "ou-req", nil, nil,
+		[]interface{}{
+			1185815100, nil, 1575289350475, "tETHUSD", 1575289351944, 1575289351948, -2,
+			-3, "LIMIT", nil, nil, nil, 0, "ACTIVE", nil, nil, 240, 0, 0, 0, nil, nil, nil,
+			0, 0, nil, nil, nil, "API>BFX", nil, nil, nil,
+		},
+		nil, "SUCCESS", "Submitting update to limit sell order for 2 ETH.",
+	}
+
+	t.Run("returns the updated order", func(t *testing.T) {
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/auth/w/order/update", r.RequestURI)
+
+			gotReqPld := map[string]interface{}{}
+			err := json.NewDecoder(r.Body).Decode(&gotReqPld)
+			require.Nil(t, err)
+			assert.Equal(t, float64(1185815100), gotReqPld["id"])
+			assert.Equal(t, "1", gotReqPld["delta"])
+
+			payload, _ := json.Marshal(respMock)
+			_, err = w.Write(payload)
+			require.Nil(t, err)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(handler))
+		defer server.Close()
+
+		c := NewClientWithURL(server.URL)
+		o, err := c.Orders.UpdateOrder(context.Background(), &order.UpdateRequest{ID: 1185815100, Delta: 1})
+		require.Nil(t, err)
+		assert.Equal(t, int64(1185815100), o.ID)
+		assert.Equal(t, -2.0, o.Amount)
+		assert.Equal(t, 240.0, o.Price)
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		c := NewClientWithURL("http://localhost")
+		_, err := c.Orders.UpdateOrder(context.Background(), &order.UpdateRequest{ID: 1, Amount: 1, Delta: 1})
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+	})
+
+	t.Run("returns the error of the notification", func(t *testing.T) {
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			rsp := append([]interface{}{}, respMock...)
+			rsp[6], rsp[7] = "ERROR", "order not found"
+			payload, _ := json.Marshal(rsp)
+			_, err := w.Write(payload)
+			require.Nil(t, err)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(handler))
+		defer server.Close()
+
+		c := NewClientWithURL(server.URL)
+		_, err := c.Orders.UpdateOrder(context.Background(), &order.UpdateRequest{ID: 1185815100, Price: 250})
+		require.NotNil(t, err)
+		assert.Contains(t, err.Error(), "order not found")
+	})
+
+	t.Run("keeps priority", func(t *testing.T) {
+		c := NewClientWithURL("http://localhost")
+		current := &order.Order{ID: 1185815100, Amount: -3, Price: 240}
+		_, err := c.Orders.UpdateOrderKeepPriority(context.Background(), current, &order.UpdateRequest{ID: 1185815100, Price: 250})
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+	})
+}
@@ -0,0 +1,142 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// ErrPageOverflow is returned when more records share a single timestamp than
+// fit into a page, as the history endpoints offer no way to reach the records
+// of that timestamp beyond the first page
+var ErrPageOverflow = errors.New("page limit exceeded within a single timestamp")
+
+// PageCursor is the progress of a Pager, which can be persisted to resume
+// paging later
+type PageCursor struct {
+	Bound int64   `json:"bound"` // time bound of the next page, zero before the first page
+	Seen  []int64 `json:"seen"`  // ids of the returned records at Bound
+	Count int     `json:"count"` // number of returned records
+	Done  bool    `json:"done"`
+}
+
+// Pager pages through a history endpoint beyond its page limit. Each page
+// moves the time bound of the query to the timestamp of the last record of
+// the page before, in the sort order of the query, so records sharing that
+// timestamp are requested again and skipped by their id. Only the ids of
+// that timestamp are kept, which bounds the memory of the pager by the page
+// size.
+type Pager struct {
+	query  *Query
+	limit  int
+	total  int
+	asc    bool
+	cursor PageCursor
+	err    error
+}
+
+// NewPager returns a pager over the records matching q, requested limit at a
+// time. The limit of q caps the total number of records.
+func NewPager(q *Query, limit int) *Pager {
+	p := &Pager{
+		query: q.clone(),
+		limit: limit,
+	}
+	if q != nil && q.limit != nil {
+		p.total = *q.limit
+	}
+	p.asc = p.query.sort != nil && *p.query.sort == common.OldestFirst
+	return p
+}
+
+// Resume continues paging from c
+func (p *Pager) Resume(c PageCursor) *Pager {
+	p.cursor = c
+	return p
+}
+
+// Cursor returns the progress of the pager
+func (p *Pager) Cursor() PageCursor {
+	return p.cursor
+}
+
+// Done reports whether all records were returned or paging failed, see Err
+func (p *Pager) Done() bool {
+	return p.cursor.Done
+}
+
+// Err returns ErrPageOverflow if paging stopped at a timestamp with more
+// records than fit into a page
+func (p *Pager) Err() error {
+	return p.err
+}
+
+// Query returns the query of the next page
+func (p *Pager) Query() *Query {
+	q := p.query.clone().Limit(p.limit)
+	if p.cursor.Bound != 0 {
+		if p.asc {
+			q.FromMts(common.Mts(p.cursor.Bound))
+		} else {
+			q.ToMts(common.Mts(p.cursor.Bound))
+		}
+	}
+	return q
+}
+
+// Page takes the n records returned for Query, whose id and timestamp are
+// returned by key, and returns the indexes of the records not returned
+// before. It moves the bound to the next page.
+func (p *Pager) Page(n int, key func(i int) (id int64, mts int64)) []int {
+	if p.cursor.Done {
+		return nil
+	}
+	if n == 0 {
+		p.cursor.Done = true
+		return nil
+	}
+	seen := make(map[int64]bool, len(p.cursor.Seen))
+	for _, id := range p.cursor.Seen {
+		seen[id] = true
+	}
+
+	var edge, first int64
+	single := true
+	fresh := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		id, mts := key(i)
+		if i == 0 {
+			first = mts
+		} else if mts != first {
+			single = false
+		}
+		if i == 0 || (p.asc && mts > edge) || (!p.asc && mts < edge) {
+			edge = mts
+		}
+		if seen[id] || (p.total > 0 && p.cursor.Count == p.total) {
+			continue
+		}
+		fresh = append(fresh, i)
+		p.cursor.Count++
+	}
+
+	switch {
+	case p.total > 0 && p.cursor.Count == p.total, n < p.limit:
+		p.cursor.Done = true
+	case single:
+		p.cursor.Done = true
+		p.err = fmt.Errorf("%w: more than %d records at %d", ErrPageOverflow, p.limit, edge)
+	case len(fresh) == 0:
+		p.cursor.Done = true
+	}
+
+	edgeIDs := make([]int64, 0)
+	for i := 0; i < n; i++ {
+		if id, mts := key(i); mts == edge {
+			edgeIDs = append(edgeIDs, id)
+		}
+	}
+	p.cursor.Bound, p.cursor.Seen = edge, edgeIDs
+	return fresh
+}
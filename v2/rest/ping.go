@@ -0,0 +1,29 @@
+package rest
+
+import (
+	"context"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+)
+
+// Latency is the result of a Ping
+type Latency struct {
+	RoundTrip time.Duration // time until the response was decoded
+	Operative bool          // platform status, false during maintenance
+	At        time.Time     // time the request was sent
+}
+
+// Ping measures the round trip of a request to the platform status endpoint,
+// e.g. to score the health of the venue. Use the websocket client's Ping for
+// the latency of a websocket connection.
+func (c *Client) Ping(ctx context.Context) (*Latency, error) {
+	l := &Latency{At: time.Now()}
+	raw, err := c.Request(NewRequestWithMethod("platform/status", "GET").WithContext(ctx))
+	l.RoundTrip = time.Since(l.At)
+	if err != nil {
+		return nil, err
+	}
+	l.Operative = len(raw) > 0 && convert.IValOrZero(raw[0]) == 1
+	return l, nil
+}
@@ -1,5 +1,7 @@
 package rest
 
+import "github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+
 type PlatformService struct {
 	Synchronous
 }
@@ -11,5 +13,5 @@ func (p *PlatformService) Status() (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return len(raw) > 0 && raw[0].(float64) == 1, nil
+	return len(raw) > 0 && convert.IValOrZero(raw[0]) == 1, nil
 }
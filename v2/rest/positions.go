@@ -25,7 +25,7 @@ func (s *PositionService) All() (*position.Snapshot, error) {
 		return nil, err
 	}
 
-	pss, err := position.SnapshotFromRaw(raw)
+	pss, err := position.SnapshotFromRaw(raw, decoding(s.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -51,5 +51,5 @@ func (s *PositionService) Claim(cp *position.ClaimRequest) (*notification.Notifi
 		return nil, err
 	}
 
-	return notification.FromRaw(raw)
+	return notificationFromRaw(raw, decoding(s.Synchronous)...)
 }
@@ -0,0 +1,190 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+)
+
+// Priority is the class of a request which decides the order in which
+// requests waiting for the rate limit of the client are sent.
+type Priority int
+
+const (
+	// PriorityCritical is meant for requests reducing risk, e.g. order
+	// cancels, which are the default for cancel endpoints
+	PriorityCritical Priority = iota
+	// PriorityNormal is meant for trading, e.g. order submits, and is the
+	// default for all other requests
+	PriorityNormal
+	// PriorityBackground is meant for requests which may be delayed, e.g.
+	// reporting
+	PriorityBackground
+
+	priorityClasses = 3
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityCritical:
+		return "critical"
+	case PriorityNormal:
+		return "normal"
+	case PriorityBackground:
+		return "background"
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+type priorityKey struct{}
+
+// WithPriority returns a copy of ctx tagging the requests issued with it with
+// the priority p, e.g.:
+//
+//	ctx := rest.WithPriority(ctx, rest.PriorityBackground)
+//	raw, err := c.DoAuthenticated(ctx, common.PermissionRead, "ledgers/hist", nil)
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority carried by ctx, if any
+func PriorityFromContext(ctx context.Context) (Priority, bool) {
+	p, ok := ctx.Value(priorityKey{}).(Priority)
+	return p, ok
+}
+
+// priorityOf returns the priority the request is tagged with, defaulting to
+// critical for cancel endpoints and to normal otherwise
+func priorityOf(req Request) Priority {
+	if p, ok := PriorityFromContext(req.Context()); ok && p >= PriorityCritical && p <= PriorityBackground {
+		return p
+	}
+	if isWriteRequest(req) && strings.Contains(req.RefURL, "/cancel") {
+		return PriorityCritical
+	}
+	return PriorityNormal
+}
+
+// RateLimit spaces the requests sent by the client to at most perMinute per
+// minute, leaving responses served from the cache aside. Requests held back
+// are sent by priority, see WithPriority, and in the order they were issued
+// within the same priority. A request whose context is done while waiting
+// is not sent. The clock times the limit, defaulting to utils.SystemClock.
+func (c *Client) RateLimit(perMinute int, clock utils.Clock) (*Client, error) {
+	if perMinute <= 0 {
+		return nil, fmt.Errorf("%w: rate limit must be positive", common.ErrBadRequest)
+	}
+	if clock == nil {
+		clock = utils.SystemClock
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.scheduler = &scheduler{clock: clock, interval: time.Minute / time.Duration(perMinute)}
+	return c, nil
+}
+
+// ticket is a request waiting for its turn
+type ticket struct {
+	ready chan struct{}
+}
+
+// scheduler hands out the slots of a rate limit to the waiting requests of
+// the highest priority first
+type scheduler struct {
+	mu          sync.Mutex
+	clock       utils.Clock
+	interval    time.Duration
+	next        time.Time
+	queues      [priorityClasses][]*ticket
+	dispatching bool
+}
+
+// acquire blocks until the request of priority p may be sent
+func (s *scheduler) acquire(ctx context.Context, p Priority) error {
+	s.mu.Lock()
+	now := s.clock.Now()
+	if s.waiting() == 0 && !now.Before(s.next) {
+		s.next = now.Add(s.interval)
+		s.mu.Unlock()
+		return nil
+	}
+	t := &ticket{ready: make(chan struct{})}
+	s.queues[p] = append(s.queues[p], t)
+	if !s.dispatching {
+		s.dispatching = true
+		go s.dispatch()
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-t.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		s.remove(p, t)
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// dispatch grants the slots to the waiting requests until none is left
+func (s *scheduler) dispatch() {
+	for {
+		s.mu.Lock()
+		if s.waiting() == 0 {
+			s.dispatching = false
+			s.mu.Unlock()
+			return
+		}
+		now := s.clock.Now()
+		if now.Before(s.next) {
+			d := s.next.Sub(now)
+			s.mu.Unlock()
+			<-s.clock.NewTimer(d).C()
+			continue
+		}
+		for p := range s.queues {
+			if len(s.queues[p]) == 0 {
+				continue
+			}
+			t := s.queues[p][0]
+			s.queues[p] = s.queues[p][1:]
+			close(t.ready)
+			break
+		}
+		s.next = now.Add(s.interval)
+		s.mu.Unlock()
+	}
+}
+
+// queued returns the number of waiting requests
+func (s *scheduler) queued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.waiting()
+}
+
+// waiting returns the number of waiting requests. It is called with the
+// lock held.
+func (s *scheduler) waiting() int {
+	n := 0
+	for _, q := range s.queues {
+		n += len(q)
+	}
+	return n
+}
+
+func (s *scheduler) remove(p Priority, t *ticket) {
+	q := s.queues[p]
+	for i, x := range q {
+		if x == t {
+			s.queues[p] = append(q[:i], q[i+1:]...)
+			return
+		}
+	}
+}
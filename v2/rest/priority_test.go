@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitPriority(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	sent := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(paths)
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		_, err := w.Write([]byte(`[]`))
+		require.Nil(t, err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	_, err := NewClientWithURL(server.URL).RateLimit(0, nil)
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	clock := utils.NewManualClock(time.Unix(1600000000, 0))
+	c, err := NewClientWithURL(server.URL).Credentials("key", "secret").RateLimit(60, clock)
+	require.Nil(t, err)
+	s := c.scheduler
+
+	// the first request is sent right away
+	_, err = c.DoPublic(context.Background(), "platform/status", nil)
+	require.Nil(t, err)
+
+	// the requests issued meanwhile wait for their turn
+	var wg sync.WaitGroup
+	issue := func(f func()) {
+		wg.Add(1)
+		n := s.queued()
+		go func() {
+			defer wg.Done()
+			f()
+		}()
+		for s.queued() == n {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	background := WithPriority(context.Background(), PriorityBackground)
+	issue(func() {
+		_, err := c.DoPublic(background, "tickers/hist", nil)
+		assert.Nil(t, err)
+	})
+	issue(func() {
+		_, err := c.DoPublic(context.Background(), "tickers", nil)
+		assert.Nil(t, err)
+	})
+	issue(func() {
+		_, err := c.DoAuthenticated(context.Background(), common.PermissionWrite, "order/cancel", map[string]int64{"id": 1})
+		assert.Nil(t, err)
+	})
+
+	// a request whose context is done leaves the queue
+	ctx, cancel := context.WithCancel(WithPriority(context.Background(), PriorityCritical))
+	done := make(chan error)
+	go func() {
+		_, err := c.DoPublic(ctx, "ticker/tBTCUSD", nil)
+		done <- err
+	}()
+	for s.queued() < 4 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	assert.True(t, errors.Is(<-done, context.Canceled))
+	assert.Equal(t, 3, s.queued())
+
+	for i := 2; i <= 4; i++ {
+		for clock.Timers() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		clock.Advance(999 * time.Millisecond)
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, i-1, sent())
+		clock.Advance(time.Millisecond)
+		for sent() < i {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	wg.Wait()
+	assert.Equal(t, []string{"/platform/status", "/auth/w/order/cancel", "/tickers", "/tickers/hist"}, paths)
+}
+
+func TestPriorityOf(t *testing.T) {
+	assert.Equal(t, PriorityCritical, priorityOf(Request{RefURL: "auth/w/order/cancel/multi"}))
+	assert.Equal(t, PriorityCritical, priorityOf(Request{RefURL: "auth/w/funding/offer/cancel"}))
+	assert.Equal(t, PriorityNormal, priorityOf(Request{RefURL: "auth/w/order/submit"}))
+	assert.Equal(t, PriorityNormal, priorityOf(Request{RefURL: "auth/r/wallets"}))
+
+	req := Request{RefURL: "auth/w/order/submit"}.WithContext(WithPriority(context.Background(), PriorityBackground))
+	assert.Equal(t, PriorityBackground, priorityOf(req))
+	assert.Equal(t, "background", PriorityBackground.String())
+}
@@ -24,7 +24,7 @@ type Nickname string
 // https://docs.bitfinex.com/reference#rest-public-pulse-profile
 func (ps *PulseService) PublicPulseProfile(nickname Nickname) (*pulseprofile.PulseProfile, error) {
 	if (len(nickname)) == 0 {
-		return nil, fmt.Errorf("nickname is required argument")
+		return nil, fmt.Errorf("%w: nickname is required argument", common.ErrBadRequest)
 	}
 
 	req := NewRequestWithMethod(path.Join("pulse", "profile", string(nickname)), "GET")
@@ -33,7 +33,7 @@ func (ps *PulseService) PublicPulseProfile(nickname Nickname) (*pulseprofile.Pul
 		return nil, err
 	}
 
-	pp, err := pulseprofile.NewFromRaw(raw)
+	pp, err := pulseprofile.NewFromRaw(raw, decoding(ps.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +55,7 @@ func (ps *PulseService) PublicPulseHistory(limit int, end common.Mts) ([]*pulse.
 		return nil, err
 	}
 
-	pph, err := pulse.SnapshotFromRaw(raw)
+	pph, err := pulse.SnapshotFromRaw(raw, decoding(ps.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +68,7 @@ func (ps *PulseService) PublicPulseHistory(limit int, end common.Mts) ([]*pulse.
 func (ps *PulseService) AddPulse(p *pulse.Pulse) (*pulse.Pulse, error) {
 	tl := len(p.Title)
 	if tl < 16 || tl > 120 {
-		return nil, fmt.Errorf("Title length min 16 and max 120 characters. Got:%d", tl)
+		return nil, fmt.Errorf("%w: title length min 16 and max 120 characters. Got:%d", common.ErrBadRequest, tl)
 	}
 
 	payload, err := json.Marshal(p)
@@ -86,7 +86,7 @@ func (ps *PulseService) AddPulse(p *pulse.Pulse) (*pulse.Pulse, error) {
 		return nil, err
 	}
 
-	pm, err := pulse.FromRaw(raw)
+	pm, err := pulse.FromRaw(raw, decoding(ps.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +98,7 @@ func (ps *PulseService) AddPulse(p *pulse.Pulse) (*pulse.Pulse, error) {
 // see https://docs.bitfinex.com/reference#rest-auth-pulse-add
 func (ps *PulseService) AddComment(p *pulse.Pulse) (*pulse.Pulse, error) {
 	if len(p.Parent) == 0 {
-		return nil, fmt.Errorf("Pulse comment requires `Parent` parameter to be set")
+		return nil, fmt.Errorf("%w: pulse comment requires `Parent` parameter to be set", common.ErrBadRequest)
 	}
 
 	return ps.AddPulse(p)
@@ -117,7 +117,7 @@ func (ps *PulseService) PulseHistory() ([]*pulse.Pulse, error) {
 		return nil, err
 	}
 
-	pph, err := pulse.SnapshotFromRaw(raw)
+	pph, err := pulse.SnapshotFromRaw(raw, decoding(ps.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
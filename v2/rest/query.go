@@ -0,0 +1,144 @@
+package rest
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// Query collects the optional parameters accepted by the history endpoints.
+// Unset parameters are omitted from the request, e.g.:
+//
+//	q := rest.NewQuery().From(start).To(end).Limit(500).SortAsc()
+type Query struct {
+	start *int64
+	end   *int64
+	limit *int
+	sort  *common.SortOrder
+}
+
+// NewQuery returns an empty query
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// From sets the start of the queried time range
+func (q *Query) From(t time.Time) *Query {
+	return q.FromMts(common.MtsFromTime(t))
+}
+
+// To sets the end of the queried time range
+func (q *Query) To(t time.Time) *Query {
+	return q.ToMts(common.MtsFromTime(t))
+}
+
+// FromMts sets the start of the queried time range in milliseconds
+func (q *Query) FromMts(mts common.Mts) *Query {
+	v := int64(mts)
+	q.start = &v
+	return q
+}
+
+// ToMts sets the end of the queried time range in milliseconds
+func (q *Query) ToMts(mts common.Mts) *Query {
+	v := int64(mts)
+	q.end = &v
+	return q
+}
+
+// Limit sets the maximum number of records returned
+func (q *Query) Limit(limit int) *Query {
+	q.limit = &limit
+	return q
+}
+
+// Sort sets the order in which records are returned
+func (q *Query) Sort(order common.SortOrder) *Query {
+	q.sort = &order
+	return q
+}
+
+// SortAsc returns the oldest records first
+func (q *Query) SortAsc() *Query {
+	return q.Sort(common.OldestFirst)
+}
+
+// SortDesc returns the newest records first
+func (q *Query) SortDesc() *Query {
+	return q.Sort(common.NewestFirst)
+}
+
+// fullQuery builds a query with all parameters set, as sent by the
+// positional *WithQuery methods
+func fullQuery(start, end common.Mts, limit common.QueryLimit, sort common.SortOrder) *Query {
+	return NewQuery().FromMts(start).ToMts(end).Limit(int(limit)).Sort(sort)
+}
+
+// clone returns a copy of the query which can be modified independently
+func (q *Query) clone() *Query {
+	c := NewQuery()
+	if q == nil {
+		return c
+	}
+	if q.start != nil {
+		c.FromMts(common.Mts(*q.start))
+	}
+	if q.end != nil {
+		c.ToMts(common.Mts(*q.end))
+	}
+	if q.limit != nil {
+		c.Limit(*q.limit)
+	}
+	if q.sort != nil {
+		c.Sort(*q.sort)
+	}
+	return c
+}
+
+func (q *Query) exceedsLimit(max int) bool {
+	return q != nil && q.limit != nil && *q.limit > max
+}
+
+// payload returns the query as body of an authenticated request
+func (q *Query) payload() map[string]interface{} {
+	pld := map[string]interface{}{}
+	if q == nil {
+		return pld
+	}
+	if q.start != nil {
+		pld["start"] = *q.start
+	}
+	if q.end != nil {
+		pld["end"] = *q.end
+	}
+	if q.limit != nil {
+		pld["limit"] = *q.limit
+	}
+	if q.sort != nil {
+		pld["sort"] = int(*q.sort)
+	}
+	return pld
+}
+
+// params returns the query as url query parameters
+func (q *Query) params() url.Values {
+	params := make(url.Values)
+	if q == nil {
+		return params
+	}
+	if q.start != nil {
+		params.Add("start", strconv.FormatInt(*q.start, 10))
+	}
+	if q.end != nil {
+		params.Add("end", strconv.FormatInt(*q.end, 10))
+	}
+	if q.limit != nil {
+		params.Add("limit", strconv.Itoa(*q.limit))
+	}
+	if q.sort != nil {
+		params.Add("sort", strconv.Itoa(int(*q.sort)))
+	}
+	return params
+}
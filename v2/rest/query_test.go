@@ -0,0 +1,71 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	from := time.Unix(1568123933, 0)
+	to := from.Add(time.Hour)
+
+	t.Run("empty query", func(t *testing.T) {
+		q := NewQuery()
+		assert.Empty(t, q.payload())
+		assert.Empty(t, q.params())
+	})
+
+	t.Run("converts time to milliseconds", func(t *testing.T) {
+		q := NewQuery().From(from).To(to).Limit(500).SortAsc()
+		assert.Equal(t, map[string]interface{}{
+			"start": int64(1568123933000),
+			"end":   int64(1568127533000),
+			"limit": 500,
+			"sort":  1,
+		}, q.payload())
+		assert.Equal(t, "end=1568127533000&limit=500&sort=1&start=1568123933000", q.params().Encode())
+	})
+
+	t.Run("sort desc", func(t *testing.T) {
+		assert.Equal(t, "sort=-1", NewQuery().SortDesc().params().Encode())
+	})
+}
+
+func TestLedgersQuery(t *testing.T) {
+	t.Run("limit exceeded", func(t *testing.T) {
+		_, err := NewClient().Ledgers.LedgersQuery("BTC", NewQuery().Limit(5000))
+		require.NotNil(t, err)
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+	})
+
+	t.Run("calls correct resource with correct payload", func(t *testing.T) {
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/auth/r/ledgers/BTC/hist", r.RequestURI)
+
+			gotReqPld := map[string]interface{}{}
+			err := json.NewDecoder(r.Body).Decode(&gotReqPld)
+			require.Nil(t, err)
+			assert.Equal(t, map[string]interface{}{"start": float64(1568123933000), "limit": float64(10)}, gotReqPld)
+
+			_, err = w.Write([]byte(`[[2531822314,"BTC",null,1568123933000,null,-0.01,0.99,null,"Settlement"]]`))
+			require.Nil(t, err)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(handler))
+		defer server.Close()
+
+		q := NewQuery().From(time.Unix(1568123933, 0)).Limit(10)
+		lss, err := NewClientWithURL(server.URL).Ledgers.LedgersQuery("BTC", q)
+		require.Nil(t, err)
+		require.Len(t, lss.Snapshot, 1)
+		assert.Equal(t, "Settlement", lss.Snapshot[0].Description)
+	})
+}
@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"strings"
+	"time"
+)
+
+// FindingKind classifies reconciliation findings
+type FindingKind string
+
+const (
+	// FindingStuck is a movement which stayed in a non-final status for longer
+	// than the configured threshold
+	FindingStuck FindingKind = "stuck"
+	// FindingMissingTxID is a completed withdrawal without transaction id
+	FindingMissingTxID FindingKind = "missing_txid"
+	// FindingUnconfirmed is a completed withdrawal whose transaction id was not
+	// observed on chain
+	FindingUnconfirmed FindingKind = "unconfirmed"
+	// FindingUnknownTx is an on-chain transaction without matching withdrawal
+	FindingUnknownTx FindingKind = "unknown_tx"
+)
+
+// ReconciliationFinding describes a discrepancy between the movements of the
+// account and the on-chain transactions
+type ReconciliationFinding struct {
+	Kind     FindingKind
+	Movement *Movement2 // nil for FindingUnknownTx
+	TxID     string
+	Age      time.Duration // time since the last movement update, FindingStuck only
+}
+
+// ReconcileOptions configures ReconcileMovements
+type ReconcileOptions struct {
+	// StuckAfter is the time after which a movement in a non-final status is
+	// reported, defaults to 24h
+	StuckAfter time.Duration
+	// Now is the reference time for stuck movements, defaults to time.Now
+	Now time.Time
+}
+
+// ReconcileMovements matches withdrawal movements against the given on-chain
+// transaction ids and reports movements stuck in non-final statuses, e.g.:
+//
+//	ms, _ := c.Wallet.MovementsQuery("BTC", rest.NewQuery().From(start))
+//	for _, f := range rest.ReconcileMovements(ms, txids, rest.ReconcileOptions{}) {
+//		alert(f)
+//	}
+func ReconcileMovements(movements []Movement2, txids []string, opts ReconcileOptions) []ReconciliationFinding {
+	if opts.StuckAfter == 0 {
+		opts.StuckAfter = 24 * time.Hour
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	onChain := make(map[string]bool, len(txids))
+	for _, id := range txids {
+		onChain[strings.ToLower(id)] = true
+	}
+
+	findings := []ReconciliationFinding{}
+	matched := map[string]bool{}
+	for i := range movements {
+		m := &movements[i]
+		txid := strings.ToLower(m.TransactionID)
+
+		if !m.Status.IsFinal() {
+			if age := opts.Now.Sub(m.UpdatedAt()); age > opts.StuckAfter {
+				findings = append(findings, ReconciliationFinding{Kind: FindingStuck, Movement: m, TxID: m.TransactionID, Age: age})
+			}
+		}
+
+		// only completed withdrawals are expected on chain
+		if m.Amount >= 0 || !m.Status.IsSuccessful() {
+			continue
+		}
+
+		switch {
+		case txid == "":
+			findings = append(findings, ReconciliationFinding{Kind: FindingMissingTxID, Movement: m})
+		case onChain[txid]:
+			matched[txid] = true
+		default:
+			findings = append(findings, ReconciliationFinding{Kind: FindingUnconfirmed, Movement: m, TxID: m.TransactionID})
+		}
+	}
+
+	for _, id := range txids {
+		if !matched[strings.ToLower(id)] {
+			findings = append(findings, ReconciliationFinding{Kind: FindingUnknownTx, TxID: id})
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileMovements(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	mts := func(d time.Duration) int64 {
+		return now.Add(-d).UnixNano() / int64(time.Millisecond)
+	}
+
+	movements := []Movement2{
+		{ID: 1, Currency: "BTC", Status: "COMPLETED", Amount: -1, TransactionID: "ABC", MtsUpdated: mts(time.Hour)},
+		{ID: 2, Currency: "BTC", Status: "COMPLETED", Amount: -1, MtsUpdated: mts(time.Hour)},
+		{ID: 3, Currency: "BTC", Status: "COMPLETED", Amount: -2, TransactionID: "def", MtsUpdated: mts(time.Hour)},
+		{ID: 4, Currency: "BTC", Status: "PROCESSING", Amount: -1, MtsUpdated: mts(3 * time.Hour)},
+		{ID: 5, Currency: "BTC", Status: "PROCESSING", Amount: -1, MtsUpdated: mts(time.Minute)},
+		{ID: 6, Currency: "BTC", Status: "COMPLETED", Amount: 1, TransactionID: "deposit", MtsUpdated: mts(time.Hour)},
+	}
+
+	findings := ReconcileMovements(movements, []string{"abc", "xyz"}, ReconcileOptions{
+		StuckAfter: 2 * time.Hour,
+		Now:        now,
+	})
+	require.Len(t, findings, 4)
+
+	assert.Equal(t, FindingMissingTxID, findings[0].Kind)
+	assert.Equal(t, int64(2), findings[0].Movement.ID)
+
+	assert.Equal(t, FindingUnconfirmed, findings[1].Kind)
+	assert.Equal(t, "def", findings[1].TxID)
+
+	assert.Equal(t, FindingStuck, findings[2].Kind)
+	assert.Equal(t, int64(4), findings[2].Movement.ID)
+	assert.Equal(t, 3*time.Hour, findings[2].Age)
+
+	assert.Equal(t, FindingUnknownTx, findings[3].Kind)
+	assert.Equal(t, "xyz", findings[3].TxID)
+	assert.Nil(t, findings[3].Movement)
+}
@@ -0,0 +1,120 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+)
+
+// Limiter spaces calls shared by several goroutines by an interval, e.g. the
+// requests of a bulk download. Waiting calls are let through by the priority
+// of their context, see WithPriority, like the requests of a client with a
+// RateLimit.
+type Limiter struct {
+	s *scheduler
+}
+
+// NewLimiter returns a limiter letting one call through per interval. The
+// clock times the interval, defaulting to utils.SystemClock.
+func NewLimiter(interval time.Duration, clock utils.Clock) *Limiter {
+	if clock == nil {
+		clock = utils.SystemClock
+	}
+	return &Limiter{s: &scheduler{clock: clock, interval: interval}}
+}
+
+// Wait blocks until the next call may be made or ctx is done
+func (l *Limiter) Wait(ctx context.Context) error {
+	p, ok := PriorityFromContext(ctx)
+	if !ok || p < PriorityCritical || p > PriorityBackground {
+		p = PriorityNormal
+	}
+	return l.s.acquire(ctx, p)
+}
+
+// Retrier repeats requests which were rate limited or failed in transport,
+// see Retryable, waiting Backoff before the first retry and twice as long
+// before every further one
+type Retrier struct {
+	MaxRetries int
+	Backoff    time.Duration
+	// Limiter, if set, is waited for before every attempt
+	Limiter *Limiter
+	// Clock times the backoffs, defaults to utils.SystemClock
+	Clock utils.Clock
+}
+
+// Do calls fn until it succeeds, fails permanently or MaxRetries retries
+// failed, and returns its last error. It returns the error of ctx once ctx is
+// done.
+func (r Retrier) Do(ctx context.Context, fn func() error) error {
+	clock := r.Clock
+	if clock == nil {
+		clock = utils.SystemClock
+	}
+	backoff := r.Backoff
+	for attempt := 0; ; attempt++ {
+		if r.Limiter != nil {
+			if err := r.Limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+		err := fn()
+		if err == nil || !Retryable(err) || attempt >= r.MaxRetries {
+			return err
+		}
+
+		t := clock.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C():
+		}
+		backoff *= 2
+	}
+}
+
+// Retryable reports whether a failed request may succeed when repeated as
+// is, i.e. it was rate limited or failed in transport: a network error or a
+// server or gateway failing without an api error code. Errors of the api
+// itself, maintenance, a done context and undecodable responses are
+// permanent.
+func Retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if RateLimited(err) {
+		return true
+	}
+	var er *ErrorResponse
+	if errors.As(err, &er) {
+		if er.Code != 0 || er.Response == nil || er.Response.Response == nil {
+			return false
+		}
+		switch er.Response.Response.StatusCode {
+		case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	var ne net.Error
+	return errors.As(err, &ne)
+}
+
+// RateLimited reports whether err was returned because the rate limit of the
+// api was exceeded
+func RateLimited(err error) bool {
+	var er *ErrorResponse
+	if !errors.As(err, &er) {
+		return false
+	}
+	if er.Code == ErrorCodeRateLimit {
+		return true
+	}
+	return er.Response != nil && er.Response.Response != nil && er.Response.Response.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,79 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	clock := utils.NewManualClock(time.Unix(1600000000, 0))
+	l := NewLimiter(20*time.Millisecond, clock)
+	require.Nil(t, l.Wait(context.Background()))
+
+	// the second call has to wait for the interval
+	done := make(chan error)
+	go func() { done <- l.Wait(context.Background()) }()
+	for clock.Timers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(19 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("call let through before the interval passed")
+	case <-time.After(10 * time.Millisecond):
+	}
+	clock.Advance(time.Millisecond)
+	require.Nil(t, <-done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.True(t, errors.Is(l.Wait(ctx), context.Canceled))
+}
+
+func TestRetryable(t *testing.T) {
+	status := func(code int) *Response {
+		return &Response{Response: &http.Response{StatusCode: code}}
+	}
+	transport := &url.Error{Op: "Get", URL: "https://api.bitfinex.com", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+
+	assert.True(t, Retryable(&ErrorResponse{Code: ErrorCodeRateLimit}))
+	assert.True(t, Retryable(&ErrorResponse{Response: status(http.StatusTooManyRequests)}))
+	assert.True(t, Retryable(&ErrorResponse{Response: status(http.StatusBadGateway)}))
+	assert.True(t, Retryable(transport))
+
+	assert.False(t, Retryable(&ErrorResponse{Code: ErrorCodeParams, Response: status(http.StatusInternalServerError)}))
+	assert.False(t, Retryable(&ErrorResponse{Response: status(http.StatusServiceUnavailable)}))
+	assert.False(t, Retryable(errors.New("invalid character 'x' looking for beginning of value")))
+	assert.False(t, Retryable(context.Canceled))
+}
+
+func TestRetrier(t *testing.T) {
+	calls := 0
+	r := Retrier{MaxRetries: 2, Backoff: time.Millisecond}
+	err := r.Do(context.Background(), func() error {
+		calls++
+		return &ErrorResponse{Code: ErrorCodeRateLimit}
+	})
+	assert.True(t, RateLimited(err))
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = r.Do(context.Background(), func() error {
+		calls++
+		if calls == 1 {
+			return &ErrorResponse{Code: ErrorCodeRateLimit}
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/auth"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// SigningPayload returns the canonical message signed for an authenticated
+// request to the given path relative to the api base, e.g. auth/w/withdraw
+func SigningPayload(path, nonce string, body []byte) string {
+	return "/api/v2/" + path + nonce + string(body)
+}
+
+// UnsignedRequest is an authenticated request prepared for signing elsewhere,
+// e.g. by an air-gapped signer approving withdrawals. It is JSON encodable to
+// be handed over to the signer.
+type UnsignedRequest struct {
+	Path  string          `json:"path"`
+	Nonce string          `json:"nonce"`
+	Body  json.RawMessage `json:"body"`
+}
+
+// Payload returns the canonical message to sign
+func (u UnsignedRequest) Payload() string {
+	return SigningPayload(u.Path, u.Nonce, u.Body)
+}
+
+// Sign signs the request with the key and secret of the credentials
+func (u UnsignedRequest) Sign(creds auth.Credentials) (*SignedRequest, error) {
+	if creds.Key == "" || creds.Secret == "" {
+		return nil, fmt.Errorf("%w: key and secret are required to sign", common.ErrBadRequest)
+	}
+	sig, err := creds.Sign(u.Payload())
+	if err != nil {
+		return nil, err
+	}
+	return &SignedRequest{UnsignedRequest: u, APIKey: creds.Key, Signature: sig}, nil
+}
+
+// SignedRequest is an authenticated request along with its signature, ready to
+// be submitted by a client without access to the secret
+type SignedRequest struct {
+	UnsignedRequest
+	APIKey    string `json:"apiKey"`
+	Signature string `json:"signature"`
+}
+
+// Headers returns the authentication headers of the request
+func (s *SignedRequest) Headers() map[string]string {
+	return map[string]string{
+		"Content-Type":  "application/json",
+		"Accept":        "application/json",
+		"bfx-nonce":     s.Nonce,
+		"bfx-apikey":    s.APIKey,
+		"bfx-signature": s.Signature,
+	}
+}
+
+// PrepareAuthenticated encodes the body and assigns a nonce for an
+// authenticated request, which is then signed offline and sent with
+// SubmitSigned, e.g.:
+//
+//	u, _ := relay.PrepareAuthenticated(common.PermissionWrite, "withdraw", body)
+//	s, _ := u.Sign(creds) // on the signer
+//	raw, _ := relay.SubmitSigned(ctx, s)
+//
+// The nonce has to be higher than the nonce of any request sent with the same
+// key in the meantime, so requests should be submitted in preparation order.
+func (c *Client) PrepareAuthenticated(permission common.PermissionType, refURL string, body interface{}) (*UnsignedRequest, error) {
+	data := []byte("{}")
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encoding request payload: %w", err)
+		}
+		data = b
+	}
+
+	// pending requests of the client are signed again with a later nonce,
+	// as the key of the signer is not known
+	c.ordersMu.Lock()
+	nonce := c.nonce.GetNonce()
+	for _, o := range c.orders {
+		o.signMu.Lock()
+		o.seq++
+		o.signMu.Unlock()
+	}
+	c.ordersMu.Unlock()
+
+	return &UnsignedRequest{
+		Path:  fmt.Sprintf("auth/%s/%s", string(permission), refURL),
+		Nonce: nonce,
+		Body:  data,
+	}, nil
+}
+
+// SubmitSigned sends a request signed with UnsignedRequest.Sign
+func (c *Client) SubmitSigned(ctx context.Context, s *SignedRequest) ([]interface{}, error) {
+	req := NewRequestWithBytes(s.Path, s.Body)
+	for k, v := range s.Headers() {
+		req.Headers[k] = v
+	}
+	return c.Request(withContext(ctx, req))
+}
@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/auth"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedNonce string
+
+func (n fixedNonce) GetNonce() string {
+	return string(n)
+}
+
+func TestOfflineSigning(t *testing.T) {
+	creds := auth.Credentials{Key: "key", Secret: "secret"}
+	body := map[string]interface{}{"wallet": "exchange", "method": "bitcoin", "amount": "0.1"}
+
+	var received http.Header
+	var receivedBody []byte
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/w/withdraw", r.URL.Path)
+		received = r.Header
+		b, err := ioutil.ReadAll(r.Body)
+		require.Nil(t, err)
+		receivedBody = b
+		_, err = w.Write([]byte(`[]`))
+		require.Nil(t, err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	// the relay has no credentials
+	relay := NewClientWithURLNonce(server.URL, fixedNonce("1600000000000000"))
+	u, err := relay.PrepareAuthenticated(common.PermissionWrite, "withdraw", body)
+	require.Nil(t, err)
+	assert.Equal(t, `/api/v2/auth/w/withdraw1600000000000000{"amount":"0.1","method":"bitcoin","wallet":"exchange"}`, u.Payload())
+
+	// hand over to the signer and back as JSON
+	b, err := json.Marshal(u)
+	require.Nil(t, err)
+	var onSigner UnsignedRequest
+	require.Nil(t, json.Unmarshal(b, &onSigner))
+	s, err := onSigner.Sign(creds)
+	require.Nil(t, err)
+	b, err = json.Marshal(s)
+	require.Nil(t, err)
+	var signed SignedRequest
+	require.Nil(t, json.Unmarshal(b, &signed))
+
+	_, err = relay.SubmitSigned(context.Background(), &signed)
+	require.Nil(t, err)
+
+	// the signature matches the one of the online signing
+	online := NewClientWithURLNonce(server.URL, fixedNonce("1600000000000000")).Credentials(creds.Key, creds.Secret)
+	bodyBytes, err := json.Marshal(body)
+	require.Nil(t, err)
+	req, err := online.NewAuthenticatedRequestWithBytes(common.PermissionWrite, "withdraw", bodyBytes)
+	require.Nil(t, err)
+
+	assert.Equal(t, req.Headers["bfx-signature"], received.Get("bfx-signature"))
+	assert.Equal(t, "key", received.Get("bfx-apikey"))
+	assert.Equal(t, "1600000000000000", received.Get("bfx-nonce"))
+	assert.Equal(t, bodyBytes, receivedBody)
+
+	_, err = onSigner.Sign(auth.Credentials{Token: "token"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,141 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"sync"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/ticker"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+)
+
+// MarketSnapshot combines the ticker, top of the book and recent trades of a
+// symbol
+type MarketSnapshot struct {
+	Symbol string
+	Ticker *ticker.Ticker
+	Book   *book.Snapshot
+	Trades *trade.Snapshot
+}
+
+// MarketSnapshotOptions configures MarketSnapshot, zero values are replaced
+// by their defaults
+type MarketSnapshotOptions struct {
+	Precision   common.BookPrecision // defaults to common.Precision0
+	BookLevels  int                  // 1, 25 or 100, defaults to common.PriceLevelDefault
+	Trades      int                  // number of recent trades, defaults to 50
+	MaxParallel int                  // concurrent requests, defaults to 3
+}
+
+func (o MarketSnapshotOptions) withDefaults() MarketSnapshotOptions {
+	if o.Precision == "" {
+		o.Precision = common.Precision0
+	}
+	if o.BookLevels <= 0 {
+		o.BookLevels = common.PriceLevelDefault
+	}
+	if o.Trades <= 0 {
+		o.Trades = 50
+	}
+	if o.MaxParallel <= 0 {
+		o.MaxParallel = 3
+	}
+	return o
+}
+
+// MarketSnapshot fetches the ticker, book and recent trades of the given
+// symbol concurrently, e.g. to render a market view:
+//
+//	s, err := c.MarketSnapshot(ctx, "tBTCUSD", rest.MarketSnapshotOptions{BookLevels: 25})
+//
+// All requests share ctx, the first failing request cancels the others and
+// its error is returned.
+func (c *Client) MarketSnapshot(ctx context.Context, symbol string, opts MarketSnapshotOptions) (*MarketSnapshot, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("%w: symbol cannot be empty", common.ErrBadRequest)
+	}
+	opts = opts.withDefaults()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s := &MarketSnapshot{Symbol: symbol}
+	fetches := []func() error{
+		func() (err error) {
+			raw, err := c.DoPublic(ctx, "tickers", url.Values{"symbols": {symbol}})
+			if err != nil {
+				return err
+			}
+			if len(raw) == 0 {
+				return fmt.Errorf("%w: no ticker for %s", common.ErrNotFound, symbol)
+			}
+			traw, ok := raw[0].([]interface{})
+			if !ok {
+				return fmt.Errorf("unexpected ticker response: %#v", raw)
+			}
+			s.Ticker, err = ticker.FromRestRaw(traw, c.decodeOptions()...)
+			return err
+		},
+		func() (err error) {
+			params := url.Values{"len": {strconv.Itoa(opts.BookLevels)}}
+			raw, err := c.DoPublic(ctx, path.Join("book", symbol, string(opts.Precision)), params)
+			if err != nil {
+				return err
+			}
+			s.Book, err = book.SnapshotFromRaw(symbol, string(opts.Precision), convert.ToInterfaceArray(raw), raw, c.decodeOptions()...)
+			return err
+		},
+		func() (err error) {
+			q := NewQuery().Limit(opts.Trades).SortDesc()
+			raw, err := c.DoPublic(ctx, path.Join("trades", symbol, "hist"), q.params())
+			if err != nil {
+				return err
+			}
+			if len(raw) == 0 {
+				s.Trades = &trade.Snapshot{Snapshot: make([]*trade.Trade, 0)}
+				return nil
+			}
+			s.Trades, err = trade.SnapshotFromRaw(symbol, convert.ToInterfaceArray(raw), c.decodeOptions()...)
+			return err
+		},
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, opts.MaxParallel)
+	for _, fetch := range fetches {
+		wg.Add(1)
+		go func(fetch func() error) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				once.Do(func() { firstErr = ctx.Err() })
+				return
+			}
+			defer func() { <-sem }()
+
+			if err := fetch(); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(fetch)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return s, nil
+}
@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarketSnapshot(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tickers":
+			assert.Equal(t, "tBTCUSD", r.URL.Query().Get("symbols"))
+			w.Write([]byte(`[["tBTCUSD",9000,1,9001,2,10,0.01,9000.5,100,9100,8900]]`))
+		case "/book/tBTCUSD/P0":
+			assert.Equal(t, "1", r.URL.Query().Get("len"))
+			w.Write([]byte(`[[9000,1,1],[9001,1,-2]]`))
+		case "/trades/tBTCUSD/hist":
+			assert.Equal(t, "2", r.URL.Query().Get("limit"))
+			assert.Equal(t, "-1", r.URL.Query().Get("sort"))
+			w.Write([]byte(`[[2,1568742390999,0.1,9000.5],[1,1568742390000,-0.2,9000]]`))
+		default:
+			t.Fatalf("unexpected request %s", r.RequestURI)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	s, err := NewClientWithURL(server.URL).MarketSnapshot(context.Background(), "tBTCUSD", MarketSnapshotOptions{
+		BookLevels:  1,
+		Trades:      2,
+		MaxParallel: 1,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, 9000.5, s.Ticker.LastPrice)
+	require.Len(t, s.Book.Snapshot, 2)
+	require.Len(t, s.Trades.Snapshot, 2)
+	assert.Equal(t, int64(2), s.Trades.Snapshot[0].ID)
+}
+
+func TestMarketSnapshotError(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/book/tBTCUSD/P0" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`["error",10020,"symbol: invalid"]`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	_, err := c.MarketSnapshot(context.Background(), "tBTCUSD", MarketSnapshotOptions{})
+	require.NotNil(t, err)
+
+	_, err = c.MarketSnapshot(context.Background(), "", MarketSnapshotOptions{})
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
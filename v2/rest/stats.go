@@ -14,37 +14,67 @@ type StatsService struct {
 	Synchronous
 }
 
-func (ss *StatsService) get(symbol string, key common.StatKey, extra string, section string) ([]interface{}, error) {
-	var params string
-	if extra != "" {
-		params = fmt.Sprintf("%s:1m:%s:%s", string(key), symbol, extra)
-	} else {
-		params = fmt.Sprintf("%s:1m:%s", string(key), symbol)
+// Stats retrieves the stat key at the given size, e.g. common.OneMinute or
+// common.ThirtyMinutes for the volume keys. The side is required for
+// common.PositionSizeKey and ignored otherwise. The last section returns a
+// single stat, the history section the stats of the last periods.
+// see https://docs.bitfinex.com/reference#rest-public-stats for more info
+func (ss *StatsService) Stats(key common.StatKey, size common.CandleResolution, symbol string, side common.OrderSide, section common.StatSection) ([]*stats.Stat, error) {
+	var extra string
+	if key == common.PositionSizeKey {
+		switch side {
+		case common.Long:
+			extra = "long"
+		case common.Short:
+			extra = "short"
+		default:
+			return nil, fmt.Errorf("%w: unrecognized side %v for %s", common.ErrBadRequest, side, key)
+		}
 	}
-	req := NewRequestWithMethod(path.Join("stats1", params, section), "GET")
-	raw, err := ss.Request(req)
+	return ss.stats(key, size, symbol, extra, section)
+}
+
+func (ss *StatsService) stats(key common.StatKey, size common.CandleResolution, symbol string, extra string, section common.StatSection) ([]*stats.Stat, error) {
+	if section != common.StatSectionLast && section != common.StatSectionHistory {
+		return nil, fmt.Errorf("%w: unrecognized stats section %q", common.ErrBadRequest, section)
+	}
+	raw, err := ss.get(symbol, key, size, extra, section)
 	if err != nil {
 		return nil, err
 	}
-	return raw, nil
+	if section == common.StatSectionHistory {
+		return stats.SnapshotFromRaw(raw, decoding(ss.Synchronous)...)
+	}
+	s, err := stats.FromRaw(raw, decoding(ss.Synchronous)...)
+	if err != nil {
+		return nil, err
+	}
+	return []*stats.Stat{s}, nil
 }
 
-func (ss *StatsService) getHistory(symbol string, key common.StatKey, extra string) ([]*stats.Stat, error) {
-	raw, err := ss.get(symbol, key, extra, "hist")
+func (ss *StatsService) get(symbol string, key common.StatKey, size common.CandleResolution, extra string, section common.StatSection) ([]interface{}, error) {
+	params := fmt.Sprintf("%s:%s:%s", string(key), string(size), symbol)
+	if extra != "" {
+		params = fmt.Sprintf("%s:%s", params, extra)
+	}
+	req := NewRequestWithMethod(path.Join("stats1", params, string(section)), "GET")
+	raw, err := ss.Request(req)
 	if err != nil {
 		return nil, err
 	}
+	return raw, nil
+}
 
-	return stats.SnapshotFromRaw(raw)
+func (ss *StatsService) getHistory(symbol string, key common.StatKey, extra string) ([]*stats.Stat, error) {
+	return ss.stats(key, common.OneMinute, symbol, extra, common.StatSectionHistory)
 }
 
 func (ss *StatsService) getLast(symbol string, key common.StatKey, extra string) (*stats.Stat, error) {
-	raw, err := ss.get(symbol, key, extra, "last")
+	s, err := ss.stats(key, common.OneMinute, symbol, extra, common.StatSectionLast)
 	if err != nil {
 		return nil, err
 	}
-
-	return stats.FromRaw(raw)
+	return s[0], nil
 }
 
 // Retrieves platform statistics for funding history
@@ -86,27 +116,53 @@ func (ss *StatsService) SymbolCreditSizeLast(fundingSymbol string, tradingSymbol
 // Retrieves platform statistics for position history
 // see https://docs.bitfinex.com/reference#rest-public-stats for more info
 func (ss *StatsService) PositionHistory(symbol string, side common.OrderSide) ([]*stats.Stat, error) {
-	var strSide string
-	if side == common.Long {
-		strSide = "long"
-	} else if side == common.Short {
-		strSide = "short"
-	} else {
-		return nil, fmt.Errorf("Unrecognized side %v in PositionHistory", side)
-	}
-	return ss.getHistory(symbol, common.PositionSizeKey, strSide)
+	return ss.Stats(common.PositionSizeKey, common.OneMinute, symbol, side, common.StatSectionHistory)
 }
 
 // Retrieves platform statistics for position last
 // see https://docs.bitfinex.com/reference#rest-public-stats for more info
 func (ss *StatsService) PositionLast(symbol string, side common.OrderSide) (*stats.Stat, error) {
-	var strSide string
-	if side == common.Long {
-		strSide = "long"
-	} else if side == common.Short {
-		strSide = "short"
-	} else {
-		return nil, fmt.Errorf("Unrecognized side %v in PositionHistory", side)
+	s, err := ss.Stats(common.PositionSizeKey, common.OneMinute, symbol, side, common.StatSectionLast)
+	if err != nil {
+		return nil, err
+	}
+	return s[0], nil
+}
+
+// VolumeLast retrieves the trading volume in USD of a symbol over the window
+// of the key, one of common.VolumeOneDayKey, common.VolumeSevenDaysKey and
+// common.VolumeThirtyDaysKey. BFX retrieves the volume of the platform.
+// see https://docs.bitfinex.com/reference#rest-public-stats for more info
+func (ss *StatsService) VolumeLast(key common.StatKey, symbol string) (*stats.Stat, error) {
+	s, err := ss.stats(key, common.ThirtyMinutes, symbol, "", common.StatSectionLast)
+	if err != nil {
+		return nil, err
+	}
+	return s[0], nil
+}
+
+// VolumeHistory retrieves the history of the trading volume in USD of a
+// symbol, see VolumeLast.
+// see https://docs.bitfinex.com/reference#rest-public-stats for more info
+func (ss *StatsService) VolumeHistory(key common.StatKey, symbol string) ([]*stats.Stat, error) {
+	return ss.stats(key, common.ThirtyMinutes, symbol, "", common.StatSectionHistory)
+}
+
+// LongShortRatio retrieves the last size of the long and short positions of
+// a trading pair.
+// see https://docs.bitfinex.com/reference#rest-public-stats for more info
+func (ss *StatsService) LongShortRatio(symbol string) (*stats.LongShortRatio, error) {
+	long, err := ss.PositionLast(symbol, common.Long)
+	if err != nil {
+		return nil, err
+	}
+	short, err := ss.PositionLast(symbol, common.Short)
+	if err != nil {
+		return nil, err
 	}
-	return ss.getLast(symbol, common.PositionSizeKey, strSide)
+	return &stats.LongShortRatio{
+		Period: long.Period,
+		Long:   long.Volume,
+		Short:  short.Volume,
+	}, nil
 }
@@ -0,0 +1,62 @@
+package rest_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/stats"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	var uris []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		uris = append(uris, r.RequestURI)
+		switch r.RequestURI {
+		case "/stats1/pos.size:1m:tBTCUSD:long/last":
+			_, _ = w.Write([]byte(`[1573554000000,3000.5]`))
+		case "/stats1/pos.size:1m:tBTCUSD:short/last":
+			_, _ = w.Write([]byte(`[1573554000000,1000.25]`))
+		case "/stats1/vol.1d:30m:BFX/hist":
+			_, _ = w.Write([]byte(`[[1573554000000,25957.9],[1573552200000,24000.1]]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+	c := rest.NewClientWithURL(server.URL)
+
+	t.Run("position size requires side", func(t *testing.T) {
+		_, err := c.Stats.Stats(common.PositionSizeKey, common.OneMinute, "tBTCUSD", 0, common.StatSectionLast)
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+		_, err = c.Stats.Stats(common.FundingSizeKey, common.OneMinute, "fUSD", 0, "first")
+		assert.True(t, errors.Is(err, common.ErrBadRequest))
+		assert.Empty(t, uris)
+	})
+
+	t.Run("last", func(t *testing.T) {
+		got, err := c.Stats.Stats(common.PositionSizeKey, common.OneMinute, "tBTCUSD", common.Long, common.StatSectionLast)
+		require.Nil(t, err)
+		assert.Equal(t, []*stats.Stat{{Period: 1573554000000, Volume: 3000.5}}, got)
+	})
+
+	t.Run("volume history", func(t *testing.T) {
+		got, err := c.Stats.VolumeHistory(common.VolumeOneDayKey, "BFX")
+		require.Nil(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, 24000.1, got[1].Volume)
+	})
+
+	t.Run("long short ratio", func(t *testing.T) {
+		got, err := c.Stats.LongShortRatio("tBTCUSD")
+		require.Nil(t, err)
+		assert.Equal(t, &stats.LongShortRatio{Period: 1573554000000, Long: 3000.5, Short: 1000.25}, got)
+		assert.InDelta(t, 2.9997, got.Ratio(), 1e-4)
+	})
+}
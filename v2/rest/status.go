@@ -6,6 +6,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/derivatives"
 )
 
@@ -19,6 +20,9 @@ const (
 	DERIV_TYPE = "deriv"
 )
 
+// statusHistoryLimit is the maximum page size of the status history endpoint
+const statusHistoryLimit = 5000
+
 func (ss *StatusService) get(sType string, key string) (*derivatives.Snapshot, error) {
 	req := NewRequestWithMethod(path.Join("status", sType), "GET")
 	req.Params = make(url.Values)
@@ -31,7 +35,7 @@ func (ss *StatusService) get(sType string, key string) (*derivatives.Snapshot, e
 	for i, r := range raw {
 		trueRaw[i] = r.([]interface{})
 	}
-	s, err := derivatives.SnapshotFromRaw(trueRaw)
+	s, err := derivatives.SnapshotFromRaw(trueRaw, decoding(ss.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +50,7 @@ func (ss *StatusService) DerivativeStatus(symbol string) (*derivatives.Derivativ
 		return nil, err
 	}
 	if len(data.Snapshot) == 0 {
-		return nil, fmt.Errorf("no status found for symbol %s", symbol)
+		return nil, fmt.Errorf("%w: no status found for symbol %s", common.ErrNotFound, symbol)
 	}
 	return data.Snapshot[0], err
 }
@@ -71,3 +75,62 @@ func (ss *StatusService) DerivativeStatusAll() ([]*derivatives.DerivativeStatus,
 	}
 	return data.Snapshot, err
 }
+
+// DerivativeStatusHistory retrieves the past derivative status of the given
+// symbol matching the query.
+// see https://docs.bitfinex.com/reference#rest-public-derivatives-status-history for more info
+func (ss *StatusService) DerivativeStatusHistory(symbol string, q *Query) ([]*derivatives.DerivativeStatus, error) {
+	if q.exceedsLimit(statusHistoryLimit) {
+		return nil, fmt.Errorf("%w: max request limit:%d, got: %d", common.ErrBadRequest, statusHistoryLimit, *q.limit)
+	}
+
+	req := NewRequestWithMethod(path.Join("status", DERIV_TYPE, symbol, "hist"), "GET")
+	req.Params = q.params()
+	raw, err := ss.Request(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*derivatives.DerivativeStatus, 0, len(raw))
+	for _, r := range raw {
+		row, ok := r.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected derivative status history row: %#v", r)
+		}
+		ds, err := derivatives.FromWsRaw(symbol, row, decoding(ss.Synchronous)...)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, ds)
+	}
+	return res, nil
+}
+
+// FundingRateHistory retrieves the funding rates of a perpetual contract
+// matching the query, e.g. the last day:
+//
+//	c.Status.FundingRateHistory("tBTCF0:USTF0", rest.NewQuery().From(time.Now().Add(-24*time.Hour)).SortAsc())
+//
+// see https://docs.bitfinex.com/reference#rest-public-derivatives-status-history for more info
+func (ss *StatusService) FundingRateHistory(symbol string, q *Query) (derivatives.FundingRateSeries, error) {
+	hist, err := ss.DerivativeStatusHistory(symbol, q)
+	if err != nil {
+		return nil, err
+	}
+	series := make(derivatives.FundingRateSeries, len(hist))
+	for i, ds := range hist {
+		series[i] = derivatives.FundingRateFromStatus(ds)
+	}
+	return series, nil
+}
+
+// PredictedFundingRate retrieves the current and the predicted funding rate
+// of a perpetual contract.
+// see https://docs.bitfinex.com/reference#rest-public-status for more info
+func (ss *StatusService) PredictedFundingRate(symbol string) (*derivatives.FundingRate, error) {
+	ds, err := ss.DerivativeStatus(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return derivatives.FundingRateFromStatus(ds), nil
+}
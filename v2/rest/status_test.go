@@ -0,0 +1,51 @@
+package rest_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/v2/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFundingRateHistory(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/status/deriv/tBTCF0:USTF0/hist":
+			assert.Equal(t, "limit=2&sort=1&start=1617000000000", r.URL.RawQuery)
+			_, _ = w.Write([]byte(`[
+				[1617000000000,null,58000,57900,null,1000,null,1617001000000,0.0002,10,null,0.0001,null,null,58010,null,null,1500],
+				[1617000060000,null,58100,58000,null,1000,null,1617001000000,0.00025,11,null,0.0001,null,null,58120,null,null,1510]
+			]`))
+		case "/status/deriv":
+			assert.Equal(t, "keys=tBTCF0%3AUSTF0", r.URL.RawQuery)
+			_, _ = w.Write([]byte(`[["tBTCF0:USTF0",1617000120000,null,58200,58100,null,1000,null,1617001000000,0.0003,12,null,0.0001,null,null,58210,null,null,1520]]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+	c := rest.NewClientWithURL(server.URL)
+
+	_, err := c.Status.FundingRateHistory("tBTCF0:USTF0", rest.NewQuery().Limit(10000))
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+
+	series, err := c.Status.FundingRateHistory("tBTCF0:USTF0", rest.NewQuery().FromMts(1617000000000).Limit(2).SortAsc())
+	require.Nil(t, err)
+	require.Len(t, series, 2)
+	assert.Equal(t, "tBTCF0:USTF0", series[1].Symbol)
+	assert.Equal(t, int64(1617000060000), series[1].MTS)
+	assert.Equal(t, 0.00025, series[1].Predicted)
+	assert.Equal(t, 58120.0, series[1].MarkPrice)
+	assert.InDelta(t, 0.0001, series.Mean(), 1e-12)
+
+	predicted, err := c.Status.PredictedFundingRate("tBTCF0:USTF0")
+	require.Nil(t, err)
+	assert.Equal(t, 0.0003, predicted.Predicted)
+	assert.Equal(t, int64(1617001000000), predicted.NextFundingMTS)
+}
@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/wallet"
+)
+
+// SweepResult reports the transfer of one wallet balance by SweepTo
+type SweepResult struct {
+	Currency     string
+	From         string
+	Amount       float64
+	Notification *notification.Notification
+	Err          error
+}
+
+// SweepTo consolidates the available balances of all other wallets into the
+// wallet of the given type, e.g. everything into the exchange wallet:
+//
+//	results, err := c.Wallet.SweepTo(wallet.Exchange, map[string]float64{"BTC": 0.001})
+//
+// Balances below the minimum amount of their currency are left in place.
+// Every transfer is attempted, failures are reported in the result of the
+// respective currency and wallet.
+func (ws *WalletService) SweepTo(walletType string, minAmount map[string]float64) ([]SweepResult, error) {
+	if err := wallet.ValidateType(walletType); err != nil {
+		return nil, err
+	}
+
+	snap, err := ws.Wallet()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SweepResult, 0)
+	for _, w := range snap.Snapshot {
+		if w.Type == walletType || !w.HasBalanceAvailable || w.BalanceAvailable <= 0 || w.BalanceAvailable < minAmount[w.Currency] {
+			continue
+		}
+
+		n, err := ws.Transfer(w.Type, walletType, w.Currency, w.Currency, w.BalanceAvailable)
+		results = append(results, SweepResult{
+			Currency:     w.Currency,
+			From:         w.Type,
+			Amount:       w.BalanceAvailable,
+			Notification: n,
+			Err:          err,
+		})
+	}
+
+	return results, nil
+}
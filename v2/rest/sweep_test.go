@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepTo(t *testing.T) {
+	var transfers []map[string]interface{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/auth/r/wallets":
+			w.Write([]byte(`[
+				["exchange","BTC",1,0,1,null,null],
+				["margin","BTC",0.5,0,0.5,null,null],
+				["funding","USD",100,0,40,null,null],
+				["funding","ETH",0.001,0,0.001,null,null],
+				["margin","UST",10,0,0,null,null],
+				["margin","EUR",10,0,10,null,null],
+				["margin","LTC",5,0,null,null,null]
+			]`))
+		case "/auth/w/transfer":
+			var body map[string]interface{}
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+			transfers = append(transfers, body)
+			if body["currency"] == "EUR" {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`["error",10001,"transfer failed"]`))
+				return
+			}
+			w.Write([]byte(`[1568742390999,"acc_tf",null,null,[],null,"SUCCESS","ok"]`))
+		default:
+			t.Fatalf("unexpected request %s", r.RequestURI)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	results, err := c.Wallet.SweepTo(wallet.Exchange, map[string]float64{"ETH": 0.01})
+	require.Nil(t, err)
+	require.Len(t, results, 3)
+	require.Len(t, transfers, 3)
+
+	assert.Equal(t, "BTC", results[0].Currency)
+	assert.Equal(t, wallet.Margin, results[0].From)
+	assert.Equal(t, 0.5, results[0].Amount)
+	assert.Nil(t, results[0].Err)
+	assert.Equal(t, notification.StatusSuccess, results[0].Notification.Status)
+	assert.Equal(t, "exchange", transfers[0]["to"])
+
+	assert.Equal(t, "USD", results[1].Currency)
+	assert.Equal(t, "40", transfers[1]["amount"])
+
+	assert.Equal(t, "EUR", results[2].Currency)
+	assert.NotNil(t, results[2].Err)
+
+	_, err = c.Wallet.SweepTo("trading", nil)
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
@@ -24,7 +24,7 @@ func (s *TickerService) getTickers(symbols []string) ([]*ticker.Ticker, error) {
 
 	tickers := make([]*ticker.Ticker, 0)
 	for _, traw := range raw {
-		t, err := ticker.FromRestRaw(traw.([]interface{}))
+		t, err := ticker.FromRestRaw(traw.([]interface{}), decoding(s.Synchronous)...)
 		if err != nil {
 			return nil, err
 		}
@@ -2,10 +2,10 @@ package rest
 
 import (
 	"fmt"
-	"net/url"
 	"strings"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/tickerhist"
 )
 
@@ -25,31 +25,41 @@ type GetTickerHistPayload struct {
 // Get - retrieves the ticker history for the given symbol
 // see https://docs.bitfinex.com/reference#tickers-history for more info
 func (s *TickerHistoryService) Get(pld GetTickerHistPayload) ([]tickerhist.TickerHist, error) {
-	if len(pld.Symbols) == 0 {
-		return nil, fmt.Errorf("missing mandatory parameters: []Symbols")
-	}
-
-	req := NewRequestWithMethod("tickers/hist", "GET")
-	req.Params = make(url.Values)
-	req.Params.Add("symbols", strings.Join(pld.Symbols, ","))
-
+	q := NewQuery()
 	if pld.Start != 0 {
-		req.Params.Add("start", fmt.Sprintf("%d", pld.Start))
+		q.FromMts(common.Mts(pld.Start))
 	}
 
 	if pld.End != 0 {
-		req.Params.Add("end", fmt.Sprintf("%d", pld.End))
+		q.ToMts(common.Mts(pld.End))
 	}
 
 	if pld.Limit != 0 {
-		req.Params.Add("limit", fmt.Sprintf("%d", pld.Limit))
+		q.Limit(int(pld.Limit))
+	}
+
+	return s.GetQuery(pld.Symbols, q)
+}
+
+// GetQuery - retrieves the ticker history for the given symbols matching the given query
+// see https://docs.bitfinex.com/reference#tickers-history for more info
+func (s *TickerHistoryService) GetQuery(symbols []string, q *Query) ([]tickerhist.TickerHist, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("%w: missing mandatory parameters: []Symbols", common.ErrBadRequest)
 	}
 
+	req := NewRequestWithMethod("tickers/hist", "GET")
+	req.Params = q.params()
+	req.Params.Add("symbols", strings.Join(symbols, ","))
+
 	raw, err := s.Request(req)
 	if err != nil {
 		return nil, err
 	}
 
-	tickers := tickerhist.SnapshotFromRaw(convert.ToInterfaceArray(raw))
+	tickers, err := tickerhist.SnapshotFromRaw(convert.ToInterfaceArray(raw), decoding(s.Synchronous)...)
+	if err != nil {
+		return nil, err
+	}
 	return tickers.Snapshot, nil
 }
@@ -1,9 +1,8 @@
 package rest
 
 import (
-	"net/url"
+	"fmt"
 	"path"
-	"strconv"
 
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
@@ -28,7 +27,7 @@ func (s *TradeService) allAccountWithSymbol(symbol string) (*tradeexecutionupdat
 	if err != nil {
 		return nil, err
 	}
-	return parseRawPrivateToSnapshot(raw)
+	return parseRawPrivateToSnapshot(raw, decoding(s.Synchronous)...)
 }
 
 func (s *TradeService) allAccount() (*tradeexecutionupdate.Snapshot, error) {
@@ -40,7 +39,7 @@ func (s *TradeService) allAccount() (*tradeexecutionupdate.Snapshot, error) {
 	if err != nil {
 		return nil, err
 	}
-	return parseRawPrivateToSnapshot(raw)
+	return parseRawPrivateToSnapshot(raw, decoding(s.Synchronous)...)
 }
 
 // Retrieves all matched trades for the account
@@ -64,20 +63,83 @@ func (s *TradeService) AccountHistoryWithQuery(
 	limit common.QueryLimit,
 	sort common.SortOrder,
 ) (*tradeexecutionupdate.Snapshot, error) {
-	req, err := s.requestFactory.NewAuthenticatedRequest(common.PermissionRead, path.Join("trades", symbol, "hist"))
+	return s.AccountHistoryQuery(symbol, fullQuery(start, end, limit, sort))
+}
+
+// AccountHistoryQuery - queries matched trades of the account with the given query,
+// optionally filtered by symbol. A page holds at most 2500 trades, see
+// AccountHistoryIterator to fetch more.
+// see https://docs.bitfinex.com/reference#rest-auth-trades-hist for more info
+func (s *TradeService) AccountHistoryQuery(symbol string, q *Query) (*tradeexecutionupdate.Snapshot, error) {
+	if q.exceedsLimit(int(maxLimit)) {
+		return nil, fmt.Errorf("%w: max request limit:%d, got: %d", common.ErrBadRequest, maxLimit, *q.limit)
+	}
+
+	raw, err := s.accountHistory(symbol, q)
 	if err != nil {
 		return nil, err
 	}
-	req.Params = make(url.Values)
-	req.Params.Add("end", strconv.FormatInt(int64(end), 10))
-	req.Params.Add("start", strconv.FormatInt(int64(start), 10))
-	req.Params.Add("limit", strconv.FormatInt(int64(limit), 10))
-	req.Params.Add("sort", strconv.FormatInt(int64(sort), 10))
-	raw, err := s.Request(req)
+	return parseRawPrivateToSnapshot(raw, decoding(s.Synchronous)...)
+}
+
+func (s *TradeService) accountHistory(symbol string, q *Query) ([]interface{}, error) {
+	req, err := s.requestFactory.NewAuthenticatedRequestWithData(common.PermissionRead, path.Join("trades", symbol, "hist"), q.payload())
+	if err != nil {
+		return nil, err
+	}
+	return s.Request(req)
+}
+
+// TradeHistoryIterator pages through the matched trades of the account beyond
+// the page limit of the endpoint, e.g.:
+//
+//	it := c.Trades.AccountHistoryIterator("tBTCUSD", rest.NewQuery().From(start).SortAsc())
+//	for {
+//		trades, err := it.Next()
+//		if err != nil || len(trades) == 0 {
+//			break
+//		}
+//		...
+//	}
+type TradeHistoryIterator struct {
+	trades *TradeService
+	symbol string
+	pager  *Pager
+}
+
+// AccountHistoryIterator returns an iterator over the trades matching q, in
+// the sort order of q, newest first by default. The limit of q caps the total
+// number of trades returned.
+func (s *TradeService) AccountHistoryIterator(symbol string, q *Query) *TradeHistoryIterator {
+	return &TradeHistoryIterator{
+		trades: s,
+		symbol: symbol,
+		pager:  NewPager(q, int(maxLimit)),
+	}
+}
+
+// Next returns the next page of trades. An empty page without error marks the
+// end of the history, see Pager for how pages are moved.
+func (it *TradeHistoryIterator) Next() ([]*tradeexecutionupdate.TradeExecutionUpdate, error) {
+	out := make([]*tradeexecutionupdate.TradeExecutionUpdate, 0)
+	if it.pager.Done() {
+		return out, it.pager.Err()
+	}
+
+	raw, err := it.trades.accountHistory(it.symbol, it.pager.Query())
+	if err != nil {
+		return nil, err
+	}
+	snap, err := parseRawPrivateToSnapshot(raw, decoding(it.trades.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
-	return parseRawPrivateToSnapshot(raw)
+
+	trades := snap.Snapshot
+	for _, i := range it.pager.Page(len(trades), func(i int) (int64, int64) { return trades[i].ID, trades[i].MTS }) {
+		out = append(out, trades[i])
+	}
+	return out, nil
 }
 
 // Queries all public trades with a group of optional paramters
@@ -89,12 +151,14 @@ func (s *TradeService) PublicHistoryWithQuery(
 	limit common.QueryLimit,
 	sort common.SortOrder,
 ) (*trade.Snapshot, error) {
+	return s.PublicHistoryQuery(symbol, fullQuery(start, end, limit, sort))
+}
+
+// PublicHistoryQuery - queries public trades of the given symbol with the given query
+// see https://docs.bitfinex.com/reference#rest-public-trades for more info
+func (s *TradeService) PublicHistoryQuery(symbol string, q *Query) (*trade.Snapshot, error) {
 	req := NewRequestWithMethod(path.Join("trades", symbol, "hist"), "GET")
-	req.Params = make(url.Values)
-	req.Params.Add("end", strconv.FormatInt(int64(end), 10))
-	req.Params.Add("start", strconv.FormatInt(int64(start), 10))
-	req.Params.Add("limit", strconv.FormatInt(int64(limit), 10))
-	req.Params.Add("sort", strconv.FormatInt(int64(sort), 10))
+	req.Params = q.params()
 	raw, err := s.Request(req)
 	if err != nil {
 		return nil, err
@@ -104,14 +168,69 @@ func (s *TradeService) PublicHistoryWithQuery(
 		return &trade.Snapshot{Snapshot: make([]*trade.Trade, 0)}, nil
 	}
 
-	return trade.SnapshotFromRaw(symbol, convert.ToInterfaceArray(raw))
+	return trade.SnapshotFromRaw(symbol, convert.ToInterfaceArray(raw), decoding(s.Synchronous)...)
+}
+
+// publicTradesLimit is the maximum page size of the public trades endpoint
+const publicTradesLimit = 10000
+
+// PublicTradeIterator pages through the public trades of a symbol beyond the
+// page limit of the endpoint, which allows downloading the complete tick
+// history, e.g.:
+//
+//	it := c.Trades.PublicHistoryIterator("tBTCUSD", rest.NewQuery().FromMts(start))
+//	for {
+//		trades, err := it.Next()
+//		if err != nil || len(trades) == 0 {
+//			break
+//		}
+//		...
+//	}
+type PublicTradeIterator struct {
+	trades *TradeService
+	symbol string
+	pager  *Pager
+}
+
+// PublicHistoryIterator returns an iterator over the public trades matching
+// q, in the sort order of q, newest first by default. The limit of q caps the
+// total number of trades returned.
+func (s *TradeService) PublicHistoryIterator(symbol string, q *Query) *PublicTradeIterator {
+	return &PublicTradeIterator{
+		trades: s,
+		symbol: symbol,
+		pager:  NewPager(q, publicTradesLimit),
+	}
+}
+
+// Next returns the next page of trades. An empty page without error marks the
+// end of the history, see Pager for how pages are moved. Should more trades
+// share a timestamp than fit into a page, the trades up to there are returned
+// and the following call fails with ErrPageOverflow, as the endpoint offers no
+// way to reach the remaining trades of that millisecond.
+func (it *PublicTradeIterator) Next() ([]*trade.Trade, error) {
+	out := make([]*trade.Trade, 0)
+	if it.pager.Done() {
+		return out, it.pager.Err()
+	}
+
+	snap, err := it.trades.PublicHistoryQuery(it.symbol, it.pager.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	trades := snap.Snapshot
+	for _, i := range it.pager.Page(len(trades), func(i int) (int64, int64) { return trades[i].ID, trades[i].MTS }) {
+		out = append(out, trades[i])
+	}
+	return out, nil
 }
 
-func parseRawPrivateToSnapshot(raw []interface{}) (*tradeexecutionupdate.Snapshot, error) {
+func parseRawPrivateToSnapshot(raw []interface{}, opts ...convert.DecodeOptions) (*tradeexecutionupdate.Snapshot, error) {
 	if len(raw) <= 0 {
 		return &tradeexecutionupdate.Snapshot{Snapshot: make([]*tradeexecutionupdate.TradeExecutionUpdate, 0)}, nil
 	}
-	tradeExecutions, err := tradeexecutionupdate.SnapshotFromRaw(raw)
+	tradeExecutions, err := tradeexecutionupdate.SnapshotFromRaw(raw, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,198 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tradeRaw(id, mts int64) []interface{} {
+	return []interface{}{id, "tBTCUSD", mts, 100 + id, 0.1, 9000, "EXCHANGE LIMIT", 9000, 1, -0.018, "USD", 7}
+}
+
+func TestAccountHistoryQuery(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/r/trades/tBTCUSD/hist", r.RequestURI)
+		var body map[string]interface{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"limit": float64(10), "sort": float64(1)}, body)
+		require.Nil(t, json.NewEncoder(w).Encode([]interface{}{tradeRaw(1, 1000)}))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	snap, err := c.Trades.AccountHistoryQuery("tBTCUSD", NewQuery().Limit(10).SortAsc())
+	require.Nil(t, err)
+	require.Len(t, snap.Snapshot, 1)
+	tr := snap.Snapshot[0]
+	assert.Equal(t, int64(101), tr.OrderID)
+	assert.Equal(t, -0.018, tr.Fee)
+	assert.Equal(t, "USD", tr.FeeCurrency)
+	assert.True(t, tr.IsMaker())
+	assert.Equal(t, int64(7), tr.ClientOrderID)
+
+	_, err = c.Trades.AccountHistoryQuery("tBTCUSD", NewQuery().Limit(2501))
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
+
+func TestAccountHistoryIterator(t *testing.T) {
+	// 2500 trades at mts 1..2500 followed by 3 more, the last page boundary
+	// shares a timestamp
+	var all [][]interface{}
+	for i := int64(1); i <= 2500; i++ {
+		all = append(all, tradeRaw(i, i))
+	}
+	all = append(all, tradeRaw(2501, 2500), tradeRaw(2502, 2501), tradeRaw(2503, 2502))
+
+	var starts []float64
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, float64(2500), body["limit"])
+		start, _ := body["start"].(float64)
+		starts = append(starts, start)
+
+		page := make([][]interface{}, 0)
+		for _, tr := range all {
+			if float64(tr[2].(int64)) >= start && len(page) < 2500 {
+				page = append(page, tr)
+			}
+		}
+		require.Nil(t, json.NewEncoder(w).Encode(page))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	it := NewClientWithURL(server.URL).Trades.AccountHistoryIterator("", NewQuery().FromMts(1).SortAsc())
+	var ids []int64
+	for {
+		page, err := it.Next()
+		require.Nil(t, err)
+		if len(page) == 0 {
+			break
+		}
+		for _, tr := range page {
+			ids = append(ids, tr.ID)
+		}
+	}
+
+	require.Len(t, ids, 2503)
+	assert.Equal(t, int64(2503), ids[2502])
+	assert.Equal(t, []float64{1, 2500}, starts)
+}
+
+// publicTradesServer serves the trades of all, newest first, within the end
+// of the query and records the requested ends
+func publicTradesServer(all [][]interface{}, ends *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		end := r.URL.Query().Get("end")
+		*ends = append(*ends, end)
+		max := int64(1 << 53)
+		if end != "" {
+			max, _ = strconv.ParseInt(end, 10, 64)
+		}
+
+		page := make([][]interface{}, 0)
+		for _, tr := range all {
+			if tr[1].(int64) <= max && len(page) < 10000 {
+				page = append(page, tr)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+}
+
+func TestPublicHistoryIterator(t *testing.T) {
+	// newest first: 5 trades, 5000 trades sharing mts 5000, then four
+	// trades per timestamp down to mts 0
+	var all [][]interface{}
+	for id := int64(30005); id > 30000; id-- {
+		all = append(all, []interface{}{id, id - 24000, 0.1, 9000})
+	}
+	for id := int64(30000); id > 25000; id-- {
+		all = append(all, []interface{}{id, int64(5000), 0.1, 9000})
+	}
+	for id := int64(19999); id > 0; id-- {
+		all = append(all, []interface{}{id, id / 4, -0.1, 9000})
+	}
+
+	var ends []string
+	server := publicTradesServer(all, &ends)
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	it := c.Trades.PublicHistoryIterator("tBTCUSD", NewQuery())
+	seen := map[int64]bool{}
+	last := int64(1 << 53)
+	for {
+		page, err := it.Next()
+		require.Nil(t, err)
+		if len(page) == 0 {
+			break
+		}
+		for _, tr := range page {
+			assert.False(t, seen[tr.ID], "trade %d returned twice", tr.ID)
+			assert.True(t, tr.MTS <= last)
+			seen[tr.ID] = true
+			last = tr.MTS
+		}
+	}
+	assert.Len(t, seen, 25004)
+	assert.Equal(t, []string{"", "3751", "1252"}, ends)
+
+	// the limit of the query caps the total
+	it = c.Trades.PublicHistoryIterator("tBTCUSD", NewQuery().Limit(15000))
+	count := 0
+	for {
+		page, err := it.Next()
+		require.Nil(t, err)
+		if len(page) == 0 {
+			break
+		}
+		count += len(page)
+	}
+	assert.Equal(t, 15000, count)
+}
+
+func TestPublicHistoryIteratorOverflow(t *testing.T) {
+	// 5 trades followed by 10000 trades sharing mts 5000, which fill a whole
+	// page, and one older trade which cannot be reached
+	var all [][]interface{}
+	for id := int64(10006); id > 10001; id-- {
+		all = append(all, []interface{}{id, id - 4000, 0.1, 9000})
+	}
+	for id := int64(10001); id > 1; id-- {
+		all = append(all, []interface{}{id, int64(5000), 0.1, 9000})
+	}
+	all = append(all, []interface{}{int64(1), int64(4000), 0.1, 9000})
+
+	var ends []string
+	server := publicTradesServer(all, &ends)
+	defer server.Close()
+
+	it := NewClientWithURL(server.URL).Trades.PublicHistoryIterator("tBTCUSD", NewQuery())
+	count := 0
+	var err error
+	for {
+		var page []*trade.Trade
+		page, err = it.Next()
+		if err != nil || len(page) == 0 {
+			break
+		}
+		count += len(page)
+	}
+	assert.True(t, errors.Is(err, ErrPageOverflow), "got %v", err)
+	assert.Equal(t, 10005, count)
+	assert.Equal(t, []string{"", "5000"}, ends)
+}
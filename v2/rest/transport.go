@@ -14,11 +14,21 @@ type HttpTransport struct {
 }
 
 func (h HttpTransport) Request(req Request) ([]interface{}, error) {
+	_, raw, err := h.RequestRaw(req)
+	if err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// RequestRaw executes the given request and returns the undecoded response
+// body along with its generic JSON representation.
+func (h HttpTransport) RequestRaw(req Request) ([]byte, []interface{}, error) {
 	var raw []interface{}
 
 	rel, err := url.Parse(req.RefURL)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if req.Params != nil {
 		rel.RawQuery = req.Params.Encode()
@@ -29,41 +39,44 @@ func (h HttpTransport) Request(req Request) ([]interface{}, error) {
 	body := bytes.NewReader(req.Data)
 
 	u := h.BaseURL.ResolveReference(rel)
-	httpReq, err := http.NewRequest(req.Method, u.String(), body)
+	httpReq, err := http.NewRequestWithContext(req.Context(), req.Method, u.String(), body)
+	if err != nil {
+		return nil, nil, err
+	}
 	for k, v := range req.Headers {
 		httpReq.Header.Add(k, v)
 	}
+	respBody, err := h.do(httpReq, &raw)
 	if err != nil {
-		return nil, err
-	}
-	err = h.do(httpReq, &raw)
-	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return raw, nil
+	return respBody, raw, nil
 }
 
 // Do executes API request created by NewRequest method or custom *http.Request.
-func (h HttpTransport) do(req *http.Request, v interface{}) (error) {
+func (h HttpTransport) do(req *http.Request, v interface{}) ([]byte, error) {
 	resp, err := h.httpDo(h.HTTPClient, req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	response := newResponse(resp)
 	err = checkResponse(response)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if v != nil {
-		err = json.Unmarshal(response.Body, v)
-		if err != nil {
-			return err
+		// keep numbers as json.Number so that 64 bit IDs and amounts do not
+		// lose precision by going through float64
+		d := json.NewDecoder(bytes.NewReader(response.Body))
+		d.UseNumber()
+		if err := d.Decode(v); err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	return response.Body, nil
 }
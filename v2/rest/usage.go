@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// EndpointStats counts the requests of an endpoint group
+type EndpointStats struct {
+	Requests    int64
+	Errors      int64
+	Duration    time.Duration // total duration of the requests
+	LastRequest time.Time
+	LastError   error
+	LastErrorAt time.Time
+}
+
+// MeanDuration returns the mean duration of the requests
+func (s EndpointStats) MeanDuration() time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.Duration / time.Duration(s.Requests)
+}
+
+// requestStats collects the EndpointStats of a client
+type requestStats struct {
+	mu     sync.Mutex
+	groups map[string]*EndpointStats
+}
+
+func newRequestStats() *requestStats {
+	return &requestStats{groups: map[string]*EndpointStats{}}
+}
+
+func (rs *requestStats) record(info RequestInfo) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	group := endpointGroup(info.RefURL)
+	s, ok := rs.groups[group]
+	if !ok {
+		s = &EndpointStats{}
+		rs.groups[group] = s
+	}
+	now := time.Now()
+	s.Requests++
+	s.Duration += info.Duration
+	s.LastRequest = now
+	if info.Err != nil {
+		s.Errors++
+		s.LastError = info.Err
+		s.LastErrorAt = now
+	}
+}
+
+func (rs *requestStats) snapshot() map[string]EndpointStats {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	out := make(map[string]EndpointStats, len(rs.groups))
+	for k, v := range rs.groups {
+		out[k] = *v
+	}
+	return out
+}
+
+// endpointGroup returns the endpoint without its parameters, i.e. the
+// resource of authenticated endpoints, e.g. "auth/r/orders", or the first
+// segment of public endpoints, e.g. "ticker"
+func endpointGroup(refURL string) string {
+	parts := strings.Split(strings.TrimPrefix(refURL, "/"), "/")
+	if parts[0] == "auth" && len(parts) >= 3 {
+		return strings.Join(parts[:3], "/")
+	}
+	return parts[0]
+}
+
+// RequestStats returns the request counts of the client by endpoint group,
+// e.g. "auth/r/orders" or "ticker". Cached responses are counted as well.
+func (c *Client) RequestStats() map[string]EndpointStats {
+	return c.stats.snapshot()
+}
@@ -2,10 +2,16 @@ package rest
 
 import (
 	"fmt"
+	"path"
 	"strconv"
+	"strings"
 
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/addrvalid"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/balanceinfo"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/depositaddress"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/movement"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/wallet"
 )
@@ -14,6 +20,8 @@ import (
 type WalletService struct {
 	requestFactory
 	Synchronous
+	guard     *idempotencyGuard
+	addresses *AddressBook
 }
 
 // Retrieves all of the wallets for the account
@@ -28,7 +36,7 @@ func (s *WalletService) Wallet() (*wallet.Snapshot, error) {
 		return nil, err
 	}
 
-	os, err := wallet.SnapshotFromRaw(raw)
+	os, err := wallet.SnapshotFromRaw(raw, decoding(s.Synchronous)...)
 	if err != nil {
 		return nil, err
 	}
@@ -36,9 +44,30 @@ func (s *WalletService) Wallet() (*wallet.Snapshot, error) {
 	return os, nil
 }
 
-// Submits a request to transfer funds from one Bitfinex wallet to another
+// Balances retrieves the wallets once and returns the balance of each of the
+// given currencies across the wallet types, in the given order. Currencies
+// without a wallet are included with zero balances.
+func (s *WalletService) Balances(currencies ...string) ([]*wallet.Balance, error) {
+	snap, err := s.Wallet()
+	if err != nil {
+		return nil, err
+	}
+	return snap.Balances(currencies...), nil
+}
+
+// Submits a request to transfer funds from one Bitfinex wallet to another.
+// Unknown wallet types and transfers into the same wallet without a currency
+// conversion are rejected before the request is sent.
 // see https://docs.bitfinex.com/reference#transfer-between-wallets for more info
 func (ws *WalletService) Transfer(from, to, currency, currencyTo string, amount float64) (*notification.Notification, error) {
+	if err := wallet.ValidateTransfer(from, to, currency, currencyTo, amount); err != nil {
+		return nil, err
+	}
+	ref := TransferRef(from, to, currency, currencyTo, amount)
+	if err := ws.guard.acquire(ref); err != nil {
+		return nil, err
+	}
+
 	body := map[string]interface{}{
 		"from":        from,
 		"to":          to,
@@ -48,18 +77,55 @@ func (ws *WalletService) Transfer(from, to, currency, currencyTo string, amount
 	}
 	req, err := ws.requestFactory.NewAuthenticatedRequestWithData(common.PermissionWrite, "transfer", body)
 	if err != nil {
+		ws.guard.release(ref)
 		return nil, err
 	}
 	raw, err := ws.Request(req)
+	var n *notification.Notification
+	if err == nil {
+		n, err = notificationFromRaw(raw, decoding(ws.Synchronous)...)
+	}
+	ws.guard.done(ref, err)
 	if err != nil {
 		return nil, err
 	}
-	return notification.FromRaw(raw)
+	return n, nil
 }
 
-func (ws *WalletService) depositAddress(wallet string, method string, renew int) (*notification.Notification, error) {
+// ConvertAndTransfer transfers amount of currency from one wallet to another
+// and converts it into the equivalent currencyTo on the way, e.g. UST into
+// USTF0 when funding the derivatives wallet. Pairs which transfers cannot
+// convert between, see CurrenciesService.TransferConversions, are rejected
+// before the transfer is submitted.
+func (c *Client) ConvertAndTransfer(from, to, currency, currencyTo string, amount float64) (*notification.Notification, error) {
+	currency, currencyTo = strings.ToUpper(currency), strings.ToUpper(currencyTo)
+	if currency == currencyTo {
+		return nil, fmt.Errorf("%w: cannot convert %s into itself", common.ErrBadRequest, currency)
+	}
+	if err := wallet.ValidateTransfer(from, to, currency, currencyTo, amount); err != nil {
+		return nil, err
+	}
+
+	conversions, err := c.Currencies.TransferConversions()
+	if err != nil {
+		return nil, err
+	}
+	if !conversions.Supports(currency, currencyTo) {
+		return nil, fmt.Errorf("%w: transfers cannot convert %s into %s", common.ErrBadRequest, currency, currencyTo)
+	}
+	return c.Wallet.Transfer(from, to, currency, currencyTo, amount)
+}
+
+func (ws *WalletService) depositAddress(walletType string, method string, renew int) (*notification.Notification, error) {
+	if err := wallet.ValidateType(walletType); err != nil {
+		return nil, err
+	}
+	if method == "" {
+		return nil, fmt.Errorf("%w: missing deposit method", common.ErrBadRequest)
+	}
+
 	body := map[string]interface{}{
-		"wallet":   wallet,
+		"wallet":   walletType,
 		"method":   method,
 		"op_renew": renew,
 	}
@@ -71,7 +137,14 @@ func (ws *WalletService) depositAddress(wallet string, method string, renew int)
 	if err != nil {
 		return nil, err
 	}
-	return notification.FromRaw(raw)
+	n, err := notificationFromRaw(raw, decoding(ws.Synchronous)...)
+	if err != nil {
+		return nil, err
+	}
+	if a, ok := n.NotifyInfo.(*depositaddress.Address); ok {
+		ws.AddressBook().Record(walletType, a, renew == 1)
+	}
+	return n, nil
 }
 
 // Retrieves the deposit address for the given Bitfinex wallet
@@ -86,11 +159,27 @@ func (ws *WalletService) CreateDepositAddress(wallet, method string) (*notificat
 	return ws.depositAddress(wallet, method, 1)
 }
 
-// Submits a request to withdraw funds from the given Bitfinex wallet to the given address
+// Submits a request to withdraw funds from the given Bitfinex wallet to the given address.
+// Unknown wallet types and malformed addresses of known methods are rejected
+// before the request is sent.
 // See https://docs.bitfinex.com/reference#withdraw for more info
-func (ws *WalletService) Withdraw(wallet, method string, amount float64, address string, paymentId *string) (*notification.Notification, error) {
+func (ws *WalletService) Withdraw(walletType, method string, amount float64, address string, paymentId *string) (*notification.Notification, error) {
+	if err := wallet.ValidateType(walletType); err != nil {
+		return nil, err
+	}
+	if method == "" {
+		return nil, fmt.Errorf("%w: missing withdrawal method", common.ErrBadRequest)
+	}
+	if err := addrvalid.Validate(method, address); err != nil {
+		return nil, err
+	}
+	ref := WithdrawalRef(walletType, method, amount, address, paymentId)
+	if err := ws.guard.acquire(ref); err != nil {
+		return nil, err
+	}
+
 	body := map[string]interface{}{
-		"wallet":  wallet,
+		"wallet":  walletType,
 		"method":  method,
 		"amount":  strconv.FormatFloat(amount, 'f', -1, 64),
 		"address": address,
@@ -100,74 +189,120 @@ func (ws *WalletService) Withdraw(wallet, method string, amount float64, address
 	}
 	req, err := ws.requestFactory.NewAuthenticatedRequestWithData(common.PermissionWrite, "withdraw", body)
 	if err != nil {
+		ws.guard.release(ref)
 		return nil, err
 	}
 	raw, err := ws.Request(req)
+	var n *notification.Notification
+	if err == nil {
+		n, err = notificationFromRaw(raw, decoding(ws.Synchronous)...)
+	}
+	ws.guard.done(ref, err)
 	if err != nil {
 		return nil, err
 	}
-	return notification.FromRaw(raw)
+	return n, nil
 }
 
-type Movement2 struct {
-	ID                      int64
-	Currency                string
-	CurrencyName            string
-	MtsStarted              int64
-	MtsUpdated              int64
-	Status                  string
-	Amount                  float64
-	Fees                    float64
-	DestinationAddress      string
-	TransactionID           string
-	WithdrawTransactionNote string
+// Movement2 is a deposit or withdrawal, see movement.Movement
+type Movement2 = movement.Movement
+
+func movement2FromRaw(raw []interface{}, opts ...convert.DecodeOptions) ([]Movement2, error) {
+	return movement.SnapshotFromRaw(raw, opts...)
 }
 
-func movement2FromRaw(raw []interface{}) (n []Movement2, err error) {
-	result := []Movement2{}
-	for _, item := range raw {
-		v, ok := item.([]interface{})
-		if !ok {
-			return
-		}
-		if len(v) != 22 {
-			return nil, fmt.Errorf("data slice too short for notification: %#v", raw)
-		}
-		n := Movement2{
-			ID:                      convert.I64ValOrZero(v[0]),
-			Currency:                convert.SValOrEmpty(v[1]),
-			CurrencyName:            convert.SValOrEmpty(v[2]),
-			MtsStarted:              convert.I64ValOrZero(v[5]),
-			MtsUpdated:              convert.I64ValOrZero(v[6]),
-			Status:                  convert.SValOrEmpty(v[9]),
-			Amount:                  convert.F64ValOrZero(v[12]),
-			Fees:                    convert.F64ValOrZero(v[13]),
-			DestinationAddress:      convert.SValOrEmpty(v[16]),
-			TransactionID:           convert.SValOrEmpty(v[20]),
-			WithdrawTransactionNote: convert.SValOrEmpty(v[21]),
-		}
-		result = append(result, n)
+// CalcAvailableBalance - calculates the amount available for an order or offer
+// on the given symbol with the exchange, taking margin and funding constraints
+// into account. dir is 1 for buy and -1 for sell, rate is the order price or
+// funding rate and is omitted if 0.
+// see https://docs.bitfinex.com/reference#rest-auth-calc-order-avail for more info
+func (ws *WalletService) CalcAvailableBalance(symbol string, dir int, rate float64, typ balanceinfo.AvailableType) (*balanceinfo.Available, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("%w: symbol cannot be empty", common.ErrBadRequest)
+	}
+	if dir != 1 && dir != -1 {
+		return nil, fmt.Errorf("%w: dir must be 1 or -1, got %d", common.ErrBadRequest, dir)
+	}
+	switch typ {
+	case balanceinfo.AvailableExchange, balanceinfo.AvailableMargin, balanceinfo.AvailableDeriv, balanceinfo.AvailableFunding:
+	default:
+		return nil, fmt.Errorf("%w: invalid available balance type %q", common.ErrBadRequest, typ)
+	}
+
+	body := map[string]interface{}{
+		"symbol": symbol,
+		"dir":    dir,
+		"type":   string(typ),
+	}
+	if rate != 0 {
+		body["rate"] = strconv.FormatFloat(rate, 'f', -1, 64)
+	}
+	// the endpoint lives at auth/calc rather than auth/r or auth/w
+	req, err := ws.requestFactory.NewAuthenticatedRequestWithData(common.PermissionType("calc"), path.Join("order", "avail"), body)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := ws.Request(req)
+	if err != nil {
+		return nil, err
 	}
-	return result, nil
+
+	a, err := balanceinfo.AvailableFromRaw(raw, decoding(ws.Synchronous)...)
+	if err != nil {
+		return nil, err
+	}
+	a.Symbol = symbol
+	a.Dir = dir
+	a.Rate = rate
+	a.Type = typ
+	return a, nil
 }
 
+// Movements - retrieves past deposits and withdrawals
+// see https://docs.bitfinex.com/reference#rest-auth-movements for more info
 func (ws *WalletService) Movements(start *int64, end *int64, max *int32) (n []Movement2, err error) {
-	var maxLimit int32 = 1000
-
-	payload := map[string]interface{}{}
+	q := NewQuery()
 	if start != nil {
-		payload["start"] = *start
+		q.FromMts(common.Mts(*start))
 	}
 	if end != nil {
-		payload["end"] = *end
+		q.ToMts(common.Mts(*end))
 	}
 	if max != nil {
-		if *max > maxLimit {
-			return nil, fmt.Errorf("Max request limit:%d, got: %d", maxLimit, max)
+		q.Limit(int(*max))
+	}
+	return ws.MovementsQuery("", q)
+}
+
+// movementsLimit is the maximum page size of the movements endpoint
+const movementsLimit = 1000
+
+// movementsFrom retrieves all deposits and withdrawals of the given currency
+// started at or after since, page by page
+func (ws *WalletService) movementsFrom(currency string, since common.Mts) ([]Movement2, error) {
+	pager := NewPager(NewQuery().FromMts(since), movementsLimit)
+	ms := []Movement2{}
+	for !pager.Done() {
+		page, err := ws.MovementsQuery(currency, pager.Query())
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range pager.Page(len(page), func(i int) (int64, int64) { return page[i].ID, page[i].MtsStarted }) {
+			ms = append(ms, page[i])
 		}
-		payload["limit"] = *max
 	}
-	req, err := ws.requestFactory.NewAuthenticatedRequestWithData(common.PermissionRead, "movements/hist", payload)
+	return ms, pager.Err()
+}
+
+// MovementsQuery - retrieves past deposits and withdrawals of the given currency
+// matching the given query. An empty currency returns movements of all currencies.
+// see https://docs.bitfinex.com/reference#rest-auth-movements for more info
+func (ws *WalletService) MovementsQuery(currency string, q *Query) ([]Movement2, error) {
+	if q.exceedsLimit(movementsLimit) {
+		return nil, fmt.Errorf("%w: max request limit:%d, got: %d", common.ErrBadRequest, movementsLimit, *q.limit)
+	}
+
+	req, err := ws.requestFactory.NewAuthenticatedRequestWithData(common.PermissionRead, path.Join("movements", currency, "hist"), q.payload())
 	if err != nil {
 		return nil, err
 	}
@@ -175,5 +310,5 @@ func (ws *WalletService) Movements(start *int64, end *int64, max *int32) (n []Mo
 	if err != nil {
 		return nil, err
 	}
-	return movement2FromRaw(raw)
+	return movement2FromRaw(raw, decoding(ws.Synchronous)...)
 }
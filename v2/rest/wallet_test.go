@@ -0,0 +1,131 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/balanceinfo"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalcAvailableBalance(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/calc/order/avail", r.RequestURI)
+		var body map[string]interface{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "tBTCUSD", body["symbol"])
+		assert.Equal(t, float64(-1), body["dir"])
+		assert.Equal(t, "9000.5", body["rate"])
+		assert.Equal(t, "MARGIN", body["type"])
+		w.Write([]byte(`[-2.25]`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	a, err := c.Wallet.CalcAvailableBalance("tBTCUSD", -1, 9000.5, balanceinfo.AvailableMargin)
+	require.Nil(t, err)
+	assert.Equal(t, &balanceinfo.Available{
+		Symbol: "tBTCUSD",
+		Dir:    -1,
+		Rate:   9000.5,
+		Type:   balanceinfo.AvailableMargin,
+		Amount: -2.25,
+	}, a)
+
+	_, err = c.Wallet.CalcAvailableBalance("tBTCUSD", 0, 0, balanceinfo.AvailableMargin)
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+	_, err = c.Wallet.CalcAvailableBalance("tBTCUSD", 1, 0, "SPOT")
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
+
+func TestBalances(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/auth/r/wallets", r.RequestURI)
+		w.Write([]byte(`[
+			["exchange","BTC",1.5,0,1.25,null,null],
+			["margin","BTC",0.5,0,0.5,null,null],
+			["exchange","USD",100,0,100,null,null]
+		]`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	balances, err := c.Wallet.Balances("btc", "ETH", "BTC")
+	require.Nil(t, err)
+	assert.Equal(t, 1, calls)
+	require.Len(t, balances, 2)
+
+	assert.Equal(t, "BTC", balances[0].Currency)
+	assert.Equal(t, 2.0, balances[0].Balance)
+	assert.Equal(t, 1.75, balances[0].BalanceAvailable)
+	assert.Len(t, balances[0].Wallets, 2)
+
+	assert.True(t, balances[0].HasBalanceAvailable)
+
+	assert.Equal(t, &wallet.Balance{Currency: "ETH", HasBalanceAvailable: true, Wallets: []*wallet.Wallet{}}, balances[1])
+}
+
+func TestConvertAndTransfer(t *testing.T) {
+	var transfers []map[string]interface{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/conf/pub:map:currency:undl":
+			w.Write([]byte(`[[["USTF0","UST"],["EUTF0","EUT"]]]`))
+		case "/auth/w/transfer":
+			var body map[string]interface{}
+			require.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+			transfers = append(transfers, body)
+			w.Write([]byte(`[1568742390999,"acc_tf",null,null,[],null,"SUCCESS","ok"]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	c := NewClientWithURL(server.URL)
+	n, err := c.ConvertAndTransfer("exchange", "margin", "ust", "ustf0", 100)
+	require.Nil(t, err)
+	assert.Equal(t, notification.StatusSuccess, n.Status)
+	_, err = c.ConvertAndTransfer("exchange", "exchange", "USD", "UST", 100)
+	require.Nil(t, err)
+	require.Len(t, transfers, 2)
+	assert.Equal(t, "UST", transfers[0]["currency"])
+	assert.Equal(t, "USTF0", transfers[0]["currency_to"])
+	assert.Equal(t, "USD", transfers[1]["currency"])
+
+	_, err = c.ConvertAndTransfer("exchange", "margin", "UST", "EUTF0", 100)
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+	_, err = c.ConvertAndTransfer("exchange", "margin", "UST", "UST", 100)
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+	assert.Len(t, transfers, 2)
+}
+
+func TestMovementsKeepLargeIDs(t *testing.T) {
+	const id = int64(9007199254740993) // 2^53 + 1, not representable as float64
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		require.Nil(t, json.NewEncoder(w).Encode([]interface{}{movementRaw(id, 1000, "COMPLETED", -1)}))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	ms, err := NewClientWithURL(server.URL).Wallet.Movements(nil, nil, nil)
+	require.Nil(t, err)
+	require.Len(t, ms, 1)
+	assert.Equal(t, id, ms[0].ID)
+}
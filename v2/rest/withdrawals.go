@@ -0,0 +1,154 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/movement"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+)
+
+// WithdrawalID returns the id of the withdrawal created by a Withdraw request
+func WithdrawalID(n *notification.Notification) (int64, error) {
+	info, ok := n.NotifyInfo.([]interface{})
+	if !ok || len(info) == 0 {
+		return 0, fmt.Errorf("%w: no withdrawal in notification: %s", common.ErrNotFound, n.Text)
+	}
+
+	id := convert.I64ValOrZero(info[0])
+	if id == 0 {
+		return 0, fmt.Errorf("%w: no withdrawal in notification: %s", common.ErrNotFound, n.Text)
+	}
+	return id, nil
+}
+
+// WithdrawalUpdate is reported on every status change of a tracked withdrawal
+type WithdrawalUpdate struct {
+	ID       int64
+	Status   movement.Status
+	Final    bool // no further updates follow
+	Movement Movement2
+}
+
+type trackedWithdrawal struct {
+	status  movement.Status
+	handler func(WithdrawalUpdate)
+}
+
+// WithdrawalTracker follows withdrawals through their statuses by polling
+// the movements, e.g.:
+//
+//	n, _ := c.Wallet.Withdraw("exchange", "bitcoin", 0.1, addr, nil)
+//	wt.TrackNotification(n, func(u rest.WithdrawalUpdate) {
+//		log.Printf("withdrawal %d: %s", u.ID, u.Status)
+//	})
+type WithdrawalTracker struct {
+	wallet   *WalletService
+	interval time.Duration
+
+	mu      sync.Mutex
+	since   int64
+	tracked map[int64]*trackedWithdrawal
+}
+
+// NewWithdrawalTracker returns a tracker polling the movements every interval
+func (ws *WalletService) NewWithdrawalTracker(interval time.Duration) *WithdrawalTracker {
+	return &WithdrawalTracker{
+		wallet:   ws,
+		interval: interval,
+		tracked:  make(map[int64]*trackedWithdrawal),
+	}
+}
+
+// Track calls handler on every status change of the withdrawal until it
+// reaches a final status
+func (wt *WithdrawalTracker) Track(id int64, handler func(WithdrawalUpdate)) {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+
+	// movements are polled starting shortly before the oldest tracked withdrawal
+	since := int64(common.MtsFromTime(time.Now().Add(-time.Hour)))
+	if len(wt.tracked) == 0 || since < wt.since {
+		wt.since = since
+	}
+	wt.tracked[id] = &trackedWithdrawal{handler: handler}
+}
+
+// TrackNotification tracks the withdrawal created by a Withdraw request
+func (wt *WithdrawalTracker) TrackNotification(n *notification.Notification, handler func(WithdrawalUpdate)) (int64, error) {
+	id, err := WithdrawalID(n)
+	if err != nil {
+		return 0, err
+	}
+	wt.Track(id, handler)
+	return id, nil
+}
+
+// Pending returns the number of withdrawals which did not reach a final status
+func (wt *WithdrawalTracker) Pending() int {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+	return len(wt.tracked)
+}
+
+// Run polls until ctx is done
+func (wt *WithdrawalTracker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(wt.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := wt.Poll(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetches the movements once and reports status changes of the tracked
+// withdrawals
+func (wt *WithdrawalTracker) Poll() error {
+	wt.mu.Lock()
+	if len(wt.tracked) == 0 {
+		wt.mu.Unlock()
+		return nil
+	}
+	since := wt.since
+	wt.mu.Unlock()
+
+	ms, err := wt.wallet.movementsFrom("", common.Mts(since))
+	if err != nil {
+		return err
+	}
+
+	for _, m := range ms {
+		wt.update(m)
+	}
+	return nil
+}
+
+func (wt *WithdrawalTracker) update(m Movement2) {
+	wt.mu.Lock()
+	t, ok := wt.tracked[m.ID]
+	if !ok || t.status == m.Status {
+		wt.mu.Unlock()
+		return
+	}
+
+	t.status = m.Status
+	final := m.Status.IsFinal()
+	if final {
+		delete(wt.tracked, m.ID)
+	}
+	wt.mu.Unlock()
+
+	t.handler(WithdrawalUpdate{ID: m.ID, Status: m.Status, Final: final, Movement: m})
+}
@@ -0,0 +1,110 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/movement"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithdrawalTracker(t *testing.T) {
+	responses := [][]interface{}{
+		{movementRaw(13080092, 1000, "PENDING REVIEW", -0.01), movementRaw(1, 1000, "PROCESSING", -1)},
+		{movementRaw(13080092, 1000, "PENDING REVIEW", -0.01)},
+		{movementRaw(13080092, 1000, "SENDING", -0.01)},
+		{movementRaw(13080092, 1000, "COMPLETED", -0.01)},
+	}
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/r/movements/hist", r.RequestURI)
+		require.Nil(t, json.NewEncoder(w).Encode(responses[calls]))
+		calls++
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	n, err := notification.FromRaw([]interface{}{
+		1568742390999, "acc_wd-req", nil, nil,
+		[]interface{}{13080092, nil, "ethereum", "r", nil, -0.01, nil, nil, 0.000221},
+		nil, "SUCCESS", "Your withdrawal request has been successfully submitted.",
+	})
+	require.Nil(t, err)
+
+	wt := NewClientWithURL(server.URL).Wallet.NewWithdrawalTracker(0)
+	var updates []WithdrawalUpdate
+	id, err := wt.TrackNotification(n, func(u WithdrawalUpdate) { updates = append(updates, u) })
+	require.Nil(t, err)
+	assert.Equal(t, int64(13080092), id)
+
+	for i := 0; i < len(responses); i++ {
+		require.Nil(t, wt.Poll())
+	}
+
+	require.Len(t, updates, 3)
+	assert.Equal(t, movement.StatusPendingReview, updates[0].Status)
+	assert.Equal(t, movement.StatusSending, updates[1].Status)
+	assert.Equal(t, movement.StatusCompleted, updates[2].Status)
+	assert.True(t, updates[2].Final)
+	assert.Equal(t, 0, wt.Pending())
+
+	// nothing is polled once all withdrawals are final
+	require.Nil(t, wt.Poll())
+	assert.Equal(t, len(responses), calls)
+}
+
+func TestWithdrawalTrackerPages(t *testing.T) {
+	// the tracked withdrawal is older than the first page of movements
+	now := int64(common.MtsFromTime(time.Now()))
+	status := "PROCESSING"
+	all := func() [][]interface{} {
+		ms := [][]interface{}{}
+		for id := int64(1); id <= 100; id++ {
+			s := "COMPLETED"
+			if id == 90 {
+				s = status
+			}
+			ms = append(ms, movementRaw(id, now-id*1000, s, -1))
+		}
+		return ms
+	}
+	server := movementsServer(all)
+	defer server.Close()
+
+	wt := NewClientWithURL(server.URL).Wallet.NewWithdrawalTracker(0)
+	var updates []WithdrawalUpdate
+	wt.Track(90, func(u WithdrawalUpdate) { updates = append(updates, u) })
+
+	require.Nil(t, wt.Poll())
+	status = "COMPLETED"
+	require.Nil(t, wt.Poll())
+
+	require.Len(t, updates, 2)
+	assert.Equal(t, movement.StatusProcessing, updates[0].Status)
+	assert.True(t, updates[1].Final)
+}
+
+func TestWithdrawalID(t *testing.T) {
+	_, err := WithdrawalID(&notification.Notification{Text: "Invalid bitcoin address"})
+	assert.True(t, errors.Is(err, common.ErrNotFound))
+}
+
+func TestWithdrawValidatesAddress(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("request should not be sent")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	_, err := NewClientWithURL(server.URL).Wallet.Withdraw("exchange", "bitcoin", 0.1, "bc1invalid", nil)
+	assert.True(t, errors.Is(err, common.ErrBadRequest))
+}
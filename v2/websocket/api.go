@@ -25,7 +25,7 @@ func (c *Client) Send(ctx context.Context, msg interface{}) error {
 	if err != nil {
 		return err
 	}
-	return socket.Asynchronous.Send(ctx, msg)
+	return c.sendBySocket(ctx, socket, msg)
 }
 
 // Submit a request to enable the given flag
@@ -43,7 +43,7 @@ func (c *Client) EnableFlag(ctx context.Context, flag int) (string, error) {
 	}
 	c.mtx.RUnlock()
 	for _, socket := range socks {
-		err := socket.Asynchronous.Send(ctx, req)
+		err := c.sendBySocket(ctx, socket, req)
 		if err != nil {
 			return "", err
 		}
@@ -72,7 +72,7 @@ func (c *Client) StartNewConnection() error {
 
 func (c *Client) subscribeBySocket(ctx context.Context, socket *Socket, req *SubscriptionRequest) (string, error) {
 	c.subscriptions.add(socket.Id, req)
-	err := socket.Asynchronous.Send(ctx, req)
+	err := c.sendBySocket(ctx, socket, req)
 	if err != nil {
 		// propagate send error
 		return "", err
@@ -160,6 +160,19 @@ func (c *Client) SubscribeStatus(ctx context.Context, symbol string, sType commo
 	return c.Subscribe(ctx, req)
 }
 
+// SubscribeDerivativeStatus subscribes to the status of a derivative, e.g.
+// its mark price, funding and open interest, delivered as
+// *derivatives.DerivativeStatus
+func (c *Client) SubscribeDerivativeStatus(ctx context.Context, symbol string) (string, error) {
+	return c.SubscribeStatus(ctx, symbol, common.DerivativeStatusType)
+}
+
+// SubscribeLiquidations subscribes to the liquidations of the platform,
+// delivered as *status.LiquidationsSnapshot
+func (c *Client) SubscribeLiquidations(ctx context.Context) (string, error) {
+	return c.SubscribeStatus(ctx, "global", common.LiquidationStatusType)
+}
+
 // Retrieve the Orderbook for the given symbol which is managed locally.
 // This requires ManageOrderbook=True and an active chanel subscribed to the given
 // symbols orderbook
@@ -170,34 +183,45 @@ func (c *Client) GetOrderbook(symbol string) (*Orderbook, error) {
 		// take dereferenced copy of orderbook
 		return val, nil
 	}
-	return nil, fmt.Errorf("Orderbook %s does not exist", symbol)
+	return nil, fmt.Errorf("Orderbook %s does not exist: %w", symbol, common.ErrNotFound)
+}
+
+// EstimateFill walks the managed orderbook of the symbol for an order of the
+// given side and absolute amount, see book.EstimateFill. This requires
+// ManageOrderbook=True and an active subscription to the book of the symbol.
+func (c *Client) EstimateFill(symbol string, side common.OrderSide, amount float64) (book.Estimate, error) {
+	ob, err := c.GetOrderbook(symbol)
+	if err != nil {
+		return book.Estimate{}, err
+	}
+	return ob.EstimateFill(side, amount), nil
 }
 
 // Submit a request to create a new order
 func (c *Client) SubmitOrder(ctx context.Context, onr *order.NewRequest) error {
-	socket, err := c.GetAuthenticatedSocket()
+	socket, err := c.writeSocket(ctx)
 	if err != nil {
 		return err
 	}
-	return socket.Asynchronous.Send(ctx, onr)
+	return c.sendBySocket(ctx, socket, onr)
 }
 
 // Submit and update request to change an existing orders values
 func (c *Client) SubmitUpdateOrder(ctx context.Context, our *order.UpdateRequest) error {
-	socket, err := c.GetAuthenticatedSocket()
+	socket, err := c.writeSocket(ctx)
 	if err != nil {
 		return err
 	}
-	return socket.Asynchronous.Send(ctx, our)
+	return c.sendBySocket(ctx, socket, our)
 }
 
 // Submit a cancel request for an existing order
 func (c *Client) SubmitCancel(ctx context.Context, ocr *order.CancelRequest) error {
-	socket, err := c.GetAuthenticatedSocket()
+	socket, err := c.writeSocket(ctx)
 	if err != nil {
 		return err
 	}
-	return socket.Asynchronous.Send(ctx, ocr)
+	return c.sendBySocket(ctx, socket, ocr)
 }
 
 // Get a subscription request using a subscription ID
@@ -211,36 +235,36 @@ func (c *Client) LookupSubscription(subID string) (*SubscriptionRequest, error)
 
 // Submit a new funding offer request
 func (c *Client) SubmitFundingOffer(ctx context.Context, fundingOffer *fundingoffer.SubmitRequest) error {
-	socket, err := c.GetAuthenticatedSocket()
+	socket, err := c.writeSocket(ctx)
 	if err != nil {
 		return err
 	}
-	return socket.Asynchronous.Send(ctx, fundingOffer)
+	return c.sendBySocket(ctx, socket, fundingOffer)
 }
 
 // Submit a request to cancel and existing funding offer
 func (c *Client) SubmitFundingCancel(ctx context.Context, fundingOffer *fundingoffer.CancelRequest) error {
-	socket, err := c.GetAuthenticatedSocket()
+	socket, err := c.writeSocket(ctx)
 	if err != nil {
 		return err
 	}
-	return socket.Asynchronous.Send(ctx, fundingOffer)
+	return c.sendBySocket(ctx, socket, fundingOffer)
 }
 
 // CloseFundingLoan - cancels funding loan by ID. Emits an error if not authenticated.
 func (c *Client) CloseFundingLoan(ctx context.Context, flcr *fundingloan.CancelRequest) error {
-	socket, err := c.GetAuthenticatedSocket()
+	socket, err := c.writeSocket(ctx)
 	if err != nil {
 		return err
 	}
-	return socket.Asynchronous.Send(ctx, flcr)
+	return c.sendBySocket(ctx, socket, flcr)
 }
 
 // CloseFundingCredit - cancels funding credit by ID. Emits an error if not authenticated.
 func (c *Client) CloseFundingCredit(ctx context.Context, fundingOffer *fundingcredit.CancelRequest) error {
-	socket, err := c.GetAuthenticatedSocket()
+	socket, err := c.writeSocket(ctx)
 	if err != nil {
 		return err
 	}
-	return socket.Asynchronous.Send(ctx, fundingOffer)
+	return c.sendBySocket(ctx, socket, fundingOffer)
 }
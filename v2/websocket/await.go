@@ -0,0 +1,189 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/notification"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/order"
+)
+
+// TimeoutError is returned by the helpers awaiting the response of the
+// exchange when the context is done before it arrives. It wraps the error of
+// the context, so errors.Is(err, context.DeadlineExceeded) holds for
+// deadlines.
+type TimeoutError struct {
+	Request interface{} // the request which was not responded to
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("awaiting response to %T: %s", e.Request, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// waiter receives the first event accepted by match
+type waiter struct {
+	match func(ev interface{}) bool
+	ch    chan interface{}
+}
+
+// waiters correlates events with pending requests
+type waiters struct {
+	mu      sync.Mutex
+	pending []*waiter
+}
+
+func (w *waiters) add(match func(ev interface{}) bool) *waiter {
+	wt := &waiter{match: match, ch: make(chan interface{}, 1)}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = append(w.pending, wt)
+	return wt
+}
+
+func (w *waiters) remove(wt *waiter) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i, p := range w.pending {
+		if p == wt {
+			w.pending = append(w.pending[:i], w.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// resolve hands the event to the waiters accepting it
+func (w *waiters) resolve(ev interface{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	pending := w.pending[:0]
+	for _, p := range w.pending {
+		if p.match(ev) {
+			p.ch <- ev
+			continue
+		}
+		pending = append(pending, p)
+	}
+	w.pending = pending
+}
+
+// await sends the request and returns the first event accepted by match, or
+// a *TimeoutError once ctx is done
+func (c *Client) await(ctx context.Context, req interface{}, send func() error, match func(ev interface{}) bool) (interface{}, error) {
+	wt := c.waiters.add(match)
+	defer c.waiters.remove(wt)
+
+	if err := send(); err != nil {
+		return nil, err
+	}
+	select {
+	case ev := <-wt.ch:
+		return ev, nil
+	case <-ctx.Done():
+		return nil, &TimeoutError{Request: req, Err: ctx.Err()}
+	}
+}
+
+// SubscribeAndWait subscribes like Subscribe and waits until the exchange
+// confirms the subscription. A rejected subscription is returned as error.
+func (c *Client) SubscribeAndWait(ctx context.Context, req *SubscriptionRequest) (*SubscribeEvent, error) {
+	if req.SubID == "" {
+		req.SubID = c.nonce.GetNonce()
+	}
+	ev, err := c.await(ctx, req, func() error {
+		_, err := c.Subscribe(ctx, req)
+		return err
+	}, func(ev interface{}) bool {
+		switch e := ev.(type) {
+		case *SubscribeEvent:
+			return e.SubID == req.SubID
+		case *ErrorEvent:
+			return e.SubID == req.SubID
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := ev.(*ErrorEvent); ok {
+		return nil, fmt.Errorf("subscription %s to %s rejected: %s (%d)", req.SubID, req.Channel, e.Message, e.Code)
+	}
+	return ev.(*SubscribeEvent), nil
+}
+
+var cidCounter = time.Now().UnixNano() / int64(time.Millisecond)
+
+// SubmitOrderAndWait submits the order and waits for the notification of the
+// exchange accepting it, which is matched by the client id of the order. An
+// order without client id is assigned one unique within the process. A
+// rejected order is returned as error along with the notification.
+func (c *Client) SubmitOrderAndWait(ctx context.Context, onr *order.NewRequest) (*notification.Notification, error) {
+	if onr.CID == 0 {
+		onr.CID = atomic.AddInt64(&cidCounter, 1)
+	}
+	return c.awaitNotification(ctx, onr, func() error { return c.SubmitOrder(ctx, onr) }, "on-req", func(o order.Order) bool {
+		return o.CID == onr.CID
+	})
+}
+
+// SubmitUpdateOrderAndWait submits the update and waits for the notification
+// of the exchange, which is matched by the order id.
+func (c *Client) SubmitUpdateOrderAndWait(ctx context.Context, our *order.UpdateRequest) (*notification.Notification, error) {
+	return c.awaitNotification(ctx, our, func() error { return c.SubmitUpdateOrder(ctx, our) }, "ou-req", func(o order.Order) bool {
+		return o.ID == our.ID
+	})
+}
+
+// SubmitCancelAndWait submits the cancel and waits for the notification of
+// the exchange, which is matched by the order id or the client id. A cancel
+// naming neither is rejected with common.ErrBadRequest.
+func (c *Client) SubmitCancelAndWait(ctx context.Context, ocr *order.CancelRequest) (*notification.Notification, error) {
+	if ocr.ID == 0 && ocr.CID == 0 {
+		return nil, fmt.Errorf("%w: cancel requires an order id or a client id", common.ErrBadRequest)
+	}
+	return c.awaitNotification(ctx, ocr, func() error { return c.SubmitCancel(ctx, ocr) }, "oc-req", func(o order.Order) bool {
+		if ocr.ID != 0 {
+			return o.ID == ocr.ID
+		}
+		return o.CID == ocr.CID
+	})
+}
+
+func (c *Client) awaitNotification(ctx context.Context, req interface{}, send func() error, typ string, match func(order.Order) bool) (*notification.Notification, error) {
+	ev, err := c.await(ctx, req, send, func(ev interface{}) bool {
+		n, ok := ev.(*notification.Notification)
+		if !ok || n.Type != typ {
+			return false
+		}
+		o, ok := notifiedOrder(n.NotifyInfo)
+		return ok && match(o)
+	})
+	if err != nil {
+		return nil, err
+	}
+	n := ev.(*notification.Notification)
+	if err := n.Err(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+func notifiedOrder(info interface{}) (order.Order, bool) {
+	switch o := info.(type) {
+	case order.New:
+		return order.Order(o), true
+	case order.Update:
+		return order.Order(o), true
+	case order.Cancel:
+		return order.Order(o), true
+	}
+	return order.Order{}, false
+}
@@ -0,0 +1,112 @@
+package websocket
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff decides whether and when a disconnected socket is reconnected
+type Backoff interface {
+	// NextDelay returns the delay before the given reconnect attempt,
+	// counted from 1, or false if no further attempt should be made
+	NextDelay(attempt int) (time.Duration, bool)
+	// ResetAfter returns how long a reconnected socket has to stay up before
+	// the attempt count starts over. A socket disconnecting earlier continues
+	// counting, so a flapping connection backs off further.
+	ResetAfter() time.Duration
+}
+
+// ConstantBackoff waits Interval before each of at most Attempts attempts.
+// This is the behavior of Parameters.ReconnectInterval and ReconnectAttempts.
+type ConstantBackoff struct {
+	Interval time.Duration
+	Attempts int
+	Reset    time.Duration // see Backoff.ResetAfter, 0 resets on every reconnect
+}
+
+// NextDelay implements Backoff
+func (b *ConstantBackoff) NextDelay(attempt int) (time.Duration, bool) {
+	return b.Interval, attempt <= b.Attempts
+}
+
+// ResetAfter implements Backoff
+func (b *ConstantBackoff) ResetAfter() time.Duration {
+	return b.Reset
+}
+
+// ExponentialBackoff multiplies the delay with every attempt up to Max
+type ExponentialBackoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64 // defaults to 2
+	// Jitter randomizes the given fraction of each delay, e.g. 0.2 waits
+	// between 80% and 100% of the delay, so clients do not reconnect in
+	// lockstep after an outage
+	Jitter   float64
+	Attempts int           // 0 retries forever
+	Reset    time.Duration // see Backoff.ResetAfter
+}
+
+// NewExponentialBackoff returns an exponential backoff from one second up to
+// one minute, retrying forever and starting over once a socket stayed up for
+// five minutes
+func NewExponentialBackoff() *ExponentialBackoff {
+	return &ExponentialBackoff{
+		Initial: time.Second,
+		Max:     time.Minute,
+		Jitter:  0.2,
+		Reset:   5 * time.Minute,
+	}
+}
+
+// NextDelay implements Backoff
+func (b *ExponentialBackoff) NextDelay(attempt int) (time.Duration, bool) {
+	if b.Attempts > 0 && attempt > b.Attempts {
+		return 0, false
+	}
+	mult := b.Multiplier
+	if mult <= 0 {
+		mult = 2
+	}
+
+	d := float64(b.Initial) * math.Pow(mult, float64(attempt-1))
+	if b.Max > 0 && d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+	if b.Jitter > 0 {
+		d -= d * b.Jitter * rand.Float64()
+	}
+	return time.Duration(d), true
+}
+
+// ResetAfter implements Backoff
+func (b *ExponentialBackoff) ResetAfter() time.Duration {
+	return b.Reset
+}
+
+// ReconnectEvent is emitted on the Listen channel after every reconnect
+// attempt of a socket, e.g. to alert on long outages
+type ReconnectEvent struct {
+	SocketId SocketId
+	// Attempt counts the attempts since the socket was last stable, see
+	// Backoff.ResetAfter
+	Attempt  int
+	Delay    time.Duration // delay before the attempt
+	Downtime time.Duration // time since the socket was disconnected
+	Err      error         // error of a failed attempt, nil once reconnected
+	// GaveUp is set once the backoff allows no further attempt, Err is the
+	// error of the disconnect then
+	GaveUp bool
+}
+
+// Reconnected reports whether the attempt succeeded
+func (e *ReconnectEvent) Reconnected() bool {
+	return e.Err == nil && !e.GaveUp
+}
+
+// reconnectState tracks the reconnects of a socket id across disconnects
+type reconnectState struct {
+	attempt     int
+	connectedAt time.Time
+}
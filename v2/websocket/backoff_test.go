@@ -0,0 +1,54 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	b := &ExponentialBackoff{Initial: time.Second, Max: 10 * time.Second, Attempts: 5}
+	var delays []time.Duration
+	for attempt := 1; ; attempt++ {
+		d, ok := b.NextDelay(attempt)
+		if !ok {
+			break
+		}
+		delays = append(delays, d)
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second}, delays)
+
+	b.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d, _ := b.NextDelay(2)
+		assert.True(t, d > time.Second && d <= 2*time.Second, d)
+	}
+
+	d, ok := NewExponentialBackoff().NextDelay(1000)
+	assert.True(t, ok)
+	assert.True(t, d <= time.Minute)
+}
+
+func TestReconnectPolicyBackoff(t *testing.T) {
+	p := NewDefaultParameters()
+	b := p.reconnectPolicy(false).backoff()
+	d, ok := b.NextDelay(15)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+	_, ok = b.NextDelay(16)
+	assert.False(t, ok)
+	assert.Equal(t, time.Duration(0), b.ResetAfter())
+
+	exp := NewExponentialBackoff()
+	p.ReconnectBackoff = exp
+	assert.Equal(t, Backoff(exp), p.reconnectPolicy(false).backoff())
+	p.AuthReconnect = &ReconnectPolicy{AutoReconnect: true, Interval: time.Second, Attempts: 1}
+	_, ok = p.reconnectPolicy(true).backoff().NextDelay(2)
+	assert.False(t, ok)
+}
+
+func TestReconnectEvent(t *testing.T) {
+	assert.True(t, (&ReconnectEvent{Attempt: 1}).Reconnected())
+	assert.False(t, (&ReconnectEvent{Attempt: 15, GaveUp: true}).Reconnected())
+}
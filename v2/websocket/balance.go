@@ -0,0 +1,113 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/wallet"
+)
+
+const (
+	// calcInterval is the minimum delay between two calc requests
+	calcInterval = time.Second
+	// calcTimeout is how long a wallet is not requested again while its
+	// available balance does not arrive
+	calcTimeout = 30 * time.Second
+)
+
+// balanceCalcs collects the wallets whose available balance is requested.
+// A wallet is requested once until its balance arrives or calcTimeout
+// passed, and the wallets missing it are batched into at most one request per
+// calcInterval.
+type balanceCalcs struct {
+	mu        sync.Mutex
+	pending   map[wallet.CalcWallet]time.Time // requested wallets by time of request
+	queue     []wallet.CalcWallet
+	scheduled bool
+	last      time.Time
+}
+
+// CalcAvailableBalance makes the client request the calculation of the
+// available balance of every wallet received without it, so that wallet
+// updates carrying it follow the wallet snapshot and the updates after
+// trades. The missing wallets are batched into at most one request per
+// second, and a wallet is not requested again while its balance is pending.
+func (c *Client) CalcAvailableBalance(calc bool) *Client {
+	c.calcAvailable = calc
+	return c
+}
+
+// SubmitCalc requests the calculation of the available balance of the
+// wallets, which is sent as wallet updates. Emits an error if not
+// authenticated.
+func (c *Client) SubmitCalc(ctx context.Context, cr *wallet.CalcRequest) error {
+	socket, err := c.GetAuthenticatedSocket()
+	if err != nil {
+		return err
+	}
+	return c.sendBySocket(ctx, socket, cr)
+}
+
+// calcMissing queues the calc request of the wallets of a private event which
+// were received without their available balance. It is called by the reader
+// and leaves sending the request to sendCalcs.
+func (c *Client) calcMissing(ev interface{}) {
+	if !c.calcAvailable {
+		return
+	}
+	var wallets []*wallet.Wallet
+	switch e := ev.(type) {
+	case *wallet.Snapshot:
+		wallets = e.Snapshot
+	case *wallet.Update:
+		w := wallet.Wallet(*e)
+		wallets = []*wallet.Wallet{&w}
+	}
+	if len(wallets) == 0 {
+		return
+	}
+
+	now := c.clock.Now()
+	b := &c.calcs
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.pending == nil {
+		b.pending = make(map[wallet.CalcWallet]time.Time)
+	}
+	for _, w := range wallets {
+		cw := wallet.CalcWallet{Type: w.Type, Currency: w.Currency}
+		if w.HasBalanceAvailable {
+			delete(b.pending, cw)
+			continue
+		}
+		if at, ok := b.pending[cw]; ok && now.Sub(at) < calcTimeout {
+			continue
+		}
+		b.pending[cw] = now
+		b.queue = append(b.queue, cw)
+	}
+	if len(b.queue) == 0 || b.scheduled {
+		return
+	}
+	b.scheduled = true
+	go c.sendCalcs(b.last.Add(calcInterval).Sub(now))
+}
+
+// sendCalcs sends the queued calc request after delay
+func (c *Client) sendCalcs(delay time.Duration) {
+	if delay > 0 {
+		c.clock.Sleep(delay)
+	}
+	b := &c.calcs
+	b.mu.Lock()
+	cr := &wallet.CalcRequest{Wallets: b.queue}
+	b.queue = nil
+	b.scheduled = false
+	b.last = c.clock.Now()
+	b.mu.Unlock()
+
+	if err := c.SubmitCalc(context.Background(), cr); err != nil {
+		c.log.Warningf("could not request available balances: %s", err)
+	}
+}
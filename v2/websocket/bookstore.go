@@ -0,0 +1,206 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+)
+
+// BookStore persists order book snapshots between restarts
+type BookStore interface {
+	// LoadBook returns the stored snapshot of the symbol, or nil if there is none
+	LoadBook(symbol string) (*book.Snapshot, error)
+	SaveBook(symbol string, snapshot *book.Snapshot) error
+}
+
+// FileBookStore stores every book as a JSON file in Dir
+type FileBookStore struct {
+	Dir string
+}
+
+func (fs FileBookStore) path(symbol string) string {
+	return filepath.Join(fs.Dir, symbol+".json")
+}
+
+// LoadBook returns the stored snapshot, or nil if the file does not exist
+func (fs FileBookStore) LoadBook(symbol string) (*book.Snapshot, error) {
+	b, err := ioutil.ReadFile(fs.path(symbol))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &book.Snapshot{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SaveBook writes the snapshot to the file of the symbol
+func (fs FileBookStore) SaveBook(symbol string, snapshot *book.Snapshot) error {
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	tmp := fs.path(symbol) + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fs.path(symbol))
+}
+
+// Snapshot returns a copy of all levels of the book, bids first
+func (ob *Orderbook) Snapshot() *book.Snapshot {
+	ob.lock.RLock()
+	defer ob.lock.RUnlock()
+
+	s := &book.Snapshot{Snapshot: make([]*book.Book, 0, len(ob.bids)+len(ob.asks))}
+	for _, b := range ob.bids {
+		cpy := *b
+		s.Snapshot = append(s.Snapshot, &cpy)
+	}
+	for _, a := range ob.asks {
+		cpy := *a
+		s.Snapshot = append(s.Snapshot, &cpy)
+	}
+	return s
+}
+
+// FastForward replaces the levels covered by a newer, possibly shallower,
+// snapshot and keeps the levels beyond its deepest price on either side
+func (ob *Orderbook) FastForward(s *book.Snapshot) {
+	ob.lock.Lock()
+	defer ob.lock.Unlock()
+
+	var bids, asks []*book.Book
+	for _, b := range s.Snapshot {
+		if b.Side == common.Bid {
+			bids = append(bids, b)
+		} else {
+			asks = append(asks, b)
+		}
+	}
+
+	ob.bids = fastForwardSide(ob.bids, bids, func(a, b float64) bool { return a > b })
+	ob.asks = fastForwardSide(ob.asks, asks, func(a, b float64) bool { return a < b })
+
+	ob.bidVolume, ob.askVolume = 0, 0
+	for _, b := range ob.bids {
+		ob.bidVolume += b.Amount
+	}
+	for _, a := range ob.asks {
+		ob.askVolume += a.Amount
+	}
+}
+
+func fastForwardSide(old, latest []*book.Book, better func(a, b float64) bool) []*book.Book {
+	if len(latest) == 0 {
+		return old
+	}
+
+	worst := latest[0].Price
+	for _, l := range latest {
+		if better(worst, l.Price) {
+			worst = l.Price
+		}
+	}
+
+	side := append(make([]*book.Book, 0, len(old)+len(latest)), latest...)
+	for _, o := range old {
+		if better(worst, o.Price) {
+			side = append(side, o)
+		}
+	}
+	sort.SliceStable(side, func(i, j int) bool { return better(side[i].Price, side[j].Price) })
+	return side
+}
+
+// RestoreOrderbook loads the stored book of the symbol so it can be read with
+// GetOrderbook before the book subscription delivers its snapshot. If fetch is
+// given, the stored book is fast forwarded with its result, e.g. with the rest
+// client:
+//
+//	err := ws.RestoreOrderbook("tBTCUSD", store, func(symbol string) (*book.Snapshot, error) {
+//		return rc.Book.All(symbol, common.Precision0, 250)
+//	})
+//
+// The snapshot of the subscription replaces the restored book once received.
+// This requires ManageOrderbook=True. Returns false if nothing was stored.
+func (c *Client) RestoreOrderbook(symbol string, store BookStore, fetch func(symbol string) (*book.Snapshot, error)) (bool, error) {
+	stored, err := store.LoadBook(symbol)
+	if err != nil || stored == nil {
+		return false, err
+	}
+
+	ob := &Orderbook{symbol: symbol}
+	ob.SetWithSnapshot(stored)
+
+	if fetch != nil {
+		latest, err := fetch(symbol)
+		if err != nil {
+			return false, err
+		}
+		ob.FastForward(latest)
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	// book updates access the books with the factory lock only
+	if f, ok := c.factories[ChanBook].(*BookFactory); ok {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+	}
+	if _, ok := c.orderbooks[symbol]; !ok {
+		c.orderbooks[symbol] = ob
+	}
+	return true, nil
+}
+
+// PersistOrderbooks saves all managed books to the store every interval until
+// the context is done, then saves them a last time.
+func (c *Client) PersistOrderbooks(ctx context.Context, store BookStore, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := c.SaveOrderbooks(store); err != nil {
+				return err
+			}
+			return ctx.Err()
+		case <-ticker.C:
+			if err := c.SaveOrderbooks(store); err != nil {
+				c.log.Warningf("could not persist order books: %s", err)
+			}
+		}
+	}
+}
+
+// SaveOrderbooks saves all managed books to the store
+func (c *Client) SaveOrderbooks(store BookStore) error {
+	c.mtx.RLock()
+	books := make([]*Orderbook, 0, len(c.orderbooks))
+	for _, ob := range c.orderbooks {
+		books = append(books, ob)
+	}
+	c.mtx.RUnlock()
+
+	for _, ob := range books {
+		if err := store.SaveBook(ob.Symbol(), ob.Snapshot()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
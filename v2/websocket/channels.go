@@ -55,7 +55,8 @@ func (c *Client) handleChannel(socketId SocketId, msg []byte) error {
 		case string:
 			switch data {
 			case "hb":
-				// no-op, already updated heartbeat timeout from this event
+				// heartbeat timeout was already updated from this event
+				c.subscriptions.heartbeatReceived(chanID)
 				return nil
 			case "cs":
 				if checksum, ok := raw[2].(float64); ok {
@@ -71,7 +72,7 @@ func (c *Client) handleChannel(socketId SocketId, msg []byte) error {
 			return c.handlePublicChannel(sub, sub.Request.Channel, "", data, msg)
 		}
 	} else {
-		return c.handlePrivateChannel(raw)
+		return c.handlePrivateChannel(raw, msg)
 	}
 	return nil
 }
@@ -103,6 +104,7 @@ func (c *Client) handleChecksumChannel(sub *subscription, checksum int) error {
 				Event:   sub.Request.Event,
 				Channel: sub.Request.Channel,
 				Symbol:  sub.Request.Symbol,
+				route:   sub.Request.route,
 			}
 			_, err_sub := c.Subscribe(context.Background(), newSub)
 			if err_sub != nil {
@@ -131,7 +133,7 @@ func (c *Client) handlePublicChannel(sub *subscription, channel, objType string,
 					return err
 				}
 				if msg != nil {
-					c.listener <- msg
+					c.publish(sub.Request, msg)
 				}
 			} else {
 				// single item
@@ -140,18 +142,24 @@ func (c *Client) handlePublicChannel(sub *subscription, channel, objType string,
 					return err
 				}
 				if msg != nil {
-					c.listener <- msg
+					c.publish(sub.Request, msg)
 				}
 			}
 		}
 	} else {
-		// factory lookup error
-		return fmt.Errorf("could not find public factory for %s channel", channel)
+		// no factory for the channel, leave it to a registered decoder
+		msg, err := c.decodeUnknown(channel, raw_msg)
+		if err != nil {
+			return err
+		}
+		if msg != nil {
+			c.publish(sub.Request, msg)
+		}
 	}
 	return nil
 }
 
-func (c *Client) handlePrivateChannel(raw []interface{}) error {
+func (c *Client) handlePrivateChannel(raw []interface{}, msg []byte) error {
 	// authenticated data slice, or a heartbeat
 	if val, ok := raw[1].(string); ok && val == "hb" {
 		chanID, ok := raw[0].(float64)
@@ -165,13 +173,17 @@ func (c *Client) handlePrivateChannel(raw []interface{}) error {
 		// authenticated snapshots?
 		if len(raw) > 2 {
 			if arr, ok := raw[2].([]interface{}); ok {
-				obj, err := c.handlePrivateDataMessage(raw[1].(string), arr)
+				term := raw[1].(string)
+				obj, err := c.handlePrivateDataMessage(term, arr, msg)
 				if err != nil {
 					return err
 				}
 				// private data is returned as strongly typed data, publish directly
+				ev := c.generations.tag(term, obj)
 				if obj != nil {
-					c.listener <- obj
+					c.waiters.resolve(obj)
+					c.listener <- ev
+					c.calcMissing(obj)
 				}
 			}
 		}
@@ -181,6 +193,7 @@ func (c *Client) handlePrivateChannel(raw []interface{}) error {
 
 func (c *Client) handleHeartbeat(chanID int64) {
 	c.subscriptions.heartbeat(chanID)
+	c.subscriptions.heartbeatReceived(chanID)
 }
 
 type unsubscribeMsg struct {
@@ -192,7 +205,7 @@ type unsubscribeMsg struct {
 // hb (both): [ChanID, "hb"]
 // private update msg: [ChanID, "type", [Data]]
 // private snapshot msg: [ChanID, "type", [[Data]]]
-func (c *Client) handlePrivateDataMessage(term string, data []interface{}) (ms interface{}, err error) {
+func (c *Client) handlePrivateDataMessage(term string, data []interface{}, msg []byte) (ms interface{}, err error) {
 	if len(data) == 0 {
 		// empty data msg
 		return nil, nil
@@ -208,218 +221,219 @@ func (c *Client) handlePrivateDataMessage(term string, data []interface{}) (ms i
 			return ms, fmt.Errorf("expected data list in third position but got %#v in %#v", data[2], data)
 		}
 	*/
-	ms = c.convertRaw(term, data)
+	ms = c.convertRaw(term, data, msg)
 
 	return
 }
 
 // convertRaw takes a term and the raw data attached to it to try and convert that
-// untyped list into a proper type.
-func (c *Client) convertRaw(term string, raw []interface{}) interface{} {
+// untyped list into a proper type. Unrecognized terms are passed to
+// decodeUnknown along with the whole message.
+func (c *Client) convertRaw(term string, raw []interface{}, msg []byte) interface{} {
 	// The things you do to get proper types.
 	switch term {
 	case "bu":
-		o, err := balanceinfo.FromRaw(raw)
+		o, err := balanceinfo.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		bu := balanceinfo.Update(*o)
 		return &bu
 	case "ps":
-		o, err := position.SnapshotFromRaw(raw)
+		o, err := position.SnapshotFromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		return o
 	case "pn":
-		o, err := position.FromRaw(raw)
+		o, err := position.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		pn := position.New(*o)
 		return &pn
 	case "pu":
-		o, err := position.FromRaw(raw)
+		o, err := position.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		pu := position.Update(*o)
 		return &pu
 	case "pc":
-		o, err := position.FromRaw(raw)
+		o, err := position.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		pc := position.Cancel(*o)
 		return &pc
 	case "ws":
-		o, err := wallet.SnapshotFromRaw(raw)
+		o, err := wallet.SnapshotFromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		return o
 	case "wu":
-		o, err := wallet.FromRaw(raw)
+		o, err := wallet.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		wu := wallet.Update(*o)
 		return &wu
 	case "os":
-		o, err := order.SnapshotFromRaw(raw)
+		o, err := order.SnapshotFromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		return o
 	case "on":
-		o, err := order.FromRaw(raw)
+		o, err := order.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		on := order.New(*o)
 		return &on
 	case "ou":
-		o, err := order.FromRaw(raw)
+		o, err := order.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		ou := order.Update(*o)
 		return &ou
 	case "oc":
-		o, err := order.FromRaw(raw)
+		o, err := order.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		oc := order.Cancel(*o)
 		return &oc
 	case "hts":
-		tu, err := tradeexecutionupdate.SnapshotFromRaw(raw)
+		tu, err := tradeexecutionupdate.SnapshotFromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		hts := tradeexecutionupdate.HistoricalTradeSnapshot(*tu)
 		return &hts
 	case "te":
-		o, err := tradeexecution.FromRaw(raw)
+		o, err := tradeexecution.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		return o
 	case "tu":
-		tu, err := tradeexecutionupdate.FromRaw(raw)
+		tu, err := tradeexecutionupdate.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		return tu
 	case "fte":
-		o, err := fundingtrade.FromRaw(raw)
+		o, err := fundingtrade.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		fte := fundingtrade.Execution(*o)
 		return &fte
 	case "ftu":
-		o, err := fundingtrade.FromRaw(raw)
+		o, err := fundingtrade.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		ftu := fundingtrade.Update(*o)
 		return &ftu
 	case "hfts":
-		fts, err := fundingtrade.SnapshotFromRaw(raw)
+		fts, err := fundingtrade.SnapshotFromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		nfts := fundingtrade.HistoricalSnapshot(*fts)
 		return &nfts
 	case "n":
-		o, err := notification.FromRaw(raw)
+		o, err := notification.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		return o
 	case "fos":
-		o, err := fundingoffer.SnapshotFromRaw(raw)
+		o, err := fundingoffer.SnapshotFromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		return o
 	case "fon":
-		o, err := fundingoffer.FromRaw(raw)
+		o, err := fundingoffer.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		fon := fundingoffer.New(*o)
 		return &fon
 	case "fou":
-		o, err := fundingoffer.FromRaw(raw)
+		o, err := fundingoffer.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		fou := fundingoffer.Update(*o)
 		return &fou
 	case "foc":
-		o, err := fundingoffer.FromRaw(raw)
+		o, err := fundingoffer.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		foc := fundingoffer.Cancel(*o)
 		return &foc
 	case "fiu":
-		o, err := fundinginfo.FromRaw(raw)
+		o, err := fundinginfo.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		return o
 	case "fcs":
-		o, err := fundingcredit.SnapshotFromRaw(raw)
+		o, err := fundingcredit.SnapshotFromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		return o
 	case "fcn":
-		o, err := fundingcredit.FromRaw(raw)
+		o, err := fundingcredit.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		fcn := fundingcredit.New(*o)
 		return &fcn
 	case "fcu":
-		o, err := fundingcredit.FromRaw(raw)
+		o, err := fundingcredit.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		fcu := fundingcredit.Update(*o)
 		return &fcu
 	case "fcc":
-		o, err := fundingcredit.FromRaw(raw)
+		o, err := fundingcredit.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		fcc := fundingcredit.Cancel(*o)
 		return &fcc
 	case "fls":
-		o, err := fundingloan.SnapshotFromRaw(raw)
+		o, err := fundingloan.SnapshotFromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		return o
 	case "fln":
-		o, err := fundingloan.FromRaw(raw)
+		o, err := fundingloan.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		fln := fundingloan.New(*o)
 		return &fln
 	case "flu":
-		o, err := fundingloan.FromRaw(raw)
+		o, err := fundingloan.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
 		flu := fundingloan.Update(*o)
 		return &flu
 	case "flc":
-		o, err := fundingloan.FromRaw(raw)
+		o, err := fundingloan.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
@@ -440,7 +454,7 @@ func (c *Client) convertRaw(term string, raw []interface{}) interface{} {
 	case "mis": // Should not be sent anymore as of 2017-04-01
 		return nil
 	case "miu":
-		o, err := margin.FromRaw(raw)
+		o, err := margin.FromRaw(raw, c.decode.options()...)
 		if err != nil {
 			return err
 		}
@@ -454,8 +468,11 @@ func (c *Client) convertRaw(term string, raw []interface{}) interface{} {
 		}
 		return o // better than nothing
 	default:
-		c.log.Warningf("unhandled channel data, term: %s", term)
+		c.log.Debugf("unhandled channel data, term: %s", term)
+		o, err := c.decodeUnknown(term, msg)
+		if err != nil {
+			return err
+		}
+		return o
 	}
-
-	return fmt.Errorf("term %q not recognized", term)
 }
@@ -12,12 +12,10 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/op/go-logging"
 
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/auth"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
-
-	"crypto/hmac"
-	"crypto/sha512"
-	"encoding/hex"
 )
 
 var productionBaseURL = "wss://api-pub.bitfinex.com/ws/2"
@@ -77,8 +75,23 @@ type Socket struct {
 	IsConnected        bool
 	ResetSubscriptions []*subscription
 	IsAuthenticated    bool
+	// IsAuthEndpoint is set for the socket connected to Parameters.AuthURL,
+	// which carries the authenticated channel only
+	IsAuthEndpoint bool
+}
+
+// SendInfo describes a message written to a socket and is handed to send
+// hooks, e.g. to feed metrics or tracing systems.
+type SendInfo struct {
+	RequestID string
+	SocketId  SocketId
+	Msg       interface{}
+	Err       error
 }
 
+// SendHook is called once for every message sent by the client.
+type SendHook func(SendInfo)
+
 // AsynchronousFactory provides an interface to re-create asynchronous transports during reconnect events.
 type AsynchronousFactory interface {
 	Create() Asynchronous
@@ -87,35 +100,50 @@ type AsynchronousFactory interface {
 // WebsocketAsynchronousFactory creates a websocket-based asynchronous transport.
 type WebsocketAsynchronousFactory struct {
 	parameters *Parameters
+	endpoints  *endpointPool
 }
 
-// NewWebsocketAsynchronousFactory creates a new websocket factory with a given URL.
+// NewWebsocketAsynchronousFactory creates a new websocket factory with a given URL
+// and the fallback URLs of the parameters.
 func NewWebsocketAsynchronousFactory(parameters *Parameters) AsynchronousFactory {
 	return &WebsocketAsynchronousFactory{
 		parameters: parameters,
+		endpoints:  newEndpointPool(append([]string{parameters.URL}, parameters.FallbackURLs...)...),
 	}
 }
 
 // Create returns a new websocket transport.
 func (w *WebsocketAsynchronousFactory) Create() Asynchronous {
-	return newWs(w.parameters.URL, w.parameters.LogTransport, w.parameters.Logger)
+	ws := newWs(w.parameters.URL, w.parameters.LogTransport, w.parameters.Logger)
+	ws.endpoints = w.endpoints
+	if w.parameters.Proxy != nil {
+		ws.proxy = w.parameters.Proxy
+	}
+	return ws
 }
 
 // Client provides a unified interface for users to interact with the Bitfinex V2 Websocket API.
 // nolint:megacheck,structcheck
 type Client struct {
-	asyncFactory AsynchronousFactory // for re-creating transport during reconnects
+	asyncFactory     AsynchronousFactory // for re-creating transport during reconnects
+	authAsyncFactory AsynchronousFactory // for the authenticated socket if it has a separate endpoint
 
-	timeout            int64 // read timeout
-	apiKey             string
-	apiSecret          string
+	timeout            int64                    // read timeout
+	credentials        auth.CredentialsProvider // guarded by credMtx
+	credMtx            sync.RWMutex
 	cancelOnDisconnect bool
+	authFilter         []string
+	calcAvailable      bool
+	calcs              balanceCalcs
 	Authentication     AuthState
 	sockets            map[SocketId]*Socket
 	nonce              utils.NonceGenerator
 	terminal           bool
 	init               bool
 	log                *logging.Logger
+	onSend             SendHook
+	clock              utils.Clock
+	decode             *decoding
 
 	// connection & operational behavior
 	parameters *Parameters
@@ -123,6 +151,7 @@ type Client struct {
 	// subscription manager
 	subscriptions *subscriptions
 	factories     map[string]messageFactory
+	decoders      map[string]Decoder
 	orderbooks    map[string]*Orderbook
 
 	// close signal sent to user on shutdown
@@ -131,6 +160,26 @@ type Client struct {
 	// downstream listener channel to deliver API objects
 	listener chan interface{}
 
+	// reconnect attempts per socket, see Backoff.ResetAfter
+	reconnects map[SocketId]*reconnectState
+
+	// pending credentials rotation, see RotateCredentials
+	rotating *KeyRotatedEvent
+
+	// pings waiting for their pong
+	pings pings
+	// requests waiting for the response of the exchange
+	waiters waiters
+	// authentications and the snapshots pending since the last one
+	generations generations
+
+	// holds back writes during maintenance, see Parameters.Maintenance
+	maintenance utils.MaintenanceGate
+
+	// typed consumers of single subscriptions
+	routes   map[*route]bool
+	routeMtx sync.Mutex
+
 	// race management
 	mtx       *sync.RWMutex
 	waitGroup sync.WaitGroup
@@ -138,24 +187,129 @@ type Client struct {
 
 // Credentials assigns authentication credentials to a connection request.
 func (c *Client) Credentials(key string, secret string) *Client {
-	c.apiKey = key
-	c.apiSecret = secret
+	var p auth.CredentialsProvider
+	if key != "" || secret != "" {
+		p = auth.StaticCredentials(key, secret)
+	}
+	return c.CredentialsProvider(p)
+}
+
+// CredentialsProvider sets the provider of the credentials, which is asked on
+// every (re-)authentication of the session. Rotated keys are thus picked up on
+// the next reconnect without restarting the client, use RotateCredentials to
+// re-authenticate right away.
+func (c *Client) CredentialsProvider(p auth.CredentialsProvider) *Client {
+	c.credMtx.Lock()
+	defer c.credMtx.Unlock()
+	c.credentials = p
+	return c
+}
+
+// credentialsProvider returns the provider set last, nil without credentials
+func (c *Client) credentialsProvider() auth.CredentialsProvider {
+	c.credMtx.RLock()
+	defer c.credMtx.RUnlock()
+	return c.credentials
+}
+
+// RotateCredentials replaces the credentials of the session at runtime, e.g.
+// for scheduled key rotation. An authenticated socket is reconnected right
+// away to authenticate with the new credentials and resubscribe its
+// channels, so consumers of Listen keep receiving events. A KeyRotatedEvent
+// is emitted once the platform accepted the new credentials. Without an
+// authenticated socket the credentials are used on the next authentication.
+func (c *Client) RotateCredentials(ctx context.Context, p auth.CredentialsProvider) error {
+	creds, err := p.Credentials(ctx)
+	if err != nil {
+		return err
+	}
+	c.CredentialsProvider(p)
+
+	socket, err := c.GetAuthenticatedSocket()
+	if err != nil {
+		return nil
+	}
+	c.mtx.Lock()
+	c.rotating = &KeyRotatedEvent{APIKey: creds.Key}
+	socket.IsConnected = false
+	c.mtx.Unlock()
+
+	c.log.Infof("socket (id=%d) re-authenticating with rotated credentials", socket.Id)
+	c.closeAsyncAndWait(socket, c.parameters.ShutdownTimeout)
+	return c.reconnectSocket(socket)
+}
+
+// rotated returns the event of a pending credentials rotation once the
+// authentication of the socket completed
+func (c *Client) rotated(socketId SocketId, a *AuthEvent) interface{} {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	ev := c.rotating
+	c.rotating = nil
+	if ev == nil || a.Status != "OK" {
+		return nil
+	}
+	ev.SocketId, ev.UserID = socketId, a.UserID
+	return ev
+}
+
+// AuthAsyncFactory sets the transport factory of the authenticated socket,
+// which then connects separately from the public sockets. This is done
+// automatically if Parameters.AuthURL is set.
+func (c *Client) AuthAsyncFactory(async AsynchronousFactory) *Client {
+	c.authAsyncFactory = async
 	return c
 }
 
 // CancelOnDisconnect ensures all orders will be canceled if this API session is disconnected.
+// The dead man's switch is armed again whenever the socket is authenticated
+// after a reconnect, which is confirmed by a CancelOnDisconnectArmedEvent.
 func (c *Client) CancelOnDisconnect(cxl bool) *Client {
 	c.cancelOnDisconnect = cxl
 	return c
 }
 
-func (c *Client) sign(msg string) (string, error) {
-	sig := hmac.New(sha512.New384, []byte(c.apiSecret))
-	_, err := sig.Write([]byte(msg))
+// AuthFilter limits the authenticated channel to the given message groups,
+// e.g. "trading" or "wallet". The filter is sent with every authentication,
+// including those after a reconnect.
+func (c *Client) AuthFilter(filter ...string) *Client {
+	c.authFilter = filter
+	return c
+}
+
+// StrictDecoding turns messages of this client holding a field of unexpected
+// type into a *convert.DecodeError, where they would otherwise be delivered
+// with the field zero-filled. onAnomaly, if set, is called for each mismatch,
+// fatal or not. Only this client is affected.
+func (c *Client) StrictDecoding(onAnomaly func(convert.Anomaly)) *Client {
+	c.decode.set(convert.DecodeOptions{Strict: true, OnAnomaly: onAnomaly})
+	return c
+}
+
+// OnSend registers a hook that is called for every message sent to the API,
+// together with the request ID it was sent under.
+func (c *Client) OnSend(hook SendHook) *Client {
+	c.onSend = hook
+	return c
+}
+
+// sendBySocket writes msg to the given socket. The request ID is taken from ctx
+// (see utils.WithRequestID) or generated, and is attached to logs, errors and hooks.
+func (c *Client) sendBySocket(ctx context.Context, socket *Socket, msg interface{}) error {
+	id, ok := utils.RequestIDFromContext(ctx)
+	if !ok {
+		id = utils.NewRequestID()
+	}
+	c.log.Debugf("socket (id=%d) request %s: sending %T", socket.Id, id, msg)
+	err := socket.Asynchronous.Send(ctx, msg)
+	if c.onSend != nil {
+		c.onSend(SendInfo{RequestID: id, SocketId: socket.Id, Msg: msg, Err: err})
+	}
 	if err != nil {
-		return "", err
+		c.log.Warningf("socket (id=%d) request %s failed: %s", socket.Id, id, err)
+		return fmt.Errorf("request %s: %w", id, err)
 	}
-	return hex.EncodeToString(sig.Sum(nil)), nil
+	return nil
 }
 
 func (c *Client) registerFactory(channel string, factory messageFactory) {
@@ -194,11 +348,16 @@ func NewWithParamsAsyncFactory(params *Parameters, async AsynchronousFactory) *C
 
 // NewWithParamsAsyncFactoryNonce creates a new client with a given set of parameters, asynchronous transport factory, and nonce generator interfaces.
 func NewWithParamsAsyncFactoryNonce(params *Parameters, async AsynchronousFactory, nonce utils.NonceGenerator) *Client {
+	clock := params.Clock
+	if clock == nil {
+		clock = utils.SystemClock
+	}
 	c := &Client{
 		asyncFactory:   async,
 		Authentication: NoAuthentication,
 		factories:      make(map[string]messageFactory),
-		subscriptions:  newSubscriptions(params.HeartbeatTimeout, params.Logger),
+		decoders:       make(map[string]Decoder),
+		subscriptions:  newSubscriptions(params.HeartbeatTimeout, params.Logger, clock),
 		orderbooks:     make(map[string]*Orderbook),
 		nonce:          nonce,
 		parameters:     params,
@@ -208,6 +367,16 @@ func NewWithParamsAsyncFactoryNonce(params *Parameters, async AsynchronousFactor
 		sockets:        make(map[SocketId]*Socket),
 		mtx:            &sync.RWMutex{},
 		log:            params.Logger,
+		clock:          clock,
+		decode:         &decoding{},
+	}
+	c.maintenance.Mode = params.Maintenance
+	c.generations.wrap = params.TagPreSnapshot
+	if params.AuthURL != "" {
+		authParams := *params
+		authParams.URL = params.AuthURL
+		authParams.FallbackURLs = nil
+		c.authAsyncFactory = NewWebsocketAsynchronousFactory(&authParams)
 	}
 	c.registerPublicFactories()
 	return c
@@ -218,7 +387,13 @@ func (c *Client) Connect() error {
 	c.dumpParams()
 	c.terminal = false
 	go c.listenDisconnect()
-	return c.connectSocket(SocketId(len(c.sockets)))
+	if err := c.connectSocketTo(SocketId(len(c.sockets)), false); err != nil {
+		return err
+	}
+	if c.authAsyncFactory != nil && c.hasCredentials() {
+		return c.connectSocketTo(SocketId(len(c.sockets)), true)
+	}
+	return nil
 }
 
 // Returns true if the underlying asynchronous transport is connected to an endpoint.
@@ -245,21 +420,21 @@ func (c *Client) Listen() <-chan interface{} {
 func (c *Client) Close() {
 	c.terminal = true
 	var wg sync.WaitGroup
-	socketCount := len(c.sockets)
-	if socketCount > 0 {
-		for _, socket := range c.sockets {
-			if socket.IsConnected {
-				wg.Add(1)
-				socket.IsConnected = false
-				go func(s *Socket) {
-					c.closeAsyncAndWait(s, c.parameters.ShutdownTimeout)
-					wg.Done()
-				}(socket)
-			}
+	c.mtx.Lock()
+	for _, socket := range c.sockets {
+		if socket.IsConnected {
+			wg.Add(1)
+			socket.IsConnected = false
+			go func(s *Socket) {
+				c.closeAsyncAndWait(s, c.parameters.ShutdownTimeout)
+				wg.Done()
+			}(socket)
 		}
-		wg.Wait()
 	}
+	c.mtx.Unlock()
+	wg.Wait()
 	c.subscriptions.Close()
+	c.closeRoutes()
 	close(c.listener)
 }
 
@@ -288,6 +463,7 @@ func (c *Client) listenDisconnect() {
 					// reconnect to the socket
 					go func() {
 						c.closeAsyncAndWait(socket, c.parameters.ShutdownTimeout)
+						c.emit(&DisconnectedEvent{SocketId: socket.Id, Err: hbErr.Error})
 						err := c.reconnect(socket, hbErr.Error)
 						if err != nil {
 							c.log.Warningf("socket disconnect: %s", err.Error())
@@ -317,11 +493,11 @@ func extractSymbolResolutionFromKey(subscription string) (symbol string, resolut
 }
 
 func (c *Client) registerPublicFactories() {
-	c.registerFactory(ChanTicker, newTickerFactory(c.subscriptions))
-	c.registerFactory(ChanTrades, newTradeFactory(c.subscriptions))
-	c.registerFactory(ChanBook, newBookFactory(c.subscriptions, c.orderbooks, c.parameters.ManageOrderbook))
-	c.registerFactory(ChanCandles, newCandlesFactory(c.subscriptions))
-	c.registerFactory(ChanStatus, newStatsFactory(c.subscriptions))
+	c.registerFactory(ChanTicker, newTickerFactory(c.subscriptions, c.decode))
+	c.registerFactory(ChanTrades, newTradeFactory(c.subscriptions, c.decode))
+	c.registerFactory(ChanBook, newBookFactory(c.subscriptions, c.decode, c.orderbooks, c.parameters.ManageOrderbook))
+	c.registerFactory(ChanCandles, newCandlesFactory(c.subscriptions, c.decode))
+	c.registerFactory(ChanStatus, newStatsFactory(c.subscriptions, c.decode))
 }
 
 func (c *Client) reconnect(socket *Socket, err error) error {
@@ -331,29 +507,79 @@ func (c *Client) reconnect(socket *Socket, err error) error {
 		c.mtx.RUnlock()
 		return err
 	}
-	if !c.parameters.AutoReconnect {
-		err := fmt.Errorf("AutoReconnect setting is disabled, do not reconnect: %s", err.Error())
+	policy := c.parameters.reconnectPolicy(socket.IsAuthEndpoint)
+	if !policy.AutoReconnect {
+		err := fmt.Errorf("AutoReconnect setting is disabled, do not reconnect: %w", err)
 		c.mtx.RUnlock()
 		return err
 	}
 	c.mtx.RUnlock()
-	reconnectTry := 0
-	for ; reconnectTry < c.parameters.ReconnectAttempts; reconnectTry++ {
-		c.log.Debugf("socket (id=%d) waiting %s until reconnect...", socket.Id, c.parameters.ReconnectInterval)
-		time.Sleep(c.parameters.ReconnectInterval)
-		c.log.Infof("socket (id=%d) reconnect attempt %d/%d", socket.Id, reconnectTry+1, c.parameters.ReconnectAttempts)
-		if err := c.reconnectSocket(socket); err == nil {
+	backoff := policy.backoff()
+	c.mtx.Lock()
+	state := c.reconnectState(socket.Id)
+	c.mtx.Unlock()
+
+	disconnected := c.clock.Now()
+	if c.clock.Now().Sub(state.connectedAt) >= backoff.ResetAfter() {
+		state.attempt = 0
+	}
+	for {
+		attempt := state.attempt + 1
+		delay, ok := backoff.NextDelay(attempt)
+		if !ok {
+			break
+		}
+		state.attempt = attempt
+		c.log.Debugf("socket (id=%d) waiting %s until reconnect...", socket.Id, delay)
+		c.clock.Sleep(delay)
+		c.log.Infof("socket (id=%d) reconnect attempt %d", socket.Id, attempt)
+		ev := &ReconnectEvent{SocketId: socket.Id, Attempt: attempt, Delay: delay}
+		ev.Err = c.reconnectSocket(socket)
+		ev.Downtime = c.clock.Now().Sub(disconnected)
+		if ev.Err == nil {
 			c.log.Debugf("reconnect OK")
+			state.connectedAt = c.clock.Now()
+			c.emit(ev)
 			return nil
 		}
-		c.log.Warningf("socket (id=%d) reconnect failed: %s", socket.Id, err.Error())
-	}
+		c.log.Warningf("socket (id=%d) reconnect failed: %s", socket.Id, ev.Err.Error())
+		c.emit(ev)
+	}
+	c.emit(&ReconnectEvent{
+		SocketId: socket.Id,
+		Attempt:  state.attempt,
+		Downtime: c.clock.Now().Sub(disconnected),
+		Err:      err,
+		GaveUp:   true,
+	})
 	if err != nil {
 		c.log.Errorf("socket (id=%d) could not reconnect: %s", socket.Id, err.Error())
 	}
 	return err
 }
 
+// reconnectState returns the reconnect state of a socket id, c.mtx must be
+// held
+func (c *Client) reconnectState(id SocketId) *reconnectState {
+	if c.reconnects == nil {
+		c.reconnects = make(map[SocketId]*reconnectState)
+	}
+	state, ok := c.reconnects[id]
+	if !ok {
+		state = &reconnectState{}
+		c.reconnects[id] = state
+	}
+	return state
+}
+
+// emit publishes a client generated event on the Listen channel
+func (c *Client) emit(ev interface{}) {
+	if c.terminal {
+		return
+	}
+	c.listener <- ev
+}
+
 func (c *Client) dumpParams() {
 	c.log.Debug("----Bitfinex Client Parameters----")
 	c.log.Debugf("AutoReconnect=%t", c.parameters.AutoReconnect)
@@ -364,11 +590,25 @@ func (c *Client) dumpParams() {
 	c.log.Debugf("ResubscribeOnReconnect=%t", c.parameters.ResubscribeOnReconnect)
 	c.log.Debugf("HeartbeatTimeout=%s", c.parameters.HeartbeatTimeout)
 	c.log.Debugf("URL=%s", c.parameters.URL)
+	c.log.Debugf("FallbackURLs=%v", c.parameters.FallbackURLs)
+	c.log.Debugf("AuthURL=%s", c.parameters.AuthURL)
 	c.log.Debugf("ManageOrderbook=%t", c.parameters.ManageOrderbook)
 }
 
 func (c *Client) connectSocket(socketId SocketId) error {
-	async := c.asyncFactory.Create()
+	authEndpoint := false
+	if oldSocket, _ := c.socketById(socketId); oldSocket != nil {
+		authEndpoint = oldSocket.IsAuthEndpoint
+	}
+	return c.connectSocketTo(socketId, authEndpoint)
+}
+
+func (c *Client) connectSocketTo(socketId SocketId, authEndpoint bool) error {
+	factory := c.asyncFactory
+	if authEndpoint {
+		factory = c.authAsyncFactory
+	}
+	async := factory.Create()
 	// create new socket instance
 	socket := &Socket{
 		Id:                 socketId,
@@ -376,6 +616,7 @@ func (c *Client) connectSocket(socketId SocketId) error {
 		IsConnected:        false,
 		ResetSubscriptions: nil,
 		IsAuthenticated:    false,
+		IsAuthEndpoint:     authEndpoint,
 	}
 	oldSocket, _ := c.socketById(socketId)
 	if oldSocket != nil {
@@ -393,7 +634,9 @@ func (c *Client) connectSocket(socketId SocketId) error {
 		// unable to establish connection
 		return err
 	}
+	c.mtx.Lock()
 	socket.IsConnected = true
+	c.mtx.Unlock()
 	go c.listenUpstream(socket)
 	return nil
 }
@@ -417,6 +660,14 @@ func (c *Client) listenUpstream(socket *Socket) {
 	for {
 		select {
 		case err := <-socket.Asynchronous.Done():
+			// sockets closed by the client are marked disconnected first
+			c.mtx.Lock()
+			connected := socket.IsConnected
+			socket.IsConnected = false
+			c.mtx.Unlock()
+			if connected {
+				c.emit(&DisconnectedEvent{SocketId: socket.Id, Err: err})
+			}
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway) {
 				err := c.reconnect(socket, err)
 				if err != nil {
@@ -476,7 +727,7 @@ func (c *Client) sendUnsubscribeMessage(ctx context.Context, sub *subscription)
 	if err != nil {
 		return err
 	}
-	return socket.Asynchronous.Send(ctx, unsubscribeMsg{Event: "unsubscribe", ChanID: sub.ChanID})
+	return c.sendBySocket(ctx, socket, unsubscribeMsg{Event: "unsubscribe", ChanID: sub.ChanID})
 }
 
 func (c *Client) checkResubscription(socketId SocketId) {
@@ -512,6 +763,18 @@ func (c *Client) checkResubscription(socketId SocketId) {
 
 // called when an info event is received
 func (c *Client) handleOpen(socketId SocketId) error {
+	// with a separate auth endpoint only its socket authenticates
+	if c.authAsyncFactory != nil {
+		socket, err := c.socketById(socketId)
+		if err != nil {
+			return err
+		}
+		if socket.IsAuthEndpoint {
+			return c.authenticate(context.Background(), socketId)
+		}
+		c.checkResubscription(socketId)
+		return nil
+	}
 	authSocket, _ := c.GetAuthenticatedSocket()
 	// if we have auth credentials and there is currently no authenticated
 	// sockets (we are only allowed one)
@@ -555,27 +818,39 @@ func (c *Client) handleAuthAck(socketId SocketId, auth *AuthEvent) {
 }
 
 func (c *Client) hasCredentials() bool {
-	return c.apiKey != "" && c.apiSecret != ""
+	return c.credentialsProvider() != nil
 }
 
 // Authenticate creates the payload for the authentication request and sends it
-// to the API. The filters will be applied to the authenticated channel, i.e.
-// only subscribe to the filtered messages.
-func (c *Client) authenticate(ctx context.Context, socketId SocketId, filter ...string) error {
-	nonce := c.nonce.GetNonce()
-	payload := "AUTH" + nonce
-	sig, err := c.sign(payload)
+// to the API. The filters set with AuthFilter will be applied to the
+// authenticated channel, i.e. only subscribe to the filtered messages.
+func (c *Client) authenticate(ctx context.Context, socketId SocketId) error {
+	p := c.credentialsProvider()
+	if p == nil {
+		return fmt.Errorf("authenticating socket (id=%d): %w: no credentials", socketId, common.ErrUnauthorized)
+	}
+	creds, err := p.Credentials(ctx)
 	if err != nil {
 		return err
 	}
+	nonce := c.nonce.GetNonce()
 	s := &SubscriptionRequest{
-		Event:       "auth",
-		APIKey:      c.apiKey,
-		AuthSig:     sig,
-		AuthPayload: payload,
-		AuthNonce:   nonce,
-		Filter:      filter,
-		SubID:       nonce,
+		Event:  "auth",
+		Filter: c.authFilter,
+		SubID:  nonce,
+	}
+	if creds.Token != "" {
+		s.AuthToken = creds.Token
+	} else {
+		payload := "AUTH" + nonce
+		sig, err := creds.Sign(payload)
+		if err != nil {
+			return err
+		}
+		s.APIKey = creds.Key
+		s.AuthSig = sig
+		s.AuthPayload = payload
+		s.AuthNonce = nonce
 	}
 	if c.cancelOnDisconnect {
 		s.DMS = DMSCancelOnDisconnect
@@ -585,7 +860,7 @@ func (c *Client) authenticate(ctx context.Context, socketId SocketId, filter ...
 	if err != nil {
 		return err
 	}
-	if err = socket.Asynchronous.Send(ctx, s); err != nil {
+	if err = c.sendBySocket(ctx, socket, s); err != nil {
 		return err
 	}
 	c.Authentication = PendingAuthentication
@@ -595,7 +870,7 @@ func (c *Client) authenticate(ctx context.Context, socketId SocketId, filter ...
 // get a random socket
 func (c *Client) getSocket() (*Socket, error) {
 	if len(c.sockets) <= 0 {
-		return nil, fmt.Errorf("no socket found")
+		return nil, fmt.Errorf("no socket found: %w", ErrWSNotConnected)
 	}
 	return c.sockets[0], nil
 }
@@ -604,6 +879,9 @@ func (c *Client) getMostAvailableSocket() (*Socket, error) {
 	var retSocket *Socket
 	bestCapacity := 0
 	for _, socket := range c.sockets {
+		if socket.IsAuthEndpoint {
+			continue
+		}
 		capac := c.getAvailableSocketCapacity(socket.Id)
 		if retSocket == nil {
 			retSocket = socket
@@ -616,7 +894,7 @@ func (c *Client) getMostAvailableSocket() (*Socket, error) {
 		}
 	}
 	if retSocket == nil {
-		return nil, fmt.Errorf("no socket found")
+		return nil, fmt.Errorf("no socket found: %w", ErrWSNotConnected)
 	}
 	return retSocket, nil
 }
@@ -628,16 +906,18 @@ func (c *Client) socketById(socketId SocketId) (*Socket, error) {
 	if socket, ok := c.sockets[socketId]; ok {
 		return socket, nil
 	}
-	return nil, fmt.Errorf("could not find socket with ID %d", socketId)
+	return nil, fmt.Errorf("could not find socket with ID %d: %w", socketId, common.ErrNotFound)
 }
 
 // calculates how many free channels are available across all of the sockets
 func (c *Client) getTotalAvailableSocketCapacity() int {
 	freeCapacity := 0
 	c.mtx.RLock()
-	ids := make([]SocketId, len(c.sockets))
-	for i, socket := range c.sockets {
-		ids[i] = socket.Id
+	ids := make([]SocketId, 0, len(c.sockets))
+	for _, socket := range c.sockets {
+		if !socket.IsAuthEndpoint {
+			ids = append(ids, socket.Id)
+		}
 	}
 	c.mtx.RUnlock()
 	for _, id := range ids {
@@ -667,5 +947,5 @@ func (c *Client) GetAuthenticatedSocket() (*Socket, error) {
 			return socket, nil
 		}
 	}
-	return nil, fmt.Errorf("no authenticated socket found")
+	return nil, fmt.Errorf("no authenticated socket found: %w", common.ErrUnauthorized)
 }
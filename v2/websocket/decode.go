@@ -0,0 +1,42 @@
+package websocket
+
+import "encoding/json"
+
+// UnknownEvent is emitted on the Listen channel for a message the client
+// has no decoder for, rather than dropping it. Name is the event field of an
+// event message, the term of an authenticated channel message, e.g. "uac",
+// or the channel of a public subscription. Raw is the whole message.
+type UnknownEvent struct {
+	Name string
+	Raw  json.RawMessage
+}
+
+// Decoder converts a message into the value emitted on the Listen channel.
+// It receives the whole message as sent by the websocket api.
+type Decoder func(raw json.RawMessage) (interface{}, error)
+
+// RegisterDecoder decodes messages which the client does not recognize with
+// d instead of emitting them as UnknownEvent, see UnknownEvent.Name for the
+// names of messages. Messages the client recognizes are not passed to
+// decoders.
+func (c *Client) RegisterDecoder(name string, d Decoder) *Client {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.decoders[name] = d
+	return c
+}
+
+// decodeUnknown decodes an unrecognized message of the given name with the
+// registered decoder, or wraps it in an UnknownEvent
+func (c *Client) decodeUnknown(name string, msg []byte) (interface{}, error) {
+	raw := make(json.RawMessage, len(msg))
+	copy(raw, msg)
+
+	c.mtx.RLock()
+	d, ok := c.decoders[name]
+	c.mtx.RUnlock()
+	if ok {
+		return d(raw)
+	}
+	return &UnknownEvent{Name: name, Raw: raw}, nil
+}
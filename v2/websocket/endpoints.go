@@ -0,0 +1,115 @@
+package websocket
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// EndpointHealth describes the connection history of a websocket endpoint
+type EndpointHealth struct {
+	URL                 string
+	Successes           int
+	Failures            int
+	ConsecutiveFailures int
+	LastFailure         time.Time
+	// LastLatency is the duration of the last successful handshake
+	LastLatency time.Duration
+}
+
+// endpointPool orders the configured endpoints by health. Endpoints are tried
+// by fewest consecutive failures first and by configuration order on ties, so
+// the primary endpoint is preferred again once the fallback fails as well.
+type endpointPool struct {
+	mu        sync.Mutex
+	endpoints []*EndpointHealth
+}
+
+func newEndpointPool(urls ...string) *endpointPool {
+	p := &endpointPool{}
+	seen := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		p.endpoints = append(p.endpoints, &EndpointHealth{URL: u})
+	}
+	return p
+}
+
+func (p *endpointPool) ordered() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	eps := make([]*EndpointHealth, len(p.endpoints))
+	copy(eps, p.endpoints)
+	sort.SliceStable(eps, func(i, j int) bool {
+		return eps[i].ConsecutiveFailures < eps[j].ConsecutiveFailures
+	})
+
+	urls := make([]string, len(eps))
+	for i, e := range eps {
+		urls[i] = e.URL
+	}
+	return urls
+}
+
+func (p *endpointPool) get(url string) *EndpointHealth {
+	for _, e := range p.endpoints {
+		if e.URL == url {
+			return e
+		}
+	}
+	return nil
+}
+
+func (p *endpointPool) success(url string, latency time.Duration) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if e := p.get(url); e != nil {
+		e.Successes++
+		e.ConsecutiveFailures = 0
+		e.LastLatency = latency
+	}
+}
+
+func (p *endpointPool) failure(url string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if e := p.get(url); e != nil {
+		e.Failures++
+		e.ConsecutiveFailures++
+		e.LastFailure = time.Now()
+	}
+}
+
+func (p *endpointPool) health() []EndpointHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]EndpointHealth, len(p.endpoints))
+	for i, e := range p.endpoints {
+		out[i] = *e
+	}
+	return out
+}
+
+// Health returns the connection history of every configured endpoint
+func (w *WebsocketAsynchronousFactory) Health() []EndpointHealth {
+	return w.endpoints.health()
+}
+
+// EndpointHealth returns the connection history of the configured endpoints,
+// or nil if the transport factory doesn't track it
+func (c *Client) EndpointHealth() []EndpointHealth {
+	if h, ok := c.asyncFactory.(interface{ Health() []EndpointHealth }); ok {
+		return h.Health()
+	}
+	return nil
+}
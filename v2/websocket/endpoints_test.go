@@ -0,0 +1,73 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointPoolOrder(t *testing.T) {
+	p := newEndpointPool("wss://a", "wss://b", "wss://a", "")
+	assert.Equal(t, []string{"wss://a", "wss://b"}, p.ordered())
+
+	p.failure("wss://a")
+	assert.Equal(t, []string{"wss://b", "wss://a"}, p.ordered())
+
+	// primary is preferred again once the fallback fails too
+	p.failure("wss://b")
+	assert.Equal(t, []string{"wss://a", "wss://b"}, p.ordered())
+
+	p.success("wss://b", time.Millisecond)
+	assert.Equal(t, []string{"wss://b", "wss://a"}, p.ordered())
+
+	h := p.health()
+	require.Len(t, h, 2)
+	assert.Equal(t, 1, h[0].Failures)
+	assert.Equal(t, 1, h[1].Successes)
+	assert.Equal(t, 0, h[1].ConsecutiveFailures)
+	assert.Equal(t, time.Millisecond, h[1].LastLatency)
+}
+
+func TestTransportFailover(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	p := NewDefaultParameters()
+	p.URL = "ws://127.0.0.1:1"
+	p.FallbackURLs = []string{"ws" + strings.TrimPrefix(server.URL, "http")}
+
+	f := NewWebsocketAsynchronousFactory(p).(*WebsocketAsynchronousFactory)
+	async := f.Create()
+	require.Nil(t, async.Connect())
+	defer func() {
+		go func() { <-async.Done() }()
+		async.Close()
+	}()
+
+	h := f.Health()
+	require.Len(t, h, 2)
+	assert.Equal(t, 1, h[0].ConsecutiveFailures)
+	assert.Equal(t, 1, h[1].Successes)
+	assert.Equal(t, p.FallbackURLs[0], async.(*ws).BaseURL)
+
+	c := NewWithParamsAsyncFactory(p, f)
+	assert.Len(t, c.EndpointHealth(), 2)
+}
@@ -0,0 +1,71 @@
+package websocket
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// Environment variables read by ClientFromEnv
+const (
+	EnvAPIKey    = "BFX_API_KEY"
+	EnvAPISecret = "BFX_API_SECRET"
+	EnvURL       = "BFX_WS_URL"
+	EnvAuthURL   = "BFX_WS_AUTH_URL"
+	EnvProxyURL  = "BFX_PROXY_URL"
+)
+
+// authBaseURL serves the authenticated channel, which is not available on the
+// public production url
+var authBaseURL = "wss://api.bitfinex.com/ws/2"
+
+// ClientFromEnv creates a client with default parameters configured by
+// environment variables:
+//
+//	BFX_API_KEY, BFX_API_SECRET  credentials, optional
+//	BFX_WS_URL                   url of the public sockets
+//	BFX_WS_AUTH_URL              url of the authenticated socket, defaults to the
+//	                             authenticated api if credentials are set and
+//	                             BFX_WS_URL isn't
+//	BFX_PROXY_URL                proxy for all connections, defaults to
+//	                             HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+func ClientFromEnv() (*Client, error) {
+	key, secret := os.Getenv(EnvAPIKey), os.Getenv(EnvAPISecret)
+	if (key == "") != (secret == "") {
+		return nil, fmt.Errorf("both %s and %s have to be set", EnvAPIKey, EnvAPISecret)
+	}
+
+	p := NewDefaultParameters()
+	if u := os.Getenv(EnvURL); u != "" {
+		p.URL = u
+	} else if key != "" {
+		p.AuthURL = authBaseURL
+	}
+	if u := os.Getenv(EnvAuthURL); u != "" {
+		p.AuthURL = u
+	}
+
+	for _, u := range []string{p.URL, p.AuthURL} {
+		if u == "" {
+			continue
+		}
+		if _, err := url.Parse(u); err != nil {
+			return nil, fmt.Errorf("invalid websocket url %q: %w", u, err)
+		}
+	}
+
+	if proxy := os.Getenv(EnvProxyURL); proxy != "" {
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvProxyURL, err)
+		}
+		p.Proxy = http.ProxyURL(u)
+	}
+
+	c := NewWithParams(p)
+	if key != "" {
+		c.Credentials(key, secret)
+	}
+	return c, nil
+}
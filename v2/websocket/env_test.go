@@ -0,0 +1,50 @@
+package websocket
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientFromEnv(t *testing.T) {
+	for _, k := range []string{EnvAPIKey, EnvAPISecret, EnvURL, EnvAuthURL, EnvProxyURL} {
+		prev, ok := os.LookupEnv(k)
+		k := k
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(k, prev)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+		os.Unsetenv(k)
+	}
+
+	c, err := ClientFromEnv()
+	require.Nil(t, err)
+	assert.Equal(t, productionBaseURL, c.parameters.URL)
+	assert.Equal(t, "", c.parameters.AuthURL)
+	assert.Nil(t, c.authAsyncFactory)
+
+	os.Setenv(EnvAPIKey, "key")
+	os.Setenv(EnvAPISecret, "secret")
+	os.Setenv(EnvProxyURL, "http://proxy:8080")
+	c, err = ClientFromEnv()
+	require.Nil(t, err)
+	assert.Equal(t, authBaseURL, c.parameters.AuthURL)
+	assert.NotNil(t, c.authAsyncFactory)
+	assert.NotNil(t, c.parameters.Proxy)
+	assert.True(t, c.hasCredentials())
+
+	os.Setenv(EnvURL, "wss://example.com/ws/2")
+	c, err = ClientFromEnv()
+	require.Nil(t, err)
+	assert.Equal(t, "wss://example.com/ws/2", c.parameters.URL)
+	assert.Equal(t, "", c.parameters.AuthURL)
+
+	os.Unsetenv(EnvAPISecret)
+	_, err = ClientFromEnv()
+	assert.NotNil(t, err)
+}
@@ -32,6 +32,7 @@ type AuthEvent struct {
 	SubID   string       `json:"subId"`
 	AuthID  string       `json:"auth_id,omitempty"`
 	Message string       `json:"msg,omitempty"`
+	Code    int          `json:"code,omitempty"`
 	Caps    Capabilities `json:"caps"`
 }
 
@@ -121,20 +122,33 @@ func (c *Client) handleEvent(socketId SocketId, msg []byte) error {
 				return err_open
 			}
 		}
+		c.updateMaintenance(&i)
 		c.listener <- &i
+		if ev := lifecycleEvent(socketId, &i); ev != nil {
+			c.emit(ev)
+		}
 	case "auth":
 		a := AuthEvent{}
 		err = json.Unmarshal(msg, &a)
 		if err != nil {
 			return err
 		}
+		var generation uint64
 		if a.Status != "" && a.Status == "OK" {
 			c.Authentication = SuccessfulAuthentication
+			generation = c.generations.authenticated()
 		} else {
 			c.Authentication = RejectedAuthentication
 		}
 		c.handleAuthAck(socketId, &a)
 		c.listener <- &a
+		c.emit(authEvent(socketId, &a, generation))
+		if ev := c.armed(socketId, &a, generation); ev != nil {
+			c.emit(ev)
+		}
+		if ev := c.rotated(socketId, &a); ev != nil {
+			c.emit(ev)
+		}
 		return nil
 	case "subscribed":
 		s := SubscribeEvent{}
@@ -146,6 +160,7 @@ func (c *Client) handleEvent(socketId SocketId, msg []byte) error {
 		if err != nil {
 			return err
 		}
+		c.waiters.resolve(&s)
 		c.listener <- &s
 		return nil
 	case "unsubscribed":
@@ -165,7 +180,10 @@ func (c *Client) handleEvent(socketId SocketId, msg []byte) error {
 		if err != nil {
 			return err
 		}
+		c.waiters.resolve(&er)
 		c.listener <- &er
+	case "pong":
+		return c.handlePong(msg)
 	case "conf":
 		ec := ConfEvent{}
 		err = json.Unmarshal(msg, &ec)
@@ -174,7 +192,15 @@ func (c *Client) handleEvent(socketId SocketId, msg []byte) error {
 		}
 		c.listener <- &ec
 	default:
-		c.log.Warningf("unknown event: %s", msg)
+		c.log.Debugf("unknown event: %s", msg)
+		var ev interface{}
+		ev, err = c.decodeUnknown(event.Event, msg)
+		if err != nil {
+			return err
+		}
+		if ev != nil {
+			c.listener <- ev
+		}
 	}
 
 	//err = json.Unmarshal(msg, &e)
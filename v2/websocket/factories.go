@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/convert"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/candle"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/derivatives"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/status"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/ticker"
 	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
 )
@@ -18,31 +22,66 @@ type messageFactory interface {
 	BuildSnapshot(sub *subscription, raw [][]interface{}, raw_bytes []byte) (interface{}, error)
 }
 
+// decoding holds the options set by Client.StrictDecoding, shared by the
+// client and its factories as they may be set after the factories are built
+type decoding struct {
+	opts atomic.Value
+}
+
+func (d *decoding) set(o convert.DecodeOptions) {
+	d.opts.Store(o)
+}
+
+// options returns the options to pass to the parsers, none to use the process
+// default
+func (d *decoding) options() []convert.DecodeOptions {
+	if o, ok := d.opts.Load().(convert.DecodeOptions); ok {
+		return []convert.DecodeOptions{o}
+	}
+	return nil
+}
+
 type TickerFactory struct {
 	*subscriptions
+	decode *decoding
 }
 
-func newTickerFactory(subs *subscriptions) *TickerFactory {
+func newTickerFactory(subs *subscriptions, decode *decoding) *TickerFactory {
 	return &TickerFactory{
 		subscriptions: subs,
+		decode:        decode,
 	}
 }
 
+// Build returns a *ticker.FundingTicker for funding symbols, a *ticker.Ticker
+// otherwise
 func (f *TickerFactory) Build(sub *subscription, objType string, raw []interface{}, raw_bytes []byte) (interface{}, error) {
-	return ticker.FromRaw(sub.Request.Symbol, raw)
+	if isFunding(sub.Request.Symbol) {
+		return ticker.FundingFromRaw(sub.Request.Symbol, raw, f.decode.options()...)
+	}
+	return ticker.FromRaw(sub.Request.Symbol, raw, f.decode.options()...)
 }
 
 func (f *TickerFactory) BuildSnapshot(sub *subscription, raw [][]interface{}, raw_bytes []byte) (interface{}, error) {
-	return ticker.SnapshotFromRaw(sub.Request.Symbol, raw)
+	if isFunding(sub.Request.Symbol) {
+		return ticker.FundingSnapshotFromRaw(sub.Request.Symbol, raw, f.decode.options()...)
+	}
+	return ticker.SnapshotFromRaw(sub.Request.Symbol, raw, f.decode.options()...)
+}
+
+func isFunding(symbol string) bool {
+	return strings.HasPrefix(symbol, common.FundingPrefix)
 }
 
 type TradeFactory struct {
 	*subscriptions
+	decode *decoding
 }
 
-func newTradeFactory(subs *subscriptions) *TradeFactory {
+func newTradeFactory(subs *subscriptions, decode *decoding) *TradeFactory {
 	return &TradeFactory{
 		subscriptions: subs,
+		decode:        decode,
 	}
 }
 
@@ -50,23 +89,25 @@ func (f *TradeFactory) Build(sub *subscription, objType string, raw []interface{
 	if "tu" == objType {
 		return nil, nil // do not process TradeUpdate messages on public feed, only need to process TradeExecution (first copy seen)
 	}
-	return trade.FromRaw(sub.Request.Symbol, raw)
+	return trade.FromRaw(sub.Request.Symbol, raw, f.decode.options()...)
 }
 
 func (f *TradeFactory) BuildSnapshot(sub *subscription, raw [][]interface{}, raw_bytes []byte) (interface{}, error) {
-	return trade.SnapshotFromRaw(sub.Request.Symbol, raw)
+	return trade.SnapshotFromRaw(sub.Request.Symbol, raw, f.decode.options()...)
 }
 
 type BookFactory struct {
 	*subscriptions
+	decode      *decoding
 	orderbooks  map[string]*Orderbook
 	manageBooks bool
 	lock        sync.Mutex
 }
 
-func newBookFactory(subs *subscriptions, obs map[string]*Orderbook, manageBooks bool) *BookFactory {
+func newBookFactory(subs *subscriptions, decode *decoding, obs map[string]*Orderbook, manageBooks bool) *BookFactory {
 	return &BookFactory{
 		subscriptions: subs,
+		decode:        decode,
 		orderbooks:    obs,
 		manageBooks:   manageBooks,
 	}
@@ -85,13 +126,18 @@ func ConvertBytesToJsonNumberArray(b []byte) ([]interface{}, error) {
 	return rawJSONNumbers, nil
 }
 
+// Build returns a *book.FundingBookUpdate for funding symbols, which are not
+// managed as orderbooks, a *book.Book otherwise
 func (f *BookFactory) Build(sub *subscription, objType string, raw []interface{}, b []byte) (interface{}, error) {
 	rawJSONNumbers, err := ConvertBytesToJsonNumberArray(b)
 	if err != nil {
 		return nil, err
 	}
+	if isFunding(sub.Request.Symbol) {
+		return book.FundingFromRaw(sub.Request.Symbol, sub.Request.Precision, raw, rawJSONNumbers[1], f.decode.options()...)
+	}
 
-	update, err := book.FromRaw(sub.Request.Symbol, sub.Request.Precision, raw, rawJSONNumbers[1])
+	update, err := book.FromRaw(sub.Request.Symbol, sub.Request.Precision, raw, rawJSONNumbers[1], f.decode.options()...)
 	if f.manageBooks {
 		f.lock.Lock()
 		defer f.lock.Unlock()
@@ -108,8 +154,11 @@ func (f *BookFactory) BuildSnapshot(sub *subscription, raw [][]interface{}, b []
 	if err != nil {
 		return nil, err
 	}
+	if isFunding(sub.Request.Symbol) {
+		return book.FundingSnapshotFromRaw(sub.Request.Symbol, sub.Request.Precision, raw, rawJSONNumbers[1], f.decode.options()...)
+	}
 
-	update, err := book.SnapshotFromRaw(sub.Request.Symbol, sub.Request.Precision, raw, rawJSONNumbers[1])
+	update, err := book.SnapshotFromRaw(sub.Request.Symbol, sub.Request.Precision, raw, rawJSONNumbers[1], f.decode.options()...)
 	if err != nil {
 		return nil, err
 	}
@@ -131,11 +180,13 @@ func (f *BookFactory) BuildSnapshot(sub *subscription, raw [][]interface{}, b []
 
 type CandlesFactory struct {
 	*subscriptions
+	decode *decoding
 }
 
-func newCandlesFactory(subs *subscriptions) *CandlesFactory {
+func newCandlesFactory(subs *subscriptions, decode *decoding) *CandlesFactory {
 	return &CandlesFactory{
 		subscriptions: subs,
+		decode:        decode,
 	}
 }
 
@@ -144,7 +195,7 @@ func (f *CandlesFactory) Build(sub *subscription, objType string, raw []interfac
 	if err != nil {
 		return nil, err
 	}
-	candle, err := candle.FromRaw(sym, res, raw)
+	candle, err := candle.FromRaw(sym, res, raw, f.decode.options()...)
 	return candle, err
 }
 
@@ -153,31 +204,63 @@ func (f *CandlesFactory) BuildSnapshot(sub *subscription, raw [][]interface{}, r
 	if err != nil {
 		return nil, err
 	}
-	snap, err := candle.SnapshotFromRaw(sym, res, raw)
+	snap, err := candle.SnapshotFromRaw(sym, res, raw, f.decode.options()...)
 	return snap, err
 }
 
 type StatsFactory struct {
 	*subscriptions
+	decode *decoding
 }
 
-func newStatsFactory(subs *subscriptions) *StatsFactory {
+func newStatsFactory(subs *subscriptions, decode *decoding) *StatsFactory {
 	return &StatsFactory{
 		subscriptions: subs,
+		decode:        decode,
+	}
+}
+
+// statusKey splits the key of a status subscription into its type and
+// symbol, e.g. deriv:tBTCF0:USTF0 or liq:global
+func statusKey(key string) (common.StatusType, string, error) {
+	splits := strings.SplitN(key, ":", 2)
+	if len(splits) != 2 || splits[1] == "" {
+		return "", "", fmt.Errorf("unable to parse status key %s", key)
 	}
+	return common.StatusType(splits[0]), splits[1], nil
 }
 
 func (f *StatsFactory) Build(sub *subscription, objType string, raw []interface{}, raw_bytes []byte) (interface{}, error) {
-	splits := strings.Split(sub.Request.Key, ":")
-	if len(splits) != 3 {
-		return nil, fmt.Errorf("unable to parse key to symbol %s", sub.Request.Key)
+	sType, symbol, err := statusKey(sub.Request.Key)
+	if err != nil {
+		return nil, err
 	}
-	symbol := splits[1] + ":" + splits[2]
-	d, err := derivatives.FromWsRaw(symbol, raw)
-	return d, err
+	switch sType {
+	case common.DerivativeStatusType:
+		return derivatives.FromWsRaw(symbol, raw, f.decode.options()...)
+	case common.LiquidationStatusType:
+		return status.LiqFromRaw(raw, f.decode.options()...)
+	}
+	return nil, fmt.Errorf("unrecognized status type %s", sType)
 }
 
 func (f *StatsFactory) BuildSnapshot(sub *subscription, raw [][]interface{}, raw_bytes []byte) (interface{}, error) {
-	// no snapshots
-	return nil, nil
+	sType, symbol, err := statusKey(sub.Request.Key)
+	if err != nil {
+		return nil, err
+	}
+	switch sType {
+	case common.DerivativeStatusType:
+		snap := &derivatives.Snapshot{Snapshot: make([]*derivatives.DerivativeStatus, len(raw))}
+		for i, r := range raw {
+			if snap.Snapshot[i], err = derivatives.FromWsRaw(symbol, r, f.decode.options()...); err != nil {
+				return nil, err
+			}
+		}
+		return snap, nil
+	case common.LiquidationStatusType:
+		// liquidations are always sent as a list, also for updates
+		return status.LiqSnapshotFromRaw(raw, f.decode.options()...)
+	}
+	return nil, fmt.Errorf("unrecognized status type %s", sType)
 }
@@ -0,0 +1,76 @@
+package websocket
+
+import "sync"
+
+// snapshotOf maps the updates of the authenticated channel to the snapshot
+// of their kind
+var snapshotOf = map[string]string{
+	"on": "os", "ou": "os", "oc": "os",
+	"pn": "ps", "pu": "ps", "pc": "ps",
+	"wu":  "ws",
+	"fon": "fos", "fou": "fos", "foc": "fos",
+	"fcn": "fcs", "fcu": "fcs", "fcc": "fcs",
+	"fln": "fls", "flu": "fls", "flc": "fls",
+}
+
+// PreSnapshotEvent is emitted on the Listen channel instead of an update of
+// the authenticated channel which was received after a re-authentication,
+// e.g. following a reconnect, but before the snapshot of its kind, if
+// Parameters.TagPreSnapshot is set. Such an update may predate the state of
+// the snapshot and is best discarded, which consumers switching on the type
+// of the events do by default. Generation is the one of the
+// AuthSucceededEvent the update was received after. Without TagPreSnapshot
+// the updates are emitted as they are, consumers track the generation with
+// AuthSucceededEvent instead.
+type PreSnapshotEvent struct {
+	Generation uint64
+	Event      interface{}
+}
+
+// generations counts the authentications of the client and tracks the
+// snapshots which have not been received since the last one
+type generations struct {
+	mu      sync.Mutex
+	current uint64
+	pending map[string]bool
+	wrap    bool // see Parameters.TagPreSnapshot
+}
+
+// authenticated starts a new generation and returns it
+func (g *generations) authenticated() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.current++
+	g.pending = nil
+	if g.current > 1 {
+		g.pending = make(map[string]bool, len(snapshotOf))
+		for _, snap := range snapshotOf {
+			g.pending[snap] = true
+		}
+	}
+	return g.current
+}
+
+// tag returns the event of a private message of the given term, wrapped in a
+// PreSnapshotEvent if the snapshot of its kind is pending and wrapping is
+// enabled
+func (g *generations) tag(term string, ev interface{}) interface{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.pending, term)
+	if ev == nil {
+		return nil
+	}
+	if snap, ok := snapshotOf[term]; ok && g.wrap && g.pending[snap] {
+		return &PreSnapshotEvent{Generation: g.current, Event: ev}
+	}
+	return ev
+}
+
+// Generation returns the number of successful authentications of the client,
+// see AuthSucceededEvent.Generation
+func (c *Client) Generation() uint64 {
+	c.generations.mu.Lock()
+	defer c.generations.mu.Unlock()
+	return c.generations.current
+}
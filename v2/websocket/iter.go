@@ -0,0 +1,85 @@
+//go:build go1.23
+
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/candle"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+)
+
+// Messages returns an iterator over the messages of Listen. The iteration
+// ends once ctx is done or the client is closed. It consumes the Listen
+// channel, use it instead of reading Listen and not alongside.
+func (c *Client) Messages(ctx context.Context) iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-c.listener:
+				if !ok || !yield(msg) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// TradesSeq subscribes to the public trades of symbol and returns an
+// iterator over them, e.g.:
+//
+//	for t, err := range c.TradesSeq(ctx, "tBTCUSD") {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// The trades are routed past Listen, see Subscribe, so other messages are
+// not affected. A failing subscription is yielded as error and ends the
+// iteration. The subscription is removed once the loop ends, ctx is done or
+// the client is closed.
+func (c *Client) TradesSeq(ctx context.Context, symbol string) iter.Seq2[*trade.Trade, error] {
+	return subscriptionSeq[*trade.Trade](ctx, c, &SubscriptionRequest{
+		Event:   EventSubscribe,
+		Channel: ChanTrades,
+		Symbol:  symbol,
+	})
+}
+
+// CandlesSeq subscribes to the candles of symbol and returns an iterator over
+// them, see TradesSeq.
+func (c *Client) CandlesSeq(ctx context.Context, symbol string, resolution common.CandleResolution) iter.Seq2[*candle.Candle, error] {
+	return subscriptionSeq[*candle.Candle](ctx, c, &SubscriptionRequest{
+		Event:   EventSubscribe,
+		Channel: ChanCandles,
+		Key:     fmt.Sprintf("trade:%s:%s", resolution, symbol),
+	})
+}
+
+// subscriptionSeq yields the messages of a typed subscription until the loop
+// ends, unsubscribing afterwards. Every loop subscribes anew.
+func subscriptionSeq[T any](ctx context.Context, c *Client, req *SubscriptionRequest) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		sub := *req
+		msgs, err := Subscribe[T](ctx, c, &sub)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		for m := range msgs {
+			if !yield(m, nil) {
+				return
+			}
+		}
+	}
+}
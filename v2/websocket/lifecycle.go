@@ -0,0 +1,141 @@
+package websocket
+
+import "context"
+
+// info codes sent by the platform on a connected socket
+const (
+	InfoCodeReconnect        int = 20051 // server restarting, reconnect
+	InfoCodeMaintenanceStart int = 20060 // maintenance started, pause activity
+	InfoCodeMaintenanceEnd   int = 20061 // maintenance ended, resubscribe
+)
+
+// ConnectedEvent is emitted on the Listen channel once the platform greeted a
+// newly connected or reconnected socket
+type ConnectedEvent struct {
+	SocketId SocketId
+	ServerId string
+	Version  float64
+}
+
+// DisconnectedEvent is emitted on the Listen channel when a socket lost its
+// connection, before any reconnect attempt. It is not emitted by Close.
+type DisconnectedEvent struct {
+	SocketId SocketId
+	Err      error // close or heartbeat error, nil for a clean close
+}
+
+// AuthSucceededEvent is emitted on the Listen channel once a socket was
+// authenticated, before the snapshots of the authenticated channel.
+// Generation counts the authentications of the client, starting at 1, so a
+// greater one marks a re-authentication after which state built from the
+// previous snapshots is stale until the new snapshots arrive, see
+// Parameters.TagPreSnapshot.
+type AuthSucceededEvent struct {
+	SocketId   SocketId
+	UserID     int64
+	Caps       Capabilities
+	Generation uint64
+}
+
+// AuthFailedEvent is emitted on the Listen channel if the platform rejected
+// the credentials of a socket
+type AuthFailedEvent struct {
+	SocketId SocketId
+	Code     int
+	Message  string
+}
+
+// KeyRotatedEvent is emitted on the Listen channel once a socket was
+// authenticated with the credentials passed to RotateCredentials. APIKey is
+// empty for token credentials.
+type KeyRotatedEvent struct {
+	SocketId SocketId
+	APIKey   string
+	UserID   int64
+}
+
+// CancelOnDisconnectArmedEvent is emitted on the Listen channel once a socket
+// was authenticated with the dead man's switch set by CancelOnDisconnect, so
+// the orders of the session are canceled if the socket disconnects. It is
+// emitted again after every re-authentication, e.g. following a reconnect,
+// with the Generation of the AuthSucceededEvent.
+type CancelOnDisconnectArmedEvent struct {
+	SocketId   SocketId
+	Generation uint64
+	Filter     []string // filter of the authenticated channel, see AuthFilter
+}
+
+// MaintenanceStartedEvent is emitted on the Listen channel when the platform
+// announces maintenance on a socket, see InfoCodeMaintenanceStart
+type MaintenanceStartedEvent struct {
+	SocketId SocketId
+}
+
+// MaintenanceEndedEvent is emitted on the Listen channel when the platform
+// announces the end of maintenance on a socket, see InfoCodeMaintenanceEnd
+type MaintenanceEndedEvent struct {
+	SocketId SocketId
+}
+
+// updateMaintenance tracks the maintenance announced by an info event. A
+// greeting of an operative platform ends the maintenance as well, in case
+// the end was missed while disconnected.
+func (c *Client) updateMaintenance(i *InfoEvent) {
+	switch {
+	case i.Code == InfoCodeMaintenanceStart:
+		c.maintenance.Start()
+	case i.Code == InfoCodeMaintenanceEnd:
+		c.maintenance.End()
+	case i.Code == 0 && i.Version != 0 && i.Platform.Status == 1:
+		c.maintenance.End()
+	}
+}
+
+// InMaintenance reports whether the platform announced a maintenance that has
+// not ended yet
+func (c *Client) InMaintenance() bool {
+	return c.maintenance.Active()
+}
+
+// writeSocket returns the authenticated socket for order and funding
+// requests once the maintenance gate allows them
+func (c *Client) writeSocket(ctx context.Context) (*Socket, error) {
+	if err := c.maintenance.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.GetAuthenticatedSocket()
+}
+
+// lifecycleEvent returns the lifecycle event announced by an info event, if any
+func lifecycleEvent(socketId SocketId, i *InfoEvent) interface{} {
+	switch {
+	case i.Code == 0 && i.Version != 0:
+		return &ConnectedEvent{SocketId: socketId, ServerId: i.ServerId, Version: i.Version}
+	case i.Code == InfoCodeMaintenanceStart:
+		return &MaintenanceStartedEvent{SocketId: socketId}
+	case i.Code == InfoCodeMaintenanceEnd:
+		return &MaintenanceEndedEvent{SocketId: socketId}
+	}
+	return nil
+}
+
+// armed returns the event confirming the dead man's switch of a successful
+// auth response, if the request set it
+func (c *Client) armed(socketId SocketId, a *AuthEvent, generation uint64) interface{} {
+	if a.Status != "OK" {
+		return nil
+	}
+	sub, err := c.subscriptions.lookupBySubscriptionID(a.SubID)
+	if err != nil || sub.Request.DMS != DMSCancelOnDisconnect {
+		return nil
+	}
+	return &CancelOnDisconnectArmedEvent{SocketId: socketId, Generation: generation, Filter: sub.Request.Filter}
+}
+
+// authEvent returns the lifecycle event of an auth response
+func authEvent(socketId SocketId, a *AuthEvent, generation uint64) interface{} {
+	if a.Status == "OK" {
+		return &AuthSucceededEvent{SocketId: socketId, UserID: a.UserID, Caps: a.Caps, Generation: generation}
+	}
+	return &AuthFailedEvent{SocketId: socketId, Code: a.Code, Message: a.Message}
+}
@@ -16,6 +16,10 @@ type Orderbook struct {
 	symbol string
 	bids   []*book.Book
 	asks   []*book.Book
+
+	// total amounts of each side, maintained on every update
+	bidVolume float64
+	askVolume float64
 }
 
 // return a dereferenced copy of an orderbook side. This is so consumers can access
@@ -50,11 +54,14 @@ func (ob *Orderbook) SetWithSnapshot(bs *book.Snapshot) {
 
 	ob.bids = make([]*book.Book, 0)
 	ob.asks = make([]*book.Book, 0)
+	ob.bidVolume, ob.askVolume = 0, 0
 	for _, order := range bs.Snapshot {
 		if order.Side == common.Bid {
 			ob.bids = append(ob.bids, order)
+			ob.bidVolume += order.Amount
 		} else {
 			ob.asks = append(ob.asks, order)
+			ob.askVolume += order.Amount
 		}
 	}
 }
@@ -63,14 +70,15 @@ func (ob *Orderbook) UpdateWith(b *book.Book) {
 	ob.lock.Lock()
 	defer ob.lock.Unlock()
 
-	side := &ob.asks
+	side, volume := &ob.asks, &ob.askVolume
 	if b.Side == common.Bid {
-		side = &ob.bids
+		side, volume = &ob.bids, &ob.bidVolume
 	}
 
 	// check if first in book
 	if len(*side) == 0 {
 		*side = append(*side, b)
+		*volume += b.Amount
 		return
 	}
 
@@ -80,6 +88,7 @@ func (ob *Orderbook) UpdateWith(b *book.Book) {
 			if index+1 > len(*(side)) {
 				return
 			}
+			*volume -= sOrder.Amount
 			if b.Count <= 0 {
 				// delete if count is equal to zero
 				*side = append((*side)[:index], (*side)[index+1:]...)
@@ -90,6 +99,7 @@ func (ob *Orderbook) UpdateWith(b *book.Book) {
 		}
 	}
 	*side = append(*side, b)
+	*volume += b.Amount
 	// add to the orderbook and sort lowest to highest
 	sort.Slice(*side, func(i, j int) bool {
 		if i >= len(*(side)) || j >= len(*(side)) {
@@ -121,3 +131,124 @@ func (ob *Orderbook) Checksum() uint32 {
 	checksumStrings := strings.Join(checksumItems, ":")
 	return crc32.ChecksumIEEE([]byte(checksumStrings))
 }
+
+// BestBid returns the highest bid, false if there are no bids
+func (ob *Orderbook) BestBid() (book.Book, bool) {
+	ob.lock.RLock()
+	defer ob.lock.RUnlock()
+	if len(ob.bids) == 0 {
+		return book.Book{}, false
+	}
+	return *ob.bids[0], true
+}
+
+// BestAsk returns the lowest ask, false if there are no asks
+func (ob *Orderbook) BestAsk() (book.Book, bool) {
+	ob.lock.RLock()
+	defer ob.lock.RUnlock()
+	if len(ob.asks) == 0 {
+		return book.Book{}, false
+	}
+	return *ob.asks[0], true
+}
+
+// Spread returns the difference between the best ask and best bid, false if
+// either side is empty
+func (ob *Orderbook) Spread() (float64, bool) {
+	ob.lock.RLock()
+	defer ob.lock.RUnlock()
+	if len(ob.bids) == 0 || len(ob.asks) == 0 {
+		return 0, false
+	}
+	return ob.asks[0].Price - ob.bids[0].Price, true
+}
+
+// Mid returns the price halfway between the best bid and best ask, false if
+// either side is empty
+func (ob *Orderbook) Mid() (float64, bool) {
+	ob.lock.RLock()
+	defer ob.lock.RUnlock()
+	return ob.mid()
+}
+
+func (ob *Orderbook) mid() (float64, bool) {
+	if len(ob.bids) == 0 || len(ob.asks) == 0 {
+		return 0, false
+	}
+	return (ob.asks[0].Price + ob.bids[0].Price) / 2, true
+}
+
+// Microprice returns the mid price weighted by the amounts at the top of the
+// book, which leans towards the side more likely to be consumed next
+func (ob *Orderbook) Microprice() (float64, bool) {
+	ob.lock.RLock()
+	defer ob.lock.RUnlock()
+	if len(ob.bids) == 0 || len(ob.asks) == 0 {
+		return 0, false
+	}
+
+	bid, ask := ob.bids[0], ob.asks[0]
+	if bid.Amount+ask.Amount == 0 {
+		return (bid.Price + ask.Price) / 2, true
+	}
+	return (bid.Price*ask.Amount + ask.Price*bid.Amount) / (bid.Amount + ask.Amount), true
+}
+
+// DepthWithin returns the cumulative bid and ask amounts priced within the
+// given distance from the mid price, in basis points. Only the levels within
+// the distance are visited.
+func (ob *Orderbook) DepthWithin(bps float64) (bids, asks float64) {
+	ob.lock.RLock()
+	defer ob.lock.RUnlock()
+
+	mid, ok := ob.mid()
+	if !ok {
+		return 0, 0
+	}
+
+	dist := mid * bps / 10000
+	for _, b := range ob.bids {
+		if b.Price < mid-dist {
+			break
+		}
+		bids += b.Amount
+	}
+	for _, a := range ob.asks {
+		if a.Price > mid+dist {
+			break
+		}
+		asks += a.Amount
+	}
+	return bids, asks
+}
+
+// Volume returns the total bid and ask amounts of the book
+func (ob *Orderbook) Volume() (bids, asks float64) {
+	ob.lock.RLock()
+	defer ob.lock.RUnlock()
+	return ob.bidVolume, ob.askVolume
+}
+
+// Imbalance returns (bids - asks) / (bids + asks) of the total amounts in the
+// book, ranging from -1 when only asks are left to 1 when only bids are. The
+// totals are maintained incrementally so this is cheap to call on every tick.
+func (ob *Orderbook) Imbalance() (float64, bool) {
+	ob.lock.RLock()
+	defer ob.lock.RUnlock()
+	total := ob.bidVolume + ob.askVolume
+	if total == 0 {
+		return 0, false
+	}
+	return (ob.bidVolume - ob.askVolume) / total, true
+}
+
+// EstimateFill walks the book for an order of the given side and absolute
+// amount, see book.EstimateFill
+func (ob *Orderbook) EstimateFill(side common.OrderSide, amount float64) book.Estimate {
+	ob.lock.RLock()
+	defer ob.lock.RUnlock()
+	if side == common.Ask {
+		return book.EstimateFill(ob.bids, side, amount)
+	}
+	return book.EstimateFill(ob.asks, side, amount)
+}
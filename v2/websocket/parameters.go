@@ -1,7 +1,11 @@
 package websocket
 
 import (
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
 	"github.com/op/go-logging"
+	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -10,6 +14,7 @@ type Parameters struct {
 	AutoReconnect          bool
 	ReconnectInterval      time.Duration
 	ReconnectAttempts      int
+	ReconnectBackoff       Backoff // delays between reconnect attempts, defaults to ReconnectInterval and ReconnectAttempts
 	reconnectTry           int
 	ShutdownTimeout        time.Duration
 	CapacityPerConnection  int
@@ -21,7 +26,44 @@ type Parameters struct {
 	LogTransport           bool
 
 	URL                    string
+	FallbackURLs           []string         // used when URL is unreachable, see Client.EndpointHealth
+	AuthURL                string           // separate endpoint for the authenticated socket, e.g. wss://api.bitfinex.com/ws/2
+	AuthReconnect          *ReconnectPolicy // reconnect policy of the AuthURL socket, defaults to the fields above
+	Proxy                  func(*http.Request) (*url.URL, error) // defaults to http.ProxyFromEnvironment
 	ManageOrderbook        bool
+	TagPreSnapshot         bool                   // wrap updates received between a re-authentication and their snapshot in a PreSnapshotEvent
+	Maintenance            common.MaintenanceMode // handling of order and funding requests during maintenance
+	Clock                  utils.Clock            // time of reconnect backoffs and the heartbeat watchdog, defaults to utils.SystemClock
+}
+
+// ReconnectPolicy controls how a socket is reconnected after an unexpected
+// disconnect
+type ReconnectPolicy struct {
+	AutoReconnect bool
+	Interval      time.Duration
+	Attempts      int
+	Backoff       Backoff // takes precedence over Interval and Attempts
+}
+
+// backoff returns the Backoff of the policy, or a constant one built from
+// Interval and Attempts
+func (p ReconnectPolicy) backoff() Backoff {
+	if p.Backoff != nil {
+		return p.Backoff
+	}
+	return &ConstantBackoff{Interval: p.Interval, Attempts: p.Attempts}
+}
+
+func (p *Parameters) reconnectPolicy(authEndpoint bool) ReconnectPolicy {
+	if authEndpoint && p.AuthReconnect != nil {
+		return *p.AuthReconnect
+	}
+	return ReconnectPolicy{
+		AutoReconnect: p.AutoReconnect,
+		Interval:      p.ReconnectInterval,
+		Attempts:      p.ReconnectAttempts,
+		Backoff:       p.ReconnectBackoff,
+	}
 }
 
 func NewDefaultParameters() *Parameters {
@@ -38,5 +80,6 @@ func NewDefaultParameters() *Parameters {
 		HeartbeatTimeout:       time.Second * 30,
 		LogTransport:           false,           // log transport send/recv
 		Logger:                 logging.MustGetLogger("bitfinex-ws"),
+		Clock:                  utils.SystemClock,
 	}
 }
@@ -0,0 +1,145 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// heartbeatSamples is the number of heartbeat intervals kept for HeartbeatStats
+const heartbeatSamples = 64
+
+// PingRequest asks the platform for a pong with the same CID
+type PingRequest struct {
+	Event string `json:"event"`
+	CID   int64  `json:"cid"`
+}
+
+// PongEvent answers a PingRequest
+type PongEvent struct {
+	CID int64 `json:"cid"`
+	Ts  int64 `json:"ts"` // server time in milliseconds
+}
+
+// HeartbeatStats describes the intervals between the heartbeats of the
+// subscribed channels. The platform sends a heartbeat every 15 seconds on an
+// idle channel, a high jitter indicates a congested connection.
+type HeartbeatStats struct {
+	Samples  int           // number of intervals, at most the last 64
+	Interval time.Duration // mean interval
+	Jitter   time.Duration // standard deviation of the intervals
+	Max      time.Duration // longest interval
+}
+
+// PingStats is the result of a Ping
+type PingStats struct {
+	RoundTrip  time.Duration // time until the pong was received
+	ServerTime time.Time     // time of the pong according to the platform
+	Heartbeat  HeartbeatStats
+}
+
+// pings correlates pongs with pending pings
+type pings struct {
+	cid     int64
+	mu      sync.Mutex
+	pending map[int64]chan *PongEvent
+}
+
+func (p *pings) add() (int64, chan *PongEvent) {
+	cid := atomic.AddInt64(&p.cid, 1)
+	ch := make(chan *PongEvent, 1)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pending == nil {
+		p.pending = make(map[int64]chan *PongEvent)
+	}
+	p.pending[cid] = ch
+	return cid, ch
+}
+
+func (p *pings) remove(cid int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.pending, cid)
+}
+
+func (p *pings) resolve(pong *PongEvent) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ch, ok := p.pending[pong.CID]
+	if ok {
+		ch <- pong
+		delete(p.pending, pong.CID)
+	}
+	return ok
+}
+
+// Ping measures the round trip of a ping on the first socket along with the
+// heartbeat jitter of the subscribed channels, e.g. to score the health of
+// the venue. Use the REST client's Ping for the latency of REST requests.
+func (c *Client) Ping(ctx context.Context) (*PingStats, error) {
+	socket, err := c.getSocket()
+	if err != nil {
+		return nil, err
+	}
+	cid, pong := c.pings.add()
+	defer c.pings.remove(cid)
+
+	start := time.Now()
+	req := &PingRequest{Event: EventPing, CID: cid}
+	if err := c.sendBySocket(ctx, socket, req); err != nil {
+		return nil, err
+	}
+	select {
+	case p := <-pong:
+		return &PingStats{
+			RoundTrip:  time.Since(start),
+			ServerTime: time.Unix(0, p.Ts*int64(time.Millisecond)),
+			Heartbeat:  c.HeartbeatStats(),
+		}, nil
+	case <-ctx.Done():
+		return nil, &TimeoutError{Request: req, Err: ctx.Err()}
+	}
+}
+
+// HeartbeatStats returns the statistics of the recent heartbeat intervals
+func (c *Client) HeartbeatStats() HeartbeatStats {
+	return heartbeatStats(c.subscriptions.heartbeatIntervals())
+}
+
+func (c *Client) handlePong(msg []byte) error {
+	p := &PongEvent{}
+	if err := json.Unmarshal(msg, p); err != nil {
+		return err
+	}
+	if !c.pings.resolve(p) {
+		c.log.Debugf("pong %d without pending ping", p.CID)
+	}
+	return nil
+}
+
+func heartbeatStats(intervals []time.Duration) HeartbeatStats {
+	st := HeartbeatStats{Samples: len(intervals)}
+	if len(intervals) == 0 {
+		return st
+	}
+	var sum float64
+	for _, d := range intervals {
+		sum += float64(d)
+		if d > st.Max {
+			st.Max = d
+		}
+	}
+	mean := sum / float64(len(intervals))
+	var variance float64
+	for _, d := range intervals {
+		variance += (float64(d) - mean) * (float64(d) - mean)
+	}
+	variance /= float64(len(intervals))
+	st.Interval = time.Duration(mean)
+	st.Jitter = time.Duration(math.Sqrt(variance))
+	return st
+}
@@ -0,0 +1,27 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeartbeatStats(t *testing.T) {
+	assert.Equal(t, HeartbeatStats{}, heartbeatStats(nil))
+
+	st := heartbeatStats([]time.Duration{14 * time.Second, 16 * time.Second, 14 * time.Second, 16 * time.Second})
+	assert.Equal(t, 4, st.Samples)
+	assert.Equal(t, 15*time.Second, st.Interval)
+	assert.Equal(t, time.Second, st.Jitter)
+	assert.Equal(t, 16*time.Second, st.Max)
+}
+
+func TestPingsResolve(t *testing.T) {
+	var p pings
+	cid, ch := p.add()
+	assert.False(t, p.resolve(&PongEvent{CID: cid + 1}))
+	assert.True(t, p.resolve(&PongEvent{CID: cid, Ts: 1}))
+	assert.Equal(t, int64(1), (<-ch).Ts)
+	assert.False(t, p.resolve(&PongEvent{CID: cid}))
+}
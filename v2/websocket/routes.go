@@ -0,0 +1,144 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+)
+
+// route delivers the messages of a subscription to a dedicated consumer
+// instead of the Listen channel. It stays attached to the subscription
+// request across resubscriptions.
+type route struct {
+	deliver func(msg interface{}, done <-chan struct{})
+	onClose func()
+
+	done chan struct{}
+	once sync.Once
+	mu   sync.Mutex
+	// closed is set once onClose was called, no more messages are delivered
+	closed bool
+}
+
+func newRoute(deliver func(msg interface{}, done <-chan struct{}), onClose func()) *route {
+	return &route{
+		deliver: deliver,
+		onClose: onClose,
+		done:    make(chan struct{}),
+	}
+}
+
+func (r *route) send(msg interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.deliver(msg, r.done)
+}
+
+// stop unblocks a pending delivery and closes the route
+func (r *route) stop() {
+	r.once.Do(func() { close(r.done) })
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.closed {
+		r.closed = true
+		r.onClose()
+	}
+}
+
+// publish passes a message of a public subscription to its route, or to the
+// Listen channel if it has none
+func (c *Client) publish(req *SubscriptionRequest, msg interface{}) {
+	if r := req.route; r != nil {
+		r.send(msg)
+		return
+	}
+	c.listener <- msg
+}
+
+func (c *Client) addRoute(r *route) {
+	c.routeMtx.Lock()
+	defer c.routeMtx.Unlock()
+	if c.routes == nil {
+		c.routes = make(map[*route]bool)
+	}
+	c.routes[r] = true
+}
+
+func (c *Client) removeRoute(r *route) {
+	c.routeMtx.Lock()
+	defer c.routeMtx.Unlock()
+	delete(c.routes, r)
+}
+
+// closeRoutes stops all routes on shutdown of the client
+func (c *Client) closeRoutes() {
+	c.routeMtx.Lock()
+	routes := c.routes
+	c.routes = nil
+	c.routeMtx.Unlock()
+
+	for r := range routes {
+		r.stop()
+	}
+}
+
+// unsubscribeRoute removes the current subscription of a route, which may
+// have been renewed since it was created
+func (c *Client) unsubscribeRoute(r *route) {
+	sub, err := c.subscriptions.lookupByRoute(r)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.parameters.ShutdownTimeout)
+	defer cancel()
+	if err := c.sendUnsubscribeMessage(ctx, sub); err != nil {
+		c.log.Warningf("could not unsubscribe %s: %s", sub.SubID(), err)
+	}
+}
+
+// SubscribeFunc subscribes to a public channel and passes its messages,
+// snapshots as well as updates, to fn instead of Listen. fn is called from
+// the goroutine reading the connection and must not block. The subscription
+// is removed once ctx is done, onClose is called, if set, once no more
+// messages are delivered. A subscription ID is generated if req has none.
+func (c *Client) SubscribeFunc(ctx context.Context, req *SubscriptionRequest, fn func(msg interface{}), onClose func()) (string, error) {
+	r := newRoute(func(msg interface{}, done <-chan struct{}) {
+		fn(msg)
+	}, func() {
+		if onClose != nil {
+			onClose()
+		}
+	})
+	return c.subscribeRoute(ctx, req, r)
+}
+
+// subscribeRoute subscribes req with its messages delivered to r until ctx
+// is done
+func (c *Client) subscribeRoute(ctx context.Context, req *SubscriptionRequest, r *route) (string, error) {
+	if req.SubID == "" {
+		req.SubID = c.nonce.GetNonce()
+	}
+	req.route = r
+	c.addRoute(r)
+	subID, err := c.Subscribe(ctx, req)
+	if err != nil {
+		c.removeRoute(r)
+		r.stop()
+		return "", err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-r.done:
+			return
+		}
+		c.removeRoute(r)
+		r.stop()
+		c.unsubscribeRoute(r)
+	}()
+	return subID, nil
+}
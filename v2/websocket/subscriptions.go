@@ -6,6 +6,9 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/utils"
 )
 
 type SubscriptionRequest struct {
@@ -17,6 +20,7 @@ type SubscriptionRequest struct {
 	AuthSig     string   `json:"authSig,omitempty"`
 	AuthPayload string   `json:"authPayload,omitempty"`
 	AuthNonce   string   `json:"authNonce,omitempty"`
+	AuthToken   string   `json:"token,omitempty"`
 	Filter      []string `json:"filter,omitempty"`
 	DMS         int      `json:"dms,omitempty"` // dead man switch
 
@@ -28,6 +32,9 @@ type SubscriptionRequest struct {
 	Key       string `json:"key,omitempty"`
 	Len       string `json:"len,omitempty"`
 	Pair      string `json:"pair,omitempty"`
+
+	// route receives the messages instead of the Listen channel
+	route *route
 }
 
 const MaxChannels = 25
@@ -64,6 +71,7 @@ type subscription struct {
 	Request    *SubscriptionRequest
 
 	hbDeadline time.Time
+	hbLast     time.Time
 }
 
 func isPublic(request *SubscriptionRequest) bool {
@@ -100,7 +108,7 @@ func (s subscription) Pending() bool {
 	return s.pending
 }
 
-func newSubscriptions(heartbeatTimeout time.Duration, log *logging.Logger) *subscriptions {
+func newSubscriptions(heartbeatTimeout time.Duration, log *logging.Logger, clock utils.Clock) *subscriptions {
 	subs := &subscriptions{
 		subsBySubID:  make(map[string]*subscription),
 		subsByChanID: make(map[int64]*subscription),
@@ -110,6 +118,7 @@ func newSubscriptions(heartbeatTimeout time.Duration, log *logging.Logger) *subs
 		hbDisconnect: make(chan HeartbeatDisconnect),
 		hbSleep:      heartbeatTimeout / time.Duration(4),
 		log:          log,
+		clock:        clock,
 		lock:         &sync.RWMutex{},
 	}
 	go subs.control()
@@ -125,6 +134,7 @@ type heartbeat struct {
 type subscriptions struct {
 	lock         *sync.RWMutex
 	log          *logging.Logger
+	clock        utils.Clock // time of the heartbeat watchdog
 
 	subsBySocketId map[SocketId]SubscriptionSet // subscripts map indexed by socket id
 	subsBySubID  map[string]*subscription // subscription map indexed by subscription ID
@@ -135,6 +145,7 @@ type subscriptions struct {
 	hbTimeout    time.Duration
 	hbSleep      time.Duration
 	hbShutdown   chan struct{}
+	hbIntervals  []time.Duration // recent intervals between heartbeat messages
 }
 
 // SubscriptionSet is a typed version of an array of subscription pointers, intended to meet the sortable interface.
@@ -182,8 +193,33 @@ func (s *subscriptions) heartbeat(chanID int64) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	if sub, ok := s.subsByChanID[chanID]; ok {
-		sub.hbDeadline = time.Now().Add(s.hbTimeout)
+		sub.hbDeadline = s.clock.Now().Add(s.hbTimeout)
+	}
+}
+
+// heartbeatReceived records the interval since the previous heartbeat message
+// of a channel
+func (s *subscriptions) heartbeatReceived(chanID int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	sub, ok := s.subsByChanID[chanID]
+	if !ok {
+		return
+	}
+	now := s.clock.Now()
+	if !sub.hbLast.IsZero() {
+		s.hbIntervals = append(s.hbIntervals, now.Sub(sub.hbLast))
+		if len(s.hbIntervals) > heartbeatSamples {
+			s.hbIntervals = s.hbIntervals[len(s.hbIntervals)-heartbeatSamples:]
+		}
 	}
+	sub.hbLast = now
+}
+
+func (s *subscriptions) heartbeatIntervals() []time.Duration {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return append([]time.Duration(nil), s.hbIntervals...)
 }
 
 func (s *subscriptions) sweep(exp time.Time) {
@@ -216,8 +252,8 @@ func (s *subscriptions) control() {
 			return
 		default:
 		}
-		s.sweep(time.Now())
-		time.Sleep(s.hbSleep)
+		s.sweep(s.clock.Now())
+		s.clock.Sleep(s.hbSleep)
 	}
 }
 
@@ -279,7 +315,7 @@ func (s *subscriptions) removeByChannelID(chanID int64) error {
 	// remove from socketId map
 	sub, ok := s.subsByChanID[chanID]
 	if !ok {
-		return fmt.Errorf("could not find channel ID %d", chanID)
+		return fmt.Errorf("could not find channel ID %d: %w", chanID, common.ErrNotFound)
 	}
 	delete(s.subsByChanID, chanID)
 	delete(s.subsBySubID, sub.SubID())
@@ -296,7 +332,7 @@ func (s *subscriptions) removeBySubscriptionID(subID string) error {
 	defer s.lock.Unlock()
 	sub, ok := s.subsBySubID[subID]
 	if !ok {
-		return fmt.Errorf("could not find subscription ID %s to remove", subID)
+		return fmt.Errorf("could not find subscription ID %s to remove: %w", subID, common.ErrNotFound)
 	}
 	// exists, remove both indices
 	delete(s.subsBySubID, subID)
@@ -318,13 +354,13 @@ func (s *subscriptions) activate(subID string, chanID int64) error {
 		}
 		sub.pending = false
 		sub.ChanID = chanID
-		sub.hbDeadline = time.Now().Add(s.hbTimeout)
+		sub.hbDeadline = s.clock.Now().Add(s.hbTimeout)
 		s.subsByChanID[chanID] = sub
 		s.hbActive = true
 		return nil
 	}
 
-	return fmt.Errorf("could not find subscription ID %s to activate", subID)
+	return fmt.Errorf("could not find subscription ID %s to activate: %w", subID, common.ErrNotFound)
 }
 
 func (s *subscriptions) lookupBySocketChannelID(chanID int64, sId SocketId) (*subscription, error) {
@@ -337,7 +373,7 @@ func (s *subscriptions) lookupBySocketChannelID(chanID int64, sId SocketId) (*su
 			}
 		}
 	}
-	return nil, fmt.Errorf("could not find subscription for channel ID %d and socket sId %d", chanID, sId)
+	return nil, fmt.Errorf("could not find subscription for channel ID %d and socket sId %d: %w", chanID, sId, common.ErrNotFound)
 }
 
 func (s *subscriptions) lookupBySubscriptionID(subID string) (*subscription, error) {
@@ -346,7 +382,18 @@ func (s *subscriptions) lookupBySubscriptionID(subID string) (*subscription, err
 	if sub, ok := s.subsBySubID[subID]; ok {
 		return sub, nil
 	}
-	return nil, fmt.Errorf("could not find subscription ID %s", subID)
+	return nil, fmt.Errorf("could not find subscription ID %s: %w", subID, common.ErrNotFound)
+}
+
+func (s *subscriptions) lookupByRoute(r *route) (*subscription, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, sub := range s.subsBySubID {
+		if sub.Request.route == r {
+			return sub, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find subscription of route: %w", common.ErrNotFound)
 }
 
 func (s *subscriptions) lookupBySocketId(socketId SocketId) (*SubscriptionSet, error) {
@@ -355,5 +402,5 @@ func (s *subscriptions) lookupBySocketId(socketId SocketId) (*SubscriptionSet, e
 	if set, ok := s.subsBySocketId[socketId]; ok {
 		return &set, nil
 	}
-	return nil, fmt.Errorf("could not find subscription with socketId %d", socketId)
+	return nil, fmt.Errorf("could not find subscription with socketId %d: %w", socketId, common.ErrNotFound)
 }
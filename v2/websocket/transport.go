@@ -8,6 +8,7 @@ import (
 	"github.com/op/go-logging"
 	"net"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
@@ -34,6 +35,7 @@ func newWs(baseURL string, logTransport bool, log *logging.Logger) *ws {
 		log:          log,
 		lock:         &sync.RWMutex{},
 		createTime:   time.Now(),
+		proxy:        http.ProxyFromEnvironment,
 		writeChan:    make(chan []byte, WS_WRITE_CAPACITY),
 	}
 }
@@ -48,36 +50,59 @@ type ws struct {
 	log           *logging.Logger
 	createTime    time.Time
 	writeChan     chan []byte
+	endpoints     *endpointPool // optional, tries BaseURL only if nil
+	proxy         func(*http.Request) (*url.URL, error)
 
 	kill chan interface{} // signal to routines to kill
 	quit chan error    	  // signal to parent with error, if applicable
 }
 
 func (w *ws) Connect() error {
-	if w.ws != nil {
+	if w.conn() != nil {
 		return nil // no op
 	}
 	var d = websocket.Dialer{
 		Subprotocols:    []string{"p1", "p2"},
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
-		Proxy:           http.ProxyFromEnvironment,
+		Proxy:           w.proxy,
 		HandshakeTimeout: time.Second * 10,
 	}
 
 	d.TLSClientConfig = &tls.Config{InsecureSkipVerify: w.TLSSkipVerify}
 
-	w.log.Infof("connecting ws to %s", w.BaseURL)
-	ws, resp, err := d.Dial(w.BaseURL, nil)
-	if err != nil {
+	urls := []string{w.BaseURL}
+	if w.endpoints != nil {
+		urls = w.endpoints.ordered()
+	}
+
+	var (
+		ws  *websocket.Conn
+		err error
+	)
+	for _, url := range urls {
+		w.log.Infof("connecting ws to %s", url)
+		start := time.Now()
+		var resp *http.Response
+		ws, resp, err = d.Dial(url, nil)
+		if err == nil {
+			w.BaseURL = url
+			w.endpoints.success(url, time.Since(start))
+			break
+		}
 		if err == websocket.ErrBadHandshake {
 			w.log.Errorf("bad handshake: status code %d", resp.StatusCode)
 		}
+		w.endpoints.failure(url)
+	}
+	if err != nil {
 		return err
 	}
+	w.lock.Lock()
 	w.ws = ws
-	go w.listenWriteChannel()
-	go w.listenWs()
+	w.lock.Unlock()
+	go w.listenWriteChannel(ws)
+	go w.listenWs(ws)
 	// Gorilla/go dont natively support keep alive pinging
 	// so we need to keep sending a message down the channel to stop
 	// tcp killing the connection
@@ -123,14 +148,21 @@ func (w *ws) Done() <-chan error {
 	return w.quit
 }
 
-// listen for write requests and perform them
-func (w *ws) listenWriteChannel() {
+// conn returns the connection, nil once stopped
+func (w *ws) conn() *websocket.Conn {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.ws
+}
+
+// listen for write requests and perform them on conn
+func (w *ws) listenWriteChannel(conn *websocket.Conn) {
 	for {
 		select {
 		case <-w.kill: // ws closed
 			return
 		case message := <- w.writeChan:
-			wsWriter, err := w.ws.NextWriter(websocket.TextMessage)
+			wsWriter, err := conn.NextWriter(websocket.TextMessage)
 			if err != nil {
 				w.log.Error("Unable to provision ws connection writer: ", err)
 				w.stop(err)
@@ -151,17 +183,14 @@ func (w *ws) listenWriteChannel() {
 	}
 }
 
-// listen on ws & fwd to listen()
-func (w *ws) listenWs() {
+// listen on conn & fwd to listen()
+func (w *ws) listenWs(conn *websocket.Conn) {
 	for {
-		if w.ws == nil {
-			return
-		}
 		select {
 		case <-w.kill: // ws connection ended
 			return
 		default:
-			_, msg, err := w.ws.ReadMessage()
+			_, msg, err := conn.ReadMessage()
 			if err != nil {
 				if cl, ok := err.(*websocket.CloseError); ok {
 					w.log.Errorf("close error code: %d", cl.Code)
@@ -172,6 +201,7 @@ func (w *ws) listenWs() {
 					return
 				}
 
+				w.endpoints.failure(w.BaseURL)
 				w.stop(err)
 				return
 			}
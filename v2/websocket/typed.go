@@ -0,0 +1,149 @@
+//go:build go1.18
+
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/book"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/candle"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/common"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/derivatives"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/status"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/ticker"
+	"github.com/bitfinexcom/bitfinex-api-go/pkg/models/trade"
+)
+
+// typedBufferSize is the capacity of the channels returned by Subscribe
+const typedBufferSize = 64
+
+// channelTypes lists the update and snapshot types of the public channels,
+// funding symbols have their own ticker and book types and liquidations
+// their own status types
+var channelTypes = map[string][][2]reflect.Type{
+	ChanTicker: {
+		{reflect.TypeOf(&ticker.Ticker{}), reflect.TypeOf(&ticker.Snapshot{})},
+		{reflect.TypeOf(&ticker.FundingTicker{}), reflect.TypeOf(&ticker.FundingSnapshot{})},
+	},
+	ChanTrades: {{reflect.TypeOf(&trade.Trade{}), reflect.TypeOf(&trade.Snapshot{})}},
+	ChanBook: {
+		{reflect.TypeOf(&book.Book{}), reflect.TypeOf(&book.Snapshot{})},
+		{reflect.TypeOf(&book.FundingBookUpdate{}), reflect.TypeOf(&book.FundingSnapshot{})},
+	},
+	ChanCandles: {{reflect.TypeOf(&candle.Candle{}), reflect.TypeOf(&candle.Snapshot{})}},
+	ChanStatus: {
+		{reflect.TypeOf(&derivatives.DerivativeStatus{}), reflect.TypeOf(&derivatives.Snapshot{})},
+		{reflect.TypeOf(&status.Liquidation{}), reflect.TypeOf(&status.LiquidationsSnapshot{})},
+	},
+}
+
+// channelType returns the update and snapshot types of a subscription
+func channelType(req *SubscriptionRequest) ([2]reflect.Type, bool) {
+	types, ok := channelTypes[req.Channel]
+	if !ok {
+		return [2]reflect.Type{}, false
+	}
+	if len(types) > 1 && (isFunding(req.Symbol) || strings.HasPrefix(req.Key, string(common.LiquidationStatusType)+":")) {
+		return types[1], true
+	}
+	return types[0], true
+}
+
+// Subscribe subscribes to a public channel and returns a channel of its
+// messages of type T, e.g.:
+//
+//	trades, err := websocket.Subscribe[*trade.Trade](ctx, c, &websocket.SubscriptionRequest{
+//		Event:   websocket.EventSubscribe,
+//		Channel: websocket.ChanTrades,
+//		Symbol:  "tBTCUSD",
+//	})
+//
+// T is either the update type of the channel, e.g. *trade.Trade or
+// *ticker.FundingTicker for the ticker of a funding symbol, in which
+// case snapshots are split into their updates, or the snapshot type, in
+// which case only snapshots are delivered. Any other type is rejected. The
+// messages do not show up on Listen. The subscription is removed and the
+// channel is closed once ctx is done or the client is closed. A subscription
+// ID is generated if req has none.
+func Subscribe[T any](ctx context.Context, c *Client, req *SubscriptionRequest) (<-chan T, error) {
+	types, ok := channelType(req)
+	if !ok {
+		return nil, fmt.Errorf("%w: no typed messages for channel %q", common.ErrBadRequest, req.Channel)
+	}
+	want := reflect.TypeOf((*T)(nil)).Elem()
+	if want != types[0] && want != types[1] {
+		return nil, fmt.Errorf("%w: channel %q delivers %s or %s, not %s", common.ErrBadRequest, req.Channel, types[0], types[1], want)
+	}
+
+	ch := make(chan T, typedBufferSize)
+	r := newRoute(func(msg interface{}, done <-chan struct{}) {
+		for _, v := range typedMessages[T](msg) {
+			select {
+			case ch <- v:
+			case <-done:
+				return
+			}
+		}
+	}, func() {
+		close(ch)
+	})
+
+	if _, err := c.subscribeRoute(ctx, req, r); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// typedMessages returns msg as T, or the entries of the snapshot msg
+func typedMessages[T any](msg interface{}) []T {
+	if v, ok := msg.(T); ok {
+		return []T{v}
+	}
+
+	var entries []interface{}
+	switch m := msg.(type) {
+	case *ticker.Snapshot:
+		for _, e := range m.Snapshot {
+			entries = append(entries, e)
+		}
+	case *trade.Snapshot:
+		for _, e := range m.Snapshot {
+			entries = append(entries, e)
+		}
+	case *ticker.FundingSnapshot:
+		for _, e := range m.Snapshot {
+			entries = append(entries, e)
+		}
+	case *book.Snapshot:
+		for _, e := range m.Snapshot {
+			entries = append(entries, e)
+		}
+	case *book.FundingSnapshot:
+		for _, e := range m.Snapshot {
+			entries = append(entries, e)
+		}
+	case *candle.Snapshot:
+		for _, e := range m.Snapshot {
+			entries = append(entries, e)
+		}
+	case *derivatives.Snapshot:
+		for _, e := range m.Snapshot {
+			entries = append(entries, e)
+		}
+	case *status.LiquidationsSnapshot:
+		for _, e := range m.Snapshot {
+			entries = append(entries, e)
+		}
+	}
+
+	out := make([]T, 0, len(entries))
+	for _, e := range entries {
+		if v, ok := e.(T); ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}